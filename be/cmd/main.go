@@ -1,18 +1,25 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"hris-backend/config"
+	"hris-backend/internal/email"
 	"hris-backend/internal/handler"
 	"hris-backend/internal/middleware"
 	"hris-backend/internal/model"
+	"hris-backend/internal/permissions"
+	"hris-backend/internal/realtime"
 	"hris-backend/internal/repository"
 	"hris-backend/internal/service"
 	"hris-backend/pkg/hash"
+	jwtPkg "hris-backend/pkg/jwt"
 	"hris-backend/pkg/signoz"
 	"hris-backend/pkg/kafka"
+	"hris-backend/pkg/mailer"
+	"hris-backend/pkg/oidc"
 
 	_ "hris-backend/docs" // swagger docs
 
@@ -45,6 +52,10 @@ import (
 
 func main() {
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Refusing to start in %s mode: %v", cfg.AppEnv, err)
+	}
+	signingKeys := loadSigningKeys(cfg)
 	signoz.Init(cfg.SigNozEndpoint, cfg.SigNozAccessToken, "hris-backend")
 	db := config.ConnectDatabase(cfg)
 
@@ -52,8 +63,12 @@ func main() {
 	seedAdmin(db, cfg)
 	seedJobLevels(db)
 
-	// Kafka producer (fire-and-forget; logs errors if broker unavailable)
-	kafkaProducer := kafka.NewProducer(cfg.KafkaBrokers, kafka.TopicNotifications)
+	// Kafka producer, used by the outbox relay to publish domain events
+	kafkaCompression, err := kafka.ParseCompression(cfg.KafkaCompression)
+	if err != nil {
+		log.Fatalf("Invalid KAFKA_COMPRESSION: %v", err)
+	}
+	kafkaProducer := kafka.NewProducer(cfg.KafkaBrokers, kafka.TopicNotifications, kafkaCompression)
 
 	// Notification repository (needed by both the Kafka consumer and the HTTP handler)
 	notifRepo := repository.NewNotificationRepository(db)
@@ -69,45 +84,123 @@ func main() {
 	holidayRepo := repository.NewHolidayRepository(db)
 	attRepo := repository.NewAttendanceRepository(db)
 	leaveRepo := repository.NewLeaveRepository(db)
+	leavePolicyRepo := repository.NewLeavePolicyRepository(db)
+	leaveBalanceRepo := repository.NewLeaveBalanceRepository(db)
+	leaveWorkflowRepo := repository.NewLeaveWorkflowRepository(db)
+	leaveDelegationRepo := repository.NewLeaveDelegationRepository(db)
 	payrollRepo := repository.NewPayrollRepository(db)
+	payrollRunRepo := repository.NewPayrollRunRepository(db)
 	jobLevelRepo := repository.NewJobLevelRepository(db)
 	gradeRepo := repository.NewGradeRepository(db)
 	moduleRepo := repository.NewModuleRepository(db)
 	compModuleRepo := repository.NewCompanyModuleRepository(db)
 	visitRepo := repository.NewVisitRepository(db)
 	visitPlanRepo := repository.NewVisitPlanRepository(db)
+	announcementRepo := repository.NewAnnouncementRepository(db)
+	userCompanyRepo := repository.NewUserCompanyRepository(db)
+	permRepo := repository.NewPermissionRepository(db)
+	customRoleRepo := repository.NewCustomRoleRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	resetRepo := repository.NewPasswordResetRepository(db)
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	oidcProviderRepo := repository.NewOIDCProviderRepository(db)
+	apiTokenRepo := repository.NewAPITokenRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	deadLetterRepo := repository.NewDeadLetterRepository(db)
+	emailDeliveryRepo := repository.NewEmailDeliveryRepository(db)
+	notifPrefRepo := repository.NewNotificationPreferenceRepository(db)
+	notifDigestRepo := repository.NewNotificationDigestRepository(db)
+
+	// Email, when SMTP is configured — password reset tokens are mailed
+	// directly, notifications through the delivery log further below
+	var emailRenderer *email.Renderer
+	var smtpMailer *mailer.Mailer
+	var resetMailer service.PasswordResetMailer
+	if cfg.SMTPHost != "" {
+		emailRenderer, err = email.NewRenderer()
+		if err != nil {
+			log.Fatalf("Failed to load email templates: %v", err)
+		}
+		smtpMailer, err = mailer.New(mailer.Config{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+			TLS:      cfg.SMTPTLS,
+		})
+		if err != nil {
+			log.Fatalf("Invalid SMTP configuration: %v", err)
+		}
+		resetMailer = email.NewPasswordResetMailer(emailRenderer, smtpMailer, cfg.FrontendURL)
+	}
 
 	// Services
-	authService := service.NewAuthService(userRepo, cfg)
-	userService := service.NewUserService(userRepo)
+	authService := service.NewAuthService(userRepo, sessionRepo, resetRepo, twoFactorRepo, loginAttemptRepo, empRepo, userCompanyRepo, resetMailer, signingKeys, cfg)
+	sessionService := service.NewSessionService(sessionRepo, userRepo)
+	twoFactorService := service.NewTwoFactorService(twoFactorRepo, userRepo, cfg)
+	loginAuditService := service.NewLoginAuditService(loginAttemptRepo, userRepo)
+	apiTokenService := service.NewAPITokenService(apiTokenRepo, userRepo, companyRepo)
+	serviceAccountService := service.NewServiceAccountService(userRepo, userCompanyRepo, companyRepo, customRoleRepo, apiTokenRepo)
+	oidcService := service.NewOIDCService(oidcProviderRepo, userRepo, empRepo, userCompanyRepo, companyRepo, oidc.NewClient(nil), cfg)
+	scopeService := service.NewCompanyScopeService(userCompanyRepo, userRepo, empRepo, companyRepo)
+	permService := service.NewPermissionService(permRepo, customRoleRepo, userRepo)
+	roleService := service.NewCustomRoleService(customRoleRepo, permRepo, companyRepo)
+	userService := service.NewUserService(userRepo, customRoleRepo)
 	companyService := service.NewCompanyService(companyRepo)
-	deptService := service.NewDepartmentService(deptRepo, companyRepo)
+	deptService := service.NewDepartmentService(deptRepo, companyRepo, empRepo)
 	posService := service.NewPositionService(posRepo, companyRepo)
 	shiftService := service.NewShiftService(shiftRepo, companyRepo)
 	empService := service.NewEmployeeService(empRepo, userRepo, companyRepo, deptRepo, posRepo, shiftRepo, jobLevelRepo, gradeRepo)
 	empSalaryService := service.NewEmployeeSalaryService(empSalaryRepo, empRepo)
 	holidayService := service.NewHolidayService(holidayRepo, companyRepo)
 	attService := service.NewAttendanceService(attRepo, empRepo, shiftRepo)
-	leaveService := service.NewLeaveService(leaveRepo, empRepo)
+	leaveService := service.NewLeaveService(leaveRepo, empRepo, deptRepo, holidayRepo, attRepo, leavePolicyRepo, leaveBalanceRepo, leaveWorkflowRepo, leaveDelegationRepo)
+	leavePolicyService := service.NewLeavePolicyService(leavePolicyRepo, companyRepo)
+	leaveBalanceService := service.NewLeaveBalanceService(leaveBalanceRepo, leavePolicyRepo, leaveRepo, empRepo, companyRepo)
+	leaveWorkflowService := service.NewLeaveWorkflowService(leaveWorkflowRepo, companyRepo, deptRepo, userRepo)
+	leaveDelegationService := service.NewLeaveDelegationService(leaveDelegationRepo, userRepo)
 	payrollService := service.NewPayrollService(payrollRepo, empRepo, empSalaryRepo, attRepo)
+	payrollRunService := service.NewPayrollRunService(payrollRunRepo, payrollRepo, companyRepo, empRepo, empSalaryRepo, attRepo)
 	orgService := service.NewOrganizationService(companyRepo)
+	deadLetterService := service.NewDeadLetterService(deadLetterRepo)
+	emailDeliveryService := service.NewEmailDeliveryService(emailDeliveryRepo)
 	menuAccessRepo := repository.NewMenuAccessRepository(db)
 	menuAccessService := service.NewMenuAccessService(menuAccessRepo, userRepo)
-	notifService := service.NewNotificationService(notifRepo)
+	notifService := service.NewNotificationService(notifRepo, notifPrefRepo)
 	jobLevelService := service.NewJobLevelService(jobLevelRepo, companyRepo)
 	gradeService := service.NewGradeService(gradeRepo, jobLevelRepo, companyRepo)
 	moduleService := service.NewModuleService(moduleRepo, compModuleRepo, companyRepo)
 	visitService := service.NewVisitService(visitRepo, attRepo, empRepo)
 	visitPlanService := service.NewVisitPlanService(visitPlanRepo, empRepo, visitRepo)
+	announcementService := service.NewAnnouncementService(announcementRepo, companyRepo, deptRepo, jobLevelRepo, empRepo)
 
 	// Sync the code-defined module registry into the DB on every startup.
-	if err := moduleService.SyncRegistry(); err != nil {
+	if err := moduleService.SyncRegistry(context.Background()); err != nil {
 		log.Printf("Failed to sync module registry: %v", err)
 	}
 
+	// Sync the code-defined permission catalog and built-in role defaults.
+	if err := permService.SyncCatalog(context.Background()); err != nil {
+		log.Printf("Failed to sync permission catalog: %v", err)
+	}
+
+	// Start the notification hub — pushes notifications created on any instance to streams open on this one
+	notifHub := realtime.NewHub(db, notifRepo)
+	notifHub.Start()
+
 	// Handlers
 	authHandler := handler.NewAuthHandler(authService)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+	twoFactorHandler := handler.NewTwoFactorHandler(twoFactorService)
+	loginAuditHandler := handler.NewLoginAuditHandler(loginAuditService)
+	oidcHandler := handler.NewOIDCHandler(oidcService, authService, cfg)
+	apiTokenHandler := handler.NewAPITokenHandler(apiTokenService)
+	serviceAccountHandler := handler.NewServiceAccountHandler(serviceAccountService, apiTokenService, permService)
 	userHandler := handler.NewUserHandler(userService)
+	userCompanyHandler := handler.NewUserCompanyHandler(scopeService)
+	roleHandler := handler.NewRoleHandler(roleService, permService)
 	companyHandler := handler.NewCompanyHandler(companyService)
 	deptHandler := handler.NewDepartmentHandler(deptService)
 	posHandler := handler.NewPositionHandler(posService)
@@ -116,23 +209,73 @@ func main() {
 	empSalaryHandler := handler.NewEmployeeSalaryHandler(empSalaryService)
 	holidayHandler := handler.NewHolidayHandler(holidayService)
 	attHandler := handler.NewAttendanceHandler(attService, empService)
-	leaveHandler := handler.NewLeaveHandler(leaveService, kafkaProducer)
+	leaveHandler := handler.NewLeaveHandler(leaveService)
+	leavePolicyHandler := handler.NewLeavePolicyHandler(leavePolicyService)
+	leaveBalanceHandler := handler.NewLeaveBalanceHandler(leaveBalanceService, empService)
+	leaveWorkflowHandler := handler.NewLeaveWorkflowHandler(leaveWorkflowService)
+	leaveDelegationHandler := handler.NewLeaveDelegationHandler(leaveDelegationService)
 	payrollHandler := handler.NewPayrollHandler(payrollService, empService)
+	payrollRunHandler := handler.NewPayrollRunHandler(payrollRunService)
 	orgHandler := handler.NewOrganizationHandler(orgService)
+	deadLetterHandler := handler.NewDeadLetterHandler(deadLetterService)
+	emailDeliveryHandler := handler.NewEmailDeliveryHandler(emailDeliveryService)
 	menuAccessHandler := handler.NewMenuAccessHandler(menuAccessService)
-	notifHandler := handler.NewNotificationHandler(notifService)
+	notifHandler := handler.NewNotificationHandler(notifService, notifHub)
 	jobLevelHandler := handler.NewJobLevelHandler(jobLevelService)
 	gradeHandler := handler.NewGradeHandler(gradeService)
+	jwksHandler := handler.NewJWKSHandler(signingKeys)
 	moduleHandler := handler.NewModuleHandler(moduleService, empService)
 	visitHandler := handler.NewVisitHandler(visitService, empService)
 	visitPlanHandler := handler.NewVisitPlanHandler(visitPlanService, empService)
+	announcementHandler := handler.NewAnnouncementHandler(announcementService)
+
+	// Start the outbox relay — publishes the events services wrote with their changes
+	outboxRelay := kafka.NewOutboxRelay(outboxRepo, kafkaProducer)
+	outboxRelay.Start()
+
+	// Start the lifecycle scheduler — publishes contract ending and missing clock-in events
+	lifecycleScheduler := service.NewLifecycleScheduler(companyRepo, empRepo, holidayRepo, attRepo, outboxRepo)
+	lifecycleScheduler.Start()
+
+	// Start the digest scheduler — sends the daily summaries of the notifications users chose to get as a digest
+	if cfg.NotificationDigestHour < 0 || cfg.NotificationDigestHour > 23 {
+		log.Fatalf("Invalid NOTIFICATION_DIGEST_HOUR: %d is not an hour of the day", cfg.NotificationDigestHour)
+	}
+	digestScheduler := service.NewDigestScheduler(notifDigestRepo, cfg.NotificationDigestHour)
+	digestScheduler.Start()
+
+	// Start the notification retention job — purges read and deleted notifications past the retention period
+	notifRetention := service.NewNotificationRetention(notifRepo, cfg.NotificationRetentionDays)
+	notifRetention.Start()
+
+	// Start the announcement scheduler — notifies the audience of scheduled announcements when they are shown
+	announcementScheduler := service.NewAnnouncementScheduler(announcementService)
+	announcementScheduler.Start()
+
+	// Start Kafka consumer — processes events and writes notifications to DB,
+	// dead-lettering the events it keeps failing on
+	processor := kafka.NewEventProcessor(notifRepo, userRepo, notifPrefRepo, notifDigestRepo, leaveDelegationRepo)
+
+	// Email notifications, when SMTP is configured — the channel queues the
+	// email of each notification and the dispatcher sends it with retries
+	if smtpMailer != nil {
+		processor.AddChannel(email.NewChannel(userRepo, emailDeliveryRepo, emailRenderer, cfg.FrontendURL))
+		email.NewDispatcher(emailDeliveryRepo, smtpMailer).Start()
+	}
 
-	// Start Kafka consumer — processes events and writes notifications to DB
-	processor := kafka.NewEventProcessor(notifRepo, userRepo)
-	consumer := kafka.NewConsumer(cfg.KafkaBrokers, kafka.TopicNotifications, "hris-notification-group", processor.Handle)
+	consumer := kafka.NewConsumer(cfg.KafkaBrokers, kafka.TopicNotifications, "hris-notification-group", processor.Handle, kafkaProducer)
 	consumer.Start()
 
+	// Record dead letters in the DB so admins can inspect and replay them
+	deadLetterConsumer := kafka.NewConsumer(cfg.KafkaBrokers, kafka.DeadLetterTopic(kafka.TopicNotifications), "hris-dead-letter-group", kafka.NewDeadLetterRecorder(deadLetterRepo).Handle, nil)
+	deadLetterConsumer.Start()
+
 	app := fiber.New(fiber.Config{
+		// c.IP() feeds the login throttle, so only trusted proxies may set it
+		ProxyHeader:             cfg.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          cfg.TrustedProxies,
+		EnableIPValidation:      true,
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
 			if e, ok := err.(*fiber.Error); ok {
@@ -156,11 +299,12 @@ func main() {
 		},
 	})
 
+	app.Use(middleware.Trace())
 	app.Use(logger.New())
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     cfg.CORSOrigins,
 		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders:     "Origin,Content-Type,Accept,Authorization",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,traceparent,Last-Event-ID",
 		AllowCredentials: true,
 	}))
 
@@ -168,177 +312,317 @@ func main() {
 
 	auth := api.Group("/auth")
 	auth.Post("/login", authHandler.Login)
+	auth.Post("/2fa/verify", authHandler.VerifyTwoFactor)
 	auth.Post("/refresh", authHandler.Refresh)
-	auth.Post("/logout", middleware.AuthMiddleware(cfg), authHandler.Logout)
-
-	users := api.Group("/users", middleware.AuthMiddleware(cfg))
+	auth.Post("/forgot-password", authHandler.ForgotPassword)
+	auth.Post("/reset-password", authHandler.ResetPassword)
+	auth.Post("/logout", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), authHandler.Logout)
+	auth.Post("/change-password", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), authHandler.ChangePassword)
+	auth.Get("/sessions", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), sessionHandler.GetMine)
+	auth.Delete("/sessions", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), sessionHandler.RevokeMyOthers)
+	auth.Delete("/sessions/:id", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), sessionHandler.RevokeMine)
+	auth.Get("/tokens", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), apiTokenHandler.GetMine)
+	auth.Post("/tokens", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), apiTokenHandler.CreateMine)
+	auth.Delete("/tokens/:id", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), apiTokenHandler.RevokeMine)
+	auth.Get("/oidc/providers", oidcHandler.GetLoginOptions)
+	auth.Get("/oidc/:provider/login", oidcHandler.Login)
+	auth.Get("/oidc/:provider/callback", oidcHandler.Callback)
+	auth.Post("/oidc/:provider/link", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), oidcHandler.Link)
+
+	twoFactor := auth.Group("/2fa", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService))
+	twoFactor.Get("/", twoFactorHandler.GetStatus)
+	twoFactor.Post("/setup", twoFactorHandler.Setup)
+	twoFactor.Post("/enable", twoFactorHandler.Enable)
+	twoFactor.Post("/disable", twoFactorHandler.Disable)
+	twoFactor.Post("/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+
+	users := api.Group("/users", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService))
 	users.Get("/me", userHandler.GetMe)
-	users.Get("/", middleware.RoleMiddleware("admin", "hr"), userHandler.GetAll)
+	users.Get("/", middleware.RequirePermission(permissions.UsersRead), userHandler.GetAll)
 	users.Get("/:id", userHandler.GetByID)
-	users.Post("/", middleware.RoleMiddleware("admin"), userHandler.Create)
-	users.Put("/:id", middleware.RoleMiddleware("admin"), userHandler.Update)
-	users.Delete("/:id", middleware.RoleMiddleware("admin"), userHandler.Delete)
-
-	// Company routes (admin only)
-	companies := api.Group("/companies", middleware.AuthMiddleware(cfg), middleware.RoleMiddleware("admin", "hr"))
-	companies.Get("/", companyHandler.GetAll)
-	companies.Get("/:id", companyHandler.GetByID)
-	companies.Post("/", middleware.RoleMiddleware("admin"), companyHandler.Create)
-	companies.Put("/:id", middleware.RoleMiddleware("admin"), companyHandler.Update)
-	companies.Delete("/", middleware.RoleMiddleware("admin"), companyHandler.DeleteMultiple)
-	companies.Delete("/:id", middleware.RoleMiddleware("admin"), companyHandler.Delete)
-
-	// Department routes (admin, hr can view; admin can manage)
-	departments := api.Group("/departments", middleware.AuthMiddleware(cfg), middleware.RoleMiddleware("admin", "hr"))
+	users.Post("/", middleware.RequirePermission(permissions.UsersManage), userHandler.Create)
+	manageableUser := middleware.RequireManageableUser(userService)
+	users.Put("/:id", middleware.RequirePermission(permissions.UsersManage), manageableUser, userHandler.Update)
+	users.Delete("/:id", middleware.RequirePermission(permissions.UsersManage), manageableUser, userHandler.Delete)
+	users.Get("/:id/companies", middleware.RoleMiddleware("superadmin"), userCompanyHandler.GetCompanies)
+	users.Put("/:id/companies", middleware.RoleMiddleware("superadmin"), userCompanyHandler.SetCompanies)
+	users.Get("/:id/sessions", middleware.RequirePermission(permissions.UsersManage), manageableUser, sessionHandler.GetByUser)
+	users.Delete("/:id/sessions", middleware.RequirePermission(permissions.UsersManage), manageableUser, sessionHandler.RevokeAllForUser)
+	users.Delete("/:id/sessions/:sessionId", middleware.RequirePermission(permissions.UsersManage), manageableUser, sessionHandler.RevokeForUser)
+	users.Delete("/:id/2fa", middleware.RequirePermission(permissions.UsersManage), manageableUser, twoFactorHandler.Reset)
+	users.Post("/:id/unlock", middleware.RequirePermission(permissions.UsersManage), manageableUser, loginAuditHandler.Unlock)
+	users.Get("/:id/tokens", middleware.RequirePermission(permissions.UsersManage), manageableUser, apiTokenHandler.GetByUser)
+	users.Delete("/:id/tokens/:tokenId", middleware.RequirePermission(permissions.UsersManage), manageableUser, apiTokenHandler.RevokeForUser)
+
+	// Login audit routes
+	loginAttempts := api.Group("/login-attempts", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequirePermission(permissions.LoginAuditRead))
+	loginAttempts.Get("/", loginAuditHandler.GetAll)
+
+	// Dead-letter routes
+	deadLetters := api.Group("/dead-letters", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequirePermission(permissions.EventsManage))
+	deadLetters.Get("/", deadLetterHandler.GetAll)
+	deadLetters.Post("/replay", deadLetterHandler.ReplayMany)
+	deadLetters.Get("/:id", deadLetterHandler.GetByID)
+	deadLetters.Post("/:id/replay", deadLetterHandler.Replay)
+
+	// Email delivery log routes
+	emailDeliveries := api.Group("/email-deliveries", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequirePermission(permissions.EventsManage))
+	emailDeliveries.Get("/", emailDeliveryHandler.GetAll)
+	emailDeliveries.Get("/:id", emailDeliveryHandler.GetByID)
+
+	// Single sign-on provider routes
+	ssoProviders := api.Group("/sso-providers", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequirePermission(permissions.SSOManage))
+	ssoProviders.Get("/", oidcHandler.GetAll)
+	ssoProviders.Get("/:id", oidcHandler.GetByID)
+	ssoProviders.Post("/", oidcHandler.Create)
+	ssoProviders.Put("/:id", oidcHandler.Update)
+	ssoProviders.Delete("/:id", oidcHandler.Delete)
+
+	// Service account routes
+	serviceAccounts := api.Group("/service-accounts", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequirePermission(permissions.ServiceAccountsManage))
+	serviceAccounts.Get("/", serviceAccountHandler.GetAll)
+	serviceAccounts.Get("/:id", serviceAccountHandler.GetByID)
+	serviceAccounts.Post("/", serviceAccountHandler.Create)
+	serviceAccounts.Put("/:id", serviceAccountHandler.Update)
+	serviceAccounts.Delete("/:id", serviceAccountHandler.Delete)
+	serviceAccounts.Get("/:id/tokens", serviceAccountHandler.GetTokens)
+	serviceAccounts.Post("/:id/tokens", serviceAccountHandler.CreateToken)
+	serviceAccounts.Delete("/:id/tokens/:tokenId", serviceAccountHandler.RevokeToken)
+
+	// Custom role routes
+	roles := api.Group("/roles", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequirePermission(permissions.RolesManage))
+	roles.Get("/permissions", roleHandler.GetPermissions)
+	roles.Get("/", roleHandler.GetAll)
+	roles.Get("/:id", roleHandler.GetByID)
+	roles.Post("/", roleHandler.Create)
+	roles.Put("/:id", roleHandler.Update)
+	roles.Delete("/:id", roleHandler.Delete)
+
+	// Company routes (only superadmin creates and deletes companies)
+	companies := api.Group("/companies", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService))
+	companies.Get("/", middleware.RequirePermission(permissions.CompaniesRead), companyHandler.GetAll)
+	companies.Get("/:id", middleware.RequirePermission(permissions.CompaniesRead), companyHandler.GetByID)
+	companies.Post("/", middleware.RoleMiddleware("superadmin"), companyHandler.Create)
+	companies.Put("/:id", middleware.RequirePermission(permissions.CompaniesManage), companyHandler.Update)
+	companies.Delete("/", middleware.RoleMiddleware("superadmin"), companyHandler.DeleteMultiple)
+	companies.Delete("/:id", middleware.RoleMiddleware("superadmin"), companyHandler.Delete)
+
+	// Department routes
+	departments := api.Group("/departments", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequirePermission(permissions.OrganizationRead))
 	departments.Get("/", deptHandler.GetAll)
 	departments.Get("/:id", deptHandler.GetByID)
-	departments.Post("/", middleware.RoleMiddleware("admin"), deptHandler.Create)
-	departments.Put("/:id", middleware.RoleMiddleware("admin"), deptHandler.Update)
-	departments.Delete("/:id", middleware.RoleMiddleware("admin"), deptHandler.Delete)
+	departments.Post("/", middleware.RequirePermission(permissions.OrganizationManage), deptHandler.Create)
+	departments.Put("/:id", middleware.RequirePermission(permissions.OrganizationManage), deptHandler.Update)
+	departments.Delete("/:id", middleware.RequirePermission(permissions.OrganizationManage), deptHandler.Delete)
 
-	// Position routes (admin, hr can view; admin can manage)
-	positions := api.Group("/positions", middleware.AuthMiddleware(cfg), middleware.RoleMiddleware("admin", "hr"))
+	// Position routes
+	positions := api.Group("/positions", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequirePermission(permissions.OrganizationRead))
 	positions.Get("/", posHandler.GetAll)
 	positions.Get("/:id", posHandler.GetByID)
-	positions.Post("/", middleware.RoleMiddleware("admin"), posHandler.Create)
-	positions.Put("/:id", middleware.RoleMiddleware("admin"), posHandler.Update)
-	positions.Delete("/:id", middleware.RoleMiddleware("admin"), posHandler.Delete)
+	positions.Post("/", middleware.RequirePermission(permissions.OrganizationManage), posHandler.Create)
+	positions.Put("/:id", middleware.RequirePermission(permissions.OrganizationManage), posHandler.Update)
+	positions.Delete("/:id", middleware.RequirePermission(permissions.OrganizationManage), posHandler.Delete)
 
-	// Shift routes (admin, hr can view; admin can manage)
-	shifts := api.Group("/shifts", middleware.AuthMiddleware(cfg), middleware.RoleMiddleware("admin", "hr"))
+	// Shift routes
+	shifts := api.Group("/shifts", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequirePermission(permissions.OrganizationRead))
 	shifts.Get("/", shiftHandler.GetAll)
 	shifts.Get("/:id", shiftHandler.GetByID)
-	shifts.Post("/", middleware.RoleMiddleware("admin"), shiftHandler.Create)
-	shifts.Put("/:id", middleware.RoleMiddleware("admin"), shiftHandler.Update)
-	shifts.Delete("/:id", middleware.RoleMiddleware("admin"), shiftHandler.Delete)
+	shifts.Post("/", middleware.RequirePermission(permissions.OrganizationManage), shiftHandler.Create)
+	shifts.Put("/:id", middleware.RequirePermission(permissions.OrganizationManage), shiftHandler.Update)
+	shifts.Delete("/:id", middleware.RequirePermission(permissions.OrganizationManage), shiftHandler.Delete)
 
-	// Employee routes (admin, hr can view all; admin can manage)
-	employees := api.Group("/employees", middleware.AuthMiddleware(cfg))
+	// Employee routes
+	employees := api.Group("/employees", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService))
 	employees.Get("/me", empHandler.GetMe)
-	employees.Get("/", middleware.RoleMiddleware("admin", "hr"), empHandler.GetAll)
-	employees.Get("/:id", middleware.RoleMiddleware("admin", "hr"), empHandler.GetByID)
-	employees.Post("/", middleware.RoleMiddleware("admin"), empHandler.Create)
-	employees.Put("/:id", middleware.RoleMiddleware("admin"), empHandler.Update)
-	employees.Delete("/:id", middleware.RoleMiddleware("admin"), empHandler.Delete)
-
-	// Employee salary routes (admin, hr only)
-	empSalaries := api.Group("/employee-salaries", middleware.AuthMiddleware(cfg), middleware.RoleMiddleware("admin", "hr"))
+	employees.Get("/", middleware.RequirePermission(permissions.EmployeesRead), empHandler.GetAll)
+	employees.Get("/:id", middleware.RequirePermission(permissions.EmployeesRead), empHandler.GetByID)
+	employees.Post("/", middleware.RequirePermission(permissions.EmployeesManage), empHandler.Create)
+	employees.Put("/:id", middleware.RequirePermission(permissions.EmployeesManage), empHandler.Update)
+	employees.Delete("/:id", middleware.RequirePermission(permissions.EmployeesManage), empHandler.Delete)
+
+	// Employee salary routes
+	empSalaries := api.Group("/employee-salaries", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequirePermission(permissions.SalariesRead))
 	empSalaries.Get("/", empSalaryHandler.GetAll)
 	empSalaries.Get("/:id", empSalaryHandler.GetByID)
 	empSalaries.Get("/employee/:employeeId/latest", empSalaryHandler.GetLatest)
-	empSalaries.Post("/", middleware.RoleMiddleware("admin"), empSalaryHandler.Create)
-	empSalaries.Post("/seed-from-position/:employeeId", middleware.RoleMiddleware("admin"), empSalaryHandler.SeedFromPosition)
-	empSalaries.Put("/:id", middleware.RoleMiddleware("admin"), empSalaryHandler.Update)
-	empSalaries.Delete("/:id", middleware.RoleMiddleware("admin"), empSalaryHandler.Delete)
+	empSalaries.Post("/", middleware.RequirePermission(permissions.SalariesManage), empSalaryHandler.Create)
+	empSalaries.Post("/seed-from-position/:employeeId", middleware.RequirePermission(permissions.SalariesManage), empSalaryHandler.SeedFromPosition)
+	empSalaries.Put("/:id", middleware.RequirePermission(permissions.SalariesManage), empSalaryHandler.Update)
+	empSalaries.Delete("/:id", middleware.RequirePermission(permissions.SalariesManage), empSalaryHandler.Delete)
 
-	// Holiday routes (admin, hr can view; admin can manage)
-	holidays := api.Group("/holidays", middleware.AuthMiddleware(cfg), middleware.RoleMiddleware("admin", "hr"))
+	// Holiday routes
+	holidays := api.Group("/holidays", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequirePermission(permissions.OrganizationRead))
 	holidays.Get("/", holidayHandler.GetAll)
 	holidays.Get("/:id", holidayHandler.GetByID)
-	holidays.Post("/", middleware.RoleMiddleware("admin"), holidayHandler.Create)
-	holidays.Put("/:id", middleware.RoleMiddleware("admin"), holidayHandler.Update)
-	holidays.Delete("/:id", middleware.RoleMiddleware("admin"), holidayHandler.Delete)
+	holidays.Post("/", middleware.RequirePermission(permissions.OrganizationManage), holidayHandler.Create)
+	holidays.Put("/:id", middleware.RequirePermission(permissions.OrganizationManage), holidayHandler.Update)
+	holidays.Delete("/:id", middleware.RequirePermission(permissions.OrganizationManage), holidayHandler.Delete)
 
 	// Attendance routes
-	attendances := api.Group("/attendances", middleware.AuthMiddleware(cfg))
+	attendances := api.Group("/attendances", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService))
 	attendances.Get("/", attHandler.GetAll)
 	attendances.Get("/:id", attHandler.GetByID)
 	attendances.Post("/clock-in", attHandler.ClockIn)
 	attendances.Put("/:id/clock-out", attHandler.ClockOut)
-	attendances.Post("/", middleware.RoleMiddleware("admin", "hr"), attHandler.Create)
-	attendances.Post("/import", middleware.RoleMiddleware("admin", "hr"), attHandler.Import)
-	attendances.Put("/:id", middleware.RoleMiddleware("admin", "hr"), attHandler.Update)
-	attendances.Delete("/:id", middleware.RoleMiddleware("admin"), attHandler.Delete)
+	attendances.Post("/", middleware.RequirePermission(permissions.AttendanceManage), attHandler.Create)
+	attendances.Post("/import", middleware.RequirePermission(permissions.AttendanceManage), attHandler.Import)
+	attendances.Put("/:id", middleware.RequirePermission(permissions.AttendanceManage), attHandler.Update)
+	attendances.Delete("/:id", middleware.RequirePermission(permissions.AttendanceDelete), attHandler.Delete)
 
 	// Leave routes
-	leaves := api.Group("/leaves", middleware.AuthMiddleware(cfg))
+	leaves := api.Group("/leaves", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService))
 	leaves.Get("/", leaveHandler.GetAll)
+	leaves.Get("/balance", leaveBalanceHandler.GetBalance)
+	leaves.Get("/balance/ledger", leaveBalanceHandler.GetLedger)
+	leaves.Get("/approvals", leaveHandler.GetAwaitingApproval)
 	leaves.Get("/:id", leaveHandler.GetByID)
 	leaves.Post("/", leaveHandler.Create)
 	leaves.Put("/:id", leaveHandler.Update)
-	leaves.Put("/:id/approve", middleware.RoleMiddleware("admin", "hr"), leaveHandler.Approve)
+	leaves.Put("/:id/approve", leaveHandler.Approve)
+	leaves.Put("/:id/cancel", leaveHandler.Cancel)
 	leaves.Delete("/:id", leaveHandler.Delete)
 
+	// Leave policy routes
+	leavePolicies := api.Group("/leave-policies", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequirePermission(permissions.LeaveConfigure))
+	leavePolicies.Get("/", leavePolicyHandler.GetAll)
+	leavePolicies.Get("/:id", leavePolicyHandler.GetByID)
+	leavePolicies.Post("/", leavePolicyHandler.Create)
+	leavePolicies.Put("/:id", leavePolicyHandler.Update)
+	leavePolicies.Delete("/:id", leavePolicyHandler.Delete)
+
+	// Leave workflow routes
+	leaveWorkflows := api.Group("/leave-workflows", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequirePermission(permissions.LeaveConfigure))
+	leaveWorkflows.Get("/", leaveWorkflowHandler.GetAll)
+	leaveWorkflows.Get("/:id", leaveWorkflowHandler.GetByID)
+	leaveWorkflows.Post("/", leaveWorkflowHandler.Create)
+	leaveWorkflows.Put("/:id", leaveWorkflowHandler.Update)
+	leaveWorkflows.Delete("/:id", leaveWorkflowHandler.Delete)
+
+	// Leave delegation routes
+	leaveDelegations := api.Group("/leave-delegations", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService))
+	leaveDelegations.Get("/", leaveDelegationHandler.GetAll)
+	leaveDelegations.Post("/", leaveDelegationHandler.Create)
+	leaveDelegations.Delete("/:id", leaveDelegationHandler.Delete)
+
+	// Leave balance management routes
+	leaveBalances := api.Group("/leave-balances", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequirePermission(permissions.LeaveBalances))
+	leaveBalances.Get("/entitlements", leaveBalanceHandler.GetEntitlements)
+	leaveBalances.Put("/entitlements", leaveBalanceHandler.SetEntitlement)
+	leaveBalances.Post("/adjustments", leaveBalanceHandler.Adjust)
+	leaveBalances.Post("/accrue", leaveBalanceHandler.Accrue)
+	leaveBalances.Post("/close-year", leaveBalanceHandler.CloseYear)
+
 	// Payslips self-service route (all authenticated users)
-	payrollsSelf := api.Group("/payrolls", middleware.AuthMiddleware(cfg))
+	payrollsSelf := api.Group("/payrolls", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService))
 	payrollsSelf.Get("/me", payrollHandler.GetMyPayslips)
 
-	// Payroll routes (admin, hr only)
-	payrolls := api.Group("/payrolls", middleware.AuthMiddleware(cfg), middleware.RoleMiddleware("admin", "hr"))
-	payrolls.Get("/", payrollHandler.GetAll)
-	payrolls.Get("/:id", payrollHandler.GetByID)
-	payrolls.Post("/generate", payrollHandler.Generate)
-	payrolls.Put("/:id", middleware.RoleMiddleware("admin"), payrollHandler.Update)
-	payrolls.Put("/:id/status", middleware.RoleMiddleware("admin"), payrollHandler.UpdateStatus)
-	payrolls.Delete("/:id", middleware.RoleMiddleware("admin"), payrollHandler.Delete)
-
-	// Organization structure routes (admin, hr only)
-	organization := api.Group("/organization", middleware.AuthMiddleware(cfg), middleware.RoleMiddleware("admin", "hr"))
+	// Payroll routes (status changes check payroll:approve or payroll:pay by target status)
+	payrolls := api.Group("/payrolls", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService))
+	payrolls.Get("/", middleware.RequirePermission(permissions.PayrollRead), payrollHandler.GetAll)
+	payrolls.Get("/:id", middleware.RequirePermission(permissions.PayrollRead), payrollHandler.GetByID)
+	payrolls.Post("/generate", middleware.RequirePermission(permissions.PayrollGenerate), payrollHandler.Generate)
+	payrolls.Put("/:id", middleware.RequirePermission(permissions.PayrollUpdate), payrollHandler.Update)
+	payrolls.Put("/:id/status", middleware.RequirePermission(permissions.PayrollApprove, permissions.PayrollPay), payrollHandler.UpdateStatus)
+	payrolls.Delete("/:id", middleware.RequirePermission(permissions.PayrollDelete), payrollHandler.Delete)
+
+	// Payroll run routes
+	payrollRuns := api.Group("/payroll-runs", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService))
+	payrollRuns.Get("/", middleware.RequirePermission(permissions.PayrollRead), payrollRunHandler.GetAll)
+	payrollRuns.Get("/:id", middleware.RequirePermission(permissions.PayrollRead), payrollRunHandler.GetByID)
+	payrollRuns.Post("/", middleware.RequirePermission(permissions.PayrollGenerate), payrollRunHandler.Generate)
+	payrollRuns.Put("/:id/status", middleware.RequirePermission(permissions.PayrollApprove, permissions.PayrollPay), payrollRunHandler.UpdateStatus)
+	payrollRuns.Delete("/:id", middleware.RequirePermission(permissions.PayrollDelete), payrollRunHandler.Delete)
+
+	// Organization structure routes
+	organization := api.Group("/organization", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequirePermission(permissions.OrganizationRead))
 	organization.Get("/structure", orgHandler.GetStructure)
 
 	// Menu access routes
-	menuAccess := api.Group("/menu-access", middleware.AuthMiddleware(cfg))
+	menuAccess := api.Group("/menu-access", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService))
 	menuAccess.Get("/me", menuAccessHandler.GetMyMenus)
-	menuAccess.Get("/", middleware.RoleMiddleware("admin"), menuAccessHandler.GetAll)
-	menuAccess.Post("/", middleware.RoleMiddleware("admin"), menuAccessHandler.Set)
-	menuAccess.Delete("/:user_id", middleware.RoleMiddleware("admin"), menuAccessHandler.Delete)
+	menuAccess.Get("/", middleware.RequirePermission(permissions.MenuAccessManage), menuAccessHandler.GetAll)
+	menuAccess.Post("/", middleware.RequirePermission(permissions.MenuAccessManage), menuAccessHandler.Set)
+	menuAccess.Delete("/:user_id", middleware.RequirePermission(permissions.MenuAccessManage), menuAccessHandler.Delete)
 
 	// Notification routes (all authenticated)
-	notifications := api.Group("/notifications", middleware.AuthMiddleware(cfg))
+	notifications := api.Group("/notifications", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService))
 	notifications.Get("/", notifHandler.GetMyNotifications)
 	notifications.Get("/unread-count", notifHandler.GetUnreadCount)
+	notifications.Get("/stream", notifHandler.Stream)
+	notifications.Get("/preferences", notifHandler.GetPreferences)
+	notifications.Put("/preferences", notifHandler.UpdatePreferences)
 	notifications.Put("/read-all", notifHandler.MarkAllAsRead)
+	notifications.Post("/archive", notifHandler.Archive)
+	notifications.Post("/unarchive", notifHandler.Unarchive)
+	notifications.Post("/delete", notifHandler.Delete)
+	notifications.Delete("/:id", notifHandler.DeleteOne)
 	notifications.Put("/:id/read", notifHandler.MarkAsRead)
 
-	// Job level routes (admin, hr can view; admin can manage)
-	jobLevels := api.Group("/job-levels", middleware.AuthMiddleware(cfg), middleware.RoleMiddleware("admin", "hr"))
+	// Job level routes
+	jobLevels := api.Group("/job-levels", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequirePermission(permissions.OrganizationRead))
 	jobLevels.Get("/", jobLevelHandler.GetAll)
 	jobLevels.Get("/:id", jobLevelHandler.GetByID)
-	jobLevels.Post("/", middleware.RoleMiddleware("admin"), jobLevelHandler.Create)
-	jobLevels.Put("/:id", middleware.RoleMiddleware("admin"), jobLevelHandler.Update)
-	jobLevels.Delete("/:id", middleware.RoleMiddleware("admin"), jobLevelHandler.Delete)
+	jobLevels.Post("/", middleware.RequirePermission(permissions.OrganizationManage), jobLevelHandler.Create)
+	jobLevels.Put("/:id", middleware.RequirePermission(permissions.OrganizationManage), jobLevelHandler.Update)
+	jobLevels.Delete("/:id", middleware.RequirePermission(permissions.OrganizationManage), jobLevelHandler.Delete)
 
-	// Grade routes (admin, hr can view; admin can manage)
-	grades := api.Group("/grades", middleware.AuthMiddleware(cfg), middleware.RoleMiddleware("admin", "hr"))
+	// Grade routes
+	grades := api.Group("/grades", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequirePermission(permissions.OrganizationRead))
 	grades.Get("/", gradeHandler.GetAll)
 	grades.Get("/:id", gradeHandler.GetByID)
-	grades.Post("/", middleware.RoleMiddleware("admin"), gradeHandler.Create)
-	grades.Put("/:id", middleware.RoleMiddleware("admin"), gradeHandler.Update)
-	grades.Delete("/:id", middleware.RoleMiddleware("admin"), gradeHandler.Delete)
+	grades.Post("/", middleware.RequirePermission(permissions.OrganizationManage), gradeHandler.Create)
+	grades.Put("/:id", middleware.RequirePermission(permissions.OrganizationManage), gradeHandler.Update)
+	grades.Delete("/:id", middleware.RequirePermission(permissions.OrganizationManage), gradeHandler.Delete)
 
 	// Module catalog (all authenticated users can read catalog; superadmin manages per-company toggles)
-	modulesRoutes := api.Group("/modules", middleware.AuthMiddleware(cfg))
+	modulesRoutes := api.Group("/modules", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService))
 	modulesRoutes.Get("/", moduleHandler.ListCatalog)
 
 	// Per-user effective modules — used by FE to filter the sidebar
-	api.Get("/me/modules", middleware.AuthMiddleware(cfg), moduleHandler.GetMyModules)
+	api.Get("/me/modules", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), moduleHandler.GetMyModules)
 
 	// Superadmin-only: manage per-company module toggles
-	companyModules := api.Group("/companies/:id/modules", middleware.AuthMiddleware(cfg), middleware.RoleMiddleware("superadmin"))
+	companyModules := api.Group("/companies/:id/modules", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RoleMiddleware("superadmin"))
 	companyModules.Get("/", moduleHandler.ListForCompany)
 	companyModules.Put("/:key", moduleHandler.SetForCompany)
 
 	// Visit tracking (opt-in module: visit_tracking) — multi-point check-ins inside one attendance session
-	visits := api.Group("/visits", middleware.AuthMiddleware(cfg), middleware.RequireModule("visit_tracking", moduleService, empService))
+	visits := api.Group("/visits", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequireModule("visit_tracking", moduleService, empService))
 	visits.Post("/start", visitHandler.Start)
 	visits.Post("/:id/end", visitHandler.End)
 	visits.Get("/attendance/:attendanceId", visitHandler.GetByAttendanceID)
-	visits.Get("/", middleware.RoleMiddleware("admin", "hr"), visitHandler.List)
+	visits.Get("/", middleware.RequirePermission(permissions.VisitsRead), visitHandler.List)
 	visits.Get("/:id", visitHandler.GetByID)
-	visits.Delete("/:id", middleware.RoleMiddleware("admin"), visitHandler.Delete)
+	visits.Delete("/:id", middleware.RequirePermission(permissions.VisitsManage), visitHandler.Delete)
 
 	// Visit planning (opt-in module: visit_planning) — depends on visit_tracking
-	visitPlans := api.Group("/visit-plans", middleware.AuthMiddleware(cfg), middleware.RequireModule("visit_planning", moduleService, empService))
-	visitPlans.Get("/report", middleware.RoleMiddleware("admin", "hr"), visitPlanHandler.AdherenceReport)
+	visitPlans := api.Group("/visit-plans", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequireModule("visit_planning", moduleService, empService))
+	visitPlans.Get("/report", middleware.RequirePermission(permissions.VisitPlansManage), visitPlanHandler.AdherenceReport)
 	visitPlans.Get("/by-date", visitPlanHandler.GetByEmployeeAndDate)
 	visitPlans.Get("/employee/:employeeId", visitPlanHandler.ListByEmployee)
-	visitPlans.Post("/", middleware.RoleMiddleware("admin", "hr"), visitPlanHandler.Create)
+	visitPlans.Post("/", middleware.RequirePermission(permissions.VisitPlansManage), visitPlanHandler.Create)
 	visitPlans.Get("/:id", visitPlanHandler.GetByID)
-	visitPlans.Put("/:id", middleware.RoleMiddleware("admin", "hr"), visitPlanHandler.Update)
-	visitPlans.Delete("/:id", middleware.RoleMiddleware("admin"), visitPlanHandler.Delete)
-	visitPlans.Post("/:id/items", middleware.RoleMiddleware("admin", "hr"), visitPlanHandler.AddItem)
+	visitPlans.Put("/:id", middleware.RequirePermission(permissions.VisitPlansManage), visitPlanHandler.Update)
+	visitPlans.Delete("/:id", middleware.RequirePermission(permissions.VisitPlansDelete), visitPlanHandler.Delete)
+	visitPlans.Post("/:id/items", middleware.RequirePermission(permissions.VisitPlansManage), visitPlanHandler.AddItem)
 	visitPlans.Put("/items/:itemId", visitPlanHandler.UpdateItem)
-	visitPlans.Delete("/items/:itemId", middleware.RoleMiddleware("admin", "hr"), visitPlanHandler.DeleteItem)
+	visitPlans.Delete("/items/:itemId", middleware.RequirePermission(permissions.VisitPlansManage), visitPlanHandler.DeleteItem)
+
+	// Announcements (opt-in module: announcements) — company bulletin board with read receipts
+	announcements := api.Group("/announcements", middleware.AuthMiddleware(signingKeys, scopeService, permService, apiTokenService), middleware.RequireModule("announcements", moduleService, empService))
+	announcements.Get("/", announcementHandler.GetMine)
+	announcements.Get("/manage", middleware.RequirePermission(permissions.AnnouncementsManage), announcementHandler.GetAll)
+	announcements.Post("/", middleware.RequirePermission(permissions.AnnouncementsManage), announcementHandler.Create)
+	announcements.Get("/:id", announcementHandler.GetByID)
+	announcements.Put("/:id", middleware.RequirePermission(permissions.AnnouncementsManage), announcementHandler.Update)
+	announcements.Delete("/:id", middleware.RequirePermission(permissions.AnnouncementsManage), announcementHandler.Delete)
+	announcements.Post("/:id/publish", middleware.RequirePermission(permissions.AnnouncementsManage), announcementHandler.Publish)
+	announcements.Post("/:id/unpublish", middleware.RequirePermission(permissions.AnnouncementsManage), announcementHandler.Unpublish)
+	announcements.Post("/:id/read", announcementHandler.MarkAsRead)
+	announcements.Get("/:id/read-report", middleware.RequirePermission(permissions.AnnouncementsManage), announcementHandler.GetReadReport)
+
+	// Public keys for verifying access tokens
+	app.Get("/.well-known/jwks.json", jwksHandler.GetKeys)
 
 	// Swagger documentation
 	app.Get("/swagger/*", fiberSwagger.WrapHandler)
@@ -347,6 +631,26 @@ func main() {
 	log.Fatal(app.Listen(fmt.Sprintf(":%s", cfg.AppPort)))
 }
 
+// loadSigningKeys loads the access token signing keys. Development without
+// JWT_KEYS_DIR gets a throwaway key, so access tokens die with the process.
+func loadSigningKeys(cfg *config.Config) *jwtPkg.KeySet {
+	if cfg.JWTKeysDir == "" {
+		keys, err := jwtPkg.GenerateKeySet()
+		if err != nil {
+			log.Fatalf("Failed to generate signing key: %v", err)
+		}
+		log.Printf("JWT_KEYS_DIR not set; signing access tokens with ephemeral key %s", keys.Active().ID)
+		return keys
+	}
+
+	keys, err := jwtPkg.LoadKeySet(cfg.JWTKeysDir, cfg.JWTActiveKeyID)
+	if err != nil {
+		log.Fatalf("Failed to load signing keys: %v", err)
+	}
+	log.Printf("Signing access tokens with key %s", keys.Active().ID)
+	return keys
+}
+
 func seedSuperAdmin(db *gorm.DB, cfg *config.Config) {
 	var count int64
 	db.Model(&model.User{}).Where("role = ?", "superadmin").Count(&count)
@@ -366,6 +670,9 @@ func seedSuperAdmin(db *gorm.DB, cfg *config.Config) {
 		Password: hashedPassword,
 		Role:     model.RoleSuperAdmin,
 		IsActive: true,
+		// The seeded password is a config default; force the first login to
+		// replace it
+		MustChangePassword: true,
 	}
 
 	if err := db.Create(superAdmin).Error; err != nil {
@@ -421,11 +728,12 @@ func seedAdmin(db *gorm.DB, cfg *config.Config) {
 	}
 
 	admin := &model.User{
-		Name:     "Administrator",
-		Email:    cfg.AdminEmail,
-		Password: hashedPassword,
-		Role:     model.RoleAdmin,
-		IsActive: true,
+		Name:               "Administrator",
+		Email:              cfg.AdminEmail,
+		Password:           hashedPassword,
+		Role:               model.RoleAdmin,
+		IsActive:           true,
+		MustChangePassword: true,
 	}
 
 	if err := db.Create(admin).Error; err != nil {
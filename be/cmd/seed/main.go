@@ -699,7 +699,7 @@ func seedLeaves(db *gorm.DB, employees []model.Employee, users []model.User) {
 		LeaveType       model.LeaveType
 		StartDate       string
 		EndDate         string
-		TotalDays       int
+		TotalDays       float64
 		Reason          string
 		Status          model.LeaveStatus
 		ApprovedBy      string
@@ -784,14 +784,14 @@ func seedPayroll(db *gorm.DB, employees []model.Employee) {
 	}
 
 	for i, p := range payrollData {
-		kesEmp, _, jhtEmp, _, _, _, jpEmp, _ := calculator.CalculateBPJS(p.BasicSalary)
+		kesEmp, kesCompany, jhtEmp, _, jkk, jkm, jpEmp, _ := calculator.CalculateBPJS(p.BasicSalary)
 		bpjsKesDeduction := kesEmp
 		bpjsTKDeduction := jhtEmp + jpEmp
 		grossSalary := p.BasicSalary + p.TotalAllowances + p.OvertimePay
 
-		// Simple PPh21 estimation (use TK/0 PTKP = 54,000,000)
-		annualGross := grossSalary * 12
-		pph21Monthly := calculator.CalculatePPh21Monthly(annualGross, 54000000)
+		// PPh21 for January uses the TER rate (assume TK/0, category A)
+		taxableIncome := grossSalary + jkk + jkm + kesCompany
+		pph21Monthly := calculator.CalculatePPh21TER(taxableIncome, calculator.TERCategoryA)
 
 		totalDeductions := bpjsKesDeduction + bpjsTKDeduction + pph21Monthly
 		netSalary := grossSalary - totalDeductions
@@ -810,6 +810,9 @@ func seedPayroll(db *gorm.DB, employees []model.Employee) {
 			BPJSKesDeduction: bpjsKesDeduction,
 			BPJSTKDeduction:  bpjsTKDeduction,
 			PPH21:            pph21Monthly,
+			TaxableIncome:    taxableIncome,
+			PTKPStatus:       "TK/0",
+			TERCategory:      string(calculator.TERCategoryA),
 			OtherDeductions:  0,
 			GrossSalary:      grossSalary,
 			NetSalary:        netSalary,
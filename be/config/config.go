@@ -1,15 +1,36 @@
 package config
 
 import (
+	"errors"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// defaultSecrets are secrets shipped with the code or its examples. Outside
+// development they are refused, as is anything too short to brute-force.
+var defaultSecrets = map[string]bool{
+	"refresh-secret":                         true,
+	"your-refresh-secret-key-change-this":    true,
+	"hris-jwt-refresh-secret-prod-change-me": true,
+}
+
+const minSecretLength = 32
+
+// defaultPasswords are the seed account passwords shipped with the code and
+// its examples. Outside development they are refused.
+var defaultPasswords = map[string]bool{
+	"superadmin123": true,
+	"admin123":      true,
+}
+
 type Config struct {
+	AppEnv string
+
 	DBHost     string
 	DBPort     string
 	DBUser     string
@@ -17,21 +38,63 @@ type Config struct {
 	DBName     string
 	DBSSLMode  string
 
-	JWTSecret         string
-	JWTRefreshSecret  string
-	JWTAccessExpiry   time.Duration
-	JWTRefreshExpiry  time.Duration
+	JWTRefreshSecret string
+	JWTAccessExpiry  time.Duration
+	JWTRefreshExpiry time.Duration
+
+	// JWTKeysDir holds the PEM keys access tokens are signed with; see
+	// jwt.LoadKeySet. JWTActiveKeyID picks the signing key among them.
+	JWTKeysDir     string
+	JWTActiveKeyID string
+
+	PasswordResetExpiry time.Duration
+
+	TwoFactorIssuer          string
+	TwoFactorRequiredRoles   []string
+	TwoFactorChallengeExpiry time.Duration
+
+	LoginMaxFailures     int
+	LoginLockoutDuration time.Duration
+	LoginIPMaxFailures   int
+	LoginIPWindow        time.Duration
 
 	AppPort      string
 	CORSOrigins  string
 
+	// Requests from TrustedProxies (IPs or CIDRs) take the client IP from
+	// ProxyHeader, which those proxies must overwrite rather than append to.
+	// Other requests use the peer address, so clients cannot pick their IP.
+	TrustedProxies []string
+	ProxyHeader    string
+
+	AppBaseURL  string
+	FrontendURL string
+
 	SuperAdminEmail    string
 	SuperAdminPassword string
 
 	AdminEmail    string
 	AdminPassword string
 
-	KafkaBrokers []string
+	KafkaBrokers     []string
+	KafkaCompression string
+
+	// Notifications are also emailed when SMTPHost is set. SMTPTLS is one
+	// of auto, starttls, tls or none; see mailer.Config.
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+	SMTPTLS      string
+
+	// NotificationDigestHour is the hour of the day, in server time, daily
+	// notification digests are sent at
+	NotificationDigestHour int
+
+	// Read notifications are purged NotificationRetentionDays after they
+	// were created, deleted ones that long after their deletion
+	NotificationRetentionDays int
 
 	SigNozEndpoint    string
 	SigNozAccessToken string
@@ -52,7 +115,29 @@ func Load() *Config {
 		refreshExpiry = 7 * 24 * time.Hour
 	}
 
+	resetExpiry, err := time.ParseDuration(getEnv("PASSWORD_RESET_EXPIRY", "30m"))
+	if err != nil {
+		resetExpiry = 30 * time.Minute
+	}
+
+	challengeExpiry, err := time.ParseDuration(getEnv("TWO_FACTOR_CHALLENGE_EXPIRY", "5m"))
+	if err != nil {
+		challengeExpiry = 5 * time.Minute
+	}
+
+	lockoutDuration, err := time.ParseDuration(getEnv("LOGIN_LOCKOUT_DURATION", "15m"))
+	if err != nil {
+		lockoutDuration = 15 * time.Minute
+	}
+
+	ipWindow, err := time.ParseDuration(getEnv("LOGIN_IP_WINDOW", "15m"))
+	if err != nil {
+		ipWindow = 15 * time.Minute
+	}
+
 	return &Config{
+		AppEnv: getEnv("APP_ENV", "production"),
+
 		DBHost:     getEnv("DB_HOST", "localhost"),
 		DBPort:     getEnv("DB_PORT", "5432"),
 		DBUser:     getEnv("DB_USER", "postgres"),
@@ -60,27 +145,83 @@ func Load() *Config {
 		DBName:     getEnv("DB_NAME", "hris"),
 		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
 
-		JWTSecret:        getEnv("JWT_SECRET", "secret"),
 		JWTRefreshSecret: getEnv("JWT_REFRESH_SECRET", "refresh-secret"),
 		JWTAccessExpiry:  accessExpiry,
 		JWTRefreshExpiry: refreshExpiry,
+		JWTKeysDir:       getEnv("JWT_KEYS_DIR", ""),
+		JWTActiveKeyID:   getEnv("JWT_ACTIVE_KEY_ID", ""),
+
+		PasswordResetExpiry: resetExpiry,
+
+		TwoFactorIssuer:          getEnv("TWO_FACTOR_ISSUER", "HRIS"),
+		TwoFactorRequiredRoles:   splitList(getEnv("TWO_FACTOR_REQUIRED_ROLES", "")),
+		TwoFactorChallengeExpiry: challengeExpiry,
+
+		LoginMaxFailures:     getEnvInt("LOGIN_MAX_FAILURES", 5),
+		LoginLockoutDuration: lockoutDuration,
+		LoginIPMaxFailures:   getEnvInt("LOGIN_IP_MAX_FAILURES", 20),
+		LoginIPWindow:        ipWindow,
 
 		AppPort:     getEnv("APP_PORT", "8080"),
 		CORSOrigins: getEnv("CORS_ORIGINS", "http://localhost:3000"),
 
+		TrustedProxies: splitList(getEnv("TRUSTED_PROXIES", "")),
+		ProxyHeader:    getEnv("PROXY_HEADER", "X-Real-IP"),
+
+		AppBaseURL:  strings.TrimSuffix(getEnv("APP_BASE_URL", "http://localhost:8080"), "/"),
+		FrontendURL: strings.TrimSuffix(getEnv("FRONTEND_URL", "http://localhost:3000"), "/"),
+
 		SuperAdminEmail:    getEnv("SUPERADMIN_EMAIL", "superadmin@hris.com"),
 		SuperAdminPassword: getEnv("SUPERADMIN_PASSWORD", "superadmin123"),
 
 		AdminEmail:    getEnv("ADMIN_EMAIL", "admin@hris.com"),
 		AdminPassword: getEnv("ADMIN_PASSWORD", "admin123"),
 
-		KafkaBrokers: splitBrokers(getEnv("KAFKA_BROKERS", "localhost:9092")),
+		KafkaBrokers:     splitList(getEnv("KAFKA_BROKERS", "localhost:9092")),
+		KafkaCompression: getEnv("KAFKA_COMPRESSION", "snappy"),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnvInt("SMTP_PORT", 587),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", "HRIS <no-reply@hris.local>"),
+		SMTPTLS:      getEnv("SMTP_TLS", "auto"),
+
+		NotificationDigestHour:    getEnvInt("NOTIFICATION_DIGEST_HOUR", 7),
+		NotificationRetentionDays: getEnvInt("NOTIFICATION_RETENTION_DAYS", 90),
 
 		SigNozEndpoint:    getEnv("SIGNOZ_ENDPOINT", ""),
 		SigNozAccessToken: getEnv("SIGNOZ_ACCESS_TOKEN", ""),
 	}
 }
 
+// IsDevelopment reports whether the server runs with APP_ENV=development,
+// where default secrets and throwaway signing keys are tolerated. Without
+// APP_ENV the server runs as production, so a forgotten setting fails safe.
+func (c *Config) IsDevelopment() bool {
+	return c.AppEnv == "development"
+}
+
+// Validate refuses settings that are only safe in development
+func (c *Config) Validate() error {
+	if c.IsDevelopment() {
+		return nil
+	}
+	if defaultSecrets[c.JWTRefreshSecret] || len(c.JWTRefreshSecret) < minSecretLength {
+		return errors.New("JWT_REFRESH_SECRET must be set to a random value of at least 32 characters")
+	}
+	if c.JWTKeysDir == "" {
+		return errors.New("JWT_KEYS_DIR must point to the access token signing keys")
+	}
+	if c.SuperAdminPassword == "" || defaultPasswords[c.SuperAdminPassword] {
+		return errors.New("SUPERADMIN_PASSWORD must be set to a password other than the default")
+	}
+	if c.AdminPassword == "" || defaultPasswords[c.AdminPassword] {
+		return errors.New("ADMIN_PASSWORD must be set to a password other than the default")
+	}
+	return nil
+}
+
 func getEnv(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
@@ -88,12 +229,21 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
-func splitBrokers(brokers string) []string {
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// splitList splits a comma-separated env value, dropping empty items
+func splitList(list string) []string {
 	var result []string
-	for _, b := range strings.Split(brokers, ",") {
-		b = strings.TrimSpace(b)
-		if b != "" {
-			result = append(result, b)
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
 		}
 	}
 	return result
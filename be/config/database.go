@@ -5,6 +5,7 @@ import (
 	"log"
 
 	"hris-backend/internal/model"
+	"hris-backend/internal/tenant"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -24,8 +25,26 @@ func ConnectDatabase(cfg *Config) *gorm.DB {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
+	if err := tenant.RegisterCallbacks(db); err != nil {
+		log.Fatalf("Failed to register tenant callbacks: %v", err)
+	}
+
 	if err := db.AutoMigrate(
 		&model.User{},
+		&model.Session{},
+		&model.PasswordResetToken{},
+		&model.UserTwoFactor{},
+		&model.RecoveryCode{},
+		&model.LoginAttempt{},
+		&model.OIDCProvider{},
+		&model.OIDCLoginState{},
+		&model.UserIdentity{},
+		&model.APIToken{},
+		&model.OutboxEvent{},
+		&model.DeadLetterEvent{},
+		&model.EmailDelivery{},
+		&model.NotificationPreference{},
+		&model.NotificationDigestItem{},
 		&model.Company{},
 		&model.Department{},
 		&model.Position{},
@@ -34,11 +53,21 @@ func ConnectDatabase(cfg *Config) *gorm.DB {
 		&model.EmployeeSalary{},
 		&model.Attendance{},
 		&model.Leave{},
+		&model.LeavePolicy{},
+		&model.LeaveEntitlement{},
+		&model.LeaveLedgerEntry{},
+		&model.LeaveWorkflow{},
+		&model.LeaveWorkflowStep{},
+		&model.LeaveApproval{},
+		&model.LeaveDelegation{},
 		&model.Holiday{},
 		&model.Payroll{},
+		&model.PayrollRun{},
 		&model.Permission{},
 		&model.RolePermission{},
+		&model.CustomRole{},
 		&model.MenuAccess{},
+		&model.UserCompany{},
 		&model.Notification{},
 		&model.JobLevel{},
 		&model.Grade{},
@@ -47,6 +76,10 @@ func ConnectDatabase(cfg *Config) *gorm.DB {
 		&model.Visit{},
 		&model.VisitPlan{},
 		&model.VisitPlanItem{},
+		&model.Announcement{},
+		&model.AnnouncementTarget{},
+		&model.AnnouncementAttachment{},
+		&model.AnnouncementRead{},
 	); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
@@ -23,70 +23,32 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/attendances": {
+        "/announcements": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve all attendance records with optional filters and pagination",
+                "description": "Returns the announcements shown now to the authenticated user, pinned first, then newest first, each telling whether the user read it. Employees see the announcements for their whole company and for their department or job level; users without an employee record see the ones for the whole company",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Attendances"
+                    "Announcements"
                 ],
-                "summary": "Get all attendances",
+                "summary": "Get my announcements",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by employee ID",
-                        "name": "employee_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter by month (1-12)",
-                        "name": "month",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter by year",
-                        "name": "year",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter start date (YYYY-MM-DD)",
-                        "name": "start_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter end date (YYYY-MM-DD)",
-                        "name": "end_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Items per page",
-                        "name": "limit",
+                        "description": "Company, for users without an employee record (defaults to their primary company)",
+                        "name": "company_id",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Attendances retrieved",
+                        "description": "Announcements retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -96,7 +58,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.PaginatedAttendanceResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.AnnouncementResponse"
+                                            }
                                         }
                                     }
                                 }
@@ -104,13 +69,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid month or year format",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Failed to fetch attendances",
+                        "description": "No company",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -123,7 +82,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Manually create an attendance record",
+                "description": "Creates a draft announcement. Without department_ids and job_level_ids it is for the whole company; otherwise for the employees of any of the departments and job levels. The body is HTML: only basic formatting and links are kept. Attachments are linked by URL",
                 "consumes": [
                     "application/json"
                 ],
@@ -131,23 +90,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Attendances"
+                    "Announcements"
                 ],
-                "summary": "Create an attendance record",
+                "summary": "Create an announcement",
                 "parameters": [
                     {
-                        "description": "Attendance data",
+                        "description": "Announcement data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateAttendanceRequest"
+                            "$ref": "#/definitions/dto.CreateAnnouncementRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Attendance created",
+                        "description": "Announcement created",
                         "schema": {
                             "allOf": [
                                 {
@@ -157,7 +116,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.AttendanceResponse"
+                                            "$ref": "#/definitions/dto.AnnouncementResponse"
                                         }
                                     }
                                 }
@@ -173,38 +132,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/attendances/clock-in": {
-            "post": {
+        "/announcements/manage": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Record clock-in time for an employee",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieve the announcements of the companies in scope, drafts included, pinned first, then newest first, with optional filters and pagination",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Attendances"
+                    "Announcements"
                 ],
-                "summary": "Clock in",
+                "summary": "Get all announcements",
                 "parameters": [
                     {
-                        "description": "Clock-in data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.ClockInRequest"
-                        }
+                        "type": "string",
+                        "description": "Filter by company",
+                        "name": "company_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status (draft, scheduled, published, expired)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Clock in successful",
+                    "200": {
+                        "description": "Announcements retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -214,7 +187,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.AttendanceResponse"
+                                            "$ref": "#/definitions/dto.PaginatedAnnouncementResponse"
                                         }
                                     }
                                 }
@@ -222,50 +195,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request or already clocked in",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    }
-                }
-            }
-        },
-        "/attendances/import": {
-            "post": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Bulk import attendance records from an Excel (.xlsx) file. Required columns: employee_number, date, status. Optional columns: clock_in, clock_out, overtime_hours, notes. Valid statuses: hadir, alpha, terlambat, izin, sakit, cuti.",
-                "consumes": [
-                    "multipart/form-data"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Attendances"
-                ],
-                "summary": "Import attendances from XLSX",
-                "parameters": [
-                    {
-                        "type": "file",
-                        "description": "XLSX file",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Import result with imported count and errors",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid file or data errors",
+                        "description": "Invalid filter",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -273,25 +203,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/attendances/{id}": {
+        "/announcements/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve an attendance record by its ID",
+                "description": "Users who manage announcements get any announcement in scope; others only the ones shown to them",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Attendances"
+                    "Announcements"
                 ],
-                "summary": "Get attendance by ID",
+                "summary": "Get an announcement",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Attendance ID",
+                        "description": "Announcement ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -299,7 +229,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Attendance retrieved",
+                        "description": "Announcement retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -309,7 +239,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.AttendanceResponse"
+                                            "$ref": "#/definitions/dto.AnnouncementResponse"
                                         }
                                     }
                                 }
@@ -317,7 +247,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Attendance not found",
+                        "description": "Announcement not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -330,7 +260,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Update an existing attendance record by ID",
+                "description": "Changes the fields that are set; department_ids, job_level_ids and attachments replace the current ones. Editing a published announcement does not notify anyone again",
                 "consumes": [
                     "application/json"
                 ],
@@ -338,30 +268,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Attendances"
+                    "Announcements"
                 ],
-                "summary": "Update an attendance record",
+                "summary": "Update an announcement",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Attendance ID",
+                        "description": "Announcement ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Attendance data",
+                        "description": "Fields to update",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateAttendanceRequest"
+                            "$ref": "#/definitions/dto.UpdateAnnouncementRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Attendance updated",
+                        "description": "Announcement updated",
                         "schema": {
                             "allOf": [
                                 {
@@ -371,7 +301,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.AttendanceResponse"
+                                            "$ref": "#/definitions/dto.AnnouncementResponse"
                                         }
                                     }
                                 }
@@ -392,18 +322,17 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Delete an attendance record by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Attendances"
+                    "Announcements"
                 ],
-                "summary": "Delete an attendance record",
+                "summary": "Delete an announcement",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Attendance ID",
+                        "description": "Announcement ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -411,13 +340,13 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Attendance deleted",
+                        "description": "Announcement deleted",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "400": {
-                        "description": "Failed to delete",
+                    "404": {
+                        "description": "Announcement not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -425,14 +354,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/attendances/{id}/clock-out": {
-            "put": {
+        "/announcements/{id}/publish": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Record clock-out time for an existing attendance record",
+                "description": "Shows an announcement from publish_at, or right away. Its audience is notified when it is first shown, in the application and by email as each user chose for announcement.published",
                 "consumes": [
                     "application/json"
                 ],
@@ -440,30 +369,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Attendances"
+                    "Announcements"
                 ],
-                "summary": "Clock out",
+                "summary": "Publish an announcement",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Attendance ID",
+                        "description": "Announcement ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Clock-out data",
+                        "description": "When to publish",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.ClockOutRequest"
+                            "$ref": "#/definitions/dto.PublishAnnouncementRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Clock out successful",
+                        "description": "Announcement published",
                         "schema": {
                             "allOf": [
                                 {
@@ -473,7 +401,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.AttendanceResponse"
+                                            "$ref": "#/definitions/dto.AnnouncementResponse"
                                         }
                                     }
                                 }
@@ -481,7 +409,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request or already clocked out",
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -489,33 +417,73 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/login": {
+        "/announcements/{id}/read": {
             "post": {
-                "description": "Authenticate user with email and password, returns access token and sets refresh token in cookie",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "Records that the authenticated user read an announcement shown to them. Reading it again keeps the first receipt",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Announcements"
                 ],
-                "summary": "User login",
+                "summary": "Mark an announcement as read",
                 "parameters": [
                     {
-                        "description": "Login credentials",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Announcement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Announcement marked as read",
                         "schema": {
-                            "$ref": "#/definitions/dto.LoginRequest"
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Announcement not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
                         }
                     }
+                }
+            }
+        },
+        "/announcements/{id}/read-report": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Tells how many of the current employees an announcement is for read it, and lists the ones who have not, by employee number",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Announcements"
+                ],
+                "summary": "Get the read report of an announcement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Announcement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login successful",
+                        "description": "Read report retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -525,21 +493,15 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.TokenResponse"
+                                            "$ref": "#/definitions/dto.AnnouncementReadReportResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Invalid request body",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Announcement not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -547,44 +509,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/logout": {
+        "/announcements/{id}/unpublish": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Clear refresh token cookie to logout user",
+                "description": "Takes an announcement back to draft",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Announcements"
                 ],
-                "summary": "User logout",
-                "responses": {
-                    "200": {
-                        "description": "Logged out successfully",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
+                "summary": "Unpublish an announcement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Announcement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
-                }
-            }
-        },
-        "/auth/refresh": {
-            "post": {
-                "description": "Generate new access token using refresh token from cookie",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Authentication"
                 ],
-                "summary": "Refresh access token",
                 "responses": {
                     "200": {
-                        "description": "Token refreshed",
+                        "description": "Announcement unpublished",
                         "schema": {
                             "allOf": [
                                 {
@@ -594,15 +545,15 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.TokenResponse"
+                                            "$ref": "#/definitions/dto.AnnouncementResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized - refresh token not found or invalid",
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -610,56 +561,70 @@ const docTemplate = `{
                 }
             }
         },
-        "/companies": {
+        "/attendances": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve companies with pagination, search, and sorting",
+                "description": "Retrieve all attendance records with optional filters and pagination",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Companies"
+                    "Attendances"
                 ],
-                "summary": "Get all companies",
+                "summary": "Get all attendances",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by employee ID",
+                        "name": "employee_id",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Page number (default 1)",
-                        "name": "page",
+                        "description": "Filter by month (1-12)",
+                        "name": "month",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Items per page (default 10)",
-                        "name": "limit",
+                        "description": "Filter by year",
+                        "name": "year",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Search by name, email, phone, or NPWP",
-                        "name": "search",
+                        "description": "Filter start date (YYYY-MM-DD)",
+                        "name": "start_date",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Sort field: name, email, phone, npwp, is_active, created_at",
-                        "name": "sort_by",
+                        "description": "Filter end date (YYYY-MM-DD)",
+                        "name": "end_date",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Sort direction: asc or desc",
-                        "name": "sort_order",
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Companies retrieved",
+                        "description": "Attendances retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -669,15 +634,21 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.PaginatedCompanyResponse"
+                                            "$ref": "#/definitions/dto.PaginatedAttendanceResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Invalid month or year format",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
-                        "description": "Failed to fetch companies",
+                        "description": "Failed to fetch attendances",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -690,7 +661,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new company",
+                "description": "Manually create an attendance record",
                 "consumes": [
                     "application/json"
                 ],
@@ -698,23 +669,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Companies"
+                    "Attendances"
                 ],
-                "summary": "Create a company",
+                "summary": "Create an attendance record",
                 "parameters": [
                     {
-                        "description": "Company data",
+                        "description": "Attendance data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateCompanyRequest"
+                            "$ref": "#/definitions/dto.CreateAttendanceRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Company created",
+                        "description": "Attendance created",
                         "schema": {
                             "allOf": [
                                 {
@@ -724,7 +695,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.CompanyResponse"
+                                            "$ref": "#/definitions/dto.AttendanceResponse"
                                         }
                                     }
                                 }
@@ -738,14 +709,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/attendances/clock-in": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete multiple companies by IDs",
+                "description": "Record clock-in time for an employee",
                 "consumes": [
                     "application/json"
                 ],
@@ -753,29 +726,84 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Companies"
+                    "Attendances"
                 ],
-                "summary": "Delete multiple companies",
+                "summary": "Clock in",
                 "parameters": [
                     {
-                        "description": "Company IDs",
+                        "description": "Clock-in data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.DeleteMultipleCompaniesRequest"
+                            "$ref": "#/definitions/dto.ClockInRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Clock in successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.AttendanceResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or already clocked in",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
                         }
                     }
+                }
+            }
+        },
+        "/attendances/import": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Bulk import attendance records from an Excel (.xlsx) file. Required columns: employee_number, date, status. Optional columns: clock_in, clock_out, overtime_hours, notes. Valid statuses: hadir, alpha, terlambat, izin, sakit, cuti.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Attendances"
+                ],
+                "summary": "Import attendances from XLSX",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "XLSX file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Companies deleted",
+                        "description": "Import result with imported count and errors",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
                     "400": {
-                        "description": "Invalid request or failed to delete",
+                        "description": "Invalid file or data errors",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -783,25 +811,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/companies/{id}": {
+        "/attendances/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve a company by its ID",
+                "description": "Retrieve an attendance record by its ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Companies"
+                    "Attendances"
                 ],
-                "summary": "Get company by ID",
+                "summary": "Get attendance by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Company ID",
+                        "description": "Attendance ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -809,7 +837,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Company retrieved",
+                        "description": "Attendance retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -819,7 +847,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.CompanyResponse"
+                                            "$ref": "#/definitions/dto.AttendanceResponse"
                                         }
                                     }
                                 }
@@ -827,7 +855,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Company not found",
+                        "description": "Attendance not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -840,7 +868,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Update an existing company by ID",
+                "description": "Update an existing attendance record by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -848,30 +876,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Companies"
+                    "Attendances"
                 ],
-                "summary": "Update a company",
+                "summary": "Update an attendance record",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Company ID",
+                        "description": "Attendance ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Company data",
+                        "description": "Attendance data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateCompanyRequest"
+                            "$ref": "#/definitions/dto.UpdateAttendanceRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Company updated",
+                        "description": "Attendance updated",
                         "schema": {
                             "allOf": [
                                 {
@@ -881,7 +909,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.CompanyResponse"
+                                            "$ref": "#/definitions/dto.AttendanceResponse"
                                         }
                                     }
                                 }
@@ -902,18 +930,18 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a company by ID",
+                "description": "Delete an attendance record by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Companies"
+                    "Attendances"
                 ],
-                "summary": "Delete a company",
+                "summary": "Delete an attendance record",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Company ID",
+                        "description": "Attendance ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -921,7 +949,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Company deleted",
+                        "description": "Attendance deleted",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -935,33 +963,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/companies/{id}/modules": {
-            "get": {
+        "/attendances/{id}/clock-out": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Superadmin view: every module + whether it is enabled for the given company.",
+                "description": "Record clock-out time for an existing attendance record",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Modules"
+                    "Attendances"
                 ],
-                "summary": "List modules with enabled state for a specific company",
+                "summary": "Clock out",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Company ID",
+                        "description": "Attendance ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Clock-out data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ClockOutRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Company modules fetched",
+                        "description": "Clock out successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -971,18 +1011,15 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dto.CompanyModuleResponse"
-                                            }
+                                            "$ref": "#/definitions/dto.AttendanceResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "500": {
-                        "description": "Failed to fetch modules",
+                    "400": {
+                        "description": "Invalid request or already clocked out",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -990,52 +1027,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/companies/{id}/modules/{key}": {
-            "put": {
+        "/auth/2fa": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Superadmin-only. Core modules cannot be disabled. Dependencies are validated.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Whether two-factor authentication is enabled for the current user, whether their role requires it, and how many recovery codes are left",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Modules"
-                ],
-                "summary": "Enable or disable a module for a company",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Company ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Module key (e.g. visit_tracking)",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Enable/disable + optional JSON config",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.SetCompanyModuleRequest"
-                        }
-                    }
+                    "Two-Factor Authentication"
                 ],
+                "summary": "Get my two-factor status",
                 "responses": {
                     "200": {
-                        "description": "Module updated",
+                        "description": "Two-factor status retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -1045,15 +1054,15 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.CompanyModuleResponse"
+                                            "$ref": "#/definitions/dto.TwoFactorStatusResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Invalid request or dependency violation",
+                    "500": {
+                        "description": "Failed to fetch two-factor status",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1061,66 +1070,59 @@ const docTemplate = `{
                 }
             }
         },
-        "/departments": {
-            "get": {
+        "/auth/2fa/disable": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve all departments, optionally filtered by company",
+                "description": "Turn two-factor authentication off with the password and a TOTP or recovery code. Not allowed when the user's role requires two-factor authentication",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Departments"
+                    "Two-Factor Authentication"
                 ],
-                "summary": "Get all departments",
+                "summary": "Disable two-factor authentication",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Filter by company ID",
-                        "name": "company_id",
-                        "in": "query"
+                        "description": "Password and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DisableTwoFactorRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Departments retrieved",
+                        "description": "Two-factor authentication disabled",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/response.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dto.DepartmentResponse"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "500": {
-                        "description": "Failed to fetch departments",
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/2fa/enable": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new department",
+                "description": "Confirm the secret from setup with a code from the authenticator app. Returns recovery codes, shown only once. Users required to enroll should refresh their access token afterwards",
                 "consumes": [
                     "application/json"
                 ],
@@ -1128,23 +1130,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Departments"
+                    "Two-Factor Authentication"
                 ],
-                "summary": "Create a department",
+                "summary": "Enable two-factor authentication",
                 "parameters": [
                     {
-                        "description": "Department data",
+                        "description": "TOTP code",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateDepartmentRequest"
+                            "$ref": "#/definitions/dto.TwoFactorCodeRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Department created",
+                    "200": {
+                        "description": "Two-factor authentication enabled",
                         "schema": {
                             "allOf": [
                                 {
@@ -1154,7 +1156,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.DepartmentResponse"
+                                            "$ref": "#/definitions/dto.RecoveryCodesResponse"
                                         }
                                     }
                                 }
@@ -1162,7 +1164,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Invalid code",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1170,33 +1172,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/departments/{id}": {
-            "get": {
+        "/auth/2fa/recovery-codes": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve a department by its ID",
+                "description": "Replace all recovery codes with new ones after checking a TOTP code. The new codes are shown only once",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Departments"
+                    "Two-Factor Authentication"
                 ],
-                "summary": "Get department by ID",
+                "summary": "Regenerate recovery codes",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Department ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "TOTP code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorCodeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Department retrieved",
+                        "description": "Recovery codes regenerated",
                         "schema": {
                             "allOf": [
                                 {
@@ -1206,59 +1213,40 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.DepartmentResponse"
+                                            "$ref": "#/definitions/dto.RecoveryCodesResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "404": {
-                        "description": "Department not found",
+                    "400": {
+                        "description": "Invalid code",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/auth/2fa/setup": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update an existing department by ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Generate a TOTP secret and its otpauth:// URI for an authenticator app. The secret is enabled once confirmed with a code",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Departments"
-                ],
-                "summary": "Update a department",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Department ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Department data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateDepartmentRequest"
-                        }
-                    }
+                    "Two-Factor Authentication"
                 ],
+                "summary": "Start two-factor enrollment",
                 "responses": {
                     "200": {
-                        "description": "Department updated",
+                        "description": "Two-factor setup started",
                         "schema": {
                             "allOf": [
                                 {
@@ -1268,7 +1256,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.DepartmentResponse"
+                                            "$ref": "#/definitions/dto.TwoFactorSetupResponse"
                                         }
                                     }
                                 }
@@ -1276,45 +1264,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Delete a department by ID",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Departments"
-                ],
-                "summary": "Delete a department",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Department ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Department deleted",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "400": {
-                        "description": "Failed to delete",
+                        "description": "Two-factor authentication is already enabled",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1322,32 +1272,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/employee-salaries": {
-            "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
+        "/auth/2fa/verify": {
+            "post": {
+                "description": "Trade the challenge token from login and a TOTP or recovery code for an access token; sets the refresh token in cookie. A challenge token can only be presented once: after a wrong code, log in again",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Retrieve all employee salary records, optionally filtered by employee",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Employee Salaries"
+                    "Authentication"
                 ],
-                "summary": "Get all employee salaries",
+                "summary": "Complete a two-factor login",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Filter by employee ID",
-                        "name": "employee_id",
-                        "in": "query"
+                        "description": "Challenge token and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyTwoFactorRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Employee salaries retrieved",
+                        "description": "Login successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -1357,31 +1308,42 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dto.EmployeeSalaryResponse"
-                                            }
+                                            "$ref": "#/definitions/dto.TokenResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "500": {
-                        "description": "Failed to fetch employee salaries",
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid code or challenge",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed login attempts or account locked",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/change-password": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new salary record for an employee",
+                "description": "Change the current user's password after checking the current one. Other sessions are logged out and a new access token is returned. Users flagged to change their password can call no other route until they do",
                 "consumes": [
                     "application/json"
                 ],
@@ -1389,23 +1351,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Employee Salaries"
+                    "Authentication"
                 ],
-                "summary": "Create an employee salary",
+                "summary": "Change my password",
                 "parameters": [
                     {
-                        "description": "Salary data",
+                        "description": "Current and new password",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateEmployeeSalaryRequest"
+                            "$ref": "#/definitions/dto.ChangePasswordRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Employee salary created",
+                    "200": {
+                        "description": "Password changed",
                         "schema": {
                             "allOf": [
                                 {
@@ -1415,7 +1377,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.EmployeeSalaryResponse"
+                                            "$ref": "#/definitions/dto.TokenResponse"
                                         }
                                     }
                                 }
@@ -1431,33 +1393,73 @@ const docTemplate = `{
                 }
             }
         },
-        "/employee-salaries/employee/{employeeId}/latest": {
-            "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
+        "/auth/forgot-password": {
+            "post": {
+                "description": "Email a single-use password reset token to the account with this email; it is only sent when SMTP is configured. The response is the same whether or not the email is registered",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Retrieve the most recent salary record for a specific employee",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Employee Salaries"
+                    "Authentication"
                 ],
-                "summary": "Get latest salary for an employee",
+                "summary": "Request a password reset",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Employee ID",
-                        "name": "employeeId",
-                        "in": "path",
-                        "required": true
+                        "description": "Account email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ForgotPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Latest employee salary retrieved",
+                        "description": "Reset instructions sent",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate user with email and password, returns access token and sets refresh token in cookie. When two-factor authentication is enabled, returns a challenge token to complete at /auth/2fa/verify instead. Unknown emails and throttled or locked accounts get the same 401 as a wrong password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "User login",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -1467,15 +1469,27 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.EmployeeSalaryResponse"
+                                            "$ref": "#/definitions/dto.TokenResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "404": {
-                        "description": "Employee salary not found",
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed login attempts from this IP",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1483,33 +1497,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/employee-salaries/seed-from-position/{employeeId}": {
+        "/auth/logout": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Create an employee salary record using the position's base salary and standard Indonesian BPJS rates. Fails if a salary record already exists.",
+                "description": "Revoke the current session and clear the refresh token cookie",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Employee Salaries"
+                    "Authentication"
                 ],
-                "summary": "Seed salary from position base salary",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Employee ID",
-                        "name": "employeeId",
-                        "in": "path",
-                        "required": true
+                "summary": "User logout",
+                "responses": {
+                    "200": {
+                        "description": "Logged out successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revoke session",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
+                }
+            }
+        },
+        "/auth/oidc/providers": {
+            "get": {
+                "description": "List the active OIDC providers to offer on the login page, with the URL that starts a login at each",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Single Sign-On"
                 ],
+                "summary": "Get single sign-on providers",
                 "responses": {
-                    "201": {
-                        "description": "Employee salary seeded",
+                    "200": {
+                        "description": "SSO providers retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -1519,15 +1550,18 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.EmployeeSalaryResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.OIDCLoginOptionResponse"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Seed failed",
+                    "500": {
+                        "description": "Failed to fetch SSO providers",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1535,33 +1569,75 @@ const docTemplate = `{
                 }
             }
         },
-        "/employee-salaries/{id}": {
+        "/auth/oidc/{provider}/callback": {
             "get": {
+                "description": "The OIDC provider's redirect URI. Validates the login, opens a session like a password login and sets the refresh token in cookie, then redirects to the frontend. Users with two-factor authentication get no session: they are sent to the frontend login page with challenge_token and redirect in the URL fragment, to complete at /auth/2fa/verify. Links started at /auth/oidc/{provider}/link redirect without a new session. Failures redirect to the frontend login page with an sso_error query parameter",
+                "tags": [
+                    "Single Sign-On"
+                ],
+                "summary": "Complete a single sign-on login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider slug",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Login state",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirect to the frontend"
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/link": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve an employee salary record by its ID",
+                "description": "Start a login at the OIDC provider that links the identity it confirms to your account. Needed for users of several companies, whose first SSO login is not linked by email. Send the browser to the returned URL; after the callback it returns to the frontend path given in redirect",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Employee Salaries"
+                    "Single Sign-On"
                 ],
-                "summary": "Get employee salary by ID",
+                "summary": "Link an SSO provider to your account",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Salary ID",
-                        "name": "id",
+                        "description": "Provider slug",
+                        "name": "provider",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Frontend path to return to after linking",
+                        "name": "redirect",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Employee salary retrieved",
+                        "description": "SSO link started",
                         "schema": {
                             "allOf": [
                                 {
@@ -1571,59 +1647,64 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.EmployeeSalaryResponse"
+                                            "$ref": "#/definitions/dto.OIDCLinkResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "404": {
-                        "description": "Employee salary not found",
+                    "400": {
+                        "description": "Failed to start link",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Update an existing salary record by ID",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+            }
+        },
+        "/auth/oidc/{provider}/login": {
+            "get": {
+                "description": "Redirect the browser to the OIDC provider to log in with the authorization-code flow and PKCE. After the callback the browser returns to the frontend path given in redirect",
                 "tags": [
-                    "Employee Salaries"
+                    "Single Sign-On"
                 ],
-                "summary": "Update an employee salary",
+                "summary": "Start a single sign-on login",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Salary ID",
-                        "name": "id",
+                        "description": "Provider slug",
+                        "name": "provider",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Salary data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateEmployeeSalaryRequest"
-                        }
+                        "type": "string",
+                        "description": "Frontend path to return to after login",
+                        "name": "redirect",
+                        "in": "query"
                     }
                 ],
+                "responses": {
+                    "302": {
+                        "description": "Redirect to the identity provider"
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Generate new access token using refresh token from cookie. The refresh token is rotated; presenting an already used refresh token revokes its session",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Refresh access token",
                 "responses": {
                     "200": {
-                        "description": "Employee salary updated",
+                        "description": "Token refreshed",
                         "schema": {
                             "allOf": [
                                 {
@@ -1633,53 +1714,55 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.EmployeeSalaryResponse"
+                                            "$ref": "#/definitions/dto.TokenResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Invalid request",
+                    "401": {
+                        "description": "Unauthorized - refresh token not found or invalid",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
+            }
+        },
+        "/auth/reset-password": {
+            "post": {
+                "description": "Set a new password with a reset token. The token can be used once; all sessions of the account are logged out",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Delete an employee salary record by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Employee Salaries"
+                    "Authentication"
                 ],
-                "summary": "Delete an employee salary",
+                "summary": "Reset a forgotten password",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Salary ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Reset token and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResetPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Employee salary deleted",
+                        "description": "Password reset",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
                     "400": {
-                        "description": "Failed to delete",
+                        "description": "Invalid or expired reset token",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1687,32 +1770,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/employees": {
+        "/auth/sessions": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve all employees, optionally filtered by company",
+                "description": "Retrieve the active sessions (logins) of the current user with their device, IP and last use. The session of the calling token is flagged as current",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Employees"
-                ],
-                "summary": "Get all employees",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Filter by company ID",
-                        "name": "company_id",
-                        "in": "query"
-                    }
+                    "Authentication"
                 ],
+                "summary": "Get my sessions",
                 "responses": {
                     "200": {
-                        "description": "Employees retrieved",
+                        "description": "Sessions retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -1724,7 +1799,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/dto.EmployeeResponse"
+                                                "$ref": "#/definitions/dto.SessionResponse"
                                             }
                                         }
                                     }
@@ -1733,62 +1808,36 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to fetch employees",
+                        "description": "Failed to fetch sessions",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new employee record linked to a user account",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Log out every session of the current user except the calling one",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Employees"
-                ],
-                "summary": "Create an employee",
-                "parameters": [
-                    {
-                        "description": "Employee data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreateEmployeeRequest"
-                        }
-                    }
+                    "Authentication"
                 ],
+                "summary": "Revoke my other sessions",
                 "responses": {
-                    "201": {
-                        "description": "Employee created",
+                    "200": {
+                        "description": "Sessions revoked",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/response.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/dto.EmployeeResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "400": {
-                        "description": "Invalid request",
+                    "500": {
+                        "description": "Failed to revoke sessions",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1796,42 +1845,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/employees/me": {
-            "get": {
+        "/auth/sessions/{id}": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve the employee profile of the currently authenticated user",
+                "description": "Log out one of the current user's sessions. Its refresh token stops working immediately",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Employees"
+                    "Authentication"
+                ],
+                "summary": "Revoke one of my sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get my employee profile",
                 "responses": {
                     "200": {
-                        "description": "Employee retrieved",
+                        "description": "Session revoked",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/response.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/dto.EmployeeResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/response.Response"
                         }
                     },
                     "404": {
-                        "description": "Employee profile not found",
+                        "description": "Session not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1839,33 +1885,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/employees/{id}": {
+        "/auth/tokens": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve an employee by their ID",
+                "description": "Retrieve the current user's personal API tokens that are not revoked, with their last use",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Employees"
-                ],
-                "summary": "Get employee by ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Employee ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "API Tokens"
                 ],
+                "summary": "Get my API tokens",
                 "responses": {
                     "200": {
-                        "description": "Employee retrieved",
+                        "description": "API tokens retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -1875,28 +1912,31 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.EmployeeResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.APITokenResponse"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "404": {
-                        "description": "Employee not found",
+                    "500": {
+                        "description": "Failed to fetch API tokens",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update an existing employee record by ID",
+                "description": "Create a token for scripts, sent as \"Authorization: Bearer hris_...\". It acts for the current user within one company (the primary company when not given) and with at most the listed permissions, which must be the user's own. Tokens act through these permissions alone, never the user's role: superadmin-only routes refuse them. The token is shown only in this response",
                 "consumes": [
                     "application/json"
                 ],
@@ -1904,30 +1944,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Employees"
+                    "API Tokens"
                 ],
-                "summary": "Update an employee",
+                "summary": "Create a personal API token",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Employee ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Employee data",
+                        "description": "Token data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateEmployeeRequest"
+                            "$ref": "#/definitions/dto.CreateAPITokenRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Employee updated",
+                    "201": {
+                        "description": "API token created",
                         "schema": {
                             "allOf": [
                                 {
@@ -1937,7 +1970,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.EmployeeResponse"
+                                            "$ref": "#/definitions/dto.CreatedAPITokenResponse"
                                         }
                                     }
                                 }
@@ -1949,27 +1982,35 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "403": {
+                        "description": "API tokens cannot create API tokens",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/auth/tokens/{id}": {
             "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete an employee record by ID",
+                "description": "Revoke a personal API token. It stops working immediately",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Employees"
+                    "API Tokens"
                 ],
-                "summary": "Delete an employee",
+                "summary": "Revoke one of my API tokens",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Employee ID",
+                        "description": "Token ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1977,13 +2018,13 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Employee deleted",
+                        "description": "API token revoked",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "400": {
-                        "description": "Failed to delete",
+                    "404": {
+                        "description": "API token not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1991,38 +2032,56 @@ const docTemplate = `{
                 }
             }
         },
-        "/holidays": {
+        "/companies": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve all holidays, optionally filtered by company and/or year",
+                "description": "Retrieve companies with pagination, search, and sorting",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Holidays"
+                    "Companies"
                 ],
-                "summary": "Get all holidays",
+                "summary": "Get all companies",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Filter by company ID",
-                        "name": "company_id",
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Filter by year",
-                        "name": "year",
+                        "description": "Items per page (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by name, email, phone, or NPWP",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: name, email, phone, npwp, is_active, created_at",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort direction: asc or desc",
+                        "name": "sort_order",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Holidays retrieved",
+                        "description": "Companies retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -2032,24 +2091,15 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dto.HolidayResponse"
-                                            }
+                                            "$ref": "#/definitions/dto.PaginatedCompanyResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Invalid year format",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
                     "500": {
-                        "description": "Failed to fetch holidays",
+                        "description": "Failed to fetch companies",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -2062,7 +2112,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new holiday record",
+                "description": "Create a new company",
                 "consumes": [
                     "application/json"
                 ],
@@ -2070,23 +2120,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Holidays"
+                    "Companies"
                 ],
-                "summary": "Create a holiday",
+                "summary": "Create a company",
                 "parameters": [
                     {
-                        "description": "Holiday data",
+                        "description": "Company data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateHolidayRequest"
+                            "$ref": "#/definitions/dto.CreateCompanyRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Holiday created",
+                        "description": "Company created",
                         "schema": {
                             "allOf": [
                                 {
@@ -2096,7 +2146,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.HolidayResponse"
+                                            "$ref": "#/definitions/dto.CompanyResponse"
                                         }
                                     }
                                 }
@@ -2110,27 +2160,70 @@ const docTemplate = `{
                         }
                     }
                 }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete multiple companies by IDs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Companies"
+                ],
+                "summary": "Delete multiple companies",
+                "parameters": [
+                    {
+                        "description": "Company IDs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeleteMultipleCompaniesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Companies deleted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or failed to delete",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
             }
         },
-        "/holidays/{id}": {
+        "/companies/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve a holiday by its ID",
+                "description": "Retrieve a company by its ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Holidays"
+                    "Companies"
                 ],
-                "summary": "Get holiday by ID",
+                "summary": "Get company by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Holiday ID",
+                        "description": "Company ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2138,7 +2231,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Holiday retrieved",
+                        "description": "Company retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -2148,7 +2241,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.HolidayResponse"
+                                            "$ref": "#/definitions/dto.CompanyResponse"
                                         }
                                     }
                                 }
@@ -2156,7 +2249,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Holiday not found",
+                        "description": "Company not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -2169,7 +2262,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Update an existing holiday by ID",
+                "description": "Update an existing company by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -2177,30 +2270,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Holidays"
+                    "Companies"
                 ],
-                "summary": "Update a holiday",
+                "summary": "Update a company",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Holiday ID",
+                        "description": "Company ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Holiday data",
+                        "description": "Company data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateHolidayRequest"
+                            "$ref": "#/definitions/dto.UpdateCompanyRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Holiday updated",
+                        "description": "Company updated",
                         "schema": {
                             "allOf": [
                                 {
@@ -2210,7 +2303,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.HolidayResponse"
+                                            "$ref": "#/definitions/dto.CompanyResponse"
                                         }
                                     }
                                 }
@@ -2231,18 +2324,18 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a holiday by ID",
+                "description": "Delete a company by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Holidays"
+                    "Companies"
                 ],
-                "summary": "Delete a holiday",
+                "summary": "Delete a company",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Holiday ID",
+                        "description": "Company ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2250,7 +2343,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Holiday deleted",
+                        "description": "Company deleted",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -2264,38 +2357,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/leaves": {
+        "/companies/{id}/modules": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve all leave requests, optionally filtered by employee or status",
+                "description": "Superadmin view: every module + whether it is enabled for the given company.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Leaves"
+                    "Modules"
                 ],
-                "summary": "Get all leave requests",
+                "summary": "List modules with enabled state for a specific company",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by employee ID",
-                        "name": "employee_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by status (e.g. pending)",
-                        "name": "status",
-                        "in": "query"
+                        "description": "Company ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Leaves retrieved",
+                        "description": "Company modules fetched",
                         "schema": {
                             "allOf": [
                                 {
@@ -2307,7 +2395,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/dto.LeaveResponse"
+                                                "$ref": "#/definitions/dto.CompanyModuleResponse"
                                             }
                                         }
                                     }
@@ -2316,20 +2404,22 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to fetch leaves",
+                        "description": "Failed to fetch modules",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/companies/{id}/modules/{key}": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Submit a new leave request",
+                "description": "Superadmin-only. Core modules cannot be disabled. Dependencies are validated.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2337,23 +2427,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Leaves"
+                    "Modules"
                 ],
-                "summary": "Create a leave request",
+                "summary": "Enable or disable a module for a company",
                 "parameters": [
                     {
-                        "description": "Leave request data",
+                        "type": "string",
+                        "description": "Company ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Module key (e.g. visit_tracking)",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Enable/disable + optional JSON config",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateLeaveRequest"
+                            "$ref": "#/definitions/dto.SetCompanyModuleRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Leave request created",
+                    "200": {
+                        "description": "Module updated",
                         "schema": {
                             "allOf": [
                                 {
@@ -2363,7 +2467,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.LeaveResponse"
+                                            "$ref": "#/definitions/dto.CompanyModuleResponse"
                                         }
                                     }
                                 }
@@ -2371,7 +2475,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Invalid request or dependency violation",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -2379,33 +2483,64 @@ const docTemplate = `{
                 }
             }
         },
-        "/leaves/{id}": {
+        "/dead-letters": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve a leave request by its ID",
+                "description": "Retrieve the events the notification consumer gave up on after retrying them, newest first, with optional filters and pagination. Events outside any company are visible to superadmins only",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Leaves"
+                    "Dead Letters"
                 ],
-                "summary": "Get leave request by ID",
+                "summary": "Get dead-lettered events",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Leave ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by status (pending, replayed)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by event type, e.g. leave.submitted",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter start date of the failure (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter end date of the failure (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Leave retrieved",
+                        "description": "Dead letters retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -2415,28 +2550,30 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.LeaveResponse"
+                                            "$ref": "#/definitions/dto.PaginatedDeadLetterResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "404": {
-                        "description": "Leave not found",
+                    "500": {
+                        "description": "Failed to fetch dead letters",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/dead-letters/replay": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update an existing leave request by ID",
+                "description": "Publish pending dead-lettered events again, selected by ID, by event type or both, oldest first and at most 500 per request",
                 "consumes": [
                     "application/json"
                 ],
@@ -2444,30 +2581,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Leaves"
+                    "Dead Letters"
                 ],
-                "summary": "Update a leave request",
+                "summary": "Replay dead-lettered events",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Leave ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Leave request data",
+                        "description": "Dead letters to replay",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateLeaveRequest"
+                            "$ref": "#/definitions/dto.ReplayDeadLettersRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Leave request updated",
+                        "description": "Dead letters replayed",
                         "schema": {
                             "allOf": [
                                 {
@@ -2477,7 +2607,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.LeaveResponse"
+                                            "$ref": "#/definitions/dto.ReplayDeadLettersResponse"
                                         }
                                     }
                                 }
@@ -2491,25 +2621,27 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/dead-letters/{id}": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a leave request by ID",
+                "description": "Retrieve a dead-lettered event with the original message, its key and headers, and the error it failed with",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Leaves"
+                    "Dead Letters"
                 ],
-                "summary": "Delete a leave request",
+                "summary": "Get a dead-lettered event",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Leave ID",
+                        "description": "Dead letter ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2517,13 +2649,25 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Leave request deleted",
+                        "description": "Dead letter retrieved",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.DeadLetterDetailResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "400": {
-                        "description": "Failed to delete",
+                    "404": {
+                        "description": "Dead letter not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -2531,63 +2675,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/leaves/{id}/approve": {
-            "put": {
+        "/dead-letters/{id}/replay": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Approve or reject a pending leave request (Admin/HR only)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Publish a pending dead-lettered event again to its original topic, once the cause of the failure is fixed. It keeps its event ID, so users already notified are not notified twice",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Leaves"
+                    "Dead Letters"
                 ],
-                "summary": "Approve or reject a leave request",
+                "summary": "Replay a dead-lettered event",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Leave ID",
+                        "description": "Dead letter ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Approval data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.ApproveLeaveRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Leave request updated",
+                        "description": "Dead letter replayed",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/response.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/dto.LeaveResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/response.Response"
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Dead letter not found or already replayed",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -2595,24 +2715,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/me/modules": {
+        "/departments": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Used by the frontend to filter the sidebar. Superadmins receive all modules.",
+                "description": "Retrieve all departments, optionally filtered by company",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Modules"
+                    "Departments"
+                ],
+                "summary": "Get all departments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by company ID",
+                        "name": "company_id",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get the enabled module keys for the current caller",
                 "responses": {
                     "200": {
-                        "description": "Modules fetched",
+                        "description": "Departments retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -2622,40 +2750,55 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.MyModulesResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.DepartmentResponse"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "404": {
-                        "description": "Employee or company not found for user",
+                    "500": {
+                        "description": "Failed to fetch departments",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            }
-        },
-        "/menu-access": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve all user menu access mappings (Admin only)",
+                "description": "Create a new department",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Menu Access"
+                    "Departments"
+                ],
+                "summary": "Create a department",
+                "parameters": [
+                    {
+                        "description": "Department data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateDepartmentRequest"
+                        }
+                    }
                 ],
-                "summary": "Get all menu access mappings",
                 "responses": {
-                    "200": {
-                        "description": "All menu access retrieved",
+                    "201": {
+                        "description": "Department created",
                         "schema": {
                             "allOf": [
                                 {
@@ -2665,86 +2808,111 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dto.MenuAccessResponse"
-                                            }
+                                            "$ref": "#/definitions/dto.DepartmentResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/departments/{id}": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Set the menu keys accessible to a specific user (Admin only)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieve a department by its ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Menu Access"
+                    "Departments"
                 ],
-                "summary": "Set user menu access",
+                "summary": "Get department by ID",
                 "parameters": [
                     {
-                        "description": "Menu access data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.SetMenuAccessRequest"
-                        }
+                        "type": "string",
+                        "description": "Department ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Menu access updated",
+                        "description": "Department retrieved",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.DepartmentResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "400": {
-                        "description": "Invalid request",
+                    "404": {
+                        "description": "Department not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            }
-        },
-        "/menu-access/me": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve the list of menu keys accessible to the currently authenticated user",
+                "description": "Update an existing department by ID",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Menu Access"
+                    "Departments"
+                ],
+                "summary": "Update a department",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Department ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Department data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateDepartmentRequest"
+                        }
+                    }
                 ],
-                "summary": "Get my menu access",
                 "responses": {
                     "200": {
-                        "description": "Menu access retrieved",
+                        "description": "Department updated",
                         "schema": {
                             "allOf": [
                                 {
@@ -2754,67 +2922,53 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.UserMenuKeysResponse"
+                                            "$ref": "#/definitions/dto.DepartmentResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            }
-        },
-        "/menu-access/{user_id}": {
+            },
             "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete custom menu access for a user, reverting to role defaults (Admin only)",
+                "description": "Delete a department by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Menu Access"
+                    "Departments"
                 ],
-                "summary": "Reset user menu access",
+                "summary": "Delete a department",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "description": "Department ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Menu access reset to defaults",
+                        "description": "Department deleted",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
                     "400": {
-                        "description": "user_id is required",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                        "description": "Failed to delete",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -2822,24 +2976,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/modules": {
+        "/email-deliveries": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Returns every feature module known to the system (core + opt-in). Authenticated.",
+                "description": "Retrieve the delivery log of notification emails, newest first, with optional filters and pagination. Pending emails are retried with backoff until they are sent or marked failed. Emails outside any company are visible to superadmins only",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Modules"
+                    "Email Deliveries"
+                ],
+                "summary": "Get email deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status (pending, sent, failed)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by event type, e.g. leave.submitted",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by recipient user ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "List the feature module catalog",
                 "responses": {
                     "200": {
-                        "description": "Modules fetched",
+                        "description": "Email deliveries retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -2849,10 +3037,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dto.ModuleResponse"
-                                            }
+                                            "$ref": "#/definitions/dto.PaginatedEmailDeliveryResponse"
                                         }
                                     }
                                 }
@@ -2860,7 +3045,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to fetch modules",
+                        "description": "Failed to fetch email deliveries",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -2868,32 +3053,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/notifications": {
+        "/email-deliveries/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Returns the latest notifications for the authenticated user",
+                "description": "Retrieve a notification email with its text and HTML bodies and the error of its last failed attempt",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Notifications"
+                    "Email Deliveries"
                 ],
-                "summary": "Get notifications for the current user",
+                "summary": "Get an email delivery",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Max number to return (default 20)",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Email delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Email delivery retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -2903,61 +3089,106 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dto.NotificationResponse"
-                                            }
+                                            "$ref": "#/definitions/dto.EmailDeliveryDetailResponse"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "404": {
+                        "description": "Email delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
-        "/notifications/read-all": {
-            "put": {
+        "/employee-salaries": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
+                "description": "Retrieve all employee salary records, optionally filtered by employee",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Notifications"
+                    "Employee Salaries"
+                ],
+                "summary": "Get all employee salaries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by employee ID",
+                        "name": "employee_id",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Mark all notifications as read",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Employee salaries retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.EmployeeSalaryResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch employee salaries",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            }
-        },
-        "/notifications/unread-count": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Returns the number of unread notifications for the authenticated user",
+                "description": "Create a new salary record for an employee",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Notifications"
+                    "Employee Salaries"
+                ],
+                "summary": "Create an employee salary",
+                "parameters": [
+                    {
+                        "description": "Salary data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateEmployeeSalaryRequest"
+                        }
+                    }
                 ],
-                "summary": "Get unread notification count",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Employee salary created",
                         "schema": {
                             "allOf": [
                                 {
@@ -2967,42 +3198,67 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.UnreadCountResponse"
+                                            "$ref": "#/definitions/dto.EmployeeSalaryResponse"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
-        "/notifications/{id}/read": {
-            "put": {
+        "/employee-salaries/employee/{employeeId}/latest": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
+                "description": "Retrieve the most recent salary record for a specific employee",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Notifications"
+                    "Employee Salaries"
                 ],
-                "summary": "Mark a notification as read",
+                "summary": "Get latest salary for an employee",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Notification ID",
-                        "name": "id",
+                        "description": "Employee ID",
+                        "name": "employeeId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Latest employee salary retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.EmployeeSalaryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Employee salary not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -3010,33 +3266,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/organization/structure": {
-            "get": {
+        "/employee-salaries/seed-from-position/{employeeId}": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve the hierarchical organization structure for a company (departments → positions → employees)",
+                "description": "Create an employee salary record using the position's base salary and standard Indonesian BPJS rates. Fails if a salary record already exists.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organization"
+                    "Employee Salaries"
                 ],
-                "summary": "Get organization structure",
+                "summary": "Seed salary from position base salary",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Company ID",
-                        "name": "company_id",
-                        "in": "query",
+                        "description": "Employee ID",
+                        "name": "employeeId",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Organization structure retrieved",
+                    "201": {
+                        "description": "Employee salary seeded",
                         "schema": {
                             "allOf": [
                                 {
@@ -3046,7 +3302,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.OrgStructureResponse"
+                                            "$ref": "#/definitions/dto.EmployeeSalaryResponse"
                                         }
                                     }
                                 }
@@ -3054,13 +3310,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "company_id is required",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Company not found",
+                        "description": "Seed failed",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -3068,44 +3318,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/payrolls": {
+        "/employee-salaries/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve all payroll records, optionally filtered by employee, month, and year",
+                "description": "Retrieve an employee salary record by its ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Payrolls"
+                    "Employee Salaries"
                 ],
-                "summary": "Get all payrolls",
+                "summary": "Get employee salary by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by employee ID",
-                        "name": "employee_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter by month (1-12)",
-                        "name": "month",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter by year",
-                        "name": "year",
-                        "in": "query"
+                        "description": "Salary ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Payrolls retrieved",
+                        "description": "Employee salary retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -3115,39 +3354,28 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dto.PayrollResponse"
-                                            }
+                                            "$ref": "#/definitions/dto.EmployeeSalaryResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Invalid month or year format",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Failed to fetch payrolls",
+                    "404": {
+                        "description": "Employee salary not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            }
-        },
-        "/payrolls/generate": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Generate a payroll record for an employee for a specific month and year",
+                "description": "Update an existing salary record by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -3155,23 +3383,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Payrolls"
+                    "Employee Salaries"
                 ],
-                "summary": "Generate payroll",
+                "summary": "Update an employee salary",
                 "parameters": [
                     {
-                        "description": "Payroll generation data",
+                        "type": "string",
+                        "description": "Salary ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Salary data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.GeneratePayrollRequest"
+                            "$ref": "#/definitions/dto.UpdateEmployeeSalaryRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Payroll generated",
+                    "200": {
+                        "description": "Employee salary updated",
                         "schema": {
                             "allOf": [
                                 {
@@ -3181,7 +3416,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.PayrollResponse"
+                                            "$ref": "#/definitions/dto.EmployeeSalaryResponse"
                                         }
                                     }
                                 }
@@ -3195,53 +3430,39 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/payrolls/me": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve paid payroll records for the authenticated user",
+                "description": "Delete an employee salary record by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Payrolls"
+                    "Employee Salaries"
+                ],
+                "summary": "Delete an employee salary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Salary ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get my payslips",
                 "responses": {
                     "200": {
-                        "description": "Payslips retrieved",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/response.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dto.PayrollResponse"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "404": {
-                        "description": "Employee profile not found",
+                        "description": "Employee salary deleted",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "500": {
-                        "description": "Failed to fetch payslips",
+                    "400": {
+                        "description": "Failed to delete",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -3249,33 +3470,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/payrolls/{id}": {
+        "/employees": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve a payroll record by its ID",
+                "description": "Retrieve all employees, optionally filtered by company",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Payrolls"
+                    "Employees"
                 ],
-                "summary": "Get payroll by ID",
+                "summary": "Get all employees",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Payroll ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by company ID",
+                        "name": "company_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Payroll retrieved",
+                        "description": "Employees retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -3285,28 +3505,31 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.PayrollResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.EmployeeResponse"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "404": {
-                        "description": "Payroll not found",
+                    "500": {
+                        "description": "Failed to fetch employees",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update an existing payroll record by ID",
+                "description": "Create a new employee record linked to a user account",
                 "consumes": [
                     "application/json"
                 ],
@@ -3314,30 +3537,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Payrolls"
+                    "Employees"
                 ],
-                "summary": "Update a payroll",
+                "summary": "Create an employee",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Payroll ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Payroll data",
+                        "description": "Employee data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdatePayrollRequest"
+                            "$ref": "#/definitions/dto.CreateEmployeeRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Payroll updated",
+                    "201": {
+                        "description": "Employee created",
                         "schema": {
                             "allOf": [
                                 {
@@ -3347,7 +3563,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.PayrollResponse"
+                                            "$ref": "#/definitions/dto.EmployeeResponse"
                                         }
                                     }
                                 }
@@ -3361,39 +3577,44 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/employees/me": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a payroll record by ID",
+                "description": "Retrieve the employee profile of the currently authenticated user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Payrolls"
-                ],
-                "summary": "Delete a payroll",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Payroll ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Employees"
                 ],
+                "summary": "Get my employee profile",
                 "responses": {
                     "200": {
-                        "description": "Payroll deleted",
+                        "description": "Employee retrieved",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "400": {
-                        "description": "Failed to delete",
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.EmployeeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Employee profile not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -3401,14 +3622,64 @@ const docTemplate = `{
                 }
             }
         },
-        "/payrolls/{id}/status": {
+        "/employees/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve an employee by their ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Employees"
+                ],
+                "summary": "Get employee by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Employee retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.EmployeeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Employee not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
             "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update the status of a payroll record (draft, processed, paid)",
+                "description": "Update an existing employee record by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -3416,30 +3687,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Payrolls"
+                    "Employees"
                 ],
-                "summary": "Update payroll status",
+                "summary": "Update an employee",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Payroll ID",
+                        "description": "Employee ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Status data",
+                        "description": "Employee data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.PayrollStatusRequest"
+                            "$ref": "#/definitions/dto.UpdateEmployeeRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Payroll status updated",
+                        "description": "Employee updated",
                         "schema": {
                             "allOf": [
                                 {
@@ -3449,7 +3720,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.PayrollResponse"
+                                            "$ref": "#/definitions/dto.EmployeeResponse"
                                         }
                                     }
                                 }
@@ -3457,7 +3728,45 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request or status",
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete an employee record by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Employees"
+                ],
+                "summary": "Delete an employee",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Employee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Employee deleted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Failed to delete",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -3465,32 +3774,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/positions": {
+        "/holidays": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve all positions, optionally filtered by company",
+                "description": "Retrieve all holidays, optionally filtered by company and/or year",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Positions"
+                    "Holidays"
                 ],
-                "summary": "Get all positions",
+                "summary": "Get all holidays",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Filter by company ID",
                         "name": "company_id",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by year",
+                        "name": "year",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Positions retrieved",
+                        "description": "Holidays retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -3502,7 +3817,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/dto.PositionResponse"
+                                                "$ref": "#/definitions/dto.HolidayResponse"
                                             }
                                         }
                                     }
@@ -3510,8 +3825,14 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Invalid year format",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
-                        "description": "Failed to fetch positions",
+                        "description": "Failed to fetch holidays",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -3524,7 +3845,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new position",
+                "description": "Create a new holiday record",
                 "consumes": [
                     "application/json"
                 ],
@@ -3532,23 +3853,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Positions"
+                    "Holidays"
                 ],
-                "summary": "Create a position",
+                "summary": "Create a holiday",
                 "parameters": [
                     {
-                        "description": "Position data",
+                        "description": "Holiday data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreatePositionRequest"
+                            "$ref": "#/definitions/dto.CreateHolidayRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Position created",
+                        "description": "Holiday created",
                         "schema": {
                             "allOf": [
                                 {
@@ -3558,7 +3879,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.PositionResponse"
+                                            "$ref": "#/definitions/dto.HolidayResponse"
                                         }
                                     }
                                 }
@@ -3574,25 +3895,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/positions/{id}": {
+        "/holidays/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve a position by its ID",
+                "description": "Retrieve a holiday by its ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Positions"
+                    "Holidays"
                 ],
-                "summary": "Get position by ID",
+                "summary": "Get holiday by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Position ID",
+                        "description": "Holiday ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3600,7 +3921,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Position retrieved",
+                        "description": "Holiday retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -3610,7 +3931,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.PositionResponse"
+                                            "$ref": "#/definitions/dto.HolidayResponse"
                                         }
                                     }
                                 }
@@ -3618,7 +3939,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Position not found",
+                        "description": "Holiday not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -3631,7 +3952,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Update an existing position by ID",
+                "description": "Update an existing holiday by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -3639,30 +3960,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Positions"
+                    "Holidays"
                 ],
-                "summary": "Update a position",
+                "summary": "Update a holiday",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Position ID",
+                        "description": "Holiday ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Position data",
+                        "description": "Holiday data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdatePositionRequest"
+                            "$ref": "#/definitions/dto.UpdateHolidayRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Position updated",
+                        "description": "Holiday updated",
                         "schema": {
                             "allOf": [
                                 {
@@ -3672,7 +3993,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.PositionResponse"
+                                            "$ref": "#/definitions/dto.HolidayResponse"
                                         }
                                     }
                                 }
@@ -3693,18 +4014,18 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a position by ID",
+                "description": "Delete a holiday by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Positions"
+                    "Holidays"
                 ],
-                "summary": "Delete a position",
+                "summary": "Delete a holiday",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Position ID",
+                        "description": "Holiday ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3712,7 +4033,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Position deleted",
+                        "description": "Holiday deleted",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -3726,32 +4047,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/shifts": {
-            "get": {
+        "/leave-balances/accrue": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve all shifts, optionally filtered by company",
+                "description": "Post the leave accruals due for every active employee of a company up to the given month, and expire carried-over days past their expiry date. Safe to run repeatedly.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Shifts"
+                    "Leave Balances"
                 ],
-                "summary": "Get all shifts",
+                "summary": "Run leave accrual",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Filter by company ID",
-                        "name": "company_id",
-                        "in": "query"
+                        "description": "Accrual data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LeaveAccrualRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Shifts retrieved",
+                        "description": "Leave accrual completed",
                         "schema": {
                             "allOf": [
                                 {
@@ -3761,31 +4088,30 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dto.ShiftResponse"
-                                            }
+                                            "$ref": "#/definitions/dto.LeaveBalanceJobResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "500": {
-                        "description": "Failed to fetch shifts",
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/leave-balances/adjustments": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new work shift",
+                "description": "Post a manual adjustment to an employee's leave balance. Positive days add to the balance, negative days deduct.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3793,23 +4119,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Shifts"
+                    "Leave Balances"
                 ],
-                "summary": "Create a shift",
+                "summary": "Adjust a leave balance",
                 "parameters": [
                     {
-                        "description": "Shift data",
+                        "description": "Adjustment data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateShiftRequest"
+                            "$ref": "#/definitions/dto.LeaveAdjustmentRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Shift created",
+                        "description": "Leave balance adjusted",
                         "schema": {
                             "allOf": [
                                 {
@@ -3819,7 +4145,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.ShiftResponse"
+                                            "$ref": "#/definitions/dto.LeaveLedgerEntryResponse"
                                         }
                                     }
                                 }
@@ -3835,33 +4161,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/shifts/{id}": {
-            "get": {
+        "/leave-balances/close-year": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve a shift by its ID",
+                "description": "Carry each employee's remaining balance into the next year up to the policy limit and expire the rest. Each year is only closed once per employee and leave type.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Shifts"
+                    "Leave Balances"
                 ],
-                "summary": "Get shift by ID",
+                "summary": "Close a leave year",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Shift ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Year close data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LeaveYearCloseRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Shift retrieved",
+                        "description": "Leave year closed",
                         "schema": {
                             "allOf": [
                                 {
@@ -3871,59 +4202,55 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.ShiftResponse"
+                                            "$ref": "#/definitions/dto.LeaveBalanceJobResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "404": {
-                        "description": "Shift not found",
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/leave-balances/entitlements": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update an existing shift by ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieve the per-year entitlement overrides of an employee",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Shifts"
+                    "Leave Balances"
                 ],
-                "summary": "Update a shift",
+                "summary": "Get leave entitlements",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Shift ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Employee ID",
+                        "name": "employee_id",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Shift data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateShiftRequest"
-                        }
+                        "type": "integer",
+                        "description": "Year (defaults to the current year)",
+                        "name": "year",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Shift updated",
+                        "description": "Leave entitlements retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -3933,7 +4260,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.ShiftResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.LeaveEntitlementResponse"
+                                            }
                                         }
                                     }
                                 }
@@ -3948,38 +4278,55 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a shift by ID",
+                "description": "Create or replace an employee's entitlement for a leave type and year, overriding the policy's annual days",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Shifts"
+                    "Leave Balances"
                 ],
-                "summary": "Delete a shift",
+                "summary": "Set a leave entitlement",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Shift ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Entitlement data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetLeaveEntitlementRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Shift deleted",
+                        "description": "Leave entitlement saved",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.LeaveEntitlementResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Failed to delete",
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -3987,24 +4334,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/users": {
+        "/leave-delegations": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve all users (admin and hr only)",
+                "description": "Retrieve approval delegations. Users see the delegations they gave or received; admin and HR see all, or one user's with user_id.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Leave Delegations"
+                ],
+                "summary": "Get leave delegations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by delegator or delegate user ID (admin/HR only)",
+                        "name": "user_id",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all users",
                 "responses": {
                     "200": {
-                        "description": "Users retrieved",
+                        "description": "Leave delegations retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -4016,7 +4371,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/dto.UserResponse"
+                                                "$ref": "#/definitions/dto.LeaveDelegationResponse"
                                             }
                                         }
                                     }
@@ -4025,7 +4380,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to fetch users",
+                        "description": "Failed to fetch leave delegations",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -4038,7 +4393,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new user (admin only)",
+                "description": "Let another user decide your leave approval steps for a period, e.g. while you are on leave. Admin and HR may set delegator_id to delegate on behalf of another user.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4046,23 +4401,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Leave Delegations"
                 ],
-                "summary": "Create new user",
+                "summary": "Delegate leave approvals",
                 "parameters": [
                     {
-                        "description": "User details",
+                        "description": "Delegation data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateUserRequest"
+                            "$ref": "#/definitions/dto.CreateLeaveDelegationRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "User created",
+                        "description": "Leave delegation created",
                         "schema": {
                             "allOf": [
                                 {
@@ -4072,7 +4427,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.UserResponse"
+                                            "$ref": "#/definitions/dto.LeaveDelegationResponse"
                                         }
                                     }
                                 }
@@ -4080,7 +4435,13 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or validation error",
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Cannot delegate for another user",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -4088,42 +4449,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/me": {
-            "get": {
+        "/leave-delegations/{id}": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve currently authenticated user's information",
+                "description": "Remove an approval delegation. Users may remove their own delegations; admin and HR may remove any.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Leave Delegations"
+                ],
+                "summary": "Delete a leave delegation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Leave delegation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get current user",
                 "responses": {
                     "200": {
-                        "description": "User retrieved",
+                        "description": "Leave delegation deleted",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/response.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/dto.UserResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "400": {
+                        "description": "Failed to delete",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -4131,33 +4489,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
+        "/leave-policies": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve user by ID (admin, hr, or self)",
+                "description": "Retrieve all leave policies, optionally filtered by company",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Leave Policies"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Get all leave policies",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by company ID",
+                        "name": "company_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User retrieved",
+                        "description": "Leave policies retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -4167,34 +4524,31 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.UserResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.LeavePolicyResponse"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "User not found",
+                    "500": {
+                        "description": "Failed to fetch leave policies",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update user information (admin only)",
+                "description": "Define how a company grants a leave type: yearly days, accrual method, carry-over limit and expiry",
                 "consumes": [
                     "application/json"
                 ],
@@ -4202,30 +4556,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Leave Policies"
                 ],
-                "summary": "Update user",
+                "summary": "Create a leave policy",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "User details to update",
+                        "description": "Leave policy data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateUserRequest"
+                            "$ref": "#/definitions/dto.CreateLeavePolicyRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "User updated",
+                    "201": {
+                        "description": "Leave policy created",
                         "schema": {
                             "allOf": [
                                 {
@@ -4235,7 +4582,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.UserResponse"
+                                            "$ref": "#/definitions/dto.LeavePolicyResponse"
                                         }
                                     }
                                 }
@@ -4243,31 +4590,33 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or validation error",
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/leave-policies/{id}": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a user by ID (admin only, cannot delete self)",
+                "description": "Retrieve a leave policy by its ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Leave Policies"
                 ],
-                "summary": "Delete user",
+                "summary": "Get leave policy by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Leave policy ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -4275,27 +4624,38 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "User deleted",
+                        "description": "Leave policy retrieved",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.LeavePolicyResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "400": {
-                        "description": "Cannot delete your own account or user not found",
+                    "404": {
+                        "description": "Leave policy not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            }
-        },
-        "/visit-plans": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
+                "description": "Update an existing leave policy by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -4303,23 +4663,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "VisitPlans"
+                    "Leave Policies"
                 ],
-                "summary": "Create a visit plan for an employee on a specific date",
+                "summary": "Update a leave policy",
                 "parameters": [
                     {
-                        "description": "Plan data",
+                        "type": "string",
+                        "description": "Leave policy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Leave policy data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateVisitPlanRequest"
+                            "$ref": "#/definitions/dto.UpdateLeavePolicyRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Leave policy updated",
                         "schema": {
                             "allOf": [
                                 {
@@ -4329,74 +4696,53 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.VisitPlanResponse"
+                                            "$ref": "#/definitions/dto.LeavePolicyResponse"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
-            }
-        },
-        "/visit-plans/by-date": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Pass employee_id=me to look up the caller's plan.",
+                "description": "Delete a leave policy by ID. The leave type stops being balance-tracked.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "VisitPlans"
+                    "Leave Policies"
                 ],
-                "summary": "Fetch the plan for an employee on a given date",
+                "summary": "Delete a leave policy",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Employee ID or 'me'",
-                        "name": "employee_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Plan date (YYYY-MM-DD)",
-                        "name": "date",
-                        "in": "query",
+                        "description": "Leave policy ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Plan fetched",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/response.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/dto.VisitPlanResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid date format",
+                        "description": "Leave policy deleted",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
                     "404": {
-                        "description": "Plan not found",
+                        "description": "Leave policy not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -4404,45 +4750,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/visit-plans/employee/{employeeId}": {
+        "/leave-workflows": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Optionally filter by date range. Use employeeId=me for self-service.",
+                "description": "Retrieve all leave approval workflows, optionally filtered by company",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "VisitPlans"
+                    "Leave Workflows"
                 ],
-                "summary": "List plans for an employee",
+                "summary": "Get all leave workflows",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Employee ID or 'me'",
-                        "name": "employeeId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Start date (YYYY-MM-DD)",
-                        "name": "from",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "End date (YYYY-MM-DD)",
-                        "name": "to",
+                        "description": "Filter by company ID",
+                        "name": "company_id",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Plans fetched",
+                        "description": "Leave workflows retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -4454,24 +4787,29 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/dto.VisitPlanResponse"
+                                                "$ref": "#/definitions/dto.LeaveWorkflowResponse"
                                             }
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "500": {
+                        "description": "Failed to fetch leave workflows",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
-            }
-        },
-        "/visit-plans/items/{itemId}": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
+                "description": "Define the ordered approval steps for leave requests of a company, or of one department when department_id is set. Step approver types are supervisor, department_head, role (hr or admin) and user.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4479,30 +4817,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "VisitPlans"
+                    "Leave Workflows"
                 ],
-                "summary": "Update a plan item (mark visited/skipped, edit fields)",
+                "summary": "Create a leave workflow",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Plan item ID",
-                        "name": "itemId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Fields to update",
+                        "description": "Leave workflow data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateVisitPlanItemRequest"
+                            "$ref": "#/definitions/dto.CreateLeaveWorkflowRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Item updated",
+                    "201": {
+                        "description": "Leave workflow created",
                         "schema": {
                             "allOf": [
                                 {
@@ -4512,7 +4843,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.VisitPlanItemResponse"
+                                            "$ref": "#/definitions/dto.LeaveWorkflowResponse"
                                         }
                                     }
                                 }
@@ -4526,79 +4857,35 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "VisitPlans"
-                ],
-                "summary": "Delete a plan item",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Plan item ID",
-                        "name": "itemId",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Item deleted",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    }
-                }
             }
         },
-        "/visit-plans/report": {
+        "/leave-workflows/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Planned-vs-actual per employee for one date. The under_minimum flag is soft (default minimum 5).",
+                "description": "Retrieve a leave approval workflow and its steps by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "VisitPlans"
+                    "Leave Workflows"
                 ],
-                "summary": "Daily visit adherence report",
+                "summary": "Get leave workflow by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Company ID",
-                        "name": "company_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Report date (YYYY-MM-DD)",
-                        "name": "date",
-                        "in": "query",
+                        "description": "Leave workflow ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Override the 5/day threshold",
-                        "name": "minimum",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Report generated",
+                        "description": "Leave workflow retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -4608,48 +4895,59 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.VisitAdherenceReport"
+                                            "$ref": "#/definitions/dto.LeaveWorkflowResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Invalid parameters",
+                    "404": {
+                        "description": "Leave workflow not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            }
-        },
-        "/visit-plans/{id}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
+                "description": "Update a leave approval workflow. When steps are given they replace the existing steps; leave requests already submitted keep their chain.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "VisitPlans"
+                    "Leave Workflows"
                 ],
-                "summary": "Get a visit plan by ID",
+                "summary": "Update a leave workflow",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Plan ID",
+                        "description": "Leave workflow ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Leave workflow data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateLeaveWorkflowRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Plan fetched",
+                        "description": "Leave workflow updated",
                         "schema": {
                             "allOf": [
                                 {
@@ -4659,122 +4957,126 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.VisitPlanResponse"
+                                            "$ref": "#/definitions/dto.LeaveWorkflowResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "404": {
-                        "description": "Plan not found",
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Delete a leave approval workflow by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "VisitPlans"
+                    "Leave Workflows"
                 ],
-                "summary": "Update a visit plan (status / notes)",
+                "summary": "Delete a leave workflow",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Plan ID",
+                        "description": "Leave workflow ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Fields to update",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateVisitPlanRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Plan updated",
+                        "description": "Leave workflow deleted",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/response.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/dto.VisitPlanResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/response.Response"
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Failed to delete",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/leaves": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Soft-deletes the plan and all its items. Admin only.",
+                "description": "Retrieve all leave requests, optionally filtered by employee or status",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "VisitPlans"
+                    "Leaves"
                 ],
-                "summary": "Delete a visit plan",
+                "summary": "Get all leave requests",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Plan ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by employee ID",
+                        "name": "employee_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status (e.g. pending)",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Plan deleted",
+                        "description": "Leaves retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.LeaveResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch leaves",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            }
-        },
-        "/visit-plans/{id}/items": {
+            },
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
+                "description": "Submit a new leave request",
                 "consumes": [
                     "application/json"
                 ],
@@ -4782,30 +5084,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "VisitPlans"
+                    "Leaves"
                 ],
-                "summary": "Add a stop to a visit plan",
+                "summary": "Create a leave request",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Plan ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Plan item data",
+                        "description": "Leave request data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.AddVisitPlanItemRequest"
+                            "$ref": "#/definitions/dto.CreateLeaveRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Item added",
+                        "description": "Leave request created",
                         "schema": {
                             "allOf": [
                                 {
@@ -4815,7 +5110,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.VisitPlanItemResponse"
+                                            "$ref": "#/definitions/dto.LeaveResponse"
                                         }
                                     }
                                 }
@@ -4831,64 +5126,84 @@ const docTemplate = `{
                 }
             }
         },
-        "/visits": {
+        "/leaves/approvals": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Admin/HR listing. Employees should use /visits/attendance/{attendanceId}.",
+                "description": "Retrieve the leave requests whose current approval step the current user can decide, directly or as a delegate",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Visits"
+                    "Leaves"
                 ],
-                "summary": "List visits with filters",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Filter by employee ID",
-                        "name": "employee_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by company ID",
-                        "name": "company_id",
-                        "in": "query"
+                "summary": "Get leave requests awaiting my approval",
+                "responses": {
+                    "200": {
+                        "description": "Leaves retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.LeaveResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
+                    "500": {
+                        "description": "Failed to fetch leaves",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/leaves/balance": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Filter start date (YYYY-MM-DD)",
-                        "name": "from",
-                        "in": "query"
-                    },
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve leave balances per leave type for a year. Employees see their own balance; admin and HR may pass employee_id.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Leave Balances"
+                ],
+                "summary": "Get leave balances",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter end date (YYYY-MM-DD, inclusive)",
-                        "name": "to",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
+                        "description": "Employee ID (admin/HR only, defaults to the current user)",
+                        "name": "employee_id",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "default": 20,
-                        "description": "Items per page",
-                        "name": "limit",
+                        "description": "Year (defaults to the current year)",
+                        "name": "year",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Visits fetched",
+                        "description": "Leave balances retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -4898,7 +5213,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.PaginatedVisitResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.LeaveBalanceResponse"
+                                            }
                                         }
                                     }
                                 }
@@ -4906,7 +5224,13 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid date filter",
+                        "description": "Invalid year format",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Employee profile not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -4914,33 +5238,44 @@ const docTemplate = `{
                 }
             }
         },
-        "/visits/attendance/{attendanceId}": {
+        "/leaves/balance/ledger": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Timeline of visits nested inside one clock-in session, ordered by arrival.",
+                "description": "Retrieve the leave balance ledger entries for a year. Employees see their own ledger; admin and HR may pass employee_id.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Visits"
+                    "Leave Balances"
                 ],
-                "summary": "List visits within an attendance session",
+                "summary": "Get leave ledger",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Attendance ID",
-                        "name": "attendanceId",
-                        "in": "path",
-                        "required": true
+                        "description": "Employee ID (admin/HR only, defaults to the current user)",
+                        "name": "employee_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year (defaults to the current year)",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by leave type",
+                        "name": "leave_type",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Visits fetched",
+                        "description": "Leave ledger retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -4952,49 +5287,56 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/dto.VisitResponse"
+                                                "$ref": "#/definitions/dto.LeaveLedgerEntryResponse"
                                             }
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "400": {
+                        "description": "Invalid year format",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Employee profile not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
-        "/visits/start": {
-            "post": {
+        "/leaves/{id}": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Record arrival at a sub-location inside an active attendance session",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieve a leave request by its ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Visits"
+                    "Leaves"
                 ],
-                "summary": "Start a visit",
+                "summary": "Get leave request by ID",
                 "parameters": [
                     {
-                        "description": "Visit start data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.StartVisitRequest"
-                        }
+                        "type": "string",
+                        "description": "Leave ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Leave retrieved",
                         "schema": {
                             "allOf": [
                                 {
@@ -5004,43 +5346,59 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.VisitResponse"
+                                            "$ref": "#/definitions/dto.LeaveResponse"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "404": {
+                        "description": "Leave not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
-            }
-        },
-        "/visits/{id}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve a single visit record.",
+                "description": "Update an existing leave request by ID",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Visits"
+                    "Leaves"
                 ],
-                "summary": "Get a visit by ID",
+                "summary": "Update a leave request",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Visit ID",
+                        "description": "Leave ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Leave request data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateLeaveRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Visit fetched",
+                        "description": "Leave request updated",
                         "schema": {
                             "allOf": [
                                 {
@@ -5050,15 +5408,15 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.VisitResponse"
+                                            "$ref": "#/definitions/dto.LeaveResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "404": {
-                        "description": "Visit not found",
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -5071,18 +5429,18 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Soft-deletes a visit. Admin only.",
+                "description": "Delete a leave request by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Visits"
+                    "Leaves"
                 ],
-                "summary": "Delete a visit",
+                "summary": "Delete a leave request",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Visit ID",
+                        "description": "Leave ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -5090,13 +5448,13 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Visit deleted",
+                        "description": "Leave request deleted",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
                     "400": {
-                        "description": "Failed to delete visit",
+                        "description": "Failed to delete",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -5104,14 +5462,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/visits/{id}/end": {
-            "post": {
+        "/leaves/{id}/approve": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Finalize a visit with result notes and optional additional photos",
+                "description": "Decide the current step of a leave request's approval chain. Only the step's approver, their delegate, or for role steps a user with that role may decide. The leave stays in_review until the last step approves it; any rejection rejects it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -5119,30 +5477,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Visits"
+                    "Leaves"
                 ],
-                "summary": "End a visit",
+                "summary": "Approve or reject a leave request",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Visit ID",
+                        "description": "Leave ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Visit end data",
+                        "description": "Approval data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.EndVisitRequest"
+                            "$ref": "#/definitions/dto.ApproveLeaveRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Leave request updated",
                         "schema": {
                             "allOf": [
                                 {
@@ -5152,332 +5510,6264 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.VisitResponse"
+                                            "$ref": "#/definitions/dto.LeaveResponse"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "400": {
+                        "description": "Invalid request or not an approver of the current step",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.AddVisitPlanItemRequest": {
-            "type": "object",
-            "required": [
-                "location"
-            ],
-            "properties": {
-                "location": {
-                    "type": "string"
-                },
-                "purpose": {
-                    "type": "string"
-                },
-                "scheduled_time": {
-                    "type": "string"
-                },
-                "sequence_order": {
-                    "type": "integer"
-                },
-                "sub_location": {
-                    "type": "string"
-                }
-            }
         },
-        "dto.ApproveLeaveRequest": {
+        "/leaves/{id}/cancel": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Withdraw a leave request awaiting approval, or cancel an approved one. Cancelling an approved leave credits its days back to the balance and reverts the attendance rows it wrote. Employees may cancel their own leave before it starts; admin and HR may cancel any leave.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Leaves"
+                ],
+                "summary": "Cancel a leave request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Leave ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cancellation data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CancelLeaveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Leave request cancelled",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.LeaveResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/login-attempts": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the login audit log of accounts in your companies, newest first, with optional filters and pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Login Audit"
+                ],
+                "summary": "Get login attempts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by user ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by email (partial match)",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by IP address",
+                        "name": "ip_address",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by result (success, 2fa_challenge, invalid_password, unknown_user, invalid_2fa, deactivated, locked, throttled)",
+                        "name": "result",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter end date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login attempts retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.PaginatedLoginAttemptResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch login attempts",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/modules": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Used by the frontend to filter the sidebar. Superadmins receive all modules.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Modules"
+                ],
+                "summary": "Get the enabled module keys for the current caller",
+                "responses": {
+                    "200": {
+                        "description": "Modules fetched",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.MyModulesResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Employee or company not found for user",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/menu-access": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve all user menu access mappings (Admin only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menu Access"
+                ],
+                "summary": "Get all menu access mappings",
+                "responses": {
+                    "200": {
+                        "description": "All menu access retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.MenuAccessResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Set the menu keys accessible to a specific user (Admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menu Access"
+                ],
+                "summary": "Set user menu access",
+                "parameters": [
+                    {
+                        "description": "Menu access data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetMenuAccessRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Menu access updated",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/menu-access/me": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the list of menu keys accessible to the currently authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menu Access"
+                ],
+                "summary": "Get my menu access",
+                "responses": {
+                    "200": {
+                        "description": "Menu access retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserMenuKeysResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/menu-access/{user_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete custom menu access for a user, reverting to role defaults (Admin only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menu Access"
+                ],
+                "summary": "Reset user menu access",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Menu access reset to defaults",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "user_id is required",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/modules": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns every feature module known to the system (core + opt-in). Authenticated.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Modules"
+                ],
+                "summary": "List the feature module catalog",
+                "responses": {
+                    "200": {
+                        "description": "Modules fetched",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.ModuleResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch modules",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns the notifications of the authenticated user, newest first, from the inbox or the archive, with optional filters. Pages are cursor-based: pass the next_cursor of a page as the cursor of the next one; the last page has none. Each notification carries the link of the frontend page of what it refers to, when there is one",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Get notifications for the current user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number to return (default 20, at most 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by type (info, success, warning, error)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by the kind of record referred to, e.g. leave",
+                        "name": "ref_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by read state",
+                        "name": "is_read",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "List the archive instead of the inbox",
+                        "name": "archived",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NotificationPageResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/archive": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Moves notifications of the authenticated user out of the inbox into the archive, at most 100 at a time. Archived notifications do not count as unread. IDs that are not the user's or already archived are skipped",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Archive notifications",
+                "parameters": [
+                    {
+                        "description": "Notification IDs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NotificationIDsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NotificationBulkResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/delete": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes notifications of the authenticated user, at most 100 at a time. IDs that are not the user's are skipped",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Delete notifications",
+                "parameters": [
+                    {
+                        "description": "Notification IDs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NotificationIDsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NotificationBulkResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/preferences": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns how the authenticated user is notified of each event type: in_app (in the application only), email (in the application and by email, the default), digest (in one summary a day) or off. Password resets are always delivered right away",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Get my notification preferences",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.NotificationPreferenceResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch notification preferences",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sets the mode of the listed event types for the authenticated user; the others keep theirs. Returns the preferences of every event type",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Update my notification preferences",
+                "parameters": [
+                    {
+                        "description": "Modes per event type",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateNotificationPreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.NotificationPreferenceResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/read-all": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Mark all notifications as read",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/stream": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Server-Sent Events stream of the authenticated user's new notifications, each sent as a \"notification\" event whose ID is the notification ID and whose data is the notification. A client reconnecting with the Last-Event-ID header, or the last_event_id query parameter, first receives the notifications it missed, at most 100. Browsers may pass the access token in the access_token query parameter, since EventSource cannot set headers. The server ends the stream every 15 minutes; clients reconnect with a current token",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Stream new notifications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the last notification received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last notification received, for clients that cannot set headers",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Access token, for clients that cannot set headers",
+                        "name": "access_token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid last event ID",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/unarchive": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Moves archived notifications of the authenticated user back to the inbox, at most 100 at a time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Unarchive notifications",
+                "parameters": [
+                    {
+                        "description": "Notification IDs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NotificationIDsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NotificationBulkResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/unread-count": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns the number of unread notifications for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Get unread notification count",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UnreadCountResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Delete a notification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Notification not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/{id}/read": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Mark a notification as read",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/structure": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the hierarchical organization structure for a company (departments → positions → employees)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organization"
+                ],
+                "summary": "Get organization structure",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Company ID",
+                        "name": "company_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Organization structure retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.OrgStructureResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "company_id is required",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Company not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/payroll-runs": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve all payroll runs, optionally filtered by company",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payroll Runs"
+                ],
+                "summary": "Get all payroll runs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by company ID",
+                        "name": "company_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payroll runs retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.PayrollRunResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch payroll runs",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Generate draft payrolls for every active employee of a company for a specific month and year. Employees that already have a payroll for the period are skipped and employees that cannot be calculated are reported as failed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payroll Runs"
+                ],
+                "summary": "Generate a payroll run",
+                "parameters": [
+                    {
+                        "description": "Payroll run generation data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneratePayrollRunRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Payroll run generated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.PayrollRunGenerateResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or no payrolls generated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.PayrollRunGenerateResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/payroll-runs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve a payroll run and its payrolls by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payroll Runs"
+                ],
+                "summary": "Get payroll run by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payroll run ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payroll run retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.PayrollRunResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Payroll run not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a draft payroll run together with its payrolls",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payroll Runs"
+                ],
+                "summary": "Delete a payroll run",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payroll run ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payroll run deleted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Failed to delete",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/payroll-runs/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Move a payroll run and all of its payrolls to the next status (draft, processed, paid)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payroll Runs"
+                ],
+                "summary": "Update payroll run status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payroll run ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PayrollStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payroll run status updated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.PayrollRunResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or status",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/payrolls": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve all payroll records, optionally filtered by employee, month, and year",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payrolls"
+                ],
+                "summary": "Get all payrolls",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by employee ID",
+                        "name": "employee_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by month (1-12)",
+                        "name": "month",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by year",
+                        "name": "year",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payrolls retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.PayrollResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid month or year format",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch payrolls",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/payrolls/generate": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Generate a payroll record for an employee for a specific month and year",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payrolls"
+                ],
+                "summary": "Generate payroll",
+                "parameters": [
+                    {
+                        "description": "Payroll generation data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.GeneratePayrollRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Payroll generated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.PayrollResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/payrolls/me": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve paid payroll records for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payrolls"
+                ],
+                "summary": "Get my payslips",
+                "responses": {
+                    "200": {
+                        "description": "Payslips retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.PayrollResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Employee profile not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch payslips",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/payrolls/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve a payroll record by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payrolls"
+                ],
+                "summary": "Get payroll by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payroll ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payroll retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.PayrollResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Payroll not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update an existing payroll record by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payrolls"
+                ],
+                "summary": "Update a payroll",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payroll ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payroll data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePayrollRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payroll updated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.PayrollResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a payroll record by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payrolls"
+                ],
+                "summary": "Delete a payroll",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payroll ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payroll deleted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Failed to delete",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/payrolls/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update the status of a payroll record (draft, processed, paid)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payrolls"
+                ],
+                "summary": "Update payroll status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payroll ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PayrollStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payroll status updated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.PayrollResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or status",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/positions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve all positions, optionally filtered by company",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Positions"
+                ],
+                "summary": "Get all positions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by company ID",
+                        "name": "company_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Positions retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.PositionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch positions",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a new position",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Positions"
+                ],
+                "summary": "Create a position",
+                "parameters": [
+                    {
+                        "description": "Position data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreatePositionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Position created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.PositionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/positions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve a position by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Positions"
+                ],
+                "summary": "Get position by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Position ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Position retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.PositionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Position not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update an existing position by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Positions"
+                ],
+                "summary": "Update a position",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Position ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Position data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePositionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Position updated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.PositionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a position by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Positions"
+                ],
+                "summary": "Delete a position",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Position ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Position deleted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Failed to delete",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve all custom roles, optionally filtered by company",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Get all custom roles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by company ID",
+                        "name": "company_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Roles retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.CustomRoleResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch roles",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a company role from permissions in the catalog, e.g. a payroll officer with payroll:read and payroll:generate but not payroll:pay. Users assigned the role get exactly these permissions. Callers can only grant permissions they have themselves.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Create a custom role",
+                "parameters": [
+                    {
+                        "description": "Role data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateCustomRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Role created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.CustomRoleResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/permissions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve every permission that can be granted to a custom role",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Get the permission catalog",
+                "responses": {
+                    "200": {
+                        "description": "Permissions retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.PermissionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch permissions",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve a custom role and its permissions by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Get custom role by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.CustomRoleResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update a custom role. When permissions are given they replace the role's permissions; callers can only grant permissions they have themselves.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Update a custom role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateCustomRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role updated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.CustomRoleResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a custom role that is no longer assigned to any user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Delete a custom role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role deleted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Failed to delete",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/service-accounts": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the service accounts of your companies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Service Accounts"
+                ],
+                "summary": "Get service accounts",
+                "responses": {
+                    "200": {
+                        "description": "Service accounts retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.ServiceAccountResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch service accounts",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a non-human account for an integration, bound to one company. It cannot log in; it authenticates with API tokens whose permissions are taken from its role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Service Accounts"
+                ],
+                "summary": "Create a service account",
+                "parameters": [
+                    {
+                        "description": "Service account data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateServiceAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Service account created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ServiceAccountResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/service-accounts/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve a service account by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Service Accounts"
+                ],
+                "summary": "Get service account by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Service account retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ServiceAccountResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Service account not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Rename a service account, change its role or deactivate it. A deactivated account's tokens stop working",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Service Accounts"
+                ],
+                "summary": "Update a service account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Service account data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateServiceAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Service account updated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ServiceAccountResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a service account and revoke its API tokens",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Service Accounts"
+                ],
+                "summary": "Delete a service account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Service account deleted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Failed to delete",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/service-accounts/{id}/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the API tokens of a service account that are not revoked",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Service Accounts"
+                ],
+                "summary": "Get a service account's API tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "API tokens retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.APITokenResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Service account not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a token for a service account, valid in its company and with at most the listed permissions of its role. The token is shown only in this response",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Service Accounts"
+                ],
+                "summary": "Create a service account API token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Token data; company_id is ignored",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateAPITokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "API token created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.CreatedAPITokenResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "API tokens cannot create API tokens",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/service-accounts/{id}/tokens/{tokenId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revoke an API token of a service account. It stops working immediately",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Service Accounts"
+                ],
+                "summary": "Revoke a service account API token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token ID",
+                        "name": "tokenId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "API token revoked",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "API token not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/shifts": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve all shifts, optionally filtered by company",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shifts"
+                ],
+                "summary": "Get all shifts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by company ID",
+                        "name": "company_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shifts retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.ShiftResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch shifts",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a new work shift",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shifts"
+                ],
+                "summary": "Create a shift",
+                "parameters": [
+                    {
+                        "description": "Shift data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateShiftRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Shift created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ShiftResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/shifts/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve a shift by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shifts"
+                ],
+                "summary": "Get shift by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Shift ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shift retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ShiftResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Shift not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update an existing shift by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shifts"
+                ],
+                "summary": "Update a shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Shift ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Shift data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateShiftRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shift updated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ShiftResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a shift by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shifts"
+                ],
+                "summary": "Delete a shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Shift ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shift deleted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Failed to delete",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/sso-providers": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the OIDC providers of your companies. Client secrets are never returned",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Single Sign-On"
+                ],
+                "summary": "Get SSO providers",
+                "responses": {
+                    "200": {
+                        "description": "SSO providers retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.OIDCProviderResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch SSO providers",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Register a company's OIDC provider. The issuer must be an https URL. Register {APP_BASE_URL}/api/auth/oidc/{slug}/callback as the redirect URI at the provider. With auto_provision, unknown emails get an account with default_role in the company",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Single Sign-On"
+                ],
+                "summary": "Create an SSO provider",
+                "parameters": [
+                    {
+                        "description": "Provider data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateOIDCProviderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "SSO provider created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.OIDCProviderResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/sso-providers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve an OIDC provider by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Single Sign-On"
+                ],
+                "summary": "Get SSO provider by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "SSO provider retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.OIDCProviderResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "SSO provider not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update an OIDC provider. The client secret is kept unless given",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Single Sign-On"
+                ],
+                "summary": "Update an SSO provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Provider data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateOIDCProviderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "SSO provider updated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.OIDCProviderResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete an OIDC provider. Accounts created through it are kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Single Sign-On"
+                ],
+                "summary": "Delete an SSO provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "SSO provider deleted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Failed to delete",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve all users (admin and hr only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get all users",
+                "responses": {
+                    "200": {
+                        "description": "Users retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.UserResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch users",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a new user (admin only). The role cannot be above the caller's own, superadmin is granted only by superadmins, and a custom role can only be assigned by callers holding all of its permissions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create new user",
+                "parameters": [
+                    {
+                        "description": "User details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve currently authenticated user's information",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get current user",
+                "responses": {
+                    "200": {
+                        "description": "User retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve user by ID (admin, hr, or self)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update user information (admin only). Users whose role is above the caller's cannot be changed, and roles are granted as on create",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User details to update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User updated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a user by ID (admin only, cannot delete self or a user whose role is above your own)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User deleted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Cannot delete your own account, a user above your role or a user not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/2fa": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Remove a user's TOTP secret and recovery codes so they can enroll again. Only a superadmin can reset a superadmin",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Reset a user's two-factor authentication",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Two-factor authentication reset",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Failed to reset two-factor authentication",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/companies": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the companies a user is bound to, in addition to the company of their employee record (Superadmin only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get a user's companies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User companies retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.UserCompanyResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Replace the companies a user is bound to. Admin and HR users only see and change data of these companies and of their own employee record's company (Superadmin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Set a user's companies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Company IDs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetUserCompaniesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User companies updated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.UserCompanyResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the active sessions of a user in your companies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get a user's sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sessions retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.SessionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Log out every session of a user in your companies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Revoke all of a user's sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sessions revoked",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/sessions/{sessionId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Log out one session of a user in your companies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Revoke a user's session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve the API tokens of a user in your companies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get a user's API tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "API tokens retrieved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.APITokenResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/tokens/{tokenId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revoke an API token of a user in your companies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Revoke a user's API token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token ID",
+                        "name": "tokenId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "API token revoked",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "API token not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/unlock": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lift the lockout of a user locked after too many failed logins and clear their failed login count",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Unlock a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User unlocked",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit-plans": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "VisitPlans"
+                ],
+                "summary": "Create a visit plan for an employee on a specific date",
+                "parameters": [
+                    {
+                        "description": "Plan data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateVisitPlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VisitPlanResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/visit-plans/by-date": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Pass employee_id=me to look up the caller's plan.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "VisitPlans"
+                ],
+                "summary": "Fetch the plan for an employee on a given date",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Employee ID or 'me'",
+                        "name": "employee_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plan date (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Plan fetched",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VisitPlanResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date format",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit-plans/employee/{employeeId}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Optionally filter by date range. Use employeeId=me for self-service.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "VisitPlans"
+                ],
+                "summary": "List plans for an employee",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Employee ID or 'me'",
+                        "name": "employeeId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Plans fetched",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.VisitPlanResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/visit-plans/items/{itemId}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "VisitPlans"
+                ],
+                "summary": "Update a plan item (mark visited/skipped, edit fields)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plan item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateVisitPlanItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Item updated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VisitPlanItemResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "VisitPlans"
+                ],
+                "summary": "Delete a plan item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plan item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Item deleted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit-plans/report": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Planned-vs-actual per employee for one date. The under_minimum flag is soft (default minimum 5).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "VisitPlans"
+                ],
+                "summary": "Daily visit adherence report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Company ID",
+                        "name": "company_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Report date (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Override the 5/day threshold",
+                        "name": "minimum",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Report generated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VisitAdherenceReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit-plans/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "VisitPlans"
+                ],
+                "summary": "Get a visit plan by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Plan fetched",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VisitPlanResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "VisitPlans"
+                ],
+                "summary": "Update a visit plan (status / notes)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateVisitPlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Plan updated",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VisitPlanResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Soft-deletes the plan and all its items. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "VisitPlans"
+                ],
+                "summary": "Delete a visit plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Plan deleted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit-plans/{id}/items": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "VisitPlans"
+                ],
+                "summary": "Add a stop to a visit plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Plan item data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddVisitPlanItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Item added",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VisitPlanItemResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/visits": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Admin/HR listing. Employees should use /visits/attendance/{attendanceId}.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Visits"
+                ],
+                "summary": "List visits with filters",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by employee ID",
+                        "name": "employee_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by company ID",
+                        "name": "company_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter start date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter end date (YYYY-MM-DD, inclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Visits fetched",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.PaginatedVisitResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date filter",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/visits/attendance/{attendanceId}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Timeline of visits nested inside one clock-in session, ordered by arrival.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Visits"
+                ],
+                "summary": "List visits within an attendance session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Attendance ID",
+                        "name": "attendanceId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Visits fetched",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.VisitResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/visits/start": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Record arrival at a sub-location inside an active attendance session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Visits"
+                ],
+                "summary": "Start a visit",
+                "parameters": [
+                    {
+                        "description": "Visit start data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.StartVisitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VisitResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/visits/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve a single visit record.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Visits"
+                ],
+                "summary": "Get a visit by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Visit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Visit fetched",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VisitResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Visit not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Soft-deletes a visit. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Visits"
+                ],
+                "summary": "Delete a visit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Visit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Visit deleted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Failed to delete visit",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/visits/{id}/end": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Finalize a visit with result notes and optional additional photos",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Visits"
+                ],
+                "summary": "End a visit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Visit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Visit end data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.EndVisitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VisitResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.APITokenResponse": {
+            "type": "object",
+            "properties": {
+                "company": {
+                    "$ref": "#/definitions/dto.CompanyResponse"
+                },
+                "company_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expired": {
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "last_used_ip": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "prefix": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AddVisitPlanItemRequest": {
+            "type": "object",
+            "required": [
+                "location"
+            ],
+            "properties": {
+                "location": {
+                    "type": "string"
+                },
+                "purpose": {
+                    "type": "string"
+                },
+                "scheduled_time": {
+                    "type": "string"
+                },
+                "sequence_order": {
+                    "type": "integer"
+                },
+                "sub_location": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AnnouncementAttachmentInput": {
+            "type": "object",
+            "required": [
+                "name",
+                "url"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "http(s) URL of the stored file",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AnnouncementAttachmentResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AnnouncementReadReportResponse": {
+            "type": "object",
+            "properties": {
+                "announcement_id": {
+                    "type": "string"
+                },
+                "audience_total": {
+                    "type": "integer"
+                },
+                "read_count": {
+                    "type": "integer"
+                },
+                "unread": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AnnouncementUnreadReader"
+                    }
+                },
+                "unread_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AnnouncementResponse": {
+            "type": "object",
+            "properties": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AnnouncementAttachmentResponse"
+                    }
+                },
+                "body": {
+                    "type": "string"
+                },
+                "company_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "department_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expire_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_pinned": {
+                    "type": "boolean"
+                },
+                "is_read": {
+                    "type": "boolean"
+                },
+                "job_level_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AnnouncementUnreadReader": {
+            "type": "object",
+            "properties": {
+                "department_name": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "employee_number": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ApproveLeaveRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "rejection_reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.LeaveStatus"
+                }
+            }
+        },
+        "dto.AttendanceResponse": {
+            "type": "object",
+            "properties": {
+                "clock_in": {
+                    "type": "string"
+                },
+                "clock_in_distance_m": {
+                    "type": "number"
+                },
+                "clock_in_lat": {
+                    "description": "GPS + photo (present when captured; omitted when null/empty)",
+                    "type": "number"
+                },
+                "clock_in_lng": {
+                    "type": "number"
+                },
+                "clock_in_photo": {
+                    "type": "string"
+                },
+                "clock_out": {
+                    "type": "string"
+                },
+                "clock_out_distance_m": {
+                    "type": "number"
+                },
+                "clock_out_lat": {
+                    "type": "number"
+                },
+                "clock_out_lng": {
+                    "type": "number"
+                },
+                "clock_out_photo": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "employee": {
+                    "$ref": "#/definitions/dto.EmployeeResponse"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "leave_id": {
+                    "description": "Set when the row was written by an approved leave",
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "overtime_hours": {
+                    "type": "number"
+                },
+                "shift_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.AttendanceStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CancelLeaveRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string",
+                    "minLength": 6
+                }
+            }
+        },
+        "dto.ClockInRequest": {
+            "type": "object",
+            "required": [
+                "employee_id"
+            ],
+            "properties": {
+                "distance_m": {
+                    "description": "meters from expected location",
+                    "type": "number"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "lat": {
+                    "description": "Optional GPS + photo capture — required only when geo_attendance module is enabled.",
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "photo": {
+                    "description": "URL to uploaded photo",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ClockOutRequest": {
+            "type": "object",
+            "properties": {
+                "distance_m": {
+                    "type": "number"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "photo": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CompanyModuleResponse": {
+            "type": "object",
+            "properties": {
+                "company_id": {
+                    "type": "string"
+                },
+                "config": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "enabled_at": {
+                    "type": "string"
+                },
+                "enabled_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "module": {
+                    "$ref": "#/definitions/dto.ModuleResponse"
+                },
+                "module_key": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CompanyResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "logo": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "npp": {
+                    "type": "string"
+                },
+                "npwp": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateAPITokenRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "permissions"
+            ],
+            "properties": {
+                "company_id": {
+                    "type": "string"
+                },
+                "expires_in_days": {
+                    "description": "ExpiresInDays defaults to 90 and is at most 365",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.CreateAnnouncementRequest": {
+            "type": "object",
+            "required": [
+                "body",
+                "company_id",
+                "title"
+            ],
+            "properties": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AnnouncementAttachmentInput"
+                    }
+                },
+                "body": {
+                    "description": "HTML; unsupported markup is removed",
+                    "type": "string"
+                },
+                "company_id": {
+                    "type": "string"
+                },
+                "department_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expire_at": {
+                    "type": "string"
+                },
+                "is_pinned": {
+                    "type": "boolean"
+                },
+                "job_level_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateAttendanceRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "employee_id",
+                "status"
+            ],
+            "properties": {
+                "clock_in": {
+                    "type": "string"
+                },
+                "clock_out": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "overtime_hours": {
+                    "type": "number"
+                },
+                "shift_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.AttendanceStatus"
+                }
+            }
+        },
+        "dto.CreateCompanyRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "logo": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "npp": {
+                    "type": "string"
+                },
+                "npwp": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateCustomRoleRequest": {
+            "type": "object",
+            "required": [
+                "company_id",
+                "name",
+                "permissions"
+            ],
+            "properties": {
+                "company_id": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.CreateDepartmentRequest": {
+            "type": "object",
+            "required": [
+                "company_id",
+                "name"
+            ],
+            "properties": {
+                "company_id": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "head_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateEmployeeRequest": {
+            "type": "object",
+            "required": [
+                "company_id",
+                "department_id",
+                "employee_number",
+                "join_date",
+                "position_id",
+                "shift_id",
+                "user_id"
+            ],
+            "properties": {
+                "bank_account": {
+                    "type": "string"
+                },
+                "bank_name": {
+                    "type": "string"
+                },
+                "birth_date": {
+                    "type": "string"
+                },
+                "birth_place": {
+                    "type": "string"
+                },
+                "blood_type": {
+                    "type": "string"
+                },
+                "bpjs_kes_no": {
+                    "type": "string"
+                },
+                "bpjs_tk_no": {
+                    "type": "string"
+                },
+                "company_id": {
+                    "type": "string"
+                },
+                "contract_end_date": {
+                    "type": "string"
+                },
+                "contract_start_date": {
+                    "type": "string"
+                },
+                "department_id": {
+                    "type": "string"
+                },
+                "dependents": {
+                    "type": "integer"
+                },
+                "employee_number": {
+                    "type": "string"
+                },
+                "employee_status": {
+                    "$ref": "#/definitions/model.EmployeeStatus"
+                },
+                "gender": {
+                    "type": "string"
+                },
+                "grade_id": {
+                    "type": "string"
+                },
+                "job_level_id": {
+                    "type": "string"
+                },
+                "join_date": {
+                    "type": "string"
+                },
+                "last_education": {
+                    "type": "string"
+                },
+                "marital_status": {
+                    "type": "string"
+                },
+                "nik": {
+                    "type": "string"
+                },
+                "npwp": {
+                    "type": "string"
+                },
+                "position_id": {
+                    "type": "string"
+                },
+                "religion": {
+                    "type": "string"
+                },
+                "shift_id": {
+                    "type": "string"
+                },
+                "supervisor_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateEmployeeSalaryRequest": {
+            "type": "object",
+            "required": [
+                "basic_salary",
+                "effective_date",
+                "employee_id"
+            ],
+            "properties": {
+                "basic_salary": {
+                    "type": "number"
+                },
+                "effective_date": {
+                    "type": "string"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "housing_allowance": {
+                    "type": "number"
+                },
+                "meal_allowance": {
+                    "type": "number"
+                },
+                "position_allowance": {
+                    "type": "number"
+                },
+                "transport_allowance": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.CreateHolidayRequest": {
+            "type": "object",
+            "required": [
+                "company_id",
+                "date",
+                "name"
+            ],
+            "properties": {
+                "company_id": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "is_national": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateLeaveDelegationRequest": {
+            "type": "object",
+            "required": [
+                "delegate_id",
+                "end_date",
+                "start_date"
+            ],
+            "properties": {
+                "delegate_id": {
+                    "type": "string"
+                },
+                "delegator_id": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateLeavePolicyRequest": {
+            "type": "object",
+            "required": [
+                "annual_days",
+                "company_id",
+                "leave_type"
+            ],
+            "properties": {
+                "accrual_method": {
+                    "$ref": "#/definitions/model.LeaveAccrualMethod"
+                },
+                "annual_days": {
+                    "type": "number"
+                },
+                "carry_over_expiry_months": {
+                    "type": "integer"
+                },
+                "company_id": {
+                    "type": "string"
+                },
+                "leave_type": {
+                    "$ref": "#/definitions/model.LeaveType"
+                },
+                "max_carry_over_days": {
+                    "type": "number"
+                },
+                "requires_balance": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.CreateLeaveRequest": {
+            "type": "object",
+            "required": [
+                "employee_id",
+                "end_date",
+                "leave_type",
+                "reason",
+                "start_date"
+            ],
+            "properties": {
+                "attachment": {
+                    "type": "string"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "end_half_day": {
+                    "type": "boolean"
+                },
+                "leave_type": {
+                    "$ref": "#/definitions/model.LeaveType"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "start_half_day": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.CreateLeaveWorkflowRequest": {
+            "type": "object",
+            "required": [
+                "company_id",
+                "name",
+                "steps"
+            ],
+            "properties": {
+                "company_id": {
+                    "type": "string"
+                },
+                "department_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LeaveWorkflowStepRequest"
+                    }
+                }
+            }
+        },
+        "dto.CreateOIDCProviderRequest": {
+            "type": "object",
+            "required": [
+                "client_id",
+                "company_id",
+                "issuer_url",
+                "name",
+                "slug"
+            ],
+            "properties": {
+                "allowed_domains": {
+                    "type": "string"
+                },
+                "auto_provision": {
+                    "type": "boolean"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "client_secret": {
+                    "type": "string"
+                },
+                "company_id": {
+                    "type": "string"
+                },
+                "default_role": {
+                    "type": "string"
+                },
+                "issuer_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreatePositionRequest": {
+            "type": "object",
+            "required": [
+                "company_id",
+                "name"
+            ],
+            "properties": {
+                "base_salary": {
+                    "type": "number"
+                },
+                "company_id": {
+                    "type": "string"
+                },
+                "department_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateServiceAccountRequest": {
+            "type": "object",
+            "required": [
+                "company_id",
+                "name"
+            ],
+            "properties": {
+                "company_id": {
+                    "type": "string"
+                },
+                "custom_role_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "enum": [
+                        "admin",
+                        "hr",
+                        "employee"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Role"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.CreateShiftRequest": {
+            "type": "object",
+            "required": [
+                "company_id",
+                "end_time",
+                "name",
+                "start_time"
+            ],
+            "properties": {
+                "company_id": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "work_days": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateUserRequest": {
             "type": "object",
             "required": [
-                "status"
+                "email",
+                "name",
+                "password",
+                "role"
             ],
             "properties": {
-                "rejection_reason": {
+                "address": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/model.LeaveStatus"
+                "custom_role_id": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "language": {
+                    "description": "Language of the user's email, id (default) or en",
+                    "type": "string"
+                },
+                "must_change_password": {
+                    "description": "MustChangePassword makes the user replace the initial password on\nfirst login",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "role": {
+                    "enum": [
+                        "superadmin",
+                        "admin",
+                        "hr",
+                        "employee"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Role"
+                        }
+                    ]
                 }
             }
         },
-        "dto.AttendanceResponse": {
+        "dto.CreateVisitPlanRequest": {
             "type": "object",
+            "required": [
+                "employee_id",
+                "plan_date"
+            ],
             "properties": {
-                "clock_in": {
+                "employee_id": {
                     "type": "string"
                 },
-                "clock_in_distance_m": {
-                    "type": "number"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VisitPlanItemInput"
+                    }
                 },
-                "clock_in_lat": {
-                    "description": "GPS + photo (present when captured; omitted when null/empty)",
-                    "type": "number"
+                "notes": {
+                    "type": "string"
                 },
-                "clock_in_lng": {
-                    "type": "number"
+                "plan_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
                 },
-                "clock_in_photo": {
+                "status": {
+                    "description": "optional, defaults to draft",
                     "type": "string"
+                }
+            }
+        },
+        "dto.CreatedAPITokenResponse": {
+            "type": "object",
+            "properties": {
+                "company": {
+                    "$ref": "#/definitions/dto.CompanyResponse"
                 },
-                "clock_out": {
+                "company_id": {
                     "type": "string"
                 },
-                "clock_out_distance_m": {
-                    "type": "number"
+                "created_at": {
+                    "type": "string"
                 },
-                "clock_out_lat": {
-                    "type": "number"
+                "expired": {
+                    "type": "boolean"
                 },
-                "clock_out_lng": {
-                    "type": "number"
+                "expires_at": {
+                    "type": "string"
                 },
-                "clock_out_photo": {
+                "id": {
                     "type": "string"
                 },
-                "created_at": {
+                "last_used_at": {
                     "type": "string"
                 },
-                "date": {
+                "last_used_ip": {
                     "type": "string"
                 },
-                "employee": {
-                    "$ref": "#/definitions/dto.EmployeeResponse"
+                "name": {
+                    "type": "string"
                 },
-                "employee_id": {
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "prefix": {
                     "type": "string"
                 },
-                "id": {
+                "token": {
                     "type": "string"
+                }
+            }
+        },
+        "dto.CustomRoleResponse": {
+            "type": "object",
+            "properties": {
+                "company": {
+                    "$ref": "#/definitions/dto.CompanyResponse"
                 },
-                "notes": {
+                "company_id": {
                     "type": "string"
                 },
-                "overtime_hours": {
-                    "type": "number"
+                "created_at": {
+                    "type": "string"
                 },
-                "shift_id": {
+                "description": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/model.AttendanceStatus"
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
                 "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "dto.ClockInRequest": {
+        "dto.DeadLetterDetailResponse": {
             "type": "object",
-            "required": [
-                "employee_id"
-            ],
             "properties": {
-                "distance_m": {
-                    "description": "meters from expected location",
-                    "type": "number"
+                "attempts": {
+                    "type": "integer"
                 },
-                "employee_id": {
+                "company_id": {
                     "type": "string"
                 },
-                "lat": {
-                    "description": "Optional GPS + photo capture — required only when geo_attendance module is enabled.",
-                    "type": "number"
+                "error": {
+                    "type": "string"
                 },
-                "lng": {
-                    "type": "number"
+                "event_id": {
+                    "type": "string"
                 },
-                "notes": {
+                "event_type": {
                     "type": "string"
                 },
-                "photo": {
-                    "description": "URL to uploaded photo",
+                "failed_at": {
+                    "type": "string"
+                },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "replayed_at": {
+                    "type": "string"
+                },
+                "replayed_by": {
+                    "type": "string"
+                },
+                "source_offset": {
+                    "type": "integer"
+                },
+                "source_partition": {
+                    "type": "integer"
+                },
+                "source_topic": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "dto.ClockOutRequest": {
+        "dto.DeadLetterResponse": {
             "type": "object",
             "properties": {
-                "distance_m": {
-                    "type": "number"
+                "attempts": {
+                    "type": "integer"
                 },
-                "lat": {
-                    "type": "number"
+                "company_id": {
+                    "type": "string"
                 },
-                "lng": {
-                    "type": "number"
+                "error": {
+                    "type": "string"
                 },
-                "notes": {
+                "event_id": {
                     "type": "string"
                 },
-                "photo": {
+                "event_type": {
+                    "type": "string"
+                },
+                "failed_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "replayed_at": {
+                    "type": "string"
+                },
+                "replayed_by": {
+                    "type": "string"
+                },
+                "source_offset": {
+                    "type": "integer"
+                },
+                "source_partition": {
+                    "type": "integer"
+                },
+                "source_topic": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "dto.CompanyModuleResponse": {
+        "dto.DeleteMultipleCompaniesRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.DepartmentResponse": {
             "type": "object",
             "properties": {
+                "company": {
+                    "$ref": "#/definitions/dto.CompanyResponse"
+                },
                 "company_id": {
                     "type": "string"
                 },
-                "config": {
+                "created_at": {
                     "type": "string"
                 },
-                "enabled": {
-                    "type": "boolean"
-                },
-                "enabled_at": {
+                "description": {
                     "type": "string"
                 },
-                "enabled_by": {
+                "head_id": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "module": {
-                    "$ref": "#/definitions/dto.ModuleResponse"
+                "is_active": {
+                    "type": "boolean"
                 },
-                "module_key": {
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DisableTwoFactorRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "password"
+            ],
+            "properties": {
+                "code": {
                     "type": "string"
                 },
-                "updated_at": {
+                "password": {
                     "type": "string"
                 }
             }
         },
-        "dto.CompanyResponse": {
+        "dto.EmailDeliveryDetailResponse": {
             "type": "object",
             "properties": {
-                "address": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "company_id": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "email": {
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "html_body": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "language": {
+                    "type": "string"
                 },
-                "logo": {
+                "last_error": {
                     "type": "string"
                 },
-                "name": {
+                "next_attempt_at": {
                     "type": "string"
                 },
-                "npp": {
+                "notification_id": {
                     "type": "string"
                 },
-                "npwp": {
+                "sent_at": {
                     "type": "string"
                 },
-                "phone": {
+                "status": {
                     "type": "string"
                 },
-                "updated_at": {
+                "subject": {
+                    "type": "string"
+                },
+                "text_body": {
+                    "type": "string"
+                },
+                "to_address": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.CreateAttendanceRequest": {
+        "dto.EmailDeliveryResponse": {
             "type": "object",
-            "required": [
-                "date",
-                "employee_id",
-                "status"
-            ],
             "properties": {
-                "clock_in": {
-                    "type": "string"
+                "attempts": {
+                    "type": "integer"
                 },
-                "clock_out": {
+                "company_id": {
                     "type": "string"
                 },
-                "date": {
+                "created_at": {
                     "type": "string"
                 },
-                "employee_id": {
+                "event_id": {
                     "type": "string"
                 },
-                "notes": {
+                "event_type": {
                     "type": "string"
                 },
-                "overtime_hours": {
-                    "type": "number"
-                },
-                "shift_id": {
+                "id": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/model.AttendanceStatus"
-                }
-            }
-        },
-        "dto.CreateCompanyRequest": {
-            "type": "object",
-            "required": [
-                "name"
-            ],
-            "properties": {
-                "address": {
+                "language": {
                     "type": "string"
                 },
-                "email": {
+                "last_error": {
                     "type": "string"
                 },
-                "logo": {
+                "next_attempt_at": {
                     "type": "string"
                 },
-                "name": {
+                "notification_id": {
                     "type": "string"
                 },
-                "npp": {
+                "sent_at": {
                     "type": "string"
                 },
-                "npwp": {
+                "status": {
                     "type": "string"
                 },
-                "phone": {
-                    "type": "string"
-                }
-            }
-        },
-        "dto.CreateDepartmentRequest": {
-            "type": "object",
-            "required": [
-                "company_id",
-                "name"
-            ],
-            "properties": {
-                "company_id": {
+                "subject": {
                     "type": "string"
                 },
-                "description": {
+                "to_address": {
                     "type": "string"
                 },
-                "name": {
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.CreateEmployeeRequest": {
+        "dto.EmployeeResponse": {
             "type": "object",
-            "required": [
-                "company_id",
-                "department_id",
-                "employee_number",
-                "join_date",
-                "position_id",
-                "shift_id",
-                "user_id"
-            ],
             "properties": {
                 "bank_account": {
                     "type": "string"
@@ -5500,6 +11790,9 @@ const docTemplate = `{
                 "bpjs_tk_no": {
                     "type": "string"
                 },
+                "company": {
+                    "$ref": "#/definitions/dto.CompanyResponse"
+                },
                 "company_id": {
                     "type": "string"
                 },
@@ -5509,9 +11802,18 @@ const docTemplate = `{
                 "contract_start_date": {
                     "type": "string"
                 },
+                "created_at": {
+                    "type": "string"
+                },
+                "department": {
+                    "$ref": "#/definitions/dto.DepartmentResponse"
+                },
                 "department_id": {
                     "type": "string"
                 },
+                "dependents": {
+                    "type": "integer"
+                },
                 "employee_number": {
                     "type": "string"
                 },
@@ -5521,529 +11823,646 @@ const docTemplate = `{
                 "gender": {
                     "type": "string"
                 },
+                "grade": {
+                    "$ref": "#/definitions/dto.GradeResponse"
+                },
                 "grade_id": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "string"
+                },
+                "job_level": {
+                    "$ref": "#/definitions/dto.JobLevelResponse"
+                },
                 "job_level_id": {
                     "type": "string"
                 },
-                "join_date": {
-                    "type": "string"
+                "join_date": {
+                    "type": "string"
+                },
+                "last_education": {
+                    "type": "string"
+                },
+                "marital_status": {
+                    "type": "string"
+                },
+                "nik": {
+                    "type": "string"
+                },
+                "npwp": {
+                    "type": "string"
+                },
+                "position": {
+                    "$ref": "#/definitions/dto.PositionResponse"
+                },
+                "position_id": {
+                    "type": "string"
+                },
+                "religion": {
+                    "type": "string"
+                },
+                "resign_date": {
+                    "type": "string"
+                },
+                "shift": {
+                    "$ref": "#/definitions/dto.ShiftResponse"
+                },
+                "shift_id": {
+                    "type": "string"
+                },
+                "supervisor_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.UserResponse"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.EmployeeSalaryResponse": {
+            "type": "object",
+            "properties": {
+                "basic_salary": {
+                    "type": "number"
+                },
+                "bpjs_kes_company": {
+                    "type": "number"
+                },
+                "bpjs_kes_employee": {
+                    "type": "number"
+                },
+                "bpjs_tk_jht_company": {
+                    "type": "number"
+                },
+                "bpjs_tk_jht_employee": {
+                    "type": "number"
+                },
+                "bpjs_tk_jkk": {
+                    "type": "number"
+                },
+                "bpjs_tk_jkm": {
+                    "type": "number"
+                },
+                "bpjs_tk_jp_company": {
+                    "type": "number"
+                },
+                "bpjs_tk_jp_employee": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "effective_date": {
+                    "type": "string"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "housing_allowance": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "meal_allowance": {
+                    "type": "number"
+                },
+                "position_allowance": {
+                    "type": "number"
+                },
+                "transport_allowance": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.EndVisitRequest": {
+            "type": "object",
+            "properties": {
+                "photos": {
+                    "description": "additional photos appended to existing",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "result_notes": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GeneratePayrollRequest": {
+            "type": "object",
+            "required": [
+                "employee_id",
+                "month",
+                "year"
+            ],
+            "properties": {
+                "employee_id": {
+                    "type": "string"
+                },
+                "month": {
+                    "type": "integer"
                 },
-                "last_education": {
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GeneratePayrollRunRequest": {
+            "type": "object",
+            "required": [
+                "company_id",
+                "month",
+                "year"
+            ],
+            "properties": {
+                "company_id": {
                     "type": "string"
                 },
-                "marital_status": {
-                    "type": "string"
+                "month": {
+                    "type": "integer"
                 },
-                "nik": {
+                "notes": {
                     "type": "string"
                 },
-                "npwp": {
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GradeResponse": {
+            "type": "object",
+            "properties": {
+                "company_id": {
                     "type": "string"
                 },
-                "position_id": {
+                "created_at": {
                     "type": "string"
                 },
-                "religion": {
+                "description": {
                     "type": "string"
                 },
-                "shift_id": {
+                "id": {
                     "type": "string"
                 },
-                "user_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "dto.CreateEmployeeSalaryRequest": {
-            "type": "object",
-            "required": [
-                "basic_salary",
-                "effective_date",
-                "employee_id"
-            ],
-            "properties": {
-                "basic_salary": {
-                    "type": "number"
+                "is_active": {
+                    "type": "boolean"
                 },
-                "effective_date": {
+                "job_level_id": {
                     "type": "string"
                 },
-                "employee_id": {
+                "job_level_name": {
                     "type": "string"
                 },
-                "housing_allowance": {
+                "max_salary": {
                     "type": "number"
                 },
-                "meal_allowance": {
+                "min_salary": {
                     "type": "number"
                 },
-                "position_allowance": {
-                    "type": "number"
+                "name": {
+                    "type": "string"
                 },
-                "transport_allowance": {
-                    "type": "number"
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "dto.CreateHolidayRequest": {
+        "dto.HolidayResponse": {
             "type": "object",
-            "required": [
-                "company_id",
-                "date",
-                "name"
-            ],
             "properties": {
+                "company": {
+                    "$ref": "#/definitions/dto.CompanyResponse"
+                },
                 "company_id": {
                     "type": "string"
                 },
+                "created_at": {
+                    "type": "string"
+                },
                 "date": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "string"
+                },
                 "is_national": {
                     "type": "boolean"
                 },
                 "name": {
                     "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "dto.CreateLeaveRequest": {
+        "dto.JobLevelResponse": {
             "type": "object",
-            "required": [
-                "employee_id",
-                "end_date",
-                "leave_type",
-                "reason",
-                "start_date",
-                "total_days"
-            ],
             "properties": {
-                "attachment": {
+                "company_id": {
                     "type": "string"
                 },
-                "employee_id": {
+                "created_at": {
                     "type": "string"
                 },
-                "end_date": {
+                "description": {
                     "type": "string"
                 },
-                "leave_type": {
-                    "$ref": "#/definitions/model.LeaveType"
-                },
-                "reason": {
+                "id": {
                     "type": "string"
                 },
-                "start_date": {
-                    "type": "string"
+                "is_active": {
+                    "type": "boolean"
                 },
-                "total_days": {
+                "level_order": {
                     "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "dto.CreatePositionRequest": {
+        "dto.LeaveAccrualRequest": {
             "type": "object",
             "required": [
                 "company_id",
-                "name"
+                "year"
             ],
             "properties": {
-                "base_salary": {
-                    "type": "number"
-                },
                 "company_id": {
                     "type": "string"
                 },
-                "department_id": {
-                    "type": "string"
+                "month": {
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "year": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.CreateShiftRequest": {
+        "dto.LeaveAdjustmentRequest": {
             "type": "object",
             "required": [
-                "company_id",
-                "end_time",
-                "name",
-                "start_time"
+                "days",
+                "description",
+                "employee_id",
+                "leave_type",
+                "year"
             ],
             "properties": {
-                "company_id": {
-                    "type": "string"
+                "days": {
+                    "type": "number"
                 },
-                "end_time": {
+                "description": {
                     "type": "string"
                 },
-                "name": {
+                "employee_id": {
                     "type": "string"
                 },
-                "start_time": {
-                    "type": "string"
+                "leave_type": {
+                    "$ref": "#/definitions/model.LeaveType"
+                },
+                "year": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.CreateUserRequest": {
+        "dto.LeaveApprovalResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "name",
-                "password",
-                "role"
-            ],
             "properties": {
-                "address": {
-                    "type": "string"
+                "approver_role": {
+                    "$ref": "#/definitions/model.Role"
                 },
-                "email": {
-                    "type": "string"
+                "approver_type": {
+                    "$ref": "#/definitions/model.LeaveApproverType"
                 },
-                "name": {
+                "approver_user_id": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
+                "comment": {
+                    "type": "string"
                 },
-                "phone": {
+                "decided_at": {
                     "type": "string"
                 },
-                "role": {
-                    "enum": [
-                        "superadmin",
-                        "admin",
-                        "hr",
-                        "employee"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.Role"
-                        }
-                    ]
-                }
-            }
-        },
-        "dto.CreateVisitPlanRequest": {
-            "type": "object",
-            "required": [
-                "employee_id",
-                "plan_date"
-            ],
-            "properties": {
-                "employee_id": {
+                "decided_by": {
                     "type": "string"
                 },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.VisitPlanItemInput"
-                    }
+                "decider": {
+                    "$ref": "#/definitions/dto.UserResponse"
                 },
-                "notes": {
+                "delegated_from": {
                     "type": "string"
                 },
-                "plan_date": {
-                    "description": "YYYY-MM-DD",
+                "id": {
                     "type": "string"
                 },
                 "status": {
-                    "description": "optional, defaults to draft",
-                    "type": "string"
+                    "$ref": "#/definitions/model.LeaveApprovalStatus"
+                },
+                "step_order": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.DeleteMultipleCompaniesRequest": {
+        "dto.LeaveBalanceJobResponse": {
             "type": "object",
-            "required": [
-                "ids"
-            ],
             "properties": {
-                "ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "employees_processed": {
+                    "type": "integer"
+                },
+                "entries_created": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.DepartmentResponse": {
+        "dto.LeaveBalanceResponse": {
             "type": "object",
             "properties": {
-                "company": {
-                    "$ref": "#/definitions/dto.CompanyResponse"
+                "accrued": {
+                    "type": "number"
                 },
-                "company_id": {
-                    "type": "string"
+                "adjusted": {
+                    "type": "number"
                 },
-                "created_at": {
-                    "type": "string"
+                "available": {
+                    "type": "number"
                 },
-                "description": {
-                    "type": "string"
+                "balance": {
+                    "type": "number"
                 },
-                "id": {
+                "carried_over": {
+                    "type": "number"
+                },
+                "employee_id": {
                     "type": "string"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "entitlement": {
+                    "type": "number"
                 },
-                "name": {
-                    "type": "string"
+                "expired": {
+                    "type": "number"
+                },
+                "leave_type": {
+                    "$ref": "#/definitions/model.LeaveType"
+                },
+                "pending": {
+                    "type": "number"
+                },
+                "requires_balance": {
+                    "type": "boolean"
                 },
-                "updated_at": {
-                    "type": "string"
+                "taken": {
+                    "type": "number"
+                },
+                "year": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.EmployeeResponse": {
+        "dto.LeaveDelegationResponse": {
             "type": "object",
             "properties": {
-                "bank_account": {
+                "created_at": {
                     "type": "string"
                 },
-                "bank_name": {
+                "created_by": {
                     "type": "string"
                 },
-                "birth_date": {
-                    "type": "string"
+                "delegate": {
+                    "$ref": "#/definitions/dto.UserResponse"
                 },
-                "birth_place": {
+                "delegate_id": {
                     "type": "string"
                 },
-                "blood_type": {
-                    "type": "string"
+                "delegator": {
+                    "$ref": "#/definitions/dto.UserResponse"
                 },
-                "bpjs_kes_no": {
+                "delegator_id": {
                     "type": "string"
                 },
-                "bpjs_tk_no": {
+                "end_date": {
                     "type": "string"
                 },
-                "company": {
-                    "$ref": "#/definitions/dto.CompanyResponse"
-                },
-                "company_id": {
+                "id": {
                     "type": "string"
                 },
-                "contract_end_date": {
+                "reason": {
                     "type": "string"
                 },
-                "contract_start_date": {
+                "start_date": {
                     "type": "string"
-                },
+                }
+            }
+        },
+        "dto.LeaveEntitlementResponse": {
+            "type": "object",
+            "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "department": {
-                    "$ref": "#/definitions/dto.DepartmentResponse"
-                },
-                "department_id": {
-                    "type": "string"
-                },
-                "employee_number": {
-                    "type": "string"
-                },
-                "employee_status": {
-                    "$ref": "#/definitions/model.EmployeeStatus"
-                },
-                "gender": {
-                    "type": "string"
-                },
-                "grade": {
-                    "$ref": "#/definitions/dto.GradeResponse"
+                "days": {
+                    "type": "number"
                 },
-                "grade_id": {
+                "employee_id": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "job_level": {
-                    "$ref": "#/definitions/dto.JobLevelResponse"
+                "leave_type": {
+                    "$ref": "#/definitions/model.LeaveType"
                 },
-                "job_level_id": {
+                "notes": {
                     "type": "string"
                 },
-                "join_date": {
+                "updated_at": {
                     "type": "string"
                 },
-                "last_education": {
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.LeaveLedgerEntryResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
                 },
-                "marital_status": {
+                "created_by": {
                     "type": "string"
                 },
-                "nik": {
-                    "type": "string"
+                "days": {
+                    "type": "number"
                 },
-                "npwp": {
+                "description": {
                     "type": "string"
                 },
-                "position": {
-                    "$ref": "#/definitions/dto.PositionResponse"
-                },
-                "position_id": {
+                "employee_id": {
                     "type": "string"
                 },
-                "religion": {
-                    "type": "string"
+                "entry_type": {
+                    "$ref": "#/definitions/model.LeaveLedgerEntryType"
                 },
-                "resign_date": {
+                "expires_at": {
                     "type": "string"
                 },
-                "shift": {
-                    "$ref": "#/definitions/dto.ShiftResponse"
-                },
-                "shift_id": {
+                "id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "leave_id": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/dto.UserResponse"
+                "leave_type": {
+                    "$ref": "#/definitions/model.LeaveType"
                 },
-                "user_id": {
+                "reference": {
                     "type": "string"
+                },
+                "year": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.EmployeeSalaryResponse": {
+        "dto.LeavePolicyResponse": {
             "type": "object",
             "properties": {
-                "basic_salary": {
-                    "type": "number"
-                },
-                "bpjs_kes_company": {
-                    "type": "number"
-                },
-                "bpjs_kes_employee": {
-                    "type": "number"
-                },
-                "bpjs_tk_jht_company": {
-                    "type": "number"
-                },
-                "bpjs_tk_jht_employee": {
-                    "type": "number"
-                },
-                "bpjs_tk_jkk": {
-                    "type": "number"
+                "accrual_method": {
+                    "$ref": "#/definitions/model.LeaveAccrualMethod"
                 },
-                "bpjs_tk_jkm": {
-                    "type": "number"
-                },
-                "bpjs_tk_jp_company": {
+                "annual_days": {
                     "type": "number"
                 },
-                "bpjs_tk_jp_employee": {
-                    "type": "number"
-                },
-                "created_at": {
-                    "type": "string"
+                "carry_over_expiry_months": {
+                    "type": "integer"
                 },
-                "effective_date": {
+                "company_id": {
                     "type": "string"
                 },
-                "employee_id": {
+                "created_at": {
                     "type": "string"
                 },
-                "housing_allowance": {
-                    "type": "number"
-                },
                 "id": {
                     "type": "string"
                 },
-                "meal_allowance": {
-                    "type": "number"
+                "is_active": {
+                    "type": "boolean"
                 },
-                "position_allowance": {
-                    "type": "number"
+                "leave_type": {
+                    "$ref": "#/definitions/model.LeaveType"
                 },
-                "transport_allowance": {
+                "max_carry_over_days": {
                     "type": "number"
                 },
+                "requires_balance": {
+                    "type": "boolean"
+                },
                 "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "dto.EndVisitRequest": {
+        "dto.LeaveResponse": {
             "type": "object",
             "properties": {
-                "photos": {
-                    "description": "additional photos appended to existing",
+                "approvals": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/dto.LeaveApprovalResponse"
                     }
                 },
-                "result_notes": {
+                "approved_at": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.GeneratePayrollRequest": {
-            "type": "object",
-            "required": [
-                "employee_id",
-                "month",
-                "year"
-            ],
-            "properties": {
-                "employee_id": {
+                },
+                "approved_by": {
                     "type": "string"
                 },
-                "month": {
-                    "type": "integer"
+                "approver": {
+                    "$ref": "#/definitions/dto.UserResponse"
                 },
-                "year": {
-                    "type": "integer"
-                }
-            }
-        },
-        "dto.GradeResponse": {
-            "type": "object",
-            "properties": {
-                "company_id": {
+                "attachment": {
+                    "type": "string"
+                },
+                "cancel_reason": {
+                    "type": "string"
+                },
+                "cancelled_at": {
+                    "type": "string"
+                },
+                "cancelled_by": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "description": {
+                "employee": {
+                    "$ref": "#/definitions/dto.EmployeeResponse"
+                },
+                "employee_id": {
                     "type": "string"
                 },
-                "id": {
+                "end_date": {
                     "type": "string"
                 },
-                "is_active": {
+                "end_half_day": {
                     "type": "boolean"
                 },
-                "job_level_id": {
+                "id": {
                     "type": "string"
                 },
-                "job_level_name": {
-                    "type": "string"
+                "leave_type": {
+                    "$ref": "#/definitions/model.LeaveType"
                 },
-                "max_salary": {
-                    "type": "number"
+                "reason": {
+                    "type": "string"
                 },
-                "min_salary": {
-                    "type": "number"
+                "rejection_reason": {
+                    "type": "string"
                 },
-                "name": {
+                "start_date": {
                     "type": "string"
                 },
+                "start_half_day": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.LeaveStatus"
+                },
+                "total_days": {
+                    "type": "number"
+                },
                 "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "dto.HolidayResponse": {
+        "dto.LeaveWorkflowResponse": {
             "type": "object",
             "properties": {
                 "company": {
@@ -6055,101 +12474,109 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
-                "date": {
+                "department": {
+                    "$ref": "#/definitions/dto.DepartmentResponse"
+                },
+                "department_id": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "is_national": {
+                "is_active": {
                     "type": "boolean"
                 },
                 "name": {
                     "type": "string"
                 },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LeaveWorkflowStepResponse"
+                    }
+                },
                 "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "dto.JobLevelResponse": {
+        "dto.LeaveWorkflowStepRequest": {
             "type": "object",
+            "required": [
+                "approver_type"
+            ],
             "properties": {
-                "company_id": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "is_active": {
-                    "type": "boolean"
-                },
-                "level_order": {
-                    "type": "integer"
+                "approver_role": {
+                    "$ref": "#/definitions/model.Role"
                 },
-                "name": {
-                    "type": "string"
+                "approver_type": {
+                    "$ref": "#/definitions/model.LeaveApproverType"
                 },
-                "updated_at": {
+                "approver_user_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.LeaveResponse": {
+        "dto.LeaveWorkflowStepResponse": {
             "type": "object",
             "properties": {
-                "approved_at": {
-                    "type": "string"
-                },
-                "approved_by": {
-                    "type": "string"
+                "approver_role": {
+                    "$ref": "#/definitions/model.Role"
                 },
-                "approver": {
-                    "$ref": "#/definitions/dto.UserResponse"
+                "approver_type": {
+                    "$ref": "#/definitions/model.LeaveApproverType"
                 },
-                "attachment": {
+                "approver_user_id": {
                     "type": "string"
                 },
-                "created_at": {
+                "id": {
                     "type": "string"
                 },
-                "employee": {
-                    "$ref": "#/definitions/dto.EmployeeResponse"
+                "step_order": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.LeaveYearCloseRequest": {
+            "type": "object",
+            "required": [
+                "company_id",
+                "year"
+            ],
+            "properties": {
+                "company_id": {
+                    "type": "string"
                 },
-                "employee_id": {
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.LoginAttemptResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
                 },
-                "end_date": {
+                "email": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "leave_type": {
-                    "$ref": "#/definitions/model.LeaveType"
-                },
-                "reason": {
+                "ip_address": {
                     "type": "string"
                 },
-                "rejection_reason": {
+                "result": {
                     "type": "string"
                 },
-                "start_date": {
+                "user_agent": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/model.LeaveStatus"
-                },
-                "total_days": {
-                    "type": "integer"
+                "user_id": {
+                    "type": "string"
                 },
-                "updated_at": {
+                "user_name": {
                     "type": "string"
                 }
             }
@@ -6232,9 +12659,67 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.NotificationBulkResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.NotificationIDsRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.NotificationPageResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.NotificationResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.NotificationPreferenceRequest": {
+            "type": "object",
+            "properties": {
+                "event_type": {
+                    "type": "string"
+                },
+                "mode": {
+                    "$ref": "#/definitions/model.NotificationMode"
+                }
+            }
+        },
+        "dto.NotificationPreferenceResponse": {
+            "type": "object",
+            "properties": {
+                "event_type": {
+                    "type": "string"
+                },
+                "mode": {
+                    "$ref": "#/definitions/model.NotificationMode"
+                }
+            }
+        },
         "dto.NotificationResponse": {
             "type": "object",
             "properties": {
+                "archived_at": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -6244,6 +12729,10 @@ const docTemplate = `{
                 "is_read": {
                     "type": "boolean"
                 },
+                "link": {
+                    "description": "frontend route of the record it refers to",
+                    "type": "string"
+                },
                 "message": {
                     "type": "string"
                 },
@@ -6264,6 +12753,78 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.OIDCLinkResponse": {
+            "type": "object",
+            "properties": {
+                "authorization_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OIDCLoginOptionResponse": {
+            "type": "object",
+            "properties": {
+                "login_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OIDCProviderResponse": {
+            "type": "object",
+            "properties": {
+                "allowed_domains": {
+                    "type": "string"
+                },
+                "auto_provision": {
+                    "type": "boolean"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "company": {
+                    "$ref": "#/definitions/dto.CompanyResponse"
+                },
+                "company_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "default_role": {
+                    "type": "string"
+                },
+                "has_client_secret": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "issuer_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.OrgDepartmentNode": {
             "type": "object",
             "properties": {
@@ -6295,47 +12856,139 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.OrgPositionNode": {
+        "dto.OrgPositionNode": {
+            "type": "object",
+            "properties": {
+                "employees": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.OrgEmployeeNode"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OrgStructureResponse": {
+            "type": "object",
+            "properties": {
+                "company_id": {
+                    "type": "string"
+                },
+                "company_name": {
+                    "type": "string"
+                },
+                "departments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.OrgDepartmentNode"
+                    }
+                }
+            }
+        },
+        "dto.PaginatedAnnouncementResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AnnouncementResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PaginatedAttendanceResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AttendanceResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PaginatedCompanyResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CompanyResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PaginatedDeadLetterResponse": {
             "type": "object",
             "properties": {
-                "employees": {
+                "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.OrgEmployeeNode"
+                        "$ref": "#/definitions/dto.DeadLetterResponse"
                     }
                 },
-                "id": {
-                    "type": "string"
+                "limit": {
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "dto.OrgStructureResponse": {
-            "type": "object",
-            "properties": {
-                "company_id": {
-                    "type": "string"
+                "page": {
+                    "type": "integer"
                 },
-                "company_name": {
-                    "type": "string"
+                "total_items": {
+                    "type": "integer"
                 },
-                "departments": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.OrgDepartmentNode"
-                    }
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.PaginatedAttendanceResponse": {
+        "dto.PaginatedEmailDeliveryResponse": {
             "type": "object",
             "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.AttendanceResponse"
+                        "$ref": "#/definitions/dto.EmailDeliveryResponse"
                     }
                 },
                 "limit": {
@@ -6352,13 +13005,13 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.PaginatedCompanyResponse": {
+        "dto.PaginatedLoginAttemptResponse": {
             "type": "object",
             "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.CompanyResponse"
+                        "$ref": "#/definitions/dto.LoginAttemptResponse"
                     }
                 },
                 "limit": {
@@ -6452,9 +13105,18 @@ const docTemplate = `{
                 "present_days": {
                     "type": "integer"
                 },
+                "ptkp_status": {
+                    "type": "string"
+                },
                 "status": {
                     "$ref": "#/definitions/model.PayrollStatus"
                 },
+                "taxable_income": {
+                    "type": "number"
+                },
+                "ter_category": {
+                    "type": "string"
+                },
                 "thr": {
                     "type": "number"
                 },
@@ -6472,6 +13134,108 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.PayrollRunEmployeeResult": {
+            "type": "object",
+            "properties": {
+                "employee_id": {
+                    "type": "string"
+                },
+                "employee_name": {
+                    "type": "string"
+                },
+                "employee_number": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "type": "string"
+                },
+                "payroll_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PayrollRunGenerateResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PayrollRunEmployeeResult"
+                    }
+                },
+                "run": {
+                    "$ref": "#/definitions/dto.PayrollRunResponse"
+                }
+            }
+        },
+        "dto.PayrollRunResponse": {
+            "type": "object",
+            "properties": {
+                "company_id": {
+                    "type": "string"
+                },
+                "company_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "created_count": {
+                    "type": "integer"
+                },
+                "failed_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "payrolls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PayrollResponse"
+                    }
+                },
+                "period_month": {
+                    "type": "integer"
+                },
+                "period_year": {
+                    "type": "integer"
+                },
+                "processed_at": {
+                    "type": "string"
+                },
+                "skipped_count": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.PayrollStatus"
+                },
+                "total_employees": {
+                    "type": "integer"
+                },
+                "total_gross": {
+                    "type": "number"
+                },
+                "total_net": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.PayrollStatusRequest": {
             "type": "object",
             "required": [
@@ -6483,6 +13247,23 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.PermissionResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "module": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.PositionResponse": {
             "type": "object",
             "properties": {
@@ -6518,6 +13299,124 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.PublishAnnouncementRequest": {
+            "type": "object",
+            "properties": {
+                "publish_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.ReplayDeadLettersRequest": {
+            "type": "object",
+            "properties": {
+                "event_type": {
+                    "type": "string"
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.ReplayDeadLettersResponse": {
+            "type": "object",
+            "properties": {
+                "replayed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "token"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ServiceAccountResponse": {
+            "type": "object",
+            "properties": {
+                "company": {
+                    "$ref": "#/definitions/dto.CompanyResponse"
+                },
+                "company_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "custom_role_id": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/model.Role"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.SessionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.SetCompanyModuleRequest": {
             "type": "object",
             "properties": {
@@ -6525,8 +13424,33 @@ const docTemplate = `{
                     "description": "raw JSON string; empty = keep existing",
                     "type": "string"
                 },
-                "enabled": {
-                    "type": "boolean"
+                "enabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.SetLeaveEntitlementRequest": {
+            "type": "object",
+            "required": [
+                "employee_id",
+                "leave_type",
+                "year"
+            ],
+            "properties": {
+                "days": {
+                    "type": "number"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "leave_type": {
+                    "$ref": "#/definitions/model.LeaveType"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "year": {
+                    "type": "integer"
                 }
             }
         },
@@ -6548,6 +13472,20 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.SetUserCompaniesRequest": {
+            "type": "object",
+            "required": [
+                "company_ids"
+            ],
+            "properties": {
+                "company_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "dto.ShiftResponse": {
             "type": "object",
             "properties": {
@@ -6577,6 +13515,9 @@ const docTemplate = `{
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "work_days": {
+                    "type": "string"
                 }
             }
         },
@@ -6623,11 +13564,54 @@ const docTemplate = `{
                 "access_token": {
                     "type": "string"
                 },
+                "challenge_token": {
+                    "type": "string"
+                },
                 "expires_in": {
                     "type": "integer"
                 },
                 "token_type": {
                     "type": "string"
+                },
+                "two_factor_required": {
+                    "description": "TwoFactorRequired is set instead of the access token when the login\nmust be completed with a code at /auth/2fa/verify",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.TwoFactorCodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TwoFactorSetupResponse": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "string"
+                },
+                "uri": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TwoFactorStatusResponse": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "recovery_codes_left": {
+                    "type": "integer"
+                },
+                "required": {
+                    "type": "boolean"
                 }
             }
         },
@@ -6639,6 +13623,41 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.UpdateAnnouncementRequest": {
+            "type": "object",
+            "properties": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AnnouncementAttachmentInput"
+                    }
+                },
+                "body": {
+                    "type": "string"
+                },
+                "department_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expire_at": {
+                    "type": "string"
+                },
+                "is_pinned": {
+                    "type": "boolean"
+                },
+                "job_level_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.UpdateAttendanceRequest": {
             "type": "object",
             "properties": {
@@ -6691,12 +13710,32 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.UpdateCustomRoleRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "dto.UpdateDepartmentRequest": {
             "type": "object",
             "properties": {
                 "description": {
                     "type": "string"
                 },
+                "head_id": {
+                    "type": "string"
+                },
                 "is_active": {
                     "type": "boolean"
                 },
@@ -6738,6 +13777,9 @@ const docTemplate = `{
                 "department_id": {
                     "type": "string"
                 },
+                "dependents": {
+                    "type": "integer"
+                },
                 "employee_status": {
                     "$ref": "#/definitions/model.EmployeeStatus"
                 },
@@ -6773,6 +13815,9 @@ const docTemplate = `{
                 },
                 "shift_id": {
                     "type": "string"
+                },
+                "supervisor_id": {
+                    "type": "string"
                 }
             }
         },
@@ -6813,6 +13858,29 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.UpdateLeavePolicyRequest": {
+            "type": "object",
+            "properties": {
+                "accrual_method": {
+                    "$ref": "#/definitions/model.LeaveAccrualMethod"
+                },
+                "annual_days": {
+                    "type": "number"
+                },
+                "carry_over_expiry_months": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_carry_over_days": {
+                    "type": "number"
+                },
+                "requires_balance": {
+                    "type": "boolean"
+                }
+            }
+        },
         "dto.UpdateLeaveRequest": {
             "type": "object",
             "properties": {
@@ -6822,6 +13890,9 @@ const docTemplate = `{
                 "end_date": {
                     "type": "string"
                 },
+                "end_half_day": {
+                    "type": "boolean"
+                },
                 "leave_type": {
                     "$ref": "#/definitions/model.LeaveType"
                 },
@@ -6831,8 +13902,68 @@ const docTemplate = `{
                 "start_date": {
                     "type": "string"
                 },
-                "total_days": {
-                    "type": "integer"
+                "start_half_day": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.UpdateLeaveWorkflowRequest": {
+            "type": "object",
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LeaveWorkflowStepRequest"
+                    }
+                }
+            }
+        },
+        "dto.UpdateNotificationPreferencesRequest": {
+            "type": "object",
+            "properties": {
+                "preferences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.NotificationPreferenceRequest"
+                    }
+                }
+            }
+        },
+        "dto.UpdateOIDCProviderRequest": {
+            "type": "object",
+            "properties": {
+                "allowed_domains": {
+                    "type": "string"
+                },
+                "auto_provision": {
+                    "type": "boolean"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "client_secret": {
+                    "type": "string"
+                },
+                "default_role": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "issuer_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "string"
                 }
             }
         },
@@ -6870,6 +14001,23 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.UpdateServiceAccountRequest": {
+            "type": "object",
+            "properties": {
+                "custom_role_id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/model.Role"
+                }
+            }
+        },
         "dto.UpdateShiftRequest": {
             "type": "object",
             "properties": {
@@ -6884,6 +14032,9 @@ const docTemplate = `{
                 },
                 "start_time": {
                     "type": "string"
+                },
+                "work_days": {
+                    "type": "string"
                 }
             }
         },
@@ -6893,12 +14044,21 @@ const docTemplate = `{
                 "address": {
                     "type": "string"
                 },
+                "custom_role_id": {
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
                 "is_active": {
                     "type": "boolean"
                 },
+                "language": {
+                    "type": "string"
+                },
+                "must_change_password": {
+                    "type": "boolean"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -6948,6 +14108,17 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.UserCompanyResponse": {
+            "type": "object",
+            "properties": {
+                "company_id": {
+                    "type": "string"
+                },
+                "company_name": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.UserMenuKeysResponse": {
             "type": "object",
             "properties": {
@@ -6968,6 +14139,9 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
+                "custom_role_id": {
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
@@ -6977,6 +14151,18 @@ const docTemplate = `{
                 "is_active": {
                     "type": "boolean"
                 },
+                "is_service_account": {
+                    "type": "boolean"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "locked_until": {
+                    "type": "string"
+                },
+                "must_change_password": {
+                    "type": "boolean"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -6991,6 +14177,22 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.VerifyTwoFactorRequest": {
+            "type": "object",
+            "required": [
+                "challenge_token",
+                "code"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "code": {
+                    "description": "Code is a TOTP code or a recovery code",
+                    "type": "string"
+                }
+            }
+        },
         "dto.VisitAdherenceReport": {
             "type": "object",
             "properties": {
@@ -7234,17 +14436,84 @@ const docTemplate = `{
                 "StatusInternship"
             ]
         },
+        "model.LeaveAccrualMethod": {
+            "type": "string",
+            "enum": [
+                "annual",
+                "monthly"
+            ],
+            "x-enum-varnames": [
+                "LeaveAccrualAnnual",
+                "LeaveAccrualMonthly"
+            ]
+        },
+        "model.LeaveApprovalStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "rejected",
+                "skipped"
+            ],
+            "x-enum-varnames": [
+                "LeaveApprovalPending",
+                "LeaveApprovalApproved",
+                "LeaveApprovalRejected",
+                "LeaveApprovalSkipped"
+            ]
+        },
+        "model.LeaveApproverType": {
+            "type": "string",
+            "enum": [
+                "supervisor",
+                "department_head",
+                "role",
+                "user"
+            ],
+            "x-enum-varnames": [
+                "LeaveApproverSupervisor",
+                "LeaveApproverDepartmentHead",
+                "LeaveApproverRole",
+                "LeaveApproverUser"
+            ]
+        },
+        "model.LeaveLedgerEntryType": {
+            "type": "string",
+            "enum": [
+                "accrual",
+                "carry_over",
+                "expiry",
+                "taken",
+                "adjustment",
+                "reversal"
+            ],
+            "x-enum-varnames": [
+                "LeaveLedgerAccrual",
+                "LeaveLedgerCarryOver",
+                "LeaveLedgerExpiry",
+                "LeaveLedgerTaken",
+                "LeaveLedgerAdjustment",
+                "LeaveLedgerReversal"
+            ]
+        },
         "model.LeaveStatus": {
             "type": "string",
             "enum": [
                 "pending",
+                "in_review",
                 "approved",
-                "rejected"
+                "rejected",
+                "cancelled"
             ],
+            "x-enum-comments": {
+                "LeaveStatusInReview": "approved by some, but not all, steps of the chain"
+            },
             "x-enum-varnames": [
                 "LeaveStatusPending",
+                "LeaveStatusInReview",
                 "LeaveStatusApproved",
-                "LeaveStatusRejected"
+                "LeaveStatusRejected",
+                "LeaveStatusCancelled"
             ]
         },
         "model.LeaveType": {
@@ -7266,6 +14535,21 @@ const docTemplate = `{
                 "LeaveDinasLuar"
             ]
         },
+        "model.NotificationMode": {
+            "type": "string",
+            "enum": [
+                "in_app",
+                "email",
+                "digest",
+                "off"
+            ],
+            "x-enum-varnames": [
+                "NotificationModeInApp",
+                "NotificationModeEmail",
+                "NotificationModeDigest",
+                "NotificationModeOff"
+            ]
+        },
         "model.NotificationType": {
             "type": "string",
             "enum": [
@@ -0,0 +1,159 @@
+package dto
+
+import (
+	"time"
+
+	"hris-backend/internal/model"
+)
+
+// --- Requests ---
+
+type AnnouncementAttachmentInput struct {
+	Name string `json:"name" validate:"required"`
+	URL  string `json:"url" validate:"required"` // http(s) URL of the stored file
+}
+
+// CreateAnnouncementRequest creates a draft. Without department_ids and
+// job_level_ids the announcement is for the whole company.
+type CreateAnnouncementRequest struct {
+	CompanyID     string                        `json:"company_id" validate:"required"`
+	Title         string                        `json:"title" validate:"required"`
+	Body          string                        `json:"body" validate:"required"` // HTML; unsupported markup is removed
+	IsPinned      bool                          `json:"is_pinned"`
+	DepartmentIDs []string                      `json:"department_ids"`
+	JobLevelIDs   []string                      `json:"job_level_ids"`
+	Attachments   []AnnouncementAttachmentInput `json:"attachments"`
+	ExpireAt      *time.Time                    `json:"expire_at,omitempty"`
+}
+
+// UpdateAnnouncementRequest changes the fields that are set; the lists
+// replace the current ones
+type UpdateAnnouncementRequest struct {
+	Title         *string                        `json:"title,omitempty"`
+	Body          *string                        `json:"body,omitempty"`
+	IsPinned      *bool                          `json:"is_pinned,omitempty"`
+	DepartmentIDs *[]string                      `json:"department_ids,omitempty"`
+	JobLevelIDs   *[]string                      `json:"job_level_ids,omitempty"`
+	Attachments   *[]AnnouncementAttachmentInput `json:"attachments,omitempty"`
+	ExpireAt      *time.Time                     `json:"expire_at,omitempty"`
+}
+
+// PublishAnnouncementRequest publishes at publish_at, or right away
+type PublishAnnouncementRequest struct {
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+}
+
+// --- Responses ---
+
+type AnnouncementAttachmentResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// AnnouncementResponse is an announcement. Status is draft, scheduled,
+// published or expired; IsRead is set in the announcements of the current
+// user.
+type AnnouncementResponse struct {
+	ID            string                           `json:"id"`
+	CompanyID     string                           `json:"company_id"`
+	Title         string                           `json:"title"`
+	Body          string                           `json:"body"`
+	Status        string                           `json:"status"`
+	IsPinned      bool                             `json:"is_pinned"`
+	PublishAt     *time.Time                       `json:"publish_at,omitempty"`
+	ExpireAt      *time.Time                       `json:"expire_at,omitempty"`
+	DepartmentIDs []string                         `json:"department_ids"`
+	JobLevelIDs   []string                         `json:"job_level_ids"`
+	Attachments   []AnnouncementAttachmentResponse `json:"attachments"`
+	IsRead        *bool                            `json:"is_read,omitempty"`
+	CreatedBy     string                           `json:"created_by"`
+	CreatedAt     time.Time                        `json:"created_at"`
+	UpdatedAt     time.Time                        `json:"updated_at"`
+}
+
+type PaginatedAnnouncementResponse struct {
+	Data       []AnnouncementResponse `json:"data"`
+	Page       int                    `json:"page"`
+	Limit      int                    `json:"limit"`
+	TotalItems int64                  `json:"total_items"`
+	TotalPages int                    `json:"total_pages"`
+}
+
+// AnnouncementReadReportResponse tells how many of the employees an
+// announcement is for read it, and lists the ones who did not
+type AnnouncementReadReportResponse struct {
+	AnnouncementID string                     `json:"announcement_id"`
+	AudienceTotal  int64                      `json:"audience_total"`
+	ReadCount      int64                      `json:"read_count"`
+	UnreadCount    int64                      `json:"unread_count"`
+	Unread         []AnnouncementUnreadReader `json:"unread"`
+}
+
+type AnnouncementUnreadReader struct {
+	EmployeeID     string `json:"employee_id"`
+	EmployeeNumber string `json:"employee_number"`
+	Name           string `json:"name"`
+	Email          string `json:"email"`
+	DepartmentName string `json:"department_name"`
+}
+
+// AnnouncementStatus is the status of an announcement at the given time
+func AnnouncementStatus(a *model.Announcement, now time.Time) string {
+	switch {
+	case a.Status != model.AnnouncementPublished || a.PublishAt == nil:
+		return string(model.AnnouncementDraft)
+	case a.PublishAt.After(now):
+		return "scheduled"
+	case a.ExpireAt != nil && !a.ExpireAt.After(now):
+		return "expired"
+	}
+	return string(model.AnnouncementPublished)
+}
+
+func ToAnnouncementResponse(a *model.Announcement) AnnouncementResponse {
+	resp := AnnouncementResponse{
+		ID:            a.ID,
+		CompanyID:     a.CompanyID,
+		Title:         a.Title,
+		Body:          a.Body,
+		Status:        AnnouncementStatus(a, time.Now()),
+		IsPinned:      a.IsPinned,
+		PublishAt:     a.PublishAt,
+		ExpireAt:      a.ExpireAt,
+		DepartmentIDs: a.TargetIDs(model.AnnouncementTargetDepartment),
+		JobLevelIDs:   a.TargetIDs(model.AnnouncementTargetJobLevel),
+		Attachments:   make([]AnnouncementAttachmentResponse, len(a.Attachments)),
+		CreatedBy:     a.CreatedBy,
+		CreatedAt:     a.CreatedAt,
+		UpdatedAt:     a.UpdatedAt,
+	}
+	if resp.DepartmentIDs == nil {
+		resp.DepartmentIDs = []string{}
+	}
+	if resp.JobLevelIDs == nil {
+		resp.JobLevelIDs = []string{}
+	}
+	for i, att := range a.Attachments {
+		resp.Attachments[i] = AnnouncementAttachmentResponse{ID: att.ID, Name: att.Name, URL: att.URL}
+	}
+	return resp
+}
+
+func ToAnnouncementResponses(announcements []model.Announcement) []AnnouncementResponse {
+	responses := make([]AnnouncementResponse, len(announcements))
+	for i := range announcements {
+		responses[i] = ToAnnouncementResponse(&announcements[i])
+	}
+	return responses
+}
+
+func ToAnnouncementUnreadReader(e *model.Employee) AnnouncementUnreadReader {
+	return AnnouncementUnreadReader{
+		EmployeeID:     e.ID,
+		EmployeeNumber: e.EmployeeNumber,
+		Name:           e.User.Name,
+		Email:          e.User.Email,
+		DepartmentName: e.Department.Name,
+	}
+}
@@ -0,0 +1,123 @@
+package dto
+
+import (
+	"time"
+
+	"hris-backend/internal/model"
+)
+
+type CreateAPITokenRequest struct {
+	Name        string   `json:"name" validate:"required"`
+	CompanyID   string   `json:"company_id"`
+	Permissions []string `json:"permissions" validate:"required"`
+	// ExpiresInDays defaults to 90 and is at most 365
+	ExpiresInDays int `json:"expires_in_days"`
+}
+
+type APITokenResponse struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Prefix      string           `json:"prefix"`
+	CompanyID   string           `json:"company_id"`
+	Company     *CompanyResponse `json:"company,omitempty"`
+	Permissions []string         `json:"permissions"`
+	ExpiresAt   time.Time        `json:"expires_at"`
+	Expired     bool             `json:"expired"`
+	LastUsedAt  *time.Time       `json:"last_used_at,omitempty"`
+	LastUsedIP  string           `json:"last_used_ip,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
+
+// CreatedAPITokenResponse carries the token itself, which is shown only once
+type CreatedAPITokenResponse struct {
+	APITokenResponse
+	Token string `json:"token"`
+}
+
+// APITokenIdentity is who an authenticated API token acts for
+type APITokenIdentity struct {
+	TokenID     string
+	UserID      string
+	Email       string
+	Role        string
+	CompanyID   string
+	Permissions []string
+}
+
+type CreateServiceAccountRequest struct {
+	CompanyID    string     `json:"company_id" validate:"required"`
+	Name         string     `json:"name" validate:"required"`
+	Role         model.Role `json:"role" validate:"omitempty,oneof=admin hr employee"`
+	CustomRoleID string     `json:"custom_role_id"`
+}
+
+type UpdateServiceAccountRequest struct {
+	Name         string     `json:"name"`
+	Role         model.Role `json:"role"`
+	CustomRoleID *string    `json:"custom_role_id"`
+	IsActive     *bool      `json:"is_active"`
+}
+
+type ServiceAccountResponse struct {
+	ID           string           `json:"id"`
+	Name         string           `json:"name"`
+	Email        string           `json:"email"`
+	Role         model.Role       `json:"role"`
+	CustomRoleID string           `json:"custom_role_id,omitempty"`
+	CompanyID    string           `json:"company_id"`
+	Company      *CompanyResponse `json:"company,omitempty"`
+	IsActive     bool             `json:"is_active"`
+	CreatedAt    string           `json:"created_at"`
+	UpdatedAt    string           `json:"updated_at"`
+}
+
+func ToAPITokenResponse(t *model.APIToken) APITokenResponse {
+	resp := APITokenResponse{
+		ID:          t.ID,
+		Name:        t.Name,
+		Prefix:      t.Prefix,
+		CompanyID:   t.CompanyID,
+		Permissions: t.Permissions,
+		ExpiresAt:   t.ExpiresAt,
+		Expired:     time.Now().After(t.ExpiresAt),
+		LastUsedAt:  t.LastUsedAt,
+		LastUsedIP:  t.LastUsedIP,
+		CreatedAt:   t.CreatedAt,
+	}
+	if t.Company.ID != "" {
+		companyResp := ToCompanyResponse(&t.Company)
+		resp.Company = &companyResp
+	}
+	return resp
+}
+
+func ToAPITokenResponses(tokens []model.APIToken) []APITokenResponse {
+	responses := make([]APITokenResponse, len(tokens))
+	for i, t := range tokens {
+		responses[i] = ToAPITokenResponse(&t)
+	}
+	return responses
+}
+
+// ToServiceAccountResponse converts a service account and the company it is
+// bound to
+func ToServiceAccountResponse(user *model.User, company *model.Company) ServiceAccountResponse {
+	resp := ServiceAccountResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Role:      user.Role,
+		IsActive:  user.IsActive,
+		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if user.CustomRoleID != nil {
+		resp.CustomRoleID = *user.CustomRoleID
+	}
+	if company != nil {
+		resp.CompanyID = company.ID
+		companyResp := ToCompanyResponse(company)
+		resp.Company = &companyResp
+	}
+	return resp
+}
@@ -62,6 +62,9 @@ type AttendanceResponse struct {
 	ClockOutPhoto     string   `json:"clock_out_photo,omitempty"`
 	ClockOutDistanceM *float64 `json:"clock_out_distance_m,omitempty"`
 
+	// Set when the row was written by an approved leave
+	LeaveID string `json:"leave_id,omitempty"`
+
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
@@ -93,6 +96,9 @@ func ToAttendanceResponse(a *model.Attendance) AttendanceResponse {
 	if a.ClockOut != nil {
 		resp.ClockOut = a.ClockOut.Format("2006-01-02T15:04:05Z")
 	}
+	if a.LeaveID != nil {
+		resp.LeaveID = *a.LeaveID
+	}
 
 	if a.Employee.ID != "" {
 		empResp := ToEmployeeResponse(&a.Employee)
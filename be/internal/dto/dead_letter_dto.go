@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"hris-backend/internal/model"
+)
+
+type DeadLetterResponse struct {
+	ID              string `json:"id"`
+	SourceTopic     string `json:"source_topic"`
+	SourcePartition int32  `json:"source_partition"`
+	SourceOffset    int64  `json:"source_offset"`
+	EventID         string `json:"event_id,omitempty"`
+	EventType       string `json:"event_type,omitempty"`
+	CompanyID       string `json:"company_id,omitempty"`
+	Error           string `json:"error"`
+	Attempts        int    `json:"attempts"`
+	Status          string `json:"status"`
+	FailedAt        string `json:"failed_at"`
+	ReplayedAt      string `json:"replayed_at,omitempty"`
+	ReplayedBy      string `json:"replayed_by,omitempty"`
+}
+
+// DeadLetterDetailResponse adds the original message to a dead letter
+type DeadLetterDetailResponse struct {
+	DeadLetterResponse
+	Key     string            `json:"key,omitempty"`
+	Headers map[string]string `json:"headers"`
+	Message string            `json:"message"`
+}
+
+type PaginatedDeadLetterResponse struct {
+	Data       []DeadLetterResponse `json:"data"`
+	Page       int                  `json:"page"`
+	Limit      int                  `json:"limit"`
+	TotalItems int64                `json:"total_items"`
+	TotalPages int                  `json:"total_pages"`
+}
+
+// ReplayDeadLettersRequest selects pending dead letters to replay by ID, by
+// event type, or both
+type ReplayDeadLettersRequest struct {
+	IDs       []string `json:"ids"`
+	EventType string   `json:"event_type"`
+}
+
+type ReplayDeadLettersResponse struct {
+	Replayed int `json:"replayed"`
+}
+
+func ToDeadLetterResponse(e *model.DeadLetterEvent) DeadLetterResponse {
+	resp := DeadLetterResponse{
+		ID:              e.ID,
+		SourceTopic:     e.SourceTopic,
+		SourcePartition: e.SourcePartition,
+		SourceOffset:    e.SourceOffset,
+		EventID:         e.EventID,
+		EventType:       e.EventType,
+		Error:           e.Error,
+		Attempts:        e.Attempts,
+		Status:          string(e.Status),
+		FailedAt:        e.FailedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if e.CompanyID != nil {
+		resp.CompanyID = *e.CompanyID
+	}
+	if e.ReplayedAt != nil {
+		resp.ReplayedAt = e.ReplayedAt.Format("2006-01-02T15:04:05Z")
+	}
+	if e.ReplayedBy != nil {
+		resp.ReplayedBy = *e.ReplayedBy
+	}
+	return resp
+}
+
+func ToDeadLetterResponses(events []model.DeadLetterEvent) []DeadLetterResponse {
+	responses := make([]DeadLetterResponse, len(events))
+	for i := range events {
+		responses[i] = ToDeadLetterResponse(&events[i])
+	}
+	return responses
+}
+
+func ToDeadLetterDetailResponse(e *model.DeadLetterEvent) DeadLetterDetailResponse {
+	headers := make(map[string]string)
+	json.Unmarshal([]byte(e.Headers), &headers)
+
+	return DeadLetterDetailResponse{
+		DeadLetterResponse: ToDeadLetterResponse(e),
+		Key:                e.Key,
+		Headers:            headers,
+		Message:            e.Message,
+	}
+}
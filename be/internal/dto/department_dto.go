@@ -6,11 +6,13 @@ type CreateDepartmentRequest struct {
 	CompanyID   string `json:"company_id" validate:"required"`
 	Name        string `json:"name" validate:"required"`
 	Description string `json:"description"`
+	HeadID      string `json:"head_id"`
 }
 
 type UpdateDepartmentRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	HeadID      string `json:"head_id"`
 	IsActive    *bool  `json:"is_active"`
 }
 
@@ -20,6 +22,7 @@ type DepartmentResponse struct {
 	Company     *CompanyResponse `json:"company,omitempty"`
 	Name        string           `json:"name"`
 	Description string           `json:"description"`
+	HeadID      string           `json:"head_id"`
 	IsActive    bool             `json:"is_active"`
 	CreatedAt   string           `json:"created_at"`
 	UpdatedAt   string           `json:"updated_at"`
@@ -35,6 +38,9 @@ func ToDepartmentResponse(dept *model.Department) DepartmentResponse {
 		CreatedAt:   dept.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:   dept.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
+	if dept.HeadID != nil {
+		resp.HeadID = *dept.HeadID
+	}
 	if dept.Company.ID != "" {
 		companyResp := ToCompanyResponse(&dept.Company)
 		resp.Company = &companyResp
@@ -0,0 +1,83 @@
+package dto
+
+import "hris-backend/internal/model"
+
+type EmailDeliveryResponse struct {
+	ID             string `json:"id"`
+	EventID        string `json:"event_id,omitempty"`
+	EventType      string `json:"event_type"`
+	CompanyID      string `json:"company_id,omitempty"`
+	UserID         string `json:"user_id"`
+	NotificationID string `json:"notification_id,omitempty"`
+	ToAddress      string `json:"to_address"`
+	Language       string `json:"language"`
+	Subject        string `json:"subject"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	NextAttemptAt  string `json:"next_attempt_at,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+	SentAt         string `json:"sent_at,omitempty"`
+	CreatedAt      string `json:"created_at"`
+}
+
+// EmailDeliveryDetailResponse adds the bodies to an email delivery
+type EmailDeliveryDetailResponse struct {
+	EmailDeliveryResponse
+	TextBody string `json:"text_body"`
+	HTMLBody string `json:"html_body"`
+}
+
+type PaginatedEmailDeliveryResponse struct {
+	Data       []EmailDeliveryResponse `json:"data"`
+	Page       int                     `json:"page"`
+	Limit      int                     `json:"limit"`
+	TotalItems int64                   `json:"total_items"`
+	TotalPages int                     `json:"total_pages"`
+}
+
+func ToEmailDeliveryResponse(d *model.EmailDelivery) EmailDeliveryResponse {
+	resp := EmailDeliveryResponse{
+		ID:        d.ID,
+		EventType: d.EventType,
+		UserID:    d.UserID,
+		ToAddress: d.ToAddress,
+		Language:  d.Language,
+		Subject:   d.Subject,
+		Status:    string(d.Status),
+		Attempts:  d.Attempts,
+		LastError: d.LastError,
+		CreatedAt: d.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if d.EventID != nil {
+		resp.EventID = *d.EventID
+	}
+	if d.CompanyID != nil {
+		resp.CompanyID = *d.CompanyID
+	}
+	if d.NotificationID != nil {
+		resp.NotificationID = *d.NotificationID
+	}
+	if d.Status == model.EmailDeliveryPending {
+		resp.NextAttemptAt = d.NextAttemptAt.Format("2006-01-02T15:04:05Z")
+	}
+	if d.SentAt != nil {
+		resp.SentAt = d.SentAt.Format("2006-01-02T15:04:05Z")
+	}
+	return resp
+}
+
+func ToEmailDeliveryResponses(deliveries []model.EmailDelivery) []EmailDeliveryResponse {
+	responses := make([]EmailDeliveryResponse, len(deliveries))
+	for i := range deliveries {
+		responses[i] = ToEmailDeliveryResponse(&deliveries[i])
+	}
+	return responses
+}
+
+func ToEmailDeliveryDetailResponse(d *model.EmailDelivery) EmailDeliveryDetailResponse {
+	return EmailDeliveryDetailResponse{
+		EmailDeliveryResponse: ToEmailDeliveryResponse(d),
+		TextBody:              d.TextBody,
+		HTMLBody:              d.HTMLBody,
+	}
+}
@@ -12,12 +12,14 @@ type CreateEmployeeRequest struct {
 	ShiftID           string               `json:"shift_id" validate:"required"`
 	JobLevelID        string               `json:"job_level_id"`
 	GradeID           string               `json:"grade_id"`
+	SupervisorID      string               `json:"supervisor_id"`
 	EmployeeNumber    string               `json:"employee_number" validate:"required"`
 	NIK               string               `json:"nik"`
 	Gender            string               `json:"gender"`
 	BirthPlace        string               `json:"birth_place"`
 	BirthDate         string               `json:"birth_date"`
 	MaritalStatus     string               `json:"marital_status"`
+	Dependents        *int                 `json:"dependents"`
 	Religion          string               `json:"religion"`
 	BloodType         string               `json:"blood_type"`
 	LastEducation     string               `json:"last_education"`
@@ -38,11 +40,13 @@ type UpdateEmployeeRequest struct {
 	ShiftID           string               `json:"shift_id"`
 	JobLevelID        string               `json:"job_level_id"`
 	GradeID           string               `json:"grade_id"`
+	SupervisorID      string               `json:"supervisor_id"`
 	NIK               string               `json:"nik"`
 	Gender            string               `json:"gender"`
 	BirthPlace        string               `json:"birth_place"`
 	BirthDate         string               `json:"birth_date"`
 	MaritalStatus     string               `json:"marital_status"`
+	Dependents        *int                 `json:"dependents"`
 	Religion          string               `json:"religion"`
 	BloodType         string               `json:"blood_type"`
 	LastEducation     string               `json:"last_education"`
@@ -73,12 +77,14 @@ type EmployeeResponse struct {
 	JobLevel          *JobLevelResponse    `json:"job_level,omitempty"`
 	GradeID           string               `json:"grade_id"`
 	Grade             *GradeResponse       `json:"grade,omitempty"`
+	SupervisorID      string               `json:"supervisor_id"`
 	EmployeeNumber    string               `json:"employee_number"`
 	NIK               string               `json:"nik"`
 	Gender            string               `json:"gender"`
 	BirthPlace        string               `json:"birth_place"`
 	BirthDate         string               `json:"birth_date"`
 	MaritalStatus     string               `json:"marital_status"`
+	Dependents        int                  `json:"dependents"`
 	Religion          string               `json:"religion"`
 	BloodType         string               `json:"blood_type"`
 	LastEducation     string               `json:"last_education"`
@@ -109,6 +115,7 @@ func ToEmployeeResponse(emp *model.Employee) EmployeeResponse {
 		Gender:         emp.Gender,
 		BirthPlace:     emp.BirthPlace,
 		MaritalStatus:  emp.MaritalStatus,
+		Dependents:     emp.Dependents,
 		Religion:       emp.Religion,
 		BloodType:      emp.BloodType,
 		LastEducation:  emp.LastEducation,
@@ -141,6 +148,9 @@ func ToEmployeeResponse(emp *model.Employee) EmployeeResponse {
 	if emp.GradeID != nil {
 		resp.GradeID = *emp.GradeID
 	}
+	if emp.SupervisorID != nil {
+		resp.SupervisorID = *emp.SupervisorID
+	}
 
 	if emp.User.ID != "" {
 		userResp := ToUserResponse(&emp.User)
@@ -0,0 +1,191 @@
+package dto
+
+import "hris-backend/internal/model"
+
+type CreateLeavePolicyRequest struct {
+	CompanyID             string                   `json:"company_id" validate:"required"`
+	LeaveType             model.LeaveType          `json:"leave_type" validate:"required"`
+	AnnualDays            float64                  `json:"annual_days" validate:"required"`
+	AccrualMethod         model.LeaveAccrualMethod `json:"accrual_method"`
+	MaxCarryOverDays      float64                  `json:"max_carry_over_days"`
+	CarryOverExpiryMonths int                      `json:"carry_over_expiry_months"`
+	RequiresBalance       *bool                    `json:"requires_balance"`
+}
+
+type UpdateLeavePolicyRequest struct {
+	AnnualDays            *float64                 `json:"annual_days"`
+	AccrualMethod         model.LeaveAccrualMethod `json:"accrual_method"`
+	MaxCarryOverDays      *float64                 `json:"max_carry_over_days"`
+	CarryOverExpiryMonths *int                     `json:"carry_over_expiry_months"`
+	RequiresBalance       *bool                    `json:"requires_balance"`
+	IsActive              *bool                    `json:"is_active"`
+}
+
+type LeavePolicyResponse struct {
+	ID                    string                   `json:"id"`
+	CompanyID             string                   `json:"company_id"`
+	LeaveType             model.LeaveType          `json:"leave_type"`
+	AnnualDays            float64                  `json:"annual_days"`
+	AccrualMethod         model.LeaveAccrualMethod `json:"accrual_method"`
+	MaxCarryOverDays      float64                  `json:"max_carry_over_days"`
+	CarryOverExpiryMonths int                      `json:"carry_over_expiry_months"`
+	RequiresBalance       bool                     `json:"requires_balance"`
+	IsActive              bool                     `json:"is_active"`
+	CreatedAt             string                   `json:"created_at"`
+	UpdatedAt             string                   `json:"updated_at"`
+}
+
+type SetLeaveEntitlementRequest struct {
+	EmployeeID string          `json:"employee_id" validate:"required"`
+	LeaveType  model.LeaveType `json:"leave_type" validate:"required"`
+	Year       int             `json:"year" validate:"required"`
+	Days       float64         `json:"days"`
+	Notes      string          `json:"notes"`
+}
+
+type LeaveEntitlementResponse struct {
+	ID         string          `json:"id"`
+	EmployeeID string          `json:"employee_id"`
+	LeaveType  model.LeaveType `json:"leave_type"`
+	Year       int             `json:"year"`
+	Days       float64         `json:"days"`
+	Notes      string          `json:"notes"`
+	CreatedAt  string          `json:"created_at"`
+	UpdatedAt  string          `json:"updated_at"`
+}
+
+type LeaveAdjustmentRequest struct {
+	EmployeeID  string          `json:"employee_id" validate:"required"`
+	LeaveType   model.LeaveType `json:"leave_type" validate:"required"`
+	Year        int             `json:"year" validate:"required"`
+	Days        float64         `json:"days" validate:"required"`
+	Description string          `json:"description" validate:"required"`
+}
+
+type LeaveAccrualRequest struct {
+	CompanyID string `json:"company_id" validate:"required"`
+	Year      int    `json:"year" validate:"required"`
+	Month     int    `json:"month"`
+}
+
+type LeaveYearCloseRequest struct {
+	CompanyID string `json:"company_id" validate:"required"`
+	Year      int    `json:"year" validate:"required"`
+}
+
+type LeaveBalanceJobResponse struct {
+	EmployeesProcessed int `json:"employees_processed"`
+	EntriesCreated     int `json:"entries_created"`
+}
+
+// LeaveBalanceResponse summarises one leave type for one year. Balance is the
+// sum of all ledger entries; Available also subtracts pending requests.
+type LeaveBalanceResponse struct {
+	EmployeeID      string          `json:"employee_id"`
+	LeaveType       model.LeaveType `json:"leave_type"`
+	Year            int             `json:"year"`
+	Entitlement     float64         `json:"entitlement"`
+	Accrued         float64         `json:"accrued"`
+	CarriedOver     float64         `json:"carried_over"`
+	Adjusted        float64         `json:"adjusted"`
+	Taken           float64         `json:"taken"`
+	Expired         float64         `json:"expired"`
+	Balance         float64         `json:"balance"`
+	Pending         float64         `json:"pending"`
+	Available       float64         `json:"available"`
+	RequiresBalance bool            `json:"requires_balance"`
+}
+
+type LeaveLedgerEntryResponse struct {
+	ID          string                     `json:"id"`
+	EmployeeID  string                     `json:"employee_id"`
+	LeaveType   model.LeaveType            `json:"leave_type"`
+	Year        int                        `json:"year"`
+	EntryType   model.LeaveLedgerEntryType `json:"entry_type"`
+	Days        float64                    `json:"days"`
+	LeaveID     string                     `json:"leave_id"`
+	Reference   string                     `json:"reference"`
+	ExpiresAt   string                     `json:"expires_at"`
+	Description string                     `json:"description"`
+	CreatedBy   string                     `json:"created_by"`
+	CreatedAt   string                     `json:"created_at"`
+}
+
+func ToLeavePolicyResponse(p *model.LeavePolicy) LeavePolicyResponse {
+	return LeavePolicyResponse{
+		ID:                    p.ID,
+		CompanyID:             p.CompanyID,
+		LeaveType:             p.LeaveType,
+		AnnualDays:            p.AnnualDays,
+		AccrualMethod:         p.AccrualMethod,
+		MaxCarryOverDays:      p.MaxCarryOverDays,
+		CarryOverExpiryMonths: p.CarryOverExpiryMonths,
+		RequiresBalance:       p.RequiresBalance,
+		IsActive:              p.IsActive,
+		CreatedAt:             p.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:             p.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+func ToLeavePolicyResponses(policies []model.LeavePolicy) []LeavePolicyResponse {
+	responses := make([]LeavePolicyResponse, len(policies))
+	for i, p := range policies {
+		responses[i] = ToLeavePolicyResponse(&p)
+	}
+	return responses
+}
+
+func ToLeaveEntitlementResponse(e *model.LeaveEntitlement) LeaveEntitlementResponse {
+	return LeaveEntitlementResponse{
+		ID:         e.ID,
+		EmployeeID: e.EmployeeID,
+		LeaveType:  e.LeaveType,
+		Year:       e.Year,
+		Days:       e.Days,
+		Notes:      e.Notes,
+		CreatedAt:  e.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:  e.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+func ToLeaveEntitlementResponses(entitlements []model.LeaveEntitlement) []LeaveEntitlementResponse {
+	responses := make([]LeaveEntitlementResponse, len(entitlements))
+	for i, e := range entitlements {
+		responses[i] = ToLeaveEntitlementResponse(&e)
+	}
+	return responses
+}
+
+func ToLeaveLedgerEntryResponse(e *model.LeaveLedgerEntry) LeaveLedgerEntryResponse {
+	resp := LeaveLedgerEntryResponse{
+		ID:          e.ID,
+		EmployeeID:  e.EmployeeID,
+		LeaveType:   e.LeaveType,
+		Year:        e.Year,
+		EntryType:   e.EntryType,
+		Days:        e.Days,
+		Reference:   e.Reference,
+		Description: e.Description,
+		CreatedAt:   e.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if e.LeaveID != nil {
+		resp.LeaveID = *e.LeaveID
+	}
+	if e.ExpiresAt != nil {
+		resp.ExpiresAt = e.ExpiresAt.Format("2006-01-02")
+	}
+	if e.CreatedBy != nil {
+		resp.CreatedBy = *e.CreatedBy
+	}
+
+	return resp
+}
+
+func ToLeaveLedgerEntryResponses(entries []model.LeaveLedgerEntry) []LeaveLedgerEntryResponse {
+	responses := make([]LeaveLedgerEntryResponse, len(entries))
+	for i, e := range entries {
+		responses[i] = ToLeaveLedgerEntryResponse(&e)
+	}
+	return responses
+}
@@ -3,46 +3,73 @@ package dto
 import "hris-backend/internal/model"
 
 type CreateLeaveRequest struct {
-	EmployeeID string          `json:"employee_id" validate:"required"`
-	LeaveType  model.LeaveType `json:"leave_type" validate:"required"`
-	StartDate  string          `json:"start_date" validate:"required"`
-	EndDate    string          `json:"end_date" validate:"required"`
-	TotalDays  int             `json:"total_days" validate:"required"`
-	Reason     string          `json:"reason" validate:"required"`
-	Attachment string          `json:"attachment"`
+	EmployeeID   string          `json:"employee_id" validate:"required"`
+	LeaveType    model.LeaveType `json:"leave_type" validate:"required"`
+	StartDate    string          `json:"start_date" validate:"required"`
+	EndDate      string          `json:"end_date" validate:"required"`
+	StartHalfDay bool            `json:"start_half_day"`
+	EndHalfDay   bool            `json:"end_half_day"`
+	Reason       string          `json:"reason" validate:"required"`
+	Attachment   string          `json:"attachment"`
 }
 
 type UpdateLeaveRequest struct {
-	LeaveType  model.LeaveType `json:"leave_type"`
-	StartDate  string          `json:"start_date"`
-	EndDate    string          `json:"end_date"`
-	TotalDays  *int            `json:"total_days"`
-	Reason     string          `json:"reason"`
-	Attachment string          `json:"attachment"`
+	LeaveType    model.LeaveType `json:"leave_type"`
+	StartDate    string          `json:"start_date"`
+	EndDate      string          `json:"end_date"`
+	StartHalfDay *bool           `json:"start_half_day"`
+	EndHalfDay   *bool           `json:"end_half_day"`
+	Reason       string          `json:"reason"`
+	Attachment   string          `json:"attachment"`
 }
 
 type ApproveLeaveRequest struct {
 	Status          model.LeaveStatus `json:"status" validate:"required"`
 	RejectionReason string            `json:"rejection_reason"`
+	Comment         string            `json:"comment"`
+}
+
+type CancelLeaveRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+type LeaveApprovalResponse struct {
+	ID             string                    `json:"id"`
+	StepOrder      int                       `json:"step_order"`
+	ApproverType   model.LeaveApproverType   `json:"approver_type"`
+	ApproverRole   model.Role                `json:"approver_role"`
+	ApproverUserID string                    `json:"approver_user_id"`
+	Status         model.LeaveApprovalStatus `json:"status"`
+	DecidedBy      string                    `json:"decided_by"`
+	Decider        *UserResponse             `json:"decider,omitempty"`
+	DelegatedFrom  string                    `json:"delegated_from"`
+	Comment        string                    `json:"comment"`
+	DecidedAt      string                    `json:"decided_at"`
 }
 
 type LeaveResponse struct {
-	ID              string            `json:"id"`
-	EmployeeID      string            `json:"employee_id"`
-	Employee        *EmployeeResponse `json:"employee,omitempty"`
-	LeaveType       model.LeaveType   `json:"leave_type"`
-	StartDate       string            `json:"start_date"`
-	EndDate         string            `json:"end_date"`
-	TotalDays       int               `json:"total_days"`
-	Reason          string            `json:"reason"`
-	Attachment      string            `json:"attachment"`
-	Status          model.LeaveStatus `json:"status"`
-	ApprovedBy      string            `json:"approved_by"`
-	Approver        *UserResponse     `json:"approver,omitempty"`
-	ApprovedAt      string            `json:"approved_at"`
-	RejectionReason string            `json:"rejection_reason"`
-	CreatedAt       string            `json:"created_at"`
-	UpdatedAt       string            `json:"updated_at"`
+	ID              string                  `json:"id"`
+	EmployeeID      string                  `json:"employee_id"`
+	Employee        *EmployeeResponse       `json:"employee,omitempty"`
+	LeaveType       model.LeaveType         `json:"leave_type"`
+	StartDate       string                  `json:"start_date"`
+	EndDate         string                  `json:"end_date"`
+	StartHalfDay    bool                    `json:"start_half_day"`
+	EndHalfDay      bool                    `json:"end_half_day"`
+	TotalDays       float64                 `json:"total_days"`
+	Reason          string                  `json:"reason"`
+	Attachment      string                  `json:"attachment"`
+	Status          model.LeaveStatus       `json:"status"`
+	ApprovedBy      string                  `json:"approved_by"`
+	Approver        *UserResponse           `json:"approver,omitempty"`
+	ApprovedAt      string                  `json:"approved_at"`
+	RejectionReason string                  `json:"rejection_reason"`
+	CancelledBy     string                  `json:"cancelled_by"`
+	CancelledAt     string                  `json:"cancelled_at"`
+	CancelReason    string                  `json:"cancel_reason"`
+	Approvals       []LeaveApprovalResponse `json:"approvals"`
+	CreatedAt       string                  `json:"created_at"`
+	UpdatedAt       string                  `json:"updated_at"`
 }
 
 func ToLeaveResponse(l *model.Leave) LeaveResponse {
@@ -52,11 +79,14 @@ func ToLeaveResponse(l *model.Leave) LeaveResponse {
 		LeaveType:       l.LeaveType,
 		StartDate:       l.StartDate.Format("2006-01-02"),
 		EndDate:         l.EndDate.Format("2006-01-02"),
+		StartHalfDay:    l.StartHalfDay,
+		EndHalfDay:      l.EndHalfDay,
 		TotalDays:       l.TotalDays,
 		Reason:          l.Reason,
 		Attachment:      l.Attachment,
 		Status:          l.Status,
 		RejectionReason: l.RejectionReason,
+		CancelReason:    l.CancelReason,
 		CreatedAt:       l.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:       l.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
@@ -67,6 +97,12 @@ func ToLeaveResponse(l *model.Leave) LeaveResponse {
 	if l.ApprovedAt != nil {
 		resp.ApprovedAt = l.ApprovedAt.Format("2006-01-02T15:04:05Z")
 	}
+	if l.CancelledBy != nil {
+		resp.CancelledBy = *l.CancelledBy
+	}
+	if l.CancelledAt != nil {
+		resp.CancelledAt = l.CancelledAt.Format("2006-01-02T15:04:05Z")
+	}
 
 	if l.Employee.ID != "" {
 		empResp := ToEmployeeResponse(&l.Employee)
@@ -77,6 +113,41 @@ func ToLeaveResponse(l *model.Leave) LeaveResponse {
 		resp.Approver = &approverResp
 	}
 
+	resp.Approvals = make([]LeaveApprovalResponse, len(l.Approvals))
+	for i := range l.Approvals {
+		resp.Approvals[i] = ToLeaveApprovalResponse(&l.Approvals[i])
+	}
+
+	return resp
+}
+
+func ToLeaveApprovalResponse(a *model.LeaveApproval) LeaveApprovalResponse {
+	resp := LeaveApprovalResponse{
+		ID:           a.ID,
+		StepOrder:    a.StepOrder,
+		ApproverType: a.ApproverType,
+		ApproverRole: a.ApproverRole,
+		Status:       a.Status,
+		Comment:      a.Comment,
+	}
+
+	if a.ApproverUserID != nil {
+		resp.ApproverUserID = *a.ApproverUserID
+	}
+	if a.DecidedBy != nil {
+		resp.DecidedBy = *a.DecidedBy
+	}
+	if a.DelegatedFrom != nil {
+		resp.DelegatedFrom = *a.DelegatedFrom
+	}
+	if a.DecidedAt != nil {
+		resp.DecidedAt = a.DecidedAt.Format("2006-01-02T15:04:05Z")
+	}
+	if a.Decider != nil && a.Decider.ID != "" {
+		deciderResp := ToUserResponse(a.Decider)
+		resp.Decider = &deciderResp
+	}
+
 	return resp
 }
 
@@ -0,0 +1,142 @@
+package dto
+
+import "hris-backend/internal/model"
+
+type LeaveWorkflowStepRequest struct {
+	ApproverType   model.LeaveApproverType `json:"approver_type" validate:"required"`
+	ApproverRole   model.Role              `json:"approver_role"`
+	ApproverUserID string                  `json:"approver_user_id"`
+}
+
+type CreateLeaveWorkflowRequest struct {
+	CompanyID    string                     `json:"company_id" validate:"required"`
+	DepartmentID string                     `json:"department_id"`
+	Name         string                     `json:"name" validate:"required"`
+	Steps        []LeaveWorkflowStepRequest `json:"steps" validate:"required"`
+}
+
+type UpdateLeaveWorkflowRequest struct {
+	Name     string                     `json:"name"`
+	IsActive *bool                      `json:"is_active"`
+	Steps    []LeaveWorkflowStepRequest `json:"steps"`
+}
+
+type LeaveWorkflowStepResponse struct {
+	ID             string                  `json:"id"`
+	StepOrder      int                     `json:"step_order"`
+	ApproverType   model.LeaveApproverType `json:"approver_type"`
+	ApproverRole   model.Role              `json:"approver_role"`
+	ApproverUserID string                  `json:"approver_user_id"`
+}
+
+type LeaveWorkflowResponse struct {
+	ID           string                      `json:"id"`
+	CompanyID    string                      `json:"company_id"`
+	Company      *CompanyResponse            `json:"company,omitempty"`
+	DepartmentID string                      `json:"department_id"`
+	Department   *DepartmentResponse         `json:"department,omitempty"`
+	Name         string                      `json:"name"`
+	IsActive     bool                        `json:"is_active"`
+	Steps        []LeaveWorkflowStepResponse `json:"steps"`
+	CreatedAt    string                      `json:"created_at"`
+	UpdatedAt    string                      `json:"updated_at"`
+}
+
+func ToLeaveWorkflowResponse(w *model.LeaveWorkflow) LeaveWorkflowResponse {
+	resp := LeaveWorkflowResponse{
+		ID:        w.ID,
+		CompanyID: w.CompanyID,
+		Name:      w.Name,
+		IsActive:  w.IsActive,
+		Steps:     make([]LeaveWorkflowStepResponse, len(w.Steps)),
+		CreatedAt: w.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt: w.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if w.DepartmentID != nil {
+		resp.DepartmentID = *w.DepartmentID
+	}
+	if w.Company.ID != "" {
+		companyResp := ToCompanyResponse(&w.Company)
+		resp.Company = &companyResp
+	}
+	if w.Department != nil && w.Department.ID != "" {
+		deptResp := ToDepartmentResponse(w.Department)
+		resp.Department = &deptResp
+	}
+
+	for i, step := range w.Steps {
+		resp.Steps[i] = LeaveWorkflowStepResponse{
+			ID:           step.ID,
+			StepOrder:    step.StepOrder,
+			ApproverType: step.ApproverType,
+			ApproverRole: step.ApproverRole,
+		}
+		if step.ApproverUserID != nil {
+			resp.Steps[i].ApproverUserID = *step.ApproverUserID
+		}
+	}
+
+	return resp
+}
+
+func ToLeaveWorkflowResponses(workflows []model.LeaveWorkflow) []LeaveWorkflowResponse {
+	responses := make([]LeaveWorkflowResponse, len(workflows))
+	for i, w := range workflows {
+		responses[i] = ToLeaveWorkflowResponse(&w)
+	}
+	return responses
+}
+
+type CreateLeaveDelegationRequest struct {
+	DelegatorID string `json:"delegator_id"`
+	DelegateID  string `json:"delegate_id" validate:"required"`
+	StartDate   string `json:"start_date" validate:"required"`
+	EndDate     string `json:"end_date" validate:"required"`
+	Reason      string `json:"reason"`
+}
+
+type LeaveDelegationResponse struct {
+	ID          string        `json:"id"`
+	DelegatorID string        `json:"delegator_id"`
+	Delegator   *UserResponse `json:"delegator,omitempty"`
+	DelegateID  string        `json:"delegate_id"`
+	Delegate    *UserResponse `json:"delegate,omitempty"`
+	StartDate   string        `json:"start_date"`
+	EndDate     string        `json:"end_date"`
+	Reason      string        `json:"reason"`
+	CreatedBy   string        `json:"created_by"`
+	CreatedAt   string        `json:"created_at"`
+}
+
+func ToLeaveDelegationResponse(d *model.LeaveDelegation) LeaveDelegationResponse {
+	resp := LeaveDelegationResponse{
+		ID:          d.ID,
+		DelegatorID: d.DelegatorID,
+		DelegateID:  d.DelegateID,
+		StartDate:   d.StartDate.Format("2006-01-02"),
+		EndDate:     d.EndDate.Format("2006-01-02"),
+		Reason:      d.Reason,
+		CreatedBy:   d.CreatedBy,
+		CreatedAt:   d.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if d.Delegator.ID != "" {
+		delegatorResp := ToUserResponse(&d.Delegator)
+		resp.Delegator = &delegatorResp
+	}
+	if d.Delegate.ID != "" {
+		delegateResp := ToUserResponse(&d.Delegate)
+		resp.Delegate = &delegateResp
+	}
+
+	return resp
+}
+
+func ToLeaveDelegationResponses(delegations []model.LeaveDelegation) []LeaveDelegationResponse {
+	responses := make([]LeaveDelegationResponse, len(delegations))
+	for i, d := range delegations {
+		responses[i] = ToLeaveDelegationResponse(&d)
+	}
+	return responses
+}
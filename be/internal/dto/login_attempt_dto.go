@@ -0,0 +1,43 @@
+package dto
+
+import "hris-backend/internal/model"
+
+type LoginAttemptResponse struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id,omitempty"`
+	UserName  string `json:"user_name,omitempty"`
+	Email     string `json:"email"`
+	IPAddress string `json:"ip_address"`
+	UserAgent string `json:"user_agent"`
+	Result    string `json:"result"`
+	CreatedAt string `json:"created_at"`
+}
+
+type PaginatedLoginAttemptResponse struct {
+	Data       []LoginAttemptResponse `json:"data"`
+	Page       int                    `json:"page"`
+	Limit      int                    `json:"limit"`
+	TotalItems int64                  `json:"total_items"`
+	TotalPages int                    `json:"total_pages"`
+}
+
+func ToLoginAttemptResponses(attempts []model.LoginAttempt) []LoginAttemptResponse {
+	responses := make([]LoginAttemptResponse, len(attempts))
+	for i, a := range attempts {
+		responses[i] = LoginAttemptResponse{
+			ID:        a.ID,
+			Email:     a.Email,
+			IPAddress: a.IPAddress,
+			UserAgent: a.UserAgent,
+			Result:    string(a.Result),
+			CreatedAt: a.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		}
+		if a.UserID != nil {
+			responses[i].UserID = *a.UserID
+		}
+		if a.User != nil {
+			responses[i].UserName = a.User.Name
+		}
+	}
+	return responses
+}
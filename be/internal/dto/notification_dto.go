@@ -1,37 +1,87 @@
 package dto
 
-import "hris-backend/internal/model"
+import (
+	"net/url"
+	"strings"
+
+	"hris-backend/internal/model"
+)
 
 type NotificationResponse struct {
-	ID        string                  `json:"id"`
-	Title     string                  `json:"title"`
-	Message   string                  `json:"message"`
-	Type      model.NotificationType  `json:"type"`
-	RefID     string                  `json:"ref_id,omitempty"`
-	RefType   string                  `json:"ref_type,omitempty"`
-	IsRead    bool                    `json:"is_read"`
-	ReadAt    string                  `json:"read_at,omitempty"`
-	CreatedAt string                  `json:"created_at"`
+	ID         string                 `json:"id"`
+	Title      string                 `json:"title"`
+	Message    string                 `json:"message"`
+	Type       model.NotificationType `json:"type"`
+	RefID      string                 `json:"ref_id,omitempty"`
+	RefType    string                 `json:"ref_type,omitempty"`
+	IsRead     bool                   `json:"is_read"`
+	ReadAt     string                 `json:"read_at,omitempty"`
+	CreatedAt  string                 `json:"created_at"`
+	ArchivedAt string                 `json:"archived_at,omitempty"`
+	Link       string                 `json:"link,omitempty"` // frontend route of the record it refers to
+}
+
+// NotificationPageResponse is a page of notifications. NextCursor is passed
+// as the cursor of the next page; it is empty on the last one.
+type NotificationPageResponse struct {
+	Items      []NotificationResponse `json:"items"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
+}
+
+// NotificationIDsRequest lists the notifications a bulk action applies to
+type NotificationIDsRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// NotificationBulkResponse is how many notifications a bulk action changed
+type NotificationBulkResponse struct {
+	Count int64 `json:"count"`
 }
 
 type UnreadCountResponse struct {
 	Count int64 `json:"count"`
 }
 
+// NotificationPreferenceResponse is how the user is notified of one event
+// type; event types they did not choose a mode for have the default
+type NotificationPreferenceResponse struct {
+	EventType string                 `json:"event_type"`
+	Mode      model.NotificationMode `json:"mode"`
+}
+
+type NotificationPreferenceRequest struct {
+	EventType string                 `json:"event_type"`
+	Mode      model.NotificationMode `json:"mode"`
+}
+
+// UpdateNotificationPreferencesRequest sets the modes of the listed event
+// types; the others keep theirs
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceRequest `json:"preferences"`
+}
+
 func ToNotificationResponse(n *model.Notification) NotificationResponse {
+	var refID string
+	if n.RefID != nil {
+		refID = *n.RefID
+	}
 	resp := NotificationResponse{
 		ID:        n.ID,
 		Title:     n.Title,
 		Message:   n.Message,
 		Type:      n.Type,
-		RefID:     n.RefID,
+		RefID:     refID,
 		RefType:   n.RefType,
 		IsRead:    n.IsRead,
 		CreatedAt: n.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		Link:      NotificationLink(n.RefType, refID),
 	}
 	if n.ReadAt != nil {
 		resp.ReadAt = n.ReadAt.Format("2006-01-02T15:04:05Z")
 	}
+	if n.ArchivedAt != nil {
+		resp.ArchivedAt = n.ArchivedAt.Format("2006-01-02T15:04:05Z")
+	}
 	return resp
 }
 
@@ -42,3 +92,26 @@ func ToNotificationResponses(notifications []model.Notification) []NotificationR
 	}
 	return responses
 }
+
+// notificationRoutes are the frontend routes of the records notifications
+// refer to, by RefType; {id} stands for the RefID. Leaves and payslips link
+// to their list, and announcements, which have no page, to nothing.
+var notificationRoutes = map[string]string{
+	"leave":      "/dashboard/leaves",
+	"payroll":    "/dashboard/payslips",
+	"attendance": "/dashboard/checkin",
+	"user":       "/dashboard/users/{id}/edit",
+	"employee":   "/dashboard/employees/{id}/edit",
+	"company":    "/dashboard/settings/modules",
+	"digest":     "/dashboard",
+}
+
+// NotificationLink resolves the record a notification refers to into its
+// frontend route, or "" when it has none
+func NotificationLink(refType, refID string) string {
+	route, ok := notificationRoutes[refType]
+	if !ok || (refID == "" && strings.Contains(route, "{id}")) {
+		return ""
+	}
+	return strings.Replace(route, "{id}", url.PathEscape(refID), 1)
+}
@@ -0,0 +1,112 @@
+package dto
+
+import "hris-backend/internal/model"
+
+type CreateOIDCProviderRequest struct {
+	CompanyID      string `json:"company_id" validate:"required"`
+	Slug           string `json:"slug" validate:"required"`
+	Name           string `json:"name" validate:"required"`
+	IssuerURL      string `json:"issuer_url" validate:"required"`
+	ClientID       string `json:"client_id" validate:"required"`
+	ClientSecret   string `json:"client_secret"`
+	Scopes         string `json:"scopes"`
+	AllowedDomains string `json:"allowed_domains"`
+	AutoProvision  bool   `json:"auto_provision"`
+	DefaultRole    string `json:"default_role"`
+}
+
+type UpdateOIDCProviderRequest struct {
+	Name           string  `json:"name"`
+	IssuerURL      string  `json:"issuer_url"`
+	ClientID       string  `json:"client_id"`
+	ClientSecret   *string `json:"client_secret"`
+	Scopes         string  `json:"scopes"`
+	AllowedDomains *string `json:"allowed_domains"`
+	AutoProvision  *bool   `json:"auto_provision"`
+	DefaultRole    string  `json:"default_role"`
+	IsActive       *bool   `json:"is_active"`
+}
+
+type OIDCProviderResponse struct {
+	ID              string           `json:"id"`
+	CompanyID       string           `json:"company_id"`
+	Company         *CompanyResponse `json:"company,omitempty"`
+	Slug            string           `json:"slug"`
+	Name            string           `json:"name"`
+	IssuerURL       string           `json:"issuer_url"`
+	ClientID        string           `json:"client_id"`
+	HasClientSecret bool             `json:"has_client_secret"`
+	Scopes          string           `json:"scopes"`
+	AllowedDomains  string           `json:"allowed_domains"`
+	AutoProvision   bool             `json:"auto_provision"`
+	DefaultRole     string           `json:"default_role"`
+	IsActive        bool             `json:"is_active"`
+	CreatedAt       string           `json:"created_at"`
+	UpdatedAt       string           `json:"updated_at"`
+}
+
+// OIDCLoginOptionResponse is a provider as offered on the login page
+type OIDCLoginOptionResponse struct {
+	Slug     string `json:"slug"`
+	Name     string `json:"name"`
+	LoginURL string `json:"login_url"`
+}
+
+// OIDCLogin is an identity confirmed by a provider callback. Linked is set
+// when the callback linked the identity to a signed-in user rather than
+// logging in.
+type OIDCLogin struct {
+	UserID       string
+	RedirectPath string
+	Linked       bool
+}
+
+// OIDCLinkResponse is the provider URL that links an identity to the
+// signed-in user
+type OIDCLinkResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+func ToOIDCProviderResponse(p *model.OIDCProvider) OIDCProviderResponse {
+	resp := OIDCProviderResponse{
+		ID:              p.ID,
+		CompanyID:       p.CompanyID,
+		Slug:            p.Slug,
+		Name:            p.Name,
+		IssuerURL:       p.IssuerURL,
+		ClientID:        p.ClientID,
+		HasClientSecret: p.ClientSecret != "",
+		Scopes:          p.Scopes,
+		AllowedDomains:  p.AllowedDomains,
+		AutoProvision:   p.AutoProvision,
+		DefaultRole:     string(p.DefaultRole),
+		IsActive:        p.IsActive,
+		CreatedAt:       p.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:       p.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if p.Company.ID != "" {
+		companyResp := ToCompanyResponse(&p.Company)
+		resp.Company = &companyResp
+	}
+	return resp
+}
+
+func ToOIDCProviderResponses(providers []model.OIDCProvider) []OIDCProviderResponse {
+	responses := make([]OIDCProviderResponse, len(providers))
+	for i, p := range providers {
+		responses[i] = ToOIDCProviderResponse(&p)
+	}
+	return responses
+}
+
+func ToOIDCLoginOptionResponses(providers []model.OIDCProvider) []OIDCLoginOptionResponse {
+	responses := make([]OIDCLoginOptionResponse, len(providers))
+	for i, p := range providers {
+		responses[i] = OIDCLoginOptionResponse{
+			Slug:     p.Slug,
+			Name:     p.Name,
+			LoginURL: "/api/auth/oidc/" + p.Slug + "/login",
+		}
+	}
+	return responses
+}
@@ -36,6 +36,9 @@ type PayrollResponse struct {
 	BPJSKesDeduction float64             `json:"bpjs_kes_deduction"`
 	BPJSTKDeduction  float64             `json:"bpjs_tk_deduction"`
 	PPH21            float64             `json:"pph21"`
+	TaxableIncome    float64             `json:"taxable_income"`
+	PTKPStatus       string              `json:"ptkp_status"`
+	TERCategory      string              `json:"ter_category"`
 	OtherDeductions  float64             `json:"other_deductions"`
 	NetSalary        float64             `json:"net_salary"`
 	Status           model.PayrollStatus `json:"status"`
@@ -62,6 +65,9 @@ func ToPayrollResponse(p *model.Payroll) PayrollResponse {
 		BPJSKesDeduction: p.BPJSKesDeduction,
 		BPJSTKDeduction:  p.BPJSTKDeduction,
 		PPH21:            p.PPH21,
+		TaxableIncome:    p.TaxableIncome,
+		PTKPStatus:       p.PTKPStatus,
+		TERCategory:      p.TERCategory,
 		OtherDeductions:  p.OtherDeductions,
 		NetSalary:        p.NetSalary,
 		Status:           p.Status,
@@ -89,3 +95,89 @@ func ToPayrollResponses(payrolls []model.Payroll) []PayrollResponse {
 	}
 	return responses
 }
+
+type GeneratePayrollRunRequest struct {
+	CompanyID string `json:"company_id" validate:"required"`
+	Month     int    `json:"month" validate:"required"`
+	Year      int    `json:"year" validate:"required"`
+	Notes     string `json:"notes"`
+}
+
+type PayrollRunResponse struct {
+	ID             string              `json:"id"`
+	CompanyID      string              `json:"company_id"`
+	CompanyName    string              `json:"company_name,omitempty"`
+	PeriodMonth    int                 `json:"period_month"`
+	PeriodYear     int                 `json:"period_year"`
+	Status         model.PayrollStatus `json:"status"`
+	TotalEmployees int                 `json:"total_employees"`
+	CreatedCount   int                 `json:"created_count"`
+	SkippedCount   int                 `json:"skipped_count"`
+	FailedCount    int                 `json:"failed_count"`
+	TotalGross     float64             `json:"total_gross"`
+	TotalNet       float64             `json:"total_net"`
+	CreatedBy      string              `json:"created_by"`
+	ProcessedAt    string              `json:"processed_at"`
+	PaidAt         string              `json:"paid_at"`
+	Notes          string              `json:"notes"`
+	Payrolls       []PayrollResponse   `json:"payrolls,omitempty"`
+	CreatedAt      string              `json:"created_at"`
+	UpdatedAt      string              `json:"updated_at"`
+}
+
+// PayrollRunEmployeeResult reports what happened to one employee when a run
+// was generated. Outcome is one of "created", "skipped" or "failed".
+type PayrollRunEmployeeResult struct {
+	EmployeeID     string `json:"employee_id"`
+	EmployeeNumber string `json:"employee_number"`
+	EmployeeName   string `json:"employee_name"`
+	Outcome        string `json:"outcome"`
+	Reason         string `json:"reason,omitempty"`
+	PayrollID      string `json:"payroll_id,omitempty"`
+}
+
+type PayrollRunGenerateResponse struct {
+	Run     *PayrollRunResponse        `json:"run,omitempty"`
+	Results []PayrollRunEmployeeResult `json:"results"`
+}
+
+func ToPayrollRunResponse(r *model.PayrollRun) PayrollRunResponse {
+	resp := PayrollRunResponse{
+		ID:             r.ID,
+		CompanyID:      r.CompanyID,
+		CompanyName:    r.Company.Name,
+		PeriodMonth:    r.PeriodMonth,
+		PeriodYear:     r.PeriodYear,
+		Status:         r.Status,
+		TotalEmployees: r.TotalEmployees,
+		CreatedCount:   r.CreatedCount,
+		SkippedCount:   r.SkippedCount,
+		FailedCount:    r.FailedCount,
+		TotalGross:     r.TotalGross,
+		TotalNet:       r.TotalNet,
+		CreatedBy:      r.CreatedBy,
+		Notes:          r.Notes,
+		CreatedAt:      r.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:      r.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if r.ProcessedAt != nil {
+		resp.ProcessedAt = r.ProcessedAt.Format("2006-01-02T15:04:05Z")
+	}
+	if r.PaidAt != nil {
+		resp.PaidAt = r.PaidAt.Format("2006-01-02T15:04:05Z")
+	}
+	if len(r.Payrolls) > 0 {
+		resp.Payrolls = ToPayrollResponses(r.Payrolls)
+	}
+
+	return resp
+}
+
+func ToPayrollRunResponses(runs []model.PayrollRun) []PayrollRunResponse {
+	responses := make([]PayrollRunResponse, len(runs))
+	for i, r := range runs {
+		responses[i] = ToPayrollRunResponse(&r)
+	}
+	return responses
+}
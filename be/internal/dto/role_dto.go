@@ -0,0 +1,79 @@
+package dto
+
+import "hris-backend/internal/model"
+
+type PermissionResponse struct {
+	Name        string `json:"name"`
+	Module      string `json:"module"`
+	Action      string `json:"action"`
+	Description string `json:"description"`
+}
+
+type CreateCustomRoleRequest struct {
+	CompanyID   string   `json:"company_id" validate:"required"`
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions" validate:"required"`
+}
+
+type UpdateCustomRoleRequest struct {
+	Name        string   `json:"name"`
+	Description *string  `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type CustomRoleResponse struct {
+	ID          string           `json:"id"`
+	CompanyID   string           `json:"company_id"`
+	Company     *CompanyResponse `json:"company,omitempty"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Permissions []string         `json:"permissions"`
+	CreatedAt   string           `json:"created_at"`
+	UpdatedAt   string           `json:"updated_at"`
+}
+
+func ToPermissionResponse(p *model.Permission) PermissionResponse {
+	return PermissionResponse{
+		Name:        p.Name,
+		Module:      p.Module,
+		Action:      p.Action,
+		Description: p.Description,
+	}
+}
+
+func ToPermissionResponses(permissions []model.Permission) []PermissionResponse {
+	responses := make([]PermissionResponse, len(permissions))
+	for i, p := range permissions {
+		responses[i] = ToPermissionResponse(&p)
+	}
+	return responses
+}
+
+func ToCustomRoleResponse(r *model.CustomRole) CustomRoleResponse {
+	resp := CustomRoleResponse{
+		ID:          r.ID,
+		CompanyID:   r.CompanyID,
+		Name:        r.Name,
+		Description: r.Description,
+		Permissions: make([]string, len(r.Permissions)),
+		CreatedAt:   r.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:   r.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if r.Company.ID != "" {
+		companyResp := ToCompanyResponse(&r.Company)
+		resp.Company = &companyResp
+	}
+	for i, p := range r.Permissions {
+		resp.Permissions[i] = p.Name
+	}
+	return resp
+}
+
+func ToCustomRoleResponses(roles []model.CustomRole) []CustomRoleResponse {
+	responses := make([]CustomRoleResponse, len(roles))
+	for i, r := range roles {
+		responses[i] = ToCustomRoleResponse(&r)
+	}
+	return responses
+}
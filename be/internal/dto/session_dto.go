@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+
+	"hris-backend/internal/model"
+)
+
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	Current    bool      `json:"current"`
+}
+
+// ToSessionResponses converts sessions, flagging the one with ID currentID
+func ToSessionResponses(sessions []model.Session, currentID string) []SessionResponse {
+	responses := make([]SessionResponse, len(sessions))
+	for i, s := range sessions {
+		responses[i] = SessionResponse{
+			ID:         s.ID,
+			UserAgent:  s.UserAgent,
+			IPAddress:  s.IPAddress,
+			LastUsedAt: s.LastUsedAt,
+			ExpiresAt:  s.ExpiresAt,
+			CreatedAt:  s.CreatedAt,
+			Current:    currentID != "" && s.ID == currentID,
+		}
+	}
+	return responses
+}
@@ -7,12 +7,14 @@ type CreateShiftRequest struct {
 	Name      string `json:"name" validate:"required"`
 	StartTime string `json:"start_time" validate:"required"`
 	EndTime   string `json:"end_time" validate:"required"`
+	WorkDays  string `json:"work_days"`
 }
 
 type UpdateShiftRequest struct {
 	Name      string `json:"name"`
 	StartTime string `json:"start_time"`
 	EndTime   string `json:"end_time"`
+	WorkDays  string `json:"work_days"`
 	IsActive  *bool  `json:"is_active"`
 }
 
@@ -23,6 +25,7 @@ type ShiftResponse struct {
 	Name      string           `json:"name"`
 	StartTime string           `json:"start_time"`
 	EndTime   string           `json:"end_time"`
+	WorkDays  string           `json:"work_days"`
 	IsActive  bool             `json:"is_active"`
 	CreatedAt string           `json:"created_at"`
 	UpdatedAt string           `json:"updated_at"`
@@ -35,6 +38,7 @@ func ToShiftResponse(shift *model.Shift) ShiftResponse {
 		Name:      shift.Name,
 		StartTime: shift.StartTime,
 		EndTime:   shift.EndTime,
+		WorkDays:  shift.WorkDays,
 		IsActive:  shift.IsActive,
 		CreatedAt: shift.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt: shift.UpdatedAt.Format("2006-01-02T15:04:05Z"),
@@ -0,0 +1,23 @@
+package dto
+
+import "hris-backend/internal/model"
+
+type SetUserCompaniesRequest struct {
+	CompanyIDs []string `json:"company_ids" validate:"required"`
+}
+
+type UserCompanyResponse struct {
+	CompanyID   string `json:"company_id"`
+	CompanyName string `json:"company_name"`
+}
+
+func ToUserCompanyResponses(bindings []model.UserCompany) []UserCompanyResponse {
+	responses := make([]UserCompanyResponse, len(bindings))
+	for i, b := range bindings {
+		responses[i] = UserCompanyResponse{
+			CompanyID:   b.CompanyID,
+			CompanyName: b.Company.Name,
+		}
+	}
+	return responses
+}
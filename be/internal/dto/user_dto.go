@@ -1,50 +1,76 @@
 package dto
 
-import "hris-backend/internal/model"
+import (
+	"time"
+
+	"hris-backend/internal/model"
+)
 
 type CreateUserRequest struct {
-	Name     string     `json:"name" validate:"required"`
-	Email    string     `json:"email" validate:"required,email"`
-	Password string     `json:"password" validate:"required,min=6"`
-	Role     model.Role `json:"role" validate:"required,oneof=superadmin admin hr employee"`
-	Phone    string     `json:"phone"`
-	Address  string     `json:"address"`
+	Name         string     `json:"name" validate:"required"`
+	Email        string     `json:"email" validate:"required,email"`
+	Password     string     `json:"password" validate:"required,min=6"`
+	Role         model.Role `json:"role" validate:"required,oneof=superadmin admin hr employee"`
+	CustomRoleID string     `json:"custom_role_id"`
+	Phone        string     `json:"phone"`
+	Address      string     `json:"address"`
+	// Language of the user's email, id (default) or en
+	Language string `json:"language"`
+	// MustChangePassword makes the user replace the initial password on
+	// first login
+	MustChangePassword bool `json:"must_change_password"`
 }
 
 type UpdateUserRequest struct {
-	Name     string     `json:"name"`
-	Email    string     `json:"email"`
-	Password string     `json:"password"`
-	Role     model.Role `json:"role"`
-	Phone    string     `json:"phone"`
-	Address  string     `json:"address"`
-	IsActive *bool      `json:"is_active"`
+	Name               string     `json:"name"`
+	Email              string     `json:"email"`
+	Password           string     `json:"password"`
+	Role               model.Role `json:"role"`
+	CustomRoleID       *string    `json:"custom_role_id"`
+	Phone              string     `json:"phone"`
+	Address            string     `json:"address"`
+	Language           string     `json:"language"`
+	IsActive           *bool      `json:"is_active"`
+	MustChangePassword *bool      `json:"must_change_password"`
 }
 
 type UserResponse struct {
-	ID        string     `json:"id"`
-	Name      string     `json:"name"`
-	Email     string     `json:"email"`
-	Role      model.Role `json:"role"`
-	Phone     string     `json:"phone"`
-	Address   string     `json:"address"`
-	IsActive  bool       `json:"is_active"`
-	CreatedAt string     `json:"created_at"`
-	UpdatedAt string     `json:"updated_at"`
+	ID                 string     `json:"id"`
+	Name               string     `json:"name"`
+	Email              string     `json:"email"`
+	Role               model.Role `json:"role"`
+	CustomRoleID       string     `json:"custom_role_id,omitempty"`
+	Phone              string     `json:"phone"`
+	Address            string     `json:"address"`
+	Language           string     `json:"language"`
+	IsActive           bool       `json:"is_active"`
+	MustChangePassword bool       `json:"must_change_password"`
+	IsServiceAccount   bool       `json:"is_service_account"`
+	LockedUntil        *time.Time `json:"locked_until,omitempty"`
+	CreatedAt          string     `json:"created_at"`
+	UpdatedAt          string     `json:"updated_at"`
 }
 
 func ToUserResponse(user *model.User) UserResponse {
-	return UserResponse{
-		ID:        user.ID,
-		Name:      user.Name,
-		Email:     user.Email,
-		Role:      user.Role,
-		Phone:     user.Phone,
-		Address:   user.Address,
-		IsActive:  user.IsActive,
-		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	resp := UserResponse{
+		ID:                 user.ID,
+		Name:               user.Name,
+		Email:              user.Email,
+		Role:               user.Role,
+		Phone:              user.Phone,
+		Address:            user.Address,
+		Language:           user.Language,
+		IsActive:           user.IsActive,
+		MustChangePassword: user.MustChangePassword,
+		IsServiceAccount:   user.IsServiceAccount,
+		LockedUntil:        user.LockedUntil,
+		CreatedAt:          user.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:          user.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if user.CustomRoleID != nil {
+		resp.CustomRoleID = *user.CustomRoleID
 	}
+	return resp
 }
 
 func ToUserResponses(users []model.User) []UserResponse {
@@ -0,0 +1,90 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+	"hris-backend/pkg/kafka"
+)
+
+// Channel emails notifications to their users. It renders the email in the
+// user's language and queues it in the delivery log, for the Dispatcher to
+// send.
+type Channel struct {
+	userRepo     repository.UserRepository
+	deliveryRepo repository.EmailDeliveryRepository
+	renderer     *Renderer
+	appURL       string
+}
+
+// NewChannel creates a channel whose emails link to appURL, the frontend.
+func NewChannel(
+	userRepo repository.UserRepository,
+	deliveryRepo repository.EmailDeliveryRepository,
+	renderer *Renderer,
+	appURL string,
+) *Channel {
+	return &Channel{
+		userRepo:     userRepo,
+		deliveryRepo: deliveryRepo,
+		renderer:     renderer,
+		appURL:       appURL,
+	}
+}
+
+// Deliver queues the email of a notification. Events without an email
+// template, service accounts and inactive users get none.
+func (c *Channel) Deliver(ctx context.Context, event *kafka.NotificationEvent, n *model.Notification) error {
+	eventType := string(event.EventType)
+	if !c.renderer.Has(eventType) {
+		return nil
+	}
+
+	user, err := c.userRepo.FindByID(ctx, n.UserID)
+	if err != nil {
+		return fmt.Errorf("find user %s: %w", n.UserID, err)
+	}
+	if user.IsServiceAccount || !user.IsActive || user.Email == "" {
+		return nil
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("unmarshal payload: %w", err)
+	}
+	rendered, err := c.renderer.Render(eventType, Data{
+		Lang:      user.Language,
+		Recipient: Recipient{ID: user.ID, Name: user.Name},
+		Payload:   payload,
+		AppURL:    c.appURL,
+	})
+	if err != nil {
+		// The event cannot get better by retrying it; keep the in-app
+		// notification and skip the email
+		log.Printf("[email] render %s for user %s: %v", eventType, user.ID, err)
+		return nil
+	}
+
+	delivery := &model.EmailDelivery{
+		EventID:   n.EventID,
+		EventType: eventType,
+		UserID:    user.ID,
+		ToAddress: user.Email,
+		Language:  user.Language,
+		Subject:   rendered.Subject,
+		TextBody:  rendered.Text,
+		HTMLBody:  rendered.HTML,
+		Status:    model.EmailDeliveryPending,
+	}
+	if n.ID != "" {
+		delivery.NotificationID = &n.ID
+	}
+	if event.CompanyID != "" {
+		delivery.CompanyID = &event.CompanyID
+	}
+	return c.deliveryRepo.Create(ctx, delivery)
+}
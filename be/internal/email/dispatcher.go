@@ -0,0 +1,113 @@
+package email
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+	"hris-backend/pkg/mailer"
+)
+
+const (
+	dispatchPollInterval = 5 * time.Second
+	dispatchBatchSize    = 20
+
+	// dispatchLease is how long a claimed email is reserved for the
+	// dispatcher sending it
+	dispatchLease = 5 * time.Minute
+
+	// Failed sends are retried with exponential backoff from
+	// dispatchBaseBackoff up to dispatchMaxBackoff. After
+	// dispatchMaxAttempts, about a day, the email is marked failed.
+	dispatchBaseBackoff = 30 * time.Second
+	dispatchMaxBackoff  = time.Hour
+	dispatchMaxAttempts = 30
+)
+
+// Dispatcher sends the queued emails of the delivery log. An email is marked
+// sent once the SMTP server accepted it.
+type Dispatcher struct {
+	deliveryRepo repository.EmailDeliveryRepository
+	mailer       *mailer.Mailer
+}
+
+// NewDispatcher creates a dispatcher sending through m.
+func NewDispatcher(deliveryRepo repository.EmailDeliveryRepository, m *mailer.Mailer) *Dispatcher {
+	return &Dispatcher{
+		deliveryRepo: deliveryRepo,
+		mailer:       m,
+	}
+}
+
+// Start begins sending emails in a background loop.
+func (d *Dispatcher) Start() {
+	go func() {
+		for {
+			// The delivery log is read across companies
+			ctx := context.Background()
+			if d.dispatchBatch(ctx) < dispatchBatchSize {
+				time.Sleep(dispatchPollInterval)
+			}
+		}
+	}()
+}
+
+// dispatchBatch sends the emails that are due and returns how many it
+// claimed
+func (d *Dispatcher) dispatchBatch(ctx context.Context) int {
+	deliveries, err := d.deliveryRepo.ClaimDue(ctx, dispatchBatchSize, dispatchLease)
+	if err != nil {
+		log.Printf("[email] claim deliveries: %v", err)
+		return 0
+	}
+
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		err := d.mailer.Send(&mailer.Message{
+			To:      delivery.ToAddress,
+			Subject: delivery.Subject,
+			Text:    delivery.TextBody,
+			HTML:    delivery.HTMLBody,
+		})
+		if err != nil {
+			d.failed(ctx, delivery, err)
+			continue
+		}
+		if err := d.deliveryRepo.MarkSent(ctx, delivery.ID); err != nil {
+			log.Printf("[email] mark delivery %s sent: %v", delivery.ID, err)
+		}
+	}
+	return len(deliveries)
+}
+
+// failed schedules the next attempt of an email, or gives up on it
+func (d *Dispatcher) failed(ctx context.Context, delivery *model.EmailDelivery, sendErr error) {
+	attempts := delivery.Attempts + 1
+	if attempts >= dispatchMaxAttempts {
+		log.Printf("[email] giving up on delivery %s to %s after %d attempts: %v", delivery.ID, delivery.ToAddress, attempts, sendErr)
+		if err := d.deliveryRepo.MarkFailed(ctx, delivery.ID, sendErr.Error()); err != nil {
+			log.Printf("[email] mark delivery %s failed: %v", delivery.ID, err)
+		}
+		return
+	}
+
+	next := time.Now().Add(dispatchBackoff(attempts))
+	if err := d.deliveryRepo.MarkRetry(ctx, delivery.ID, sendErr.Error(), next); err != nil {
+		log.Printf("[email] reschedule delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// dispatchBackoff is the delay before the attempt following the given number
+// of failed attempts
+func dispatchBackoff(attempts int) time.Duration {
+	backoff := dispatchBaseBackoff
+	for i := 1; i < attempts && backoff < dispatchMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > dispatchMaxBackoff {
+		backoff = dispatchMaxBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,54 @@
+package email
+
+import (
+	"time"
+
+	"hris-backend/internal/model"
+	"hris-backend/pkg/mailer"
+)
+
+// passwordResetTemplate is the template of the password reset email. It is
+// not an event type, so notifications never render it.
+const passwordResetTemplate = "auth.password_reset"
+
+// PasswordResetMailer emails password reset tokens straight to the user.
+// Unlike notifications, the email is neither built from an event nor queued
+// in the delivery log, so the token is never stored in plain text.
+type PasswordResetMailer struct {
+	renderer *Renderer
+	mailer   *mailer.Mailer
+	appURL   string
+}
+
+// NewPasswordResetMailer creates a mailer whose emails link to appURL, the
+// frontend.
+func NewPasswordResetMailer(renderer *Renderer, m *mailer.Mailer, appURL string) *PasswordResetMailer {
+	return &PasswordResetMailer{
+		renderer: renderer,
+		mailer:   m,
+		appURL:   appURL,
+	}
+}
+
+// SendPasswordReset emails a reset token to the user, in their language.
+func (m *PasswordResetMailer) SendPasswordReset(user *model.User, token string, expiresAt time.Time) error {
+	rendered, err := m.renderer.Render(passwordResetTemplate, Data{
+		Lang:      user.Language,
+		Recipient: Recipient{ID: user.ID, Name: user.Name},
+		Payload: map[string]any{
+			"token":      token,
+			"expires_at": expiresAt.Format(time.RFC3339),
+		},
+		AppURL: m.appURL,
+	})
+	if err != nil {
+		return err
+	}
+	return m.mailer.Send(&mailer.Message{
+		To:      user.Email,
+		ToName:  user.Name,
+		Subject: rendered.Subject,
+		Text:    rendered.Text,
+		HTML:    rendered.HTML,
+	})
+}
@@ -0,0 +1,262 @@
+// Package email delivers notifications by email: it renders the email of a
+// notification from the templates of its event type, queues it in the
+// delivery log and sends it over SMTP in the background.
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"hris-backend/internal/model"
+)
+
+// templatesFS holds templates/layout.html and, per language,
+// templates/<lang>/<event type>.tmpl, plus the templates of emails sent
+// outside the notification pipeline such as password resets. A template
+// defines "subject", "text" and "html"; the html block is rendered inside the
+// layout.
+//
+//go:embed templates
+var templatesFS embed.FS
+
+// languages are the languages emails are written in; the first is the
+// fallback for users with any other
+var languages = []string{model.LanguageIndonesian, model.LanguageEnglish}
+
+// Data is what the templates of an email are executed with. Payload is the
+// event payload as decoded from JSON, so its numbers are float64.
+type Data struct {
+	Lang      string
+	Recipient Recipient
+	Payload   map[string]any
+	AppURL    string
+}
+
+// Recipient is the user an email is addressed to.
+type Recipient struct {
+	ID   string
+	Name string
+}
+
+// Rendered is an email ready to be sent.
+type Rendered struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type eventTemplates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// Renderer renders the emails of the event types that have templates.
+type Renderer struct {
+	templates map[string]map[string]*eventTemplates // event type, language
+}
+
+// NewRenderer parses the embedded templates. Every event type must have a
+// template in every language.
+func NewRenderer() (*Renderer, error) {
+	r := &Renderer{templates: make(map[string]map[string]*eventTemplates)}
+
+	for _, lang := range languages {
+		files, err := fs.Glob(templatesFS, "templates/"+lang+"/*.tmpl")
+		if err != nil {
+			return nil, err
+		}
+		funcs := templateFuncs(lang)
+
+		for _, file := range files {
+			eventType := strings.TrimSuffix(file[strings.LastIndex(file, "/")+1:], ".tmpl")
+
+			text, err := texttemplate.New(eventType).Funcs(funcs).ParseFS(templatesFS, file)
+			if err != nil {
+				return nil, fmt.Errorf("parse %s: %w", file, err)
+			}
+			html, err := htmltemplate.New(eventType).Funcs(funcs).ParseFS(templatesFS, "templates/layout.html", file)
+			if err != nil {
+				return nil, fmt.Errorf("parse %s: %w", file, err)
+			}
+			for _, name := range []string{"subject", "text"} {
+				if text.Lookup(name) == nil {
+					return nil, fmt.Errorf("%s does not define %q", file, name)
+				}
+			}
+			if html.Lookup("html") == nil {
+				return nil, fmt.Errorf("%s does not define \"html\"", file)
+			}
+
+			if r.templates[eventType] == nil {
+				r.templates[eventType] = make(map[string]*eventTemplates)
+			}
+			r.templates[eventType][lang] = &eventTemplates{text: text, html: html}
+		}
+	}
+
+	for eventType, byLang := range r.templates {
+		for _, lang := range languages {
+			if byLang[lang] == nil {
+				return nil, fmt.Errorf("event type %s has no %s template", eventType, lang)
+			}
+		}
+	}
+	return r, nil
+}
+
+// Has reports whether the event type has an email. Events without one are
+// notified in the application only.
+func (r *Renderer) Has(eventType string) bool {
+	return r.templates[eventType] != nil
+}
+
+// Render renders the email of an event in data.Lang, or in the fallback
+// language if there are no templates in it.
+func (r *Renderer) Render(eventType string, data Data) (*Rendered, error) {
+	byLang := r.templates[eventType]
+	if byLang == nil {
+		return nil, fmt.Errorf("no email template for event type %s", eventType)
+	}
+	t := byLang[data.Lang]
+	if t == nil {
+		data.Lang = languages[0]
+		t = byLang[data.Lang]
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := t.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if err := t.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return nil, err
+	}
+	if err := t.html.ExecuteTemplate(&html, "layout", data); err != nil {
+		return nil, err
+	}
+	return &Rendered{
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
+
+var monthNames = map[string][12]string{
+	model.LanguageIndonesian: {"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"},
+	model.LanguageEnglish:    {"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+}
+
+var leaveTypeNames = map[string]map[string]string{
+	model.LanguageIndonesian: {
+		string(model.LeaveCutiTahunan):    "cuti tahunan",
+		string(model.LeaveCutiSakit):      "cuti sakit",
+		string(model.LeaveCutiMelahirkan): "cuti melahirkan",
+		string(model.LeaveCutiBesar):      "cuti besar",
+		string(model.LeaveIzin):           "izin",
+		string(model.LeaveDinasLuar):      "dinas luar",
+	},
+	model.LanguageEnglish: {
+		string(model.LeaveCutiTahunan):    "annual leave",
+		string(model.LeaveCutiSakit):      "sick leave",
+		string(model.LeaveCutiMelahirkan): "maternity leave",
+		string(model.LeaveCutiBesar):      "long leave",
+		string(model.LeaveIzin):           "permission",
+		string(model.LeaveDinasLuar):      "business trip",
+	},
+}
+
+// templateFuncs are the functions of the templates in lang. Values that do
+// not parse are printed as they are.
+func templateFuncs(lang string) map[string]any {
+	months := monthNames[lang]
+
+	formatDate := func(t time.Time) string {
+		return fmt.Sprintf("%d %s %d", t.Day(), months[t.Month()-1], t.Year())
+	}
+
+	return map[string]any{
+		// number prints a JSON number without decimals when it has none
+		"number": func(v any) string {
+			f, ok := v.(float64)
+			if !ok {
+				return fmt.Sprint(v)
+			}
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		},
+		// rupiah prints an amount as Rp 1.234.567
+		"rupiah": func(v any) string {
+			f, ok := v.(float64)
+			if !ok {
+				return fmt.Sprint(v)
+			}
+			digits := strconv.FormatFloat(f, 'f', 0, 64)
+			sign := ""
+			if strings.HasPrefix(digits, "-") {
+				sign, digits = "-", digits[1:]
+			}
+			var b strings.Builder
+			for i, d := range digits {
+				if i > 0 && (len(digits)-i)%3 == 0 {
+					b.WriteByte('.')
+				}
+				b.WriteRune(d)
+			}
+			return sign + "Rp " + b.String()
+		},
+		// date prints a YYYY-MM-DD date as 17 Oktober 2026
+		"date": func(v any) string {
+			s := fmt.Sprint(v)
+			t, err := time.Parse("2006-01-02", s)
+			if err != nil {
+				return s
+			}
+			return formatDate(t)
+		},
+		// datetime prints an RFC 3339 time in server time as 17 Oktober 2026 14:05
+		"datetime": func(v any) string {
+			s := fmt.Sprint(v)
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return s
+			}
+			t = t.Local()
+			return formatDate(t) + " " + t.Format("15:04")
+		},
+		// period prints a payroll period, written as October 2026, in lang
+		"period": func(v any) string {
+			s := fmt.Sprint(v)
+			t, err := time.Parse("January 2006", s)
+			if err != nil {
+				return s
+			}
+			return fmt.Sprintf("%s %d", months[t.Month()-1], t.Year())
+		},
+		// sub subtracts two numbers, JSON or Go ints
+		"sub": func(a, b any) float64 {
+			return toFloat(a) - toFloat(b)
+		},
+		"leaveType": func(v any) string {
+			s := fmt.Sprint(v)
+			if name, ok := leaveTypeNames[lang][s]; ok {
+				return name
+			}
+			return strings.ReplaceAll(s, "_", " ")
+		},
+	}
+}
+
+func toFloat(v any) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	}
+	return 0
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"strconv"
+
+	"hris-backend/internal/dto"
+	"hris-backend/internal/middleware"
+	"hris-backend/internal/permissions"
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AnnouncementHandler struct {
+	announcementService service.AnnouncementService
+}
+
+func NewAnnouncementHandler(announcementService service.AnnouncementService) *AnnouncementHandler {
+	return &AnnouncementHandler{announcementService: announcementService}
+}
+
+// GetMine godoc
+// @Summary Get my announcements
+// @Description Returns the announcements shown now to the authenticated user, pinned first, then newest first, each telling whether the user read it. Employees see the announcements for their whole company and for their department or job level; users without an employee record see the ones for the whole company
+// @Tags Announcements
+// @Security Bearer
+// @Produce json
+// @Param company_id query string false "Company, for users without an employee record (defaults to their primary company)"
+// @Success 200 {object} response.Response{data=[]dto.AnnouncementResponse} "Announcements retrieved"
+// @Failure 400 {object} response.Response "No company"
+// @Router /announcements [get]
+func (h *AnnouncementHandler) GetMine(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	companyID := c.Query("company_id")
+	if companyID == "" {
+		companyID, _ = c.Locals("companyID").(string)
+	}
+
+	announcements, err := h.announcementService.GetMine(c.UserContext(), userID, companyID)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Announcements retrieved", announcements)
+}
+
+// GetAll godoc
+// @Summary Get all announcements
+// @Description Retrieve the announcements of the companies in scope, drafts included, pinned first, then newest first, with optional filters and pagination
+// @Tags Announcements
+// @Security Bearer
+// @Produce json
+// @Param company_id query string false "Filter by company"
+// @Param status query string false "Filter by status (draft, scheduled, published, expired)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} response.Response{data=dto.PaginatedAnnouncementResponse} "Announcements retrieved"
+// @Failure 400 {object} response.Response "Invalid filter"
+// @Router /announcements/manage [get]
+func (h *AnnouncementHandler) GetAll(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	result, err := h.announcementService.GetAllPaginated(c.UserContext(), page, limit, c.Query("company_id"), c.Query("status"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Announcements retrieved", result)
+}
+
+// GetByID godoc
+// @Summary Get an announcement
+// @Description Users who manage announcements get any announcement in scope; others only the ones shown to them
+// @Tags Announcements
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Announcement ID"
+// @Success 200 {object} response.Response{data=dto.AnnouncementResponse} "Announcement retrieved"
+// @Failure 404 {object} response.Response "Announcement not found"
+// @Router /announcements/{id} [get]
+func (h *AnnouncementHandler) GetByID(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	canManage := middleware.HasPermission(c, permissions.AnnouncementsManage)
+
+	announcement, err := h.announcementService.GetByID(c.UserContext(), c.Params("id"), userID, canManage)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Announcement retrieved", announcement)
+}
+
+// Create godoc
+// @Summary Create an announcement
+// @Description Creates a draft announcement. Without department_ids and job_level_ids it is for the whole company; otherwise for the employees of any of the departments and job levels. The body is HTML: only basic formatting and links are kept. Attachments are linked by URL
+// @Tags Announcements
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAnnouncementRequest true "Announcement data"
+// @Success 201 {object} response.Response{data=dto.AnnouncementResponse} "Announcement created"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /announcements [post]
+func (h *AnnouncementHandler) Create(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req dto.CreateAnnouncementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if req.CompanyID == "" {
+		req.CompanyID, _ = c.Locals("companyID").(string)
+	}
+	if req.CompanyID == "" {
+		return response.Error(c, fiber.StatusBadRequest, "company_id is required")
+	}
+
+	announcement, err := h.announcementService.Create(c.UserContext(), userID, req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusCreated, "Announcement created", announcement)
+}
+
+// Update godoc
+// @Summary Update an announcement
+// @Description Changes the fields that are set; department_ids, job_level_ids and attachments replace the current ones. Editing a published announcement does not notify anyone again
+// @Tags Announcements
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "Announcement ID"
+// @Param request body dto.UpdateAnnouncementRequest true "Fields to update"
+// @Success 200 {object} response.Response{data=dto.AnnouncementResponse} "Announcement updated"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /announcements/{id} [put]
+func (h *AnnouncementHandler) Update(c *fiber.Ctx) error {
+	var req dto.UpdateAnnouncementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	announcement, err := h.announcementService.Update(c.UserContext(), c.Params("id"), req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Announcement updated", announcement)
+}
+
+// Publish godoc
+// @Summary Publish an announcement
+// @Description Shows an announcement from publish_at, or right away. Its audience is notified when it is first shown, in the application and by email as each user chose for announcement.published
+// @Tags Announcements
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "Announcement ID"
+// @Param request body dto.PublishAnnouncementRequest false "When to publish"
+// @Success 200 {object} response.Response{data=dto.AnnouncementResponse} "Announcement published"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /announcements/{id}/publish [post]
+func (h *AnnouncementHandler) Publish(c *fiber.Ctx) error {
+	var req dto.PublishAnnouncementRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	announcement, err := h.announcementService.Publish(c.UserContext(), c.Params("id"), req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Announcement published", announcement)
+}
+
+// Unpublish godoc
+// @Summary Unpublish an announcement
+// @Description Takes an announcement back to draft
+// @Tags Announcements
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Announcement ID"
+// @Success 200 {object} response.Response{data=dto.AnnouncementResponse} "Announcement unpublished"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /announcements/{id}/unpublish [post]
+func (h *AnnouncementHandler) Unpublish(c *fiber.Ctx) error {
+	announcement, err := h.announcementService.Unpublish(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Announcement unpublished", announcement)
+}
+
+// Delete godoc
+// @Summary Delete an announcement
+// @Tags Announcements
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Announcement ID"
+// @Success 200 {object} response.Response "Announcement deleted"
+// @Failure 404 {object} response.Response "Announcement not found"
+// @Router /announcements/{id} [delete]
+func (h *AnnouncementHandler) Delete(c *fiber.Ctx) error {
+	if err := h.announcementService.Delete(c.UserContext(), c.Params("id")); err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Announcement deleted", nil)
+}
+
+// MarkAsRead godoc
+// @Summary Mark an announcement as read
+// @Description Records that the authenticated user read an announcement shown to them. Reading it again keeps the first receipt
+// @Tags Announcements
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Announcement ID"
+// @Success 200 {object} response.Response "Announcement marked as read"
+// @Failure 404 {object} response.Response "Announcement not found"
+// @Router /announcements/{id}/read [post]
+func (h *AnnouncementHandler) MarkAsRead(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	if err := h.announcementService.MarkRead(c.UserContext(), c.Params("id"), userID); err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Announcement marked as read", nil)
+}
+
+// GetReadReport godoc
+// @Summary Get the read report of an announcement
+// @Description Tells how many of the current employees an announcement is for read it, and lists the ones who have not, by employee number
+// @Tags Announcements
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Announcement ID"
+// @Success 200 {object} response.Response{data=dto.AnnouncementReadReportResponse} "Read report retrieved"
+// @Failure 404 {object} response.Response "Announcement not found"
+// @Router /announcements/{id}/read-report [get]
+func (h *AnnouncementHandler) GetReadReport(c *fiber.Ctx) error {
+	report, err := h.announcementService.GetReadReport(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Read report retrieved", report)
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"hris-backend/internal/dto"
+	"hris-backend/internal/permissions"
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type APITokenHandler struct {
+	apiTokenService service.APITokenService
+}
+
+func NewAPITokenHandler(apiTokenService service.APITokenService) *APITokenHandler {
+	return &APITokenHandler{apiTokenService: apiTokenService}
+}
+
+// GetMine godoc
+// @Summary Get my API tokens
+// @Description Retrieve the current user's personal API tokens that are not revoked, with their last use
+// @Tags API Tokens
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.APITokenResponse} "API tokens retrieved"
+// @Failure 500 {object} response.Response "Failed to fetch API tokens"
+// @Router /auth/tokens [get]
+func (h *APITokenHandler) GetMine(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	tokens, err := h.apiTokenService.GetByUserID(c.UserContext(), userID)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "API tokens retrieved", tokens)
+}
+
+// CreateMine godoc
+// @Summary Create a personal API token
+// @Description Create a token for scripts, sent as "Authorization: Bearer hris_...". It acts for the current user within one company (the primary company when not given) and with at most the listed permissions, which must be the user's own. Tokens act through these permissions alone, never the user's role: superadmin-only routes refuse them. The token is shown only in this response
+// @Tags API Tokens
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAPITokenRequest true "Token data"
+// @Success 201 {object} response.Response{data=dto.CreatedAPITokenResponse} "API token created"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Failure 403 {object} response.Response "API tokens cannot create API tokens"
+// @Router /auth/tokens [post]
+func (h *APITokenHandler) CreateMine(c *fiber.Ctx) error {
+	if c.Locals("apiTokenID") != nil {
+		return response.Error(c, fiber.StatusForbidden, "API tokens cannot create API tokens")
+	}
+
+	userID := c.Locals("userID").(string)
+	granted, _ := c.Locals("permissions").(permissions.Set)
+
+	var req dto.CreateAPITokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.Name == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Name is required")
+	}
+	if req.CompanyID == "" {
+		req.CompanyID, _ = c.Locals("companyID").(string)
+	}
+
+	token, err := h.apiTokenService.Create(c.UserContext(), userID, granted, req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusCreated, "API token created", token)
+}
+
+// RevokeMine godoc
+// @Summary Revoke one of my API tokens
+// @Description Revoke a personal API token. It stops working immediately
+// @Tags API Tokens
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Token ID"
+// @Success 200 {object} response.Response "API token revoked"
+// @Failure 404 {object} response.Response "API token not found"
+// @Router /auth/tokens/{id} [delete]
+func (h *APITokenHandler) RevokeMine(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	id := c.Params("id")
+
+	if err := h.apiTokenService.Revoke(c.UserContext(), userID, id); err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "API token revoked", nil)
+}
+
+// GetByUser godoc
+// @Summary Get a user's API tokens
+// @Description Retrieve the API tokens of a user in your companies
+// @Tags Users
+// @Security Bearer
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response{data=[]dto.APITokenResponse} "API tokens retrieved"
+// @Failure 404 {object} response.Response "User not found"
+// @Router /users/{id}/tokens [get]
+func (h *APITokenHandler) GetByUser(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	tokens, err := h.apiTokenService.GetByUserID(c.UserContext(), id)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "API tokens retrieved", tokens)
+}
+
+// RevokeForUser godoc
+// @Summary Revoke a user's API token
+// @Description Revoke an API token of a user in your companies
+// @Tags Users
+// @Security Bearer
+// @Produce json
+// @Param id path string true "User ID"
+// @Param tokenId path string true "Token ID"
+// @Success 200 {object} response.Response "API token revoked"
+// @Failure 404 {object} response.Response "API token not found"
+// @Router /users/{id}/tokens/{tokenId} [delete]
+func (h *APITokenHandler) RevokeForUser(c *fiber.Ctx) error {
+	id := c.Params("id")
+	tokenID := c.Params("tokenId")
+
+	if err := h.apiTokenService.Revoke(c.UserContext(), id, tokenID); err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "API token revoked", nil)
+}
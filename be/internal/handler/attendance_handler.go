@@ -73,7 +73,7 @@ func (h *AttendanceHandler) GetAll(c *fiber.Ctx) error {
 		year = y
 	}
 
-	result, err := h.attService.GetAllPaginated(page, limit, employeeID, month, year, startDate, endDate)
+	result, err := h.attService.GetAllPaginated(c.UserContext(), page, limit, employeeID, month, year, startDate, endDate)
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch attendances")
 	}
@@ -92,7 +92,7 @@ func (h *AttendanceHandler) GetAll(c *fiber.Ctx) error {
 // @Router /attendances/{id} [get]
 func (h *AttendanceHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
-	att, err := h.attService.GetByID(id)
+	att, err := h.attService.GetByID(c.UserContext(), id)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -120,7 +120,7 @@ func (h *AttendanceHandler) ClockIn(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Employee ID is required")
 	}
 
-	att, err := h.attService.ClockIn(req)
+	att, err := h.attService.ClockIn(c.UserContext(), req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -147,7 +147,7 @@ func (h *AttendanceHandler) ClockOut(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	att, err := h.attService.ClockOut(id, req)
+	att, err := h.attService.ClockOut(c.UserContext(), id, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -175,7 +175,7 @@ func (h *AttendanceHandler) Create(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Employee ID, date, and status are required")
 	}
 
-	att, err := h.attService.Create(req)
+	att, err := h.attService.Create(c.UserContext(), req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -202,7 +202,7 @@ func (h *AttendanceHandler) Update(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	att, err := h.attService.Update(id, req)
+	att, err := h.attService.Update(c.UserContext(), id, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -222,7 +222,7 @@ func (h *AttendanceHandler) Update(c *fiber.Ctx) error {
 func (h *AttendanceHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	if err := h.attService.Delete(id); err != nil {
+	if err := h.attService.Delete(c.UserContext(), id); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	return response.Success(c, fiber.StatusOK, "Attendance deleted", nil)
@@ -287,7 +287,7 @@ func (h *AttendanceHandler) Import(c *fiber.Ctx) error {
 	}
 
 	// Build employee cache by employee_number
-	allEmployees, err := h.empService.GetAll()
+	allEmployees, err := h.empService.GetAll(c.UserContext())
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch employees")
 	}
@@ -387,7 +387,7 @@ func (h *AttendanceHandler) Import(c *fiber.Ctx) error {
 			req.Notes = notes
 		}
 
-		_, err := h.attService.Create(req)
+		_, err := h.attService.Create(c.UserContext(), req)
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("Row %d: %s", rowNum, err.Error()))
 			continue
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"time"
 
 	"hris-backend/internal/dto"
@@ -20,7 +21,7 @@ func NewAuthHandler(authService service.AuthService) *AuthHandler {
 
 // Login godoc
 // @Summary User login
-// @Description Authenticate user with email and password, returns access token and sets refresh token in cookie
+// @Description Authenticate user with email and password, returns access token and sets refresh token in cookie. When two-factor authentication is enabled, returns a challenge token to complete at /auth/2fa/verify instead. Unknown emails and throttled or locked accounts get the same 401 as a wrong password
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -28,6 +29,7 @@ func NewAuthHandler(authService service.AuthService) *AuthHandler {
 // @Success 200 {object} response.Response{data=dto.TokenResponse} "Login successful"
 // @Failure 400 {object} response.Response "Invalid request body"
 // @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 429 {object} response.Response "Too many failed login attempts from this IP"
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	var req dto.LoginRequest
@@ -39,27 +41,53 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Email and password are required")
 	}
 
-	tokenResp, refreshToken, err := h.authService.Login(req)
+	tokenResp, refreshToken, err := h.authService.Login(c.UserContext(), req, c.Get(fiber.HeaderUserAgent), c.IP())
 	if err != nil {
-		return response.Error(c, fiber.StatusUnauthorized, err.Error())
+		return h.loginError(c, err)
 	}
 
-	c.Cookie(&fiber.Cookie{
-		Name:     "refresh_token",
-		Value:    refreshToken,
-		Expires:  time.Now().Add(7 * 24 * time.Hour),
-		HTTPOnly: true,
-		Secure:   false,
-		SameSite: "Lax",
-		Path:     "/",
-	})
+	if tokenResp.TwoFactorRequired {
+		return response.Success(c, fiber.StatusOK, "Two-factor code required", tokenResp)
+	}
+
+	setRefreshCookie(c, refreshToken)
+	return response.Success(c, fiber.StatusOK, "Login successful", tokenResp)
+}
+
+// VerifyTwoFactor godoc
+// @Summary Complete a two-factor login
+// @Description Trade the challenge token from login and a TOTP or recovery code for an access token; sets the refresh token in cookie. A challenge token can only be presented once: after a wrong code, log in again
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body dto.VerifyTwoFactorRequest true "Challenge token and code"
+// @Success 200 {object} response.Response{data=dto.TokenResponse} "Login successful"
+// @Failure 400 {object} response.Response "Invalid request body"
+// @Failure 401 {object} response.Response "Invalid code or challenge"
+// @Failure 429 {object} response.Response "Too many failed login attempts or account locked"
+// @Router /auth/2fa/verify [post]
+func (h *AuthHandler) VerifyTwoFactor(c *fiber.Ctx) error {
+	var req dto.VerifyTwoFactorRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.ChallengeToken == "" || req.Code == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Challenge token and code are required")
+	}
 
+	tokenResp, refreshToken, err := h.authService.VerifyTwoFactor(c.UserContext(), req, c.Get(fiber.HeaderUserAgent), c.IP())
+	if err != nil {
+		return h.loginError(c, err)
+	}
+
+	setRefreshCookie(c, refreshToken)
 	return response.Success(c, fiber.StatusOK, "Login successful", tokenResp)
 }
 
 // Refresh godoc
 // @Summary Refresh access token
-// @Description Generate new access token using refresh token from cookie
+// @Description Generate new access token using refresh token from cookie. The refresh token is rotated; presenting an already used refresh token revokes its session
 // @Tags Authentication
 // @Produce json
 // @Success 200 {object} response.Response{data=dto.TokenResponse} "Token refreshed"
@@ -71,33 +99,30 @@ func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusUnauthorized, "Refresh token not found")
 	}
 
-	tokenResp, newRefreshToken, err := h.authService.RefreshToken(refreshToken)
+	tokenResp, newRefreshToken, err := h.authService.RefreshToken(c.UserContext(), refreshToken, c.Get(fiber.HeaderUserAgent), c.IP())
 	if err != nil {
 		return response.Error(c, fiber.StatusUnauthorized, err.Error())
 	}
 
-	c.Cookie(&fiber.Cookie{
-		Name:     "refresh_token",
-		Value:    newRefreshToken,
-		Expires:  time.Now().Add(7 * 24 * time.Hour),
-		HTTPOnly: true,
-		Secure:   false,
-		SameSite: "Lax",
-		Path:     "/",
-	})
-
+	setRefreshCookie(c, newRefreshToken)
 	return response.Success(c, fiber.StatusOK, "Token refreshed", tokenResp)
 }
 
 // Logout godoc
 // @Summary User logout
-// @Description Clear refresh token cookie to logout user
+// @Description Revoke the current session and clear the refresh token cookie
 // @Tags Authentication
 // @Security Bearer
 // @Produce json
 // @Success 200 {object} response.Response "Logged out successfully"
+// @Failure 500 {object} response.Response "Failed to revoke session"
 // @Router /auth/logout [post]
 func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	sessionID, _ := c.Locals("sessionID").(string)
+	if err := h.authService.Logout(c.UserContext(), c.Cookies("refresh_token"), sessionID); err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+
 	c.Cookie(&fiber.Cookie{
 		Name:     "refresh_token",
 		Value:    "",
@@ -110,3 +135,117 @@ func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 
 	return response.Success(c, fiber.StatusOK, "Logged out successfully", nil)
 }
+
+// ForgotPassword godoc
+// @Summary Request a password reset
+// @Description Email a single-use password reset token to the account with this email; it is only sent when SMTP is configured. The response is the same whether or not the email is registered
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body dto.ForgotPasswordRequest true "Account email"
+// @Success 200 {object} response.Response "Reset instructions sent"
+// @Failure 400 {object} response.Response "Invalid request body"
+// @Router /auth/forgot-password [post]
+func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
+	var req dto.ForgotPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.Email == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Email is required")
+	}
+
+	if err := h.authService.ForgotPassword(c.UserContext(), req); err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, fiber.StatusOK, "If the email is registered, reset instructions have been sent", nil)
+}
+
+// ResetPassword godoc
+// @Summary Reset a forgotten password
+// @Description Set a new password with a reset token. The token can be used once; all sessions of the account are logged out
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body dto.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} response.Response "Password reset"
+// @Failure 400 {object} response.Response "Invalid or expired reset token"
+// @Router /auth/reset-password [post]
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	var req dto.ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.Token == "" || req.NewPassword == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Token and new password are required")
+	}
+
+	if len(req.NewPassword) < 6 {
+		return response.Error(c, fiber.StatusBadRequest, "Password must be at least 6 characters")
+	}
+
+	if err := h.authService.ResetPassword(c.UserContext(), req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return response.Success(c, fiber.StatusOK, "Password reset", nil)
+}
+
+// ChangePassword godoc
+// @Summary Change my password
+// @Description Change the current user's password after checking the current one. Other sessions are logged out and a new access token is returned. Users flagged to change their password can call no other route until they do
+// @Tags Authentication
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} response.Response{data=dto.TokenResponse} "Password changed"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /auth/change-password [post]
+func (h *AuthHandler) ChangePassword(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	sessionID, _ := c.Locals("sessionID").(string)
+
+	var req dto.ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Current and new password are required")
+	}
+
+	if len(req.NewPassword) < 6 {
+		return response.Error(c, fiber.StatusBadRequest, "Password must be at least 6 characters")
+	}
+
+	tokenResp, err := h.authService.ChangePassword(c.UserContext(), userID, sessionID, req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return response.Success(c, fiber.StatusOK, "Password changed", tokenResp)
+}
+
+// loginError answers a failed login. Throttled and locked logins get 429.
+func (h *AuthHandler) loginError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, service.ErrLoginThrottled) || errors.Is(err, service.ErrAccountLocked) {
+		return response.Error(c, fiber.StatusTooManyRequests, err.Error())
+	}
+	return response.Error(c, fiber.StatusUnauthorized, err.Error())
+}
+
+func setRefreshCookie(c *fiber.Ctx, refreshToken string) {
+	c.Cookie(&fiber.Cookie{
+		Name:     "refresh_token",
+		Value:    refreshToken,
+		Expires:  time.Now().Add(7 * 24 * time.Hour),
+		HTTPOnly: true,
+		Secure:   false,
+		SameSite: "Lax",
+		Path:     "/",
+	})
+}
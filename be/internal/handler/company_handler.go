@@ -45,7 +45,7 @@ func (h *CompanyHandler) GetAll(c *fiber.Ctx) error {
 	sortBy := c.Query("sort_by", "created_at")
 	sortOrder := c.Query("sort_order", "desc")
 
-	result, err := h.companyService.GetAllPaginated(page, limit, search, sortBy, sortOrder)
+	result, err := h.companyService.GetAllPaginated(c.UserContext(), page, limit, search, sortBy, sortOrder)
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch companies")
 	}
@@ -64,7 +64,7 @@ func (h *CompanyHandler) GetAll(c *fiber.Ctx) error {
 // @Router /companies/{id} [get]
 func (h *CompanyHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
-	company, err := h.companyService.GetByID(id)
+	company, err := h.companyService.GetByID(c.UserContext(), id)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -92,7 +92,7 @@ func (h *CompanyHandler) Create(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Company name is required")
 	}
 
-	company, err := h.companyService.Create(req)
+	company, err := h.companyService.Create(c.UserContext(), req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -119,7 +119,7 @@ func (h *CompanyHandler) Update(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	company, err := h.companyService.Update(id, req)
+	company, err := h.companyService.Update(c.UserContext(), id, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -139,7 +139,7 @@ func (h *CompanyHandler) Update(c *fiber.Ctx) error {
 func (h *CompanyHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	if err := h.companyService.Delete(id); err != nil {
+	if err := h.companyService.Delete(c.UserContext(), id); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	return response.Success(c, fiber.StatusOK, "Company deleted", nil)
@@ -166,7 +166,7 @@ func (h *CompanyHandler) DeleteMultiple(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "No IDs provided")
 	}
 
-	if err := h.companyService.DeleteMultiple(req.IDs); err != nil {
+	if err := h.companyService.DeleteMultiple(c.UserContext(), req.IDs); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	return response.Success(c, fiber.StatusOK, "Companies deleted", nil)
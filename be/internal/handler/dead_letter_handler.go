@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"strconv"
+
+	"hris-backend/internal/dto"
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type DeadLetterHandler struct {
+	deadLetterService service.DeadLetterService
+}
+
+func NewDeadLetterHandler(deadLetterService service.DeadLetterService) *DeadLetterHandler {
+	return &DeadLetterHandler{deadLetterService: deadLetterService}
+}
+
+// GetAll godoc
+// @Summary Get dead-lettered events
+// @Description Retrieve the events the notification consumer gave up on after retrying them, newest first, with optional filters and pagination. Events outside any company are visible to superadmins only
+// @Tags Dead Letters
+// @Security Bearer
+// @Produce json
+// @Param status query string false "Filter by status (pending, replayed)"
+// @Param event_type query string false "Filter by event type, e.g. leave.submitted"
+// @Param start_date query string false "Filter start date of the failure (YYYY-MM-DD)"
+// @Param end_date query string false "Filter end date of the failure (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} response.Response{data=dto.PaginatedDeadLetterResponse} "Dead letters retrieved"
+// @Failure 500 {object} response.Response "Failed to fetch dead letters"
+// @Router /dead-letters [get]
+func (h *DeadLetterHandler) GetAll(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	result, err := h.deadLetterService.GetAllPaginated(
+		c.UserContext(), page, limit,
+		c.Query("status"), c.Query("event_type"), c.Query("start_date"), c.Query("end_date"),
+	)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch dead letters")
+	}
+	return response.Success(c, fiber.StatusOK, "Dead letters retrieved", result)
+}
+
+// GetByID godoc
+// @Summary Get a dead-lettered event
+// @Description Retrieve a dead-lettered event with the original message, its key and headers, and the error it failed with
+// @Tags Dead Letters
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Dead letter ID"
+// @Success 200 {object} response.Response{data=dto.DeadLetterDetailResponse} "Dead letter retrieved"
+// @Failure 404 {object} response.Response "Dead letter not found"
+// @Router /dead-letters/{id} [get]
+func (h *DeadLetterHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	result, err := h.deadLetterService.GetByID(c.UserContext(), id)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Dead letter retrieved", result)
+}
+
+// Replay godoc
+// @Summary Replay a dead-lettered event
+// @Description Publish a pending dead-lettered event again to its original topic, once the cause of the failure is fixed. It keeps its event ID, so users already notified are not notified twice
+// @Tags Dead Letters
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Dead letter ID"
+// @Success 200 {object} response.Response "Dead letter replayed"
+// @Failure 400 {object} response.Response "Dead letter not found or already replayed"
+// @Router /dead-letters/{id}/replay [post]
+func (h *DeadLetterHandler) Replay(c *fiber.Ctx) error {
+	id := c.Params("id")
+	userID := c.Locals("userID").(string)
+
+	if err := h.deadLetterService.Replay(c.UserContext(), id, userID); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Dead letter replayed", nil)
+}
+
+// ReplayMany godoc
+// @Summary Replay dead-lettered events
+// @Description Publish pending dead-lettered events again, selected by ID, by event type or both, oldest first and at most 500 per request
+// @Tags Dead Letters
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.ReplayDeadLettersRequest true "Dead letters to replay"
+// @Success 200 {object} response.Response{data=dto.ReplayDeadLettersResponse} "Dead letters replayed"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /dead-letters/replay [post]
+func (h *DeadLetterHandler) ReplayMany(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req dto.ReplayDeadLettersRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	result, err := h.deadLetterService.ReplayMany(c.UserContext(), req, userID)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Dead letters replayed", result)
+}
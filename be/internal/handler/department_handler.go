@@ -29,14 +29,14 @@ func NewDepartmentHandler(deptService service.DepartmentService) *DepartmentHand
 func (h *DepartmentHandler) GetAll(c *fiber.Ctx) error {
 	companyID := c.Query("company_id")
 	if companyID != "" {
-		depts, err := h.deptService.GetByCompanyID(companyID)
+		depts, err := h.deptService.GetByCompanyID(c.UserContext(), companyID)
 		if err != nil {
 			return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch departments")
 		}
 		return response.Success(c, fiber.StatusOK, "Departments retrieved", depts)
 	}
 
-	depts, err := h.deptService.GetAll()
+	depts, err := h.deptService.GetAll(c.UserContext())
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch departments")
 	}
@@ -55,7 +55,7 @@ func (h *DepartmentHandler) GetAll(c *fiber.Ctx) error {
 // @Router /departments/{id} [get]
 func (h *DepartmentHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
-	dept, err := h.deptService.GetByID(id)
+	dept, err := h.deptService.GetByID(c.UserContext(), id)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -83,7 +83,7 @@ func (h *DepartmentHandler) Create(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Company ID and name are required")
 	}
 
-	dept, err := h.deptService.Create(req)
+	dept, err := h.deptService.Create(c.UserContext(), req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -110,7 +110,7 @@ func (h *DepartmentHandler) Update(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	dept, err := h.deptService.Update(id, req)
+	dept, err := h.deptService.Update(c.UserContext(), id, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -130,7 +130,7 @@ func (h *DepartmentHandler) Update(c *fiber.Ctx) error {
 func (h *DepartmentHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	if err := h.deptService.Delete(id); err != nil {
+	if err := h.deptService.Delete(c.UserContext(), id); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	return response.Success(c, fiber.StatusOK, "Department deleted", nil)
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"strconv"
+
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type EmailDeliveryHandler struct {
+	deliveryService service.EmailDeliveryService
+}
+
+func NewEmailDeliveryHandler(deliveryService service.EmailDeliveryService) *EmailDeliveryHandler {
+	return &EmailDeliveryHandler{deliveryService: deliveryService}
+}
+
+// GetAll godoc
+// @Summary Get email deliveries
+// @Description Retrieve the delivery log of notification emails, newest first, with optional filters and pagination. Pending emails are retried with backoff until they are sent or marked failed. Emails outside any company are visible to superadmins only
+// @Tags Email Deliveries
+// @Security Bearer
+// @Produce json
+// @Param status query string false "Filter by status (pending, sent, failed)"
+// @Param event_type query string false "Filter by event type, e.g. leave.submitted"
+// @Param user_id query string false "Filter by recipient user ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} response.Response{data=dto.PaginatedEmailDeliveryResponse} "Email deliveries retrieved"
+// @Failure 500 {object} response.Response "Failed to fetch email deliveries"
+// @Router /email-deliveries [get]
+func (h *EmailDeliveryHandler) GetAll(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	result, err := h.deliveryService.GetAllPaginated(
+		c.UserContext(), page, limit,
+		c.Query("status"), c.Query("event_type"), c.Query("user_id"),
+	)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch email deliveries")
+	}
+	return response.Success(c, fiber.StatusOK, "Email deliveries retrieved", result)
+}
+
+// GetByID godoc
+// @Summary Get an email delivery
+// @Description Retrieve a notification email with its text and HTML bodies and the error of its last failed attempt
+// @Tags Email Deliveries
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Email delivery ID"
+// @Success 200 {object} response.Response{data=dto.EmailDeliveryDetailResponse} "Email delivery retrieved"
+// @Failure 404 {object} response.Response "Email delivery not found"
+// @Router /email-deliveries/{id} [get]
+func (h *EmailDeliveryHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	result, err := h.deliveryService.GetByID(c.UserContext(), id)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Email delivery retrieved", result)
+}
@@ -29,14 +29,14 @@ func NewEmployeeHandler(empService service.EmployeeService) *EmployeeHandler {
 func (h *EmployeeHandler) GetAll(c *fiber.Ctx) error {
 	companyID := c.Query("company_id")
 	if companyID != "" {
-		employees, err := h.empService.GetByCompanyID(companyID)
+		employees, err := h.empService.GetByCompanyID(c.UserContext(), companyID)
 		if err != nil {
 			return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch employees")
 		}
 		return response.Success(c, fiber.StatusOK, "Employees retrieved", employees)
 	}
 
-	employees, err := h.empService.GetAll()
+	employees, err := h.empService.GetAll(c.UserContext())
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch employees")
 	}
@@ -55,7 +55,7 @@ func (h *EmployeeHandler) GetAll(c *fiber.Ctx) error {
 // @Router /employees/{id} [get]
 func (h *EmployeeHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
-	emp, err := h.empService.GetByID(id)
+	emp, err := h.empService.GetByID(c.UserContext(), id)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -73,7 +73,7 @@ func (h *EmployeeHandler) GetByID(c *fiber.Ctx) error {
 // @Router /employees/me [get]
 func (h *EmployeeHandler) GetMe(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
-	emp, err := h.empService.GetByUserID(userID)
+	emp, err := h.empService.GetByUserID(c.UserContext(), userID)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -113,7 +113,7 @@ func (h *EmployeeHandler) Create(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid employee status. Must be tetap, kontrak, or probation")
 	}
 
-	emp, err := h.empService.Create(req)
+	emp, err := h.empService.Create(c.UserContext(), req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -144,7 +144,7 @@ func (h *EmployeeHandler) Update(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid employee status. Must be tetap, kontrak, or probation")
 	}
 
-	emp, err := h.empService.Update(id, req)
+	emp, err := h.empService.Update(c.UserContext(), id, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -164,7 +164,7 @@ func (h *EmployeeHandler) Update(c *fiber.Ctx) error {
 func (h *EmployeeHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	if err := h.empService.Delete(id); err != nil {
+	if err := h.empService.Delete(c.UserContext(), id); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	return response.Success(c, fiber.StatusOK, "Employee deleted", nil)
@@ -29,14 +29,14 @@ func NewEmployeeSalaryHandler(salaryService service.EmployeeSalaryService) *Empl
 func (h *EmployeeSalaryHandler) GetAll(c *fiber.Ctx) error {
 	employeeID := c.Query("employee_id")
 	if employeeID != "" {
-		salaries, err := h.salaryService.GetByEmployeeID(employeeID)
+		salaries, err := h.salaryService.GetByEmployeeID(c.UserContext(), employeeID)
 		if err != nil {
 			return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch employee salaries")
 		}
 		return response.Success(c, fiber.StatusOK, "Employee salaries retrieved", salaries)
 	}
 
-	salaries, err := h.salaryService.GetAll()
+	salaries, err := h.salaryService.GetAll(c.UserContext())
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch employee salaries")
 	}
@@ -55,7 +55,7 @@ func (h *EmployeeSalaryHandler) GetAll(c *fiber.Ctx) error {
 // @Router /employee-salaries/{id} [get]
 func (h *EmployeeSalaryHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
-	salary, err := h.salaryService.GetByID(id)
+	salary, err := h.salaryService.GetByID(c.UserContext(), id)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -74,7 +74,7 @@ func (h *EmployeeSalaryHandler) GetByID(c *fiber.Ctx) error {
 // @Router /employee-salaries/employee/{employeeId}/latest [get]
 func (h *EmployeeSalaryHandler) GetLatest(c *fiber.Ctx) error {
 	employeeID := c.Params("employeeId")
-	salary, err := h.salaryService.GetLatestByEmployeeID(employeeID)
+	salary, err := h.salaryService.GetLatestByEmployeeID(c.UserContext(), employeeID)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -110,7 +110,7 @@ func (h *EmployeeSalaryHandler) Create(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Effective date is required")
 	}
 
-	salary, err := h.salaryService.Create(req)
+	salary, err := h.salaryService.Create(c.UserContext(), req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -129,7 +129,7 @@ func (h *EmployeeSalaryHandler) Create(c *fiber.Ctx) error {
 // @Router /employee-salaries/seed-from-position/{employeeId} [post]
 func (h *EmployeeSalaryHandler) SeedFromPosition(c *fiber.Ctx) error {
 	employeeID := c.Params("employeeId")
-	salary, err := h.salaryService.SeedFromPosition(employeeID)
+	salary, err := h.salaryService.SeedFromPosition(c.UserContext(), employeeID)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -160,7 +160,7 @@ func (h *EmployeeSalaryHandler) Update(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Basic salary must be greater than 0")
 	}
 
-	salary, err := h.salaryService.Update(id, req)
+	salary, err := h.salaryService.Update(c.UserContext(), id, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -180,7 +180,7 @@ func (h *EmployeeSalaryHandler) Update(c *fiber.Ctx) error {
 func (h *EmployeeSalaryHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	if err := h.salaryService.Delete(id); err != nil {
+	if err := h.salaryService.Delete(c.UserContext(), id); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	return response.Success(c, fiber.StatusOK, "Employee salary deleted", nil)
@@ -21,21 +21,21 @@ func (h *GradeHandler) GetAll(c *fiber.Ctx) error {
 	companyID := c.Query("company_id")
 
 	if jobLevelID != "" {
-		grades, err := h.gradeService.GetByJobLevelID(jobLevelID)
+		grades, err := h.gradeService.GetByJobLevelID(c.UserContext(), jobLevelID)
 		if err != nil {
 			return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch grades")
 		}
 		return response.Success(c, fiber.StatusOK, "Grades retrieved", grades)
 	}
 	if companyID != "" {
-		grades, err := h.gradeService.GetByCompanyID(companyID)
+		grades, err := h.gradeService.GetByCompanyID(c.UserContext(), companyID)
 		if err != nil {
 			return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch grades")
 		}
 		return response.Success(c, fiber.StatusOK, "Grades retrieved", grades)
 	}
 
-	grades, err := h.gradeService.GetAll()
+	grades, err := h.gradeService.GetAll(c.UserContext())
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch grades")
 	}
@@ -44,7 +44,7 @@ func (h *GradeHandler) GetAll(c *fiber.Ctx) error {
 
 func (h *GradeHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
-	g, err := h.gradeService.GetByID(id)
+	g, err := h.gradeService.GetByID(c.UserContext(), id)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -60,7 +60,7 @@ func (h *GradeHandler) Create(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Company ID, job level ID, and name are required")
 	}
 
-	g, err := h.gradeService.Create(req)
+	g, err := h.gradeService.Create(c.UserContext(), req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -74,7 +74,7 @@ func (h *GradeHandler) Update(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	g, err := h.gradeService.Update(id, req)
+	g, err := h.gradeService.Update(c.UserContext(), id, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -83,7 +83,7 @@ func (h *GradeHandler) Update(c *fiber.Ctx) error {
 
 func (h *GradeHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
-	if err := h.gradeService.Delete(id); err != nil {
+	if err := h.gradeService.Delete(c.UserContext(), id); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	return response.Success(c, fiber.StatusOK, "Grade deleted", nil)
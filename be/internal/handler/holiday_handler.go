@@ -39,7 +39,7 @@ func (h *HolidayHandler) GetAll(c *fiber.Ctx) error {
 		if err != nil {
 			return response.Error(c, fiber.StatusBadRequest, "Invalid year format")
 		}
-		holidays, err := h.holidayService.GetByCompanyIDAndYear(companyID, year)
+		holidays, err := h.holidayService.GetByCompanyIDAndYear(c.UserContext(), companyID, year)
 		if err != nil {
 			return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch holidays")
 		}
@@ -47,14 +47,14 @@ func (h *HolidayHandler) GetAll(c *fiber.Ctx) error {
 	}
 
 	if companyID != "" {
-		holidays, err := h.holidayService.GetByCompanyID(companyID)
+		holidays, err := h.holidayService.GetByCompanyID(c.UserContext(), companyID)
 		if err != nil {
 			return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch holidays")
 		}
 		return response.Success(c, fiber.StatusOK, "Holidays retrieved", holidays)
 	}
 
-	holidays, err := h.holidayService.GetAll()
+	holidays, err := h.holidayService.GetAll(c.UserContext())
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch holidays")
 	}
@@ -73,7 +73,7 @@ func (h *HolidayHandler) GetAll(c *fiber.Ctx) error {
 // @Router /holidays/{id} [get]
 func (h *HolidayHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
-	holiday, err := h.holidayService.GetByID(id)
+	holiday, err := h.holidayService.GetByID(c.UserContext(), id)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -101,7 +101,7 @@ func (h *HolidayHandler) Create(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Company ID, name, and date are required")
 	}
 
-	holiday, err := h.holidayService.Create(req)
+	holiday, err := h.holidayService.Create(c.UserContext(), req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -128,7 +128,7 @@ func (h *HolidayHandler) Update(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	holiday, err := h.holidayService.Update(id, req)
+	holiday, err := h.holidayService.Update(c.UserContext(), id, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -148,7 +148,7 @@ func (h *HolidayHandler) Update(c *fiber.Ctx) error {
 func (h *HolidayHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	if err := h.holidayService.Delete(id); err != nil {
+	if err := h.holidayService.Delete(c.UserContext(), id); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	return response.Success(c, fiber.StatusOK, "Holiday deleted", nil)
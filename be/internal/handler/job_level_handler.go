@@ -19,14 +19,14 @@ func NewJobLevelHandler(jobLevelService service.JobLevelService) *JobLevelHandle
 func (h *JobLevelHandler) GetAll(c *fiber.Ctx) error {
 	companyID := c.Query("company_id")
 	if companyID != "" {
-		levels, err := h.jobLevelService.GetByCompanyID(companyID)
+		levels, err := h.jobLevelService.GetByCompanyID(c.UserContext(), companyID)
 		if err != nil {
 			return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch job levels")
 		}
 		return response.Success(c, fiber.StatusOK, "Job levels retrieved", levels)
 	}
 
-	levels, err := h.jobLevelService.GetAll()
+	levels, err := h.jobLevelService.GetAll(c.UserContext())
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch job levels")
 	}
@@ -35,7 +35,7 @@ func (h *JobLevelHandler) GetAll(c *fiber.Ctx) error {
 
 func (h *JobLevelHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
-	jl, err := h.jobLevelService.GetByID(id)
+	jl, err := h.jobLevelService.GetByID(c.UserContext(), id)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -51,7 +51,7 @@ func (h *JobLevelHandler) Create(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Company ID and name are required")
 	}
 
-	jl, err := h.jobLevelService.Create(req)
+	jl, err := h.jobLevelService.Create(c.UserContext(), req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -65,7 +65,7 @@ func (h *JobLevelHandler) Update(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	jl, err := h.jobLevelService.Update(id, req)
+	jl, err := h.jobLevelService.Update(c.UserContext(), id, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -74,7 +74,7 @@ func (h *JobLevelHandler) Update(c *fiber.Ctx) error {
 
 func (h *JobLevelHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
-	if err := h.jobLevelService.Delete(id); err != nil {
+	if err := h.jobLevelService.Delete(c.UserContext(), id); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	return response.Success(c, fiber.StatusOK, "Job level deleted", nil)
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	jwtPkg "hris-backend/pkg/jwt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type JWKSHandler struct {
+	keys *jwtPkg.KeySet
+}
+
+func NewJWKSHandler(keys *jwtPkg.KeySet) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// GetKeys serves the public keys access tokens are signed with, as a JSON Web
+// Key Set at /.well-known/jwks.json. Other services verify our tokens against
+// it, picking the key by the token's kid. It is a bare JWKS document rather
+// than a response.Response so standard JWT libraries can read it.
+func (h *JWKSHandler) GetKeys(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(h.keys.JWKS())
+}
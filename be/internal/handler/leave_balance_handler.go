@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"hris-backend/internal/dto"
+	"hris-backend/internal/model"
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LeaveBalanceHandler struct {
+	balanceService service.LeaveBalanceService
+	empService     service.EmployeeService
+}
+
+func NewLeaveBalanceHandler(balanceService service.LeaveBalanceService, empService service.EmployeeService) *LeaveBalanceHandler {
+	return &LeaveBalanceHandler{balanceService: balanceService, empService: empService}
+}
+
+// GetBalance godoc
+// @Summary Get leave balances
+// @Description Retrieve leave balances per leave type for a year. Employees see their own balance; admin and HR may pass employee_id.
+// @Tags Leave Balances
+// @Security Bearer
+// @Produce json
+// @Param employee_id query string false "Employee ID (admin/HR only, defaults to the current user)"
+// @Param year query int false "Year (defaults to the current year)"
+// @Success 200 {object} response.Response{data=[]dto.LeaveBalanceResponse} "Leave balances retrieved"
+// @Failure 400 {object} response.Response "Invalid year format"
+// @Failure 404 {object} response.Response "Employee profile not found"
+// @Router /leaves/balance [get]
+func (h *LeaveBalanceHandler) GetBalance(c *fiber.Ctx) error {
+	employeeID, year, fe := h.resolveEmployeeAndYear(c)
+	if fe != nil {
+		return response.Error(c, fe.Code, fe.Message)
+	}
+
+	balances, err := h.balanceService.GetBalances(c.UserContext(), employeeID, year)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Leave balances retrieved", balances)
+}
+
+// GetLedger godoc
+// @Summary Get leave ledger
+// @Description Retrieve the leave balance ledger entries for a year. Employees see their own ledger; admin and HR may pass employee_id.
+// @Tags Leave Balances
+// @Security Bearer
+// @Produce json
+// @Param employee_id query string false "Employee ID (admin/HR only, defaults to the current user)"
+// @Param year query int false "Year (defaults to the current year)"
+// @Param leave_type query string false "Filter by leave type"
+// @Success 200 {object} response.Response{data=[]dto.LeaveLedgerEntryResponse} "Leave ledger retrieved"
+// @Failure 400 {object} response.Response "Invalid year format"
+// @Failure 404 {object} response.Response "Employee profile not found"
+// @Router /leaves/balance/ledger [get]
+func (h *LeaveBalanceHandler) GetLedger(c *fiber.Ctx) error {
+	employeeID, year, fe := h.resolveEmployeeAndYear(c)
+	if fe != nil {
+		return response.Error(c, fe.Code, fe.Message)
+	}
+
+	entries, err := h.balanceService.GetLedger(c.UserContext(), employeeID, year, model.LeaveType(c.Query("leave_type")))
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch leave ledger")
+	}
+	return response.Success(c, fiber.StatusOK, "Leave ledger retrieved", entries)
+}
+
+// GetEntitlements godoc
+// @Summary Get leave entitlements
+// @Description Retrieve the per-year entitlement overrides of an employee
+// @Tags Leave Balances
+// @Security Bearer
+// @Produce json
+// @Param employee_id query string true "Employee ID"
+// @Param year query int false "Year (defaults to the current year)"
+// @Success 200 {object} response.Response{data=[]dto.LeaveEntitlementResponse} "Leave entitlements retrieved"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /leave-balances/entitlements [get]
+func (h *LeaveBalanceHandler) GetEntitlements(c *fiber.Ctx) error {
+	employeeID := c.Query("employee_id")
+	if employeeID == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Employee ID is required")
+	}
+
+	year := time.Now().Year()
+	if yearStr := c.Query("year"); yearStr != "" {
+		y, err := strconv.Atoi(yearStr)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "Invalid year format")
+		}
+		year = y
+	}
+
+	entitlements, err := h.balanceService.GetEntitlements(c.UserContext(), employeeID, year)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch leave entitlements")
+	}
+	return response.Success(c, fiber.StatusOK, "Leave entitlements retrieved", entitlements)
+}
+
+// SetEntitlement godoc
+// @Summary Set a leave entitlement
+// @Description Create or replace an employee's entitlement for a leave type and year, overriding the policy's annual days
+// @Tags Leave Balances
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.SetLeaveEntitlementRequest true "Entitlement data"
+// @Success 200 {object} response.Response{data=dto.LeaveEntitlementResponse} "Leave entitlement saved"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /leave-balances/entitlements [put]
+func (h *LeaveBalanceHandler) SetEntitlement(c *fiber.Ctx) error {
+	var req dto.SetLeaveEntitlementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.EmployeeID == "" || req.LeaveType == "" || req.Year < 2000 {
+		return response.Error(c, fiber.StatusBadRequest, "Employee ID, leave type and a valid year are required")
+	}
+
+	entitlement, err := h.balanceService.SetEntitlement(c.UserContext(), req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Leave entitlement saved", entitlement)
+}
+
+// Adjust godoc
+// @Summary Adjust a leave balance
+// @Description Post a manual adjustment to an employee's leave balance. Positive days add to the balance, negative days deduct.
+// @Tags Leave Balances
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.LeaveAdjustmentRequest true "Adjustment data"
+// @Success 201 {object} response.Response{data=dto.LeaveLedgerEntryResponse} "Leave balance adjusted"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /leave-balances/adjustments [post]
+func (h *LeaveBalanceHandler) Adjust(c *fiber.Ctx) error {
+	var req dto.LeaveAdjustmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.EmployeeID == "" || req.LeaveType == "" || req.Year < 2000 || req.Days == 0 || req.Description == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Employee ID, leave type, year, non-zero days and description are required")
+	}
+
+	userID := c.Locals("userID").(string)
+	entry, err := h.balanceService.Adjust(c.UserContext(), req, userID)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusCreated, "Leave balance adjusted", entry)
+}
+
+// Accrue godoc
+// @Summary Run leave accrual
+// @Description Post the leave accruals due for every active employee of a company up to the given month, and expire carried-over days past their expiry date. Safe to run repeatedly.
+// @Tags Leave Balances
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.LeaveAccrualRequest true "Accrual data"
+// @Success 200 {object} response.Response{data=dto.LeaveBalanceJobResponse} "Leave accrual completed"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /leave-balances/accrue [post]
+func (h *LeaveBalanceHandler) Accrue(c *fiber.Ctx) error {
+	var req dto.LeaveAccrualRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.CompanyID == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Company ID is required")
+	}
+
+	if req.Month < 0 || req.Month > 12 {
+		return response.Error(c, fiber.StatusBadRequest, "Month must be between 1 and 12")
+	}
+
+	if req.Year < 2000 {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid year")
+	}
+
+	result, err := h.balanceService.Accrue(c.UserContext(), req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Leave accrual completed", result)
+}
+
+// CloseYear godoc
+// @Summary Close a leave year
+// @Description Carry each employee's remaining balance into the next year up to the policy limit and expire the rest. Each year is only closed once per employee and leave type.
+// @Tags Leave Balances
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.LeaveYearCloseRequest true "Year close data"
+// @Success 200 {object} response.Response{data=dto.LeaveBalanceJobResponse} "Leave year closed"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /leave-balances/close-year [post]
+func (h *LeaveBalanceHandler) CloseYear(c *fiber.Ctx) error {
+	var req dto.LeaveYearCloseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.CompanyID == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Company ID is required")
+	}
+
+	if req.Year < 2000 {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid year")
+	}
+
+	result, err := h.balanceService.CloseYear(c.UserContext(), req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Leave year closed", result)
+}
+
+// resolveEmployeeAndYear reads the employee_id and year query parameters.
+// Only admin and HR may look at another employee; everyone else gets their
+// own employee record.
+func (h *LeaveBalanceHandler) resolveEmployeeAndYear(c *fiber.Ctx) (string, int, *fiber.Error) {
+	year := time.Now().Year()
+	if yearStr := c.Query("year"); yearStr != "" {
+		y, err := strconv.Atoi(yearStr)
+		if err != nil {
+			return "", 0, fiber.NewError(fiber.StatusBadRequest, "Invalid year format")
+		}
+		year = y
+	}
+
+	employeeID := c.Query("employee_id")
+	if employeeID != "" && isLeaveManager(c) {
+		return employeeID, year, nil
+	}
+
+	userID := c.Locals("userID").(string)
+	emp, err := h.empService.GetByUserID(c.UserContext(), userID)
+	if err != nil {
+		return "", 0, fiber.NewError(fiber.StatusNotFound, "Employee profile not found")
+	}
+	return emp.ID, year, nil
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"hris-backend/internal/dto"
+	"hris-backend/internal/middleware"
+	"hris-backend/internal/permissions"
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LeaveDelegationHandler struct {
+	delegationService service.LeaveDelegationService
+}
+
+func NewLeaveDelegationHandler(delegationService service.LeaveDelegationService) *LeaveDelegationHandler {
+	return &LeaveDelegationHandler{delegationService: delegationService}
+}
+
+// GetAll godoc
+// @Summary Get leave delegations
+// @Description Retrieve approval delegations. Users see the delegations they gave or received; admin and HR see all, or one user's with user_id.
+// @Tags Leave Delegations
+// @Security Bearer
+// @Produce json
+// @Param user_id query string false "Filter by delegator or delegate user ID (admin/HR only)"
+// @Success 200 {object} response.Response{data=[]dto.LeaveDelegationResponse} "Leave delegations retrieved"
+// @Failure 500 {object} response.Response "Failed to fetch leave delegations"
+// @Router /leave-delegations [get]
+func (h *LeaveDelegationHandler) GetAll(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	if isLeaveManager(c) {
+		if filter := c.Query("user_id"); filter != "" {
+			userID = filter
+		} else {
+			delegations, err := h.delegationService.GetAll(c.UserContext())
+			if err != nil {
+				return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch leave delegations")
+			}
+			return response.Success(c, fiber.StatusOK, "Leave delegations retrieved", delegations)
+		}
+	}
+
+	delegations, err := h.delegationService.GetByUserID(c.UserContext(), userID)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch leave delegations")
+	}
+	return response.Success(c, fiber.StatusOK, "Leave delegations retrieved", delegations)
+}
+
+// Create godoc
+// @Summary Delegate leave approvals
+// @Description Let another user decide your leave approval steps for a period, e.g. while you are on leave. Admin and HR may set delegator_id to delegate on behalf of another user.
+// @Tags Leave Delegations
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateLeaveDelegationRequest true "Delegation data"
+// @Success 201 {object} response.Response{data=dto.LeaveDelegationResponse} "Leave delegation created"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Failure 403 {object} response.Response "Cannot delegate for another user"
+// @Router /leave-delegations [post]
+func (h *LeaveDelegationHandler) Create(c *fiber.Ctx) error {
+	var req dto.CreateLeaveDelegationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.DelegateID == "" || req.StartDate == "" || req.EndDate == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Delegate ID, start date and end date are required")
+	}
+
+	userID := c.Locals("userID").(string)
+	if req.DelegatorID != "" && req.DelegatorID != userID && !isLeaveManager(c) {
+		return response.Error(c, fiber.StatusForbidden, "Only admin and HR can delegate for another user")
+	}
+
+	delegation, err := h.delegationService.Create(c.UserContext(), req, userID)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusCreated, "Leave delegation created", delegation)
+}
+
+// Delete godoc
+// @Summary Delete a leave delegation
+// @Description Remove an approval delegation. Users may remove their own delegations; admin and HR may remove any.
+// @Tags Leave Delegations
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Leave delegation ID"
+// @Success 200 {object} response.Response "Leave delegation deleted"
+// @Failure 400 {object} response.Response "Failed to delete"
+// @Router /leave-delegations/{id} [delete]
+func (h *LeaveDelegationHandler) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+	userID := c.Locals("userID").(string)
+
+	if err := h.delegationService.Delete(c.UserContext(), id, userID, isLeaveManager(c)); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Leave delegation deleted", nil)
+}
+
+// isLeaveManager reports whether the caller manages leave for everyone
+func isLeaveManager(c *fiber.Ctx) bool {
+	return middleware.HasPermission(c, permissions.LeaveManage)
+}
@@ -3,20 +3,17 @@ package handler
 import (
 	"hris-backend/internal/dto"
 	"hris-backend/internal/service"
-	"hris-backend/pkg/kafka"
 	"hris-backend/pkg/response"
-	"log"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type LeaveHandler struct {
 	leaveService service.LeaveService
-	producer     *kafka.Producer
 }
 
-func NewLeaveHandler(leaveService service.LeaveService, producer *kafka.Producer) *LeaveHandler {
-	return &LeaveHandler{leaveService: leaveService, producer: producer}
+func NewLeaveHandler(leaveService service.LeaveService) *LeaveHandler {
+	return &LeaveHandler{leaveService: leaveService}
 }
 
 // GetAll godoc
@@ -35,7 +32,7 @@ func (h *LeaveHandler) GetAll(c *fiber.Ctx) error {
 	status := c.Query("status")
 
 	if status == "pending" {
-		leaves, err := h.leaveService.GetPending()
+		leaves, err := h.leaveService.GetPending(c.UserContext())
 		if err != nil {
 			return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch leaves")
 		}
@@ -43,14 +40,34 @@ func (h *LeaveHandler) GetAll(c *fiber.Ctx) error {
 	}
 
 	if employeeID != "" {
-		leaves, err := h.leaveService.GetByEmployeeID(employeeID)
+		leaves, err := h.leaveService.GetByEmployeeID(c.UserContext(), employeeID)
 		if err != nil {
 			return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch leaves")
 		}
 		return response.Success(c, fiber.StatusOK, "Leaves retrieved", leaves)
 	}
 
-	leaves, err := h.leaveService.GetAll()
+	leaves, err := h.leaveService.GetAll(c.UserContext())
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch leaves")
+	}
+	return response.Success(c, fiber.StatusOK, "Leaves retrieved", leaves)
+}
+
+// GetAwaitingApproval godoc
+// @Summary Get leave requests awaiting my approval
+// @Description Retrieve the leave requests whose current approval step the current user can decide, directly or as a delegate
+// @Tags Leaves
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.LeaveResponse} "Leaves retrieved"
+// @Failure 500 {object} response.Response "Failed to fetch leaves"
+// @Router /leaves/approvals [get]
+func (h *LeaveHandler) GetAwaitingApproval(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	role := c.Locals("role").(string)
+
+	leaves, err := h.leaveService.GetAwaitingApproval(c.UserContext(), userID, role)
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch leaves")
 	}
@@ -69,7 +86,7 @@ func (h *LeaveHandler) GetAll(c *fiber.Ctx) error {
 // @Router /leaves/{id} [get]
 func (h *LeaveHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
-	leave, err := h.leaveService.GetByID(id)
+	leave, err := h.leaveService.GetByID(c.UserContext(), id)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -97,34 +114,11 @@ func (h *LeaveHandler) Create(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Employee ID, leave type, start date, end date, and reason are required")
 	}
 
-	if req.TotalDays <= 0 {
-		return response.Error(c, fiber.StatusBadRequest, "Total days must be greater than 0")
-	}
-
-	leave, err := h.leaveService.Create(req)
+	leave, err := h.leaveService.Create(c.UserContext(), req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	// Publish Kafka event for admin/HR notification
-	if h.producer != nil {
-		empName := ""
-		if leave.Employee != nil && leave.Employee.User != nil {
-			empName = leave.Employee.User.Name
-		}
-		payload := kafka.LeaveSubmittedPayload{
-			LeaveID:      leave.ID,
-			EmployeeName: empName,
-			LeaveType:    string(leave.LeaveType),
-			TotalDays:    leave.TotalDays,
-		}
-		if msg, err := kafka.MarshalEvent(kafka.EventLeaveSubmitted, payload); err == nil {
-			h.producer.PublishAsync(msg)
-		} else {
-			log.Printf("[kafka] leave create: marshal event: %v", err)
-		}
-	}
-
 	return response.Success(c, fiber.StatusCreated, "Leave request created", leave)
 }
 
@@ -148,7 +142,7 @@ func (h *LeaveHandler) Update(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	leave, err := h.leaveService.Update(id, req)
+	leave, err := h.leaveService.Update(c.UserContext(), id, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -157,7 +151,7 @@ func (h *LeaveHandler) Update(c *fiber.Ctx) error {
 
 // Approve godoc
 // @Summary Approve or reject a leave request
-// @Description Approve or reject a pending leave request (Admin/HR only)
+// @Description Decide the current step of a leave request's approval chain. Only the step's approver, their delegate, or for role steps a user with that role may decide. The leave stays in_review until the last step approves it; any rejection rejects it.
 // @Tags Leaves
 // @Security Bearer
 // @Accept json
@@ -165,11 +159,12 @@ func (h *LeaveHandler) Update(c *fiber.Ctx) error {
 // @Param id path string true "Leave ID"
 // @Param request body dto.ApproveLeaveRequest true "Approval data"
 // @Success 200 {object} response.Response{data=dto.LeaveResponse} "Leave request updated"
-// @Failure 400 {object} response.Response "Invalid request"
+// @Failure 400 {object} response.Response "Invalid request or not an approver of the current step"
 // @Router /leaves/{id}/approve [put]
 func (h *LeaveHandler) Approve(c *fiber.Ctx) error {
 	id := c.Params("id")
 	approverID := c.Locals("userID").(string)
+	approverRole := c.Locals("role").(string)
 
 	var req dto.ApproveLeaveRequest
 	if err := c.BodyParser(&req); err != nil {
@@ -180,36 +175,45 @@ func (h *LeaveHandler) Approve(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Status is required (approved or rejected)")
 	}
 
-	leave, err := h.leaveService.Approve(id, approverID, req)
+	leave, err := h.leaveService.Approve(c.UserContext(), id, approverID, approverRole, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	// Publish Kafka event to notify the employee
-	if h.producer != nil {
-		empUserID := ""
-		empName := ""
-		if leave.Employee != nil {
-			empUserID = leave.Employee.UserID
-			if leave.Employee.User != nil {
-				empName = leave.Employee.User.Name
-			}
-		}
-		payload := kafka.LeaveStatusChangedPayload{
-			LeaveID:         leave.ID,
-			EmployeeUserID:  empUserID,
-			EmployeeName:    empName,
-			NewStatus:       string(leave.Status),
-			RejectionReason: leave.RejectionReason,
-		}
-		if msg, err := kafka.MarshalEvent(kafka.EventLeaveStatusChanged, payload); err == nil {
-			h.producer.PublishAsync(msg)
-		} else {
-			log.Printf("[kafka] leave approve: marshal event: %v", err)
-		}
+	return response.Success(c, fiber.StatusOK, "Leave request updated", leave)
+}
+
+// Cancel godoc
+// @Summary Cancel a leave request
+// @Description Withdraw a leave request awaiting approval, or cancel an approved one. Cancelling an approved leave credits its days back to the balance and reverts the attendance rows it wrote. Employees may cancel their own leave before it starts; admin and HR may cancel any leave.
+// @Tags Leaves
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "Leave ID"
+// @Param request body dto.CancelLeaveRequest true "Cancellation data"
+// @Success 200 {object} response.Response{data=dto.LeaveResponse} "Leave request cancelled"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /leaves/{id}/cancel [put]
+func (h *LeaveHandler) Cancel(c *fiber.Ctx) error {
+	id := c.Params("id")
+	userID := c.Locals("userID").(string)
+
+	var req dto.CancelLeaveRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	return response.Success(c, fiber.StatusOK, "Leave request updated", leave)
+	if req.Reason == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Cancellation reason is required")
+	}
+
+	leave, err := h.leaveService.Cancel(c.UserContext(), id, userID, isLeaveManager(c), req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return response.Success(c, fiber.StatusOK, "Leave request cancelled", leave)
 }
 
 // Delete godoc
@@ -225,7 +229,7 @@ func (h *LeaveHandler) Approve(c *fiber.Ctx) error {
 func (h *LeaveHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	if err := h.leaveService.Delete(id); err != nil {
+	if err := h.leaveService.Delete(c.UserContext(), id); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	return response.Success(c, fiber.StatusOK, "Leave request deleted", nil)
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"hris-backend/internal/dto"
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LeavePolicyHandler struct {
+	policyService service.LeavePolicyService
+}
+
+func NewLeavePolicyHandler(policyService service.LeavePolicyService) *LeavePolicyHandler {
+	return &LeavePolicyHandler{policyService: policyService}
+}
+
+// GetAll godoc
+// @Summary Get all leave policies
+// @Description Retrieve all leave policies, optionally filtered by company
+// @Tags Leave Policies
+// @Security Bearer
+// @Produce json
+// @Param company_id query string false "Filter by company ID"
+// @Success 200 {object} response.Response{data=[]dto.LeavePolicyResponse} "Leave policies retrieved"
+// @Failure 500 {object} response.Response "Failed to fetch leave policies"
+// @Router /leave-policies [get]
+func (h *LeavePolicyHandler) GetAll(c *fiber.Ctx) error {
+	companyID := c.Query("company_id")
+
+	if companyID != "" {
+		policies, err := h.policyService.GetByCompanyID(c.UserContext(), companyID)
+		if err != nil {
+			return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch leave policies")
+		}
+		return response.Success(c, fiber.StatusOK, "Leave policies retrieved", policies)
+	}
+
+	policies, err := h.policyService.GetAll(c.UserContext())
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch leave policies")
+	}
+	return response.Success(c, fiber.StatusOK, "Leave policies retrieved", policies)
+}
+
+// GetByID godoc
+// @Summary Get leave policy by ID
+// @Description Retrieve a leave policy by its ID
+// @Tags Leave Policies
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Leave policy ID"
+// @Success 200 {object} response.Response{data=dto.LeavePolicyResponse} "Leave policy retrieved"
+// @Failure 404 {object} response.Response "Leave policy not found"
+// @Router /leave-policies/{id} [get]
+func (h *LeavePolicyHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	policy, err := h.policyService.GetByID(c.UserContext(), id)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Leave policy retrieved", policy)
+}
+
+// Create godoc
+// @Summary Create a leave policy
+// @Description Define how a company grants a leave type: yearly days, accrual method, carry-over limit and expiry
+// @Tags Leave Policies
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateLeavePolicyRequest true "Leave policy data"
+// @Success 201 {object} response.Response{data=dto.LeavePolicyResponse} "Leave policy created"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /leave-policies [post]
+func (h *LeavePolicyHandler) Create(c *fiber.Ctx) error {
+	var req dto.CreateLeavePolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.CompanyID == "" || req.LeaveType == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Company ID and leave type are required")
+	}
+
+	policy, err := h.policyService.Create(c.UserContext(), req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusCreated, "Leave policy created", policy)
+}
+
+// Update godoc
+// @Summary Update a leave policy
+// @Description Update an existing leave policy by ID
+// @Tags Leave Policies
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "Leave policy ID"
+// @Param request body dto.UpdateLeavePolicyRequest true "Leave policy data"
+// @Success 200 {object} response.Response{data=dto.LeavePolicyResponse} "Leave policy updated"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /leave-policies/{id} [put]
+func (h *LeavePolicyHandler) Update(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req dto.UpdateLeavePolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	policy, err := h.policyService.Update(c.UserContext(), id, req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Leave policy updated", policy)
+}
+
+// Delete godoc
+// @Summary Delete a leave policy
+// @Description Delete a leave policy by ID. The leave type stops being balance-tracked.
+// @Tags Leave Policies
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Leave policy ID"
+// @Success 200 {object} response.Response "Leave policy deleted"
+// @Failure 404 {object} response.Response "Leave policy not found"
+// @Router /leave-policies/{id} [delete]
+func (h *LeavePolicyHandler) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.policyService.Delete(c.UserContext(), id); err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Leave policy deleted", nil)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"hris-backend/internal/dto"
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LeaveWorkflowHandler struct {
+	workflowService service.LeaveWorkflowService
+}
+
+func NewLeaveWorkflowHandler(workflowService service.LeaveWorkflowService) *LeaveWorkflowHandler {
+	return &LeaveWorkflowHandler{workflowService: workflowService}
+}
+
+// GetAll godoc
+// @Summary Get all leave workflows
+// @Description Retrieve all leave approval workflows, optionally filtered by company
+// @Tags Leave Workflows
+// @Security Bearer
+// @Produce json
+// @Param company_id query string false "Filter by company ID"
+// @Success 200 {object} response.Response{data=[]dto.LeaveWorkflowResponse} "Leave workflows retrieved"
+// @Failure 500 {object} response.Response "Failed to fetch leave workflows"
+// @Router /leave-workflows [get]
+func (h *LeaveWorkflowHandler) GetAll(c *fiber.Ctx) error {
+	companyID := c.Query("company_id")
+
+	if companyID != "" {
+		workflows, err := h.workflowService.GetByCompanyID(c.UserContext(), companyID)
+		if err != nil {
+			return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch leave workflows")
+		}
+		return response.Success(c, fiber.StatusOK, "Leave workflows retrieved", workflows)
+	}
+
+	workflows, err := h.workflowService.GetAll(c.UserContext())
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch leave workflows")
+	}
+	return response.Success(c, fiber.StatusOK, "Leave workflows retrieved", workflows)
+}
+
+// GetByID godoc
+// @Summary Get leave workflow by ID
+// @Description Retrieve a leave approval workflow and its steps by ID
+// @Tags Leave Workflows
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Leave workflow ID"
+// @Success 200 {object} response.Response{data=dto.LeaveWorkflowResponse} "Leave workflow retrieved"
+// @Failure 404 {object} response.Response "Leave workflow not found"
+// @Router /leave-workflows/{id} [get]
+func (h *LeaveWorkflowHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	workflow, err := h.workflowService.GetByID(c.UserContext(), id)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Leave workflow retrieved", workflow)
+}
+
+// Create godoc
+// @Summary Create a leave workflow
+// @Description Define the ordered approval steps for leave requests of a company, or of one department when department_id is set. Step approver types are supervisor, department_head, role (hr or admin) and user.
+// @Tags Leave Workflows
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateLeaveWorkflowRequest true "Leave workflow data"
+// @Success 201 {object} response.Response{data=dto.LeaveWorkflowResponse} "Leave workflow created"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /leave-workflows [post]
+func (h *LeaveWorkflowHandler) Create(c *fiber.Ctx) error {
+	var req dto.CreateLeaveWorkflowRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.CompanyID == "" || req.Name == "" || len(req.Steps) == 0 {
+		return response.Error(c, fiber.StatusBadRequest, "Company ID, name and at least one step are required")
+	}
+
+	workflow, err := h.workflowService.Create(c.UserContext(), req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusCreated, "Leave workflow created", workflow)
+}
+
+// Update godoc
+// @Summary Update a leave workflow
+// @Description Update a leave approval workflow. When steps are given they replace the existing steps; leave requests already submitted keep their chain.
+// @Tags Leave Workflows
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "Leave workflow ID"
+// @Param request body dto.UpdateLeaveWorkflowRequest true "Leave workflow data"
+// @Success 200 {object} response.Response{data=dto.LeaveWorkflowResponse} "Leave workflow updated"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /leave-workflows/{id} [put]
+func (h *LeaveWorkflowHandler) Update(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req dto.UpdateLeaveWorkflowRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	workflow, err := h.workflowService.Update(c.UserContext(), id, req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Leave workflow updated", workflow)
+}
+
+// Delete godoc
+// @Summary Delete a leave workflow
+// @Description Delete a leave approval workflow by ID
+// @Tags Leave Workflows
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Leave workflow ID"
+// @Success 200 {object} response.Response "Leave workflow deleted"
+// @Failure 400 {object} response.Response "Failed to delete"
+// @Router /leave-workflows/{id} [delete]
+func (h *LeaveWorkflowHandler) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.workflowService.Delete(c.UserContext(), id); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Leave workflow deleted", nil)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"strconv"
+
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LoginAuditHandler struct {
+	auditService service.LoginAuditService
+}
+
+func NewLoginAuditHandler(auditService service.LoginAuditService) *LoginAuditHandler {
+	return &LoginAuditHandler{auditService: auditService}
+}
+
+// GetAll godoc
+// @Summary Get login attempts
+// @Description Retrieve the login audit log of accounts in your companies, newest first, with optional filters and pagination
+// @Tags Login Audit
+// @Security Bearer
+// @Produce json
+// @Param user_id query string false "Filter by user ID"
+// @Param email query string false "Filter by email (partial match)"
+// @Param ip_address query string false "Filter by IP address"
+// @Param result query string false "Filter by result (success, 2fa_challenge, invalid_password, unknown_user, invalid_2fa, deactivated, locked, throttled)"
+// @Param start_date query string false "Filter start date (YYYY-MM-DD)"
+// @Param end_date query string false "Filter end date (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} response.Response{data=dto.PaginatedLoginAttemptResponse} "Login attempts retrieved"
+// @Failure 500 {object} response.Response "Failed to fetch login attempts"
+// @Router /login-attempts [get]
+func (h *LoginAuditHandler) GetAll(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	result, err := h.auditService.GetAllPaginated(
+		c.UserContext(), page, limit,
+		c.Query("user_id"), c.Query("email"), c.Query("ip_address"), c.Query("result"),
+		c.Query("start_date"), c.Query("end_date"),
+	)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch login attempts")
+	}
+	return response.Success(c, fiber.StatusOK, "Login attempts retrieved", result)
+}
+
+// Unlock godoc
+// @Summary Unlock a user
+// @Description Lift the lockout of a user locked after too many failed logins and clear their failed login count
+// @Tags Users
+// @Security Bearer
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response "User unlocked"
+// @Failure 404 {object} response.Response "User not found"
+// @Router /users/{id}/unlock [post]
+func (h *LoginAuditHandler) Unlock(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.auditService.Unlock(c.UserContext(), id); err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "User unlocked", nil)
+}
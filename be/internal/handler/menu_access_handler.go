@@ -32,7 +32,7 @@ func (h *MenuAccessHandler) GetMyMenus(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusUnauthorized, "Unauthorized")
 	}
 
-	result, err := h.menuService.GetUserMenuKeys(userID)
+	result, err := h.menuService.GetUserMenuKeys(c.UserContext(), userID)
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, err.Error())
 	}
@@ -50,7 +50,7 @@ func (h *MenuAccessHandler) GetMyMenus(c *fiber.Ctx) error {
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /menu-access [get]
 func (h *MenuAccessHandler) GetAll(c *fiber.Ctx) error {
-	result, err := h.menuService.GetAll()
+	result, err := h.menuService.GetAll(c.UserContext())
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, err.Error())
 	}
@@ -79,7 +79,7 @@ func (h *MenuAccessHandler) Set(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "user_id is required")
 	}
 
-	if err := h.menuService.SetUserMenuAccess(req); err != nil {
+	if err := h.menuService.SetUserMenuAccess(c.UserContext(), req); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 
@@ -103,7 +103,7 @@ func (h *MenuAccessHandler) Delete(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "user_id is required")
 	}
 
-	if err := h.menuService.DeleteUserMenuAccess(userID); err != nil {
+	if err := h.menuService.DeleteUserMenuAccess(c.UserContext(), userID); err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, err.Error())
 	}
 
@@ -27,7 +27,7 @@ func NewModuleHandler(s service.ModuleService, empService service.EmployeeServic
 // @Failure 500 {object} response.Response "Failed to fetch modules"
 // @Router /modules [get]
 func (h *ModuleHandler) ListCatalog(c *fiber.Ctx) error {
-	modules, err := h.service.ListAllModules()
+	modules, err := h.service.ListAllModules(c.UserContext())
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, err.Error())
 	}
@@ -46,7 +46,7 @@ func (h *ModuleHandler) ListCatalog(c *fiber.Ctx) error {
 // @Router /companies/{id}/modules [get]
 func (h *ModuleHandler) ListForCompany(c *fiber.Ctx) error {
 	companyID := c.Params("id")
-	rows, err := h.service.ListForCompany(companyID)
+	rows, err := h.service.ListForCompany(c.UserContext(), companyID)
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, err.Error())
 	}
@@ -76,7 +76,7 @@ func (h *ModuleHandler) SetForCompany(c *fiber.Ctx) error {
 	}
 
 	actorID, _ := c.Locals("userID").(string)
-	saved, err := h.service.SetForCompany(companyID, moduleKey, actorID, req)
+	saved, err := h.service.SetForCompany(c.UserContext(), companyID, moduleKey, actorID, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -100,7 +100,7 @@ func (h *ModuleHandler) GetMyModules(c *fiber.Ctx) error {
 
 	// Superadmins have no employee record; return all modules enabled so they see everything.
 	if role, _ := c.Locals("role").(string); role == "superadmin" {
-		allModules, err := h.service.ListAllModules()
+		allModules, err := h.service.ListAllModules(c.UserContext())
 		if err != nil {
 			return response.Error(c, fiber.StatusInternalServerError, err.Error())
 		}
@@ -114,12 +114,12 @@ func (h *ModuleHandler) GetMyModules(c *fiber.Ctx) error {
 		})
 	}
 
-	emp, err := h.empService.GetByUserID(userID)
+	emp, err := h.empService.GetByUserID(c.UserContext(), userID)
 	if err != nil || emp == nil || emp.CompanyID == "" {
 		return response.Error(c, fiber.StatusNotFound, "Employee / company not found for user")
 	}
 
-	keys, err := h.service.EnabledKeysForCompany(emp.CompanyID)
+	keys, err := h.service.EnabledKeysForCompany(c.UserContext(), emp.CompanyID)
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, err.Error())
 	}
@@ -1,29 +1,54 @@
 package handler
 
 import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"hris-backend/internal/dto"
+	"hris-backend/internal/realtime"
 	"hris-backend/internal/service"
 	"hris-backend/pkg/response"
 	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+const (
+	// streamHeartbeat keeps idle streams open through proxies
+	streamHeartbeat = 25 * time.Second
+
+	// streamMaxDuration ends a stream after about an access token's life;
+	// the client reconnects with a current token
+	streamMaxDuration = 15 * time.Minute
+
+	// streamRetry is how long the client waits before reconnecting
+	streamRetry = 3 * time.Second
+)
+
 type NotificationHandler struct {
 	notifService service.NotificationService
+	hub          *realtime.Hub
 }
 
-func NewNotificationHandler(notifService service.NotificationService) *NotificationHandler {
-	return &NotificationHandler{notifService: notifService}
+func NewNotificationHandler(notifService service.NotificationService, hub *realtime.Hub) *NotificationHandler {
+	return &NotificationHandler{notifService: notifService, hub: hub}
 }
 
 // GetMyNotifications godoc
 // @Summary Get notifications for the current user
-// @Description Returns the latest notifications for the authenticated user
+// @Description Returns the notifications of the authenticated user, newest first, from the inbox or the archive, with optional filters. Pages are cursor-based: pass the next_cursor of a page as the cursor of the next one; the last page has none. Each notification carries the link of the frontend page of what it refers to, when there is one
 // @Tags Notifications
 // @Security Bearer
 // @Produce json
-// @Param limit query int false "Max number to return (default 20)"
-// @Success 200 {object} response.Response{data=[]dto.NotificationResponse}
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Max number to return (default 20, at most 100)"
+// @Param type query string false "Filter by type (info, success, warning, error)"
+// @Param ref_type query string false "Filter by the kind of record referred to, e.g. leave"
+// @Param is_read query bool false "Filter by read state"
+// @Param archived query bool false "List the archive instead of the inbox"
+// @Success 200 {object} response.Response{data=dto.NotificationPageResponse}
+// @Failure 400 {object} response.Response "Invalid filter"
 // @Router /notifications [get]
 func (h *NotificationHandler) GetMyNotifications(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
@@ -34,11 +59,93 @@ func (h *NotificationHandler) GetMyNotifications(c *fiber.Ctx) error {
 		}
 	}
 
-	notifications, err := h.notifService.GetByUserID(userID, limit)
+	page, err := h.notifService.GetPage(
+		c.UserContext(), userID, c.Query("cursor"), limit,
+		c.Query("type"), c.Query("ref_type"), c.Query("is_read"), c.Query("archived"),
+	)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Notifications retrieved", page)
+}
+
+// Stream godoc
+// @Summary Stream new notifications
+// @Description Server-Sent Events stream of the authenticated user's new notifications, each sent as a "notification" event whose ID is the notification ID and whose data is the notification. A client reconnecting with the Last-Event-ID header, or the last_event_id query parameter, first receives the notifications it missed, at most 100. Browsers may pass the access token in the access_token query parameter, since EventSource cannot set headers. The server ends the stream every 15 minutes; clients reconnect with a current token
+// @Tags Notifications
+// @Security Bearer
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "ID of the last notification received"
+// @Param last_event_id query string false "ID of the last notification received, for clients that cannot set headers"
+// @Param access_token query string false "Access token, for clients that cannot set headers"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} response.Response "Invalid last event ID"
+// @Router /notifications/stream [get]
+func (h *NotificationHandler) Stream(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	lastSeenID := c.Get("Last-Event-ID", c.Query("last_event_id"))
+
+	// Subscribe before looking up missed notifications, so none created in
+	// between is lost; the live copy of a replayed one is skipped
+	sub := h.hub.Subscribe(userID)
+	missed, err := h.notifService.GetMissed(c.UserContext(), userID, lastSeenID)
 	if err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch notifications")
+		sub.Close()
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
-	return response.Success(c, fiber.StatusOK, "Notifications retrieved", notifications)
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Close()
+
+		heartbeat := time.NewTicker(streamHeartbeat)
+		defer heartbeat.Stop()
+		expiry := time.NewTimer(streamMaxDuration)
+		defer expiry.Stop()
+
+		fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+		replayed := make(map[string]bool, len(missed))
+		for _, n := range missed {
+			writeNotificationEvent(w, n)
+			replayed[n.ID] = true
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case n, ok := <-sub.C:
+				if !ok {
+					// Dropped by the hub; the client resumes from its last event
+					return
+				}
+				if replayed[n.ID] {
+					continue
+				}
+				writeNotificationEvent(w, dto.ToNotificationResponse(&n))
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": ping\n\n")
+			case <-expiry.C:
+				return
+			}
+			// A failed flush means the client went away
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+	return nil
+}
+
+// writeNotificationEvent writes a notification as a Server-Sent Event
+func writeNotificationEvent(w *bufio.Writer, n dto.NotificationResponse) {
+	data, _ := json.Marshal(n)
+	fmt.Fprintf(w, "id: %s\nevent: notification\ndata: %s\n\n", n.ID, data)
 }
 
 // GetUnreadCount godoc
@@ -51,7 +158,7 @@ func (h *NotificationHandler) GetMyNotifications(c *fiber.Ctx) error {
 // @Router /notifications/unread-count [get]
 func (h *NotificationHandler) GetUnreadCount(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
-	count, err := h.notifService.GetUnreadCount(userID)
+	count, err := h.notifService.GetUnreadCount(c.UserContext(), userID)
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to count notifications")
 	}
@@ -70,7 +177,7 @@ func (h *NotificationHandler) MarkAsRead(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
 	id := c.Params("id")
 
-	if err := h.notifService.MarkAsRead(id, userID); err != nil {
+	if err := h.notifService.MarkAsRead(c.UserContext(), id, userID); err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to mark notification as read")
 	}
 	return response.Success(c, fiber.StatusOK, "Notification marked as read", nil)
@@ -86,8 +193,150 @@ func (h *NotificationHandler) MarkAsRead(c *fiber.Ctx) error {
 func (h *NotificationHandler) MarkAllAsRead(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
 
-	if err := h.notifService.MarkAllAsRead(userID); err != nil {
+	if err := h.notifService.MarkAllAsRead(c.UserContext(), userID); err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to mark all notifications as read")
 	}
 	return response.Success(c, fiber.StatusOK, "All notifications marked as read", nil)
 }
+
+// Archive godoc
+// @Summary Archive notifications
+// @Description Moves notifications of the authenticated user out of the inbox into the archive, at most 100 at a time. Archived notifications do not count as unread. IDs that are not the user's or already archived are skipped
+// @Tags Notifications
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.NotificationIDsRequest true "Notification IDs"
+// @Success 200 {object} response.Response{data=dto.NotificationBulkResponse}
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /notifications/archive [post]
+func (h *NotificationHandler) Archive(c *fiber.Ctx) error {
+	return h.setArchived(c, true)
+}
+
+// Unarchive godoc
+// @Summary Unarchive notifications
+// @Description Moves archived notifications of the authenticated user back to the inbox, at most 100 at a time
+// @Tags Notifications
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.NotificationIDsRequest true "Notification IDs"
+// @Success 200 {object} response.Response{data=dto.NotificationBulkResponse}
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /notifications/unarchive [post]
+func (h *NotificationHandler) Unarchive(c *fiber.Ctx) error {
+	return h.setArchived(c, false)
+}
+
+func (h *NotificationHandler) setArchived(c *fiber.Ctx, archived bool) error {
+	userID := c.Locals("userID").(string)
+
+	var req dto.NotificationIDsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	result, err := h.notifService.SetArchived(c.UserContext(), userID, req, archived)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	if archived {
+		return response.Success(c, fiber.StatusOK, "Notifications archived", result)
+	}
+	return response.Success(c, fiber.StatusOK, "Notifications unarchived", result)
+}
+
+// Delete godoc
+// @Summary Delete notifications
+// @Description Deletes notifications of the authenticated user, at most 100 at a time. IDs that are not the user's are skipped
+// @Tags Notifications
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.NotificationIDsRequest true "Notification IDs"
+// @Success 200 {object} response.Response{data=dto.NotificationBulkResponse}
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /notifications/delete [post]
+func (h *NotificationHandler) Delete(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req dto.NotificationIDsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	result, err := h.notifService.Delete(c.UserContext(), userID, req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Notifications deleted", result)
+}
+
+// DeleteOne godoc
+// @Summary Delete a notification
+// @Tags Notifications
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.Response "Notification not found"
+// @Router /notifications/{id} [delete]
+func (h *NotificationHandler) DeleteOne(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	id := c.Params("id")
+
+	result, err := h.notifService.Delete(c.UserContext(), userID, dto.NotificationIDsRequest{IDs: []string{id}})
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	if result.Count == 0 {
+		return response.Error(c, fiber.StatusNotFound, "Notification not found")
+	}
+	return response.Success(c, fiber.StatusOK, "Notification deleted", nil)
+}
+
+// GetPreferences godoc
+// @Summary Get my notification preferences
+// @Description Returns how the authenticated user is notified of each event type: in_app (in the application only), email (in the application and by email, the default), digest (in one summary a day) or off. Password resets are always delivered right away
+// @Tags Notifications
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.NotificationPreferenceResponse}
+// @Failure 500 {object} response.Response "Failed to fetch notification preferences"
+// @Router /notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	prefs, err := h.notifService.GetPreferences(c.UserContext(), userID)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Notification preferences retrieved", prefs)
+}
+
+// UpdatePreferences godoc
+// @Summary Update my notification preferences
+// @Description Sets the mode of the listed event types for the authenticated user; the others keep theirs. Returns the preferences of every event type
+// @Tags Notifications
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateNotificationPreferencesRequest true "Modes per event type"
+// @Success 200 {object} response.Response{data=[]dto.NotificationPreferenceResponse}
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req dto.UpdateNotificationPreferencesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	prefs, err := h.notifService.UpdatePreferences(c.UserContext(), userID, req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Notification preferences updated", prefs)
+}
@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"net/url"
+
+	"hris-backend/config"
+	"hris-backend/internal/dto"
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OIDCHandler struct {
+	oidcService service.OIDCService
+	authService service.AuthService
+	cfg         *config.Config
+}
+
+func NewOIDCHandler(oidcService service.OIDCService, authService service.AuthService, cfg *config.Config) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService: oidcService,
+		authService: authService,
+		cfg:         cfg,
+	}
+}
+
+// GetLoginOptions godoc
+// @Summary Get single sign-on providers
+// @Description List the active OIDC providers to offer on the login page, with the URL that starts a login at each
+// @Tags Single Sign-On
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.OIDCLoginOptionResponse} "SSO providers retrieved"
+// @Failure 500 {object} response.Response "Failed to fetch SSO providers"
+// @Router /auth/oidc/providers [get]
+func (h *OIDCHandler) GetLoginOptions(c *fiber.Ctx) error {
+	options, err := h.oidcService.GetLoginOptions(c.UserContext())
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch SSO providers")
+	}
+	return response.Success(c, fiber.StatusOK, "SSO providers retrieved", options)
+}
+
+// Login godoc
+// @Summary Start a single sign-on login
+// @Description Redirect the browser to the OIDC provider to log in with the authorization-code flow and PKCE. After the callback the browser returns to the frontend path given in redirect
+// @Tags Single Sign-On
+// @Param provider path string true "Provider slug"
+// @Param redirect query string false "Frontend path to return to after login"
+// @Success 302 "Redirect to the identity provider"
+// @Router /auth/oidc/{provider}/login [get]
+func (h *OIDCHandler) Login(c *fiber.Ctx) error {
+	authURL, err := h.oidcService.StartLogin(c.UserContext(), c.Params("provider"), c.Query("redirect"))
+	if err != nil {
+		return h.redirectError(c, err.Error())
+	}
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// Link godoc
+// @Summary Link an SSO provider to your account
+// @Description Start a login at the OIDC provider that links the identity it confirms to your account. Needed for users of several companies, whose first SSO login is not linked by email. Send the browser to the returned URL; after the callback it returns to the frontend path given in redirect
+// @Tags Single Sign-On
+// @Security Bearer
+// @Produce json
+// @Param provider path string true "Provider slug"
+// @Param redirect query string false "Frontend path to return to after linking"
+// @Success 200 {object} response.Response{data=dto.OIDCLinkResponse} "SSO link started"
+// @Failure 400 {object} response.Response "Failed to start link"
+// @Router /auth/oidc/{provider}/link [post]
+func (h *OIDCHandler) Link(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	authURL, err := h.oidcService.StartLink(c.UserContext(), userID, c.Params("provider"), c.Query("redirect"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "SSO link started", dto.OIDCLinkResponse{AuthorizationURL: authURL})
+}
+
+// Callback godoc
+// @Summary Complete a single sign-on login
+// @Description The OIDC provider's redirect URI. Validates the login, opens a session like a password login and sets the refresh token in cookie, then redirects to the frontend. Users with two-factor authentication get no session: they are sent to the frontend login page with challenge_token and redirect in the URL fragment, to complete at /auth/2fa/verify. Links started at /auth/oidc/{provider}/link redirect without a new session. Failures redirect to the frontend login page with an sso_error query parameter
+// @Tags Single Sign-On
+// @Param provider path string true "Provider slug"
+// @Param code query string false "Authorization code"
+// @Param state query string true "Login state"
+// @Success 302 "Redirect to the frontend"
+// @Router /auth/oidc/{provider}/callback [get]
+func (h *OIDCHandler) Callback(c *fiber.Ctx) error {
+	if errCode := c.Query("error"); errCode != "" {
+		message := c.Query("error_description")
+		if message == "" {
+			message = errCode
+		}
+		return h.redirectError(c, message)
+	}
+
+	if c.Query("code") == "" || c.Query("state") == "" {
+		return h.redirectError(c, "code and state are required")
+	}
+
+	login, err := h.oidcService.CompleteLogin(c.UserContext(), c.Params("provider"), c.Query("code"), c.Query("state"))
+	if err != nil {
+		return h.redirectError(c, err.Error())
+	}
+
+	if login.Linked {
+		return c.Redirect(h.cfg.FrontendURL+login.RedirectPath, fiber.StatusFound)
+	}
+
+	tokenResp, refreshToken, err := h.authService.SingleSignOn(c.UserContext(), login.UserID, c.Get(fiber.HeaderUserAgent), c.IP())
+	if err != nil {
+		return h.redirectError(c, err.Error())
+	}
+
+	// The fragment keeps the challenge out of server logs and referrers
+	if tokenResp.TwoFactorRequired {
+		fragment := url.Values{"challenge_token": {tokenResp.ChallengeToken}, "redirect": {login.RedirectPath}}
+		return c.Redirect(h.cfg.FrontendURL+"/login#"+fragment.Encode(), fiber.StatusFound)
+	}
+
+	// The frontend trades the refresh cookie for an access token on its
+	// first API call, so no token travels in the URL
+	setRefreshCookie(c, refreshToken)
+	return c.Redirect(h.cfg.FrontendURL+login.RedirectPath, fiber.StatusFound)
+}
+
+func (h *OIDCHandler) redirectError(c *fiber.Ctx, message string) error {
+	return c.Redirect(h.cfg.FrontendURL+"/login?sso_error="+url.QueryEscape(message), fiber.StatusFound)
+}
+
+// GetAll godoc
+// @Summary Get SSO providers
+// @Description Retrieve the OIDC providers of your companies. Client secrets are never returned
+// @Tags Single Sign-On
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.OIDCProviderResponse} "SSO providers retrieved"
+// @Failure 500 {object} response.Response "Failed to fetch SSO providers"
+// @Router /sso-providers [get]
+func (h *OIDCHandler) GetAll(c *fiber.Ctx) error {
+	providers, err := h.oidcService.GetAll(c.UserContext())
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch SSO providers")
+	}
+	return response.Success(c, fiber.StatusOK, "SSO providers retrieved", providers)
+}
+
+// GetByID godoc
+// @Summary Get SSO provider by ID
+// @Description Retrieve an OIDC provider by ID
+// @Tags Single Sign-On
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Provider ID"
+// @Success 200 {object} response.Response{data=dto.OIDCProviderResponse} "SSO provider retrieved"
+// @Failure 404 {object} response.Response "SSO provider not found"
+// @Router /sso-providers/{id} [get]
+func (h *OIDCHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	provider, err := h.oidcService.GetByID(c.UserContext(), id)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "SSO provider retrieved", provider)
+}
+
+// Create godoc
+// @Summary Create an SSO provider
+// @Description Register a company's OIDC provider. The issuer must be an https URL. Register {APP_BASE_URL}/api/auth/oidc/{slug}/callback as the redirect URI at the provider. With auto_provision, unknown emails get an account with default_role in the company
+// @Tags Single Sign-On
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateOIDCProviderRequest true "Provider data"
+// @Success 201 {object} response.Response{data=dto.OIDCProviderResponse} "SSO provider created"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /sso-providers [post]
+func (h *OIDCHandler) Create(c *fiber.Ctx) error {
+	var req dto.CreateOIDCProviderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.CompanyID == "" || req.Slug == "" || req.Name == "" || req.IssuerURL == "" || req.ClientID == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Company ID, slug, name, issuer URL and client ID are required")
+	}
+
+	provider, err := h.oidcService.Create(c.UserContext(), req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusCreated, "SSO provider created", provider)
+}
+
+// Update godoc
+// @Summary Update an SSO provider
+// @Description Update an OIDC provider. The client secret is kept unless given
+// @Tags Single Sign-On
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "Provider ID"
+// @Param request body dto.UpdateOIDCProviderRequest true "Provider data"
+// @Success 200 {object} response.Response{data=dto.OIDCProviderResponse} "SSO provider updated"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /sso-providers/{id} [put]
+func (h *OIDCHandler) Update(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req dto.UpdateOIDCProviderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	provider, err := h.oidcService.Update(c.UserContext(), id, req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "SSO provider updated", provider)
+}
+
+// Delete godoc
+// @Summary Delete an SSO provider
+// @Description Delete an OIDC provider. Accounts created through it are kept
+// @Tags Single Sign-On
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Provider ID"
+// @Success 200 {object} response.Response "SSO provider deleted"
+// @Failure 400 {object} response.Response "Failed to delete"
+// @Router /sso-providers/{id} [delete]
+func (h *OIDCHandler) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.oidcService.Delete(c.UserContext(), id); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "SSO provider deleted", nil)
+}
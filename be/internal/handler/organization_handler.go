@@ -33,7 +33,7 @@ func (h *OrganizationHandler) GetStructure(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "company_id query parameter is required")
 	}
 
-	result, err := h.orgService.GetStructure(companyID)
+	result, err := h.orgService.GetStructure(c.UserContext(), companyID)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
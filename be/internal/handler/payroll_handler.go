@@ -4,6 +4,9 @@ import (
 	"strconv"
 
 	"hris-backend/internal/dto"
+	"hris-backend/internal/middleware"
+	"hris-backend/internal/model"
+	"hris-backend/internal/permissions"
 	"hris-backend/internal/service"
 	"hris-backend/pkg/response"
 
@@ -46,7 +49,7 @@ func (h *PayrollHandler) GetAll(c *fiber.Ctx) error {
 		if err != nil {
 			return response.Error(c, fiber.StatusBadRequest, "Invalid year format")
 		}
-		payrolls, err := h.payrollService.GetByPeriod(month, year)
+		payrolls, err := h.payrollService.GetByPeriod(c.UserContext(), month, year)
 		if err != nil {
 			return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch payrolls")
 		}
@@ -54,14 +57,14 @@ func (h *PayrollHandler) GetAll(c *fiber.Ctx) error {
 	}
 
 	if employeeID != "" {
-		payrolls, err := h.payrollService.GetByEmployeeID(employeeID)
+		payrolls, err := h.payrollService.GetByEmployeeID(c.UserContext(), employeeID)
 		if err != nil {
 			return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch payrolls")
 		}
 		return response.Success(c, fiber.StatusOK, "Payrolls retrieved", payrolls)
 	}
 
-	payrolls, err := h.payrollService.GetAll()
+	payrolls, err := h.payrollService.GetAll(c.UserContext())
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch payrolls")
 	}
@@ -80,11 +83,11 @@ func (h *PayrollHandler) GetAll(c *fiber.Ctx) error {
 // @Router /payrolls/me [get]
 func (h *PayrollHandler) GetMyPayslips(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
-	emp, err := h.empService.GetByUserID(userID)
+	emp, err := h.empService.GetByUserID(c.UserContext(), userID)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, "Employee profile not found")
 	}
-	payslips, err := h.payrollService.GetPaidByEmployeeID(emp.ID)
+	payslips, err := h.payrollService.GetPaidByEmployeeID(c.UserContext(), emp.ID)
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch payslips")
 	}
@@ -103,7 +106,7 @@ func (h *PayrollHandler) GetMyPayslips(c *fiber.Ctx) error {
 // @Router /payrolls/{id} [get]
 func (h *PayrollHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
-	payroll, err := h.payrollService.GetByID(id)
+	payroll, err := h.payrollService.GetByID(c.UserContext(), id)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -139,10 +142,10 @@ func (h *PayrollHandler) Generate(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid year")
 	}
 
-	payroll, err := h.payrollService.Generate(req)
+	payroll, err := h.payrollService.Generate(c.UserContext(), req)
 	if err != nil {
 		if err.Error() == "employee salary not found, please set salary first" {
-			emp, lookupErr := h.empService.GetByID(req.EmployeeID)
+			emp, lookupErr := h.empService.GetByID(c.UserContext(), req.EmployeeID)
 			if lookupErr == nil && emp.Position != nil && emp.Position.BaseSalary > 0 {
 				return response.ErrorWithData(c, fiber.StatusBadRequest, err.Error(), fiber.Map{
 					"suggested_basic_salary": emp.Position.BaseSalary,
@@ -175,7 +178,7 @@ func (h *PayrollHandler) Update(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	payroll, err := h.payrollService.Update(id, req)
+	payroll, err := h.payrollService.Update(c.UserContext(), id, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -210,7 +213,11 @@ func (h *PayrollHandler) UpdateStatus(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid status. Must be draft, processed, or paid")
 	}
 
-	payroll, err := h.payrollService.UpdateStatus(id, req)
+	if !middleware.HasPermission(c, payrollStatusPermission(req.Status)) {
+		return response.Error(c, fiber.StatusForbidden, "Insufficient permissions")
+	}
+
+	payroll, err := h.payrollService.UpdateStatus(c.UserContext(), id, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -230,8 +237,18 @@ func (h *PayrollHandler) UpdateStatus(c *fiber.Ctx) error {
 func (h *PayrollHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	if err := h.payrollService.Delete(id); err != nil {
+	if err := h.payrollService.Delete(c.UserContext(), id); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	return response.Success(c, fiber.StatusOK, "Payroll deleted", nil)
 }
+
+// payrollStatusPermission returns the permission needed to move a payroll or
+// payroll run to status: paying needs payroll:pay, the other transitions
+// payroll:approve
+func payrollStatusPermission(status model.PayrollStatus) string {
+	if status == model.PayrollPaid {
+		return permissions.PayrollPay
+	}
+	return permissions.PayrollApprove
+}
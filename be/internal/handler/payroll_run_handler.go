@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"hris-backend/internal/dto"
+	"hris-backend/internal/middleware"
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PayrollRunHandler struct {
+	runService service.PayrollRunService
+}
+
+func NewPayrollRunHandler(runService service.PayrollRunService) *PayrollRunHandler {
+	return &PayrollRunHandler{runService: runService}
+}
+
+// GetAll godoc
+// @Summary Get all payroll runs
+// @Description Retrieve all payroll runs, optionally filtered by company
+// @Tags Payroll Runs
+// @Security Bearer
+// @Produce json
+// @Param company_id query string false "Filter by company ID"
+// @Success 200 {object} response.Response{data=[]dto.PayrollRunResponse} "Payroll runs retrieved"
+// @Failure 500 {object} response.Response "Failed to fetch payroll runs"
+// @Router /payroll-runs [get]
+func (h *PayrollRunHandler) GetAll(c *fiber.Ctx) error {
+	runs, err := h.runService.GetAll(c.UserContext(), c.Query("company_id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch payroll runs")
+	}
+	return response.Success(c, fiber.StatusOK, "Payroll runs retrieved", runs)
+}
+
+// GetByID godoc
+// @Summary Get payroll run by ID
+// @Description Retrieve a payroll run and its payrolls by ID
+// @Tags Payroll Runs
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Payroll run ID"
+// @Success 200 {object} response.Response{data=dto.PayrollRunResponse} "Payroll run retrieved"
+// @Failure 404 {object} response.Response "Payroll run not found"
+// @Router /payroll-runs/{id} [get]
+func (h *PayrollRunHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	run, err := h.runService.GetByID(c.UserContext(), id)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Payroll run retrieved", run)
+}
+
+// Generate godoc
+// @Summary Generate a payroll run
+// @Description Generate draft payrolls for every active employee of a company for a specific month and year. Employees that already have a payroll for the period are skipped and employees that cannot be calculated are reported as failed.
+// @Tags Payroll Runs
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.GeneratePayrollRunRequest true "Payroll run generation data"
+// @Success 201 {object} response.Response{data=dto.PayrollRunGenerateResponse} "Payroll run generated"
+// @Failure 400 {object} response.Response{data=dto.PayrollRunGenerateResponse} "Invalid request or no payrolls generated"
+// @Router /payroll-runs [post]
+func (h *PayrollRunHandler) Generate(c *fiber.Ctx) error {
+	var req dto.GeneratePayrollRunRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.CompanyID == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Company ID is required")
+	}
+
+	if req.Month < 1 || req.Month > 12 {
+		return response.Error(c, fiber.StatusBadRequest, "Month must be between 1 and 12")
+	}
+
+	if req.Year < 2000 {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid year")
+	}
+
+	userID := c.Locals("userID").(string)
+	result, err := h.runService.Generate(c.UserContext(), req, userID)
+	if err != nil {
+		if result != nil {
+			return response.ErrorWithData(c, fiber.StatusBadRequest, err.Error(), result)
+		}
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusCreated, "Payroll run generated", result)
+}
+
+// UpdateStatus godoc
+// @Summary Update payroll run status
+// @Description Move a payroll run and all of its payrolls to the next status (draft, processed, paid)
+// @Tags Payroll Runs
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "Payroll run ID"
+// @Param request body dto.PayrollStatusRequest true "Status data"
+// @Success 200 {object} response.Response{data=dto.PayrollRunResponse} "Payroll run status updated"
+// @Failure 400 {object} response.Response "Invalid request or status"
+// @Router /payroll-runs/{id}/status [put]
+func (h *PayrollRunHandler) UpdateStatus(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req dto.PayrollStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.Status == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Status is required")
+	}
+
+	if req.Status != "draft" && req.Status != "processed" && req.Status != "paid" {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid status. Must be draft, processed, or paid")
+	}
+
+	if !middleware.HasPermission(c, payrollStatusPermission(req.Status)) {
+		return response.Error(c, fiber.StatusForbidden, "Insufficient permissions")
+	}
+
+	run, err := h.runService.UpdateStatus(c.UserContext(), id, req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Payroll run status updated", run)
+}
+
+// Delete godoc
+// @Summary Delete a payroll run
+// @Description Delete a draft payroll run together with its payrolls
+// @Tags Payroll Runs
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Payroll run ID"
+// @Success 200 {object} response.Response "Payroll run deleted"
+// @Failure 400 {object} response.Response "Failed to delete"
+// @Router /payroll-runs/{id} [delete]
+func (h *PayrollRunHandler) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.runService.Delete(c.UserContext(), id); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Payroll run deleted", nil)
+}
@@ -29,14 +29,14 @@ func NewPositionHandler(posService service.PositionService) *PositionHandler {
 func (h *PositionHandler) GetAll(c *fiber.Ctx) error {
 	companyID := c.Query("company_id")
 	if companyID != "" {
-		positions, err := h.posService.GetByCompanyID(companyID)
+		positions, err := h.posService.GetByCompanyID(c.UserContext(), companyID)
 		if err != nil {
 			return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch positions")
 		}
 		return response.Success(c, fiber.StatusOK, "Positions retrieved", positions)
 	}
 
-	positions, err := h.posService.GetAll()
+	positions, err := h.posService.GetAll(c.UserContext())
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch positions")
 	}
@@ -55,7 +55,7 @@ func (h *PositionHandler) GetAll(c *fiber.Ctx) error {
 // @Router /positions/{id} [get]
 func (h *PositionHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
-	pos, err := h.posService.GetByID(id)
+	pos, err := h.posService.GetByID(c.UserContext(), id)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -83,7 +83,7 @@ func (h *PositionHandler) Create(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Company ID and name are required")
 	}
 
-	pos, err := h.posService.Create(req)
+	pos, err := h.posService.Create(c.UserContext(), req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -110,7 +110,7 @@ func (h *PositionHandler) Update(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	pos, err := h.posService.Update(id, req)
+	pos, err := h.posService.Update(c.UserContext(), id, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -130,7 +130,7 @@ func (h *PositionHandler) Update(c *fiber.Ctx) error {
 func (h *PositionHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	if err := h.posService.Delete(id); err != nil {
+	if err := h.posService.Delete(c.UserContext(), id); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	return response.Success(c, fiber.StatusOK, "Position deleted", nil)
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"hris-backend/internal/dto"
+	"hris-backend/internal/middleware"
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RoleHandler struct {
+	roleService service.CustomRoleService
+	permService service.PermissionService
+}
+
+func NewRoleHandler(roleService service.CustomRoleService, permService service.PermissionService) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+		permService: permService,
+	}
+}
+
+// GetPermissions godoc
+// @Summary Get the permission catalog
+// @Description Retrieve every permission that can be granted to a custom role
+// @Tags Roles
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.PermissionResponse} "Permissions retrieved"
+// @Failure 500 {object} response.Response "Failed to fetch permissions"
+// @Router /roles/permissions [get]
+func (h *RoleHandler) GetPermissions(c *fiber.Ctx) error {
+	perms, err := h.permService.GetCatalog(c.UserContext())
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch permissions")
+	}
+	return response.Success(c, fiber.StatusOK, "Permissions retrieved", perms)
+}
+
+// GetAll godoc
+// @Summary Get all custom roles
+// @Description Retrieve all custom roles, optionally filtered by company
+// @Tags Roles
+// @Security Bearer
+// @Produce json
+// @Param company_id query string false "Filter by company ID"
+// @Success 200 {object} response.Response{data=[]dto.CustomRoleResponse} "Roles retrieved"
+// @Failure 500 {object} response.Response "Failed to fetch roles"
+// @Router /roles [get]
+func (h *RoleHandler) GetAll(c *fiber.Ctx) error {
+	companyID := c.Query("company_id")
+
+	if companyID != "" {
+		roles, err := h.roleService.GetByCompanyID(c.UserContext(), companyID)
+		if err != nil {
+			return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch roles")
+		}
+		return response.Success(c, fiber.StatusOK, "Roles retrieved", roles)
+	}
+
+	roles, err := h.roleService.GetAll(c.UserContext())
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch roles")
+	}
+	return response.Success(c, fiber.StatusOK, "Roles retrieved", roles)
+}
+
+// GetByID godoc
+// @Summary Get custom role by ID
+// @Description Retrieve a custom role and its permissions by ID
+// @Tags Roles
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Role ID"
+// @Success 200 {object} response.Response{data=dto.CustomRoleResponse} "Role retrieved"
+// @Failure 404 {object} response.Response "Role not found"
+// @Router /roles/{id} [get]
+func (h *RoleHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	role, err := h.roleService.GetByID(c.UserContext(), id)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Role retrieved", role)
+}
+
+// Create godoc
+// @Summary Create a custom role
+// @Description Create a company role from permissions in the catalog, e.g. a payroll officer with payroll:read and payroll:generate but not payroll:pay. Users assigned the role get exactly these permissions. Callers can only grant permissions they have themselves.
+// @Tags Roles
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateCustomRoleRequest true "Role data"
+// @Success 201 {object} response.Response{data=dto.CustomRoleResponse} "Role created"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /roles [post]
+func (h *RoleHandler) Create(c *fiber.Ctx) error {
+	var req dto.CreateCustomRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.CompanyID == "" || req.Name == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Company ID and name are required")
+	}
+
+	role, err := h.roleService.Create(c.UserContext(), middleware.CurrentActor(c), req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusCreated, "Role created", role)
+}
+
+// Update godoc
+// @Summary Update a custom role
+// @Description Update a custom role. When permissions are given they replace the role's permissions; callers can only grant permissions they have themselves.
+// @Tags Roles
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Param request body dto.UpdateCustomRoleRequest true "Role data"
+// @Success 200 {object} response.Response{data=dto.CustomRoleResponse} "Role updated"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /roles/{id} [put]
+func (h *RoleHandler) Update(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req dto.UpdateCustomRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	role, err := h.roleService.Update(c.UserContext(), middleware.CurrentActor(c), id, req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Role updated", role)
+}
+
+// Delete godoc
+// @Summary Delete a custom role
+// @Description Delete a custom role that is no longer assigned to any user
+// @Tags Roles
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Role ID"
+// @Success 200 {object} response.Response "Role deleted"
+// @Failure 400 {object} response.Response "Failed to delete"
+// @Router /roles/{id} [delete]
+func (h *RoleHandler) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.roleService.Delete(c.UserContext(), id); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Role deleted", nil)
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"hris-backend/internal/dto"
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ServiceAccountHandler struct {
+	serviceAccountService service.ServiceAccountService
+	apiTokenService       service.APITokenService
+	permService           service.PermissionService
+}
+
+func NewServiceAccountHandler(serviceAccountService service.ServiceAccountService, apiTokenService service.APITokenService, permService service.PermissionService) *ServiceAccountHandler {
+	return &ServiceAccountHandler{
+		serviceAccountService: serviceAccountService,
+		apiTokenService:       apiTokenService,
+		permService:           permService,
+	}
+}
+
+// GetAll godoc
+// @Summary Get service accounts
+// @Description Retrieve the service accounts of your companies
+// @Tags Service Accounts
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.ServiceAccountResponse} "Service accounts retrieved"
+// @Failure 500 {object} response.Response "Failed to fetch service accounts"
+// @Router /service-accounts [get]
+func (h *ServiceAccountHandler) GetAll(c *fiber.Ctx) error {
+	accounts, err := h.serviceAccountService.GetAll(c.UserContext())
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch service accounts")
+	}
+	return response.Success(c, fiber.StatusOK, "Service accounts retrieved", accounts)
+}
+
+// GetByID godoc
+// @Summary Get service account by ID
+// @Description Retrieve a service account by ID
+// @Tags Service Accounts
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Service account ID"
+// @Success 200 {object} response.Response{data=dto.ServiceAccountResponse} "Service account retrieved"
+// @Failure 404 {object} response.Response "Service account not found"
+// @Router /service-accounts/{id} [get]
+func (h *ServiceAccountHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	account, err := h.serviceAccountService.GetByID(c.UserContext(), id)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Service account retrieved", account)
+}
+
+// Create godoc
+// @Summary Create a service account
+// @Description Create a non-human account for an integration, bound to one company. It cannot log in; it authenticates with API tokens whose permissions are taken from its role
+// @Tags Service Accounts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateServiceAccountRequest true "Service account data"
+// @Success 201 {object} response.Response{data=dto.ServiceAccountResponse} "Service account created"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /service-accounts [post]
+func (h *ServiceAccountHandler) Create(c *fiber.Ctx) error {
+	var req dto.CreateServiceAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.CompanyID == "" || req.Name == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Company ID and name are required")
+	}
+
+	account, err := h.serviceAccountService.Create(c.UserContext(), req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusCreated, "Service account created", account)
+}
+
+// Update godoc
+// @Summary Update a service account
+// @Description Rename a service account, change its role or deactivate it. A deactivated account's tokens stop working
+// @Tags Service Accounts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "Service account ID"
+// @Param request body dto.UpdateServiceAccountRequest true "Service account data"
+// @Success 200 {object} response.Response{data=dto.ServiceAccountResponse} "Service account updated"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /service-accounts/{id} [put]
+func (h *ServiceAccountHandler) Update(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req dto.UpdateServiceAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	account, err := h.serviceAccountService.Update(c.UserContext(), id, req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Service account updated", account)
+}
+
+// Delete godoc
+// @Summary Delete a service account
+// @Description Delete a service account and revoke its API tokens
+// @Tags Service Accounts
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Service account ID"
+// @Success 200 {object} response.Response "Service account deleted"
+// @Failure 400 {object} response.Response "Failed to delete"
+// @Router /service-accounts/{id} [delete]
+func (h *ServiceAccountHandler) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.serviceAccountService.Delete(c.UserContext(), id); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Service account deleted", nil)
+}
+
+// GetTokens godoc
+// @Summary Get a service account's API tokens
+// @Description Retrieve the API tokens of a service account that are not revoked
+// @Tags Service Accounts
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Service account ID"
+// @Success 200 {object} response.Response{data=[]dto.APITokenResponse} "API tokens retrieved"
+// @Failure 404 {object} response.Response "Service account not found"
+// @Router /service-accounts/{id}/tokens [get]
+func (h *ServiceAccountHandler) GetTokens(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if _, err := h.serviceAccountService.GetByID(c.UserContext(), id); err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+
+	tokens, err := h.apiTokenService.GetByUserID(c.UserContext(), id)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "API tokens retrieved", tokens)
+}
+
+// CreateToken godoc
+// @Summary Create a service account API token
+// @Description Create a token for a service account, valid in its company and with at most the listed permissions of its role. The token is shown only in this response
+// @Tags Service Accounts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "Service account ID"
+// @Param request body dto.CreateAPITokenRequest true "Token data; company_id is ignored"
+// @Success 201 {object} response.Response{data=dto.CreatedAPITokenResponse} "API token created"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Failure 403 {object} response.Response "API tokens cannot create API tokens"
+// @Router /service-accounts/{id}/tokens [post]
+func (h *ServiceAccountHandler) CreateToken(c *fiber.Ctx) error {
+	if c.Locals("apiTokenID") != nil {
+		return response.Error(c, fiber.StatusForbidden, "API tokens cannot create API tokens")
+	}
+
+	id := c.Params("id")
+
+	var req dto.CreateAPITokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.Name == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Name is required")
+	}
+
+	account, err := h.serviceAccountService.GetByID(c.UserContext(), id)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	req.CompanyID = account.CompanyID
+
+	granted, err := h.permService.Resolve(c.UserContext(), account.ID, string(account.Role))
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	token, err := h.apiTokenService.Create(c.UserContext(), account.ID, granted, req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusCreated, "API token created", token)
+}
+
+// RevokeToken godoc
+// @Summary Revoke a service account API token
+// @Description Revoke an API token of a service account. It stops working immediately
+// @Tags Service Accounts
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Service account ID"
+// @Param tokenId path string true "Token ID"
+// @Success 200 {object} response.Response "API token revoked"
+// @Failure 404 {object} response.Response "API token not found"
+// @Router /service-accounts/{id}/tokens/{tokenId} [delete]
+func (h *ServiceAccountHandler) RevokeToken(c *fiber.Ctx) error {
+	id := c.Params("id")
+	tokenID := c.Params("tokenId")
+
+	if _, err := h.serviceAccountService.GetByID(c.UserContext(), id); err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+
+	if err := h.apiTokenService.Revoke(c.UserContext(), id, tokenID); err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "API token revoked", nil)
+}
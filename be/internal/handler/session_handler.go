@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"hris-backend/internal/model"
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SessionHandler struct {
+	sessionService service.SessionService
+}
+
+func NewSessionHandler(sessionService service.SessionService) *SessionHandler {
+	return &SessionHandler{sessionService: sessionService}
+}
+
+// GetMine godoc
+// @Summary Get my sessions
+// @Description Retrieve the active sessions (logins) of the current user with their device, IP and last use. The session of the calling token is flagged as current
+// @Tags Authentication
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.SessionResponse} "Sessions retrieved"
+// @Failure 500 {object} response.Response "Failed to fetch sessions"
+// @Router /auth/sessions [get]
+func (h *SessionHandler) GetMine(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	sessionID, _ := c.Locals("sessionID").(string)
+
+	sessions, err := h.sessionService.GetByUserID(c.UserContext(), userID, sessionID)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Sessions retrieved", sessions)
+}
+
+// RevokeMine godoc
+// @Summary Revoke one of my sessions
+// @Description Log out one of the current user's sessions. Its refresh token stops working immediately
+// @Tags Authentication
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} response.Response "Session revoked"
+// @Failure 404 {object} response.Response "Session not found"
+// @Router /auth/sessions/{id} [delete]
+func (h *SessionHandler) RevokeMine(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	id := c.Params("id")
+
+	if err := h.sessionService.Revoke(c.UserContext(), userID, id, model.SessionRevokedByUser); err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Session revoked", nil)
+}
+
+// RevokeMyOthers godoc
+// @Summary Revoke my other sessions
+// @Description Log out every session of the current user except the calling one
+// @Tags Authentication
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} response.Response "Sessions revoked"
+// @Failure 500 {object} response.Response "Failed to revoke sessions"
+// @Router /auth/sessions [delete]
+func (h *SessionHandler) RevokeMyOthers(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	sessionID, _ := c.Locals("sessionID").(string)
+
+	if err := h.sessionService.RevokeAll(c.UserContext(), userID, sessionID, model.SessionRevokedByUser); err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Sessions revoked", nil)
+}
+
+// GetByUser godoc
+// @Summary Get a user's sessions
+// @Description Retrieve the active sessions of a user in your companies
+// @Tags Users
+// @Security Bearer
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response{data=[]dto.SessionResponse} "Sessions retrieved"
+// @Failure 404 {object} response.Response "User not found"
+// @Router /users/{id}/sessions [get]
+func (h *SessionHandler) GetByUser(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	sessions, err := h.sessionService.GetByUserID(c.UserContext(), id, "")
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Sessions retrieved", sessions)
+}
+
+// RevokeForUser godoc
+// @Summary Revoke a user's session
+// @Description Log out one session of a user in your companies
+// @Tags Users
+// @Security Bearer
+// @Produce json
+// @Param id path string true "User ID"
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} response.Response "Session revoked"
+// @Failure 404 {object} response.Response "Session not found"
+// @Router /users/{id}/sessions/{sessionId} [delete]
+func (h *SessionHandler) RevokeForUser(c *fiber.Ctx) error {
+	id := c.Params("id")
+	sessionID := c.Params("sessionId")
+
+	if err := h.sessionService.Revoke(c.UserContext(), id, sessionID, model.SessionRevokedByAdmin); err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Session revoked", nil)
+}
+
+// RevokeAllForUser godoc
+// @Summary Revoke all of a user's sessions
+// @Description Log out every session of a user in your companies
+// @Tags Users
+// @Security Bearer
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response "Sessions revoked"
+// @Failure 404 {object} response.Response "User not found"
+// @Router /users/{id}/sessions [delete]
+func (h *SessionHandler) RevokeAllForUser(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.sessionService.RevokeAll(c.UserContext(), id, "", model.SessionRevokedByAdmin); err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Sessions revoked", nil)
+}
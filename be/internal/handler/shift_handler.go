@@ -29,14 +29,14 @@ func NewShiftHandler(shiftService service.ShiftService) *ShiftHandler {
 func (h *ShiftHandler) GetAll(c *fiber.Ctx) error {
 	companyID := c.Query("company_id")
 	if companyID != "" {
-		shifts, err := h.shiftService.GetByCompanyID(companyID)
+		shifts, err := h.shiftService.GetByCompanyID(c.UserContext(), companyID)
 		if err != nil {
 			return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch shifts")
 		}
 		return response.Success(c, fiber.StatusOK, "Shifts retrieved", shifts)
 	}
 
-	shifts, err := h.shiftService.GetAll()
+	shifts, err := h.shiftService.GetAll(c.UserContext())
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch shifts")
 	}
@@ -55,7 +55,7 @@ func (h *ShiftHandler) GetAll(c *fiber.Ctx) error {
 // @Router /shifts/{id} [get]
 func (h *ShiftHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
-	shift, err := h.shiftService.GetByID(id)
+	shift, err := h.shiftService.GetByID(c.UserContext(), id)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -83,7 +83,7 @@ func (h *ShiftHandler) Create(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Company ID, name, start time, and end time are required")
 	}
 
-	shift, err := h.shiftService.Create(req)
+	shift, err := h.shiftService.Create(c.UserContext(), req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -110,7 +110,7 @@ func (h *ShiftHandler) Update(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	shift, err := h.shiftService.Update(id, req)
+	shift, err := h.shiftService.Update(c.UserContext(), id, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -130,7 +130,7 @@ func (h *ShiftHandler) Update(c *fiber.Ctx) error {
 func (h *ShiftHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	if err := h.shiftService.Delete(id); err != nil {
+	if err := h.shiftService.Delete(c.UserContext(), id); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	return response.Success(c, fiber.StatusOK, "Shift deleted", nil)
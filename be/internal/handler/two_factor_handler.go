@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"hris-backend/internal/dto"
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TwoFactorHandler struct {
+	twoFactorService service.TwoFactorService
+}
+
+func NewTwoFactorHandler(twoFactorService service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{twoFactorService: twoFactorService}
+}
+
+// GetStatus godoc
+// @Summary Get my two-factor status
+// @Description Whether two-factor authentication is enabled for the current user, whether their role requires it, and how many recovery codes are left
+// @Tags Two-Factor Authentication
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.TwoFactorStatusResponse} "Two-factor status retrieved"
+// @Failure 500 {object} response.Response "Failed to fetch two-factor status"
+// @Router /auth/2fa [get]
+func (h *TwoFactorHandler) GetStatus(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	role := c.Locals("role").(string)
+
+	status, err := h.twoFactorService.GetStatus(c.UserContext(), userID, role)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Two-factor status retrieved", status)
+}
+
+// Setup godoc
+// @Summary Start two-factor enrollment
+// @Description Generate a TOTP secret and its otpauth:// URI for an authenticator app. The secret is enabled once confirmed with a code
+// @Tags Two-Factor Authentication
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.TwoFactorSetupResponse} "Two-factor setup started"
+// @Failure 400 {object} response.Response "Two-factor authentication is already enabled"
+// @Router /auth/2fa/setup [post]
+func (h *TwoFactorHandler) Setup(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	setup, err := h.twoFactorService.Setup(c.UserContext(), userID)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Two-factor setup started", setup)
+}
+
+// Enable godoc
+// @Summary Enable two-factor authentication
+// @Description Confirm the secret from setup with a code from the authenticator app. Returns recovery codes, shown only once. Users required to enroll should refresh their access token afterwards
+// @Tags Two-Factor Authentication
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorCodeRequest true "TOTP code"
+// @Success 200 {object} response.Response{data=dto.RecoveryCodesResponse} "Two-factor authentication enabled"
+// @Failure 400 {object} response.Response "Invalid code"
+// @Router /auth/2fa/enable [post]
+func (h *TwoFactorHandler) Enable(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.Code == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Code is required")
+	}
+
+	codes, err := h.twoFactorService.Enable(c.UserContext(), userID, req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Two-factor authentication enabled", codes)
+}
+
+// Disable godoc
+// @Summary Disable two-factor authentication
+// @Description Turn two-factor authentication off with the password and a TOTP or recovery code. Not allowed when the user's role requires two-factor authentication
+// @Tags Two-Factor Authentication
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.DisableTwoFactorRequest true "Password and code"
+// @Success 200 {object} response.Response "Two-factor authentication disabled"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /auth/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	role := c.Locals("role").(string)
+
+	var req dto.DisableTwoFactorRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.Password == "" || req.Code == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Password and code are required")
+	}
+
+	if err := h.twoFactorService.Disable(c.UserContext(), userID, role, req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Two-factor authentication disabled", nil)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Replace all recovery codes with new ones after checking a TOTP code. The new codes are shown only once
+// @Tags Two-Factor Authentication
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorCodeRequest true "TOTP code"
+// @Success 200 {object} response.Response{data=dto.RecoveryCodesResponse} "Recovery codes regenerated"
+// @Failure 400 {object} response.Response "Invalid code"
+// @Router /auth/2fa/recovery-codes [post]
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.Code == "" {
+		return response.Error(c, fiber.StatusBadRequest, "Code is required")
+	}
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(c.UserContext(), userID, req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Recovery codes regenerated", codes)
+}
+
+// Reset godoc
+// @Summary Reset a user's two-factor authentication
+// @Description Remove a user's TOTP secret and recovery codes so they can enroll again. Only a superadmin can reset a superadmin
+// @Tags Users
+// @Security Bearer
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response "Two-factor authentication reset"
+// @Failure 400 {object} response.Response "Failed to reset two-factor authentication"
+// @Router /users/{id}/2fa [delete]
+func (h *TwoFactorHandler) Reset(c *fiber.Ctx) error {
+	role := c.Locals("role").(string)
+	id := c.Params("id")
+
+	if err := h.twoFactorService.Reset(c.UserContext(), role, id); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "Two-factor authentication reset", nil)
+}
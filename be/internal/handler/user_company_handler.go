@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"hris-backend/internal/dto"
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserCompanyHandler struct {
+	scopeService service.CompanyScopeService
+}
+
+func NewUserCompanyHandler(scopeService service.CompanyScopeService) *UserCompanyHandler {
+	return &UserCompanyHandler{scopeService: scopeService}
+}
+
+// GetCompanies godoc
+// @Summary Get a user's companies
+// @Description Retrieve the companies a user is bound to, in addition to the company of their employee record (Superadmin only)
+// @Tags Users
+// @Security Bearer
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response{data=[]dto.UserCompanyResponse} "User companies retrieved"
+// @Failure 404 {object} response.Response "User not found"
+// @Router /users/{id}/companies [get]
+func (h *UserCompanyHandler) GetCompanies(c *fiber.Ctx) error {
+	id := c.Params("id")
+	companies, err := h.scopeService.GetCompanies(c.UserContext(), id)
+	if err != nil {
+		return response.Error(c, fiber.StatusNotFound, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "User companies retrieved", companies)
+}
+
+// SetCompanies godoc
+// @Summary Set a user's companies
+// @Description Replace the companies a user is bound to. Admin and HR users only see and change data of these companies and of their own employee record's company (Superadmin only)
+// @Tags Users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body dto.SetUserCompaniesRequest true "Company IDs"
+// @Success 200 {object} response.Response{data=[]dto.UserCompanyResponse} "User companies updated"
+// @Failure 400 {object} response.Response "Invalid request"
+// @Router /users/{id}/companies [put]
+func (h *UserCompanyHandler) SetCompanies(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req dto.SetUserCompaniesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	companies, err := h.scopeService.SetCompanies(c.UserContext(), id, req)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+	return response.Success(c, fiber.StatusOK, "User companies updated", companies)
+}
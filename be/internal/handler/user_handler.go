@@ -2,6 +2,8 @@ package handler
 
 import (
 	"hris-backend/internal/dto"
+	"hris-backend/internal/middleware"
+	"hris-backend/internal/permissions"
 	"hris-backend/internal/service"
 	"hris-backend/pkg/response"
 
@@ -26,7 +28,7 @@ func NewUserHandler(userService service.UserService) *UserHandler {
 // @Failure 500 {object} response.Response "Failed to fetch users"
 // @Router /users [get]
 func (h *UserHandler) GetAll(c *fiber.Ctx) error {
-	users, err := h.userService.GetAll()
+	users, err := h.userService.GetAll(c.UserContext())
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, "Failed to fetch users")
 	}
@@ -47,14 +49,13 @@ func (h *UserHandler) GetAll(c *fiber.Ctx) error {
 func (h *UserHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	userRole := c.Locals("role").(string)
 	userID := c.Locals("userID").(string)
 
-	if userRole != "superadmin" && userRole != "admin" && userRole != "hr" && userID != id {
+	if userID != id && !middleware.HasPermission(c, permissions.UsersRead) {
 		return response.Error(c, fiber.StatusForbidden, "Access denied")
 	}
 
-	user, err := h.userService.GetByID(id)
+	user, err := h.userService.GetByID(c.UserContext(), id)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -72,7 +73,7 @@ func (h *UserHandler) GetByID(c *fiber.Ctx) error {
 // @Router /users/me [get]
 func (h *UserHandler) GetMe(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
-	user, err := h.userService.GetByID(userID)
+	user, err := h.userService.GetByID(c.UserContext(), userID)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -81,7 +82,7 @@ func (h *UserHandler) GetMe(c *fiber.Ctx) error {
 
 // Create godoc
 // @Summary Create new user
-// @Description Create a new user (admin only)
+// @Description Create a new user (admin only). The role cannot be above the caller's own, superadmin is granted only by superadmins, and a custom role can only be assigned by callers holding all of its permissions
 // @Tags Users
 // @Security Bearer
 // @Accept json
@@ -108,7 +109,7 @@ func (h *UserHandler) Create(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Password must be at least 6 characters")
 	}
 
-	user, err := h.userService.Create(req)
+	user, err := h.userService.Create(c.UserContext(), middleware.CurrentActor(c), req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -117,7 +118,7 @@ func (h *UserHandler) Create(c *fiber.Ctx) error {
 
 // Update godoc
 // @Summary Update user
-// @Description Update user information (admin only)
+// @Description Update user information (admin only). Users whose role is above the caller's cannot be changed, and roles are granted as on create
 // @Tags Users
 // @Security Bearer
 // @Accept json
@@ -143,7 +144,7 @@ func (h *UserHandler) Update(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Password must be at least 6 characters")
 	}
 
-	user, err := h.userService.Update(id, req)
+	user, err := h.userService.Update(c.UserContext(), middleware.CurrentActor(c), id, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -152,13 +153,13 @@ func (h *UserHandler) Update(c *fiber.Ctx) error {
 
 // Delete godoc
 // @Summary Delete user
-// @Description Delete a user by ID (admin only, cannot delete self)
+// @Description Delete a user by ID (admin only, cannot delete self or a user whose role is above your own)
 // @Tags Users
 // @Security Bearer
 // @Produce json
 // @Param id path string true "User ID"
 // @Success 200 {object} response.Response "User deleted"
-// @Failure 400 {object} response.Response "Cannot delete your own account or user not found"
+// @Failure 400 {object} response.Response "Cannot delete your own account, a user above your role or a user not found"
 // @Router /users/{id} [delete]
 func (h *UserHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
@@ -168,7 +169,7 @@ func (h *UserHandler) Delete(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Cannot delete your own account")
 	}
 
-	if err := h.userService.Delete(id); err != nil {
+	if err := h.userService.Delete(c.UserContext(), middleware.CurrentActor(c), id); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	return response.Success(c, fiber.StatusOK, "User deleted", nil)
@@ -26,7 +26,7 @@ func (h *VisitHandler) resolveActingEmployeeID(c *fiber.Ctx) (string, error) {
 	if userID == "" {
 		return "", fiber.NewError(fiber.StatusUnauthorized, "missing user")
 	}
-	emp, err := h.empService.GetByUserID(userID)
+	emp, err := h.empService.GetByUserID(c.UserContext(), userID)
 	if err != nil || emp == nil {
 		return "", fiber.NewError(fiber.StatusForbidden, "no employee record for user")
 	}
@@ -57,7 +57,7 @@ func (h *VisitHandler) Start(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "attendance_id and location are required")
 	}
 
-	v, err := h.service.Start(empID, req)
+	v, err := h.service.Start(c.UserContext(), empID, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -87,7 +87,7 @@ func (h *VisitHandler) End(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	v, err := h.service.End(id, empID, req)
+	v, err := h.service.End(c.UserContext(), id, empID, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -106,7 +106,7 @@ func (h *VisitHandler) End(c *fiber.Ctx) error {
 // @Router /visits/{id} [get]
 func (h *VisitHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
-	v, err := h.service.GetByID(id)
+	v, err := h.service.GetByID(c.UserContext(), id)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -124,7 +124,7 @@ func (h *VisitHandler) GetByID(c *fiber.Ctx) error {
 // @Router /visits/attendance/{attendanceId} [get]
 func (h *VisitHandler) GetByAttendanceID(c *fiber.Ctx) error {
 	attID := c.Params("attendanceId")
-	vs, err := h.service.GetByAttendanceID(attID)
+	vs, err := h.service.GetByAttendanceID(c.UserContext(), attID)
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, err.Error())
 	}
@@ -177,7 +177,7 @@ func (h *VisitHandler) List(c *fiber.Ctx) error {
 		toPtr = &endOfDay
 	}
 
-	out, err := h.service.List(page, limit, employeeID, companyID, fromPtr, toPtr)
+	out, err := h.service.List(c.UserContext(), page, limit, employeeID, companyID, fromPtr, toPtr)
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, err.Error())
 	}
@@ -196,7 +196,7 @@ func (h *VisitHandler) List(c *fiber.Ctx) error {
 // @Router /visits/{id} [delete]
 func (h *VisitHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
-	if err := h.service.Delete(id); err != nil {
+	if err := h.service.Delete(c.UserContext(), id); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	return response.Success(c, fiber.StatusOK, "Visit deleted", nil)
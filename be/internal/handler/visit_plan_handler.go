@@ -38,7 +38,7 @@ func (h *VisitPlanHandler) Create(c *fiber.Ctx) error {
 	if req.EmployeeID == "" || req.PlanDate == "" {
 		return response.Error(c, fiber.StatusBadRequest, "employee_id and plan_date are required")
 	}
-	p, err := h.service.Create(userID, req)
+	p, err := h.service.Create(c.UserContext(), userID, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -62,7 +62,7 @@ func (h *VisitPlanHandler) Update(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
-	p, err := h.service.Update(id, req)
+	p, err := h.service.Update(c.UserContext(), id, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -80,7 +80,7 @@ func (h *VisitPlanHandler) Update(c *fiber.Ctx) error {
 // @Router /visit-plans/{id} [get]
 func (h *VisitPlanHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
-	p, err := h.service.GetByID(id)
+	p, err := h.service.GetByID(c.UserContext(), id)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -107,7 +107,7 @@ func (h *VisitPlanHandler) GetByEmployeeAndDate(c *fiber.Ctx) error {
 	}
 	if empID == "me" {
 		userID, _ := c.Locals("userID").(string)
-		emp, err := h.empService.GetByUserID(userID)
+		emp, err := h.empService.GetByUserID(c.UserContext(), userID)
 		if err != nil || emp == nil {
 			return response.Error(c, fiber.StatusForbidden, "no employee record for user")
 		}
@@ -117,7 +117,7 @@ func (h *VisitPlanHandler) GetByEmployeeAndDate(c *fiber.Ctx) error {
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid date (YYYY-MM-DD)")
 	}
-	p, err := h.service.GetByEmployeeAndDate(empID, date)
+	p, err := h.service.GetByEmployeeAndDate(c.UserContext(), empID, date)
 	if err != nil {
 		return response.Error(c, fiber.StatusNotFound, err.Error())
 	}
@@ -139,7 +139,7 @@ func (h *VisitPlanHandler) ListByEmployee(c *fiber.Ctx) error {
 	empID := c.Params("employeeId")
 	if empID == "me" {
 		userID, _ := c.Locals("userID").(string)
-		emp, err := h.empService.GetByUserID(userID)
+		emp, err := h.empService.GetByUserID(c.UserContext(), userID)
 		if err != nil || emp == nil {
 			return response.Error(c, fiber.StatusForbidden, "no employee record for user")
 		}
@@ -160,7 +160,7 @@ func (h *VisitPlanHandler) ListByEmployee(c *fiber.Ctx) error {
 		}
 		toPtr = &t
 	}
-	ps, err := h.service.ListByEmployee(empID, fromPtr, toPtr)
+	ps, err := h.service.ListByEmployee(c.UserContext(), empID, fromPtr, toPtr)
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, err.Error())
 	}
@@ -178,7 +178,7 @@ func (h *VisitPlanHandler) ListByEmployee(c *fiber.Ctx) error {
 // @Router /visit-plans/{id} [delete]
 func (h *VisitPlanHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
-	if err := h.service.Delete(id); err != nil {
+	if err := h.service.Delete(c.UserContext(), id); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	return response.Success(c, fiber.StatusOK, "Plan deleted", nil)
@@ -204,7 +204,7 @@ func (h *VisitPlanHandler) AddItem(c *fiber.Ctx) error {
 	if req.Location == "" {
 		return response.Error(c, fiber.StatusBadRequest, "location is required")
 	}
-	item, err := h.service.AddItem(planID, req)
+	item, err := h.service.AddItem(c.UserContext(), planID, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -228,7 +228,7 @@ func (h *VisitPlanHandler) UpdateItem(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid request body")
 	}
-	item, err := h.service.UpdateItem(id, req)
+	item, err := h.service.UpdateItem(c.UserContext(), id, req)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -245,7 +245,7 @@ func (h *VisitPlanHandler) UpdateItem(c *fiber.Ctx) error {
 // @Router /visit-plans/items/{itemId} [delete]
 func (h *VisitPlanHandler) DeleteItem(c *fiber.Ctx) error {
 	id := c.Params("itemId")
-	if err := h.service.DeleteItem(id); err != nil {
+	if err := h.service.DeleteItem(c.UserContext(), id); err != nil {
 		return response.Error(c, fiber.StatusBadRequest, err.Error())
 	}
 	return response.Success(c, fiber.StatusOK, "Item deleted", nil)
@@ -274,7 +274,7 @@ func (h *VisitPlanHandler) AdherenceReport(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, "Invalid date (YYYY-MM-DD)")
 	}
 	minimum, _ := strconv.Atoi(c.Query("minimum", "0"))
-	r, err := h.service.AdherenceReport(companyID, date, minimum)
+	r, err := h.service.AdherenceReport(c.UserContext(), companyID, date, minimum)
 	if err != nil {
 		return response.Error(c, fiber.StatusInternalServerError, err.Error())
 	}
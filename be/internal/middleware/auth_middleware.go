@@ -3,16 +3,55 @@ package middleware
 import (
 	"strings"
 
-	"hris-backend/config"
+	"hris-backend/internal/service"
+	"hris-backend/internal/tenant"
 	jwtPkg "hris-backend/pkg/jwt"
 	"hris-backend/pkg/response"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func AuthMiddleware(cfg *config.Config) fiber.Handler {
+// passwordChangeRoutes are the routes open to a user who must change their
+// password before doing anything else
+var passwordChangeRoutes = map[string]bool{
+	"/api/auth/change-password": true,
+	"/api/auth/logout":          true,
+	"/api/users/me":             true,
+}
+
+// twoFactorSetupRoutes are the routes open to a user whose role requires 2FA
+// but who has not enrolled yet
+var twoFactorSetupRoutes = map[string]bool{
+	"/api/auth/2fa":             true,
+	"/api/auth/2fa/setup":       true,
+	"/api/auth/2fa/enable":      true,
+	"/api/auth/change-password": true,
+	"/api/auth/logout":          true,
+	"/api/users/me":             true,
+}
+
+// streamRoutes also take the token in the access_token query parameter,
+// since browsers cannot set headers on an EventSource
+var streamRoutes = map[string]bool{
+	"/api/notifications/stream": true,
+}
+
+// AuthMiddleware validates the bearer token and binds the request to the
+// companies the user may access. The scope is carried in the request's user
+// context, where the tenant GORM callbacks pick it up, and the user's primary
+// company is stored in Locals("companyID"). Superadmins are not scoped.
+// The user's effective permissions are stored in Locals("permissions") for
+// RequirePermission. Tokens of users who must change their password or
+// enroll in 2FA only reach the routes that let them do so.
+//
+// Besides access JWTs the bearer token may be an API token, recognised by its
+// prefix; see authenticateAPIToken.
+func AuthMiddleware(keys *jwtPkg.KeySet, scopeService service.CompanyScopeService, permService service.PermissionService, apiTokenService service.APITokenService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
+		if token := c.Query("access_token"); authHeader == "" && token != "" && streamRoutes[strings.TrimSuffix(c.Path(), "/")] {
+			authHeader = "Bearer " + token
+		}
 		if authHeader == "" {
 			return response.Error(c, fiber.StatusUnauthorized, "Missing authorization header")
 		}
@@ -22,15 +61,85 @@ func AuthMiddleware(cfg *config.Config) fiber.Handler {
 			return response.Error(c, fiber.StatusUnauthorized, "Invalid authorization header format")
 		}
 
-		claims, err := jwtPkg.ValidateAccessToken(parts[1], cfg.JWTSecret)
+		if strings.HasPrefix(parts[1], service.APITokenPrefix) {
+			return authenticateAPIToken(c, parts[1], scopeService, permService, apiTokenService)
+		}
+
+		claims, err := jwtPkg.ValidateAccessToken(parts[1], keys)
 		if err != nil {
 			return response.Error(c, fiber.StatusUnauthorized, "Invalid or expired token")
 		}
 
+		path := strings.TrimSuffix(c.Path(), "/")
+		if claims.MustChangePassword && !passwordChangeRoutes[path] {
+			return response.Error(c, fiber.StatusForbidden, "Password change required")
+		}
+		if claims.TwoFactorSetupRequired && !twoFactorSetupRoutes[path] {
+			return response.Error(c, fiber.StatusForbidden, "Two-factor authentication setup required")
+		}
+
 		c.Locals("userID", claims.UserID)
 		c.Locals("email", claims.Email)
 		c.Locals("role", claims.Role)
+		c.Locals("sessionID", claims.SessionID)
+
+		scope, err := scopeService.Resolve(c.UserContext(), claims.UserID, claims.Role)
+		if err != nil {
+			return response.Error(c, fiber.StatusInternalServerError, err.Error())
+		}
+		if scope != nil {
+			c.Locals("companyIDs", scope.CompanyIDs)
+			if len(scope.CompanyIDs) > 0 {
+				c.Locals("companyID", scope.CompanyIDs[0])
+			}
+			c.SetUserContext(tenant.WithScope(c.UserContext(), scope))
+		}
+
+		perms, err := permService.Resolve(c.UserContext(), claims.UserID, claims.Role)
+		if err != nil {
+			return response.Error(c, fiber.StatusUnauthorized, err.Error())
+		}
+		c.Locals("permissions", perms)
 
 		return c.Next()
 	}
 }
+
+// authenticateAPIToken binds the request to the owner of an API token. The
+// request is scoped to the token's company alone, and gets the permissions
+// that both the token and its owner still have. The token's ID is stored in
+// Locals("apiTokenID"). A token acts through its permissions alone: the
+// request carries an empty role, so role-gated routes refuse it and
+// role-based branches never treat it as its owner, e.g. a superadmin.
+func authenticateAPIToken(c *fiber.Ctx, rawToken string, scopeService service.CompanyScopeService, permService service.PermissionService, apiTokenService service.APITokenService) error {
+	identity, err := apiTokenService.Authenticate(c.UserContext(), rawToken, c.IP())
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, "Invalid or expired token")
+	}
+
+	ownerScope, err := scopeService.Resolve(c.UserContext(), identity.UserID, identity.Role)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+	if !ownerScope.Allows(identity.CompanyID) {
+		return response.Error(c, fiber.StatusUnauthorized, "Token owner no longer has access to the token's company")
+	}
+
+	c.Locals("userID", identity.UserID)
+	c.Locals("email", identity.Email)
+	c.Locals("role", "")
+	c.Locals("apiTokenID", identity.TokenID)
+
+	scope := &tenant.Scope{UserID: identity.UserID, CompanyIDs: []string{identity.CompanyID}}
+	c.Locals("companyIDs", scope.CompanyIDs)
+	c.Locals("companyID", identity.CompanyID)
+	c.SetUserContext(tenant.WithScope(c.UserContext(), scope))
+
+	perms, err := permService.Resolve(c.UserContext(), identity.UserID, identity.Role)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, err.Error())
+	}
+	c.Locals("permissions", perms.Intersect(identity.Permissions))
+
+	return c.Next()
+}
@@ -14,7 +14,8 @@ import (
 // Core modules are always enabled — this middleware should only be used to gate
 // opt-in modules (visit_tracking, distributor_sync, reimbursement, etc.).
 //
-// The caller's company is derived from their employee record. The result is
+// The caller's company is the primary company AuthMiddleware resolved into
+// Locals("companyID"), falling back to their employee record. The result is
 // cached in Locals("companyID") so repeated checks within a request only hit
 // the DB once.
 func RequireModule(moduleKey string, modService service.ModuleService, empService service.EmployeeService) fiber.Handler {
@@ -32,7 +33,7 @@ func RequireModule(moduleKey string, modService service.ModuleService, empServic
 		// Resolve company_id (cached for the request)
 		companyID, _ := c.Locals("companyID").(string)
 		if companyID == "" {
-			emp, err := empService.GetByUserID(userID)
+			emp, err := empService.GetByUserID(c.UserContext(), userID)
 			if err != nil || emp == nil || emp.CompanyID == "" {
 				return response.Error(c, fiber.StatusForbidden, "No company context for user")
 			}
@@ -40,7 +41,7 @@ func RequireModule(moduleKey string, modService service.ModuleService, empServic
 			c.Locals("companyID", companyID)
 		}
 
-		enabled, err := modService.IsEnabled(companyID, moduleKey)
+		enabled, err := modService.IsEnabled(c.UserContext(), companyID, moduleKey)
 		if err != nil {
 			return response.Error(c, fiber.StatusInternalServerError, err.Error())
 		}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"hris-backend/internal/permissions"
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequirePermission returns a Fiber middleware that 403s the request unless
+// the caller has at least one of the given permissions. It relies on the
+// permissions AuthMiddleware resolved into Locals("permissions"); superadmins
+// hold every permission.
+func RequirePermission(names ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !HasPermission(c, names...) {
+			return response.Error(c, fiber.StatusForbidden, "Insufficient permissions")
+		}
+		return c.Next()
+	}
+}
+
+// HasPermission reports whether the caller has at least one of the given
+// permissions. Handlers use it for checks that depend on the request body.
+func HasPermission(c *fiber.Ctx, names ...string) bool {
+	perms, _ := c.Locals("permissions").(permissions.Set)
+	return perms.Has(names...)
+}
+
+// CurrentActor returns the caller's role and permissions, for services that
+// must not let the caller grant more than they hold
+func CurrentActor(c *fiber.Ctx) service.Actor {
+	role, _ := c.Locals("role").(string)
+	perms, _ := c.Locals("permissions").(permissions.Set)
+	return service.Actor{Role: role, Permissions: perms}
+}
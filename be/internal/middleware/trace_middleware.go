@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"hris-backend/pkg/trace"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Trace returns a Fiber middleware that continues the trace of the caller's
+// traceparent header, or starts one, and puts it in the request context.
+// Events the request causes carry it to Kafka, so their processing can be
+// tied back to the request.
+func Trace() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tc, ok := trace.Parse(c.Get("traceparent"))
+		if ok {
+			tc = tc.Child()
+		} else {
+			tc = trace.New()
+		}
+		c.SetUserContext(trace.WithContext(c.UserContext(), tc))
+		c.Set("traceparent", tc.String())
+		return c.Next()
+	}
+}
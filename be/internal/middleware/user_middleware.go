@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"hris-backend/internal/service"
+	"hris-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireManageableUser returns a Fiber middleware that 403s the request
+// unless the caller may change the user in the :id route parameter. Scoped
+// callers cannot change superadmins or users outside their companies, such
+// as the ones that belong to no company yet.
+func RequireManageableUser(userService service.UserService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := userService.CheckManageable(c.UserContext(), c.Params("id")); err != nil {
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AnnouncementStatus string
+
+const (
+	AnnouncementDraft     AnnouncementStatus = "draft"
+	AnnouncementPublished AnnouncementStatus = "published"
+)
+
+// Announcement is a message HR posts to the employees of a company. Without
+// targets it is for the whole company; otherwise it is for the employees of
+// the target departments and job levels. A published announcement is shown
+// from PublishAt until ExpireAt, pinned ones first. NotifiedAt is set once
+// its audience was notified.
+type Announcement struct {
+	ID         string             `gorm:"type:uuid;primaryKey" json:"id"`
+	CompanyID  string             `gorm:"type:uuid;not null;index" json:"company_id"`
+	Title      string             `gorm:"type:varchar(255);not null" json:"title"`
+	Body       string             `gorm:"type:text;not null" json:"body"` // sanitized HTML
+	Status     AnnouncementStatus `gorm:"type:varchar(20);not null;default:'draft'" json:"status"`
+	IsPinned   bool               `gorm:"default:false" json:"is_pinned"`
+	PublishAt  *time.Time         `gorm:"index" json:"publish_at,omitempty"`
+	ExpireAt   *time.Time         `json:"expire_at,omitempty"`
+	NotifiedAt *time.Time         `json:"notified_at,omitempty"`
+	CreatedBy  string             `gorm:"type:uuid" json:"created_by"` // user_id of author
+
+	Targets     []AnnouncementTarget     `gorm:"foreignKey:AnnouncementID" json:"targets,omitempty"`
+	Attachments []AnnouncementAttachment `gorm:"foreignKey:AnnouncementID" json:"attachments,omitempty"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (a *Announcement) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// TargetIDs returns the IDs of the targets of an announcement of one type
+func (a *Announcement) TargetIDs(targetType AnnouncementTargetType) []string {
+	var ids []string
+	for _, t := range a.Targets {
+		if t.TargetType == targetType {
+			ids = append(ids, t.TargetID)
+		}
+	}
+	return ids
+}
+
+type AnnouncementTargetType string
+
+const (
+	AnnouncementTargetDepartment AnnouncementTargetType = "department"
+	AnnouncementTargetJobLevel   AnnouncementTargetType = "job_level"
+)
+
+// AnnouncementTarget is a department or job level an announcement is for
+type AnnouncementTarget struct {
+	ID             string                 `gorm:"type:uuid;primaryKey" json:"id"`
+	AnnouncementID string                 `gorm:"type:uuid;not null;index" json:"announcement_id"`
+	TargetType     AnnouncementTargetType `gorm:"type:varchar(20);not null" json:"target_type"`
+	TargetID       string                 `gorm:"type:uuid;not null" json:"target_id"`
+}
+
+func (t *AnnouncementTarget) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// AnnouncementAttachment is a file attached to an announcement, stored
+// elsewhere and linked by URL
+type AnnouncementAttachment struct {
+	ID             string `gorm:"type:uuid;primaryKey" json:"id"`
+	AnnouncementID string `gorm:"type:uuid;not null;index" json:"announcement_id"`
+	Name           string `gorm:"type:varchar(255);not null" json:"name"`
+	URL            string `gorm:"type:varchar(1000);not null" json:"url"`
+}
+
+func (a *AnnouncementAttachment) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// AnnouncementRead is the receipt of a user having read an announcement
+type AnnouncementRead struct {
+	ID             string    `gorm:"type:uuid;primaryKey" json:"id"`
+	AnnouncementID string    `gorm:"type:uuid;not null;uniqueIndex:idx_announcement_reads_announcement_user" json:"announcement_id"`
+	UserID         string    `gorm:"type:uuid;not null;uniqueIndex:idx_announcement_reads_announcement_user" json:"user_id"`
+	ReadAt         time.Time `gorm:"not null" json:"read_at"`
+}
+
+func (r *AnnouncementRead) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
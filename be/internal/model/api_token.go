@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIToken is a long-lived bearer token for scripts and integrations, owned
+// by a person or a service account. It acts for its owner within one company
+// and with at most the listed permissions. Only the hash of the token is
+// stored; Prefix is kept to recognise it in lists.
+type APIToken struct {
+	ID          string     `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID      string     `gorm:"type:uuid;not null;index" json:"user_id"`
+	User        User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	CompanyID   string     `gorm:"type:uuid;not null;index" json:"company_id"`
+	Company     Company    `gorm:"foreignKey:CompanyID" json:"company,omitempty"`
+	Name        string     `gorm:"type:varchar(100);not null" json:"name"`
+	Prefix      string     `gorm:"type:varchar(20);not null" json:"prefix"`
+	TokenHash   string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	Permissions []string   `gorm:"type:jsonb;serializer:json;not null" json:"permissions"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP  string     `gorm:"type:varchar(45)" json:"last_used_ip,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (t *APIToken) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// IsActive reports whether the token is still accepted
+func (t *APIToken) IsActive() bool {
+	return t.RevokedAt == nil && time.Now().Before(t.ExpiresAt)
+}
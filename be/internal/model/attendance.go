@@ -45,6 +45,12 @@ type Attendance struct {
 	ClockOutPhoto         string   `gorm:"type:varchar(500)" json:"clock_out_photo,omitempty"`
 	ClockOutDistanceM     *float64 `gorm:"type:decimal(10,2)" json:"clock_out_distance_m,omitempty"`
 
+	// Set on rows written by an approved leave. StatusBeforeLeave holds the
+	// status of a row the leave replaced, so cancelling the leave can restore
+	// it; it is empty when the leave created the row.
+	LeaveID           *string          `gorm:"type:uuid;index" json:"leave_id,omitempty"`
+	StatusBeforeLeave AttendanceStatus `gorm:"type:varchar(20)" json:"status_before_leave,omitempty"`
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CustomRole is a company-defined set of permissions. Users assigned a custom
+// role get exactly its permissions instead of their built-in role's.
+type CustomRole struct {
+	ID          string         `gorm:"type:uuid;primaryKey" json:"id"`
+	CompanyID   string         `gorm:"type:uuid;not null;index:idx_custom_role_company_name,unique" json:"company_id"`
+	Company     Company        `gorm:"foreignKey:CompanyID" json:"company,omitempty"`
+	Name        string         `gorm:"type:varchar(100);not null;index:idx_custom_role_company_name,unique" json:"name"`
+	Description string         `gorm:"type:varchar(255)" json:"description"`
+	Permissions []Permission   `gorm:"many2many:custom_role_permissions" json:"permissions,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (r *CustomRole) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
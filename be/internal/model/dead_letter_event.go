@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DeadLetterStatus string
+
+const (
+	DeadLetterPending  DeadLetterStatus = "pending"
+	DeadLetterReplayed DeadLetterStatus = "replayed"
+)
+
+// DeadLetterEvent is a Kafka message the consumer gave up on after retrying
+// it, recorded from the dead-letter topic for admins to inspect and replay
+// once the cause is fixed. SourceTopic, SourcePartition and SourceOffset
+// locate the original message, which is recorded once however often its
+// dead letter is delivered. Key, Headers and Message are the original
+// message's; Error is the handler error of the last attempt.
+type DeadLetterEvent struct {
+	ID              string           `gorm:"type:uuid;primaryKey" json:"id"`
+	SourceTopic     string           `gorm:"type:varchar(255);not null;uniqueIndex:idx_dead_letter_source" json:"source_topic"`
+	SourcePartition int32            `gorm:"not null;uniqueIndex:idx_dead_letter_source" json:"source_partition"`
+	SourceOffset    int64            `gorm:"not null;uniqueIndex:idx_dead_letter_source" json:"source_offset"`
+	EventID         string           `gorm:"type:varchar(100);index" json:"event_id,omitempty"`
+	EventType       string           `gorm:"type:varchar(100);index" json:"event_type,omitempty"`
+	CompanyID       *string          `gorm:"type:uuid;index" json:"company_id,omitempty"`
+	Key             string           `gorm:"type:varchar(255)" json:"key,omitempty"`
+	Headers         string           `gorm:"type:jsonb;not null;default:'{}'" json:"headers"`
+	Message         string           `gorm:"type:text;not null" json:"message"`
+	Error           string           `gorm:"type:text" json:"error"`
+	Attempts        int              `gorm:"not null;default:0" json:"attempts"`
+	FailedAt        time.Time        `gorm:"not null" json:"failed_at"`
+	Status          DeadLetterStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	ReplayedAt      *time.Time       `json:"replayed_at,omitempty"`
+	ReplayedBy      *string          `gorm:"type:uuid" json:"replayed_by,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+func (e *DeadLetterEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	return nil
+}
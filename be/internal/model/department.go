@@ -13,6 +13,7 @@ type Department struct {
 	Company     Company        `gorm:"foreignKey:CompanyID" json:"company,omitempty"`
 	Name        string         `gorm:"type:varchar(255);not null" json:"name"`
 	Description string         `gorm:"type:text" json:"description"`
+	HeadID      *string        `gorm:"type:uuid" json:"head_id"` // department head (employee ID)
 	IsActive    bool           `gorm:"default:true" json:"is_active"`
 	Positions   []Position     `gorm:"foreignKey:DepartmentID" json:"positions,omitempty"`
 	CreatedAt   time.Time      `json:"created_at"`
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type EmailDeliveryStatus string
+
+const (
+	EmailDeliveryPending EmailDeliveryStatus = "pending"
+	EmailDeliverySent    EmailDeliveryStatus = "sent"
+	EmailDeliveryFailed  EmailDeliveryStatus = "failed"
+)
+
+// EmailDelivery is a notification email, rendered when the notification is
+// created and sent afterwards by the email dispatcher with retries. It stays
+// as the delivery log. EventID and UserID identify the email of one event to
+// one user, so a redelivered event does not email anyone twice. CompanyID is
+// the company of the event, if any.
+type EmailDelivery struct {
+	ID             string              `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID        *string             `gorm:"type:varchar(100);uniqueIndex:idx_email_deliveries_event_user" json:"event_id,omitempty"`
+	EventType      string              `gorm:"type:varchar(100);not null;index" json:"event_type"`
+	CompanyID      *string             `gorm:"type:uuid;index" json:"company_id,omitempty"`
+	UserID         string              `gorm:"type:uuid;not null;uniqueIndex:idx_email_deliveries_event_user" json:"user_id"`
+	NotificationID *string             `gorm:"type:uuid" json:"notification_id,omitempty"`
+	ToAddress      string              `gorm:"type:varchar(255);not null" json:"to_address"`
+	Language       string              `gorm:"type:varchar(5);not null" json:"language"`
+	Subject        string              `gorm:"type:varchar(255);not null" json:"subject"`
+	TextBody       string              `gorm:"type:text;not null" json:"-"`
+	HTMLBody       string              `gorm:"type:text;not null" json:"-"`
+	Status         EmailDeliveryStatus `gorm:"type:varchar(20);not null;default:'pending';index:idx_email_deliveries_due" json:"status"`
+	Attempts       int                 `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time           `gorm:"not null;index:idx_email_deliveries_due" json:"next_attempt_at"`
+	LastError      string              `gorm:"type:text" json:"last_error,omitempty"`
+	SentAt         *time.Time          `json:"sent_at,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+func (d *EmailDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	if d.NextAttemptAt.IsZero() {
+		d.NextAttemptAt = time.Now()
+	}
+	return nil
+}
@@ -34,12 +34,14 @@ type Employee struct {
 	JobLevel          *JobLevel      `gorm:"foreignKey:JobLevelID" json:"job_level,omitempty"`
 	GradeID           *string        `gorm:"type:uuid" json:"grade_id"`
 	Grade             *Grade         `gorm:"foreignKey:GradeID" json:"grade,omitempty"`
+	SupervisorID      *string        `gorm:"type:uuid;index" json:"supervisor_id"` // direct supervisor (employee ID)
 	EmployeeNumber    string         `gorm:"type:varchar(50);uniqueIndex;not null" json:"employee_number"`
 	NIK               string         `gorm:"type:varchar(16)" json:"nik"`
 	Gender            string         `gorm:"type:varchar(10)" json:"gender"`
 	BirthPlace        string         `gorm:"type:varchar(100)" json:"birth_place"`
 	BirthDate         *time.Time     `gorm:"type:date" json:"birth_date"`
 	MaritalStatus     string         `gorm:"type:varchar(20)" json:"marital_status"`
+	Dependents        int            `gorm:"default:0" json:"dependents"`
 	Religion          string         `gorm:"type:varchar(20)" json:"religion"`
 	BloodType         string         `gorm:"type:varchar(5)" json:"blood_type"`
 	LastEducation     string         `gorm:"type:varchar(50)" json:"last_education"`
@@ -21,29 +21,37 @@ const (
 type LeaveStatus string
 
 const (
-	LeaveStatusPending  LeaveStatus = "pending"
-	LeaveStatusApproved LeaveStatus = "approved"
-	LeaveStatusRejected LeaveStatus = "rejected"
+	LeaveStatusPending   LeaveStatus = "pending"
+	LeaveStatusInReview  LeaveStatus = "in_review" // approved by some, but not all, steps of the chain
+	LeaveStatusApproved  LeaveStatus = "approved"
+	LeaveStatusRejected  LeaveStatus = "rejected"
+	LeaveStatusCancelled LeaveStatus = "cancelled"
 )
 
 type Leave struct {
-	ID              string         `gorm:"type:uuid;primaryKey" json:"id"`
-	EmployeeID      string         `gorm:"type:uuid;not null" json:"employee_id"`
-	Employee        Employee       `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
-	LeaveType       LeaveType      `gorm:"type:varchar(30);not null" json:"leave_type"`
-	StartDate       time.Time      `gorm:"type:date;not null" json:"start_date"`
-	EndDate         time.Time      `gorm:"type:date;not null" json:"end_date"`
-	TotalDays       int            `gorm:"not null" json:"total_days"`
-	Reason          string         `gorm:"type:text;not null" json:"reason"`
-	Attachment      string         `gorm:"type:varchar(255)" json:"attachment"`
-	Status          LeaveStatus    `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
-	ApprovedBy      *string        `gorm:"type:uuid" json:"approved_by"`
-	Approver        *User          `gorm:"foreignKey:ApprovedBy" json:"approver,omitempty"`
-	ApprovedAt      *time.Time     `gorm:"type:timestamp" json:"approved_at"`
-	RejectionReason string         `gorm:"type:text" json:"rejection_reason"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
-	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              string          `gorm:"type:uuid;primaryKey" json:"id"`
+	EmployeeID      string          `gorm:"type:uuid;not null" json:"employee_id"`
+	Employee        Employee        `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
+	LeaveType       LeaveType       `gorm:"type:varchar(30);not null" json:"leave_type"`
+	StartDate       time.Time       `gorm:"type:date;not null" json:"start_date"`
+	EndDate         time.Time       `gorm:"type:date;not null" json:"end_date"`
+	StartHalfDay    bool            `gorm:"not null;default:false" json:"start_half_day"`
+	EndHalfDay      bool            `gorm:"not null;default:false" json:"end_half_day"`
+	TotalDays       float64         `gorm:"type:decimal(5,1);not null" json:"total_days"` // working days charged, computed by the server
+	Reason          string          `gorm:"type:text;not null" json:"reason"`
+	Attachment      string          `gorm:"type:varchar(255)" json:"attachment"`
+	Status          LeaveStatus     `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	ApprovedBy      *string         `gorm:"type:uuid" json:"approved_by"`
+	Approver        *User           `gorm:"foreignKey:ApprovedBy" json:"approver,omitempty"`
+	ApprovedAt      *time.Time      `gorm:"type:timestamp" json:"approved_at"`
+	RejectionReason string          `gorm:"type:text" json:"rejection_reason"`
+	CancelledBy     *string         `gorm:"type:uuid" json:"cancelled_by"`
+	CancelledAt     *time.Time      `gorm:"type:timestamp" json:"cancelled_at"`
+	CancelReason    string          `gorm:"type:text" json:"cancel_reason"`
+	Approvals       []LeaveApproval `gorm:"foreignKey:LeaveID" json:"approvals,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt  `gorm:"index" json:"-"`
 }
 
 func (l *Leave) BeforeCreate(tx *gorm.DB) error {
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LeaveApproverType string
+
+const (
+	// LeaveApproverSupervisor is the requester's direct supervisor.
+	LeaveApproverSupervisor LeaveApproverType = "supervisor"
+	// LeaveApproverDepartmentHead is the head of the requester's department.
+	LeaveApproverDepartmentHead LeaveApproverType = "department_head"
+	// LeaveApproverRole is any user holding ApproverRole. Admins may also
+	// decide role steps.
+	LeaveApproverRole LeaveApproverType = "role"
+	// LeaveApproverUser is one named user.
+	LeaveApproverUser LeaveApproverType = "user"
+)
+
+// LeaveWorkflow is the ordered list of approval steps a leave request goes
+// through. A workflow without DepartmentID is the company default; a
+// department workflow takes precedence over it.
+type LeaveWorkflow struct {
+	ID           string              `gorm:"type:uuid;primaryKey" json:"id"`
+	CompanyID    string              `gorm:"type:uuid;not null;index" json:"company_id"`
+	Company      Company             `gorm:"foreignKey:CompanyID" json:"company,omitempty"`
+	DepartmentID *string             `gorm:"type:uuid;index" json:"department_id"`
+	Department   *Department         `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
+	Name         string              `gorm:"type:varchar(100);not null" json:"name"`
+	IsActive     bool                `gorm:"not null;default:true" json:"is_active"`
+	Steps        []LeaveWorkflowStep `gorm:"foreignKey:WorkflowID" json:"steps,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt      `gorm:"index" json:"-"`
+}
+
+func (w *LeaveWorkflow) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == "" {
+		w.ID = uuid.New().String()
+	}
+	return nil
+}
+
+type LeaveWorkflowStep struct {
+	ID             string            `gorm:"type:uuid;primaryKey" json:"id"`
+	WorkflowID     string            `gorm:"type:uuid;not null;index" json:"workflow_id"`
+	StepOrder      int               `gorm:"not null" json:"step_order"`
+	ApproverType   LeaveApproverType `gorm:"type:varchar(20);not null" json:"approver_type"`
+	ApproverRole   Role              `gorm:"type:varchar(20)" json:"approver_role"`
+	ApproverUserID *string           `gorm:"type:uuid" json:"approver_user_id"`
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
+func (s *LeaveWorkflowStep) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
+
+type LeaveApprovalStatus string
+
+const (
+	LeaveApprovalPending  LeaveApprovalStatus = "pending"
+	LeaveApprovalApproved LeaveApprovalStatus = "approved"
+	LeaveApprovalRejected LeaveApprovalStatus = "rejected"
+	LeaveApprovalSkipped  LeaveApprovalStatus = "skipped"
+)
+
+// LeaveApproval is one step of a leave request's approval chain. The chain is
+// resolved from the workflow when the leave is submitted, so later changes to
+// the workflow, supervisors or department heads do not affect it.
+type LeaveApproval struct {
+	ID             string              `gorm:"type:uuid;primaryKey" json:"id"`
+	LeaveID        string              `gorm:"type:uuid;not null;index" json:"leave_id"`
+	StepOrder      int                 `gorm:"not null" json:"step_order"`
+	ApproverType   LeaveApproverType   `gorm:"type:varchar(20);not null" json:"approver_type"`
+	ApproverRole   Role                `gorm:"type:varchar(20)" json:"approver_role"`
+	ApproverUserID *string             `gorm:"type:uuid;index" json:"approver_user_id"`
+	Status         LeaveApprovalStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	DecidedBy      *string             `gorm:"type:uuid" json:"decided_by"`
+	Decider        *User               `gorm:"foreignKey:DecidedBy" json:"decider,omitempty"`
+	DelegatedFrom  *string             `gorm:"type:uuid" json:"delegated_from"` // approver the decider stood in for
+	Comment        string              `gorm:"type:text" json:"comment"`
+	DecidedAt      *time.Time          `gorm:"type:timestamp" json:"decided_at"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+func (a *LeaveApproval) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// LeaveDelegation lets DelegateID decide the approval steps assigned to
+// DelegatorID between StartDate and EndDate, e.g. while the delegator is on
+// leave.
+type LeaveDelegation struct {
+	ID          string         `gorm:"type:uuid;primaryKey" json:"id"`
+	DelegatorID string         `gorm:"type:uuid;not null;index" json:"delegator_id"`
+	Delegator   User           `gorm:"foreignKey:DelegatorID" json:"delegator,omitempty"`
+	DelegateID  string         `gorm:"type:uuid;not null;index" json:"delegate_id"`
+	Delegate    User           `gorm:"foreignKey:DelegateID" json:"delegate,omitempty"`
+	StartDate   time.Time      `gorm:"type:date;not null" json:"start_date"`
+	EndDate     time.Time      `gorm:"type:date;not null" json:"end_date"`
+	Reason      string         `gorm:"type:text" json:"reason"`
+	CreatedBy   string         `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (d *LeaveDelegation) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LeaveAccrualMethod string
+
+const (
+	// LeaveAccrualAnnual grants the whole yearly entitlement at once.
+	LeaveAccrualAnnual LeaveAccrualMethod = "annual"
+	// LeaveAccrualMonthly grants 1/12 of the yearly entitlement each month.
+	LeaveAccrualMonthly LeaveAccrualMethod = "monthly"
+)
+
+// LeavePolicy defines how a company grants one LeaveType. Leave types without
+// a policy are not balance-tracked.
+type LeavePolicy struct {
+	ID                    string             `gorm:"type:uuid;primaryKey" json:"id"`
+	CompanyID             string             `gorm:"type:uuid;not null;index:idx_leave_policy_company_type,unique" json:"company_id"`
+	Company               Company            `gorm:"foreignKey:CompanyID" json:"company,omitempty"`
+	LeaveType             LeaveType          `gorm:"type:varchar(30);not null;index:idx_leave_policy_company_type,unique" json:"leave_type"`
+	AnnualDays            float64            `gorm:"type:decimal(5,2);not null;default:0" json:"annual_days"`
+	AccrualMethod         LeaveAccrualMethod `gorm:"type:varchar(20);not null;default:'annual'" json:"accrual_method"`
+	MaxCarryOverDays      float64            `gorm:"type:decimal(5,2);not null;default:0" json:"max_carry_over_days"`
+	CarryOverExpiryMonths int                `gorm:"not null;default:0" json:"carry_over_expiry_months"` // 0 = carried-over days never expire
+	RequiresBalance       bool               `gorm:"not null;default:true" json:"requires_balance"`
+	IsActive              bool               `gorm:"not null;default:true" json:"is_active"`
+	CreatedAt             time.Time          `json:"created_at"`
+	UpdatedAt             time.Time          `json:"updated_at"`
+	DeletedAt             gorm.DeletedAt     `gorm:"index" json:"-"`
+}
+
+func (p *LeavePolicy) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// LeaveEntitlement overrides the policy's AnnualDays for one employee, leave
+// type and year (e.g. extra days by seniority or a pro-rated first year).
+type LeaveEntitlement struct {
+	ID         string    `gorm:"type:uuid;primaryKey" json:"id"`
+	EmployeeID string    `gorm:"type:uuid;not null;index:idx_leave_entitlement,unique" json:"employee_id"`
+	Employee   Employee  `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
+	LeaveType  LeaveType `gorm:"type:varchar(30);not null;index:idx_leave_entitlement,unique" json:"leave_type"`
+	Year       int       `gorm:"not null;index:idx_leave_entitlement,unique" json:"year"`
+	Days       float64   `gorm:"type:decimal(5,2);not null" json:"days"`
+	Notes      string    `gorm:"type:text" json:"notes"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (e *LeaveEntitlement) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	return nil
+}
+
+type LeaveLedgerEntryType string
+
+const (
+	LeaveLedgerAccrual    LeaveLedgerEntryType = "accrual"
+	LeaveLedgerCarryOver  LeaveLedgerEntryType = "carry_over"
+	LeaveLedgerExpiry     LeaveLedgerEntryType = "expiry"
+	LeaveLedgerTaken      LeaveLedgerEntryType = "taken"
+	LeaveLedgerAdjustment LeaveLedgerEntryType = "adjustment"
+	LeaveLedgerReversal   LeaveLedgerEntryType = "reversal"
+)
+
+// LeaveLedgerEntry is one signed movement of an employee's leave balance.
+// Entries are append-only; the balance for a leave type and year is the sum
+// of its entries. Reference makes system-generated entries (accruals,
+// carry-overs, expiries) idempotent: an employee has at most one entry of a
+// leave type, year and entry type with a given reference.
+type LeaveLedgerEntry struct {
+	ID          string               `gorm:"type:uuid;primaryKey" json:"id"`
+	EmployeeID  string               `gorm:"type:uuid;not null;index:idx_leave_ledger_balance;uniqueIndex:idx_leave_ledger_reference,where:reference <> ''" json:"employee_id"`
+	LeaveType   LeaveType            `gorm:"type:varchar(30);not null;index:idx_leave_ledger_balance;uniqueIndex:idx_leave_ledger_reference" json:"leave_type"`
+	Year        int                  `gorm:"not null;index:idx_leave_ledger_balance;uniqueIndex:idx_leave_ledger_reference" json:"year"`
+	EntryType   LeaveLedgerEntryType `gorm:"type:varchar(20);not null;uniqueIndex:idx_leave_ledger_reference" json:"entry_type"`
+	Days        float64              `gorm:"type:decimal(6,2);not null" json:"days"`
+	LeaveID     *string              `gorm:"type:uuid;index" json:"leave_id"`
+	Reference   string               `gorm:"type:varchar(50);index;uniqueIndex:idx_leave_ledger_reference" json:"reference"`
+	ExpiresAt   *time.Time           `gorm:"type:date" json:"expires_at"`
+	Description string               `gorm:"type:text" json:"description"`
+	CreatedBy   *string              `gorm:"type:uuid" json:"created_by"`
+	CreatedAt   time.Time            `json:"created_at"`
+}
+
+func (e *LeaveLedgerEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LoginResult string
+
+const (
+	LoginSuccess          LoginResult = "success"
+	LoginChallengeIssued  LoginResult = "2fa_challenge"
+	LoginInvalidPassword  LoginResult = "invalid_password"
+	LoginUnknownUser      LoginResult = "unknown_user"
+	LoginInvalidTwoFactor LoginResult = "invalid_2fa"
+	LoginDeactivated      LoginResult = "deactivated"
+	LoginLocked           LoginResult = "locked"
+	LoginThrottled        LoginResult = "throttled"
+)
+
+// LoginFailureResults are the results that count as guesses towards account
+// lockout and IP blocking
+var LoginFailureResults = []LoginResult{LoginInvalidPassword, LoginUnknownUser, LoginInvalidTwoFactor}
+
+// LoginAttempt is an entry of the login audit log. CompanyID is the company
+// of the account at the time of the attempt, so admins and HR only see
+// attempts on accounts of their companies; attempts on unknown emails have
+// none and are visible to superadmins only.
+type LoginAttempt struct {
+	ID        string      `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    *string     `gorm:"type:uuid;index" json:"user_id"`
+	User      *User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	CompanyID *string     `gorm:"type:uuid;index" json:"company_id"`
+	Email     string      `gorm:"type:varchar(255);not null" json:"email"`
+	IPAddress string      `gorm:"type:varchar(45);index:idx_login_attempt_ip" json:"ip_address"`
+	UserAgent string      `gorm:"type:varchar(500)" json:"user_agent"`
+	Result    LoginResult `gorm:"type:varchar(20);not null" json:"result"`
+	CreatedAt time.Time   `gorm:"index:idx_login_attempt_ip" json:"created_at"`
+}
+
+func (a *LoginAttempt) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -17,20 +17,26 @@ const (
 	NotificationTypeError   NotificationType = "error"
 )
 
-// Notification represents a persisted notification for a specific user
+// Notification represents a persisted notification for a specific user.
+// EventID is the Kafka event it was created from; a user gets at most one
+// notification per event, however often the event is delivered. RefID is
+// the record of RefType it refers to, if any. Archived notifications are kept
+// out of the inbox and the unread count.
 type Notification struct {
-	ID        string           `gorm:"type:uuid;primaryKey" json:"id"`
-	UserID    string           `gorm:"type:uuid;not null;index" json:"user_id"`
-	Title     string           `gorm:"type:varchar(255);not null" json:"title"`
-	Message   string           `gorm:"type:text;not null" json:"message"`
-	Type      NotificationType `gorm:"type:varchar(20);not null;default:'info'" json:"type"`
-	RefID     string           `gorm:"type:uuid" json:"ref_id,omitempty"`
-	RefType   string           `gorm:"type:varchar(50)" json:"ref_type,omitempty"`
-	IsRead    bool             `gorm:"default:false" json:"is_read"`
-	ReadAt    *time.Time       `json:"read_at,omitempty"`
-	CreatedAt time.Time        `json:"created_at"`
-	UpdatedAt time.Time        `json:"updated_at"`
-	DeletedAt gorm.DeletedAt   `gorm:"index" json:"-"`
+	ID         string           `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID     string           `gorm:"type:uuid;not null;index;uniqueIndex:idx_notifications_event_user" json:"user_id"`
+	EventID    *string          `gorm:"type:uuid;uniqueIndex:idx_notifications_event_user" json:"-"`
+	Title      string           `gorm:"type:varchar(255);not null" json:"title"`
+	Message    string           `gorm:"type:text;not null" json:"message"`
+	Type       NotificationType `gorm:"type:varchar(20);not null;default:'info'" json:"type"`
+	RefID      *string          `gorm:"type:uuid" json:"ref_id,omitempty"`
+	RefType    string           `gorm:"type:varchar(50)" json:"ref_type,omitempty"`
+	IsRead     bool             `gorm:"default:false" json:"is_read"`
+	ReadAt     *time.Time       `json:"read_at,omitempty"`
+	ArchivedAt *time.Time       `gorm:"index" json:"archived_at,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt   `gorm:"index" json:"-"`
 }
 
 func (n *Notification) BeforeCreate(tx *gorm.DB) error {
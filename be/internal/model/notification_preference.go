@@ -0,0 +1,67 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationMode is how a user is notified of the events of one type
+type NotificationMode string
+
+const (
+	// NotificationModeInApp notifies in the application only
+	NotificationModeInApp NotificationMode = "in_app"
+	// NotificationModeEmail notifies in the application and by email, when
+	// the event type has an email. It is the default.
+	NotificationModeEmail NotificationMode = "email"
+	// NotificationModeDigest collects the notifications into one summary a
+	// day
+	NotificationModeDigest NotificationMode = "digest"
+	// NotificationModeOff does not notify at all
+	NotificationModeOff NotificationMode = "off"
+)
+
+// NotificationPreference is how a user wants to be notified of one event
+// type. Event types without a preference use NotificationModeEmail.
+type NotificationPreference struct {
+	ID        string           `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    string           `gorm:"type:uuid;not null;uniqueIndex:idx_notification_preferences_user_event" json:"user_id"`
+	EventType string           `gorm:"type:varchar(100);not null;uniqueIndex:idx_notification_preferences_user_event" json:"event_type"`
+	Mode      NotificationMode `gorm:"type:varchar(20);not null" json:"mode"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+func (p *NotificationPreference) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// NotificationDigestItem is a notification held back for the daily digest
+// of a user who chose NotificationModeDigest for its event type. DigestedAt
+// is set once the item went out in a digest. Like notifications, a user gets
+// at most one item per event.
+type NotificationDigestItem struct {
+	ID         string           `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID     string           `gorm:"type:uuid;not null;index:idx_notification_digest_items_pending;uniqueIndex:idx_notification_digest_items_event_user" json:"user_id"`
+	EventID    *string          `gorm:"type:uuid;uniqueIndex:idx_notification_digest_items_event_user" json:"event_id,omitempty"`
+	EventType  string           `gorm:"type:varchar(100);not null" json:"event_type"`
+	Title      string           `gorm:"type:varchar(255);not null" json:"title"`
+	Message    string           `gorm:"type:text;not null" json:"message"`
+	Type       NotificationType `gorm:"type:varchar(20);not null;default:'info'" json:"type"`
+	RefID      *string          `gorm:"type:uuid" json:"ref_id,omitempty"`
+	RefType    string           `gorm:"type:varchar(50)" json:"ref_type,omitempty"`
+	DigestedAt *time.Time       `gorm:"index:idx_notification_digest_items_pending" json:"digested_at,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+func (i *NotificationDigestItem) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxPending OutboxStatus = "pending"
+	OutboxSent    OutboxStatus = "sent"
+	OutboxFailed  OutboxStatus = "failed"
+)
+
+// OutboxEvent is a Kafka message waiting to be published. It is written in
+// the same transaction as the change it reports, so the event exists exactly
+// when the change does, and the outbox relay publishes it afterwards with
+// retries. The ID is the event ID of the message, except for replays of
+// dead letters, which keep the event ID of the original. Key orders the
+// events of one subject, e.g. an employee, on a partition, and Headers is a
+// JSON object of the message headers. Events that keep failing are marked
+// failed and kept for inspection; failing is final, so the later events of
+// their key go ahead without them.
+type OutboxEvent struct {
+	ID            string       `gorm:"type:uuid;primaryKey" json:"id"`
+	Topic         string       `gorm:"type:varchar(255);not null" json:"topic"`
+	EventType     string       `gorm:"type:varchar(100);not null" json:"event_type"`
+	CompanyID     *string      `gorm:"type:uuid;index" json:"company_id,omitempty"`
+	Key           string       `gorm:"type:varchar(255)" json:"key,omitempty"`
+	Headers       string       `gorm:"type:jsonb;not null;default:'{}'" json:"headers"`
+	Message       string       `gorm:"type:jsonb;not null" json:"message"`
+	Status        OutboxStatus `gorm:"type:varchar(20);not null;default:'pending';index:idx_outbox_due" json:"status"`
+	Attempts      int          `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time    `gorm:"not null;index:idx_outbox_due" json:"next_attempt_at"`
+	LastError     string       `gorm:"type:text" json:"last_error,omitempty"`
+	SentAt        *time.Time   `json:"sent_at,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+func (e *OutboxEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	if e.NextAttemptAt.IsZero() {
+		e.NextAttemptAt = time.Now()
+	}
+	return nil
+}
@@ -15,12 +15,15 @@ const (
 	PayrollPaid      PayrollStatus = "paid"
 )
 
+// Payroll is the pay of one employee for one period. An employee has at most
+// one payroll per period; deleted payrolls do not count.
 type Payroll struct {
 	ID               string         `gorm:"type:uuid;primaryKey" json:"id"`
-	EmployeeID       string         `gorm:"type:uuid;not null" json:"employee_id"`
+	EmployeeID       string         `gorm:"type:uuid;not null;uniqueIndex:idx_payrolls_employee_period,where:deleted_at IS NULL" json:"employee_id"`
 	Employee         Employee       `gorm:"foreignKey:EmployeeID" json:"employee,omitempty"`
-	PeriodMonth      int            `gorm:"not null" json:"period_month"`
-	PeriodYear       int            `gorm:"not null" json:"period_year"`
+	PayrollRunID     *string        `gorm:"type:uuid;index" json:"payroll_run_id"`
+	PeriodMonth      int            `gorm:"not null;uniqueIndex:idx_payrolls_employee_period" json:"period_month"`
+	PeriodYear       int            `gorm:"not null;uniqueIndex:idx_payrolls_employee_period" json:"period_year"`
 	WorkingDays      int            `gorm:"default:0" json:"working_days"`
 	PresentDays      int            `gorm:"default:0" json:"present_days"`
 	BasicSalary      float64        `gorm:"type:decimal(15,2);default:0" json:"basic_salary"`
@@ -31,6 +34,9 @@ type Payroll struct {
 	BPJSKesDeduction float64        `gorm:"type:decimal(15,2);default:0" json:"bpjs_kes_deduction"`
 	BPJSTKDeduction  float64        `gorm:"type:decimal(15,2);default:0" json:"bpjs_tk_deduction"`
 	PPH21            float64        `gorm:"type:decimal(15,2);default:0" json:"pph21"`
+	TaxableIncome    float64        `gorm:"type:decimal(15,2);default:0" json:"taxable_income"`
+	PTKPStatus       string         `gorm:"type:varchar(10)" json:"ptkp_status"`
+	TERCategory      string         `gorm:"type:varchar(1)" json:"ter_category"`
 	OtherDeductions  float64        `gorm:"type:decimal(15,2);default:0" json:"other_deductions"`
 	GrossSalary      float64        `gorm:"type:decimal(15,2);default:0" json:"gross_salary"`
 	NetSalary        float64        `gorm:"type:decimal(15,2);default:0" json:"net_salary"`
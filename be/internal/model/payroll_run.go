@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PayrollRun groups the payrolls generated together for one company and
+// period so they can be processed and paid as a single batch. The run status
+// follows the same draft -> processed -> paid lifecycle as each Payroll.
+type PayrollRun struct {
+	ID             string         `gorm:"type:uuid;primaryKey" json:"id"`
+	CompanyID      string         `gorm:"type:uuid;not null;index" json:"company_id"`
+	Company        Company        `gorm:"foreignKey:CompanyID" json:"company,omitempty"`
+	PeriodMonth    int            `gorm:"not null" json:"period_month"`
+	PeriodYear     int            `gorm:"not null" json:"period_year"`
+	Status         PayrollStatus  `gorm:"type:varchar(20);not null;default:'draft'" json:"status"`
+	TotalEmployees int            `gorm:"default:0" json:"total_employees"`
+	CreatedCount   int            `gorm:"default:0" json:"created_count"`
+	SkippedCount   int            `gorm:"default:0" json:"skipped_count"`
+	FailedCount    int            `gorm:"default:0" json:"failed_count"`
+	TotalGross     float64        `gorm:"type:decimal(15,2);default:0" json:"total_gross"`
+	TotalNet       float64        `gorm:"type:decimal(15,2);default:0" json:"total_net"`
+	CreatedBy      string         `gorm:"type:uuid" json:"created_by"`
+	ProcessedAt    *time.Time     `gorm:"type:timestamp" json:"processed_at"`
+	PaidAt         *time.Time     `gorm:"type:timestamp" json:"paid_at"`
+	Notes          string         `gorm:"type:text" json:"notes"`
+	Payrolls       []Payroll      `gorm:"foreignKey:PayrollRunID" json:"payrolls,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (r *PayrollRun) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Session revocation reasons
+const (
+	SessionRevokedLogout        = "logout"
+	SessionRevokedByUser        = "revoked_by_user"
+	SessionRevokedByAdmin       = "revoked_by_admin"
+	SessionRevokedReuseDetected = "reuse_detected"
+	SessionRevokedPassword      = "password_changed"
+)
+
+// Session is a refresh-token family: one login on one device. Every refresh
+// rotates TokenID, the jti of the only refresh token of the family that is
+// still accepted. Presenting an older token of the family means it was
+// copied, so the whole session is revoked.
+type Session struct {
+	ID            string     `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID        string     `gorm:"type:uuid;not null;index" json:"user_id"`
+	User          User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	TokenID       string     `gorm:"type:uuid;not null" json:"-"`
+	UserAgent     string     `gorm:"type:varchar(500)" json:"user_agent"`
+	IPAddress     string     `gorm:"type:varchar(45)" json:"ip_address"`
+	LastUsedAt    time.Time  `gorm:"not null" json:"last_used_at"`
+	ExpiresAt     time.Time  `gorm:"not null;index" json:"expires_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	RevokedReason string     `gorm:"type:varchar(30)" json:"revoked_reason,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (s *Session) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// IsActive reports whether the session can still be refreshed
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
@@ -14,6 +14,7 @@ type Shift struct {
 	Name      string         `gorm:"type:varchar(100);not null" json:"name"`
 	StartTime string         `gorm:"type:varchar(5);not null" json:"start_time"`
 	EndTime   string         `gorm:"type:varchar(5);not null" json:"end_time"`
+	WorkDays  string         `gorm:"type:varchar(20);not null;default:'1,2,3,4,5'" json:"work_days"` // ISO weekdays, 1 = Monday ... 7 = Sunday
 	IsActive  bool           `gorm:"default:true" json:"is_active"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserTwoFactor holds a user's TOTP secret. The secret is pending until the
+// user proves their authenticator works by entering a code, which enables
+// it. LastUsedStep is the time step of the last accepted code; codes of that
+// step or earlier are rejected so a code cannot be replayed. ChallengeID is
+// the ID of the login challenge the user may still trade in; it is cleared
+// when the challenge is presented, so each challenge is tried only once.
+type UserTwoFactor struct {
+	ID           string     `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID       string     `gorm:"type:uuid;not null;uniqueIndex" json:"user_id"`
+	User         User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Secret       string     `gorm:"type:varchar(64);not null" json:"-"`
+	Enabled      bool       `gorm:"default:false" json:"enabled"`
+	EnabledAt    *time.Time `json:"enabled_at,omitempty"`
+	LastUsedStep int64      `gorm:"default:0" json:"-"`
+	ChallengeID  *string    `gorm:"type:uuid" json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (t *UserTwoFactor) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// RecoveryCode is a single-use code that stands in for a TOTP code when the
+// user has lost their authenticator. Only the hash is stored.
+type RecoveryCode struct {
+	ID        string     `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    string     `gorm:"type:uuid;not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"type:varchar(64);not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (r *RecoveryCode) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
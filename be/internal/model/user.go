@@ -16,18 +16,35 @@ const (
 	RoleEmployee   Role = "employee"
 )
 
+// Languages a user can receive email in
+const (
+	LanguageIndonesian = "id"
+	LanguageEnglish    = "en"
+)
+
+// User is an account that can sign in. Service accounts are users that
+// belong to an integration rather than a person: they cannot log in and only
+// authenticate with API tokens.
 type User struct {
-	ID        string         `gorm:"type:uuid;primaryKey" json:"id"`
-	Name      string         `gorm:"type:varchar(255);not null" json:"name"`
-	Email     string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
-	Password  string         `gorm:"type:varchar(255);not null" json:"-"`
-	Role      Role           `gorm:"type:varchar(20);not null;default:'employee'" json:"role"`
-	Phone     string         `gorm:"type:varchar(20)" json:"phone"`
-	Address   string         `gorm:"type:text" json:"address"`
-	IsActive  bool           `gorm:"default:true" json:"is_active"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                  string         `gorm:"type:uuid;primaryKey" json:"id"`
+	Name                string         `gorm:"type:varchar(255);not null" json:"name"`
+	Email               string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
+	Password            string         `gorm:"type:varchar(255);not null" json:"-"`
+	Role                Role           `gorm:"type:varchar(20);not null;default:'employee'" json:"role"`
+	CustomRoleID        *string        `gorm:"type:uuid" json:"custom_role_id"`
+	CustomRole          *CustomRole    `gorm:"foreignKey:CustomRoleID" json:"custom_role,omitempty"`
+	Phone               string         `gorm:"type:varchar(20)" json:"phone"`
+	Address             string         `gorm:"type:text" json:"address"`
+	Language            string         `gorm:"type:varchar(5);not null;default:'id'" json:"language"`
+	IsActive            bool           `gorm:"default:true" json:"is_active"`
+	MustChangePassword  bool           `gorm:"default:false" json:"must_change_password"`
+	IsServiceAccount    bool           `gorm:"default:false" json:"is_service_account"`
+	FailedLoginAttempts int            `gorm:"default:0" json:"-"`
+	LastFailedLoginAt   *time.Time     `json:"-"`
+	LockedUntil         *time.Time     `json:"locked_until,omitempty"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+	DeletedAt           gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) error {
@@ -36,3 +53,8 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// IsLocked reports whether the account is locked out after failed logins
+func (u *User) IsLocked() bool {
+	return u.LockedUntil != nil && time.Now().Before(*u.LockedUntil)
+}
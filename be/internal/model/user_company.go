@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserCompany grants a user access to a company's data in addition to the
+// company of their own employee record. Admin and HR users of a holding
+// typically have one binding per subsidiary they manage.
+type UserCompany struct {
+	ID        string    `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    string    `gorm:"type:uuid;not null;uniqueIndex:idx_user_company" json:"user_id"`
+	User      User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	CompanyID string    `gorm:"type:uuid;not null;uniqueIndex:idx_user_company" json:"company_id"`
+	Company   Company   `gorm:"foreignKey:CompanyID" json:"company,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (u *UserCompany) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == "" {
+		u.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -55,7 +55,7 @@ var Registry = []ModuleDef{
 		Description: "Pre-approval workflow for overtime"},
 	{Key: "announcements", Name: "Announcements",
 		Category: "company",
-		Description: "Company-wide bulletin board"},
+		Description: "Company bulletin board targeted by department or job level, with read receipts"},
 }
 
 // IsCore returns true if the module is always-on regardless of company toggle.
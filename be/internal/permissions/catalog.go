@@ -0,0 +1,160 @@
+// Package permissions is the code-defined catalog of permissions checked by
+// RequirePermission. A permission is a module and an action, named
+// "module:action". The catalog and the built-in role defaults are synced into
+// the DB on startup; custom roles pick from the catalog at runtime.
+package permissions
+
+import "strings"
+
+const (
+	UsersRead             = "users:read"
+	UsersManage           = "users:manage"
+	LoginAuditRead        = "login_audit:read"
+	SSOManage             = "sso:manage"
+	ServiceAccountsManage = "service_accounts:manage"
+	EventsManage          = "events:manage"
+	CompaniesRead         = "companies:read"
+	CompaniesManage       = "companies:manage"
+	OrganizationRead      = "organization:read"
+	OrganizationManage    = "organization:manage"
+	EmployeesRead         = "employees:read"
+	EmployeesManage       = "employees:manage"
+	SalariesRead          = "salaries:read"
+	SalariesManage        = "salaries:manage"
+	AttendanceManage      = "attendance:manage"
+	AttendanceDelete      = "attendance:delete"
+	LeaveManage           = "leave:manage"
+	LeaveConfigure        = "leave:configure"
+	LeaveBalances         = "leave:balances"
+	PayrollRead           = "payroll:read"
+	PayrollGenerate       = "payroll:generate"
+	PayrollUpdate         = "payroll:update"
+	PayrollApprove        = "payroll:approve"
+	PayrollPay            = "payroll:pay"
+	PayrollDelete         = "payroll:delete"
+	MenuAccessManage      = "menu_access:manage"
+	RolesManage           = "roles:manage"
+	VisitsRead            = "visits:read"
+	VisitsManage          = "visits:manage"
+	VisitPlansManage      = "visit_plans:manage"
+	VisitPlansDelete      = "visit_plans:delete"
+	AnnouncementsManage   = "announcements:manage"
+)
+
+type PermissionDef struct {
+	Name        string
+	Description string
+}
+
+// Module returns the module part of the permission name
+func (p PermissionDef) Module() string {
+	module, _, _ := strings.Cut(p.Name, ":")
+	return module
+}
+
+// Action returns the action part of the permission name
+func (p PermissionDef) Action() string {
+	_, action, _ := strings.Cut(p.Name, ":")
+	return action
+}
+
+// Catalog enumerates every permission known to the system
+var Catalog = []PermissionDef{
+	{Name: UsersRead, Description: "View user accounts"},
+	{Name: UsersManage, Description: "Create, update and delete user accounts"},
+	{Name: LoginAuditRead, Description: "View the login audit log"},
+	{Name: SSOManage, Description: "Manage single sign-on identity providers"},
+	{Name: ServiceAccountsManage, Description: "Manage service accounts and their API tokens"},
+	{Name: EventsManage, Description: "View and replay dead-lettered events and the email delivery log"},
+	{Name: CompaniesRead, Description: "View companies"},
+	{Name: CompaniesManage, Description: "Update company details"},
+	{Name: OrganizationRead, Description: "View departments, positions, shifts, holidays, job levels, grades and the org structure"},
+	{Name: OrganizationManage, Description: "Manage departments, positions, shifts, holidays, job levels and grades"},
+	{Name: EmployeesRead, Description: "View all employees"},
+	{Name: EmployeesManage, Description: "Create, update and delete employees"},
+	{Name: SalariesRead, Description: "View employee salaries"},
+	{Name: SalariesManage, Description: "Manage employee salaries"},
+	{Name: AttendanceManage, Description: "Record, import and correct attendance for any employee"},
+	{Name: AttendanceDelete, Description: "Delete attendance records"},
+	{Name: LeaveManage, Description: "View and cancel any employee's leave, balances and delegations"},
+	{Name: LeaveConfigure, Description: "Manage leave policies and approval workflows"},
+	{Name: LeaveBalances, Description: "Set entitlements, adjust balances, accrue and close leave years"},
+	{Name: PayrollRead, Description: "View payrolls and payroll runs"},
+	{Name: PayrollGenerate, Description: "Generate payrolls and payroll runs"},
+	{Name: PayrollUpdate, Description: "Edit payroll amounts"},
+	{Name: PayrollApprove, Description: "Process payrolls or send them back to draft"},
+	{Name: PayrollPay, Description: "Mark payrolls as paid"},
+	{Name: PayrollDelete, Description: "Delete payrolls and payroll runs"},
+	{Name: MenuAccessManage, Description: "Manage per-user menu access"},
+	{Name: RolesManage, Description: "Manage custom roles"},
+	{Name: VisitsRead, Description: "View all visits"},
+	{Name: VisitsManage, Description: "Delete visits"},
+	{Name: VisitPlansManage, Description: "Plan visits and view the adherence report"},
+	{Name: VisitPlansDelete, Description: "Delete visit plans"},
+	{Name: AnnouncementsManage, Description: "Write, publish and delete announcements and see who has not read them"},
+}
+
+// RoleDefaults lists the permissions of the built-in roles, granted on every
+// startup. Other combinations need no code change: admins create a custom
+// role instead.
+var RoleDefaults = map[string][]string{
+	"admin": Names(),
+	"hr": {
+		UsersRead, LoginAuditRead, CompaniesRead, OrganizationRead, EmployeesRead, SalariesRead,
+		AttendanceManage, LeaveManage, LeaveConfigure, LeaveBalances,
+		PayrollRead, PayrollGenerate, VisitsRead, VisitPlansManage, AnnouncementsManage,
+	},
+	"employee": {},
+}
+
+// Names returns the names of every permission in the catalog
+func Names() []string {
+	names := make([]string, len(Catalog))
+	for i, p := range Catalog {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// IsValid reports whether name is in the catalog
+func IsValid(name string) bool {
+	for _, p := range Catalog {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Set is a user's effective permissions
+type Set map[string]bool
+
+// NewSet builds a set from permission names
+func NewSet(names []string) Set {
+	set := make(Set, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// Has reports whether the set contains any of the given permissions
+func (s Set) Has(names ...string) bool {
+	for _, name := range names {
+		if s[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// Intersect returns the permissions of names that are also in the set
+func (s Set) Intersect(names []string) Set {
+	set := make(Set, len(names))
+	for _, name := range names {
+		if s[name] {
+			set[name] = true
+		}
+	}
+	return set
+}
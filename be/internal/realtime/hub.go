@@ -0,0 +1,200 @@
+// Package realtime pushes new notifications to the users connected to this
+// instance.
+package realtime
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+)
+
+const (
+	// subscriptionBuffer is how many notifications a subscriber may fall
+	// behind before it is dropped
+	subscriptionBuffer = 32
+
+	listenRetryDelay = 5 * time.Second
+	fetchTimeout     = 5 * time.Second
+)
+
+// Hub fans new notifications out to the subscriptions of their users. It
+// learns about them from Postgres LISTEN/NOTIFY on
+// repository.NotificationChannel, so a notification created by any instance
+// reaches the users connected to every instance.
+//
+// A subscription that falls behind, or that may have missed notifications
+// because the listener reconnected, is closed. Its client reconnects and
+// resumes from the last notification it saw.
+type Hub struct {
+	db        *gorm.DB
+	notifRepo repository.NotificationRepository
+
+	mu   sync.Mutex
+	subs map[string]map[*Subscription]struct{}
+}
+
+// Subscription receives the new notifications of one user on C until it is
+// closed. C is closed when the hub drops the subscription.
+type Subscription struct {
+	C <-chan model.Notification
+
+	c      chan model.Notification
+	userID string
+	hub    *Hub
+}
+
+// NewHub creates a hub listening through a connection of db.
+func NewHub(db *gorm.DB, notifRepo repository.NotificationRepository) *Hub {
+	return &Hub{
+		db:        db,
+		notifRepo: notifRepo,
+		subs:      make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Start begins listening in a background loop.
+func (h *Hub) Start() {
+	go func() {
+		for {
+			err := h.listen()
+			h.dropAll()
+			log.Printf("[realtime] listener error (retrying in %s): %v", listenRetryDelay, err)
+			time.Sleep(listenRetryDelay)
+		}
+	}()
+}
+
+// Subscribe starts receiving the new notifications of a user.
+func (h *Hub) Subscribe(userID string) *Subscription {
+	c := make(chan model.Notification, subscriptionBuffer)
+	sub := &Subscription{C: c, c: c, userID: userID, hub: h}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[*Subscription]struct{})
+	}
+	h.subs[userID][sub] = struct{}{}
+	return sub
+}
+
+// Close stops the subscription. It may be called more than once.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.dropLocked(s)
+}
+
+// listen holds a connection of the pool in LISTEN mode and dispatches the
+// notifications it receives until the connection fails
+func (h *Hub) listen() error {
+	ctx := context.Background()
+	sqlDB, err := h.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var listenErr error
+	conn.Raw(func(driverConn any) error {
+		stdConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			listenErr = errors.New("database driver is not pgx")
+			return nil
+		}
+		pgConn := stdConn.Conn()
+		if _, err := pgConn.Exec(ctx, "LISTEN "+repository.NotificationChannel); err != nil {
+			listenErr = err
+			return driver.ErrBadConn
+		}
+		log.Printf("[realtime] listening on %s", repository.NotificationChannel)
+
+		for {
+			n, err := pgConn.WaitForNotification(ctx)
+			if err != nil {
+				listenErr = err
+				// Keep the connection out of the pool: it is still listening
+				return driver.ErrBadConn
+			}
+			h.dispatch(n.Payload)
+		}
+	})
+	return listenErr
+}
+
+// dispatch delivers the announced notification to the subscriptions of its
+// user, loading it only when there are any
+func (h *Hub) dispatch(payload string) {
+	var created repository.NotificationCreated
+	if err := json.Unmarshal([]byte(payload), &created); err != nil {
+		log.Printf("[realtime] invalid notification payload %q: %v", payload, err)
+		return
+	}
+
+	h.mu.Lock()
+	listeners := len(h.subs[created.UserID])
+	h.mu.Unlock()
+	if listeners == 0 {
+		return
+	}
+
+	// Notifications belong to users, not companies; no tenant scope applies
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	n, err := h.notifRepo.FindByID(ctx, created.ID)
+	if err != nil {
+		log.Printf("[realtime] load notification %s: %v", created.ID, err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs[created.UserID] {
+		select {
+		case sub.c <- *n:
+		default:
+			log.Printf("[realtime] dropping slow subscriber of user %s", created.UserID)
+			h.dropLocked(sub)
+		}
+	}
+}
+
+// dropAll closes every subscription; they may have missed notifications
+func (h *Hub) dropAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, subs := range h.subs {
+		for sub := range subs {
+			h.dropLocked(sub)
+		}
+	}
+}
+
+func (h *Hub) dropLocked(sub *Subscription) {
+	subs, ok := h.subs[sub.userID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subs, sub.userID)
+	}
+	close(sub.c)
+}
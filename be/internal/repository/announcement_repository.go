@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errAlreadyNotified rolls back the notification of an announcement whose
+// audience another instance notified first
+var errAlreadyNotified = errors.New("announcement audience was already notified")
+
+type AnnouncementRepository interface {
+	Create(ctx context.Context, a *model.Announcement) error
+	Update(ctx context.Context, a *model.Announcement) error
+	FindByID(ctx context.Context, id string) (*model.Announcement, error)
+	FindAllPaginated(ctx context.Context, page, limit int, companyID, status string, now time.Time) ([]model.Announcement, int64, error)
+	FindVisible(ctx context.Context, companyID string, emp *model.Employee, now time.Time) ([]model.Announcement, error)
+	FindDue(ctx context.Context, now time.Time, limit int) ([]model.Announcement, error)
+	Delete(ctx context.Context, id string) error
+	MarkNotified(ctx context.Context, id string, events []model.OutboxEvent) (bool, error)
+	MarkRead(ctx context.Context, announcementID, userID string) error
+	FindReadIDs(ctx context.Context, userID string, announcementIDs []string) (map[string]bool, error)
+	CountAudience(ctx context.Context, a *model.Announcement) (int64, int64, error)
+	FindUnreadAudience(ctx context.Context, a *model.Announcement) ([]model.Employee, error)
+}
+
+type announcementRepository struct {
+	db *gorm.DB
+}
+
+func NewAnnouncementRepository(db *gorm.DB) AnnouncementRepository {
+	return &announcementRepository{db: db}
+}
+
+// Create inserts an announcement with its targets and attachments
+func (r *announcementRepository) Create(ctx context.Context, a *model.Announcement) error {
+	return r.db.WithContext(ctx).Create(a).Error
+}
+
+// Update saves an announcement and replaces its targets and attachments.
+// NotifiedAt is left to MarkNotified.
+func (r *announcementRepository) Update(ctx context.Context, a *model.Announcement) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations, "notified_at").Save(a).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("announcement_id = ?", a.ID).Delete(&model.AnnouncementTarget{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("announcement_id = ?", a.ID).Delete(&model.AnnouncementAttachment{}).Error; err != nil {
+			return err
+		}
+		for i := range a.Targets {
+			a.Targets[i].ID = ""
+			a.Targets[i].AnnouncementID = a.ID
+		}
+		for i := range a.Attachments {
+			a.Attachments[i].ID = ""
+			a.Attachments[i].AnnouncementID = a.ID
+		}
+		if len(a.Targets) > 0 {
+			if err := tx.Create(&a.Targets).Error; err != nil {
+				return err
+			}
+		}
+		if len(a.Attachments) > 0 {
+			if err := tx.Create(&a.Attachments).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *announcementRepository) FindByID(ctx context.Context, id string) (*model.Announcement, error) {
+	var a model.Announcement
+	err := r.db.WithContext(ctx).Preload("Targets").Preload("Attachments").Where("id = ?", id).First(&a).Error
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// FindAllPaginated lists announcements pinned first, then newest first.
+// status is draft, scheduled (published but not shown yet), published
+// (shown now) or expired.
+func (r *announcementRepository) FindAllPaginated(ctx context.Context, page, limit int, companyID, status string, now time.Time) ([]model.Announcement, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Announcement{})
+
+	if companyID != "" {
+		query = query.Where("company_id = ?", companyID)
+	}
+	switch status {
+	case "draft":
+		query = query.Where("status = ?", model.AnnouncementDraft)
+	case "scheduled":
+		query = query.Where("status = ? AND publish_at > ?", model.AnnouncementPublished, now)
+	case "published":
+		query = query.Where("status = ? AND publish_at <= ? AND (expire_at IS NULL OR expire_at > ?)", model.AnnouncementPublished, now, now)
+	case "expired":
+		query = query.Where("status = ? AND expire_at <= ?", model.AnnouncementPublished, now)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var announcements []model.Announcement
+	offset := (page - 1) * limit
+	err := query.Preload("Targets").Preload("Attachments").
+		Order("is_pinned DESC, COALESCE(publish_at, created_at) DESC").
+		Limit(limit).Offset(offset).
+		Find(&announcements).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return announcements, total, nil
+}
+
+// FindVisible returns the announcements of a company shown now to an
+// employee, pinned first, then newest first. Without an employee record only
+// the announcements for the whole company are shown.
+func (r *announcementRepository) FindVisible(ctx context.Context, companyID string, emp *model.Employee, now time.Time) ([]model.Announcement, error) {
+	query := r.db.WithContext(ctx).
+		Where("company_id = ? AND status = ?", companyID, model.AnnouncementPublished).
+		Where("publish_at <= ? AND (expire_at IS NULL OR expire_at > ?)", now, now)
+
+	untargeted := "NOT EXISTS (SELECT 1 FROM announcement_targets t WHERE t.announcement_id = announcements.id)"
+	if emp == nil {
+		query = query.Where(untargeted)
+	} else {
+		jobLevelID := ""
+		if emp.JobLevelID != nil {
+			jobLevelID = *emp.JobLevelID
+		}
+		query = query.Where("("+untargeted+" OR EXISTS (SELECT 1 FROM announcement_targets t WHERE t.announcement_id = announcements.id"+
+			" AND ((t.target_type = ? AND t.target_id::text = ?) OR (t.target_type = ? AND t.target_id::text = ?))))",
+			model.AnnouncementTargetDepartment, emp.DepartmentID, model.AnnouncementTargetJobLevel, jobLevelID)
+	}
+
+	var announcements []model.Announcement
+	err := query.Preload("Targets").Preload("Attachments").
+		Order("is_pinned DESC, publish_at DESC").
+		Find(&announcements).Error
+	return announcements, err
+}
+
+// FindDue returns the published announcements shown now whose audience was
+// not notified yet
+func (r *announcementRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]model.Announcement, error) {
+	var announcements []model.Announcement
+	err := r.db.WithContext(ctx).Preload("Targets").
+		Where("status = ? AND notified_at IS NULL", model.AnnouncementPublished).
+		Where("publish_at <= ? AND (expire_at IS NULL OR expire_at > ?)", now, now).
+		Order("publish_at ASC").
+		Limit(limit).
+		Find(&announcements).Error
+	return announcements, err
+}
+
+func (r *announcementRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.Announcement{}).Error
+}
+
+// MarkNotified marks the audience of an announcement notified and writes the
+// outbox events that notify it, in one transaction. It writes nothing and
+// returns false if the audience was notified already, so publishing and the
+// scheduler do not notify it twice.
+func (r *announcementRepository) MarkNotified(ctx context.Context, id string, events []model.OutboxEvent) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Announcement{}).
+			Where("id = ? AND notified_at IS NULL", id).
+			Update("notified_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errAlreadyNotified
+		}
+		return createOutboxEvents(tx, events)
+	})
+	if errors.Is(err, errAlreadyNotified) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// MarkRead records that a user read an announcement; reading it again keeps
+// the first receipt
+func (r *announcementRepository) MarkRead(ctx context.Context, announcementID, userID string) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "announcement_id"}, {Name: "user_id"}},
+		DoNothing: true,
+	}).Create(&model.AnnouncementRead{
+		AnnouncementID: announcementID,
+		UserID:         userID,
+		ReadAt:         time.Now(),
+	}).Error
+}
+
+// FindReadIDs returns which of the announcements the user has read
+func (r *announcementRepository) FindReadIDs(ctx context.Context, userID string, announcementIDs []string) (map[string]bool, error) {
+	read := make(map[string]bool)
+	if len(announcementIDs) == 0 {
+		return read, nil
+	}
+	var ids []string
+	err := r.db.WithContext(ctx).Model(&model.AnnouncementRead{}).
+		Where("user_id = ? AND announcement_id IN ?", userID, announcementIDs).
+		Pluck("announcement_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		read[id] = true
+	}
+	return read, nil
+}
+
+// CountAudience returns how many employees an announcement is for and how
+// many of them read it
+func (r *announcementRepository) CountAudience(ctx context.Context, a *model.Announcement) (int64, int64, error) {
+	var total, read int64
+	db := r.db.WithContext(ctx)
+	if err := audienceEmployees(db, a.CompanyID, a.TargetIDs(model.AnnouncementTargetDepartment), a.TargetIDs(model.AnnouncementTargetJobLevel)).
+		Count(&total).Error; err != nil {
+		return 0, 0, err
+	}
+	err := audienceEmployees(db, a.CompanyID, a.TargetIDs(model.AnnouncementTargetDepartment), a.TargetIDs(model.AnnouncementTargetJobLevel)).
+		Where("employees.user_id IN (SELECT user_id FROM announcement_reads WHERE announcement_id = ?)", a.ID).
+		Count(&read).Error
+	return total, read, err
+}
+
+// FindUnreadAudience returns the employees an announcement is for who have
+// not read it, by employee number
+func (r *announcementRepository) FindUnreadAudience(ctx context.Context, a *model.Announcement) ([]model.Employee, error) {
+	var employees []model.Employee
+	err := audienceEmployees(r.db.WithContext(ctx), a.CompanyID, a.TargetIDs(model.AnnouncementTargetDepartment), a.TargetIDs(model.AnnouncementTargetJobLevel)).
+		Where("employees.user_id NOT IN (SELECT user_id FROM announcement_reads WHERE announcement_id = ?)", a.ID).
+		Preload("User").Preload("Department").
+		Order("employees.employee_number ASC").
+		Find(&employees).Error
+	return employees, err
+}
+
+// audienceEmployees selects the current employees of a company with an
+// active account who are in one of the departments or job levels. With
+// neither, it selects the whole company.
+func audienceEmployees(db *gorm.DB, companyID string, departmentIDs, jobLevelIDs []string) *gorm.DB {
+	q := db.Model(&model.Employee{}).
+		Where("employees.company_id = ? AND employees.resign_date IS NULL", companyID).
+		Where("employees.user_id IN (SELECT id FROM users WHERE is_active = true AND deleted_at IS NULL)")
+
+	switch {
+	case len(departmentIDs) > 0 && len(jobLevelIDs) > 0:
+		q = q.Where("(employees.department_id IN ? OR employees.job_level_id IN ?)", departmentIDs, jobLevelIDs)
+	case len(departmentIDs) > 0:
+		q = q.Where("employees.department_id IN ?", departmentIDs)
+	case len(jobLevelIDs) > 0:
+		q = q.Where("employees.job_level_id IN ?", jobLevelIDs)
+	}
+	return q
+}
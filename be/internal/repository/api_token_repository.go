@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type APITokenRepository interface {
+	Create(ctx context.Context, token *model.APIToken) error
+	FindByID(ctx context.Context, id string) (*model.APIToken, error)
+	FindByTokenHash(ctx context.Context, tokenHash string) (*model.APIToken, error)
+	FindByUserID(ctx context.Context, userID string) ([]model.APIToken, error)
+	Touch(ctx context.Context, id, ipAddress string, minInterval time.Duration) error
+	Revoke(ctx context.Context, id string) error
+	RevokeAllForUser(ctx context.Context, userID string) error
+}
+
+type apiTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewAPITokenRepository(db *gorm.DB) APITokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+func (r *apiTokenRepository) Create(ctx context.Context, token *model.APIToken) error {
+	return r.db.WithContext(ctx).Omit("User", "Company").Create(token).Error
+}
+
+func (r *apiTokenRepository) FindByID(ctx context.Context, id string) (*model.APIToken, error) {
+	var token model.APIToken
+	if err := r.db.WithContext(ctx).Preload("Company").First(&token, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *apiTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*model.APIToken, error) {
+	var token model.APIToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindByUserID returns the user's tokens that are not revoked, newest first.
+// Expired tokens are included so owners can see what stopped working.
+func (r *apiTokenRepository) FindByUserID(ctx context.Context, userID string) ([]model.APIToken, error) {
+	var tokens []model.APIToken
+	err := r.db.WithContext(ctx).Preload("Company").
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Touch records a use of the token. Uses within minInterval of the recorded
+// one are not written, so busy integrations do not update the row on every
+// request.
+func (r *apiTokenRepository) Touch(ctx context.Context, id, ipAddress string, minInterval time.Duration) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Model(&model.APIToken{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, now.Add(-minInterval)).
+		Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": ipAddress}).Error
+}
+
+func (r *apiTokenRepository) Revoke(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&model.APIToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *apiTokenRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Model(&model.APIToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
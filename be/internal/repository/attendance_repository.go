@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"time"
 
 	"hris-backend/internal/model"
@@ -9,17 +10,19 @@ import (
 )
 
 type AttendanceRepository interface {
-	Create(att *model.Attendance) error
-	FindByID(id string) (*model.Attendance, error)
-	FindByEmployeeIDAndDate(employeeID string, date time.Time) (*model.Attendance, error)
-	FindByEmployeeID(employeeID string) ([]model.Attendance, error)
-	FindByEmployeeIDAndMonth(employeeID string, month, year int) ([]model.Attendance, error)
-	FindByMonth(month, year int) ([]model.Attendance, error)
-	FindByDate(date time.Time) ([]model.Attendance, error)
-	FindAll() ([]model.Attendance, error)
-	FindAllPaginated(page, limit int, employeeID string, month, year int, startDate, endDate string) ([]model.Attendance, int64, error)
-	Update(att *model.Attendance) error
-	Delete(id string) error
+	Create(ctx context.Context, att *model.Attendance) error
+	FindByID(ctx context.Context, id string) (*model.Attendance, error)
+	FindByEmployeeIDAndDate(ctx context.Context, employeeID string, date time.Time) (*model.Attendance, error)
+	FindByEmployeeIDAndDateRange(ctx context.Context, employeeID string, start, end time.Time) ([]model.Attendance, error)
+	FindByEmployeeID(ctx context.Context, employeeID string) ([]model.Attendance, error)
+	FindByEmployeeIDAndMonth(ctx context.Context, employeeID string, month, year int) ([]model.Attendance, error)
+	FindByMonth(ctx context.Context, month, year int) ([]model.Attendance, error)
+	FindByDate(ctx context.Context, date time.Time) ([]model.Attendance, error)
+	FindEmployeeIDsByDate(ctx context.Context, date time.Time) ([]string, error)
+	FindAll(ctx context.Context) ([]model.Attendance, error)
+	FindAllPaginated(ctx context.Context, page, limit int, employeeID string, month, year int, startDate, endDate string) ([]model.Attendance, int64, error)
+	Update(ctx context.Context, att *model.Attendance) error
+	Delete(ctx context.Context, id string) error
 }
 
 type attendanceRepository struct {
@@ -34,37 +37,45 @@ func (r *attendanceRepository) preload(db *gorm.DB) *gorm.DB {
 	return db.Preload("Employee").Preload("Employee.User").Preload("Shift")
 }
 
-func (r *attendanceRepository) Create(att *model.Attendance) error {
-	return r.db.Create(att).Error
+func (r *attendanceRepository) Create(ctx context.Context, att *model.Attendance) error {
+	return r.db.WithContext(ctx).Create(att).Error
 }
 
-func (r *attendanceRepository) FindByID(id string) (*model.Attendance, error) {
+func (r *attendanceRepository) FindByID(ctx context.Context, id string) (*model.Attendance, error) {
 	var att model.Attendance
-	if err := r.preload(r.db).First(&att, "id = ?", id).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).First(&att, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &att, nil
 }
 
-func (r *attendanceRepository) FindByEmployeeIDAndDate(employeeID string, date time.Time) (*model.Attendance, error) {
+func (r *attendanceRepository) FindByEmployeeIDAndDate(ctx context.Context, employeeID string, date time.Time) (*model.Attendance, error) {
 	var att model.Attendance
-	if err := r.preload(r.db).Where("employee_id = ? AND date = ?", employeeID, date).First(&att).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).Where("employee_id = ? AND date = ?", employeeID, date).First(&att).Error; err != nil {
 		return nil, err
 	}
 	return &att, nil
 }
 
-func (r *attendanceRepository) FindByEmployeeID(employeeID string) ([]model.Attendance, error) {
+func (r *attendanceRepository) FindByEmployeeIDAndDateRange(ctx context.Context, employeeID string, start, end time.Time) ([]model.Attendance, error) {
 	var attendances []model.Attendance
-	if err := r.preload(r.db).Where("employee_id = ?", employeeID).Order("date DESC").Find(&attendances).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("employee_id = ? AND date BETWEEN ? AND ?", employeeID, start, end).Order("date ASC").Find(&attendances).Error; err != nil {
 		return nil, err
 	}
 	return attendances, nil
 }
 
-func (r *attendanceRepository) FindByEmployeeIDAndMonth(employeeID string, month, year int) ([]model.Attendance, error) {
+func (r *attendanceRepository) FindByEmployeeID(ctx context.Context, employeeID string) ([]model.Attendance, error) {
 	var attendances []model.Attendance
-	if err := r.preload(r.db).
+	if err := r.preload(r.db.WithContext(ctx)).Where("employee_id = ?", employeeID).Order("date DESC").Find(&attendances).Error; err != nil {
+		return nil, err
+	}
+	return attendances, nil
+}
+
+func (r *attendanceRepository) FindByEmployeeIDAndMonth(ctx context.Context, employeeID string, month, year int) ([]model.Attendance, error) {
+	var attendances []model.Attendance
+	if err := r.preload(r.db.WithContext(ctx)).
 		Where("employee_id = ? AND EXTRACT(MONTH FROM date) = ? AND EXTRACT(YEAR FROM date) = ?", employeeID, month, year).
 		Order("date ASC").Find(&attendances).Error; err != nil {
 		return nil, err
@@ -72,9 +83,9 @@ func (r *attendanceRepository) FindByEmployeeIDAndMonth(employeeID string, month
 	return attendances, nil
 }
 
-func (r *attendanceRepository) FindByMonth(month, year int) ([]model.Attendance, error) {
+func (r *attendanceRepository) FindByMonth(ctx context.Context, month, year int) ([]model.Attendance, error) {
 	var attendances []model.Attendance
-	if err := r.preload(r.db).
+	if err := r.preload(r.db.WithContext(ctx)).
 		Where("EXTRACT(MONTH FROM date) = ? AND EXTRACT(YEAR FROM date) = ?", month, year).
 		Order("date DESC, created_at DESC").Find(&attendances).Error; err != nil {
 		return nil, err
@@ -82,24 +93,32 @@ func (r *attendanceRepository) FindByMonth(month, year int) ([]model.Attendance,
 	return attendances, nil
 }
 
-func (r *attendanceRepository) FindByDate(date time.Time) ([]model.Attendance, error) {
+func (r *attendanceRepository) FindByDate(ctx context.Context, date time.Time) ([]model.Attendance, error) {
 	var attendances []model.Attendance
-	if err := r.preload(r.db).Where("date = ?", date).Order("created_at DESC").Find(&attendances).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).Where("date = ?", date).Order("created_at DESC").Find(&attendances).Error; err != nil {
 		return nil, err
 	}
 	return attendances, nil
 }
 
-func (r *attendanceRepository) FindAll() ([]model.Attendance, error) {
+// FindEmployeeIDsByDate returns the employees with an attendance record on
+// date, including records written by approved leaves
+func (r *attendanceRepository) FindEmployeeIDsByDate(ctx context.Context, date time.Time) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).Model(&model.Attendance{}).Where("date = ?", date).Distinct().Pluck("employee_id", &ids).Error
+	return ids, err
+}
+
+func (r *attendanceRepository) FindAll(ctx context.Context) ([]model.Attendance, error) {
 	var attendances []model.Attendance
-	if err := r.preload(r.db).Order("date DESC").Find(&attendances).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).Order("date DESC").Find(&attendances).Error; err != nil {
 		return nil, err
 	}
 	return attendances, nil
 }
 
-func (r *attendanceRepository) FindAllPaginated(page, limit int, employeeID string, month, year int, startDate, endDate string) ([]model.Attendance, int64, error) {
-	query := r.db.Model(&model.Attendance{})
+func (r *attendanceRepository) FindAllPaginated(ctx context.Context, page, limit int, employeeID string, month, year int, startDate, endDate string) ([]model.Attendance, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Attendance{})
 
 	if employeeID != "" {
 		query = query.Where("employee_id = ?", employeeID)
@@ -128,10 +147,10 @@ func (r *attendanceRepository) FindAllPaginated(page, limit int, employeeID stri
 	return attendances, total, nil
 }
 
-func (r *attendanceRepository) Update(att *model.Attendance) error {
-	return r.db.Save(att).Error
+func (r *attendanceRepository) Update(ctx context.Context, att *model.Attendance) error {
+	return r.db.WithContext(ctx).Save(att).Error
 }
 
-func (r *attendanceRepository) Delete(id string) error {
-	return r.db.Delete(&model.Attendance{}, "id = ?", id).Error
+func (r *attendanceRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&model.Attendance{}, "id = ?", id).Error
 }
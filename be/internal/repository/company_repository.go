@@ -1,20 +1,22 @@
 package repository
 
 import (
+	"context"
+
 	"hris-backend/internal/model"
 
 	"gorm.io/gorm"
 )
 
 type CompanyRepository interface {
-	Create(company *model.Company) error
-	FindByID(id string) (*model.Company, error)
-	FindAll() ([]model.Company, error)
-	FindAllPaginated(page, limit int, search, sortBy, sortOrder string) ([]model.Company, int64, error)
-	FindWithStructure(companyID string) (*model.Company, error)
-	Update(company *model.Company) error
-	Delete(id string) error
-	DeleteMultiple(ids []string) error
+	Create(ctx context.Context, company *model.Company) error
+	FindByID(ctx context.Context, id string) (*model.Company, error)
+	FindAll(ctx context.Context) ([]model.Company, error)
+	FindAllPaginated(ctx context.Context, page, limit int, search, sortBy, sortOrder string) ([]model.Company, int64, error)
+	FindWithStructure(ctx context.Context, companyID string) (*model.Company, error)
+	Update(ctx context.Context, company *model.Company) error
+	Delete(ctx context.Context, id string) error
+	DeleteMultiple(ctx context.Context, ids []string) error
 }
 
 type companyRepository struct {
@@ -25,29 +27,29 @@ func NewCompanyRepository(db *gorm.DB) CompanyRepository {
 	return &companyRepository{db: db}
 }
 
-func (r *companyRepository) Create(company *model.Company) error {
-	return r.db.Create(company).Error
+func (r *companyRepository) Create(ctx context.Context, company *model.Company) error {
+	return r.db.WithContext(ctx).Create(company).Error
 }
 
-func (r *companyRepository) FindByID(id string) (*model.Company, error) {
+func (r *companyRepository) FindByID(ctx context.Context, id string) (*model.Company, error) {
 	var company model.Company
-	if err := r.db.First(&company, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&company, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &company, nil
 }
 
-func (r *companyRepository) FindAll() ([]model.Company, error) {
+func (r *companyRepository) FindAll(ctx context.Context) ([]model.Company, error) {
 	var companies []model.Company
-	if err := r.db.Order("created_at DESC").Find(&companies).Error; err != nil {
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(&companies).Error; err != nil {
 		return nil, err
 	}
 	return companies, nil
 }
 
-func (r *companyRepository) FindWithStructure(companyID string) (*model.Company, error) {
+func (r *companyRepository) FindWithStructure(ctx context.Context, companyID string) (*model.Company, error) {
 	var company model.Company
-	if err := r.db.
+	if err := r.db.WithContext(ctx).
 		Preload("Departments", func(db *gorm.DB) *gorm.DB {
 			return db.Where("is_active = ?", true).Order("name")
 		}).
@@ -62,12 +64,12 @@ func (r *companyRepository) FindWithStructure(companyID string) (*model.Company,
 	return &company, nil
 }
 
-func (r *companyRepository) Update(company *model.Company) error {
-	return r.db.Save(company).Error
+func (r *companyRepository) Update(ctx context.Context, company *model.Company) error {
+	return r.db.WithContext(ctx).Save(company).Error
 }
 
-func (r *companyRepository) FindAllPaginated(page, limit int, search, sortBy, sortOrder string) ([]model.Company, int64, error) {
-	query := r.db.Model(&model.Company{})
+func (r *companyRepository) FindAllPaginated(ctx context.Context, page, limit int, search, sortBy, sortOrder string) ([]model.Company, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Company{})
 
 	if search != "" {
 		like := "%" + search + "%"
@@ -98,10 +100,10 @@ func (r *companyRepository) FindAllPaginated(page, limit int, search, sortBy, so
 	return companies, total, nil
 }
 
-func (r *companyRepository) Delete(id string) error {
-	return r.db.Delete(&model.Company{}, "id = ?", id).Error
+func (r *companyRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&model.Company{}, "id = ?", id).Error
 }
 
-func (r *companyRepository) DeleteMultiple(ids []string) error {
-	return r.db.Delete(&model.Company{}, "id IN ?", ids).Error
+func (r *companyRepository) DeleteMultiple(ctx context.Context, ids []string) error {
+	return r.db.WithContext(ctx).Delete(&model.Company{}, "id IN ?", ids).Error
 }
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CustomRoleRepository interface {
+	Create(ctx context.Context, role *model.CustomRole) error
+	FindByID(ctx context.Context, id string) (*model.CustomRole, error)
+	FindAll(ctx context.Context) ([]model.CustomRole, error)
+	FindByCompanyID(ctx context.Context, companyID string) ([]model.CustomRole, error)
+	FindByCompanyAndName(ctx context.Context, companyID, name string) (*model.CustomRole, error)
+	UpdateWithPermissions(ctx context.Context, role *model.CustomRole, permissions []model.Permission) error
+	CountUsers(ctx context.Context, id string) (int64, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type customRoleRepository struct {
+	db *gorm.DB
+}
+
+func NewCustomRoleRepository(db *gorm.DB) CustomRoleRepository {
+	return &customRoleRepository{db: db}
+}
+
+func (r *customRoleRepository) preload(db *gorm.DB) *gorm.DB {
+	return db.Preload("Company").Preload("Permissions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("module, action")
+	})
+}
+
+func (r *customRoleRepository) Create(ctx context.Context, role *model.CustomRole) error {
+	return r.db.WithContext(ctx).Omit("Company", "Permissions.*").Create(role).Error
+}
+
+func (r *customRoleRepository) FindByID(ctx context.Context, id string) (*model.CustomRole, error) {
+	var role model.CustomRole
+	if err := r.preload(r.db.WithContext(ctx)).First(&role, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *customRoleRepository) FindAll(ctx context.Context) ([]model.CustomRole, error) {
+	var roles []model.CustomRole
+	if err := r.preload(r.db.WithContext(ctx)).Order("company_id, name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *customRoleRepository) FindByCompanyID(ctx context.Context, companyID string) ([]model.CustomRole, error) {
+	var roles []model.CustomRole
+	if err := r.preload(r.db.WithContext(ctx)).Where("company_id = ?", companyID).Order("name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *customRoleRepository) FindByCompanyAndName(ctx context.Context, companyID, name string) (*model.CustomRole, error) {
+	var role model.CustomRole
+	if err := r.db.WithContext(ctx).Where("company_id = ? AND name = ?", companyID, name).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// UpdateWithPermissions saves the role and replaces its permissions when the
+// slice is non-nil
+func (r *customRoleRepository) UpdateWithPermissions(ctx context.Context, role *model.CustomRole, permissions []model.Permission) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(role).Error; err != nil {
+			return err
+		}
+		if permissions == nil {
+			return nil
+		}
+		return tx.Model(role).Omit("Permissions.*").Association("Permissions").Replace(permissions)
+	})
+}
+
+func (r *customRoleRepository) CountUsers(ctx context.Context, id string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.User{}).Where("custom_role_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *customRoleRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM custom_role_permissions WHERE custom_role_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.CustomRole{}, "id = ?", id).Error
+	})
+}
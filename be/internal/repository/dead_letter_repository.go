@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errNotPending rolls back a replay of dead letters that are no longer
+// pending
+var errNotPending = errors.New("dead letter is not pending")
+
+type DeadLetterRepository interface {
+	Create(ctx context.Context, event *model.DeadLetterEvent) error
+	FindByID(ctx context.Context, id string) (*model.DeadLetterEvent, error)
+	FindAllPaginated(ctx context.Context, page, limit int, status, eventType, startDate, endDate string) ([]model.DeadLetterEvent, int64, error)
+	FindPending(ctx context.Context, ids []string, eventType string, limit int) ([]model.DeadLetterEvent, error)
+	MarkReplayed(ctx context.Context, ids []string, userID string, events []model.OutboxEvent) (bool, error)
+}
+
+type deadLetterRepository struct {
+	db *gorm.DB
+}
+
+func NewDeadLetterRepository(db *gorm.DB) DeadLetterRepository {
+	return &deadLetterRepository{db: db}
+}
+
+// Create records a dead letter, unless its source message is recorded
+// already
+func (r *deadLetterRepository) Create(ctx context.Context, event *model.DeadLetterEvent) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "source_topic"}, {Name: "source_partition"}, {Name: "source_offset"}},
+			DoNothing: true,
+		}).
+		Create(event).Error
+}
+
+func (r *deadLetterRepository) FindByID(ctx context.Context, id string) (*model.DeadLetterEvent, error) {
+	var event model.DeadLetterEvent
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&event).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *deadLetterRepository) FindAllPaginated(ctx context.Context, page, limit int, status, eventType, startDate, endDate string) ([]model.DeadLetterEvent, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.DeadLetterEvent{})
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+	if startDate != "" {
+		query = query.Where("failed_at >= ?", startDate)
+	}
+	if endDate != "" {
+		query = query.Where("failed_at < ?::date + 1", endDate)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []model.DeadLetterEvent
+	offset := (page - 1) * limit
+	if err := query.Order("failed_at DESC").Limit(limit).Offset(offset).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// FindPending returns up to limit pending dead letters, oldest first, among
+// ids when given and of eventType when given
+func (r *deadLetterRepository) FindPending(ctx context.Context, ids []string, eventType string, limit int) ([]model.DeadLetterEvent, error) {
+	query := r.db.WithContext(ctx).Where("status = ?", model.DeadLetterPending)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	if eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+
+	var events []model.DeadLetterEvent
+	err := query.Order("failed_at ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// MarkReplayed marks pending dead letters replayed and writes the outbox
+// events that publish them again, in one transaction. It writes nothing and
+// returns false if any of them is no longer pending, so concurrent replays
+// do not publish a message twice.
+func (r *deadLetterRepository) MarkReplayed(ctx context.Context, ids []string, userID string, events []model.OutboxEvent) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.DeadLetterEvent{}).
+			Where("id IN ? AND status = ?", ids, model.DeadLetterPending).
+			Updates(map[string]interface{}{
+				"status":      model.DeadLetterReplayed,
+				"replayed_at": time.Now(),
+				"replayed_by": userID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != int64(len(ids)) {
+			return errNotPending
+		}
+		return createOutboxEvents(tx, events)
+	})
+	if errors.Is(err, errNotPending) {
+		return false, nil
+	}
+	return err == nil, err
+}
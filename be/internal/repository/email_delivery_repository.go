@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EmailDeliveryRepository interface {
+	Create(ctx context.Context, delivery *model.EmailDelivery) error
+	FindByID(ctx context.Context, id string) (*model.EmailDelivery, error)
+	FindAllPaginated(ctx context.Context, page, limit int, status, eventType, userID string) ([]model.EmailDelivery, int64, error)
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.EmailDelivery, error)
+	MarkSent(ctx context.Context, id string) error
+	MarkRetry(ctx context.Context, id string, lastError string, nextAttemptAt time.Time) error
+	MarkFailed(ctx context.Context, id string, lastError string) error
+}
+
+type emailDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailDeliveryRepository(db *gorm.DB) EmailDeliveryRepository {
+	return &emailDeliveryRepository{db: db}
+}
+
+// Create queues an email, unless the user has one for the same event already
+func (r *emailDeliveryRepository) Create(ctx context.Context, delivery *model.EmailDelivery) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "event_id"}, {Name: "user_id"}},
+			DoNothing: true,
+		}).
+		Create(delivery).Error
+}
+
+func (r *emailDeliveryRepository) FindByID(ctx context.Context, id string) (*model.EmailDelivery, error) {
+	var delivery model.EmailDelivery
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&delivery).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *emailDeliveryRepository) FindAllPaginated(ctx context.Context, page, limit int, status, eventType, userID string) ([]model.EmailDelivery, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.EmailDelivery{})
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []model.EmailDelivery
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+// ClaimDue returns the oldest pending emails that are due and pushes their
+// next attempt back by lease, so another dispatcher does not send them at the
+// same time. Rows claimed by a concurrent transaction are skipped.
+func (r *emailDeliveryRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.EmailDelivery, error) {
+	var deliveries []model.EmailDelivery
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.EmailDeliveryPending, now).
+			Order("created_at ASC").
+			Limit(limit).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]string, len(deliveries))
+		for i := range deliveries {
+			ids[i] = deliveries[i].ID
+		}
+		return tx.Model(&model.EmailDelivery{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	return deliveries, err
+}
+
+func (r *emailDeliveryRepository) MarkSent(ctx context.Context, id string) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Model(&model.EmailDelivery{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     model.EmailDeliverySent,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": "",
+			"sent_at":    now,
+		}).Error
+}
+
+// MarkRetry records a failed attempt and schedules the next one
+func (r *emailDeliveryRepository) MarkRetry(ctx context.Context, id string, lastError string, nextAttemptAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.EmailDelivery{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      lastError,
+			"next_attempt_at": nextAttemptAt,
+		}).Error
+}
+
+// MarkFailed records the last failed attempt and gives up on the email
+func (r *emailDeliveryRepository) MarkFailed(ctx context.Context, id string, lastError string) error {
+	return r.db.WithContext(ctx).Model(&model.EmailDelivery{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     model.EmailDeliveryFailed,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": lastError,
+		}).Error
+}
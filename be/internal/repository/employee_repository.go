@@ -1,20 +1,25 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"hris-backend/internal/model"
 
 	"gorm.io/gorm"
 )
 
 type EmployeeRepository interface {
-	Create(emp *model.Employee) error
-	FindByID(id string) (*model.Employee, error)
-	FindByUserID(userID string) (*model.Employee, error)
-	FindByEmployeeNumber(empNumber string) (*model.Employee, error)
-	FindByCompanyID(companyID string) ([]model.Employee, error)
-	FindAll() ([]model.Employee, error)
-	Update(emp *model.Employee) error
-	Delete(id string) error
+	Create(ctx context.Context, emp *model.Employee) error
+	FindByID(ctx context.Context, id string) (*model.Employee, error)
+	FindByUserID(ctx context.Context, userID string) (*model.Employee, error)
+	FindByEmployeeNumber(ctx context.Context, empNumber string) (*model.Employee, error)
+	FindByCompanyID(ctx context.Context, companyID string) ([]model.Employee, error)
+	FindActiveByCompanyID(ctx context.Context, companyID string, periodStart, periodEnd time.Time) ([]model.Employee, error)
+	FindContractsEndingBetween(ctx context.Context, start, end time.Time) ([]model.Employee, error)
+	FindAll(ctx context.Context) ([]model.Employee, error)
+	Update(ctx context.Context, emp *model.Employee) error
+	Delete(ctx context.Context, id string) error
 }
 
 type employeeRepository struct {
@@ -36,54 +41,83 @@ func (r *employeeRepository) preload(db *gorm.DB) *gorm.DB {
 		Preload("Grade")
 }
 
-func (r *employeeRepository) Create(emp *model.Employee) error {
-	return r.db.Create(emp).Error
+func (r *employeeRepository) Create(ctx context.Context, emp *model.Employee) error {
+	return r.db.WithContext(ctx).Create(emp).Error
 }
 
-func (r *employeeRepository) FindByID(id string) (*model.Employee, error) {
+func (r *employeeRepository) FindByID(ctx context.Context, id string) (*model.Employee, error) {
 	var emp model.Employee
-	if err := r.preload(r.db).First(&emp, "id = ?", id).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).First(&emp, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &emp, nil
 }
 
-func (r *employeeRepository) FindByUserID(userID string) (*model.Employee, error) {
+func (r *employeeRepository) FindByUserID(ctx context.Context, userID string) (*model.Employee, error) {
 	var emp model.Employee
-	if err := r.preload(r.db).First(&emp, "user_id = ?", userID).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).First(&emp, "user_id = ?", userID).Error; err != nil {
 		return nil, err
 	}
 	return &emp, nil
 }
 
-func (r *employeeRepository) FindByEmployeeNumber(empNumber string) (*model.Employee, error) {
+func (r *employeeRepository) FindByEmployeeNumber(ctx context.Context, empNumber string) (*model.Employee, error) {
 	var emp model.Employee
-	if err := r.preload(r.db).First(&emp, "employee_number = ?", empNumber).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).First(&emp, "employee_number = ?", empNumber).Error; err != nil {
 		return nil, err
 	}
 	return &emp, nil
 }
 
-func (r *employeeRepository) FindByCompanyID(companyID string) ([]model.Employee, error) {
+func (r *employeeRepository) FindByCompanyID(ctx context.Context, companyID string) ([]model.Employee, error) {
+	var employees []model.Employee
+	if err := r.preload(r.db.WithContext(ctx)).Where("company_id = ?", companyID).Order("created_at DESC").Find(&employees).Error; err != nil {
+		return nil, err
+	}
+	return employees, nil
+}
+
+// FindActiveByCompanyID returns employees of a company who were employed at
+// some point during the given period: joined on or before periodEnd and not
+// resigned before periodStart.
+func (r *employeeRepository) FindActiveByCompanyID(ctx context.Context, companyID string, periodStart, periodEnd time.Time) ([]model.Employee, error) {
+	var employees []model.Employee
+	if err := r.preload(r.db.WithContext(ctx)).
+		Where("company_id = ? AND join_date <= ?", companyID, periodEnd).
+		Where("resign_date IS NULL OR resign_date >= ?", periodStart).
+		Order("employee_number ASC").
+		Find(&employees).Error; err != nil {
+		return nil, err
+	}
+	return employees, nil
+}
+
+// FindContractsEndingBetween returns the employees who have not resigned and
+// whose contract ends between start and end inclusive
+func (r *employeeRepository) FindContractsEndingBetween(ctx context.Context, start, end time.Time) ([]model.Employee, error) {
 	var employees []model.Employee
-	if err := r.preload(r.db).Where("company_id = ?", companyID).Order("created_at DESC").Find(&employees).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).
+		Where("contract_end_date BETWEEN ? AND ?", start, end).
+		Where("resign_date IS NULL").
+		Order("contract_end_date ASC").
+		Find(&employees).Error; err != nil {
 		return nil, err
 	}
 	return employees, nil
 }
 
-func (r *employeeRepository) FindAll() ([]model.Employee, error) {
+func (r *employeeRepository) FindAll(ctx context.Context) ([]model.Employee, error) {
 	var employees []model.Employee
-	if err := r.preload(r.db).Order("created_at DESC").Find(&employees).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).Order("created_at DESC").Find(&employees).Error; err != nil {
 		return nil, err
 	}
 	return employees, nil
 }
 
-func (r *employeeRepository) Update(emp *model.Employee) error {
-	return r.db.Save(emp).Error
+func (r *employeeRepository) Update(ctx context.Context, emp *model.Employee) error {
+	return r.db.WithContext(ctx).Save(emp).Error
 }
 
-func (r *employeeRepository) Delete(id string) error {
-	return r.db.Delete(&model.Employee{}, "id = ?", id).Error
+func (r *employeeRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&model.Employee{}, "id = ?", id).Error
 }
@@ -1,19 +1,21 @@
 package repository
 
 import (
+	"context"
+
 	"hris-backend/internal/model"
 
 	"gorm.io/gorm"
 )
 
 type EmployeeSalaryRepository interface {
-	Create(salary *model.EmployeeSalary) error
-	FindByID(id string) (*model.EmployeeSalary, error)
-	FindByEmployeeID(employeeID string) ([]model.EmployeeSalary, error)
-	FindLatestByEmployeeID(employeeID string) (*model.EmployeeSalary, error)
-	FindAll() ([]model.EmployeeSalary, error)
-	Update(salary *model.EmployeeSalary) error
-	Delete(id string) error
+	Create(ctx context.Context, salary *model.EmployeeSalary) error
+	FindByID(ctx context.Context, id string) (*model.EmployeeSalary, error)
+	FindByEmployeeID(ctx context.Context, employeeID string) ([]model.EmployeeSalary, error)
+	FindLatestByEmployeeID(ctx context.Context, employeeID string) (*model.EmployeeSalary, error)
+	FindAll(ctx context.Context) ([]model.EmployeeSalary, error)
+	Update(ctx context.Context, salary *model.EmployeeSalary) error
+	Delete(ctx context.Context, id string) error
 }
 
 type employeeSalaryRepository struct {
@@ -24,46 +26,46 @@ func NewEmployeeSalaryRepository(db *gorm.DB) EmployeeSalaryRepository {
 	return &employeeSalaryRepository{db: db}
 }
 
-func (r *employeeSalaryRepository) Create(salary *model.EmployeeSalary) error {
-	return r.db.Create(salary).Error
+func (r *employeeSalaryRepository) Create(ctx context.Context, salary *model.EmployeeSalary) error {
+	return r.db.WithContext(ctx).Create(salary).Error
 }
 
-func (r *employeeSalaryRepository) FindByID(id string) (*model.EmployeeSalary, error) {
+func (r *employeeSalaryRepository) FindByID(ctx context.Context, id string) (*model.EmployeeSalary, error) {
 	var salary model.EmployeeSalary
-	if err := r.db.Preload("Employee").First(&salary, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Employee").First(&salary, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &salary, nil
 }
 
-func (r *employeeSalaryRepository) FindByEmployeeID(employeeID string) ([]model.EmployeeSalary, error) {
+func (r *employeeSalaryRepository) FindByEmployeeID(ctx context.Context, employeeID string) ([]model.EmployeeSalary, error) {
 	var salaries []model.EmployeeSalary
-	if err := r.db.Where("employee_id = ?", employeeID).Order("effective_date DESC").Find(&salaries).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("employee_id = ?", employeeID).Order("effective_date DESC").Find(&salaries).Error; err != nil {
 		return nil, err
 	}
 	return salaries, nil
 }
 
-func (r *employeeSalaryRepository) FindLatestByEmployeeID(employeeID string) (*model.EmployeeSalary, error) {
+func (r *employeeSalaryRepository) FindLatestByEmployeeID(ctx context.Context, employeeID string) (*model.EmployeeSalary, error) {
 	var salary model.EmployeeSalary
-	if err := r.db.Where("employee_id = ?", employeeID).Order("effective_date DESC").First(&salary).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("employee_id = ?", employeeID).Order("effective_date DESC").First(&salary).Error; err != nil {
 		return nil, err
 	}
 	return &salary, nil
 }
 
-func (r *employeeSalaryRepository) FindAll() ([]model.EmployeeSalary, error) {
+func (r *employeeSalaryRepository) FindAll(ctx context.Context) ([]model.EmployeeSalary, error) {
 	var salaries []model.EmployeeSalary
-	if err := r.db.Preload("Employee").Order("created_at DESC").Find(&salaries).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Employee").Order("created_at DESC").Find(&salaries).Error; err != nil {
 		return nil, err
 	}
 	return salaries, nil
 }
 
-func (r *employeeSalaryRepository) Update(salary *model.EmployeeSalary) error {
-	return r.db.Save(salary).Error
+func (r *employeeSalaryRepository) Update(ctx context.Context, salary *model.EmployeeSalary) error {
+	return r.db.WithContext(ctx).Save(salary).Error
 }
 
-func (r *employeeSalaryRepository) Delete(id string) error {
-	return r.db.Delete(&model.EmployeeSalary{}, "id = ?", id).Error
+func (r *employeeSalaryRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&model.EmployeeSalary{}, "id = ?", id).Error
 }
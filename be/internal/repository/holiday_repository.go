@@ -1,19 +1,23 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"hris-backend/internal/model"
 
 	"gorm.io/gorm"
 )
 
 type HolidayRepository interface {
-	Create(holiday *model.Holiday) error
-	FindByID(id string) (*model.Holiday, error)
-	FindByCompanyID(companyID string) ([]model.Holiday, error)
-	FindByCompanyIDAndYear(companyID string, year int) ([]model.Holiday, error)
-	FindAll() ([]model.Holiday, error)
-	Update(holiday *model.Holiday) error
-	Delete(id string) error
+	Create(ctx context.Context, holiday *model.Holiday) error
+	FindByID(ctx context.Context, id string) (*model.Holiday, error)
+	FindByCompanyID(ctx context.Context, companyID string) ([]model.Holiday, error)
+	FindByCompanyIDAndYear(ctx context.Context, companyID string, year int) ([]model.Holiday, error)
+	FindByCompanyIDAndDateRange(ctx context.Context, companyID string, start, end time.Time) ([]model.Holiday, error)
+	FindAll(ctx context.Context) ([]model.Holiday, error)
+	Update(ctx context.Context, holiday *model.Holiday) error
+	Delete(ctx context.Context, id string) error
 }
 
 type holidayRepository struct {
@@ -24,29 +28,29 @@ func NewHolidayRepository(db *gorm.DB) HolidayRepository {
 	return &holidayRepository{db: db}
 }
 
-func (r *holidayRepository) Create(holiday *model.Holiday) error {
-	return r.db.Create(holiday).Error
+func (r *holidayRepository) Create(ctx context.Context, holiday *model.Holiday) error {
+	return r.db.WithContext(ctx).Create(holiday).Error
 }
 
-func (r *holidayRepository) FindByID(id string) (*model.Holiday, error) {
+func (r *holidayRepository) FindByID(ctx context.Context, id string) (*model.Holiday, error) {
 	var holiday model.Holiday
-	if err := r.db.Preload("Company").First(&holiday, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Company").First(&holiday, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &holiday, nil
 }
 
-func (r *holidayRepository) FindByCompanyID(companyID string) ([]model.Holiday, error) {
+func (r *holidayRepository) FindByCompanyID(ctx context.Context, companyID string) ([]model.Holiday, error) {
 	var holidays []model.Holiday
-	if err := r.db.Preload("Company").Where("company_id = ?", companyID).Order("date ASC").Find(&holidays).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Company").Where("company_id = ?", companyID).Order("date ASC").Find(&holidays).Error; err != nil {
 		return nil, err
 	}
 	return holidays, nil
 }
 
-func (r *holidayRepository) FindByCompanyIDAndYear(companyID string, year int) ([]model.Holiday, error) {
+func (r *holidayRepository) FindByCompanyIDAndYear(ctx context.Context, companyID string, year int) ([]model.Holiday, error) {
 	var holidays []model.Holiday
-	if err := r.db.Preload("Company").
+	if err := r.db.WithContext(ctx).Preload("Company").
 		Where("company_id = ? AND EXTRACT(YEAR FROM date) = ?", companyID, year).
 		Order("date ASC").Find(&holidays).Error; err != nil {
 		return nil, err
@@ -54,19 +58,27 @@ func (r *holidayRepository) FindByCompanyIDAndYear(companyID string, year int) (
 	return holidays, nil
 }
 
-func (r *holidayRepository) FindAll() ([]model.Holiday, error) {
+func (r *holidayRepository) FindByCompanyIDAndDateRange(ctx context.Context, companyID string, start, end time.Time) ([]model.Holiday, error) {
+	var holidays []model.Holiday
+	if err := r.db.WithContext(ctx).Where("company_id = ? AND date BETWEEN ? AND ?", companyID, start, end).Order("date ASC").Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+func (r *holidayRepository) FindAll(ctx context.Context) ([]model.Holiday, error) {
 	var holidays []model.Holiday
-	if err := r.db.Preload("Company").Order("date ASC").Find(&holidays).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Company").Order("date ASC").Find(&holidays).Error; err != nil {
 		return nil, err
 	}
 	return holidays, nil
 }
 
-func (r *holidayRepository) Update(holiday *model.Holiday) error {
-	return r.db.Save(holiday).Error
+func (r *holidayRepository) Update(ctx context.Context, holiday *model.Holiday) error {
+	return r.db.WithContext(ctx).Save(holiday).Error
 }
 
-func (r *holidayRepository) Delete(id string) error {
+func (r *holidayRepository) Delete(ctx context.Context, id string) error {
 	// Holiday has no soft delete, this is a hard delete
-	return r.db.Unscoped().Delete(&model.Holiday{}, "id = ?", id).Error
+	return r.db.WithContext(ctx).Unscoped().Delete(&model.Holiday{}, "id = ?", id).Error
 }
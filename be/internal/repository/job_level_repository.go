@@ -1,18 +1,20 @@
 package repository
 
 import (
+	"context"
+
 	"hris-backend/internal/model"
 
 	"gorm.io/gorm"
 )
 
 type JobLevelRepository interface {
-	Create(jl *model.JobLevel) error
-	FindByID(id string) (*model.JobLevel, error)
-	FindAll() ([]model.JobLevel, error)
-	FindByCompanyID(companyID string) ([]model.JobLevel, error)
-	Update(jl *model.JobLevel) error
-	Delete(id string) error
+	Create(ctx context.Context, jl *model.JobLevel) error
+	FindByID(ctx context.Context, id string) (*model.JobLevel, error)
+	FindAll(ctx context.Context) ([]model.JobLevel, error)
+	FindByCompanyID(ctx context.Context, companyID string) ([]model.JobLevel, error)
+	Update(ctx context.Context, jl *model.JobLevel) error
+	Delete(ctx context.Context, id string) error
 }
 
 type jobLevelRepository struct {
@@ -23,38 +25,38 @@ func NewJobLevelRepository(db *gorm.DB) JobLevelRepository {
 	return &jobLevelRepository{db: db}
 }
 
-func (r *jobLevelRepository) Create(jl *model.JobLevel) error {
-	return r.db.Create(jl).Error
+func (r *jobLevelRepository) Create(ctx context.Context, jl *model.JobLevel) error {
+	return r.db.WithContext(ctx).Create(jl).Error
 }
 
-func (r *jobLevelRepository) FindByID(id string) (*model.JobLevel, error) {
+func (r *jobLevelRepository) FindByID(ctx context.Context, id string) (*model.JobLevel, error) {
 	var jl model.JobLevel
-	if err := r.db.First(&jl, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&jl, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &jl, nil
 }
 
-func (r *jobLevelRepository) FindAll() ([]model.JobLevel, error) {
+func (r *jobLevelRepository) FindAll(ctx context.Context) ([]model.JobLevel, error) {
 	var levels []model.JobLevel
-	if err := r.db.Order("level_order ASC, name ASC").Find(&levels).Error; err != nil {
+	if err := r.db.WithContext(ctx).Order("level_order ASC, name ASC").Find(&levels).Error; err != nil {
 		return nil, err
 	}
 	return levels, nil
 }
 
-func (r *jobLevelRepository) FindByCompanyID(companyID string) ([]model.JobLevel, error) {
+func (r *jobLevelRepository) FindByCompanyID(ctx context.Context, companyID string) ([]model.JobLevel, error) {
 	var levels []model.JobLevel
-	if err := r.db.Where("company_id = ?", companyID).Order("level_order ASC, name ASC").Find(&levels).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("company_id = ?", companyID).Order("level_order ASC, name ASC").Find(&levels).Error; err != nil {
 		return nil, err
 	}
 	return levels, nil
 }
 
-func (r *jobLevelRepository) Update(jl *model.JobLevel) error {
-	return r.db.Save(jl).Error
+func (r *jobLevelRepository) Update(ctx context.Context, jl *model.JobLevel) error {
+	return r.db.WithContext(ctx).Save(jl).Error
 }
 
-func (r *jobLevelRepository) Delete(id string) error {
-	return r.db.Delete(&model.JobLevel{}, "id = ?", id).Error
+func (r *jobLevelRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&model.JobLevel{}, "id = ?", id).Error
 }
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LeaveBalanceRepository interface {
+	FindEntitlement(ctx context.Context, employeeID string, leaveType model.LeaveType, year int) (*model.LeaveEntitlement, error)
+	FindEntitlementsByEmployeeID(ctx context.Context, employeeID string, year int) ([]model.LeaveEntitlement, error)
+	UpsertEntitlement(ctx context.Context, entitlement *model.LeaveEntitlement) error
+	CreateEntry(ctx context.Context, entry *model.LeaveLedgerEntry) error
+	CreateEntries(ctx context.Context, entries []model.LeaveLedgerEntry) (int64, error)
+	FindEntries(ctx context.Context, employeeID string, year int) ([]model.LeaveLedgerEntry, error)
+	FindEntriesByType(ctx context.Context, employeeID string, leaveType model.LeaveType, year int) ([]model.LeaveLedgerEntry, error)
+	FindEntriesByLeaveID(ctx context.Context, leaveID string) ([]model.LeaveLedgerEntry, error)
+	FindExpiringCarryOvers(ctx context.Context, employeeID string, year int) ([]model.LeaveLedgerEntry, error)
+	HasReference(ctx context.Context, employeeID string, leaveType model.LeaveType, reference string) (bool, error)
+}
+
+type leaveBalanceRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaveBalanceRepository(db *gorm.DB) LeaveBalanceRepository {
+	return &leaveBalanceRepository{db: db}
+}
+
+func (r *leaveBalanceRepository) FindEntitlement(ctx context.Context, employeeID string, leaveType model.LeaveType, year int) (*model.LeaveEntitlement, error) {
+	var entitlement model.LeaveEntitlement
+	if err := r.db.WithContext(ctx).Where("employee_id = ? AND leave_type = ? AND year = ?", employeeID, leaveType, year).First(&entitlement).Error; err != nil {
+		return nil, err
+	}
+	return &entitlement, nil
+}
+
+func (r *leaveBalanceRepository) FindEntitlementsByEmployeeID(ctx context.Context, employeeID string, year int) ([]model.LeaveEntitlement, error) {
+	var entitlements []model.LeaveEntitlement
+	if err := r.db.WithContext(ctx).Where("employee_id = ? AND year = ?", employeeID, year).Order("leave_type ASC").Find(&entitlements).Error; err != nil {
+		return nil, err
+	}
+	return entitlements, nil
+}
+
+func (r *leaveBalanceRepository) UpsertEntitlement(ctx context.Context, entitlement *model.LeaveEntitlement) error {
+	existing, err := r.FindEntitlement(ctx, entitlement.EmployeeID, entitlement.LeaveType, entitlement.Year)
+	if err == nil && existing != nil {
+		entitlement.ID = existing.ID
+		entitlement.CreatedAt = existing.CreatedAt
+		return r.db.WithContext(ctx).Omit("Employee").Save(entitlement).Error
+	}
+	return r.db.WithContext(ctx).Omit("Employee").Create(entitlement).Error
+}
+
+func (r *leaveBalanceRepository) CreateEntry(ctx context.Context, entry *model.LeaveLedgerEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// CreateEntries writes entries in one transaction and returns how many it
+// wrote. Entries whose reference the ledger has already are skipped, so
+// concurrent runs of a job post them once.
+func (r *leaveBalanceRepository) CreateEntries(ctx context.Context, entries []model.LeaveLedgerEntry) (int64, error) {
+	var created int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range entries {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entries[i])
+			if result.Error != nil {
+				return result.Error
+			}
+			created += result.RowsAffected
+		}
+		return nil
+	})
+	return created, err
+}
+
+func (r *leaveBalanceRepository) FindEntries(ctx context.Context, employeeID string, year int) ([]model.LeaveLedgerEntry, error) {
+	var entries []model.LeaveLedgerEntry
+	if err := r.db.WithContext(ctx).Where("employee_id = ? AND year = ?", employeeID, year).Order("created_at ASC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *leaveBalanceRepository) FindEntriesByType(ctx context.Context, employeeID string, leaveType model.LeaveType, year int) ([]model.LeaveLedgerEntry, error) {
+	var entries []model.LeaveLedgerEntry
+	if err := r.db.WithContext(ctx).Where("employee_id = ? AND leave_type = ? AND year = ?", employeeID, leaveType, year).Order("created_at ASC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *leaveBalanceRepository) FindEntriesByLeaveID(ctx context.Context, leaveID string) ([]model.LeaveLedgerEntry, error) {
+	var entries []model.LeaveLedgerEntry
+	if err := r.db.WithContext(ctx).Where("leave_id = ?", leaveID).Order("created_at ASC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// FindExpiringCarryOvers returns the carry-over entries of a year that have
+// an expiry date
+func (r *leaveBalanceRepository) FindExpiringCarryOvers(ctx context.Context, employeeID string, year int) ([]model.LeaveLedgerEntry, error) {
+	var entries []model.LeaveLedgerEntry
+	if err := r.db.WithContext(ctx).Where("employee_id = ? AND year = ? AND entry_type = ? AND expires_at IS NOT NULL", employeeID, year, model.LeaveLedgerCarryOver).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *leaveBalanceRepository) HasReference(ctx context.Context, employeeID string, leaveType model.LeaveType, reference string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.LeaveLedgerEntry{}).
+		Where("employee_id = ? AND leave_type = ? AND reference = ?", employeeID, leaveType, reference).
+		Count(&count).Error
+	return count > 0, err
+}
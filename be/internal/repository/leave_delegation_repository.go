@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type LeaveDelegationRepository interface {
+	Create(ctx context.Context, delegation *model.LeaveDelegation) error
+	FindByID(ctx context.Context, id string) (*model.LeaveDelegation, error)
+	FindAll(ctx context.Context) ([]model.LeaveDelegation, error)
+	FindByUserID(ctx context.Context, userID string) ([]model.LeaveDelegation, error)
+	FindActiveForDelegate(ctx context.Context, delegateID string, date time.Time) ([]model.LeaveDelegation, error)
+	FindActiveForDelegator(ctx context.Context, delegatorID string, date time.Time) ([]model.LeaveDelegation, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type leaveDelegationRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaveDelegationRepository(db *gorm.DB) LeaveDelegationRepository {
+	return &leaveDelegationRepository{db: db}
+}
+
+func (r *leaveDelegationRepository) preload(db *gorm.DB) *gorm.DB {
+	return db.Preload("Delegator").Preload("Delegate")
+}
+
+func (r *leaveDelegationRepository) Create(ctx context.Context, delegation *model.LeaveDelegation) error {
+	return r.db.WithContext(ctx).Create(delegation).Error
+}
+
+func (r *leaveDelegationRepository) FindByID(ctx context.Context, id string) (*model.LeaveDelegation, error) {
+	var delegation model.LeaveDelegation
+	if err := r.preload(r.db.WithContext(ctx)).First(&delegation, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &delegation, nil
+}
+
+func (r *leaveDelegationRepository) FindAll(ctx context.Context) ([]model.LeaveDelegation, error) {
+	var delegations []model.LeaveDelegation
+	if err := r.preload(r.db.WithContext(ctx)).Order("start_date DESC").Find(&delegations).Error; err != nil {
+		return nil, err
+	}
+	return delegations, nil
+}
+
+// FindByUserID returns the delegations a user gave or received
+func (r *leaveDelegationRepository) FindByUserID(ctx context.Context, userID string) ([]model.LeaveDelegation, error) {
+	var delegations []model.LeaveDelegation
+	if err := r.preload(r.db.WithContext(ctx)).Where("delegator_id = ? OR delegate_id = ?", userID, userID).Order("start_date DESC").Find(&delegations).Error; err != nil {
+		return nil, err
+	}
+	return delegations, nil
+}
+
+// FindActiveForDelegate returns the delegations to delegateID covering date
+func (r *leaveDelegationRepository) FindActiveForDelegate(ctx context.Context, delegateID string, date time.Time) ([]model.LeaveDelegation, error) {
+	var delegations []model.LeaveDelegation
+	day := date.Format("2006-01-02")
+	if err := r.db.WithContext(ctx).Where("delegate_id = ? AND start_date <= ? AND end_date >= ?", delegateID, day, day).Find(&delegations).Error; err != nil {
+		return nil, err
+	}
+	return delegations, nil
+}
+
+// FindActiveForDelegator returns the delegations by delegatorID covering
+// date, with their delegates
+func (r *leaveDelegationRepository) FindActiveForDelegator(ctx context.Context, delegatorID string, date time.Time) ([]model.LeaveDelegation, error) {
+	var delegations []model.LeaveDelegation
+	day := date.Format("2006-01-02")
+	if err := r.db.WithContext(ctx).Preload("Delegate").Where("delegator_id = ? AND start_date <= ? AND end_date >= ?", delegatorID, day, day).Find(&delegations).Error; err != nil {
+		return nil, err
+	}
+	return delegations, nil
+}
+
+func (r *leaveDelegationRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&model.LeaveDelegation{}, "id = ?", id).Error
+}
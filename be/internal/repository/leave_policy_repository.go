@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type LeavePolicyRepository interface {
+	Create(ctx context.Context, policy *model.LeavePolicy) error
+	FindByID(ctx context.Context, id string) (*model.LeavePolicy, error)
+	FindByCompanyID(ctx context.Context, companyID string) ([]model.LeavePolicy, error)
+	FindActiveByCompanyID(ctx context.Context, companyID string) ([]model.LeavePolicy, error)
+	FindByCompanyAndType(ctx context.Context, companyID string, leaveType model.LeaveType) (*model.LeavePolicy, error)
+	FindAll(ctx context.Context) ([]model.LeavePolicy, error)
+	Update(ctx context.Context, policy *model.LeavePolicy) error
+	Delete(ctx context.Context, id string) error
+}
+
+type leavePolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewLeavePolicyRepository(db *gorm.DB) LeavePolicyRepository {
+	return &leavePolicyRepository{db: db}
+}
+
+func (r *leavePolicyRepository) Create(ctx context.Context, policy *model.LeavePolicy) error {
+	return r.db.WithContext(ctx).Create(policy).Error
+}
+
+func (r *leavePolicyRepository) FindByID(ctx context.Context, id string) (*model.LeavePolicy, error) {
+	var policy model.LeavePolicy
+	if err := r.db.WithContext(ctx).Preload("Company").First(&policy, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *leavePolicyRepository) FindByCompanyID(ctx context.Context, companyID string) ([]model.LeavePolicy, error) {
+	var policies []model.LeavePolicy
+	if err := r.db.WithContext(ctx).Preload("Company").Where("company_id = ?", companyID).Order("leave_type ASC").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (r *leavePolicyRepository) FindActiveByCompanyID(ctx context.Context, companyID string) ([]model.LeavePolicy, error) {
+	var policies []model.LeavePolicy
+	if err := r.db.WithContext(ctx).Where("company_id = ? AND is_active = ?", companyID, true).Order("leave_type ASC").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (r *leavePolicyRepository) FindByCompanyAndType(ctx context.Context, companyID string, leaveType model.LeaveType) (*model.LeavePolicy, error) {
+	var policy model.LeavePolicy
+	if err := r.db.WithContext(ctx).Where("company_id = ? AND leave_type = ? AND is_active = ?", companyID, leaveType, true).First(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *leavePolicyRepository) FindAll(ctx context.Context) ([]model.LeavePolicy, error) {
+	var policies []model.LeavePolicy
+	if err := r.db.WithContext(ctx).Preload("Company").Order("company_id, leave_type ASC").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (r *leavePolicyRepository) Update(ctx context.Context, policy *model.LeavePolicy) error {
+	return r.db.WithContext(ctx).Omit("Company").Save(policy).Error
+}
+
+func (r *leavePolicyRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&model.LeavePolicy{}, "id = ?", id).Error
+}
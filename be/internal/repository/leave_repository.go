@@ -1,19 +1,38 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"hris-backend/internal/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrLeaveOverlaps is returned when a leave shares a day with another pending,
+// in review or approved leave of the employee
+var ErrLeaveOverlaps = errors.New("leave overlaps with an existing leave")
+
+// ErrLeaveChanged is returned when a leave was decided or cancelled by
+// someone else since it was read
+var ErrLeaveChanged = errors.New("leave request was changed by someone else, reload it and try again")
+
 type LeaveRepository interface {
-	Create(leave *model.Leave) error
-	FindByID(id string) (*model.Leave, error)
-	FindByEmployeeID(employeeID string) ([]model.Leave, error)
-	FindByStatus(status model.LeaveStatus) ([]model.Leave, error)
-	FindAll() ([]model.Leave, error)
-	Update(leave *model.Leave) error
-	Delete(id string) error
+	Create(ctx context.Context, leave *model.Leave) error
+	CreateWithApprovals(ctx context.Context, leave *model.Leave, approvals []model.LeaveApproval, events []model.OutboxEvent) error
+	FindByID(ctx context.Context, id string) (*model.Leave, error)
+	FindByEmployeeID(ctx context.Context, employeeID string) ([]model.Leave, error)
+	FindByStatus(ctx context.Context, status model.LeaveStatus) ([]model.Leave, error)
+	FindAll(ctx context.Context) ([]model.Leave, error)
+	FindAwaitingApproval(ctx context.Context) ([]model.Leave, error)
+	FindOverlapping(ctx context.Context, employeeID string, start, end time.Time, excludeID string) ([]model.Leave, error)
+	SumDaysByStatus(ctx context.Context, employeeID string, leaveType model.LeaveType, year int, statuses []model.LeaveStatus, excludeID string) (float64, error)
+	Update(ctx context.Context, leave *model.Leave) error
+	UpdateWithDecision(ctx context.Context, leave *model.Leave, from model.LeaveStatus, approvals []model.LeaveApproval, entries []model.LeaveLedgerEntry, attendances []model.Attendance, events []model.OutboxEvent) error
+	Cancel(ctx context.Context, leave *model.Leave, from model.LeaveStatus, approvals []model.LeaveApproval, entries []model.LeaveLedgerEntry, events []model.OutboxEvent) error
+	Delete(ctx context.Context, id string) error
 }
 
 type leaveRepository struct {
@@ -25,49 +44,260 @@ func NewLeaveRepository(db *gorm.DB) LeaveRepository {
 }
 
 func (r *leaveRepository) preload(db *gorm.DB) *gorm.DB {
-	return db.Preload("Employee").Preload("Employee.User").Preload("Approver")
+	return db.Preload("Employee").Preload("Employee.User").Preload("Approver").
+		Preload("Approvals", func(db *gorm.DB) *gorm.DB {
+			return db.Order("step_order ASC")
+		}).
+		Preload("Approvals.Decider")
 }
 
-func (r *leaveRepository) Create(leave *model.Leave) error {
-	return r.db.Create(leave).Error
+func (r *leaveRepository) Create(ctx context.Context, leave *model.Leave) error {
+	return r.db.WithContext(ctx).Create(leave).Error
+}
+
+// CreateWithApprovals inserts the leave, its approval chain and the events
+// announcing it in a single transaction. It returns ErrLeaveOverlaps if the
+// leave shares a day with another leave of the employee.
+func (r *leaveRepository) CreateWithApprovals(ctx context.Context, leave *model.Leave, approvals []model.LeaveApproval, events []model.OutboxEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkOverlap(tx, leave); err != nil {
+			return err
+		}
+		if err := tx.Create(leave).Error; err != nil {
+			return err
+		}
+
+		for i := range approvals {
+			approvals[i].LeaveID = leave.ID
+			if err := tx.Create(&approvals[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		return createOutboxEvents(tx, events)
+	})
 }
 
-func (r *leaveRepository) FindByID(id string) (*model.Leave, error) {
+func (r *leaveRepository) FindByID(ctx context.Context, id string) (*model.Leave, error) {
 	var leave model.Leave
-	if err := r.preload(r.db).First(&leave, "id = ?", id).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).First(&leave, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &leave, nil
 }
 
-func (r *leaveRepository) FindByEmployeeID(employeeID string) ([]model.Leave, error) {
+func (r *leaveRepository) FindByEmployeeID(ctx context.Context, employeeID string) ([]model.Leave, error) {
 	var leaves []model.Leave
-	if err := r.preload(r.db).Where("employee_id = ?", employeeID).Order("created_at DESC").Find(&leaves).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).Where("employee_id = ?", employeeID).Order("created_at DESC").Find(&leaves).Error; err != nil {
 		return nil, err
 	}
 	return leaves, nil
 }
 
-func (r *leaveRepository) FindByStatus(status model.LeaveStatus) ([]model.Leave, error) {
+func (r *leaveRepository) FindByStatus(ctx context.Context, status model.LeaveStatus) ([]model.Leave, error) {
 	var leaves []model.Leave
-	if err := r.preload(r.db).Where("status = ?", status).Order("created_at DESC").Find(&leaves).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).Where("status = ?", status).Order("created_at DESC").Find(&leaves).Error; err != nil {
 		return nil, err
 	}
 	return leaves, nil
 }
 
-func (r *leaveRepository) FindAll() ([]model.Leave, error) {
+func (r *leaveRepository) FindAll(ctx context.Context) ([]model.Leave, error) {
 	var leaves []model.Leave
-	if err := r.preload(r.db).Order("created_at DESC").Find(&leaves).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).Order("created_at DESC").Find(&leaves).Error; err != nil {
 		return nil, err
 	}
 	return leaves, nil
 }
 
-func (r *leaveRepository) Update(leave *model.Leave) error {
-	return r.db.Save(leave).Error
+// FindAwaitingApproval returns the leaves whose approval chain is not yet
+// complete
+func (r *leaveRepository) FindAwaitingApproval(ctx context.Context) ([]model.Leave, error) {
+	var leaves []model.Leave
+	if err := r.preload(r.db.WithContext(ctx)).Where("status IN ?", []model.LeaveStatus{model.LeaveStatusPending, model.LeaveStatusInReview}).Order("created_at ASC").Find(&leaves).Error; err != nil {
+		return nil, err
+	}
+	return leaves, nil
 }
 
-func (r *leaveRepository) Delete(id string) error {
-	return r.db.Delete(&model.Leave{}, "id = ?", id).Error
+// FindOverlapping returns the employee's pending, in review and approved
+// leaves that share at least one day with the given period, ignoring excludeID
+func (r *leaveRepository) FindOverlapping(ctx context.Context, employeeID string, start, end time.Time, excludeID string) ([]model.Leave, error) {
+	var leaves []model.Leave
+	query := r.db.WithContext(ctx).Where("employee_id = ? AND status IN ?", employeeID, []model.LeaveStatus{model.LeaveStatusPending, model.LeaveStatusInReview, model.LeaveStatusApproved}).
+		Where("start_date <= ? AND end_date >= ?", end, start)
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Order("start_date ASC").Find(&leaves).Error; err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+// SumDaysByStatus totals the days of an employee's leaves of one type and
+// one of the given statuses that start in the given year, ignoring excludeID
+func (r *leaveRepository) SumDaysByStatus(ctx context.Context, employeeID string, leaveType model.LeaveType, year int, statuses []model.LeaveStatus, excludeID string) (float64, error) {
+	var total float64
+	query := r.db.WithContext(ctx).Model(&model.Leave{}).
+		Where("employee_id = ? AND leave_type = ? AND status IN ?", employeeID, leaveType, statuses).
+		Where("EXTRACT(YEAR FROM start_date) = ?", year)
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+	err := query.Select("COALESCE(SUM(total_days), 0)").Scan(&total).Error
+	return total, err
+}
+
+// Update saves a leave, returning ErrLeaveOverlaps if it now shares a day
+// with another leave of the employee
+func (r *leaveRepository) Update(ctx context.Context, leave *model.Leave) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkOverlap(tx, leave); err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Save(leave).Error
+	})
+}
+
+// UpdateWithDecision saves the leave, the approval steps touched by a
+// decision, the resulting balance ledger entries, the attendance rows written
+// for the leave and the events announcing the decision in a single
+// transaction. The decided step comes first in approvals. It returns
+// ErrLeaveChanged, and writes nothing, unless the leave still has status from
+// and the decided step is still pending.
+func (r *leaveRepository) UpdateWithDecision(ctx context.Context, leave *model.Leave, from model.LeaveStatus, approvals []model.LeaveApproval, entries []model.LeaveLedgerEntry, attendances []model.Attendance, events []model.OutboxEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockLeave(tx, leave.ID, from); err != nil {
+			return err
+		}
+		if len(approvals) > 0 {
+			var step model.LeaveApproval
+			if err := tx.Select("status").First(&step, "id = ?", approvals[0].ID).Error; err != nil {
+				return err
+			}
+			if step.Status != model.LeaveApprovalPending {
+				return ErrLeaveChanged
+			}
+		}
+
+		if err := tx.Omit(clause.Associations).Save(leave).Error; err != nil {
+			return err
+		}
+
+		for i := range approvals {
+			if err := tx.Omit(clause.Associations).Save(&approvals[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range entries {
+			if err := tx.Create(&entries[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range attendances {
+			if err := tx.Omit(clause.Associations).Save(&attendances[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		return createOutboxEvents(tx, events)
+	})
+}
+
+// Cancel saves the cancelled leave, its skipped approval steps, the ledger
+// reversal and the events announcing the cancellation, and reverts the
+// attendance rows the leave wrote: rows it created are deleted and rows it
+// replaced get their previous status back. It returns ErrLeaveChanged, and
+// writes nothing, unless the leave still has status from.
+func (r *leaveRepository) Cancel(ctx context.Context, leave *model.Leave, from model.LeaveStatus, approvals []model.LeaveApproval, entries []model.LeaveLedgerEntry, events []model.OutboxEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockLeave(tx, leave.ID, from); err != nil {
+			return err
+		}
+
+		if err := tx.Omit(clause.Associations).Save(leave).Error; err != nil {
+			return err
+		}
+
+		for i := range approvals {
+			if err := tx.Omit(clause.Associations).Save(&approvals[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range entries {
+			if err := tx.Create(&entries[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := createOutboxEvents(tx, events); err != nil {
+			return err
+		}
+
+		if err := tx.Where("leave_id = ? AND (status_before_leave IS NULL OR status_before_leave = '')", leave.ID).
+			Delete(&model.Attendance{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.Attendance{}).
+			Where("leave_id = ?", leave.ID).
+			Updates(map[string]interface{}{
+				"status":              gorm.Expr("status_before_leave"),
+				"leave_id":            nil,
+				"status_before_leave": "",
+			}).Error
+	})
+}
+
+func (r *leaveRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("leave_id = ?", id).Delete(&model.LeaveApproval{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Leave{}, "id = ?", id).Error
+	})
+}
+
+// lockLeave locks a leave row until the transaction ends, so decisions and
+// cancellations of the leave take turns, and returns ErrLeaveChanged unless
+// its status is still from
+func lockLeave(tx *gorm.DB, id string, from model.LeaveStatus) error {
+	var current model.Leave
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status").First(&current, "id = ?", id).Error; err != nil {
+		return err
+	}
+	if current.Status != from {
+		return ErrLeaveChanged
+	}
+	return nil
+}
+
+// checkOverlap returns ErrLeaveOverlaps if a leave shares a day with another
+// pending, in review or approved leave of the employee. The employee row is
+// locked until the transaction ends so that two requests of the same
+// employee cannot both pass the check.
+func checkOverlap(tx *gorm.DB, leave *model.Leave) error {
+	var emp model.Employee
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&emp, "id = ?", leave.EmployeeID).Error; err != nil {
+		return err
+	}
+
+	query := tx.Model(&model.Leave{}).
+		Where("employee_id = ? AND status IN ?", leave.EmployeeID, []model.LeaveStatus{model.LeaveStatusPending, model.LeaveStatusInReview, model.LeaveStatusApproved}).
+		Where("start_date <= ? AND end_date >= ?", leave.EndDate, leave.StartDate)
+	if leave.ID != "" {
+		query = query.Where("id <> ?", leave.ID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrLeaveOverlaps
+	}
+	return nil
 }
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type LeaveWorkflowRepository interface {
+	Create(ctx context.Context, workflow *model.LeaveWorkflow) error
+	FindByID(ctx context.Context, id string) (*model.LeaveWorkflow, error)
+	FindAll(ctx context.Context) ([]model.LeaveWorkflow, error)
+	FindByCompanyID(ctx context.Context, companyID string) ([]model.LeaveWorkflow, error)
+	FindByScope(ctx context.Context, companyID string, departmentID *string) (*model.LeaveWorkflow, error)
+	FindApplicable(ctx context.Context, companyID, departmentID string) (*model.LeaveWorkflow, error)
+	UpdateWithSteps(ctx context.Context, workflow *model.LeaveWorkflow, steps []model.LeaveWorkflowStep) error
+	Delete(ctx context.Context, id string) error
+}
+
+type leaveWorkflowRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaveWorkflowRepository(db *gorm.DB) LeaveWorkflowRepository {
+	return &leaveWorkflowRepository{db: db}
+}
+
+func (r *leaveWorkflowRepository) preload(db *gorm.DB) *gorm.DB {
+	return db.Preload("Company").Preload("Department").Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("step_order ASC")
+	})
+}
+
+// Create inserts the workflow together with its steps
+func (r *leaveWorkflowRepository) Create(ctx context.Context, workflow *model.LeaveWorkflow) error {
+	return r.db.WithContext(ctx).Create(workflow).Error
+}
+
+func (r *leaveWorkflowRepository) FindByID(ctx context.Context, id string) (*model.LeaveWorkflow, error) {
+	var workflow model.LeaveWorkflow
+	if err := r.preload(r.db.WithContext(ctx)).First(&workflow, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &workflow, nil
+}
+
+func (r *leaveWorkflowRepository) FindAll(ctx context.Context) ([]model.LeaveWorkflow, error) {
+	var workflows []model.LeaveWorkflow
+	if err := r.preload(r.db.WithContext(ctx)).Order("company_id, department_id NULLS FIRST, name").Find(&workflows).Error; err != nil {
+		return nil, err
+	}
+	return workflows, nil
+}
+
+func (r *leaveWorkflowRepository) FindByCompanyID(ctx context.Context, companyID string) ([]model.LeaveWorkflow, error) {
+	var workflows []model.LeaveWorkflow
+	if err := r.preload(r.db.WithContext(ctx)).Where("company_id = ?", companyID).Order("department_id NULLS FIRST, name").Find(&workflows).Error; err != nil {
+		return nil, err
+	}
+	return workflows, nil
+}
+
+// FindByScope returns the workflow of a company (departmentID nil) or of one
+// of its departments, active or not. It returns nil when none exists.
+func (r *leaveWorkflowRepository) FindByScope(ctx context.Context, companyID string, departmentID *string) (*model.LeaveWorkflow, error) {
+	query := r.db.WithContext(ctx).Where("company_id = ?", companyID)
+	if departmentID == nil {
+		query = query.Where("department_id IS NULL")
+	} else {
+		query = query.Where("department_id = ?", *departmentID)
+	}
+
+	var workflows []model.LeaveWorkflow
+	if err := query.Limit(1).Find(&workflows).Error; err != nil {
+		return nil, err
+	}
+	if len(workflows) == 0 {
+		return nil, nil
+	}
+	return &workflows[0], nil
+}
+
+// FindApplicable returns the active workflow for an employee's department,
+// falling back to the active company default. It returns nil when neither
+// exists.
+func (r *leaveWorkflowRepository) FindApplicable(ctx context.Context, companyID, departmentID string) (*model.LeaveWorkflow, error) {
+	var workflows []model.LeaveWorkflow
+	err := r.preload(r.db.WithContext(ctx)).
+		Where("company_id = ? AND is_active = ?", companyID, true).
+		Where("department_id = ? OR department_id IS NULL", departmentID).
+		Order("department_id NULLS LAST").
+		Limit(1).
+		Find(&workflows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(workflows) == 0 {
+		return nil, nil
+	}
+	return &workflows[0], nil
+}
+
+// UpdateWithSteps saves the workflow and, when steps is not nil, replaces its
+// steps in a single transaction
+func (r *leaveWorkflowRepository) UpdateWithSteps(ctx context.Context, workflow *model.LeaveWorkflow, steps []model.LeaveWorkflowStep) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Company", "Department", "Steps").Save(workflow).Error; err != nil {
+			return err
+		}
+
+		if steps == nil {
+			return nil
+		}
+
+		if err := tx.Where("workflow_id = ?", workflow.ID).Delete(&model.LeaveWorkflowStep{}).Error; err != nil {
+			return err
+		}
+
+		for i := range steps {
+			steps[i].WorkflowID = workflow.ID
+			if err := tx.Create(&steps[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *leaveWorkflowRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("workflow_id = ?", id).Delete(&model.LeaveWorkflowStep{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.LeaveWorkflow{}, "id = ?", id).Error
+	})
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type LoginAttemptRepository interface {
+	Create(ctx context.Context, attempt *model.LoginAttempt) error
+	CountFailuresByIP(ctx context.Context, ipAddress string, since time.Time) (int64, error)
+	FindAllPaginated(ctx context.Context, page, limit int, userID, email, ipAddress, result, startDate, endDate string) ([]model.LoginAttempt, int64, error)
+}
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+func (r *loginAttemptRepository) Create(ctx context.Context, attempt *model.LoginAttempt) error {
+	return r.db.WithContext(ctx).Omit("User").Create(attempt).Error
+}
+
+func (r *loginAttemptRepository) CountFailuresByIP(ctx context.Context, ipAddress string, since time.Time) (int64, error) {
+	failures := make([]string, len(model.LoginFailureResults))
+	for i, result := range model.LoginFailureResults {
+		failures[i] = string(result)
+	}
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.LoginAttempt{}).
+		Where("ip_address = ? AND created_at > ? AND result IN ?", ipAddress, since, failures).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *loginAttemptRepository) FindAllPaginated(ctx context.Context, page, limit int, userID, email, ipAddress, result, startDate, endDate string) ([]model.LoginAttempt, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.LoginAttempt{})
+
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if email != "" {
+		query = query.Where("email ILIKE ?", "%"+email+"%")
+	}
+	if ipAddress != "" {
+		query = query.Where("ip_address = ?", ipAddress)
+	}
+	if result != "" {
+		query = query.Where("result = ?", result)
+	}
+	if startDate != "" {
+		query = query.Where("created_at >= ?", startDate)
+	}
+	if endDate != "" {
+		query = query.Where("created_at < ?::date + 1", endDate)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var attempts []model.LoginAttempt
+	offset := (page - 1) * limit
+	if err := query.Preload("User").Order("created_at DESC").Limit(limit).Offset(offset).Find(&attempts).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return attempts, total, nil
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"hris-backend/internal/model"
 
 	"github.com/google/uuid"
@@ -8,10 +10,10 @@ import (
 )
 
 type MenuAccessRepository interface {
-	FindByUserID(userID string) ([]model.MenuAccess, error)
-	FindAllWithUsers() ([]model.MenuAccess, error)
-	ReplaceForUser(userID string, menuKeys []string) error
-	DeleteByUserID(userID string) error
+	FindByUserID(ctx context.Context, userID string) ([]model.MenuAccess, error)
+	FindAllWithUsers(ctx context.Context) ([]model.MenuAccess, error)
+	ReplaceForUser(ctx context.Context, userID string, menuKeys []string) error
+	DeleteByUserID(ctx context.Context, userID string) error
 }
 
 type menuAccessRepository struct {
@@ -22,24 +24,24 @@ func NewMenuAccessRepository(db *gorm.DB) MenuAccessRepository {
 	return &menuAccessRepository{db: db}
 }
 
-func (r *menuAccessRepository) FindByUserID(userID string) ([]model.MenuAccess, error) {
+func (r *menuAccessRepository) FindByUserID(ctx context.Context, userID string) ([]model.MenuAccess, error) {
 	var accesses []model.MenuAccess
-	if err := r.db.Where("user_id = ?", userID).Find(&accesses).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&accesses).Error; err != nil {
 		return nil, err
 	}
 	return accesses, nil
 }
 
-func (r *menuAccessRepository) FindAllWithUsers() ([]model.MenuAccess, error) {
+func (r *menuAccessRepository) FindAllWithUsers(ctx context.Context) ([]model.MenuAccess, error) {
 	var accesses []model.MenuAccess
-	if err := r.db.Preload("User").Order("user_id, menu_key").Find(&accesses).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("User").Order("user_id, menu_key").Find(&accesses).Error; err != nil {
 		return nil, err
 	}
 	return accesses, nil
 }
 
-func (r *menuAccessRepository) ReplaceForUser(userID string, menuKeys []string) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+func (r *menuAccessRepository) ReplaceForUser(ctx context.Context, userID string, menuKeys []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Delete existing menu access for user
 		if err := tx.Where("user_id = ?", userID).Delete(&model.MenuAccess{}).Error; err != nil {
 			return err
@@ -61,6 +63,6 @@ func (r *menuAccessRepository) ReplaceForUser(userID string, menuKeys []string)
 	})
 }
 
-func (r *menuAccessRepository) DeleteByUserID(userID string) error {
-	return r.db.Where("user_id = ?", userID).Delete(&model.MenuAccess{}).Error
+func (r *menuAccessRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.MenuAccess{}).Error
 }
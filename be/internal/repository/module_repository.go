@@ -1,15 +1,17 @@
 package repository
 
 import (
+	"context"
+
 	"hris-backend/internal/model"
 
 	"gorm.io/gorm"
 )
 
 type ModuleRepository interface {
-	FindAll() ([]model.Module, error)
-	FindByKey(key string) (*model.Module, error)
-	UpsertMany(modules []model.Module) error
+	FindAll(ctx context.Context) ([]model.Module, error)
+	FindByKey(ctx context.Context, key string) (*model.Module, error)
+	UpsertMany(ctx context.Context, modules []model.Module) error
 }
 
 type moduleRepository struct {
@@ -20,24 +22,24 @@ func NewModuleRepository(db *gorm.DB) ModuleRepository {
 	return &moduleRepository{db}
 }
 
-func (r *moduleRepository) FindAll() ([]model.Module, error) {
+func (r *moduleRepository) FindAll(ctx context.Context) ([]model.Module, error) {
 	var modules []model.Module
-	err := r.db.Order("is_core DESC, category ASC, name ASC").Find(&modules).Error
+	err := r.db.WithContext(ctx).Order("is_core DESC, category ASC, name ASC").Find(&modules).Error
 	return modules, err
 }
 
-func (r *moduleRepository) FindByKey(key string) (*model.Module, error) {
+func (r *moduleRepository) FindByKey(ctx context.Context, key string) (*model.Module, error) {
 	var m model.Module
-	if err := r.db.Where("key = ?", key).First(&m).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("key = ?", key).First(&m).Error; err != nil {
 		return nil, err
 	}
 	return &m, nil
 }
 
 // UpsertMany inserts or updates all provided modules (by key). Used for seeding from registry.
-func (r *moduleRepository) UpsertMany(modules []model.Module) error {
+func (r *moduleRepository) UpsertMany(ctx context.Context, modules []model.Module) error {
 	for i := range modules {
-		if err := r.db.Save(&modules[i]).Error; err != nil {
+		if err := r.db.WithContext(ctx).Save(&modules[i]).Error; err != nil {
 			return err
 		}
 	}
@@ -45,10 +47,10 @@ func (r *moduleRepository) UpsertMany(modules []model.Module) error {
 }
 
 type CompanyModuleRepository interface {
-	FindByCompanyID(companyID string) ([]model.CompanyModule, error)
-	FindByCompanyAndKey(companyID, moduleKey string) (*model.CompanyModule, error)
-	EnabledKeysForCompany(companyID string) ([]string, error)
-	Upsert(cm *model.CompanyModule) error
+	FindByCompanyID(ctx context.Context, companyID string) ([]model.CompanyModule, error)
+	FindByCompanyAndKey(ctx context.Context, companyID, moduleKey string) (*model.CompanyModule, error)
+	EnabledKeysForCompany(ctx context.Context, companyID string) ([]string, error)
+	Upsert(ctx context.Context, cm *model.CompanyModule, events []model.OutboxEvent) error
 }
 
 type companyModuleRepository struct {
@@ -59,35 +61,43 @@ func NewCompanyModuleRepository(db *gorm.DB) CompanyModuleRepository {
 	return &companyModuleRepository{db}
 }
 
-func (r *companyModuleRepository) FindByCompanyID(companyID string) ([]model.CompanyModule, error) {
+func (r *companyModuleRepository) FindByCompanyID(ctx context.Context, companyID string) ([]model.CompanyModule, error) {
 	var rows []model.CompanyModule
-	err := r.db.Preload("Module").Where("company_id = ?", companyID).Find(&rows).Error
+	err := r.db.WithContext(ctx).Preload("Module").Where("company_id = ?", companyID).Find(&rows).Error
 	return rows, err
 }
 
-func (r *companyModuleRepository) FindByCompanyAndKey(companyID, moduleKey string) (*model.CompanyModule, error) {
+func (r *companyModuleRepository) FindByCompanyAndKey(ctx context.Context, companyID, moduleKey string) (*model.CompanyModule, error) {
 	var cm model.CompanyModule
-	if err := r.db.Where("company_id = ? AND module_key = ?", companyID, moduleKey).First(&cm).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("company_id = ? AND module_key = ?", companyID, moduleKey).First(&cm).Error; err != nil {
 		return nil, err
 	}
 	return &cm, nil
 }
 
 // EnabledKeysForCompany returns just the module keys where enabled = true.
-func (r *companyModuleRepository) EnabledKeysForCompany(companyID string) ([]string, error) {
+func (r *companyModuleRepository) EnabledKeysForCompany(ctx context.Context, companyID string) ([]string, error) {
 	var keys []string
-	err := r.db.Model(&model.CompanyModule{}).
+	err := r.db.WithContext(ctx).Model(&model.CompanyModule{}).
 		Where("company_id = ? AND enabled = ?", companyID, true).
 		Pluck("module_key", &keys).Error
 	return keys, err
 }
 
-func (r *companyModuleRepository) Upsert(cm *model.CompanyModule) error {
+// Upsert saves the row of a company and module together with the events
+// announcing the change
+func (r *companyModuleRepository) Upsert(ctx context.Context, cm *model.CompanyModule, events []model.OutboxEvent) error {
 	// Check if row exists by (company_id, module_key)
-	existing, err := r.FindByCompanyAndKey(cm.CompanyID, cm.ModuleKey)
-	if err == nil && existing != nil {
-		cm.ID = existing.ID
-		return r.db.Save(cm).Error
-	}
-	return r.db.Create(cm).Error
+	existing, err := r.FindByCompanyAndKey(ctx, cm.CompanyID, cm.ModuleKey)
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err == nil && existing != nil {
+			cm.ID = existing.ID
+			if err := tx.Save(cm).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Create(cm).Error; err != nil {
+			return err
+		}
+		return createOutboxEvents(tx, events)
+	})
 }
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errAlreadyDigested rolls back a digest whose items another instance sent
+// first
+var errAlreadyDigested = errors.New("digest items were already digested")
+
+type NotificationDigestRepository interface {
+	Create(ctx context.Context, item *model.NotificationDigestItem) error
+	FindPendingUserIDs(ctx context.Context, before time.Time) ([]string, error)
+	FindPending(ctx context.Context, userID string, before time.Time) ([]model.NotificationDigestItem, error)
+	MarkDigested(ctx context.Context, ids []string, events []model.OutboxEvent) (bool, error)
+}
+
+type notificationDigestRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationDigestRepository(db *gorm.DB) NotificationDigestRepository {
+	return &notificationDigestRepository{db: db}
+}
+
+// Create holds an item back for the digest, unless the user has one for the
+// same event already
+func (r *notificationDigestRepository) Create(ctx context.Context, item *model.NotificationDigestItem) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "event_id"}, {Name: "user_id"}},
+			DoNothing: true,
+		}).
+		Create(item).Error
+}
+
+// FindPendingUserIDs returns the users with items created before the given
+// time that were not digested yet
+func (r *notificationDigestRepository) FindPendingUserIDs(ctx context.Context, before time.Time) ([]string, error) {
+	var userIDs []string
+	err := r.db.WithContext(ctx).Model(&model.NotificationDigestItem{}).
+		Where("digested_at IS NULL AND created_at < ?", before).
+		Distinct().Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// FindPending returns the items of a user created before the given time that
+// were not digested yet, oldest first
+func (r *notificationDigestRepository) FindPending(ctx context.Context, userID string, before time.Time) ([]model.NotificationDigestItem, error) {
+	var items []model.NotificationDigestItem
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND digested_at IS NULL AND created_at < ?", userID, before).
+		Order("created_at ASC").
+		Find(&items).Error
+	return items, err
+}
+
+// MarkDigested marks items digested and writes the outbox events that
+// deliver the digest, in one transaction. It writes nothing and returns false
+// if any of them was digested already, so concurrent schedulers do not send
+// an item twice.
+func (r *notificationDigestRepository) MarkDigested(ctx context.Context, ids []string, events []model.OutboxEvent) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.NotificationDigestItem{}).
+			Where("id IN ? AND digested_at IS NULL", ids).
+			Update("digested_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != int64(len(ids)) {
+			return errAlreadyDigested
+		}
+		return createOutboxEvents(tx, events)
+	})
+	if errors.Is(err, errAlreadyDigested) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationPreferenceRepository interface {
+	FindByUserID(ctx context.Context, userID string) ([]model.NotificationPreference, error)
+	FindMode(ctx context.Context, userID, eventType string) (model.NotificationMode, error)
+	Upsert(ctx context.Context, prefs []model.NotificationPreference) error
+}
+
+type notificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationPreferenceRepository(db *gorm.DB) NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{db: db}
+}
+
+func (r *notificationPreferenceRepository) FindByUserID(ctx context.Context, userID string) ([]model.NotificationPreference, error) {
+	var prefs []model.NotificationPreference
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("event_type").Find(&prefs).Error
+	return prefs, err
+}
+
+// FindMode returns the mode a user chose for an event type, or "" when they
+// did not choose one
+func (r *notificationPreferenceRepository) FindMode(ctx context.Context, userID, eventType string) (model.NotificationMode, error) {
+	var prefs []model.NotificationPreference
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND event_type = ?", userID, eventType).
+		Limit(1).Find(&prefs).Error; err != nil {
+		return "", err
+	}
+	if len(prefs) == 0 {
+		return "", nil
+	}
+	return prefs[0].Mode, nil
+}
+
+// Upsert sets the modes of the given preferences, creating those the user
+// does not have yet
+func (r *notificationPreferenceRepository) Upsert(ctx context.Context, prefs []model.NotificationPreference) error {
+	if len(prefs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "event_type"}},
+			DoUpdates: clause.AssignmentColumns([]string{"mode", "updated_at"}),
+		}).
+		Create(&prefs).Error
+}
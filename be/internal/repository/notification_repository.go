@@ -1,18 +1,38 @@
 package repository
 
 import (
+	"context"
+	"encoding/json"
+
 	"hris-backend/internal/model"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// NotificationChannel is the Postgres channel new notifications are
+// announced on, with a NotificationCreated payload
+const NotificationChannel = "notifications_created"
+
+// NotificationCreated announces a new notification. It carries the IDs only:
+// a notification may not fit in a Postgres notification payload.
+type NotificationCreated struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+}
+
 type NotificationRepository interface {
-	Create(n *model.Notification) error
-	FindByUserID(userID string, limit int) ([]model.Notification, error)
-	CountUnread(userID string) (int64, error)
-	MarkAsRead(id string, userID string) error
-	MarkAllAsRead(userID string) error
+	Create(ctx context.Context, n *model.Notification) error
+	FindByID(ctx context.Context, id string) (*model.Notification, error)
+	FindAfter(ctx context.Context, userID, afterID string, limit int) ([]model.Notification, error)
+	FindPage(ctx context.Context, userID, cursor string, limit int, notifType, refType string, isRead *bool, archived bool) ([]model.Notification, error)
+	CountUnread(ctx context.Context, userID string) (int64, error)
+	MarkAsRead(ctx context.Context, id string, userID string) error
+	MarkAllAsRead(ctx context.Context, userID string) error
+	SetArchived(ctx context.Context, userID string, ids []string, archived bool) (int64, error)
+	Delete(ctx context.Context, userID string, ids []string) (int64, error)
+	PurgeBefore(ctx context.Context, before time.Time, limit int) (int64, error)
 }
 
 type notificationRepository struct {
@@ -23,33 +43,104 @@ func NewNotificationRepository(db *gorm.DB) NotificationRepository {
 	return &notificationRepository{db: db}
 }
 
-func (r *notificationRepository) Create(n *model.Notification) error {
-	return r.db.Create(n).Error
+// Create inserts a notification, skipping it when the user already has one
+// for the same event; n then takes the ID of that one. A new notification is
+// announced on NotificationChannel when it commits, so every instance can
+// push it to the user.
+func (r *notificationRepository) Create(ctx context.Context, n *model.Notification) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "event_id"}, {Name: "user_id"}},
+			DoNothing: true,
+		}).Create(n)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return tx.Model(&model.Notification{}).
+				Where("event_id = ? AND user_id = ?", n.EventID, n.UserID).
+				Select("id").Scan(&n.ID).Error
+		}
+
+		payload, err := json.Marshal(NotificationCreated{ID: n.ID, UserID: n.UserID})
+		if err != nil {
+			return err
+		}
+		return tx.Exec("SELECT pg_notify(?, ?)", NotificationChannel, string(payload)).Error
+	})
+}
+
+func (r *notificationRepository) FindByID(ctx context.Context, id string) (*model.Notification, error) {
+	var n model.Notification
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&n).Error; err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// FindAfter returns the notifications of a user created after the one with
+// afterID, oldest first. When there are more than limit, it returns the
+// latest limit of them. It returns none when afterID is not the user's.
+func (r *notificationRepository) FindAfter(ctx context.Context, userID, afterID string, limit int) ([]model.Notification, error) {
+	var notifications []model.Notification
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("(created_at, id) > (SELECT created_at, id FROM notifications WHERE id = ? AND user_id = ?)", afterID, userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(notifications)-1; i < j; i, j = i+1, j-1 {
+		notifications[i], notifications[j] = notifications[j], notifications[i]
+	}
+	return notifications, nil
 }
 
-func (r *notificationRepository) FindByUserID(userID string, limit int) ([]model.Notification, error) {
+// FindPage returns a page of the notifications of a user, newest first,
+// starting after the one with ID cursor when it is set. notifType, refType
+// and isRead filter when set; archived picks the archive over the inbox.
+func (r *notificationRepository) FindPage(ctx context.Context, userID, cursor string, limit int, notifType, refType string, isRead *bool, archived bool) ([]model.Notification, error) {
 	var notifications []model.Notification
-	q := r.db.Where("user_id = ?", userID).Order("created_at DESC")
-	if limit > 0 {
-		q = q.Limit(limit)
+	q := r.db.WithContext(ctx).Where("user_id = ?", userID)
+
+	if cursor != "" {
+		q = q.Where("(created_at, id) < (SELECT created_at, id FROM notifications WHERE id = ? AND user_id = ?)", cursor, userID)
+	}
+	if notifType != "" {
+		q = q.Where("type = ?", notifType)
+	}
+	if refType != "" {
+		q = q.Where("ref_type = ?", refType)
 	}
-	if err := q.Find(&notifications).Error; err != nil {
+	if isRead != nil {
+		q = q.Where("is_read = ?", *isRead)
+	}
+	if archived {
+		q = q.Where("archived_at IS NOT NULL")
+	} else {
+		q = q.Where("archived_at IS NULL")
+	}
+
+	if err := q.Order("created_at DESC, id DESC").Limit(limit).Find(&notifications).Error; err != nil {
 		return nil, err
 	}
 	return notifications, nil
 }
 
-func (r *notificationRepository) CountUnread(userID string) (int64, error) {
+func (r *notificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
 	var count int64
-	err := r.db.Model(&model.Notification{}).
-		Where("user_id = ? AND is_read = false", userID).
+	err := r.db.WithContext(ctx).Model(&model.Notification{}).
+		Where("user_id = ? AND is_read = false AND archived_at IS NULL", userID).
 		Count(&count).Error
 	return count, err
 }
 
-func (r *notificationRepository) MarkAsRead(id string, userID string) error {
+func (r *notificationRepository) MarkAsRead(ctx context.Context, id string, userID string) error {
 	now := time.Now()
-	return r.db.Model(&model.Notification{}).
+	return r.db.WithContext(ctx).Model(&model.Notification{}).
 		Where("id = ? AND user_id = ?", id, userID).
 		Updates(map[string]any{
 			"is_read": true,
@@ -57,12 +148,49 @@ func (r *notificationRepository) MarkAsRead(id string, userID string) error {
 		}).Error
 }
 
-func (r *notificationRepository) MarkAllAsRead(userID string) error {
+func (r *notificationRepository) MarkAllAsRead(ctx context.Context, userID string) error {
 	now := time.Now()
-	return r.db.Model(&model.Notification{}).
+	return r.db.WithContext(ctx).Model(&model.Notification{}).
 		Where("user_id = ? AND is_read = false", userID).
 		Updates(map[string]any{
 			"is_read": true,
 			"read_at": now,
 		}).Error
 }
+
+// SetArchived moves the listed notifications of a user to the archive, or
+// back to the inbox, and returns how many it moved. IDs that are not the
+// user's are ignored.
+func (r *notificationRepository) SetArchived(ctx context.Context, userID string, ids []string, archived bool) (int64, error) {
+	q := r.db.WithContext(ctx).Model(&model.Notification{}).Where("user_id = ? AND id IN ?", userID, ids)
+
+	var result *gorm.DB
+	if archived {
+		result = q.Where("archived_at IS NULL").Update("archived_at", time.Now())
+	} else {
+		result = q.Where("archived_at IS NOT NULL").Update("archived_at", nil)
+	}
+	return result.RowsAffected, result.Error
+}
+
+// Delete deletes the listed notifications of a user and returns how many it
+// deleted. IDs that are not the user's are ignored.
+func (r *notificationRepository) Delete(ctx context.Context, userID string, ids []string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Delete(&model.Notification{})
+	return result.RowsAffected, result.Error
+}
+
+// PurgeBefore removes for good, at most limit at a time, the read
+// notifications created before the given time and the ones deleted before
+// it. It returns how many it removed.
+func (r *notificationRepository) PurgeBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		DELETE FROM notifications WHERE id IN (
+			SELECT id FROM notifications
+			WHERE (is_read = true AND created_at < ?) OR deleted_at < ?
+			LIMIT ?
+		)`, before, before, limit)
+	return result.RowsAffected, result.Error
+}
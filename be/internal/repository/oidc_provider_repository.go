@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type OIDCProviderRepository interface {
+	Create(ctx context.Context, provider *model.OIDCProvider) error
+	FindByID(ctx context.Context, id string) (*model.OIDCProvider, error)
+	FindBySlug(ctx context.Context, slug string) (*model.OIDCProvider, error)
+	FindAll(ctx context.Context) ([]model.OIDCProvider, error)
+	FindActive(ctx context.Context) ([]model.OIDCProvider, error)
+	Update(ctx context.Context, provider *model.OIDCProvider) error
+	Delete(ctx context.Context, id string) error
+	CreateState(ctx context.Context, state *model.OIDCLoginState) error
+	ConsumeState(ctx context.Context, stateHash string) (*model.OIDCLoginState, error)
+	DeleteExpiredStates(ctx context.Context) error
+	FindIdentity(ctx context.Context, providerID, subject string) (*model.UserIdentity, error)
+	CreateIdentity(ctx context.Context, identity *model.UserIdentity) error
+}
+
+type oidcProviderRepository struct {
+	db *gorm.DB
+}
+
+func NewOIDCProviderRepository(db *gorm.DB) OIDCProviderRepository {
+	return &oidcProviderRepository{db: db}
+}
+
+func (r *oidcProviderRepository) Create(ctx context.Context, provider *model.OIDCProvider) error {
+	return r.db.WithContext(ctx).Omit("Company").Create(provider).Error
+}
+
+func (r *oidcProviderRepository) FindByID(ctx context.Context, id string) (*model.OIDCProvider, error) {
+	var provider model.OIDCProvider
+	if err := r.db.WithContext(ctx).Preload("Company").First(&provider, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &provider, nil
+}
+
+func (r *oidcProviderRepository) FindBySlug(ctx context.Context, slug string) (*model.OIDCProvider, error) {
+	var provider model.OIDCProvider
+	if err := r.db.WithContext(ctx).First(&provider, "slug = ?", slug).Error; err != nil {
+		return nil, err
+	}
+	return &provider, nil
+}
+
+func (r *oidcProviderRepository) FindAll(ctx context.Context) ([]model.OIDCProvider, error) {
+	var providers []model.OIDCProvider
+	if err := r.db.WithContext(ctx).Preload("Company").Order("company_id, name").Find(&providers).Error; err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
+
+func (r *oidcProviderRepository) FindActive(ctx context.Context) ([]model.OIDCProvider, error) {
+	var providers []model.OIDCProvider
+	if err := r.db.WithContext(ctx).Where("is_active = ?", true).Order("name").Find(&providers).Error; err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
+
+func (r *oidcProviderRepository) Update(ctx context.Context, provider *model.OIDCProvider) error {
+	return r.db.WithContext(ctx).Omit("Company").Save(provider).Error
+}
+
+func (r *oidcProviderRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("provider_id = ?", id).Delete(&model.OIDCLoginState{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("provider_id = ?", id).Delete(&model.UserIdentity{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.OIDCProvider{}, "id = ?", id).Error
+	})
+}
+
+func (r *oidcProviderRepository) CreateState(ctx context.Context, state *model.OIDCLoginState) error {
+	return r.db.WithContext(ctx).Omit("Provider").Create(state).Error
+}
+
+// ConsumeState deletes the login state and returns it. A state can be
+// consumed once, so a replayed callback finds nothing.
+func (r *oidcProviderRepository) ConsumeState(ctx context.Context, stateHash string) (*model.OIDCLoginState, error) {
+	var state model.OIDCLoginState
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("state_hash = ?", stateHash).First(&state).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&model.OIDCLoginState{}, "id = ?", state.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *oidcProviderRepository) DeleteExpiredStates(ctx context.Context) error {
+	return r.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&model.OIDCLoginState{}).Error
+}
+
+func (r *oidcProviderRepository) FindIdentity(ctx context.Context, providerID, subject string) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	if err := r.db.WithContext(ctx).First(&identity, "provider_id = ? AND subject = ?", providerID, subject).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *oidcProviderRepository) CreateIdentity(ctx context.Context, identity *model.UserIdentity) error {
+	return r.db.WithContext(ctx).Omit("Provider").Create(identity).Error
+}
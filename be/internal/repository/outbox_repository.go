@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository interface {
+	Create(ctx context.Context, events []model.OutboxEvent) error
+	CreateIfAbsent(ctx context.Context, events []model.OutboxEvent) error
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEvent, error)
+	MarkSent(ctx context.Context, id string) error
+	MarkRetry(ctx context.Context, id string, lastError string, nextAttemptAt time.Time) error
+	MarkFailed(ctx context.Context, id string, lastError string) error
+	DeleteSentBefore(ctx context.Context, before time.Time) error
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// createOutboxEvents writes events within the transaction of the change they
+// report. Repositories that take events call it from their transactions.
+func createOutboxEvents(tx *gorm.DB, events []model.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return tx.Create(&events).Error
+}
+
+func (r *outboxRepository) Create(ctx context.Context, events []model.OutboxEvent) error {
+	return createOutboxEvents(r.db.WithContext(ctx), events)
+}
+
+// CreateIfAbsent writes the events whose ID is not in the outbox yet. It is
+// for events identified by their occurrence, which periodic scans find again
+// and again.
+func (r *outboxRepository) CreateIfAbsent(ctx context.Context, events []model.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "id"}}, DoNothing: true}).
+		Create(&events).Error
+}
+
+// ClaimDue returns the oldest pending events that are due and pushes their
+// next attempt back by lease, so another relay instance does not publish them
+// at the same time. Rows claimed by a concurrent transaction are skipped.
+// An event is held back while an earlier event of its key is still pending
+// outside the claim, e.g. waiting out a retry backoff, so the events of a key
+// are published in order. Failed events are final and hold nothing back.
+func (r *outboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var due []model.OutboxEvent
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.OutboxPending, now).
+			Order("created_at ASC").
+			Limit(limit).
+			Find(&due).Error; err != nil {
+			return err
+		}
+		if len(due) == 0 {
+			return nil
+		}
+
+		ids := make([]string, len(due))
+		var keys []string
+		for i := range due {
+			ids[i] = due[i].ID
+			if due[i].Key != "" {
+				keys = append(keys, due[i].Key)
+			}
+		}
+
+		// The oldest unclaimed event of each key that is still to be
+		// published; the key's later events wait for it
+		blockedFrom := make(map[string]time.Time)
+		if len(keys) > 0 {
+			var blockers []struct {
+				Key       string
+				CreatedAt time.Time
+			}
+			if err := tx.Model(&model.OutboxEvent{}).
+				Select("key, MIN(created_at) AS created_at").
+				Where("key IN ? AND status = ? AND id NOT IN ?", keys, model.OutboxPending, ids).
+				Group("key").
+				Scan(&blockers).Error; err != nil {
+				return err
+			}
+			for _, b := range blockers {
+				blockedFrom[b.Key] = b.CreatedAt
+			}
+		}
+
+		claimed := ids[:0]
+		for _, event := range due {
+			if from, ok := blockedFrom[event.Key]; ok && !event.CreatedAt.Before(from) {
+				continue
+			}
+			events = append(events, event)
+			claimed = append(claimed, event.ID)
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+		return tx.Model(&model.OutboxEvent{}).Where("id IN ?", claimed).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	return events, err
+}
+
+func (r *outboxRepository) MarkSent(ctx context.Context, id string) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Model(&model.OutboxEvent{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     model.OutboxSent,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": "",
+			"sent_at":    now,
+		}).Error
+}
+
+// MarkRetry records a failed attempt and schedules the next one
+func (r *outboxRepository) MarkRetry(ctx context.Context, id string, lastError string, nextAttemptAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.OutboxEvent{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      lastError,
+			"next_attempt_at": nextAttemptAt,
+		}).Error
+}
+
+// MarkFailed records the last failed attempt and gives up on the event
+func (r *outboxRepository) MarkFailed(ctx context.Context, id string, lastError string) error {
+	return r.db.WithContext(ctx).Model(&model.OutboxEvent{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     model.OutboxFailed,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": lastError,
+		}).Error
+}
+
+// DeleteSentBefore purges events published before the given time
+func (r *outboxRepository) DeleteSentBefore(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).
+		Where("status = ? AND sent_at < ?", model.OutboxSent, before).
+		Delete(&model.OutboxEvent{}).Error
+}
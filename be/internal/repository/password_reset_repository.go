@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type PasswordResetRepository interface {
+	Issue(ctx context.Context, token *model.PasswordResetToken, events []model.OutboxEvent) error
+	FindByTokenHash(ctx context.Context, tokenHash string) (*model.PasswordResetToken, error)
+	MarkUsed(ctx context.Context, id string) (bool, error)
+}
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Issue consumes every outstanding token of the user and stores the new one
+// with the events delivering it, in a single transaction
+func (r *passwordResetRepository) Issue(ctx context.Context, token *model.PasswordResetToken, events []model.OutboxEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Omit("User").Create(token).Error; err != nil {
+			return err
+		}
+
+		return createOutboxEvents(tx, events)
+	})
+}
+
+func (r *passwordResetRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*model.PasswordResetToken, error) {
+	var token model.PasswordResetToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed consumes the token. It reports false when the token was already
+// used, so two concurrent resets cannot both succeed.
+func (r *passwordResetRepository) MarkUsed(ctx context.Context, id string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -1,21 +1,32 @@
 package repository
 
 import (
+	"context"
+	"errors"
+
 	"hris-backend/internal/model"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
+// ErrPayrollExists is returned when an employee already has a payroll for the
+// period
+var ErrPayrollExists = errors.New("payroll already exists for this period")
+
 type PayrollRepository interface {
-	Create(payroll *model.Payroll) error
-	FindByID(id string) (*model.Payroll, error)
-	FindByEmployeeID(employeeID string) ([]model.Payroll, error)
-	FindByPeriod(month, year int) ([]model.Payroll, error)
-	FindByEmployeeIDAndPeriod(employeeID string, month, year int) (*model.Payroll, error)
-	FindPaidByEmployeeID(employeeID string) ([]model.Payroll, error)
-	FindAll() ([]model.Payroll, error)
-	Update(payroll *model.Payroll) error
-	Delete(id string) error
+	Create(ctx context.Context, payroll *model.Payroll) error
+	FindByID(ctx context.Context, id string) (*model.Payroll, error)
+	FindByEmployeeID(ctx context.Context, employeeID string) ([]model.Payroll, error)
+	FindByPeriod(ctx context.Context, month, year int) ([]model.Payroll, error)
+	FindByEmployeeIDAndPeriod(ctx context.Context, employeeID string, month, year int) (*model.Payroll, error)
+	FindByEmployeeIDAndYear(ctx context.Context, employeeID string, year int) ([]model.Payroll, error)
+	FindByPayrollRunID(ctx context.Context, runID string) ([]model.Payroll, error)
+	FindPaidByEmployeeID(ctx context.Context, employeeID string) ([]model.Payroll, error)
+	FindAll(ctx context.Context) ([]model.Payroll, error)
+	Update(ctx context.Context, payroll *model.Payroll) error
+	UpdateStatus(ctx context.Context, payroll *model.Payroll, events []model.OutboxEvent) error
+	Delete(ctx context.Context, id string) error
 }
 
 type payrollRepository struct {
@@ -30,62 +41,116 @@ func (r *payrollRepository) preload(db *gorm.DB) *gorm.DB {
 	return db.Preload("Employee").Preload("Employee.User").Preload("Employee.Company").Preload("Employee.Department").Preload("Employee.Position")
 }
 
-func (r *payrollRepository) Create(payroll *model.Payroll) error {
-	return r.db.Create(payroll).Error
+func (r *payrollRepository) Create(ctx context.Context, payroll *model.Payroll) error {
+	return createPayroll(r.db.WithContext(ctx), payroll)
 }
 
-func (r *payrollRepository) FindByID(id string) (*model.Payroll, error) {
+func (r *payrollRepository) FindByID(ctx context.Context, id string) (*model.Payroll, error) {
 	var payroll model.Payroll
-	if err := r.preload(r.db).First(&payroll, "id = ?", id).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).First(&payroll, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &payroll, nil
 }
 
-func (r *payrollRepository) FindByEmployeeID(employeeID string) ([]model.Payroll, error) {
+func (r *payrollRepository) FindByEmployeeID(ctx context.Context, employeeID string) ([]model.Payroll, error) {
 	var payrolls []model.Payroll
-	if err := r.preload(r.db).Where("employee_id = ?", employeeID).Order("period_year DESC, period_month DESC").Find(&payrolls).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).Where("employee_id = ?", employeeID).Order("period_year DESC, period_month DESC").Find(&payrolls).Error; err != nil {
 		return nil, err
 	}
 	return payrolls, nil
 }
 
-func (r *payrollRepository) FindByPeriod(month, year int) ([]model.Payroll, error) {
+func (r *payrollRepository) FindByPeriod(ctx context.Context, month, year int) ([]model.Payroll, error) {
 	var payrolls []model.Payroll
-	if err := r.preload(r.db).Where("period_month = ? AND period_year = ?", month, year).Order("created_at DESC").Find(&payrolls).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).Where("period_month = ? AND period_year = ?", month, year).Order("created_at DESC").Find(&payrolls).Error; err != nil {
 		return nil, err
 	}
 	return payrolls, nil
 }
 
-func (r *payrollRepository) FindByEmployeeIDAndPeriod(employeeID string, month, year int) (*model.Payroll, error) {
+func (r *payrollRepository) FindByEmployeeIDAndPeriod(ctx context.Context, employeeID string, month, year int) (*model.Payroll, error) {
 	var payroll model.Payroll
-	if err := r.preload(r.db).Where("employee_id = ? AND period_month = ? AND period_year = ?", employeeID, month, year).First(&payroll).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).Where("employee_id = ? AND period_month = ? AND period_year = ?", employeeID, month, year).First(&payroll).Error; err != nil {
 		return nil, err
 	}
 	return &payroll, nil
 }
 
-func (r *payrollRepository) FindPaidByEmployeeID(employeeID string) ([]model.Payroll, error) {
+func (r *payrollRepository) FindByEmployeeIDAndYear(ctx context.Context, employeeID string, year int) ([]model.Payroll, error) {
+	var payrolls []model.Payroll
+	if err := r.db.WithContext(ctx).Where("employee_id = ? AND period_year = ?", employeeID, year).Order("period_month ASC").Find(&payrolls).Error; err != nil {
+		return nil, err
+	}
+	return payrolls, nil
+}
+
+func (r *payrollRepository) FindByPayrollRunID(ctx context.Context, runID string) ([]model.Payroll, error) {
 	var payrolls []model.Payroll
-	if err := r.preload(r.db).Where("employee_id = ? AND status = ?", employeeID, model.PayrollPaid).Order("period_year DESC, period_month DESC").Find(&payrolls).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).Where("payroll_run_id = ?", runID).Order("created_at ASC").Find(&payrolls).Error; err != nil {
 		return nil, err
 	}
 	return payrolls, nil
 }
 
-func (r *payrollRepository) FindAll() ([]model.Payroll, error) {
+func (r *payrollRepository) FindPaidByEmployeeID(ctx context.Context, employeeID string) ([]model.Payroll, error) {
 	var payrolls []model.Payroll
-	if err := r.preload(r.db).Order("period_year DESC, period_month DESC").Find(&payrolls).Error; err != nil {
+	if err := r.preload(r.db.WithContext(ctx)).Where("employee_id = ? AND status = ?", employeeID, model.PayrollPaid).Order("period_year DESC, period_month DESC").Find(&payrolls).Error; err != nil {
 		return nil, err
 	}
 	return payrolls, nil
 }
 
-func (r *payrollRepository) Update(payroll *model.Payroll) error {
-	return r.db.Save(payroll).Error
+func (r *payrollRepository) FindAll(ctx context.Context) ([]model.Payroll, error) {
+	var payrolls []model.Payroll
+	if err := r.preload(r.db.WithContext(ctx)).Order("period_year DESC, period_month DESC").Find(&payrolls).Error; err != nil {
+		return nil, err
+	}
+	return payrolls, nil
+}
+
+func (r *payrollRepository) Update(ctx context.Context, payroll *model.Payroll) error {
+	return r.db.WithContext(ctx).Save(payroll).Error
+}
+
+// UpdateStatus saves a payroll moved to a new status together with the
+// events announcing it
+func (r *payrollRepository) UpdateStatus(ctx context.Context, payroll *model.Payroll, events []model.OutboxEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Employee").Save(payroll).Error; err != nil {
+			return err
+		}
+		return createOutboxEvents(tx, events)
+	})
+}
+
+func (r *payrollRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&model.Payroll{}, "id = ?", id).Error
+}
+
+// createPayroll inserts a payroll, returning ErrPayrollExists if the employee
+// already has one for the period. The unique index decides when two requests
+// create the same payroll at once.
+func createPayroll(tx *gorm.DB, payroll *model.Payroll) error {
+	var count int64
+	if err := tx.Model(&model.Payroll{}).
+		Where("employee_id = ? AND period_month = ? AND period_year = ?", payroll.EmployeeID, payroll.PeriodMonth, payroll.PeriodYear).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrPayrollExists
+	}
+	if err := tx.Create(payroll).Error; err != nil {
+		if isUniqueViolation(err) {
+			return ErrPayrollExists
+		}
+		return err
+	}
+	return nil
 }
 
-func (r *payrollRepository) Delete(id string) error {
-	return r.db.Delete(&model.Payroll{}, "id = ?", id).Error
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
 }
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type PayrollRunRepository interface {
+	CreateWithPayrolls(ctx context.Context, run *model.PayrollRun, payrolls []model.Payroll) error
+	FindByID(ctx context.Context, id string) (*model.PayrollRun, error)
+	FindByCompanyID(ctx context.Context, companyID string) ([]model.PayrollRun, error)
+	FindAll(ctx context.Context) ([]model.PayrollRun, error)
+	SaveWithPayrolls(ctx context.Context, run *model.PayrollRun, payrolls []model.Payroll, events []model.OutboxEvent) error
+	DeleteWithPayrolls(ctx context.Context, id string) error
+}
+
+type payrollRunRepository struct {
+	db *gorm.DB
+}
+
+func NewPayrollRunRepository(db *gorm.DB) PayrollRunRepository {
+	return &payrollRunRepository{db: db}
+}
+
+// CreateWithPayrolls inserts the run and all of its payrolls in a single
+// transaction, so a failure part-way through leaves no partial run behind.
+// It returns ErrPayrollExists and stores nothing if any of the employees got
+// a payroll for the period since the run was calculated.
+func (r *payrollRunRepository) CreateWithPayrolls(ctx context.Context, run *model.PayrollRun, payrolls []model.Payroll) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(run).Error; err != nil {
+			return err
+		}
+
+		for i := range payrolls {
+			payrolls[i].PayrollRunID = &run.ID
+			if err := createPayroll(tx, &payrolls[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *payrollRunRepository) FindByID(ctx context.Context, id string) (*model.PayrollRun, error) {
+	var run model.PayrollRun
+	if err := r.db.WithContext(ctx).Preload("Company").First(&run, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *payrollRunRepository) FindByCompanyID(ctx context.Context, companyID string) ([]model.PayrollRun, error) {
+	var runs []model.PayrollRun
+	if err := r.db.WithContext(ctx).Preload("Company").Where("company_id = ?", companyID).Order("period_year DESC, period_month DESC, created_at DESC").Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (r *payrollRunRepository) FindAll(ctx context.Context) ([]model.PayrollRun, error) {
+	var runs []model.PayrollRun
+	if err := r.db.WithContext(ctx).Preload("Company").Order("period_year DESC, period_month DESC, created_at DESC").Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// SaveWithPayrolls persists the run together with its payrolls and the
+// events announcing their new status in a single transaction. It is used to
+// move a whole run through its status lifecycle.
+func (r *payrollRunRepository) SaveWithPayrolls(ctx context.Context, run *model.PayrollRun, payrolls []model.Payroll, events []model.OutboxEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Company", "Payrolls").Save(run).Error; err != nil {
+			return err
+		}
+
+		for i := range payrolls {
+			if err := tx.Omit("Employee").Save(&payrolls[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		return createOutboxEvents(tx, events)
+	})
+}
+
+func (r *payrollRunRepository) DeleteWithPayrolls(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("payroll_run_id = ?", id).Delete(&model.Payroll{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.PayrollRun{}, "id = ?", id).Error
+	})
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PermissionRepository interface {
+	FindAll(ctx context.Context) ([]model.Permission, error)
+	FindByNames(ctx context.Context, names []string) ([]model.Permission, error)
+	FindNamesByRole(ctx context.Context, role model.Role) ([]string, error)
+	UpsertMany(ctx context.Context, permissions []model.Permission) error
+	GrantToRole(ctx context.Context, role model.Role, names []string) error
+}
+
+type permissionRepository struct {
+	db *gorm.DB
+}
+
+func NewPermissionRepository(db *gorm.DB) PermissionRepository {
+	return &permissionRepository{db: db}
+}
+
+func (r *permissionRepository) FindAll(ctx context.Context) ([]model.Permission, error) {
+	var permissions []model.Permission
+	if err := r.db.WithContext(ctx).Order("module, action").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (r *permissionRepository) FindByNames(ctx context.Context, names []string) ([]model.Permission, error) {
+	var permissions []model.Permission
+	if err := r.db.WithContext(ctx).Where("name IN ?", names).Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (r *permissionRepository) FindNamesByRole(ctx context.Context, role model.Role) ([]string, error) {
+	var names []string
+	err := r.db.WithContext(ctx).Model(&model.Permission{}).
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Where("role_permissions.role = ?", role).
+		Pluck("permissions.name", &names).Error
+	return names, err
+}
+
+// UpsertMany inserts or updates all provided permissions by name. Used for
+// seeding from the catalog.
+func (r *permissionRepository) UpsertMany(ctx context.Context, permissions []model.Permission) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"description", "module", "action", "updated_at"}),
+	}).Create(&permissions).Error
+}
+
+// GrantToRole grants the named permissions a built-in role does not have yet
+func (r *permissionRepository) GrantToRole(ctx context.Context, role model.Role, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var permissions []model.Permission
+		err := tx.Where("name IN ?", names).
+			Where("id NOT IN (?)", tx.Model(&model.RolePermission{}).Select("permission_id").Where("role = ?", role)).
+			Find(&permissions).Error
+		if err != nil || len(permissions) == 0 {
+			return err
+		}
+
+		rows := make([]model.RolePermission, len(permissions))
+		for i, p := range permissions {
+			rows[i] = model.RolePermission{Role: role, PermissionID: p.ID}
+		}
+		return tx.Create(&rows).Error
+	})
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *model.Session) error
+	FindByID(ctx context.Context, id string) (*model.Session, error)
+	FindActiveByUserID(ctx context.Context, userID string) ([]model.Session, error)
+	Rotate(ctx context.Context, id, currentTokenID, newTokenID, userAgent, ipAddress string, expiresAt time.Time) (bool, error)
+	Revoke(ctx context.Context, id, reason string) error
+	RevokeAllForUser(ctx context.Context, userID, exceptID, reason string) error
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *model.Session) error {
+	return r.db.WithContext(ctx).Omit("User").Create(session).Error
+}
+
+func (r *sessionRepository) FindByID(ctx context.Context, id string) (*model.Session, error) {
+	var session model.Session
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) FindActiveByUserID(ctx context.Context, userID string) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Rotate swaps the session's current token for a new one, provided the
+// session is still active and currentTokenID is still its current token. It
+// reports false when another refresh got there first.
+func (r *sessionRepository) Rotate(ctx context.Context, id, currentTokenID, newTokenID, userAgent, ipAddress string, expiresAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.Session{}).
+		Where("id = ? AND token_id = ? AND revoked_at IS NULL", id, currentTokenID).
+		Updates(map[string]interface{}{
+			"token_id":     newTokenID,
+			"user_agent":   userAgent,
+			"ip_address":   ipAddress,
+			"last_used_at": time.Now(),
+			"expires_at":   expiresAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *sessionRepository) Revoke(ctx context.Context, id, reason string) error {
+	return r.db.WithContext(ctx).Model(&model.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"revoked_reason": reason,
+		}).Error
+}
+
+func (r *sessionRepository) RevokeAllForUser(ctx context.Context, userID, exceptID, reason string) error {
+	query := r.db.WithContext(ctx).Model(&model.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID)
+	if exceptID != "" {
+		query = query.Where("id <> ?", exceptID)
+	}
+	return query.Updates(map[string]interface{}{
+		"revoked_at":     time.Now(),
+		"revoked_reason": reason,
+	}).Error
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"hris-backend/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type TwoFactorRepository interface {
+	FindByUserID(ctx context.Context, userID string) (*model.UserTwoFactor, error)
+	Save(ctx context.Context, tf *model.UserTwoFactor) error
+	Delete(ctx context.Context, userID string) error
+	MarkStepUsed(ctx context.Context, userID string, step int64) (bool, error)
+	SetChallenge(ctx context.Context, userID, challengeID string) error
+	ConsumeChallenge(ctx context.Context, userID, challengeID string) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+	CountRecoveryCodes(ctx context.Context, userID string) (int64, error)
+}
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRepository(db *gorm.DB) TwoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+func (r *twoFactorRepository) FindByUserID(ctx context.Context, userID string) (*model.UserTwoFactor, error) {
+	var tf model.UserTwoFactor
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&tf).Error; err != nil {
+		return nil, err
+	}
+	return &tf, nil
+}
+
+func (r *twoFactorRepository) Save(ctx context.Context, tf *model.UserTwoFactor) error {
+	return r.db.WithContext(ctx).Omit("User").Save(tf).Error
+}
+
+// Delete removes the user's TOTP secret and recovery codes
+func (r *twoFactorRepository) Delete(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.UserTwoFactor{}).Error
+	})
+}
+
+// MarkStepUsed records step as the last accepted one. It reports false when
+// a code of this step or a later one was already accepted.
+func (r *twoFactorRepository) MarkStepUsed(ctx context.Context, userID string, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.UserTwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// SetChallenge makes challengeID the user's outstanding login challenge,
+// replacing any earlier one
+func (r *twoFactorRepository) SetChallenge(ctx context.Context, userID, challengeID string) error {
+	return r.db.WithContext(ctx).Model(&model.UserTwoFactor{}).
+		Where("user_id = ?", userID).
+		Update("challenge_id", challengeID).Error
+}
+
+// ConsumeChallenge clears the user's outstanding login challenge. It reports
+// false when challengeID is not that challenge, because it was already
+// presented or a later login replaced it.
+func (r *twoFactorRepository) ConsumeChallenge(ctx context.Context, userID, challengeID string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.UserTwoFactor{}).
+		Where("user_id = ? AND challenge_id = ?", userID, challengeID).
+		Update("challenge_id", nil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *twoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		for _, codeHash := range codeHashes {
+			code := model.RecoveryCode{UserID: userID, CodeHash: codeHash}
+			if err := tx.Create(&code).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UseRecoveryCode consumes an unused recovery code of the user
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *twoFactorRepository) CountRecoveryCodes(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
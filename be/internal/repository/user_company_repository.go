@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+
+	"hris-backend/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UserCompanyRepository interface {
+	FindByUserID(ctx context.Context, userID string) ([]model.UserCompany, error)
+	ReplaceForUser(ctx context.Context, userID string, companyIDs []string) error
+}
+
+type userCompanyRepository struct {
+	db *gorm.DB
+}
+
+func NewUserCompanyRepository(db *gorm.DB) UserCompanyRepository {
+	return &userCompanyRepository{db: db}
+}
+
+func (r *userCompanyRepository) FindByUserID(ctx context.Context, userID string) ([]model.UserCompany, error) {
+	var bindings []model.UserCompany
+	if err := r.db.WithContext(ctx).Preload("Company").Where("user_id = ?", userID).Order("created_at ASC").Find(&bindings).Error; err != nil {
+		return nil, err
+	}
+	return bindings, nil
+}
+
+func (r *userCompanyRepository) ReplaceForUser(ctx context.Context, userID string, companyIDs []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.UserCompany{}).Error; err != nil {
+			return err
+		}
+
+		for _, companyID := range companyIDs {
+			binding := model.UserCompany{
+				ID:        uuid.New().String(),
+				UserID:    userID,
+				CompanyID: companyID,
+			}
+			if err := tx.Create(&binding).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -1,19 +1,30 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"hris-backend/internal/model"
+	"hris-backend/internal/tenant"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
-	Create(user *model.User) error
-	FindByID(id string) (*model.User, error)
-	FindByEmail(email string) (*model.User, error)
-	FindAll() ([]model.User, error)
-	FindByRoles(roles []string) ([]model.User, error)
-	Update(user *model.User) error
-	Delete(id string) error
+	Create(ctx context.Context, user *model.User) error
+	FindByID(ctx context.Context, id string) (*model.User, error)
+	FindByEmail(ctx context.Context, email string) (*model.User, error)
+	FindAll(ctx context.Context) ([]model.User, error)
+	FindByRoles(ctx context.Context, roles []string) ([]model.User, error)
+	FindByRolesInCompany(ctx context.Context, companyID string, roles []string) ([]model.User, error)
+	FindAnnouncementAudience(ctx context.Context, companyID string, departmentIDs, jobLevelIDs []string) ([]model.User, error)
+	FindServiceAccounts(ctx context.Context) ([]model.User, error)
+	Update(ctx context.Context, user *model.User) error
+	Delete(ctx context.Context, id string) error
+	IsInScope(ctx context.Context, id string) (bool, error)
+	RecordLoginFailure(ctx context.Context, id string, maxFailures int, lockUntil time.Time, lockEvents []model.OutboxEvent) (bool, error)
+	ResetLoginFailures(ctx context.Context, id string) error
 }
 
 type userRepository struct {
@@ -24,46 +35,139 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) Create(user *model.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) Create(ctx context.Context, user *model.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
 }
 
-func (r *userRepository) FindByID(id string) (*model.User, error) {
+func (r *userRepository) FindByID(ctx context.Context, id string) (*model.User, error) {
 	var user model.User
-	if err := r.db.First(&user, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) FindByEmail(email string) (*model.User, error) {
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
-	if err := r.db.First(&user, "email = ?", email).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&user, "email = ?", email).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) FindAll() ([]model.User, error) {
+func (r *userRepository) FindAll(ctx context.Context) ([]model.User, error) {
 	var users []model.User
-	if err := r.db.Order("created_at DESC").Find(&users).Error; err != nil {
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(&users).Error; err != nil {
 		return nil, err
 	}
 	return users, nil
 }
 
-func (r *userRepository) Update(user *model.User) error {
-	return r.db.Save(user).Error
+func (r *userRepository) Update(ctx context.Context, user *model.User) error {
+	return r.db.WithContext(ctx).Save(user).Error
+}
+
+func (r *userRepository) FindByRoles(ctx context.Context, roles []string) ([]model.User, error) {
+	var users []model.User
+	if err := r.db.WithContext(ctx).Where("role IN ? AND is_active = true AND is_service_account = false", roles).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// FindByRolesInCompany returns the active users of the given roles who
+// belong to a company, through their employee record or a company binding.
+// Unlike FindByRoles under a tenant scope, it leaves out users of no company.
+func (r *userRepository) FindByRolesInCompany(ctx context.Context, companyID string, roles []string) ([]model.User, error) {
+	var users []model.User
+	err := r.db.WithContext(ctx).
+		Where("role IN ? AND is_active = true AND is_service_account = false", roles).
+		Where("(id IN (SELECT user_id FROM employees WHERE company_id = ? AND deleted_at IS NULL)"+
+			" OR id IN (SELECT user_id FROM user_companies WHERE company_id = ?))", companyID, companyID).
+		Find(&users).Error
+	return users, err
+}
+
+// FindAnnouncementAudience returns the users of the current employees of a
+// company an announcement for the departments and job levels is for; with
+// neither, the users of the whole company
+func (r *userRepository) FindAnnouncementAudience(ctx context.Context, companyID string, departmentIDs, jobLevelIDs []string) ([]model.User, error) {
+	db := r.db.WithContext(ctx)
+	var users []model.User
+	err := db.
+		Where("id IN (?)", audienceEmployees(db, companyID, departmentIDs, jobLevelIDs).Select("employees.user_id")).
+		Find(&users).Error
+	return users, err
 }
 
-func (r *userRepository) FindByRoles(roles []string) ([]model.User, error) {
+func (r *userRepository) FindServiceAccounts(ctx context.Context) ([]model.User, error) {
 	var users []model.User
-	if err := r.db.Where("role IN ? AND is_active = true", roles).Find(&users).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("is_service_account = true").Order("name").Find(&users).Error; err != nil {
 		return nil, err
 	}
 	return users, nil
 }
 
-func (r *userRepository) Delete(id string) error {
-	return r.db.Delete(&model.User{}, "id = ?", id).Error
+func (r *userRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&model.User{}, "id = ?", id).Error
+}
+
+// IsInScope reports whether a user belongs to a company in the request's
+// scope, through their employee record or a company binding. Unscoped
+// requests reach every user.
+func (r *userRepository) IsInScope(ctx context.Context, id string) (bool, error) {
+	scope := tenant.FromContext(ctx)
+	if scope == nil {
+		return true, nil
+	}
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.User{}).
+		Where("id = ?", id).
+		Where("(id IN (SELECT user_id FROM employees WHERE company_id IN ? AND deleted_at IS NULL) OR id IN (SELECT user_id FROM user_companies WHERE company_id IN ?))",
+			scope.CompanyIDs, scope.CompanyIDs).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// RecordLoginFailure counts a failed login. When the count reaches
+// maxFailures the account is locked until lockUntil, the count starts over
+// and lockEvents are written; it reports whether this failure locked the
+// account.
+func (r *userRepository) RecordLoginFailure(ctx context.Context, id string, maxFailures int, lockUntil time.Time, lockEvents []model.OutboxEvent) (bool, error) {
+	locked := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"failed_login_attempts": user.FailedLoginAttempts + 1,
+			"last_failed_login_at":  time.Now(),
+		}
+		if user.FailedLoginAttempts+1 >= maxFailures {
+			updates["failed_login_attempts"] = 0
+			updates["locked_until"] = lockUntil
+			locked = true
+		}
+		if err := tx.Model(&model.User{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		if !locked {
+			return nil
+		}
+		return createOutboxEvents(tx, lockEvents)
+	})
+	return locked, err
+}
+
+// ResetLoginFailures clears the failure count and any lockout
+func (r *userRepository) ResetLoginFailures(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"failed_login_attempts": 0,
+			"last_failed_login_at":  nil,
+			"locked_until":          nil,
+		}).Error
 }
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"time"
 
 	"hris-backend/internal/model"
@@ -9,19 +10,19 @@ import (
 )
 
 type VisitPlanRepository interface {
-	Create(p *model.VisitPlan) error
-	Update(p *model.VisitPlan) error
-	FindByID(id string) (*model.VisitPlan, error)
-	FindByEmployeeAndDate(employeeID string, date time.Time) (*model.VisitPlan, error)
-	FindByCompanyAndDate(companyID string, date time.Time) ([]model.VisitPlan, error)
-	ListByEmployee(employeeID string, from, to *time.Time) ([]model.VisitPlan, error)
-	Delete(id string) error
+	Create(ctx context.Context, p *model.VisitPlan) error
+	Update(ctx context.Context, p *model.VisitPlan) error
+	FindByID(ctx context.Context, id string) (*model.VisitPlan, error)
+	FindByEmployeeAndDate(ctx context.Context, employeeID string, date time.Time) (*model.VisitPlan, error)
+	FindByCompanyAndDate(ctx context.Context, companyID string, date time.Time) ([]model.VisitPlan, error)
+	ListByEmployee(ctx context.Context, employeeID string, from, to *time.Time) ([]model.VisitPlan, error)
+	Delete(ctx context.Context, id string) error
 
 	// Items
-	CreateItem(i *model.VisitPlanItem) error
-	UpdateItem(i *model.VisitPlanItem) error
-	FindItemByID(id string) (*model.VisitPlanItem, error)
-	DeleteItem(id string) error
+	CreateItem(ctx context.Context, i *model.VisitPlanItem) error
+	UpdateItem(ctx context.Context, i *model.VisitPlanItem) error
+	FindItemByID(ctx context.Context, id string) (*model.VisitPlanItem, error)
+	DeleteItem(ctx context.Context, id string) error
 }
 
 type visitPlanRepository struct {
@@ -32,27 +33,27 @@ func NewVisitPlanRepository(db *gorm.DB) VisitPlanRepository {
 	return &visitPlanRepository{db}
 }
 
-func (r *visitPlanRepository) Create(p *model.VisitPlan) error {
-	return r.db.Create(p).Error
+func (r *visitPlanRepository) Create(ctx context.Context, p *model.VisitPlan) error {
+	return r.db.WithContext(ctx).Create(p).Error
 }
 
-func (r *visitPlanRepository) Update(p *model.VisitPlan) error {
-	return r.db.Save(p).Error
+func (r *visitPlanRepository) Update(ctx context.Context, p *model.VisitPlan) error {
+	return r.db.WithContext(ctx).Save(p).Error
 }
 
-func (r *visitPlanRepository) FindByID(id string) (*model.VisitPlan, error) {
+func (r *visitPlanRepository) FindByID(ctx context.Context, id string) (*model.VisitPlan, error) {
 	var p model.VisitPlan
-	err := r.db.Preload("Items").Where("id = ?", id).First(&p).Error
+	err := r.db.WithContext(ctx).Preload("Items").Where("id = ?", id).First(&p).Error
 	if err != nil {
 		return nil, err
 	}
 	return &p, nil
 }
 
-func (r *visitPlanRepository) FindByEmployeeAndDate(employeeID string, date time.Time) (*model.VisitPlan, error) {
+func (r *visitPlanRepository) FindByEmployeeAndDate(ctx context.Context, employeeID string, date time.Time) (*model.VisitPlan, error) {
 	var p model.VisitPlan
 	day := date.Format("2006-01-02")
-	err := r.db.Preload("Items").
+	err := r.db.WithContext(ctx).Preload("Items").
 		Where("employee_id = ? AND plan_date = ?", employeeID, day).
 		First(&p).Error
 	if err != nil {
@@ -61,17 +62,17 @@ func (r *visitPlanRepository) FindByEmployeeAndDate(employeeID string, date time
 	return &p, nil
 }
 
-func (r *visitPlanRepository) FindByCompanyAndDate(companyID string, date time.Time) ([]model.VisitPlan, error) {
+func (r *visitPlanRepository) FindByCompanyAndDate(ctx context.Context, companyID string, date time.Time) ([]model.VisitPlan, error) {
 	var out []model.VisitPlan
 	day := date.Format("2006-01-02")
-	err := r.db.Preload("Items").
+	err := r.db.WithContext(ctx).Preload("Items").
 		Where("company_id = ? AND plan_date = ?", companyID, day).
 		Find(&out).Error
 	return out, err
 }
 
-func (r *visitPlanRepository) ListByEmployee(employeeID string, from, to *time.Time) ([]model.VisitPlan, error) {
-	q := r.db.Preload("Items").Where("employee_id = ?", employeeID)
+func (r *visitPlanRepository) ListByEmployee(ctx context.Context, employeeID string, from, to *time.Time) ([]model.VisitPlan, error) {
+	q := r.db.WithContext(ctx).Preload("Items").Where("employee_id = ?", employeeID)
 	if from != nil {
 		q = q.Where("plan_date >= ?", from.Format("2006-01-02"))
 	}
@@ -83,30 +84,30 @@ func (r *visitPlanRepository) ListByEmployee(employeeID string, from, to *time.T
 	return out, err
 }
 
-func (r *visitPlanRepository) Delete(id string) error {
+func (r *visitPlanRepository) Delete(ctx context.Context, id string) error {
 	// Cascade items via GORM soft delete — delete items first for cleanliness.
-	if err := r.db.Where("visit_plan_id = ?", id).Delete(&model.VisitPlanItem{}).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("visit_plan_id = ?", id).Delete(&model.VisitPlanItem{}).Error; err != nil {
 		return err
 	}
-	return r.db.Where("id = ?", id).Delete(&model.VisitPlan{}).Error
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.VisitPlan{}).Error
 }
 
-func (r *visitPlanRepository) CreateItem(i *model.VisitPlanItem) error {
-	return r.db.Create(i).Error
+func (r *visitPlanRepository) CreateItem(ctx context.Context, i *model.VisitPlanItem) error {
+	return r.db.WithContext(ctx).Create(i).Error
 }
 
-func (r *visitPlanRepository) UpdateItem(i *model.VisitPlanItem) error {
-	return r.db.Save(i).Error
+func (r *visitPlanRepository) UpdateItem(ctx context.Context, i *model.VisitPlanItem) error {
+	return r.db.WithContext(ctx).Save(i).Error
 }
 
-func (r *visitPlanRepository) FindItemByID(id string) (*model.VisitPlanItem, error) {
+func (r *visitPlanRepository) FindItemByID(ctx context.Context, id string) (*model.VisitPlanItem, error) {
 	var i model.VisitPlanItem
-	if err := r.db.Where("id = ?", id).First(&i).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&i).Error; err != nil {
 		return nil, err
 	}
 	return &i, nil
 }
 
-func (r *visitPlanRepository) DeleteItem(id string) error {
-	return r.db.Where("id = ?", id).Delete(&model.VisitPlanItem{}).Error
+func (r *visitPlanRepository) DeleteItem(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.VisitPlanItem{}).Error
 }
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"time"
 
 	"hris-backend/internal/model"
@@ -9,12 +10,12 @@ import (
 )
 
 type VisitRepository interface {
-	Create(v *model.Visit) error
-	Update(v *model.Visit) error
-	FindByID(id string) (*model.Visit, error)
-	FindByAttendanceID(attendanceID string) ([]model.Visit, error)
-	FindPaginated(page, limit int, employeeID, companyID string, from, to *time.Time) ([]model.Visit, int64, error)
-	Delete(id string) error
+	Create(ctx context.Context, v *model.Visit) error
+	Update(ctx context.Context, v *model.Visit) error
+	FindByID(ctx context.Context, id string) (*model.Visit, error)
+	FindByAttendanceID(ctx context.Context, attendanceID string) ([]model.Visit, error)
+	FindPaginated(ctx context.Context, page, limit int, employeeID, companyID string, from, to *time.Time) ([]model.Visit, int64, error)
+	Delete(ctx context.Context, id string) error
 }
 
 type visitRepository struct {
@@ -25,32 +26,32 @@ func NewVisitRepository(db *gorm.DB) VisitRepository {
 	return &visitRepository{db}
 }
 
-func (r *visitRepository) Create(v *model.Visit) error {
-	return r.db.Create(v).Error
+func (r *visitRepository) Create(ctx context.Context, v *model.Visit) error {
+	return r.db.WithContext(ctx).Create(v).Error
 }
 
-func (r *visitRepository) Update(v *model.Visit) error {
-	return r.db.Save(v).Error
+func (r *visitRepository) Update(ctx context.Context, v *model.Visit) error {
+	return r.db.WithContext(ctx).Save(v).Error
 }
 
-func (r *visitRepository) FindByID(id string) (*model.Visit, error) {
+func (r *visitRepository) FindByID(ctx context.Context, id string) (*model.Visit, error) {
 	var v model.Visit
-	if err := r.db.Where("id = ?", id).First(&v).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&v).Error; err != nil {
 		return nil, err
 	}
 	return &v, nil
 }
 
-func (r *visitRepository) FindByAttendanceID(attendanceID string) ([]model.Visit, error) {
+func (r *visitRepository) FindByAttendanceID(ctx context.Context, attendanceID string) ([]model.Visit, error) {
 	var out []model.Visit
-	err := r.db.Where("attendance_id = ?", attendanceID).
+	err := r.db.WithContext(ctx).Where("attendance_id = ?", attendanceID).
 		Order("arrived_at ASC").
 		Find(&out).Error
 	return out, err
 }
 
-func (r *visitRepository) FindPaginated(page, limit int, employeeID, companyID string, from, to *time.Time) ([]model.Visit, int64, error) {
-	q := r.db.Model(&model.Visit{})
+func (r *visitRepository) FindPaginated(ctx context.Context, page, limit int, employeeID, companyID string, from, to *time.Time) ([]model.Visit, int64, error) {
+	q := r.db.WithContext(ctx).Model(&model.Visit{})
 	if employeeID != "" {
 		q = q.Where("employee_id = ?", employeeID)
 	}
@@ -77,6 +78,6 @@ func (r *visitRepository) FindPaginated(page, limit int, employeeID, companyID s
 	return out, total, err
 }
 
-func (r *visitRepository) Delete(id string) error {
-	return r.db.Where("id = ?", id).Delete(&model.Visit{}).Error
+func (r *visitRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.Visit{}).Error
 }
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	announcementScanInterval = time.Minute
+	announcementScanBatch    = 100
+)
+
+// AnnouncementScheduler notifies the audience of scheduled announcements when
+// they are shown, and of the ones whose notification failed when they were
+// published. Each audience is notified once, however many instances scan.
+type AnnouncementScheduler struct {
+	announcementService AnnouncementService
+}
+
+func NewAnnouncementScheduler(announcementService AnnouncementService) *AnnouncementScheduler {
+	return &AnnouncementScheduler{announcementService: announcementService}
+}
+
+// Start begins scanning in a background loop.
+func (s *AnnouncementScheduler) Start() {
+	go func() {
+		for {
+			// Announcements of every company are scanned; this is not tenant scoped
+			if err := s.announcementService.NotifyDue(context.Background(), time.Now()); err != nil {
+				log.Printf("[announcement] notify due announcements: %v", err)
+			}
+			time.Sleep(announcementScanInterval)
+		}
+	}()
+}
@@ -0,0 +1,503 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"hris-backend/internal/dto"
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+	"hris-backend/pkg/kafka"
+	"hris-backend/pkg/sanitize"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxAnnouncementTitle       = 255
+	maxAnnouncementBody        = 100000
+	maxAnnouncementAttachments = 10
+
+	// announcementSummaryLength is how much of the text of an announcement
+	// its notification email quotes
+	announcementSummaryLength = 200
+)
+
+type AnnouncementService interface {
+	GetAllPaginated(ctx context.Context, page, limit int, companyID, status string) (*dto.PaginatedAnnouncementResponse, error)
+	GetMine(ctx context.Context, userID, companyID string) ([]dto.AnnouncementResponse, error)
+	GetByID(ctx context.Context, id, userID string, canManage bool) (*dto.AnnouncementResponse, error)
+	Create(ctx context.Context, userID string, req dto.CreateAnnouncementRequest) (*dto.AnnouncementResponse, error)
+	Update(ctx context.Context, id string, req dto.UpdateAnnouncementRequest) (*dto.AnnouncementResponse, error)
+	Publish(ctx context.Context, id string, req dto.PublishAnnouncementRequest) (*dto.AnnouncementResponse, error)
+	Unpublish(ctx context.Context, id string) (*dto.AnnouncementResponse, error)
+	Delete(ctx context.Context, id string) error
+	MarkRead(ctx context.Context, id, userID string) error
+	GetReadReport(ctx context.Context, id string) (*dto.AnnouncementReadReportResponse, error)
+	NotifyDue(ctx context.Context, now time.Time) error
+}
+
+type announcementService struct {
+	repo         repository.AnnouncementRepository
+	companyRepo  repository.CompanyRepository
+	deptRepo     repository.DepartmentRepository
+	jobLevelRepo repository.JobLevelRepository
+	empRepo      repository.EmployeeRepository
+}
+
+func NewAnnouncementService(
+	repo repository.AnnouncementRepository,
+	companyRepo repository.CompanyRepository,
+	deptRepo repository.DepartmentRepository,
+	jobLevelRepo repository.JobLevelRepository,
+	empRepo repository.EmployeeRepository,
+) AnnouncementService {
+	return &announcementService{
+		repo:         repo,
+		companyRepo:  companyRepo,
+		deptRepo:     deptRepo,
+		jobLevelRepo: jobLevelRepo,
+		empRepo:      empRepo,
+	}
+}
+
+func (s *announcementService) GetAllPaginated(ctx context.Context, page, limit int, companyID, status string) (*dto.PaginatedAnnouncementResponse, error) {
+	switch status {
+	case "", "draft", "scheduled", "published", "expired":
+	default:
+		return nil, errors.New("status must be draft, scheduled, published or expired")
+	}
+
+	announcements, total, err := s.repo.FindAllPaginated(ctx, page, limit, companyID, status, time.Now())
+	if err != nil {
+		return nil, errors.New("failed to fetch announcements")
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	return &dto.PaginatedAnnouncementResponse{
+		Data:       dto.ToAnnouncementResponses(announcements),
+		Page:       page,
+		Limit:      limit,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetMine returns the announcements shown now to a user, pinned first, each
+// telling whether the user read it. The user's employee record decides the
+// company and the targets that apply; users without one see the
+// announcements for the whole of companyID.
+func (s *announcementService) GetMine(ctx context.Context, userID, companyID string) ([]dto.AnnouncementResponse, error) {
+	emp, err := s.empRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("failed to fetch announcements")
+		}
+		emp = nil
+	}
+	if emp != nil {
+		companyID = emp.CompanyID
+	}
+	if companyID == "" {
+		return nil, errors.New("company_id is required")
+	}
+
+	announcements, err := s.repo.FindVisible(ctx, companyID, emp, time.Now())
+	if err != nil {
+		return nil, errors.New("failed to fetch announcements")
+	}
+	return s.withReadState(ctx, userID, announcements)
+}
+
+// GetByID returns an announcement. Users who do not manage announcements
+// only get the ones shown to them.
+func (s *announcementService) GetByID(ctx context.Context, id, userID string, canManage bool) (*dto.AnnouncementResponse, error) {
+	a, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("announcement not found")
+	}
+	if !canManage {
+		visible, err := s.isVisibleTo(ctx, a, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			return nil, errors.New("announcement not found")
+		}
+	}
+
+	responses, err := s.withReadState(ctx, userID, []model.Announcement{*a})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+func (s *announcementService) Create(ctx context.Context, userID string, req dto.CreateAnnouncementRequest) (*dto.AnnouncementResponse, error) {
+	if _, err := s.companyRepo.FindByID(ctx, req.CompanyID); err != nil {
+		return nil, errors.New("company not found")
+	}
+
+	a := &model.Announcement{
+		CompanyID: req.CompanyID,
+		Status:    model.AnnouncementDraft,
+		IsPinned:  req.IsPinned,
+		ExpireAt:  req.ExpireAt,
+		CreatedBy: userID,
+	}
+	if err := s.setContent(a, req.Title, req.Body); err != nil {
+		return nil, err
+	}
+	if err := s.setTargets(ctx, a, req.DepartmentIDs, req.JobLevelIDs); err != nil {
+		return nil, err
+	}
+	if err := setAttachments(a, req.Attachments); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, a); err != nil {
+		return nil, errors.New("failed to create announcement")
+	}
+	resp := dto.ToAnnouncementResponse(a)
+	return &resp, nil
+}
+
+// Update edits an announcement. Editing a published one does not notify its
+// audience again, not even the employees the new targets add.
+func (s *announcementService) Update(ctx context.Context, id string, req dto.UpdateAnnouncementRequest) (*dto.AnnouncementResponse, error) {
+	a, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("announcement not found")
+	}
+
+	title, body := a.Title, a.Body
+	if req.Title != nil {
+		title = *req.Title
+	}
+	if req.Body != nil {
+		body = *req.Body
+	}
+	if err := s.setContent(a, title, body); err != nil {
+		return nil, err
+	}
+	if req.IsPinned != nil {
+		a.IsPinned = *req.IsPinned
+	}
+	if req.ExpireAt != nil {
+		a.ExpireAt = req.ExpireAt
+	}
+	if req.DepartmentIDs != nil || req.JobLevelIDs != nil {
+		departmentIDs := a.TargetIDs(model.AnnouncementTargetDepartment)
+		jobLevelIDs := a.TargetIDs(model.AnnouncementTargetJobLevel)
+		if req.DepartmentIDs != nil {
+			departmentIDs = *req.DepartmentIDs
+		}
+		if req.JobLevelIDs != nil {
+			jobLevelIDs = *req.JobLevelIDs
+		}
+		if err := s.setTargets(ctx, a, departmentIDs, jobLevelIDs); err != nil {
+			return nil, err
+		}
+	}
+	if req.Attachments != nil {
+		if err := setAttachments(a, *req.Attachments); err != nil {
+			return nil, err
+		}
+	}
+	if a.ExpireAt != nil && a.PublishAt != nil && !a.ExpireAt.After(*a.PublishAt) {
+		return nil, errors.New("expire_at must be after publish_at")
+	}
+
+	if err := s.repo.Update(ctx, a); err != nil {
+		return nil, errors.New("failed to update announcement")
+	}
+	resp := dto.ToAnnouncementResponse(a)
+	return &resp, nil
+}
+
+// Publish shows an announcement from publish_at, or from now. Its audience
+// is notified once it is shown: right away, or by the announcement
+// scheduler when publish_at comes.
+func (s *announcementService) Publish(ctx context.Context, id string, req dto.PublishAnnouncementRequest) (*dto.AnnouncementResponse, error) {
+	a, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("announcement not found")
+	}
+
+	now := time.Now()
+	publishAt := now
+	if req.PublishAt != nil {
+		publishAt = *req.PublishAt
+	}
+	if a.ExpireAt != nil && !a.ExpireAt.After(publishAt) {
+		return nil, errors.New("expire_at must be after publish_at")
+	}
+
+	a.Status = model.AnnouncementPublished
+	a.PublishAt = &publishAt
+	if err := s.repo.Update(ctx, a); err != nil {
+		return nil, errors.New("failed to publish announcement")
+	}
+
+	if !publishAt.After(now) {
+		if err := s.notifyAudience(ctx, a); err != nil {
+			// The scheduler notifies it on its next scan
+			log.Printf("[announcement] notify audience of %s: %v", a.ID, err)
+		}
+	}
+	resp := dto.ToAnnouncementResponse(a)
+	return &resp, nil
+}
+
+// Unpublish takes an announcement back to draft. Its audience, if notified,
+// is not notified again when it is published again.
+func (s *announcementService) Unpublish(ctx context.Context, id string) (*dto.AnnouncementResponse, error) {
+	a, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("announcement not found")
+	}
+	a.Status = model.AnnouncementDraft
+	a.PublishAt = nil
+	if err := s.repo.Update(ctx, a); err != nil {
+		return nil, errors.New("failed to unpublish announcement")
+	}
+	resp := dto.ToAnnouncementResponse(a)
+	return &resp, nil
+}
+
+func (s *announcementService) Delete(ctx context.Context, id string) error {
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return errors.New("announcement not found")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// MarkRead records the read receipt of an announcement shown to the user
+func (s *announcementService) MarkRead(ctx context.Context, id, userID string) error {
+	a, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return errors.New("announcement not found")
+	}
+	visible, err := s.isVisibleTo(ctx, a, userID)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return errors.New("announcement not found")
+	}
+	if err := s.repo.MarkRead(ctx, id, userID); err != nil {
+		return errors.New("failed to mark announcement as read")
+	}
+	return nil
+}
+
+// GetReadReport tells how many of the current employees an announcement is
+// for read it, and who did not
+func (s *announcementService) GetReadReport(ctx context.Context, id string) (*dto.AnnouncementReadReportResponse, error) {
+	a, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("announcement not found")
+	}
+
+	total, read, err := s.repo.CountAudience(ctx, a)
+	if err != nil {
+		return nil, errors.New("failed to count announcement readers")
+	}
+	unread, err := s.repo.FindUnreadAudience(ctx, a)
+	if err != nil {
+		return nil, errors.New("failed to fetch announcement readers")
+	}
+
+	report := &dto.AnnouncementReadReportResponse{
+		AnnouncementID: a.ID,
+		AudienceTotal:  total,
+		ReadCount:      read,
+		UnreadCount:    total - read,
+		Unread:         make([]dto.AnnouncementUnreadReader, len(unread)),
+	}
+	for i := range unread {
+		report.Unread[i] = dto.ToAnnouncementUnreadReader(&unread[i])
+	}
+	return report, nil
+}
+
+// NotifyDue notifies the audience of the announcements shown by now whose
+// audience was not notified yet
+func (s *announcementService) NotifyDue(ctx context.Context, now time.Time) error {
+	for {
+		due, err := s.repo.FindDue(ctx, now, announcementScanBatch)
+		if err != nil {
+			return err
+		}
+		for i := range due {
+			if err := s.notifyAudience(ctx, &due[i]); err != nil {
+				return err
+			}
+		}
+		if len(due) < announcementScanBatch {
+			return nil
+		}
+	}
+}
+
+// notifyAudience publishes the announcement.published event of an
+// announcement, once
+func (s *announcementService) notifyAudience(ctx context.Context, a *model.Announcement) error {
+	summary := sanitize.Text(a.Body)
+	if utf8.RuneCountInString(summary) > announcementSummaryLength {
+		summary = string([]rune(summary)[:announcementSummaryLength]) + "…"
+	}
+
+	payload := kafka.AnnouncementPublishedPayload{
+		AnnouncementID: a.ID,
+		CompanyID:      a.CompanyID,
+		Title:          a.Title,
+		Summary:        summary,
+		IsPinned:       a.IsPinned,
+		DepartmentIDs:  a.TargetIDs(model.AnnouncementTargetDepartment),
+		JobLevelIDs:    a.TargetIDs(model.AnnouncementTargetJobLevel),
+		AuthorUserID:   a.CreatedBy,
+		PublishAt:      a.PublishAt.Format(time.RFC3339),
+	}
+	event, err := kafka.NewOutboxEvent(ctx, kafka.EventAnnouncementPublished, a.ID, a.CompanyID, payload)
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.MarkNotified(ctx, a.ID, []model.OutboxEvent{event})
+	return err
+}
+
+// isVisibleTo reports whether an announcement is shown now to a user
+func (s *announcementService) isVisibleTo(ctx context.Context, a *model.Announcement, userID string) (bool, error) {
+	if dto.AnnouncementStatus(a, time.Now()) != string(model.AnnouncementPublished) {
+		return false, nil
+	}
+
+	emp, err := s.empRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, errors.New("failed to fetch employee")
+		}
+		// Users without an employee record see the announcements for a
+		// whole company in their scope
+		return len(a.Targets) == 0, nil
+	}
+	if emp.CompanyID != a.CompanyID {
+		return false, nil
+	}
+	if len(a.Targets) == 0 {
+		return true, nil
+	}
+	for _, t := range a.Targets {
+		switch t.TargetType {
+		case model.AnnouncementTargetDepartment:
+			if t.TargetID == emp.DepartmentID {
+				return true, nil
+			}
+		case model.AnnouncementTargetJobLevel:
+			if emp.JobLevelID != nil && t.TargetID == *emp.JobLevelID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (s *announcementService) withReadState(ctx context.Context, userID string, announcements []model.Announcement) ([]dto.AnnouncementResponse, error) {
+	ids := make([]string, len(announcements))
+	for i, a := range announcements {
+		ids[i] = a.ID
+	}
+	read, err := s.repo.FindReadIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, errors.New("failed to fetch read receipts")
+	}
+
+	responses := dto.ToAnnouncementResponses(announcements)
+	for i := range responses {
+		isRead := read[responses[i].ID]
+		responses[i].IsRead = &isRead
+	}
+	return responses, nil
+}
+
+// setContent sets the title and the sanitized body of an announcement
+func (s *announcementService) setContent(a *model.Announcement, title, body string) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return errors.New("title is required")
+	}
+	if utf8.RuneCountInString(title) > maxAnnouncementTitle {
+		return errors.New("title must be at most 255 characters")
+	}
+	if len(body) > maxAnnouncementBody {
+		return errors.New("body is too long")
+	}
+	body = sanitize.HTML(body)
+	if sanitize.Text(body) == "" {
+		return errors.New("body is required")
+	}
+	a.Title = title
+	a.Body = body
+	return nil
+}
+
+// setTargets sets the departments and job levels of an announcement, which
+// must belong to its company
+func (s *announcementService) setTargets(ctx context.Context, a *model.Announcement, departmentIDs, jobLevelIDs []string) error {
+	targets := make([]model.AnnouncementTarget, 0, len(departmentIDs)+len(jobLevelIDs))
+	seen := make(map[string]bool)
+
+	for _, id := range departmentIDs {
+		dept, err := s.deptRepo.FindByID(ctx, id)
+		if err != nil || dept.CompanyID != a.CompanyID {
+			return errors.New("department not found: " + id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			targets = append(targets, model.AnnouncementTarget{TargetType: model.AnnouncementTargetDepartment, TargetID: id})
+		}
+	}
+	for _, id := range jobLevelIDs {
+		level, err := s.jobLevelRepo.FindByID(ctx, id)
+		if err != nil || level.CompanyID != a.CompanyID {
+			return errors.New("job level not found: " + id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			targets = append(targets, model.AnnouncementTarget{TargetType: model.AnnouncementTargetJobLevel, TargetID: id})
+		}
+	}
+
+	a.Targets = targets
+	return nil
+}
+
+func setAttachments(a *model.Announcement, inputs []dto.AnnouncementAttachmentInput) error {
+	if len(inputs) > maxAnnouncementAttachments {
+		return errors.New("at most 10 attachments")
+	}
+	attachments := make([]model.AnnouncementAttachment, len(inputs))
+	for i, in := range inputs {
+		name := strings.TrimSpace(in.Name)
+		if name == "" {
+			return errors.New("attachment name is required")
+		}
+		u, err := url.Parse(strings.TrimSpace(in.URL))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("attachment url must be an http(s) URL")
+		}
+		attachments[i] = model.AnnouncementAttachment{Name: name, URL: u.String()}
+	}
+	a.Attachments = attachments
+	return nil
+}
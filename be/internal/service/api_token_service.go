@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"hris-backend/internal/dto"
+	"hris-backend/internal/model"
+	"hris-backend/internal/permissions"
+	"hris-backend/internal/repository"
+	"hris-backend/pkg/hash"
+)
+
+// APITokenPrefix starts every API token, so AuthMiddleware can tell them
+// from JWTs and secret scanners can recognise leaked ones
+const APITokenPrefix = "hris_"
+
+const (
+	defaultAPITokenDays = 90
+	maxAPITokenDays     = 365
+
+	// apiTokenTouchInterval is how often the last use of a token is written
+	apiTokenTouchInterval = time.Minute
+)
+
+type APITokenService interface {
+	GetByUserID(ctx context.Context, userID string) ([]dto.APITokenResponse, error)
+	Create(ctx context.Context, userID string, granted permissions.Set, req dto.CreateAPITokenRequest) (*dto.CreatedAPITokenResponse, error)
+	Revoke(ctx context.Context, userID, tokenID string) error
+	Authenticate(ctx context.Context, rawToken, ipAddress string) (*dto.APITokenIdentity, error)
+}
+
+type apiTokenService struct {
+	tokenRepo   repository.APITokenRepository
+	userRepo    repository.UserRepository
+	companyRepo repository.CompanyRepository
+}
+
+func NewAPITokenService(tokenRepo repository.APITokenRepository, userRepo repository.UserRepository, companyRepo repository.CompanyRepository) APITokenService {
+	return &apiTokenService{
+		tokenRepo:   tokenRepo,
+		userRepo:    userRepo,
+		companyRepo: companyRepo,
+	}
+}
+
+// GetByUserID lists the user's tokens. The user lookup goes through the
+// request's company scope.
+func (s *apiTokenService) GetByUserID(ctx context.Context, userID string) ([]dto.APITokenResponse, error) {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	tokens, err := s.tokenRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to fetch api tokens")
+	}
+	return dto.ToAPITokenResponses(tokens), nil
+}
+
+// Create issues a token for the user. granted is the user's own permission
+// set: a token can only carry permissions its owner has. The token is
+// returned only this once.
+func (s *apiTokenService) Create(ctx context.Context, userID string, granted permissions.Set, req dto.CreateAPITokenRequest) (*dto.CreatedAPITokenResponse, error) {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if req.CompanyID == "" {
+		return nil, errors.New("company is required")
+	}
+	if _, err := s.companyRepo.FindByID(ctx, req.CompanyID); err != nil {
+		return nil, errors.New("company not found")
+	}
+
+	if len(req.Permissions) == 0 {
+		return nil, errors.New("at least one permission is required")
+	}
+	for _, name := range req.Permissions {
+		if !permissions.IsValid(name) {
+			return nil, errors.New("unknown permission: " + name)
+		}
+		if !granted.Has(name) {
+			return nil, errors.New("cannot grant a permission you do not have: " + name)
+		}
+	}
+
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = defaultAPITokenDays
+	}
+	if days < 0 || days > maxAPITokenDays {
+		return nil, errors.New("expiry must be between 1 and 365 days")
+	}
+
+	secret, err := hash.GenerateToken(32)
+	if err != nil {
+		return nil, errors.New("failed to generate api token")
+	}
+	raw := APITokenPrefix + secret
+
+	token := &model.APIToken{
+		UserID:      userID,
+		CompanyID:   req.CompanyID,
+		Name:        req.Name,
+		Prefix:      raw[:len(APITokenPrefix)+8],
+		TokenHash:   hash.HashToken(raw),
+		Permissions: req.Permissions,
+		ExpiresAt:   time.Now().AddDate(0, 0, days),
+	}
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return nil, errors.New("failed to create api token")
+	}
+
+	return &dto.CreatedAPITokenResponse{
+		APITokenResponse: dto.ToAPITokenResponse(token),
+		Token:            raw,
+	}, nil
+}
+
+func (s *apiTokenService) Revoke(ctx context.Context, userID, tokenID string) error {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return errors.New("user not found")
+	}
+
+	token, err := s.tokenRepo.FindByID(ctx, tokenID)
+	if err != nil || token.UserID != userID {
+		return errors.New("api token not found")
+	}
+	if token.RevokedAt != nil {
+		return nil
+	}
+
+	if err := s.tokenRepo.Revoke(ctx, tokenID); err != nil {
+		return errors.New("failed to revoke api token")
+	}
+	return nil
+}
+
+// Authenticate resolves a raw API token to the identity it acts for and
+// records its use. Revoked and expired tokens, and tokens of deactivated or
+// locked users, are refused.
+func (s *apiTokenService) Authenticate(ctx context.Context, rawToken, ipAddress string) (*dto.APITokenIdentity, error) {
+	if !strings.HasPrefix(rawToken, APITokenPrefix) {
+		return nil, errors.New("invalid api token")
+	}
+
+	token, err := s.tokenRepo.FindByTokenHash(ctx, hash.HashToken(rawToken))
+	if err != nil || !token.IsActive() {
+		return nil, errors.New("invalid or expired api token")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, token.UserID)
+	if err != nil || !user.IsActive || user.IsLocked() {
+		return nil, errors.New("invalid or expired api token")
+	}
+
+	// Failing to record the use must not fail the request
+	s.tokenRepo.Touch(ctx, token.ID, ipAddress, apiTokenTouchInterval)
+
+	return &dto.APITokenIdentity{
+		TokenID:     token.ID,
+		UserID:      user.ID,
+		Email:       user.Email,
+		Role:        string(user.Role),
+		CompanyID:   token.CompanyID,
+		Permissions: token.Permissions,
+	}, nil
+}
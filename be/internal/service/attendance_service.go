@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -11,17 +12,17 @@ import (
 )
 
 type AttendanceService interface {
-	GetAll() ([]dto.AttendanceResponse, error)
-	GetAllPaginated(page, limit int, employeeID string, month, year int, startDate, endDate string) (*dto.PaginatedAttendanceResponse, error)
-	GetByID(id string) (*dto.AttendanceResponse, error)
-	GetByEmployeeID(employeeID string) ([]dto.AttendanceResponse, error)
-	GetByEmployeeIDAndMonth(employeeID string, month, year int) ([]dto.AttendanceResponse, error)
-	GetByMonth(month, year int) ([]dto.AttendanceResponse, error)
-	ClockIn(req dto.ClockInRequest) (*dto.AttendanceResponse, error)
-	ClockOut(id string, req dto.ClockOutRequest) (*dto.AttendanceResponse, error)
-	Create(req dto.CreateAttendanceRequest) (*dto.AttendanceResponse, error)
-	Update(id string, req dto.UpdateAttendanceRequest) (*dto.AttendanceResponse, error)
-	Delete(id string) error
+	GetAll(ctx context.Context) ([]dto.AttendanceResponse, error)
+	GetAllPaginated(ctx context.Context, page, limit int, employeeID string, month, year int, startDate, endDate string) (*dto.PaginatedAttendanceResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.AttendanceResponse, error)
+	GetByEmployeeID(ctx context.Context, employeeID string) ([]dto.AttendanceResponse, error)
+	GetByEmployeeIDAndMonth(ctx context.Context, employeeID string, month, year int) ([]dto.AttendanceResponse, error)
+	GetByMonth(ctx context.Context, month, year int) ([]dto.AttendanceResponse, error)
+	ClockIn(ctx context.Context, req dto.ClockInRequest) (*dto.AttendanceResponse, error)
+	ClockOut(ctx context.Context, id string, req dto.ClockOutRequest) (*dto.AttendanceResponse, error)
+	Create(ctx context.Context, req dto.CreateAttendanceRequest) (*dto.AttendanceResponse, error)
+	Update(ctx context.Context, id string, req dto.UpdateAttendanceRequest) (*dto.AttendanceResponse, error)
+	Delete(ctx context.Context, id string) error
 }
 
 type attendanceService struct {
@@ -71,16 +72,16 @@ func calculateAttendanceStatus(clockIn time.Time, shiftStartStr string) (model.A
 	}
 }
 
-func (s *attendanceService) GetAll() ([]dto.AttendanceResponse, error) {
-	attendances, err := s.attRepo.FindAll()
+func (s *attendanceService) GetAll(ctx context.Context) ([]dto.AttendanceResponse, error) {
+	attendances, err := s.attRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToAttendanceResponses(attendances), nil
 }
 
-func (s *attendanceService) GetAllPaginated(page, limit int, employeeID string, month, year int, startDate, endDate string) (*dto.PaginatedAttendanceResponse, error) {
-	attendances, total, err := s.attRepo.FindAllPaginated(page, limit, employeeID, month, year, startDate, endDate)
+func (s *attendanceService) GetAllPaginated(ctx context.Context, page, limit int, employeeID string, month, year int, startDate, endDate string) (*dto.PaginatedAttendanceResponse, error) {
+	attendances, total, err := s.attRepo.FindAllPaginated(ctx, page, limit, employeeID, month, year, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
@@ -99,8 +100,8 @@ func (s *attendanceService) GetAllPaginated(page, limit int, employeeID string,
 	}, nil
 }
 
-func (s *attendanceService) GetByID(id string) (*dto.AttendanceResponse, error) {
-	att, err := s.attRepo.FindByID(id)
+func (s *attendanceService) GetByID(ctx context.Context, id string) (*dto.AttendanceResponse, error) {
+	att, err := s.attRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("attendance not found")
 	}
@@ -108,33 +109,33 @@ func (s *attendanceService) GetByID(id string) (*dto.AttendanceResponse, error)
 	return &response, nil
 }
 
-func (s *attendanceService) GetByEmployeeID(employeeID string) ([]dto.AttendanceResponse, error) {
-	attendances, err := s.attRepo.FindByEmployeeID(employeeID)
+func (s *attendanceService) GetByEmployeeID(ctx context.Context, employeeID string) ([]dto.AttendanceResponse, error) {
+	attendances, err := s.attRepo.FindByEmployeeID(ctx, employeeID)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToAttendanceResponses(attendances), nil
 }
 
-func (s *attendanceService) GetByEmployeeIDAndMonth(employeeID string, month, year int) ([]dto.AttendanceResponse, error) {
-	attendances, err := s.attRepo.FindByEmployeeIDAndMonth(employeeID, month, year)
+func (s *attendanceService) GetByEmployeeIDAndMonth(ctx context.Context, employeeID string, month, year int) ([]dto.AttendanceResponse, error) {
+	attendances, err := s.attRepo.FindByEmployeeIDAndMonth(ctx, employeeID, month, year)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToAttendanceResponses(attendances), nil
 }
 
-func (s *attendanceService) GetByMonth(month, year int) ([]dto.AttendanceResponse, error) {
-	attendances, err := s.attRepo.FindByMonth(month, year)
+func (s *attendanceService) GetByMonth(ctx context.Context, month, year int) ([]dto.AttendanceResponse, error) {
+	attendances, err := s.attRepo.FindByMonth(ctx, month, year)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToAttendanceResponses(attendances), nil
 }
 
-func (s *attendanceService) ClockIn(req dto.ClockInRequest) (*dto.AttendanceResponse, error) {
+func (s *attendanceService) ClockIn(ctx context.Context, req dto.ClockInRequest) (*dto.AttendanceResponse, error) {
 	// Validate employee exists
-	emp, err := s.empRepo.FindByID(req.EmployeeID)
+	emp, err := s.empRepo.FindByID(ctx, req.EmployeeID)
 	if err != nil {
 		return nil, errors.New("employee not found")
 	}
@@ -142,13 +143,16 @@ func (s *attendanceService) ClockIn(req dto.ClockInRequest) (*dto.AttendanceResp
 	today := time.Now().Truncate(24 * time.Hour)
 
 	// Check if already clocked in today
-	existing, _ := s.attRepo.FindByEmployeeIDAndDate(req.EmployeeID, today)
+	existing, _ := s.attRepo.FindByEmployeeIDAndDate(ctx, req.EmployeeID, today)
 	if existing != nil {
+		if existing.LeaveID != nil {
+			return nil, errors.New("you are on approved leave today")
+		}
 		return nil, errors.New("already clocked in today")
 	}
 
 	// Get employee's shift to determine expected start time
-	shift, err := s.shiftRepo.FindByID(emp.ShiftID)
+	shift, err := s.shiftRepo.FindByID(ctx, emp.ShiftID)
 	if err != nil {
 		return nil, errors.New("shift not found for employee")
 	}
@@ -175,11 +179,11 @@ func (s *attendanceService) ClockIn(req dto.ClockInRequest) (*dto.AttendanceResp
 		ClockInDistanceM: req.DistanceM,
 	}
 
-	if err := s.attRepo.Create(att); err != nil {
+	if err := s.attRepo.Create(ctx, att); err != nil {
 		return nil, errors.New("failed to clock in")
 	}
 
-	created, err := s.attRepo.FindByID(att.ID)
+	created, err := s.attRepo.FindByID(ctx, att.ID)
 	if err != nil {
 		return nil, errors.New("failed to load attendance")
 	}
@@ -188,8 +192,8 @@ func (s *attendanceService) ClockIn(req dto.ClockInRequest) (*dto.AttendanceResp
 	return &response, nil
 }
 
-func (s *attendanceService) ClockOut(id string, req dto.ClockOutRequest) (*dto.AttendanceResponse, error) {
-	att, err := s.attRepo.FindByID(id)
+func (s *attendanceService) ClockOut(ctx context.Context, id string, req dto.ClockOutRequest) (*dto.AttendanceResponse, error) {
+	att, err := s.attRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("attendance not found")
 	}
@@ -221,7 +225,7 @@ func (s *attendanceService) ClockOut(id string, req dto.ClockOutRequest) (*dto.A
 		att.ClockOutDistanceM = req.DistanceM
 	}
 
-	if err := s.attRepo.Update(att); err != nil {
+	if err := s.attRepo.Update(ctx, att); err != nil {
 		return nil, errors.New("failed to clock out")
 	}
 
@@ -229,8 +233,8 @@ func (s *attendanceService) ClockOut(id string, req dto.ClockOutRequest) (*dto.A
 	return &response, nil
 }
 
-func (s *attendanceService) Create(req dto.CreateAttendanceRequest) (*dto.AttendanceResponse, error) {
-	_, err := s.empRepo.FindByID(req.EmployeeID)
+func (s *attendanceService) Create(ctx context.Context, req dto.CreateAttendanceRequest) (*dto.AttendanceResponse, error) {
+	_, err := s.empRepo.FindByID(ctx, req.EmployeeID)
 	if err != nil {
 		return nil, errors.New("employee not found")
 	}
@@ -241,7 +245,7 @@ func (s *attendanceService) Create(req dto.CreateAttendanceRequest) (*dto.Attend
 	}
 
 	// Check for duplicate
-	existing, _ := s.attRepo.FindByEmployeeIDAndDate(req.EmployeeID, date)
+	existing, _ := s.attRepo.FindByEmployeeIDAndDate(ctx, req.EmployeeID, date)
 	if existing != nil {
 		return nil, errors.New("attendance record already exists for this date")
 	}
@@ -270,11 +274,11 @@ func (s *attendanceService) Create(req dto.CreateAttendanceRequest) (*dto.Attend
 		att.ClockOut = &co
 	}
 
-	if err := s.attRepo.Create(att); err != nil {
+	if err := s.attRepo.Create(ctx, att); err != nil {
 		return nil, errors.New("failed to create attendance")
 	}
 
-	created, err := s.attRepo.FindByID(att.ID)
+	created, err := s.attRepo.FindByID(ctx, att.ID)
 	if err != nil {
 		return nil, errors.New("failed to load attendance")
 	}
@@ -283,8 +287,8 @@ func (s *attendanceService) Create(req dto.CreateAttendanceRequest) (*dto.Attend
 	return &response, nil
 }
 
-func (s *attendanceService) Update(id string, req dto.UpdateAttendanceRequest) (*dto.AttendanceResponse, error) {
-	att, err := s.attRepo.FindByID(id)
+func (s *attendanceService) Update(ctx context.Context, id string, req dto.UpdateAttendanceRequest) (*dto.AttendanceResponse, error) {
+	att, err := s.attRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("attendance not found")
 	}
@@ -316,7 +320,7 @@ func (s *attendanceService) Update(id string, req dto.UpdateAttendanceRequest) (
 		att.Notes = req.Notes
 	}
 
-	if err := s.attRepo.Update(att); err != nil {
+	if err := s.attRepo.Update(ctx, att); err != nil {
 		return nil, errors.New("failed to update attendance")
 	}
 
@@ -324,10 +328,10 @@ func (s *attendanceService) Update(id string, req dto.UpdateAttendanceRequest) (
 	return &response, nil
 }
 
-func (s *attendanceService) Delete(id string) error {
-	_, err := s.attRepo.FindByID(id)
+func (s *attendanceService) Delete(ctx context.Context, id string) error {
+	_, err := s.attRepo.FindByID(ctx, id)
 	if err != nil {
 		return errors.New("attendance not found")
 	}
-	return s.attRepo.Delete(id)
+	return s.attRepo.Delete(ctx, id)
 }
@@ -1,75 +1,305 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"log"
+	"sync"
+	"time"
 
 	"hris-backend/config"
 	"hris-backend/internal/dto"
+	"hris-backend/internal/model"
 	"hris-backend/internal/repository"
 	"hris-backend/pkg/hash"
 	jwtPkg "hris-backend/pkg/jwt"
+	"hris-backend/pkg/kafka"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Login throttling errors. Handlers answer them with 429 Too Many Requests.
+var (
+	ErrLoginThrottled = errors.New("too many failed login attempts, try again later")
+	ErrAccountLocked  = errors.New("account is temporarily locked after too many failed login attempts")
 )
 
+// errInvalidCredentials answers every login that fails before the password
+// is verified, whatever the reason, so answers do not reveal which emails
+// have an account
+var errInvalidCredentials = errors.New("invalid email or password")
+
+// unknownUserHash is checked passwords against for emails without an
+// account, so they take as long to reject as a wrong password
+var unknownUserHash = sync.OnceValue(func() string {
+	hashed, _ := hash.HashPassword("unknown-user")
+	return hashed
+})
+
+// PasswordResetMailer emails password reset tokens. Tokens are handed to it
+// directly rather than through the outbox, so they are never stored in plain
+// text.
+type PasswordResetMailer interface {
+	SendPasswordReset(user *model.User, token string, expiresAt time.Time) error
+}
+
+// loginDelayCap bounds the progressive delay between failed logins
+const loginDelayCap = 30 * time.Second
+
 type AuthService interface {
-	Login(req dto.LoginRequest) (*dto.TokenResponse, string, error)
-	RefreshToken(refreshToken string) (*dto.TokenResponse, string, error)
+	Login(ctx context.Context, req dto.LoginRequest, userAgent, ipAddress string) (*dto.TokenResponse, string, error)
+	RefreshToken(ctx context.Context, refreshToken, userAgent, ipAddress string) (*dto.TokenResponse, string, error)
+	Logout(ctx context.Context, refreshToken, sessionID string) error
+	ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) error
+	ChangePassword(ctx context.Context, userID, sessionID string, req dto.ChangePasswordRequest) (*dto.TokenResponse, error)
+	VerifyTwoFactor(ctx context.Context, req dto.VerifyTwoFactorRequest, userAgent, ipAddress string) (*dto.TokenResponse, string, error)
+	SingleSignOn(ctx context.Context, userID, userAgent, ipAddress string) (*dto.TokenResponse, string, error)
 }
 
 type authService struct {
-	userRepo repository.UserRepository
-	cfg      *config.Config
+	userRepo        repository.UserRepository
+	sessionRepo     repository.SessionRepository
+	resetRepo       repository.PasswordResetRepository
+	twoFactorRepo   repository.TwoFactorRepository
+	attemptRepo     repository.LoginAttemptRepository
+	empRepo         repository.EmployeeRepository
+	userCompanyRepo repository.UserCompanyRepository
+	resetMailer     PasswordResetMailer
+	keys            *jwtPkg.KeySet
+	cfg             *config.Config
 }
 
-func NewAuthService(userRepo repository.UserRepository, cfg *config.Config) AuthService {
+func NewAuthService(
+	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	resetRepo repository.PasswordResetRepository,
+	twoFactorRepo repository.TwoFactorRepository,
+	attemptRepo repository.LoginAttemptRepository,
+	empRepo repository.EmployeeRepository,
+	userCompanyRepo repository.UserCompanyRepository,
+	resetMailer PasswordResetMailer,
+	keys *jwtPkg.KeySet,
+	cfg *config.Config,
+) AuthService {
 	return &authService{
-		userRepo: userRepo,
-		cfg:      cfg,
+		userRepo:        userRepo,
+		sessionRepo:     sessionRepo,
+		resetRepo:       resetRepo,
+		twoFactorRepo:   twoFactorRepo,
+		attemptRepo:     attemptRepo,
+		empRepo:         empRepo,
+		userCompanyRepo: userCompanyRepo,
+		resetMailer:     resetMailer,
+		keys:            keys,
+		cfg:             cfg,
 	}
 }
 
-func (s *authService) Login(req dto.LoginRequest) (*dto.TokenResponse, string, error) {
-	user, err := s.userRepo.FindByEmail(req.Email)
-	if err != nil {
-		return nil, "", errors.New("invalid email or password")
+// Login checks the credentials and opens a session. Every attempt is
+// audited. Failures slow further attempts on the account down progressively
+// and lock it after LoginMaxFailures; an IP with LoginIPMaxFailures failures
+// within LoginIPWindow is blocked. Unknown emails and throttled or locked
+// accounts get the same error as a wrong password.
+func (s *authService) Login(ctx context.Context, req dto.LoginRequest, userAgent, ipAddress string) (*dto.TokenResponse, string, error) {
+	attempt := &model.LoginAttempt{Email: req.Email, IPAddress: ipAddress, UserAgent: truncate(userAgent, 500)}
+
+	if s.ipBlocked(ctx, ipAddress) {
+		s.recordAttempt(ctx, attempt, model.LoginThrottled)
+		return nil, "", ErrLoginThrottled
+	}
+
+	// Service accounts only authenticate with API tokens
+	user, err := s.userRepo.FindByEmail(ctx, req.Email)
+	if err != nil || user.IsServiceAccount {
+		hash.CheckPassword(req.Password, unknownUserHash())
+		s.recordAttempt(ctx, attempt, model.LoginUnknownUser)
+		return nil, "", errInvalidCredentials
+	}
+	s.bindAttempt(ctx, attempt, user)
+
+	// The password is checked either way, so a throttled account answers as
+	// slowly as any other, but its result is not acted on while throttled
+	passwordValid := hash.CheckPassword(req.Password, user.Password)
+	if err := s.checkThrottle(ctx, attempt, user); err != nil {
+		return nil, "", errInvalidCredentials
+	}
+	if !passwordValid {
+		s.loginFailed(ctx, attempt, user, model.LoginInvalidPassword)
+		return nil, "", errInvalidCredentials
 	}
 
 	if !user.IsActive {
+		s.recordAttempt(ctx, attempt, model.LoginDeactivated)
 		return nil, "", errors.New("account is deactivated")
 	}
 
-	if !hash.CheckPassword(req.Password, user.Password) {
-		return nil, "", errors.New("invalid email or password")
+	// The failure count is kept until a 2FA code is verified, so the
+	// password cannot be used to reset the count on code guesses
+	if challenge, err := s.twoFactorChallenge(ctx, attempt, user); err != nil || challenge != nil {
+		return challenge, "", err
 	}
 
-	accessToken, err := jwtPkg.GenerateAccessToken(
-		user.ID, user.Email, string(user.Role),
-		s.cfg.JWTSecret, s.cfg.JWTAccessExpiry,
-	)
+	return s.loginSucceeded(ctx, attempt, user)
+}
+
+// twoFactorChallenge returns the challenge a user with 2FA on must answer
+// with a code instead of getting a session, and nil for users without 2FA.
+// Only a user without a TOTP secret skips the challenge: a failed lookup must
+// not let the first factor alone through.
+func (s *authService) twoFactorChallenge(ctx context.Context, attempt *model.LoginAttempt, user *model.User) (*dto.TokenResponse, error) {
+	tf, err := s.twoFactorRepo.FindByUserID(ctx, user.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
 	if err != nil {
-		return nil, "", errors.New("failed to generate access token")
+		return nil, errors.New("failed to check two-factor authentication")
+	}
+	if !tf.Enabled {
+		return nil, nil
 	}
 
-	refreshToken, err := jwtPkg.GenerateRefreshToken(
-		user.ID, s.cfg.JWTRefreshSecret, s.cfg.JWTRefreshExpiry,
-	)
+	challengeID := uuid.New().String()
+	if err := s.twoFactorRepo.SetChallenge(ctx, user.ID, challengeID); err != nil {
+		return nil, errors.New("failed to generate challenge token")
+	}
+	challenge, err := jwtPkg.GenerateChallengeToken(user.ID, challengeID, s.keys, s.cfg.TwoFactorChallengeExpiry)
 	if err != nil {
-		return nil, "", errors.New("failed to generate refresh token")
+		return nil, errors.New("failed to generate challenge token")
 	}
-
+	s.recordAttempt(ctx, attempt, model.LoginChallengeIssued)
 	return &dto.TokenResponse{
-		AccessToken: accessToken,
-		TokenType:   "Bearer",
-		ExpiresIn:   int(s.cfg.JWTAccessExpiry.Seconds()),
-	}, refreshToken, nil
+		ExpiresIn:         int(s.cfg.TwoFactorChallengeExpiry.Seconds()),
+		TwoFactorRequired: true,
+		ChallengeToken:    challenge,
+	}, nil
+}
+
+// VerifyTwoFactor completes a login that returned a challenge, with a TOTP
+// code or a recovery code. A challenge is spent by the first attempt, right
+// or wrong; after a wrong code the user logs in again.
+func (s *authService) VerifyTwoFactor(ctx context.Context, req dto.VerifyTwoFactorRequest, userAgent, ipAddress string) (*dto.TokenResponse, string, error) {
+	userID, challengeID, err := jwtPkg.ValidateChallengeToken(req.ChallengeToken, s.keys)
+	if err != nil {
+		return nil, "", errors.New("invalid or expired challenge")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, "", errors.New("user not found")
+	}
+
+	consumed, err := s.twoFactorRepo.ConsumeChallenge(ctx, user.ID, challengeID)
+	if err != nil {
+		return nil, "", errors.New("failed to verify challenge")
+	}
+	if !consumed {
+		return nil, "", errors.New("invalid or expired challenge")
+	}
+
+	attempt := &model.LoginAttempt{Email: user.Email, IPAddress: ipAddress, UserAgent: truncate(userAgent, 500)}
+	s.bindAttempt(ctx, attempt, user)
+
+	if s.ipBlocked(ctx, ipAddress) {
+		s.recordAttempt(ctx, attempt, model.LoginThrottled)
+		return nil, "", ErrLoginThrottled
+	}
+	if err := s.checkThrottle(ctx, attempt, user); err != nil {
+		return nil, "", err
+	}
+
+	if !user.IsActive {
+		s.recordAttempt(ctx, attempt, model.LoginDeactivated)
+		return nil, "", errors.New("account is deactivated")
+	}
+
+	tf, err := s.twoFactorRepo.FindByUserID(ctx, user.ID)
+	if err != nil || !tf.Enabled {
+		return nil, "", errors.New("invalid or expired challenge")
+	}
+	if !checkSecondFactor(ctx, s.twoFactorRepo, tf, req.Code) {
+		if s.loginFailed(ctx, attempt, user, model.LoginInvalidTwoFactor) {
+			return nil, "", ErrAccountLocked
+		}
+		return nil, "", errors.New("invalid code")
+	}
+
+	return s.loginSucceeded(ctx, attempt, user)
 }
 
-func (s *authService) RefreshToken(refreshToken string) (*dto.TokenResponse, string, error) {
-	userID, err := jwtPkg.ValidateRefreshToken(refreshToken, s.cfg.JWTRefreshSecret)
+// SingleSignOn opens a session for a user whose identity an OIDC provider
+// confirmed. The provider checked the credentials, so no password is asked
+// for, but users with 2FA on get a challenge like after a password login.
+// Deactivated and locked accounts are refused.
+func (s *authService) SingleSignOn(ctx context.Context, userID, userAgent, ipAddress string) (*dto.TokenResponse, string, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
+		return nil, "", errors.New("user not found")
+	}
+
+	attempt := &model.LoginAttempt{Email: user.Email, IPAddress: ipAddress, UserAgent: truncate(userAgent, 500)}
+	s.bindAttempt(ctx, attempt, user)
+
+	if user.IsLocked() {
+		s.recordAttempt(ctx, attempt, model.LoginLocked)
+		return nil, "", ErrAccountLocked
+	}
+	if !user.IsActive {
+		s.recordAttempt(ctx, attempt, model.LoginDeactivated)
+		return nil, "", errors.New("account is deactivated")
+	}
+
+	if challenge, err := s.twoFactorChallenge(ctx, attempt, user); err != nil || challenge != nil {
+		return challenge, "", err
+	}
+
+	return s.loginSucceeded(ctx, attempt, user)
+}
+
+// loginSucceeded clears the user's failed logins and opens a session
+func (s *authService) loginSucceeded(ctx context.Context, attempt *model.LoginAttempt, user *model.User) (*dto.TokenResponse, string, error) {
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		if err := s.userRepo.ResetLoginFailures(ctx, user.ID); err != nil {
+			return nil, "", errors.New("failed to reset login failures")
+		}
+	}
+	s.recordAttempt(ctx, attempt, model.LoginSuccess)
+
+	session := &model.Session{
+		UserID:     user.ID,
+		TokenID:    uuid.New().String(),
+		UserAgent:  attempt.UserAgent,
+		IPAddress:  attempt.IPAddress,
+		LastUsedAt: time.Now(),
+		ExpiresAt:  time.Now().Add(s.cfg.JWTRefreshExpiry),
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, "", errors.New("failed to create session")
+	}
+
+	return s.issueTokens(ctx, user, session.ID, session.TokenID)
+}
+
+// RefreshToken rotates the refresh token of a session. A token that is no
+// longer the session's current one has been used before, which means it
+// leaked: the session is revoked so neither the thief nor the user can keep
+// refreshing with it.
+func (s *authService) RefreshToken(ctx context.Context, refreshToken, userAgent, ipAddress string) (*dto.TokenResponse, string, error) {
+	claims, err := jwtPkg.ValidateRefreshToken(refreshToken, s.cfg.JWTRefreshSecret)
+	if err != nil || claims.SessionID == "" || claims.ID == "" {
 		return nil, "", errors.New("invalid refresh token")
 	}
 
-	user, err := s.userRepo.FindByID(userID)
+	session, err := s.sessionRepo.FindByID(ctx, claims.SessionID)
+	if err != nil || session.UserID != claims.Subject {
+		return nil, "", errors.New("invalid refresh token")
+	}
+	if !session.IsActive() {
+		return nil, "", errors.New("session has been revoked or has expired")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, session.UserID)
 	if err != nil {
 		return nil, "", errors.New("user not found")
 	}
@@ -78,16 +308,202 @@ func (s *authService) RefreshToken(refreshToken string) (*dto.TokenResponse, str
 		return nil, "", errors.New("account is deactivated")
 	}
 
-	accessToken, err := jwtPkg.GenerateAccessToken(
-		user.ID, user.Email, string(user.Role),
-		s.cfg.JWTSecret, s.cfg.JWTAccessExpiry,
-	)
+	newTokenID := uuid.New().String()
+	rotated := false
+	if session.TokenID == claims.ID {
+		rotated, err = s.sessionRepo.Rotate(ctx, session.ID, claims.ID, newTokenID,
+			truncate(userAgent, 500), ipAddress, time.Now().Add(s.cfg.JWTRefreshExpiry))
+		if err != nil {
+			return nil, "", errors.New("failed to rotate refresh token")
+		}
+	}
+	if !rotated {
+		if err := s.sessionRepo.Revoke(ctx, session.ID, model.SessionRevokedReuseDetected); err != nil {
+			return nil, "", errors.New("failed to revoke session")
+		}
+		return nil, "", errors.New("refresh token reuse detected, session revoked")
+	}
+
+	return s.issueTokens(ctx, user, session.ID, newTokenID)
+}
+
+// Logout revokes the session of the refresh token, or sessionID when the
+// token is missing or unreadable
+func (s *authService) Logout(ctx context.Context, refreshToken, sessionID string) error {
+	if refreshToken != "" {
+		if claims, err := jwtPkg.ValidateRefreshToken(refreshToken, s.cfg.JWTRefreshSecret); err == nil && claims.SessionID != "" {
+			sessionID = claims.SessionID
+		}
+	}
+	if sessionID == "" {
+		return nil
+	}
+
+	if err := s.sessionRepo.Revoke(ctx, sessionID, model.SessionRevokedLogout); err != nil {
+		return errors.New("failed to revoke session")
+	}
+	return nil
+}
+
+// ForgotPassword issues a reset token for the account with the given email,
+// invalidating earlier ones, and emails it to the user. The token is only
+// ever stored hashed: the event announcing the request does not carry it.
+// It returns nil when there is no active account, so callers cannot tell
+// which emails are registered; the email is sent in the background for the
+// same reason. Without a mailer no token is issued.
+func (s *authService) ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) error {
+	user, err := s.userRepo.FindByEmail(ctx, req.Email)
+	if err != nil || !user.IsActive || user.IsServiceAccount {
+		return nil
+	}
+	if s.resetMailer == nil {
+		log.Printf("[auth] password reset for user %s not sent: SMTP is not configured", user.ID)
+		return nil
+	}
+
+	token, err := hash.GenerateToken(32)
+	if err != nil {
+		return errors.New("failed to generate reset token")
+	}
+
+	reset := &model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hash.HashToken(token),
+		ExpiresAt: time.Now().Add(s.cfg.PasswordResetExpiry),
+	}
+	event, err := kafka.NewOutboxEvent(ctx, kafka.EventPasswordResetRequested, user.ID, "", kafka.PasswordResetRequestedPayload{
+		UserID:    user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		ExpiresAt: reset.ExpiresAt.Format(time.RFC3339),
+	})
+	if err != nil {
+		return errors.New("failed to issue reset token")
+	}
+
+	if err := s.resetRepo.Issue(ctx, reset, []model.OutboxEvent{event}); err != nil {
+		return errors.New("failed to issue reset token")
+	}
+
+	go func() {
+		if err := s.resetMailer.SendPasswordReset(user, token, reset.ExpiresAt); err != nil {
+			log.Printf("[auth] send password reset to user %s: %v", user.ID, err)
+		}
+	}()
+	return nil
+}
+
+// ResetPassword sets a new password with a reset token and logs the user out
+// everywhere
+func (s *authService) ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) error {
+	reset, err := s.resetRepo.FindByTokenHash(ctx, hash.HashToken(req.Token))
+	if err != nil || reset.UsedAt != nil || time.Now().After(reset.ExpiresAt) {
+		return errors.New("invalid or expired reset token")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, reset.UserID)
+	if err != nil || !user.IsActive {
+		return errors.New("invalid or expired reset token")
+	}
+
+	used, err := s.resetRepo.MarkUsed(ctx, reset.ID)
+	if err != nil {
+		return errors.New("failed to reset password")
+	}
+	if !used {
+		return errors.New("invalid or expired reset token")
+	}
+
+	if err := s.setPassword(ctx, user, req.NewPassword); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.RevokeAllForUser(ctx, user.ID, "", model.SessionRevokedPassword); err != nil {
+		return errors.New("failed to revoke sessions")
+	}
+	return nil
+}
+
+// ChangePassword replaces the password of a logged-in user after checking
+// the current one. Other sessions are logged out; the calling session gets a
+// new access token, which no longer carries the must-change-password flag.
+func (s *authService) ChangePassword(ctx context.Context, userID, sessionID string, req dto.ChangePasswordRequest) (*dto.TokenResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if !hash.CheckPassword(req.CurrentPassword, user.Password) {
+		return nil, errors.New("current password is incorrect")
+	}
+	if req.CurrentPassword == req.NewPassword {
+		return nil, errors.New("new password must differ from the current password")
+	}
+
+	if err := s.setPassword(ctx, user, req.NewPassword); err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.RevokeAllForUser(ctx, user.ID, sessionID, model.SessionRevokedPassword); err != nil {
+		return nil, errors.New("failed to revoke sessions")
+	}
+
+	accessToken, err := s.generateAccessToken(ctx, user, sessionID)
 	if err != nil {
-		return nil, "", errors.New("failed to generate access token")
+		return nil, err
 	}
+	return &dto.TokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(s.cfg.JWTAccessExpiry.Seconds()),
+	}, nil
+}
 
-	newRefreshToken, err := jwtPkg.GenerateRefreshToken(
-		user.ID, s.cfg.JWTRefreshSecret, s.cfg.JWTRefreshExpiry,
+func (s *authService) setPassword(ctx context.Context, user *model.User, password string) error {
+	hashedPassword, err := hash.HashPassword(password)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+
+	user.Password = hashedPassword
+	user.MustChangePassword = false
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return errors.New("failed to update password")
+	}
+	return nil
+}
+
+// generateAccessToken issues an access token, restricted when the user must
+// first change their password or enroll in 2FA
+func (s *authService) generateAccessToken(ctx context.Context, user *model.User, sessionID string) (string, error) {
+	claims := jwtPkg.TokenClaims{
+		UserID:             user.ID,
+		Email:              user.Email,
+		Role:               string(user.Role),
+		SessionID:          sessionID,
+		MustChangePassword: user.MustChangePassword,
+	}
+	claims.Issuer = s.cfg.AppBaseURL
+	if twoFactorRequired(s.cfg, string(user.Role)) {
+		tf, err := s.twoFactorRepo.FindByUserID(ctx, user.ID)
+		claims.TwoFactorSetupRequired = err != nil || !tf.Enabled
+	}
+
+	accessToken, err := jwtPkg.GenerateAccessToken(claims, s.keys, s.cfg.JWTAccessExpiry)
+	if err != nil {
+		return "", errors.New("failed to generate access token")
+	}
+	return accessToken, nil
+}
+
+func (s *authService) issueTokens(ctx context.Context, user *model.User, sessionID, tokenID string) (*dto.TokenResponse, string, error) {
+	accessToken, err := s.generateAccessToken(ctx, user, sessionID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	refreshToken, err := jwtPkg.GenerateRefreshToken(
+		user.ID, sessionID, tokenID, s.cfg.JWTRefreshSecret, s.cfg.JWTRefreshExpiry,
 	)
 	if err != nil {
 		return nil, "", errors.New("failed to generate refresh token")
@@ -97,5 +513,95 @@ func (s *authService) RefreshToken(refreshToken string) (*dto.TokenResponse, str
 		AccessToken: accessToken,
 		TokenType:   "Bearer",
 		ExpiresIn:   int(s.cfg.JWTAccessExpiry.Seconds()),
-	}, newRefreshToken, nil
+	}, refreshToken, nil
+}
+
+// ipBlocked reports whether the IP has too many recent failed logins
+func (s *authService) ipBlocked(ctx context.Context, ipAddress string) bool {
+	failures, err := s.attemptRepo.CountFailuresByIP(ctx, ipAddress, time.Now().Add(-s.cfg.LoginIPWindow))
+	return err == nil && failures >= int64(s.cfg.LoginIPMaxFailures)
+}
+
+// checkThrottle rejects logins to a locked account, and logins that come
+// sooner after the last failure than the progressive delay allows: 1s after
+// the first failure, doubling with each further one up to loginDelayCap
+func (s *authService) checkThrottle(ctx context.Context, attempt *model.LoginAttempt, user *model.User) error {
+	if user.IsLocked() {
+		s.recordAttempt(ctx, attempt, model.LoginLocked)
+		return ErrAccountLocked
+	}
+
+	if user.FailedLoginAttempts > 0 && user.LastFailedLoginAt != nil {
+		doublings := min(user.FailedLoginAttempts-1, 5)
+		delay := min(time.Second<<doublings, loginDelayCap)
+		if time.Now().Before(user.LastFailedLoginAt.Add(delay)) {
+			s.recordAttempt(ctx, attempt, model.LoginThrottled)
+			return ErrLoginThrottled
+		}
+	}
+	return nil
+}
+
+// loginFailed records a failed credential check and reports whether this
+// failure locked the account. Locking an account alerts its admins.
+func (s *authService) loginFailed(ctx context.Context, attempt *model.LoginAttempt, user *model.User, result model.LoginResult) bool {
+	s.recordAttempt(ctx, attempt, result)
+
+	lockedUntil := time.Now().Add(s.cfg.LoginLockoutDuration)
+	companyID := ""
+	if attempt.CompanyID != nil {
+		companyID = *attempt.CompanyID
+	}
+	var lockEvents []model.OutboxEvent
+	if event, err := kafka.NewOutboxEvent(ctx, kafka.EventAccountLocked, user.ID, companyID, kafka.AccountLockedPayload{
+		UserID:      user.ID,
+		Name:        user.Name,
+		Email:       user.Email,
+		CompanyID:   companyID,
+		IPAddress:   attempt.IPAddress,
+		LockedUntil: lockedUntil.Format(time.RFC3339),
+	}); err == nil {
+		lockEvents = append(lockEvents, event)
+	}
+
+	locked, err := s.userRepo.RecordLoginFailure(ctx, user.ID, s.cfg.LoginMaxFailures, lockedUntil, lockEvents)
+	return err == nil && locked
+}
+
+// bindAttempt ties an attempt to the user and their company: the company of
+// their employee record, else their first company binding
+func (s *authService) bindAttempt(ctx context.Context, attempt *model.LoginAttempt, user *model.User) {
+	attempt.UserID = &user.ID
+	if emp, err := s.empRepo.FindByUserID(ctx, user.ID); err == nil {
+		attempt.CompanyID = &emp.CompanyID
+		return
+	}
+	if bindings, err := s.userCompanyRepo.FindByUserID(ctx, user.ID); err == nil && len(bindings) > 0 {
+		attempt.CompanyID = &bindings[0].CompanyID
+	}
+}
+
+// recordAttempt writes the attempt to the login audit log. A failure to
+// audit must not block logins, so errors are dropped.
+func (s *authService) recordAttempt(ctx context.Context, attempt *model.LoginAttempt, result model.LoginResult) {
+	entry := *attempt
+	entry.Result = result
+	s.attemptRepo.Create(ctx, &entry)
+}
+
+// unusablePassword returns the hash of a random password nobody knows, for
+// accounts that do not sign in with a password
+func unusablePassword() (string, error) {
+	password, err := hash.GenerateToken(32)
+	if err != nil {
+		return "", err
+	}
+	return hash.HashPassword(password)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
 }
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"hris-backend/internal/dto"
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+	"hris-backend/internal/tenant"
+)
+
+type CompanyScopeService interface {
+	Resolve(ctx context.Context, userID, role string) (*tenant.Scope, error)
+	GetCompanies(ctx context.Context, userID string) ([]dto.UserCompanyResponse, error)
+	SetCompanies(ctx context.Context, userID string, req dto.SetUserCompaniesRequest) ([]dto.UserCompanyResponse, error)
+}
+
+type companyScopeService struct {
+	userCompanyRepo repository.UserCompanyRepository
+	userRepo        repository.UserRepository
+	empRepo         repository.EmployeeRepository
+	companyRepo     repository.CompanyRepository
+}
+
+func NewCompanyScopeService(
+	userCompanyRepo repository.UserCompanyRepository,
+	userRepo repository.UserRepository,
+	empRepo repository.EmployeeRepository,
+	companyRepo repository.CompanyRepository,
+) CompanyScopeService {
+	return &companyScopeService{
+		userCompanyRepo: userCompanyRepo,
+		userRepo:        userRepo,
+		empRepo:         empRepo,
+		companyRepo:     companyRepo,
+	}
+}
+
+// Resolve returns the companies a user may access: the company of their
+// employee record first, then their company bindings. Superadmins are not
+// scoped and get a nil scope.
+func (s *companyScopeService) Resolve(ctx context.Context, userID, role string) (*tenant.Scope, error) {
+	if role == string(model.RoleSuperAdmin) {
+		return nil, nil
+	}
+
+	scope := &tenant.Scope{UserID: userID, CompanyIDs: []string{}}
+	if emp, err := s.empRepo.FindByUserID(ctx, userID); err == nil {
+		scope.CompanyIDs = append(scope.CompanyIDs, emp.CompanyID)
+	}
+
+	bindings, err := s.userCompanyRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to resolve company scope")
+	}
+	for _, b := range bindings {
+		if !scope.Allows(b.CompanyID) {
+			scope.CompanyIDs = append(scope.CompanyIDs, b.CompanyID)
+		}
+	}
+	return scope, nil
+}
+
+func (s *companyScopeService) GetCompanies(ctx context.Context, userID string) ([]dto.UserCompanyResponse, error) {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	bindings, err := s.userCompanyRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to fetch user companies")
+	}
+	return dto.ToUserCompanyResponses(bindings), nil
+}
+
+// SetCompanies replaces the companies a user is bound to. The company of the
+// user's employee record is always in scope and need not be listed.
+func (s *companyScopeService) SetCompanies(ctx context.Context, userID string, req dto.SetUserCompaniesRequest) ([]dto.UserCompanyResponse, error) {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	companyIDs := make([]string, 0, len(req.CompanyIDs))
+	seen := make(map[string]bool)
+	for _, companyID := range req.CompanyIDs {
+		if seen[companyID] {
+			continue
+		}
+		if _, err := s.companyRepo.FindByID(ctx, companyID); err != nil {
+			return nil, errors.New("company not found: " + companyID)
+		}
+		seen[companyID] = true
+		companyIDs = append(companyIDs, companyID)
+	}
+
+	if err := s.userCompanyRepo.ReplaceForUser(ctx, userID, companyIDs); err != nil {
+		return nil, errors.New("failed to update user companies")
+	}
+	return s.GetCompanies(ctx, userID)
+}
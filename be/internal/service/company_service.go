@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"hris-backend/internal/dto"
@@ -9,13 +10,13 @@ import (
 )
 
 type CompanyService interface {
-	GetAll() ([]dto.CompanyResponse, error)
-	GetAllPaginated(page, limit int, search, sortBy, sortOrder string) (*dto.PaginatedCompanyResponse, error)
-	GetByID(id string) (*dto.CompanyResponse, error)
-	Create(req dto.CreateCompanyRequest) (*dto.CompanyResponse, error)
-	Update(id string, req dto.UpdateCompanyRequest) (*dto.CompanyResponse, error)
-	Delete(id string) error
-	DeleteMultiple(ids []string) error
+	GetAll(ctx context.Context) ([]dto.CompanyResponse, error)
+	GetAllPaginated(ctx context.Context, page, limit int, search, sortBy, sortOrder string) (*dto.PaginatedCompanyResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.CompanyResponse, error)
+	Create(ctx context.Context, req dto.CreateCompanyRequest) (*dto.CompanyResponse, error)
+	Update(ctx context.Context, id string, req dto.UpdateCompanyRequest) (*dto.CompanyResponse, error)
+	Delete(ctx context.Context, id string) error
+	DeleteMultiple(ctx context.Context, ids []string) error
 }
 
 type companyService struct {
@@ -26,16 +27,16 @@ func NewCompanyService(companyRepo repository.CompanyRepository) CompanyService
 	return &companyService{companyRepo: companyRepo}
 }
 
-func (s *companyService) GetAll() ([]dto.CompanyResponse, error) {
-	companies, err := s.companyRepo.FindAll()
+func (s *companyService) GetAll(ctx context.Context) ([]dto.CompanyResponse, error) {
+	companies, err := s.companyRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToCompanyResponses(companies), nil
 }
 
-func (s *companyService) GetAllPaginated(page, limit int, search, sortBy, sortOrder string) (*dto.PaginatedCompanyResponse, error) {
-	companies, total, err := s.companyRepo.FindAllPaginated(page, limit, search, sortBy, sortOrder)
+func (s *companyService) GetAllPaginated(ctx context.Context, page, limit int, search, sortBy, sortOrder string) (*dto.PaginatedCompanyResponse, error) {
+	companies, total, err := s.companyRepo.FindAllPaginated(ctx, page, limit, search, sortBy, sortOrder)
 	if err != nil {
 		return nil, err
 	}
@@ -54,8 +55,8 @@ func (s *companyService) GetAllPaginated(page, limit int, search, sortBy, sortOr
 	}, nil
 }
 
-func (s *companyService) GetByID(id string) (*dto.CompanyResponse, error) {
-	company, err := s.companyRepo.FindByID(id)
+func (s *companyService) GetByID(ctx context.Context, id string) (*dto.CompanyResponse, error) {
+	company, err := s.companyRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("company not found")
 	}
@@ -63,7 +64,7 @@ func (s *companyService) GetByID(id string) (*dto.CompanyResponse, error) {
 	return &response, nil
 }
 
-func (s *companyService) Create(req dto.CreateCompanyRequest) (*dto.CompanyResponse, error) {
+func (s *companyService) Create(ctx context.Context, req dto.CreateCompanyRequest) (*dto.CompanyResponse, error) {
 	company := &model.Company{
 		Name:     req.Name,
 		Address:  req.Address,
@@ -75,7 +76,7 @@ func (s *companyService) Create(req dto.CreateCompanyRequest) (*dto.CompanyRespo
 		IsActive: true,
 	}
 
-	if err := s.companyRepo.Create(company); err != nil {
+	if err := s.companyRepo.Create(ctx, company); err != nil {
 		return nil, errors.New("failed to create company")
 	}
 
@@ -83,8 +84,8 @@ func (s *companyService) Create(req dto.CreateCompanyRequest) (*dto.CompanyRespo
 	return &response, nil
 }
 
-func (s *companyService) Update(id string, req dto.UpdateCompanyRequest) (*dto.CompanyResponse, error) {
-	company, err := s.companyRepo.FindByID(id)
+func (s *companyService) Update(ctx context.Context, id string, req dto.UpdateCompanyRequest) (*dto.CompanyResponse, error) {
+	company, err := s.companyRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("company not found")
 	}
@@ -114,7 +115,7 @@ func (s *companyService) Update(id string, req dto.UpdateCompanyRequest) (*dto.C
 		company.IsActive = *req.IsActive
 	}
 
-	if err := s.companyRepo.Update(company); err != nil {
+	if err := s.companyRepo.Update(ctx, company); err != nil {
 		return nil, errors.New("failed to update company")
 	}
 
@@ -122,17 +123,17 @@ func (s *companyService) Update(id string, req dto.UpdateCompanyRequest) (*dto.C
 	return &response, nil
 }
 
-func (s *companyService) Delete(id string) error {
-	_, err := s.companyRepo.FindByID(id)
+func (s *companyService) Delete(ctx context.Context, id string) error {
+	_, err := s.companyRepo.FindByID(ctx, id)
 	if err != nil {
 		return errors.New("company not found")
 	}
-	return s.companyRepo.Delete(id)
+	return s.companyRepo.Delete(ctx, id)
 }
 
-func (s *companyService) DeleteMultiple(ids []string) error {
+func (s *companyService) DeleteMultiple(ctx context.Context, ids []string) error {
 	if len(ids) == 0 {
 		return errors.New("no IDs provided")
 	}
-	return s.companyRepo.DeleteMultiple(ids)
+	return s.companyRepo.DeleteMultiple(ctx, ids)
 }
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"hris-backend/internal/dto"
+	"hris-backend/internal/model"
+	"hris-backend/internal/permissions"
+	"hris-backend/internal/repository"
+)
+
+type CustomRoleService interface {
+	GetAll(ctx context.Context) ([]dto.CustomRoleResponse, error)
+	GetByCompanyID(ctx context.Context, companyID string) ([]dto.CustomRoleResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.CustomRoleResponse, error)
+	Create(ctx context.Context, actor Actor, req dto.CreateCustomRoleRequest) (*dto.CustomRoleResponse, error)
+	Update(ctx context.Context, actor Actor, id string, req dto.UpdateCustomRoleRequest) (*dto.CustomRoleResponse, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type customRoleService struct {
+	customRoleRepo repository.CustomRoleRepository
+	permRepo       repository.PermissionRepository
+	companyRepo    repository.CompanyRepository
+}
+
+func NewCustomRoleService(customRoleRepo repository.CustomRoleRepository, permRepo repository.PermissionRepository, companyRepo repository.CompanyRepository) CustomRoleService {
+	return &customRoleService{
+		customRoleRepo: customRoleRepo,
+		permRepo:       permRepo,
+		companyRepo:    companyRepo,
+	}
+}
+
+func (s *customRoleService) GetAll(ctx context.Context) ([]dto.CustomRoleResponse, error) {
+	roles, err := s.customRoleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToCustomRoleResponses(roles), nil
+}
+
+func (s *customRoleService) GetByCompanyID(ctx context.Context, companyID string) ([]dto.CustomRoleResponse, error) {
+	roles, err := s.customRoleRepo.FindByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToCustomRoleResponses(roles), nil
+}
+
+func (s *customRoleService) GetByID(ctx context.Context, id string) (*dto.CustomRoleResponse, error) {
+	role, err := s.customRoleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("role not found")
+	}
+	response := dto.ToCustomRoleResponse(role)
+	return &response, nil
+}
+
+func (s *customRoleService) Create(ctx context.Context, actor Actor, req dto.CreateCustomRoleRequest) (*dto.CustomRoleResponse, error) {
+	if _, err := s.companyRepo.FindByID(ctx, req.CompanyID); err != nil {
+		return nil, errors.New("company not found")
+	}
+
+	if existing, _ := s.customRoleRepo.FindByCompanyAndName(ctx, req.CompanyID, req.Name); existing != nil {
+		return nil, errors.New("company already has a role with this name")
+	}
+
+	perms, err := s.loadPermissions(ctx, actor, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &model.CustomRole{
+		CompanyID:   req.CompanyID,
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: perms,
+	}
+
+	if err := s.customRoleRepo.Create(ctx, role); err != nil {
+		return nil, errors.New("failed to create role")
+	}
+
+	return s.GetByID(ctx, role.ID)
+}
+
+func (s *customRoleService) Update(ctx context.Context, actor Actor, id string, req dto.UpdateCustomRoleRequest) (*dto.CustomRoleResponse, error) {
+	role, err := s.customRoleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("role not found")
+	}
+
+	if req.Name != "" && req.Name != role.Name {
+		if existing, _ := s.customRoleRepo.FindByCompanyAndName(ctx, role.CompanyID, req.Name); existing != nil {
+			return nil, errors.New("company already has a role with this name")
+		}
+		role.Name = req.Name
+	}
+	if req.Description != nil {
+		role.Description = *req.Description
+	}
+
+	var perms []model.Permission
+	if req.Permissions != nil {
+		perms, err = s.loadPermissions(ctx, actor, req.Permissions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.customRoleRepo.UpdateWithPermissions(ctx, role, perms); err != nil {
+		return nil, errors.New("failed to update role")
+	}
+
+	return s.GetByID(ctx, role.ID)
+}
+
+func (s *customRoleService) Delete(ctx context.Context, id string) error {
+	if _, err := s.customRoleRepo.FindByID(ctx, id); err != nil {
+		return errors.New("role not found")
+	}
+
+	count, err := s.customRoleRepo.CountUsers(ctx, id)
+	if err != nil {
+		return errors.New("failed to check role users")
+	}
+	if count > 0 {
+		return errors.New("role is still assigned to users")
+	}
+
+	return s.customRoleRepo.Delete(ctx, id)
+}
+
+// loadPermissions validates permission names against the catalog and the
+// actor's own permissions, and loads their rows. The result is never nil, so
+// an empty list clears a role.
+func (s *customRoleService) loadPermissions(ctx context.Context, actor Actor, names []string) ([]model.Permission, error) {
+	for _, name := range names {
+		if !permissions.IsValid(name) {
+			return nil, errors.New("invalid permission: " + name)
+		}
+	}
+	if err := actor.checkPermissions(names); err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return []model.Permission{}, nil
+	}
+
+	perms, err := s.permRepo.FindByNames(ctx, names)
+	if err != nil {
+		return nil, errors.New("failed to load permissions")
+	}
+	return perms, nil
+}
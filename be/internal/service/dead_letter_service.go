@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"hris-backend/internal/dto"
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+	"hris-backend/pkg/kafka"
+)
+
+// maxReplayBatch bounds how many dead letters one replay request publishes
+const maxReplayBatch = 500
+
+type DeadLetterService interface {
+	GetAllPaginated(ctx context.Context, page, limit int, status, eventType, startDate, endDate string) (*dto.PaginatedDeadLetterResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.DeadLetterDetailResponse, error)
+	Replay(ctx context.Context, id, userID string) error
+	ReplayMany(ctx context.Context, req dto.ReplayDeadLettersRequest, userID string) (*dto.ReplayDeadLettersResponse, error)
+}
+
+type deadLetterService struct {
+	deadLetterRepo repository.DeadLetterRepository
+}
+
+func NewDeadLetterService(deadLetterRepo repository.DeadLetterRepository) DeadLetterService {
+	return &deadLetterService{deadLetterRepo: deadLetterRepo}
+}
+
+func (s *deadLetterService) GetAllPaginated(ctx context.Context, page, limit int, status, eventType, startDate, endDate string) (*dto.PaginatedDeadLetterResponse, error) {
+	events, total, err := s.deadLetterRepo.FindAllPaginated(ctx, page, limit, status, eventType, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	return &dto.PaginatedDeadLetterResponse{
+		Data:       dto.ToDeadLetterResponses(events),
+		Page:       page,
+		Limit:      limit,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *deadLetterService) GetByID(ctx context.Context, id string) (*dto.DeadLetterDetailResponse, error) {
+	event, err := s.deadLetterRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("dead letter not found")
+	}
+	resp := dto.ToDeadLetterDetailResponse(event)
+	return &resp, nil
+}
+
+// Replay publishes a pending dead letter again to its original topic
+// through the outbox
+func (s *deadLetterService) Replay(ctx context.Context, id, userID string) error {
+	event, err := s.deadLetterRepo.FindByID(ctx, id)
+	if err != nil {
+		return errors.New("dead letter not found")
+	}
+	if event.Status != model.DeadLetterPending {
+		return errors.New("dead letter was already replayed")
+	}
+	_, err = s.replay(ctx, []model.DeadLetterEvent{*event}, userID)
+	return err
+}
+
+// ReplayMany publishes the pending dead letters selected by ID and/or event
+// type again, oldest first and at most maxReplayBatch of them
+func (s *deadLetterService) ReplayMany(ctx context.Context, req dto.ReplayDeadLettersRequest, userID string) (*dto.ReplayDeadLettersResponse, error) {
+	if len(req.IDs) == 0 && req.EventType == "" {
+		return nil, errors.New("ids or event_type is required")
+	}
+	if len(req.IDs) > maxReplayBatch {
+		return nil, errors.New("too many dead letters in one replay")
+	}
+
+	events, err := s.deadLetterRepo.FindPending(ctx, req.IDs, req.EventType, maxReplayBatch)
+	if err != nil {
+		return nil, errors.New("failed to fetch dead letters")
+	}
+	if len(events) == 0 {
+		return &dto.ReplayDeadLettersResponse{Replayed: 0}, nil
+	}
+
+	replayed, err := s.replay(ctx, events, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ReplayDeadLettersResponse{Replayed: replayed}, nil
+}
+
+// replay marks events replayed and queues their messages in one transaction
+func (s *deadLetterService) replay(ctx context.Context, events []model.DeadLetterEvent, userID string) (int, error) {
+	ids := make([]string, len(events))
+	outboxEvents := make([]model.OutboxEvent, len(events))
+	for i := range events {
+		// The outbox stores messages as JSON; a value that is not JSON
+		// could only fail again
+		if !json.Valid([]byte(events[i].Message)) {
+			return 0, errors.New("dead letter " + events[i].ID + " is not a JSON message and cannot be replayed")
+		}
+		outboxEvent, err := kafka.ReplayOutboxEvent(&events[i])
+		if err != nil {
+			return 0, errors.New("failed to replay dead letter")
+		}
+		ids[i] = events[i].ID
+		outboxEvents[i] = outboxEvent
+	}
+
+	ok, err := s.deadLetterRepo.MarkReplayed(ctx, ids, userID, outboxEvents)
+	if err != nil {
+		return 0, errors.New("failed to replay dead letter")
+	}
+	if !ok {
+		return 0, errors.New("dead letter was already replayed")
+	}
+	return len(events), nil
+}
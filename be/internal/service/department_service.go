@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"hris-backend/internal/dto"
@@ -9,36 +10,38 @@ import (
 )
 
 type DepartmentService interface {
-	GetAll() ([]dto.DepartmentResponse, error)
-	GetByID(id string) (*dto.DepartmentResponse, error)
-	GetByCompanyID(companyID string) ([]dto.DepartmentResponse, error)
-	Create(req dto.CreateDepartmentRequest) (*dto.DepartmentResponse, error)
-	Update(id string, req dto.UpdateDepartmentRequest) (*dto.DepartmentResponse, error)
-	Delete(id string) error
+	GetAll(ctx context.Context) ([]dto.DepartmentResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.DepartmentResponse, error)
+	GetByCompanyID(ctx context.Context, companyID string) ([]dto.DepartmentResponse, error)
+	Create(ctx context.Context, req dto.CreateDepartmentRequest) (*dto.DepartmentResponse, error)
+	Update(ctx context.Context, id string, req dto.UpdateDepartmentRequest) (*dto.DepartmentResponse, error)
+	Delete(ctx context.Context, id string) error
 }
 
 type departmentService struct {
 	deptRepo    repository.DepartmentRepository
 	companyRepo repository.CompanyRepository
+	empRepo     repository.EmployeeRepository
 }
 
-func NewDepartmentService(deptRepo repository.DepartmentRepository, companyRepo repository.CompanyRepository) DepartmentService {
+func NewDepartmentService(deptRepo repository.DepartmentRepository, companyRepo repository.CompanyRepository, empRepo repository.EmployeeRepository) DepartmentService {
 	return &departmentService{
 		deptRepo:    deptRepo,
 		companyRepo: companyRepo,
+		empRepo:     empRepo,
 	}
 }
 
-func (s *departmentService) GetAll() ([]dto.DepartmentResponse, error) {
-	depts, err := s.deptRepo.FindAll()
+func (s *departmentService) GetAll(ctx context.Context) ([]dto.DepartmentResponse, error) {
+	depts, err := s.deptRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToDepartmentResponses(depts), nil
 }
 
-func (s *departmentService) GetByID(id string) (*dto.DepartmentResponse, error) {
-	dept, err := s.deptRepo.FindByID(id)
+func (s *departmentService) GetByID(ctx context.Context, id string) (*dto.DepartmentResponse, error) {
+	dept, err := s.deptRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("department not found")
 	}
@@ -46,17 +49,17 @@ func (s *departmentService) GetByID(id string) (*dto.DepartmentResponse, error)
 	return &response, nil
 }
 
-func (s *departmentService) GetByCompanyID(companyID string) ([]dto.DepartmentResponse, error) {
-	depts, err := s.deptRepo.FindByCompanyID(companyID)
+func (s *departmentService) GetByCompanyID(ctx context.Context, companyID string) ([]dto.DepartmentResponse, error) {
+	depts, err := s.deptRepo.FindByCompanyID(ctx, companyID)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToDepartmentResponses(depts), nil
 }
 
-func (s *departmentService) Create(req dto.CreateDepartmentRequest) (*dto.DepartmentResponse, error) {
+func (s *departmentService) Create(ctx context.Context, req dto.CreateDepartmentRequest) (*dto.DepartmentResponse, error) {
 	// Validate company exists
-	_, err := s.companyRepo.FindByID(req.CompanyID)
+	_, err := s.companyRepo.FindByID(ctx, req.CompanyID)
 	if err != nil {
 		return nil, errors.New("company not found")
 	}
@@ -68,12 +71,20 @@ func (s *departmentService) Create(req dto.CreateDepartmentRequest) (*dto.Depart
 		IsActive:    true,
 	}
 
-	if err := s.deptRepo.Create(dept); err != nil {
+	if req.HeadID != "" {
+		if err := s.validateHead(ctx, req.HeadID, req.CompanyID); err != nil {
+			return nil, err
+		}
+		headID := req.HeadID
+		dept.HeadID = &headID
+	}
+
+	if err := s.deptRepo.Create(ctx, dept); err != nil {
 		return nil, errors.New("failed to create department")
 	}
 
 	// Reload with company preloaded
-	created, err := s.deptRepo.FindByID(dept.ID)
+	created, err := s.deptRepo.FindByID(ctx, dept.ID)
 	if err != nil {
 		return nil, errors.New("failed to load department")
 	}
@@ -82,8 +93,8 @@ func (s *departmentService) Create(req dto.CreateDepartmentRequest) (*dto.Depart
 	return &response, nil
 }
 
-func (s *departmentService) Update(id string, req dto.UpdateDepartmentRequest) (*dto.DepartmentResponse, error) {
-	dept, err := s.deptRepo.FindByID(id)
+func (s *departmentService) Update(ctx context.Context, id string, req dto.UpdateDepartmentRequest) (*dto.DepartmentResponse, error) {
+	dept, err := s.deptRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("department not found")
 	}
@@ -94,11 +105,18 @@ func (s *departmentService) Update(id string, req dto.UpdateDepartmentRequest) (
 	if req.Description != "" {
 		dept.Description = req.Description
 	}
+	if req.HeadID != "" {
+		if err := s.validateHead(ctx, req.HeadID, dept.CompanyID); err != nil {
+			return nil, err
+		}
+		headID := req.HeadID
+		dept.HeadID = &headID
+	}
 	if req.IsActive != nil {
 		dept.IsActive = *req.IsActive
 	}
 
-	if err := s.deptRepo.Update(dept); err != nil {
+	if err := s.deptRepo.Update(ctx, dept); err != nil {
 		return nil, errors.New("failed to update department")
 	}
 
@@ -106,10 +124,23 @@ func (s *departmentService) Update(id string, req dto.UpdateDepartmentRequest) (
 	return &response, nil
 }
 
-func (s *departmentService) Delete(id string) error {
-	_, err := s.deptRepo.FindByID(id)
+func (s *departmentService) Delete(ctx context.Context, id string) error {
+	_, err := s.deptRepo.FindByID(ctx, id)
 	if err != nil {
 		return errors.New("department not found")
 	}
-	return s.deptRepo.Delete(id)
+	return s.deptRepo.Delete(ctx, id)
+}
+
+// validateHead checks that the department head is an employee of the same
+// company
+func (s *departmentService) validateHead(ctx context.Context, headID, companyID string) error {
+	head, err := s.empRepo.FindByID(ctx, headID)
+	if err != nil {
+		return errors.New("department head not found")
+	}
+	if head.CompanyID != companyID {
+		return errors.New("department head must belong to the same company")
+	}
+	return nil
 }
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+	"hris-backend/pkg/kafka"
+)
+
+const digestScanInterval = 5 * time.Minute
+
+// DigestScheduler sends the daily digests: once a day, from sendHour on,
+// each user with notifications held back for their digest gets one
+// notification.digest event summarizing the ones held back before then.
+// Notifications arriving later wait for the next day.
+type DigestScheduler struct {
+	digestRepo repository.NotificationDigestRepository
+	sendHour   int
+}
+
+func NewDigestScheduler(digestRepo repository.NotificationDigestRepository, sendHour int) *DigestScheduler {
+	return &DigestScheduler{
+		digestRepo: digestRepo,
+		sendHour:   sendHour,
+	}
+}
+
+// Start begins sending digests in a background loop.
+func (s *DigestScheduler) Start() {
+	go func() {
+		for {
+			// Digests cover every company; they are not tenant scoped
+			if err := s.sendDue(context.Background(), time.Now()); err != nil {
+				log.Printf("[digest] send digests: %v", err)
+			}
+			time.Sleep(digestScanInterval)
+		}
+	}()
+}
+
+// sendDue publishes the digests of today's send time once it has passed
+func (s *DigestScheduler) sendDue(ctx context.Context, now time.Time) error {
+	sendAt := time.Date(now.Year(), now.Month(), now.Day(), s.sendHour, 0, 0, 0, now.Location())
+	if now.Before(sendAt) {
+		return nil
+	}
+
+	userIDs, err := s.digestRepo.FindPendingUserIDs(ctx, sendAt)
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		if err := s.send(ctx, userID, sendAt); err != nil {
+			log.Printf("[digest] send digest of user %s: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// send publishes the digest of one user. Marking the items digested and
+// writing the event happen in one transaction, so each item goes out once
+// even with several instances sending.
+func (s *DigestScheduler) send(ctx context.Context, userID string, sendAt time.Time) error {
+	items, err := s.digestRepo.FindPending(ctx, userID, sendAt)
+	if err != nil || len(items) == 0 {
+		return err
+	}
+
+	payload := kafka.NotificationDigestPayload{
+		UserID: userID,
+		Date:   sendAt.Format("2006-01-02"),
+		Total:  len(items),
+	}
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+		if i < kafka.MaxDigestItems {
+			digestItem := kafka.DigestItem{
+				EventType:  item.EventType,
+				Title:      item.Title,
+				Message:    item.Message,
+				RefType:    item.RefType,
+				OccurredAt: item.CreatedAt.Format(time.RFC3339),
+			}
+			if item.RefID != nil {
+				digestItem.RefID = *item.RefID
+			}
+			payload.Items = append(payload.Items, digestItem)
+		}
+	}
+
+	event, err := kafka.NewOutboxEvent(ctx, kafka.EventNotificationDigest, userID, "", payload)
+	if err != nil {
+		return err
+	}
+	_, err = s.digestRepo.MarkDigested(ctx, ids, []model.OutboxEvent{event})
+	return err
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"hris-backend/internal/dto"
+	"hris-backend/internal/repository"
+)
+
+type EmailDeliveryService interface {
+	GetAllPaginated(ctx context.Context, page, limit int, status, eventType, userID string) (*dto.PaginatedEmailDeliveryResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.EmailDeliveryDetailResponse, error)
+}
+
+type emailDeliveryService struct {
+	deliveryRepo repository.EmailDeliveryRepository
+}
+
+func NewEmailDeliveryService(deliveryRepo repository.EmailDeliveryRepository) EmailDeliveryService {
+	return &emailDeliveryService{deliveryRepo: deliveryRepo}
+}
+
+func (s *emailDeliveryService) GetAllPaginated(ctx context.Context, page, limit int, status, eventType, userID string) (*dto.PaginatedEmailDeliveryResponse, error) {
+	deliveries, total, err := s.deliveryRepo.FindAllPaginated(ctx, page, limit, status, eventType, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	return &dto.PaginatedEmailDeliveryResponse{
+		Data:       dto.ToEmailDeliveryResponses(deliveries),
+		Page:       page,
+		Limit:      limit,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *emailDeliveryService) GetByID(ctx context.Context, id string) (*dto.EmailDeliveryDetailResponse, error) {
+	delivery, err := s.deliveryRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("email delivery not found")
+	}
+	resp := dto.ToEmailDeliveryDetailResponse(delivery)
+	return &resp, nil
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -11,14 +12,14 @@ import (
 )
 
 type EmployeeSalaryService interface {
-	GetAll() ([]dto.EmployeeSalaryResponse, error)
-	GetByID(id string) (*dto.EmployeeSalaryResponse, error)
-	GetByEmployeeID(employeeID string) ([]dto.EmployeeSalaryResponse, error)
-	GetLatestByEmployeeID(employeeID string) (*dto.EmployeeSalaryResponse, error)
-	Create(req dto.CreateEmployeeSalaryRequest) (*dto.EmployeeSalaryResponse, error)
-	SeedFromPosition(employeeID string) (*dto.EmployeeSalaryResponse, error)
-	Update(id string, req dto.UpdateEmployeeSalaryRequest) (*dto.EmployeeSalaryResponse, error)
-	Delete(id string) error
+	GetAll(ctx context.Context) ([]dto.EmployeeSalaryResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.EmployeeSalaryResponse, error)
+	GetByEmployeeID(ctx context.Context, employeeID string) ([]dto.EmployeeSalaryResponse, error)
+	GetLatestByEmployeeID(ctx context.Context, employeeID string) (*dto.EmployeeSalaryResponse, error)
+	Create(ctx context.Context, req dto.CreateEmployeeSalaryRequest) (*dto.EmployeeSalaryResponse, error)
+	SeedFromPosition(ctx context.Context, employeeID string) (*dto.EmployeeSalaryResponse, error)
+	Update(ctx context.Context, id string, req dto.UpdateEmployeeSalaryRequest) (*dto.EmployeeSalaryResponse, error)
+	Delete(ctx context.Context, id string) error
 }
 
 type employeeSalaryService struct {
@@ -33,16 +34,16 @@ func NewEmployeeSalaryService(salaryRepo repository.EmployeeSalaryRepository, em
 	}
 }
 
-func (s *employeeSalaryService) GetAll() ([]dto.EmployeeSalaryResponse, error) {
-	salaries, err := s.salaryRepo.FindAll()
+func (s *employeeSalaryService) GetAll(ctx context.Context) ([]dto.EmployeeSalaryResponse, error) {
+	salaries, err := s.salaryRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToEmployeeSalaryResponses(salaries), nil
 }
 
-func (s *employeeSalaryService) GetByID(id string) (*dto.EmployeeSalaryResponse, error) {
-	salary, err := s.salaryRepo.FindByID(id)
+func (s *employeeSalaryService) GetByID(ctx context.Context, id string) (*dto.EmployeeSalaryResponse, error) {
+	salary, err := s.salaryRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("employee salary not found")
 	}
@@ -50,16 +51,16 @@ func (s *employeeSalaryService) GetByID(id string) (*dto.EmployeeSalaryResponse,
 	return &response, nil
 }
 
-func (s *employeeSalaryService) GetByEmployeeID(employeeID string) ([]dto.EmployeeSalaryResponse, error) {
-	salaries, err := s.salaryRepo.FindByEmployeeID(employeeID)
+func (s *employeeSalaryService) GetByEmployeeID(ctx context.Context, employeeID string) ([]dto.EmployeeSalaryResponse, error) {
+	salaries, err := s.salaryRepo.FindByEmployeeID(ctx, employeeID)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToEmployeeSalaryResponses(salaries), nil
 }
 
-func (s *employeeSalaryService) GetLatestByEmployeeID(employeeID string) (*dto.EmployeeSalaryResponse, error) {
-	salary, err := s.salaryRepo.FindLatestByEmployeeID(employeeID)
+func (s *employeeSalaryService) GetLatestByEmployeeID(ctx context.Context, employeeID string) (*dto.EmployeeSalaryResponse, error) {
+	salary, err := s.salaryRepo.FindLatestByEmployeeID(ctx, employeeID)
 	if err != nil {
 		return nil, errors.New("employee salary not found")
 	}
@@ -67,9 +68,9 @@ func (s *employeeSalaryService) GetLatestByEmployeeID(employeeID string) (*dto.E
 	return &response, nil
 }
 
-func (s *employeeSalaryService) Create(req dto.CreateEmployeeSalaryRequest) (*dto.EmployeeSalaryResponse, error) {
+func (s *employeeSalaryService) Create(ctx context.Context, req dto.CreateEmployeeSalaryRequest) (*dto.EmployeeSalaryResponse, error) {
 	// Validate employee exists
-	_, err := s.empRepo.FindByID(req.EmployeeID)
+	_, err := s.empRepo.FindByID(ctx, req.EmployeeID)
 	if err != nil {
 		return nil, errors.New("employee not found")
 	}
@@ -101,7 +102,7 @@ func (s *employeeSalaryService) Create(req dto.CreateEmployeeSalaryRequest) (*dt
 		EffectiveDate:      effectiveDate,
 	}
 
-	if err := s.salaryRepo.Create(salary); err != nil {
+	if err := s.salaryRepo.Create(ctx, salary); err != nil {
 		return nil, errors.New("failed to create employee salary")
 	}
 
@@ -112,8 +113,8 @@ func (s *employeeSalaryService) Create(req dto.CreateEmployeeSalaryRequest) (*dt
 // SeedFromPosition creates an employee_salaries row using the employee's position base_salary,
 // zero allowances, and auto-calculated Indonesian BPJS amounts. Effective date is today.
 // Returns an error if the employee already has a salary record or the position has no base_salary.
-func (s *employeeSalaryService) SeedFromPosition(employeeID string) (*dto.EmployeeSalaryResponse, error) {
-	emp, err := s.empRepo.FindByID(employeeID)
+func (s *employeeSalaryService) SeedFromPosition(ctx context.Context, employeeID string) (*dto.EmployeeSalaryResponse, error) {
+	emp, err := s.empRepo.FindByID(ctx, employeeID)
 	if err != nil {
 		return nil, errors.New("employee not found")
 	}
@@ -123,7 +124,7 @@ func (s *employeeSalaryService) SeedFromPosition(employeeID string) (*dto.Employ
 	}
 
 	// Block if salary already exists so seed is not misused
-	existing, _ := s.salaryRepo.FindLatestByEmployeeID(employeeID)
+	existing, _ := s.salaryRepo.FindLatestByEmployeeID(ctx, employeeID)
 	if existing != nil {
 		return nil, errors.New("employee already has a salary record")
 	}
@@ -145,7 +146,7 @@ func (s *employeeSalaryService) SeedFromPosition(employeeID string) (*dto.Employ
 		EffectiveDate:     time.Now(),
 	}
 
-	if err := s.salaryRepo.Create(salary); err != nil {
+	if err := s.salaryRepo.Create(ctx, salary); err != nil {
 		return nil, errors.New("failed to create employee salary")
 	}
 
@@ -153,8 +154,8 @@ func (s *employeeSalaryService) SeedFromPosition(employeeID string) (*dto.Employ
 	return &response, nil
 }
 
-func (s *employeeSalaryService) Update(id string, req dto.UpdateEmployeeSalaryRequest) (*dto.EmployeeSalaryResponse, error) {
-	salary, err := s.salaryRepo.FindByID(id)
+func (s *employeeSalaryService) Update(ctx context.Context, id string, req dto.UpdateEmployeeSalaryRequest) (*dto.EmployeeSalaryResponse, error) {
+	salary, err := s.salaryRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("employee salary not found")
 	}
@@ -198,7 +199,7 @@ func (s *employeeSalaryService) Update(id string, req dto.UpdateEmployeeSalaryRe
 		salary.BPJSTKJPCompany = jpCom
 	}
 
-	if err := s.salaryRepo.Update(salary); err != nil {
+	if err := s.salaryRepo.Update(ctx, salary); err != nil {
 		return nil, errors.New("failed to update employee salary")
 	}
 
@@ -206,10 +207,10 @@ func (s *employeeSalaryService) Update(id string, req dto.UpdateEmployeeSalaryRe
 	return &response, nil
 }
 
-func (s *employeeSalaryService) Delete(id string) error {
-	_, err := s.salaryRepo.FindByID(id)
+func (s *employeeSalaryService) Delete(ctx context.Context, id string) error {
+	_, err := s.salaryRepo.FindByID(ctx, id)
 	if err != nil {
 		return errors.New("employee salary not found")
 	}
-	return s.salaryRepo.Delete(id)
+	return s.salaryRepo.Delete(ctx, id)
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -10,13 +11,13 @@ import (
 )
 
 type EmployeeService interface {
-	GetAll() ([]dto.EmployeeResponse, error)
-	GetByID(id string) (*dto.EmployeeResponse, error)
-	GetByUserID(userID string) (*dto.EmployeeResponse, error)
-	GetByCompanyID(companyID string) ([]dto.EmployeeResponse, error)
-	Create(req dto.CreateEmployeeRequest) (*dto.EmployeeResponse, error)
-	Update(id string, req dto.UpdateEmployeeRequest) (*dto.EmployeeResponse, error)
-	Delete(id string) error
+	GetAll(ctx context.Context) ([]dto.EmployeeResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.EmployeeResponse, error)
+	GetByUserID(ctx context.Context, userID string) (*dto.EmployeeResponse, error)
+	GetByCompanyID(ctx context.Context, companyID string) ([]dto.EmployeeResponse, error)
+	Create(ctx context.Context, req dto.CreateEmployeeRequest) (*dto.EmployeeResponse, error)
+	Update(ctx context.Context, id string, req dto.UpdateEmployeeRequest) (*dto.EmployeeResponse, error)
+	Delete(ctx context.Context, id string) error
 }
 
 type employeeService struct {
@@ -52,16 +53,16 @@ func NewEmployeeService(
 	}
 }
 
-func (s *employeeService) GetAll() ([]dto.EmployeeResponse, error) {
-	employees, err := s.empRepo.FindAll()
+func (s *employeeService) GetAll(ctx context.Context) ([]dto.EmployeeResponse, error) {
+	employees, err := s.empRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToEmployeeResponses(employees), nil
 }
 
-func (s *employeeService) GetByID(id string) (*dto.EmployeeResponse, error) {
-	emp, err := s.empRepo.FindByID(id)
+func (s *employeeService) GetByID(ctx context.Context, id string) (*dto.EmployeeResponse, error) {
+	emp, err := s.empRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("employee not found")
 	}
@@ -69,8 +70,8 @@ func (s *employeeService) GetByID(id string) (*dto.EmployeeResponse, error) {
 	return &response, nil
 }
 
-func (s *employeeService) GetByUserID(userID string) (*dto.EmployeeResponse, error) {
-	emp, err := s.empRepo.FindByUserID(userID)
+func (s *employeeService) GetByUserID(ctx context.Context, userID string) (*dto.EmployeeResponse, error) {
+	emp, err := s.empRepo.FindByUserID(ctx, userID)
 	if err != nil {
 		return nil, errors.New("employee not found")
 	}
@@ -78,60 +79,60 @@ func (s *employeeService) GetByUserID(userID string) (*dto.EmployeeResponse, err
 	return &response, nil
 }
 
-func (s *employeeService) GetByCompanyID(companyID string) ([]dto.EmployeeResponse, error) {
-	employees, err := s.empRepo.FindByCompanyID(companyID)
+func (s *employeeService) GetByCompanyID(ctx context.Context, companyID string) ([]dto.EmployeeResponse, error) {
+	employees, err := s.empRepo.FindByCompanyID(ctx, companyID)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToEmployeeResponses(employees), nil
 }
 
-func (s *employeeService) Create(req dto.CreateEmployeeRequest) (*dto.EmployeeResponse, error) {
+func (s *employeeService) Create(ctx context.Context, req dto.CreateEmployeeRequest) (*dto.EmployeeResponse, error) {
 	// Validate user exists
-	_, err := s.userRepo.FindByID(req.UserID)
+	_, err := s.userRepo.FindByID(ctx, req.UserID)
 	if err != nil {
 		return nil, errors.New("user not found")
 	}
 
 	// Check if user already has an employee record
-	existing, _ := s.empRepo.FindByUserID(req.UserID)
+	existing, _ := s.empRepo.FindByUserID(ctx, req.UserID)
 	if existing != nil {
 		return nil, errors.New("user already has an employee record")
 	}
 
 	// Check employee number uniqueness
-	existingEmp, _ := s.empRepo.FindByEmployeeNumber(req.EmployeeNumber)
+	existingEmp, _ := s.empRepo.FindByEmployeeNumber(ctx, req.EmployeeNumber)
 	if existingEmp != nil {
 		return nil, errors.New("employee number already exists")
 	}
 
 	// Validate company exists
-	_, err = s.companyRepo.FindByID(req.CompanyID)
+	_, err = s.companyRepo.FindByID(ctx, req.CompanyID)
 	if err != nil {
 		return nil, errors.New("company not found")
 	}
 
 	// Validate department exists
-	_, err = s.deptRepo.FindByID(req.DepartmentID)
+	_, err = s.deptRepo.FindByID(ctx, req.DepartmentID)
 	if err != nil {
 		return nil, errors.New("department not found")
 	}
 
 	// Validate position exists
-	_, err = s.posRepo.FindByID(req.PositionID)
+	_, err = s.posRepo.FindByID(ctx, req.PositionID)
 	if err != nil {
 		return nil, errors.New("position not found")
 	}
 
 	// Validate shift exists
-	_, err = s.shiftRepo.FindByID(req.ShiftID)
+	_, err = s.shiftRepo.FindByID(ctx, req.ShiftID)
 	if err != nil {
 		return nil, errors.New("shift not found")
 	}
 
 	// Validate job level if provided
 	if req.JobLevelID != "" {
-		_, err = s.jobLevelRepo.FindByID(req.JobLevelID)
+		_, err = s.jobLevelRepo.FindByID(ctx, req.JobLevelID)
 		if err != nil {
 			return nil, errors.New("job level not found")
 		}
@@ -139,12 +140,19 @@ func (s *employeeService) Create(req dto.CreateEmployeeRequest) (*dto.EmployeeRe
 
 	// Validate grade if provided
 	if req.GradeID != "" {
-		_, err = s.gradeRepo.FindByID(req.GradeID)
+		_, err = s.gradeRepo.FindByID(ctx, req.GradeID)
 		if err != nil {
 			return nil, errors.New("grade not found")
 		}
 	}
 
+	// Validate supervisor if provided
+	if req.SupervisorID != "" {
+		if err := s.validateSupervisor(ctx, req.SupervisorID, req.CompanyID, ""); err != nil {
+			return nil, err
+		}
+	}
+
 	// Parse join date
 	joinDate, err := time.Parse("2006-01-02", req.JoinDate)
 	if err != nil {
@@ -178,6 +186,13 @@ func (s *employeeService) Create(req dto.CreateEmployeeRequest) (*dto.EmployeeRe
 		emp.EmployeeStatus = model.StatusKontrak
 	}
 
+	if req.Dependents != nil {
+		if *req.Dependents < 0 {
+			return nil, errors.New("dependents cannot be negative")
+		}
+		emp.Dependents = *req.Dependents
+	}
+
 	if req.JobLevelID != "" {
 		jlID := req.JobLevelID
 		emp.JobLevelID = &jlID
@@ -186,6 +201,10 @@ func (s *employeeService) Create(req dto.CreateEmployeeRequest) (*dto.EmployeeRe
 		gID := req.GradeID
 		emp.GradeID = &gID
 	}
+	if req.SupervisorID != "" {
+		supID := req.SupervisorID
+		emp.SupervisorID = &supID
+	}
 
 	// Parse optional birth date
 	if req.BirthDate != "" {
@@ -221,12 +240,12 @@ func (s *employeeService) Create(req dto.CreateEmployeeRequest) (*dto.EmployeeRe
 		}
 	}
 
-	if err := s.empRepo.Create(emp); err != nil {
+	if err := s.empRepo.Create(ctx, emp); err != nil {
 		return nil, errors.New("failed to create employee")
 	}
 
 	// Reload with preloaded relations
-	created, err := s.empRepo.FindByID(emp.ID)
+	created, err := s.empRepo.FindByID(ctx, emp.ID)
 	if err != nil {
 		return nil, errors.New("failed to load employee")
 	}
@@ -235,35 +254,35 @@ func (s *employeeService) Create(req dto.CreateEmployeeRequest) (*dto.EmployeeRe
 	return &response, nil
 }
 
-func (s *employeeService) Update(id string, req dto.UpdateEmployeeRequest) (*dto.EmployeeResponse, error) {
-	emp, err := s.empRepo.FindByID(id)
+func (s *employeeService) Update(ctx context.Context, id string, req dto.UpdateEmployeeRequest) (*dto.EmployeeResponse, error) {
+	emp, err := s.empRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("employee not found")
 	}
 
 	if req.DepartmentID != "" {
-		_, err := s.deptRepo.FindByID(req.DepartmentID)
+		_, err := s.deptRepo.FindByID(ctx, req.DepartmentID)
 		if err != nil {
 			return nil, errors.New("department not found")
 		}
 		emp.DepartmentID = req.DepartmentID
 	}
 	if req.PositionID != "" {
-		_, err := s.posRepo.FindByID(req.PositionID)
+		_, err := s.posRepo.FindByID(ctx, req.PositionID)
 		if err != nil {
 			return nil, errors.New("position not found")
 		}
 		emp.PositionID = req.PositionID
 	}
 	if req.ShiftID != "" {
-		_, err := s.shiftRepo.FindByID(req.ShiftID)
+		_, err := s.shiftRepo.FindByID(ctx, req.ShiftID)
 		if err != nil {
 			return nil, errors.New("shift not found")
 		}
 		emp.ShiftID = req.ShiftID
 	}
 	if req.JobLevelID != "" {
-		_, err := s.jobLevelRepo.FindByID(req.JobLevelID)
+		_, err := s.jobLevelRepo.FindByID(ctx, req.JobLevelID)
 		if err != nil {
 			return nil, errors.New("job level not found")
 		}
@@ -271,13 +290,20 @@ func (s *employeeService) Update(id string, req dto.UpdateEmployeeRequest) (*dto
 		emp.JobLevelID = &jlID
 	}
 	if req.GradeID != "" {
-		_, err := s.gradeRepo.FindByID(req.GradeID)
+		_, err := s.gradeRepo.FindByID(ctx, req.GradeID)
 		if err != nil {
 			return nil, errors.New("grade not found")
 		}
 		gID := req.GradeID
 		emp.GradeID = &gID
 	}
+	if req.SupervisorID != "" {
+		if err := s.validateSupervisor(ctx, req.SupervisorID, emp.CompanyID, emp.ID); err != nil {
+			return nil, err
+		}
+		supID := req.SupervisorID
+		emp.SupervisorID = &supID
+	}
 	if req.NIK != "" {
 		emp.NIK = req.NIK
 	}
@@ -297,6 +323,12 @@ func (s *employeeService) Update(id string, req dto.UpdateEmployeeRequest) (*dto
 	if req.MaritalStatus != "" {
 		emp.MaritalStatus = req.MaritalStatus
 	}
+	if req.Dependents != nil {
+		if *req.Dependents < 0 {
+			return nil, errors.New("dependents cannot be negative")
+		}
+		emp.Dependents = *req.Dependents
+	}
 	if req.Religion != "" {
 		emp.Religion = req.Religion
 	}
@@ -352,12 +384,12 @@ func (s *employeeService) Update(id string, req dto.UpdateEmployeeRequest) (*dto
 		emp.NPWP = req.NPWP
 	}
 
-	if err := s.empRepo.Update(emp); err != nil {
+	if err := s.empRepo.Update(ctx, emp); err != nil {
 		return nil, errors.New("failed to update employee")
 	}
 
 	// Reload with preloaded relations
-	updated, err := s.empRepo.FindByID(emp.ID)
+	updated, err := s.empRepo.FindByID(ctx, emp.ID)
 	if err != nil {
 		return nil, errors.New("failed to load employee")
 	}
@@ -366,10 +398,26 @@ func (s *employeeService) Update(id string, req dto.UpdateEmployeeRequest) (*dto
 	return &response, nil
 }
 
-func (s *employeeService) Delete(id string) error {
-	_, err := s.empRepo.FindByID(id)
+func (s *employeeService) Delete(ctx context.Context, id string) error {
+	_, err := s.empRepo.FindByID(ctx, id)
 	if err != nil {
 		return errors.New("employee not found")
 	}
-	return s.empRepo.Delete(id)
+	return s.empRepo.Delete(ctx, id)
+}
+
+// validateSupervisor checks that the supervisor is another employee of the
+// same company
+func (s *employeeService) validateSupervisor(ctx context.Context, supervisorID, companyID, employeeID string) error {
+	if supervisorID == employeeID {
+		return errors.New("employee cannot supervise themselves")
+	}
+	supervisor, err := s.empRepo.FindByID(ctx, supervisorID)
+	if err != nil {
+		return errors.New("supervisor not found")
+	}
+	if supervisor.CompanyID != companyID {
+		return errors.New("supervisor must belong to the same company")
+	}
+	return nil
 }
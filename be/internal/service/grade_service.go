@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"hris-backend/internal/dto"
@@ -9,13 +10,13 @@ import (
 )
 
 type GradeService interface {
-	GetAll() ([]dto.GradeResponse, error)
-	GetByID(id string) (*dto.GradeResponse, error)
-	GetByCompanyID(companyID string) ([]dto.GradeResponse, error)
-	GetByJobLevelID(jobLevelID string) ([]dto.GradeResponse, error)
-	Create(req dto.CreateGradeRequest) (*dto.GradeResponse, error)
-	Update(id string, req dto.UpdateGradeRequest) (*dto.GradeResponse, error)
-	Delete(id string) error
+	GetAll(ctx context.Context) ([]dto.GradeResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.GradeResponse, error)
+	GetByCompanyID(ctx context.Context, companyID string) ([]dto.GradeResponse, error)
+	GetByJobLevelID(ctx context.Context, jobLevelID string) ([]dto.GradeResponse, error)
+	Create(ctx context.Context, req dto.CreateGradeRequest) (*dto.GradeResponse, error)
+	Update(ctx context.Context, id string, req dto.UpdateGradeRequest) (*dto.GradeResponse, error)
+	Delete(ctx context.Context, id string) error
 }
 
 type gradeService struct {
@@ -28,16 +29,16 @@ func NewGradeService(gradeRepo repository.GradeRepository, jobLevelRepo reposito
 	return &gradeService{gradeRepo: gradeRepo, jobLevelRepo: jobLevelRepo, companyRepo: companyRepo}
 }
 
-func (s *gradeService) GetAll() ([]dto.GradeResponse, error) {
-	grades, err := s.gradeRepo.FindAll()
+func (s *gradeService) GetAll(ctx context.Context) ([]dto.GradeResponse, error) {
+	grades, err := s.gradeRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToGradeResponses(grades), nil
 }
 
-func (s *gradeService) GetByID(id string) (*dto.GradeResponse, error) {
-	g, err := s.gradeRepo.FindByID(id)
+func (s *gradeService) GetByID(ctx context.Context, id string) (*dto.GradeResponse, error) {
+	g, err := s.gradeRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("grade not found")
 	}
@@ -45,27 +46,27 @@ func (s *gradeService) GetByID(id string) (*dto.GradeResponse, error) {
 	return &resp, nil
 }
 
-func (s *gradeService) GetByCompanyID(companyID string) ([]dto.GradeResponse, error) {
-	grades, err := s.gradeRepo.FindByCompanyID(companyID)
+func (s *gradeService) GetByCompanyID(ctx context.Context, companyID string) ([]dto.GradeResponse, error) {
+	grades, err := s.gradeRepo.FindByCompanyID(ctx, companyID)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToGradeResponses(grades), nil
 }
 
-func (s *gradeService) GetByJobLevelID(jobLevelID string) ([]dto.GradeResponse, error) {
-	grades, err := s.gradeRepo.FindByJobLevelID(jobLevelID)
+func (s *gradeService) GetByJobLevelID(ctx context.Context, jobLevelID string) ([]dto.GradeResponse, error) {
+	grades, err := s.gradeRepo.FindByJobLevelID(ctx, jobLevelID)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToGradeResponses(grades), nil
 }
 
-func (s *gradeService) Create(req dto.CreateGradeRequest) (*dto.GradeResponse, error) {
-	if _, err := s.companyRepo.FindByID(req.CompanyID); err != nil {
+func (s *gradeService) Create(ctx context.Context, req dto.CreateGradeRequest) (*dto.GradeResponse, error) {
+	if _, err := s.companyRepo.FindByID(ctx, req.CompanyID); err != nil {
 		return nil, errors.New("company not found")
 	}
-	if _, err := s.jobLevelRepo.FindByID(req.JobLevelID); err != nil {
+	if _, err := s.jobLevelRepo.FindByID(ctx, req.JobLevelID); err != nil {
 		return nil, errors.New("job level not found")
 	}
 	if req.MaxSalary > 0 && req.MinSalary > req.MaxSalary {
@@ -82,24 +83,24 @@ func (s *gradeService) Create(req dto.CreateGradeRequest) (*dto.GradeResponse, e
 		IsActive:    true,
 	}
 
-	if err := s.gradeRepo.Create(g); err != nil {
+	if err := s.gradeRepo.Create(ctx, g); err != nil {
 		return nil, errors.New("failed to create grade")
 	}
 
 	// Reload with JobLevel preloaded for the response
-	created, _ := s.gradeRepo.FindByID(g.ID)
+	created, _ := s.gradeRepo.FindByID(ctx, g.ID)
 	resp := dto.ToGradeResponse(created)
 	return &resp, nil
 }
 
-func (s *gradeService) Update(id string, req dto.UpdateGradeRequest) (*dto.GradeResponse, error) {
-	g, err := s.gradeRepo.FindByID(id)
+func (s *gradeService) Update(ctx context.Context, id string, req dto.UpdateGradeRequest) (*dto.GradeResponse, error) {
+	g, err := s.gradeRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("grade not found")
 	}
 
 	if req.JobLevelID != nil {
-		if _, err := s.jobLevelRepo.FindByID(*req.JobLevelID); err != nil {
+		if _, err := s.jobLevelRepo.FindByID(ctx, *req.JobLevelID); err != nil {
 			return nil, errors.New("job level not found")
 		}
 		g.JobLevelID = *req.JobLevelID
@@ -124,19 +125,19 @@ func (s *gradeService) Update(id string, req dto.UpdateGradeRequest) (*dto.Grade
 		return nil, errors.New("min salary cannot exceed max salary")
 	}
 
-	if err := s.gradeRepo.Update(g); err != nil {
+	if err := s.gradeRepo.Update(ctx, g); err != nil {
 		return nil, errors.New("failed to update grade")
 	}
 
 	// Reload for fresh JobLevel name
-	updated, _ := s.gradeRepo.FindByID(g.ID)
+	updated, _ := s.gradeRepo.FindByID(ctx, g.ID)
 	resp := dto.ToGradeResponse(updated)
 	return &resp, nil
 }
 
-func (s *gradeService) Delete(id string) error {
-	if _, err := s.gradeRepo.FindByID(id); err != nil {
+func (s *gradeService) Delete(ctx context.Context, id string) error {
+	if _, err := s.gradeRepo.FindByID(ctx, id); err != nil {
 		return errors.New("grade not found")
 	}
-	return s.gradeRepo.Delete(id)
+	return s.gradeRepo.Delete(ctx, id)
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -10,13 +11,13 @@ import (
 )
 
 type HolidayService interface {
-	GetAll() ([]dto.HolidayResponse, error)
-	GetByID(id string) (*dto.HolidayResponse, error)
-	GetByCompanyID(companyID string) ([]dto.HolidayResponse, error)
-	GetByCompanyIDAndYear(companyID string, year int) ([]dto.HolidayResponse, error)
-	Create(req dto.CreateHolidayRequest) (*dto.HolidayResponse, error)
-	Update(id string, req dto.UpdateHolidayRequest) (*dto.HolidayResponse, error)
-	Delete(id string) error
+	GetAll(ctx context.Context) ([]dto.HolidayResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.HolidayResponse, error)
+	GetByCompanyID(ctx context.Context, companyID string) ([]dto.HolidayResponse, error)
+	GetByCompanyIDAndYear(ctx context.Context, companyID string, year int) ([]dto.HolidayResponse, error)
+	Create(ctx context.Context, req dto.CreateHolidayRequest) (*dto.HolidayResponse, error)
+	Update(ctx context.Context, id string, req dto.UpdateHolidayRequest) (*dto.HolidayResponse, error)
+	Delete(ctx context.Context, id string) error
 }
 
 type holidayService struct {
@@ -31,16 +32,16 @@ func NewHolidayService(holidayRepo repository.HolidayRepository, companyRepo rep
 	}
 }
 
-func (s *holidayService) GetAll() ([]dto.HolidayResponse, error) {
-	holidays, err := s.holidayRepo.FindAll()
+func (s *holidayService) GetAll(ctx context.Context) ([]dto.HolidayResponse, error) {
+	holidays, err := s.holidayRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToHolidayResponses(holidays), nil
 }
 
-func (s *holidayService) GetByID(id string) (*dto.HolidayResponse, error) {
-	holiday, err := s.holidayRepo.FindByID(id)
+func (s *holidayService) GetByID(ctx context.Context, id string) (*dto.HolidayResponse, error) {
+	holiday, err := s.holidayRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("holiday not found")
 	}
@@ -48,24 +49,24 @@ func (s *holidayService) GetByID(id string) (*dto.HolidayResponse, error) {
 	return &response, nil
 }
 
-func (s *holidayService) GetByCompanyID(companyID string) ([]dto.HolidayResponse, error) {
-	holidays, err := s.holidayRepo.FindByCompanyID(companyID)
+func (s *holidayService) GetByCompanyID(ctx context.Context, companyID string) ([]dto.HolidayResponse, error) {
+	holidays, err := s.holidayRepo.FindByCompanyID(ctx, companyID)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToHolidayResponses(holidays), nil
 }
 
-func (s *holidayService) GetByCompanyIDAndYear(companyID string, year int) ([]dto.HolidayResponse, error) {
-	holidays, err := s.holidayRepo.FindByCompanyIDAndYear(companyID, year)
+func (s *holidayService) GetByCompanyIDAndYear(ctx context.Context, companyID string, year int) ([]dto.HolidayResponse, error) {
+	holidays, err := s.holidayRepo.FindByCompanyIDAndYear(ctx, companyID, year)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToHolidayResponses(holidays), nil
 }
 
-func (s *holidayService) Create(req dto.CreateHolidayRequest) (*dto.HolidayResponse, error) {
-	_, err := s.companyRepo.FindByID(req.CompanyID)
+func (s *holidayService) Create(ctx context.Context, req dto.CreateHolidayRequest) (*dto.HolidayResponse, error) {
+	_, err := s.companyRepo.FindByID(ctx, req.CompanyID)
 	if err != nil {
 		return nil, errors.New("company not found")
 	}
@@ -86,11 +87,11 @@ func (s *holidayService) Create(req dto.CreateHolidayRequest) (*dto.HolidayRespo
 		holiday.IsNational = *req.IsNational
 	}
 
-	if err := s.holidayRepo.Create(holiday); err != nil {
+	if err := s.holidayRepo.Create(ctx, holiday); err != nil {
 		return nil, errors.New("failed to create holiday")
 	}
 
-	created, err := s.holidayRepo.FindByID(holiday.ID)
+	created, err := s.holidayRepo.FindByID(ctx, holiday.ID)
 	if err != nil {
 		return nil, errors.New("failed to load holiday")
 	}
@@ -99,8 +100,8 @@ func (s *holidayService) Create(req dto.CreateHolidayRequest) (*dto.HolidayRespo
 	return &response, nil
 }
 
-func (s *holidayService) Update(id string, req dto.UpdateHolidayRequest) (*dto.HolidayResponse, error) {
-	holiday, err := s.holidayRepo.FindByID(id)
+func (s *holidayService) Update(ctx context.Context, id string, req dto.UpdateHolidayRequest) (*dto.HolidayResponse, error) {
+	holiday, err := s.holidayRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("holiday not found")
 	}
@@ -119,7 +120,7 @@ func (s *holidayService) Update(id string, req dto.UpdateHolidayRequest) (*dto.H
 		holiday.IsNational = *req.IsNational
 	}
 
-	if err := s.holidayRepo.Update(holiday); err != nil {
+	if err := s.holidayRepo.Update(ctx, holiday); err != nil {
 		return nil, errors.New("failed to update holiday")
 	}
 
@@ -127,10 +128,10 @@ func (s *holidayService) Update(id string, req dto.UpdateHolidayRequest) (*dto.H
 	return &response, nil
 }
 
-func (s *holidayService) Delete(id string) error {
-	_, err := s.holidayRepo.FindByID(id)
+func (s *holidayService) Delete(ctx context.Context, id string) error {
+	_, err := s.holidayRepo.FindByID(ctx, id)
 	if err != nil {
 		return errors.New("holiday not found")
 	}
-	return s.holidayRepo.Delete(id)
+	return s.holidayRepo.Delete(ctx, id)
 }
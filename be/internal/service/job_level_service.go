@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"hris-backend/internal/dto"
@@ -9,12 +10,12 @@ import (
 )
 
 type JobLevelService interface {
-	GetAll() ([]dto.JobLevelResponse, error)
-	GetByID(id string) (*dto.JobLevelResponse, error)
-	GetByCompanyID(companyID string) ([]dto.JobLevelResponse, error)
-	Create(req dto.CreateJobLevelRequest) (*dto.JobLevelResponse, error)
-	Update(id string, req dto.UpdateJobLevelRequest) (*dto.JobLevelResponse, error)
-	Delete(id string) error
+	GetAll(ctx context.Context) ([]dto.JobLevelResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.JobLevelResponse, error)
+	GetByCompanyID(ctx context.Context, companyID string) ([]dto.JobLevelResponse, error)
+	Create(ctx context.Context, req dto.CreateJobLevelRequest) (*dto.JobLevelResponse, error)
+	Update(ctx context.Context, id string, req dto.UpdateJobLevelRequest) (*dto.JobLevelResponse, error)
+	Delete(ctx context.Context, id string) error
 }
 
 type jobLevelService struct {
@@ -26,16 +27,16 @@ func NewJobLevelService(jobLevelRepo repository.JobLevelRepository, companyRepo
 	return &jobLevelService{jobLevelRepo: jobLevelRepo, companyRepo: companyRepo}
 }
 
-func (s *jobLevelService) GetAll() ([]dto.JobLevelResponse, error) {
-	levels, err := s.jobLevelRepo.FindAll()
+func (s *jobLevelService) GetAll(ctx context.Context) ([]dto.JobLevelResponse, error) {
+	levels, err := s.jobLevelRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToJobLevelResponses(levels), nil
 }
 
-func (s *jobLevelService) GetByID(id string) (*dto.JobLevelResponse, error) {
-	jl, err := s.jobLevelRepo.FindByID(id)
+func (s *jobLevelService) GetByID(ctx context.Context, id string) (*dto.JobLevelResponse, error) {
+	jl, err := s.jobLevelRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("job level not found")
 	}
@@ -43,16 +44,16 @@ func (s *jobLevelService) GetByID(id string) (*dto.JobLevelResponse, error) {
 	return &resp, nil
 }
 
-func (s *jobLevelService) GetByCompanyID(companyID string) ([]dto.JobLevelResponse, error) {
-	levels, err := s.jobLevelRepo.FindByCompanyID(companyID)
+func (s *jobLevelService) GetByCompanyID(ctx context.Context, companyID string) ([]dto.JobLevelResponse, error) {
+	levels, err := s.jobLevelRepo.FindByCompanyID(ctx, companyID)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToJobLevelResponses(levels), nil
 }
 
-func (s *jobLevelService) Create(req dto.CreateJobLevelRequest) (*dto.JobLevelResponse, error) {
-	if _, err := s.companyRepo.FindByID(req.CompanyID); err != nil {
+func (s *jobLevelService) Create(ctx context.Context, req dto.CreateJobLevelRequest) (*dto.JobLevelResponse, error) {
+	if _, err := s.companyRepo.FindByID(ctx, req.CompanyID); err != nil {
 		return nil, errors.New("company not found")
 	}
 
@@ -64,7 +65,7 @@ func (s *jobLevelService) Create(req dto.CreateJobLevelRequest) (*dto.JobLevelRe
 		IsActive:    true,
 	}
 
-	if err := s.jobLevelRepo.Create(jl); err != nil {
+	if err := s.jobLevelRepo.Create(ctx, jl); err != nil {
 		return nil, errors.New("failed to create job level")
 	}
 
@@ -72,8 +73,8 @@ func (s *jobLevelService) Create(req dto.CreateJobLevelRequest) (*dto.JobLevelRe
 	return &resp, nil
 }
 
-func (s *jobLevelService) Update(id string, req dto.UpdateJobLevelRequest) (*dto.JobLevelResponse, error) {
-	jl, err := s.jobLevelRepo.FindByID(id)
+func (s *jobLevelService) Update(ctx context.Context, id string, req dto.UpdateJobLevelRequest) (*dto.JobLevelResponse, error) {
+	jl, err := s.jobLevelRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("job level not found")
 	}
@@ -91,7 +92,7 @@ func (s *jobLevelService) Update(id string, req dto.UpdateJobLevelRequest) (*dto
 		jl.IsActive = *req.IsActive
 	}
 
-	if err := s.jobLevelRepo.Update(jl); err != nil {
+	if err := s.jobLevelRepo.Update(ctx, jl); err != nil {
 		return nil, errors.New("failed to update job level")
 	}
 
@@ -99,9 +100,9 @@ func (s *jobLevelService) Update(id string, req dto.UpdateJobLevelRequest) (*dto
 	return &resp, nil
 }
 
-func (s *jobLevelService) Delete(id string) error {
-	if _, err := s.jobLevelRepo.FindByID(id); err != nil {
+func (s *jobLevelService) Delete(ctx context.Context, id string) error {
+	if _, err := s.jobLevelRepo.FindByID(ctx, id); err != nil {
 		return errors.New("job level not found")
 	}
-	return s.jobLevelRepo.Delete(id)
+	return s.jobLevelRepo.Delete(ctx, id)
 }
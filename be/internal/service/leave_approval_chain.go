@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+)
+
+// defaultLeaveWorkflowSteps is used for companies without a leave workflow:
+// a single step any HR user can decide.
+var defaultLeaveWorkflowSteps = []model.LeaveWorkflowStep{
+	{StepOrder: 1, ApproverType: model.LeaveApproverRole, ApproverRole: model.RoleHR},
+}
+
+// leaveApprovalChain resolves approval chains from leave workflows and
+// decides who may act on a step. It is shared by the leave services.
+type leaveApprovalChain struct {
+	workflowRepo   repository.LeaveWorkflowRepository
+	delegationRepo repository.LeaveDelegationRepository
+	empRepo        repository.EmployeeRepository
+	deptRepo       repository.DepartmentRepository
+}
+
+// build resolves the workflow applicable to emp into approval steps. Steps
+// whose approver cannot be resolved, or would be the requester, are skipped;
+// if every step is skipped the chain falls back to the default HR step.
+func (c leaveApprovalChain) build(ctx context.Context, emp *model.Employee) []model.LeaveApproval {
+	steps := defaultLeaveWorkflowSteps
+	if workflow, err := c.workflowRepo.FindApplicable(ctx, emp.CompanyID, emp.DepartmentID); err == nil && workflow != nil && len(workflow.Steps) > 0 {
+		steps = workflow.Steps
+	}
+
+	approvals := c.resolve(ctx, emp, steps)
+	if currentApproval(approvals) == nil {
+		approvals = append(approvals, c.resolve(ctx, emp, defaultLeaveWorkflowSteps)...)
+	}
+	for i := range approvals {
+		approvals[i].StepOrder = i + 1
+	}
+	return approvals
+}
+
+func (c leaveApprovalChain) resolve(ctx context.Context, emp *model.Employee, steps []model.LeaveWorkflowStep) []model.LeaveApproval {
+	approvals := make([]model.LeaveApproval, 0, len(steps))
+	for _, step := range steps {
+		approval := model.LeaveApproval{
+			ApproverType: step.ApproverType,
+			ApproverRole: step.ApproverRole,
+			Status:       model.LeaveApprovalPending,
+		}
+
+		switch step.ApproverType {
+		case model.LeaveApproverSupervisor:
+			if emp.SupervisorID != nil {
+				approval.ApproverUserID = c.employeeUserID(ctx, *emp.SupervisorID)
+			}
+		case model.LeaveApproverDepartmentHead:
+			if dept, err := c.deptRepo.FindByID(ctx, emp.DepartmentID); err == nil && dept.HeadID != nil {
+				approval.ApproverUserID = c.employeeUserID(ctx, *dept.HeadID)
+			}
+		case model.LeaveApproverUser:
+			approval.ApproverUserID = step.ApproverUserID
+		}
+
+		if step.ApproverType != model.LeaveApproverRole {
+			switch {
+			case approval.ApproverUserID == nil:
+				approval.Status = model.LeaveApprovalSkipped
+				approval.Comment = "No approver assigned"
+			case *approval.ApproverUserID == emp.UserID:
+				approval.Status = model.LeaveApprovalSkipped
+				approval.Comment = "Approver is the requester"
+			}
+		}
+
+		approvals = append(approvals, approval)
+	}
+	return approvals
+}
+
+func (c leaveApprovalChain) employeeUserID(ctx context.Context, employeeID string) *string {
+	emp, err := c.empRepo.FindByID(ctx, employeeID)
+	if err != nil {
+		return nil
+	}
+	userID := emp.UserID
+	return &userID
+}
+
+// delegators returns the users who have delegated their approvals to userID
+// on date
+func (c leaveApprovalChain) delegators(ctx context.Context, userID string, date time.Time) map[string]bool {
+	delegators := make(map[string]bool)
+	delegations, err := c.delegationRepo.FindActiveForDelegate(ctx, userID, date)
+	if err != nil {
+		return delegators
+	}
+	for _, d := range delegations {
+		delegators[d.DelegatorID] = true
+	}
+	return delegators
+}
+
+// canDecide reports whether the user may decide the approval step of a leave,
+// either directly or on behalf of a delegator. delegatedFrom is set when the
+// user acts as a delegate. Nobody decides a step of their own leave;
+// otherwise superadmins may decide any step.
+func canDecide(leave *model.Leave, approval *model.LeaveApproval, userID, role string, delegators map[string]bool) (delegatedFrom *string, ok bool) {
+	if leave.Employee.UserID == userID {
+		return nil, false
+	}
+	if role == string(model.RoleSuperAdmin) {
+		return nil, true
+	}
+
+	if approval.ApproverType == model.LeaveApproverRole {
+		return nil, role == string(approval.ApproverRole) || role == string(model.RoleAdmin)
+	}
+
+	if approval.ApproverUserID == nil {
+		return nil, false
+	}
+	if *approval.ApproverUserID == userID {
+		return nil, true
+	}
+	if delegators[*approval.ApproverUserID] {
+		approverID := *approval.ApproverUserID
+		return &approverID, true
+	}
+	return nil, false
+}
+
+// currentApproval returns the first undecided step of a chain, or nil when
+// the chain is complete
+func currentApproval(approvals []model.LeaveApproval) *model.LeaveApproval {
+	for i := range approvals {
+		if approvals[i].Status == model.LeaveApprovalPending {
+			return &approvals[i]
+		}
+	}
+	return nil
+}
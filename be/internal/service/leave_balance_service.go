@@ -0,0 +1,564 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"hris-backend/internal/dto"
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+type LeaveBalanceService interface {
+	GetBalances(ctx context.Context, employeeID string, year int) ([]dto.LeaveBalanceResponse, error)
+	GetLedger(ctx context.Context, employeeID string, year int, leaveType model.LeaveType) ([]dto.LeaveLedgerEntryResponse, error)
+	GetEntitlements(ctx context.Context, employeeID string, year int) ([]dto.LeaveEntitlementResponse, error)
+	SetEntitlement(ctx context.Context, req dto.SetLeaveEntitlementRequest) (*dto.LeaveEntitlementResponse, error)
+	Adjust(ctx context.Context, req dto.LeaveAdjustmentRequest, createdBy string) (*dto.LeaveLedgerEntryResponse, error)
+	Accrue(ctx context.Context, req dto.LeaveAccrualRequest) (*dto.LeaveBalanceJobResponse, error)
+	CloseYear(ctx context.Context, req dto.LeaveYearCloseRequest) (*dto.LeaveBalanceJobResponse, error)
+}
+
+type leaveBalanceService struct {
+	empRepo     repository.EmployeeRepository
+	companyRepo repository.CompanyRepository
+	balances    leaveBalanceCalculator
+}
+
+func NewLeaveBalanceService(
+	balanceRepo repository.LeaveBalanceRepository,
+	policyRepo repository.LeavePolicyRepository,
+	leaveRepo repository.LeaveRepository,
+	empRepo repository.EmployeeRepository,
+	companyRepo repository.CompanyRepository,
+) LeaveBalanceService {
+	return &leaveBalanceService{
+		empRepo:     empRepo,
+		companyRepo: companyRepo,
+		balances: leaveBalanceCalculator{
+			balanceRepo: balanceRepo,
+			policyRepo:  policyRepo,
+			leaveRepo:   leaveRepo,
+		},
+	}
+}
+
+func (s *leaveBalanceService) GetBalances(ctx context.Context, employeeID string, year int) ([]dto.LeaveBalanceResponse, error) {
+	emp, err := s.empRepo.FindByID(ctx, employeeID)
+	if err != nil {
+		return nil, errors.New("employee not found")
+	}
+
+	policies, err := s.balances.policyRepo.FindActiveByCompanyID(ctx, emp.CompanyID)
+	if err != nil {
+		return nil, errors.New("failed to fetch leave policies")
+	}
+
+	balances := make([]dto.LeaveBalanceResponse, 0, len(policies))
+	for i := range policies {
+		balance, err := s.balances.balance(ctx, emp, &policies[i], year, "")
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, *balance)
+	}
+	return balances, nil
+}
+
+func (s *leaveBalanceService) GetLedger(ctx context.Context, employeeID string, year int, leaveType model.LeaveType) ([]dto.LeaveLedgerEntryResponse, error) {
+	var entries []model.LeaveLedgerEntry
+	var err error
+	if leaveType != "" {
+		entries, err = s.balances.balanceRepo.FindEntriesByType(ctx, employeeID, leaveType, year)
+	} else {
+		entries, err = s.balances.balanceRepo.FindEntries(ctx, employeeID, year)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToLeaveLedgerEntryResponses(entries), nil
+}
+
+func (s *leaveBalanceService) GetEntitlements(ctx context.Context, employeeID string, year int) ([]dto.LeaveEntitlementResponse, error) {
+	entitlements, err := s.balances.balanceRepo.FindEntitlementsByEmployeeID(ctx, employeeID, year)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToLeaveEntitlementResponses(entitlements), nil
+}
+
+func (s *leaveBalanceService) SetEntitlement(ctx context.Context, req dto.SetLeaveEntitlementRequest) (*dto.LeaveEntitlementResponse, error) {
+	if _, err := s.empRepo.FindByID(ctx, req.EmployeeID); err != nil {
+		return nil, errors.New("employee not found")
+	}
+	if req.Days < 0 {
+		return nil, errors.New("entitlement days cannot be negative")
+	}
+
+	entitlement := &model.LeaveEntitlement{
+		EmployeeID: req.EmployeeID,
+		LeaveType:  req.LeaveType,
+		Year:       req.Year,
+		Days:       req.Days,
+		Notes:      req.Notes,
+	}
+
+	if err := s.balances.balanceRepo.UpsertEntitlement(ctx, entitlement); err != nil {
+		return nil, errors.New("failed to save leave entitlement")
+	}
+
+	response := dto.ToLeaveEntitlementResponse(entitlement)
+	return &response, nil
+}
+
+func (s *leaveBalanceService) Adjust(ctx context.Context, req dto.LeaveAdjustmentRequest, createdBy string) (*dto.LeaveLedgerEntryResponse, error) {
+	if _, err := s.empRepo.FindByID(ctx, req.EmployeeID); err != nil {
+		return nil, errors.New("employee not found")
+	}
+
+	entry := &model.LeaveLedgerEntry{
+		EmployeeID:  req.EmployeeID,
+		LeaveType:   req.LeaveType,
+		Year:        req.Year,
+		EntryType:   model.LeaveLedgerAdjustment,
+		Days:        req.Days,
+		Description: req.Description,
+		CreatedBy:   &createdBy,
+	}
+
+	if err := s.balances.balanceRepo.CreateEntry(ctx, entry); err != nil {
+		return nil, errors.New("failed to create leave adjustment")
+	}
+
+	response := dto.ToLeaveLedgerEntryResponse(entry)
+	return &response, nil
+}
+
+// Accrue posts the accrual entries due for every active employee of a company
+// up to and including the given month, and expires carried-over days whose
+// expiry date has passed. Entries already posted are not posted again, so the
+// job can be re-run safely (e.g. from a monthly cron).
+func (s *leaveBalanceService) Accrue(ctx context.Context, req dto.LeaveAccrualRequest) (*dto.LeaveBalanceJobResponse, error) {
+	if _, err := s.companyRepo.FindByID(ctx, req.CompanyID); err != nil {
+		return nil, errors.New("company not found")
+	}
+
+	month := req.Month
+	if month == 0 {
+		month = 12
+		if now := time.Now(); now.Year() == req.Year {
+			month = int(now.Month())
+		}
+	}
+
+	policies, err := s.balances.policyRepo.FindActiveByCompanyID(ctx, req.CompanyID)
+	if err != nil {
+		return nil, errors.New("failed to fetch leave policies")
+	}
+
+	yearStart := time.Date(req.Year, 1, 1, 0, 0, 0, 0, time.Local)
+	yearEnd := time.Date(req.Year, 12, 31, 0, 0, 0, 0, time.Local)
+	employees, err := s.empRepo.FindActiveByCompanyID(ctx, req.CompanyID, yearStart, yearEnd)
+	if err != nil {
+		return nil, errors.New("failed to fetch employees")
+	}
+
+	result := &dto.LeaveBalanceJobResponse{EmployeesProcessed: len(employees)}
+	for i := range employees {
+		emp := &employees[i]
+		for j := range policies {
+			entries, err := s.accrualEntries(ctx, emp, &policies[j], req.Year, month)
+			if err != nil {
+				return nil, err
+			}
+
+			expiries, err := s.expiryEntries(ctx, emp, &policies[j], req.Year)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, expiries...)
+
+			if len(entries) == 0 {
+				continue
+			}
+			created, err := s.balances.balanceRepo.CreateEntries(ctx, entries)
+			if err != nil {
+				return nil, errors.New("failed to post leave accruals")
+			}
+			result.EntriesCreated += int(created)
+		}
+	}
+
+	return result, nil
+}
+
+// CloseYear moves each employee's remaining balance of a year into the next
+// year, up to the policy's MaxCarryOverDays; the rest is expired. All entries
+// of one employee and leave type share the reference "year_close:<year>", so
+// a year is only closed once.
+func (s *leaveBalanceService) CloseYear(ctx context.Context, req dto.LeaveYearCloseRequest) (*dto.LeaveBalanceJobResponse, error) {
+	if _, err := s.companyRepo.FindByID(ctx, req.CompanyID); err != nil {
+		return nil, errors.New("company not found")
+	}
+
+	policies, err := s.balances.policyRepo.FindActiveByCompanyID(ctx, req.CompanyID)
+	if err != nil {
+		return nil, errors.New("failed to fetch leave policies")
+	}
+
+	yearStart := time.Date(req.Year, 1, 1, 0, 0, 0, 0, time.Local)
+	yearEnd := time.Date(req.Year, 12, 31, 0, 0, 0, 0, time.Local)
+	employees, err := s.empRepo.FindActiveByCompanyID(ctx, req.CompanyID, yearStart, yearEnd)
+	if err != nil {
+		return nil, errors.New("failed to fetch employees")
+	}
+
+	reference := fmt.Sprintf("year_close:%d", req.Year)
+	result := &dto.LeaveBalanceJobResponse{EmployeesProcessed: len(employees)}
+
+	for i := range employees {
+		emp := &employees[i]
+		for j := range policies {
+			policy := &policies[j]
+
+			closed, err := s.balances.balanceRepo.HasReference(ctx, emp.ID, policy.LeaveType, reference)
+			if err != nil {
+				return nil, errors.New("failed to check leave ledger")
+			}
+			if closed {
+				continue
+			}
+
+			entries, err := s.balances.balanceRepo.FindEntriesByType(ctx, emp.ID, policy.LeaveType, req.Year)
+			if err != nil {
+				return nil, errors.New("failed to fetch leave ledger")
+			}
+			remaining := sumLedgerDays(entries)
+			if remaining <= 0 {
+				continue
+			}
+
+			carry := math.Min(remaining, policy.MaxCarryOverDays)
+			forfeited := remaining - carry
+
+			var closing []model.LeaveLedgerEntry
+			if forfeited > 0 {
+				closing = append(closing, model.LeaveLedgerEntry{
+					EmployeeID:  emp.ID,
+					LeaveType:   policy.LeaveType,
+					Year:        req.Year,
+					EntryType:   model.LeaveLedgerExpiry,
+					Days:        -forfeited,
+					Reference:   reference,
+					Description: fmt.Sprintf("Unused %d balance above carry-over limit", req.Year),
+				})
+			}
+			if carry > 0 {
+				var expiresAt *time.Time
+				if policy.CarryOverExpiryMonths > 0 {
+					exp := time.Date(req.Year+1, 1, 1, 0, 0, 0, 0, time.Local).AddDate(0, policy.CarryOverExpiryMonths, 0)
+					expiresAt = &exp
+				}
+				closing = append(closing,
+					model.LeaveLedgerEntry{
+						EmployeeID:  emp.ID,
+						LeaveType:   policy.LeaveType,
+						Year:        req.Year,
+						EntryType:   model.LeaveLedgerCarryOver,
+						Days:        -carry,
+						Reference:   reference,
+						Description: fmt.Sprintf("Carried over to %d", req.Year+1),
+					},
+					model.LeaveLedgerEntry{
+						EmployeeID:  emp.ID,
+						LeaveType:   policy.LeaveType,
+						Year:        req.Year + 1,
+						EntryType:   model.LeaveLedgerCarryOver,
+						Days:        carry,
+						Reference:   reference,
+						ExpiresAt:   expiresAt,
+						Description: fmt.Sprintf("Carried over from %d", req.Year),
+					},
+				)
+			}
+
+			created, err := s.balances.balanceRepo.CreateEntries(ctx, closing)
+			if err != nil {
+				return nil, errors.New("failed to close leave year")
+			}
+			result.EntriesCreated += int(created)
+		}
+	}
+
+	return result, nil
+}
+
+// accrualEntries returns the accrual entries of a policy that are due but not
+// yet posted. Annual policies grant the full entitlement once per year;
+// monthly policies grant 1/12 for each month the employee was employed.
+func (s *leaveBalanceService) accrualEntries(ctx context.Context, emp *model.Employee, policy *model.LeavePolicy, year, month int) ([]model.LeaveLedgerEntry, error) {
+	entitlement, err := s.balances.entitlementDays(ctx, emp.ID, policy, year)
+	if err != nil {
+		return nil, err
+	}
+	if entitlement <= 0 {
+		return nil, nil
+	}
+
+	var entries []model.LeaveLedgerEntry
+	post := func(reference string, days float64, description string) error {
+		exists, err := s.balances.balanceRepo.HasReference(ctx, emp.ID, policy.LeaveType, reference)
+		if err != nil {
+			return errors.New("failed to check leave ledger")
+		}
+		if !exists {
+			entries = append(entries, model.LeaveLedgerEntry{
+				EmployeeID:  emp.ID,
+				LeaveType:   policy.LeaveType,
+				Year:        year,
+				EntryType:   model.LeaveLedgerAccrual,
+				Days:        days,
+				Reference:   reference,
+				Description: description,
+			})
+		}
+		return nil
+	}
+
+	if policy.AccrualMethod != model.LeaveAccrualMonthly {
+		err := post(fmt.Sprintf("accrual:%d", year), entitlement, fmt.Sprintf("Annual entitlement %d", year))
+		return entries, err
+	}
+
+	monthly := math.Round(entitlement/12*100) / 100
+	for m := 1; m <= month; m++ {
+		monthStart := time.Date(year, time.Month(m), 1, 0, 0, 0, 0, time.Local)
+		monthEnd := monthStart.AddDate(0, 1, -1)
+		if emp.JoinDate.After(monthEnd) {
+			continue
+		}
+		if emp.ResignDate != nil && emp.ResignDate.Before(monthStart) {
+			continue
+		}
+		if err := post(fmt.Sprintf("accrual:%d-%02d", year, m), monthly, fmt.Sprintf("Monthly accrual %d-%02d", year, m)); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// expiryEntries expires the unused part of carried-over days whose expiry
+// date has passed. Days taken up to the expiry date are counted against the
+// carried-over days first.
+func (s *leaveBalanceService) expiryEntries(ctx context.Context, emp *model.Employee, policy *model.LeavePolicy, year int) ([]model.LeaveLedgerEntry, error) {
+	carryOvers, err := s.balances.balanceRepo.FindExpiringCarryOvers(ctx, emp.ID, year)
+	if err != nil {
+		return nil, errors.New("failed to fetch leave ledger")
+	}
+
+	now := time.Now()
+	var entries []model.LeaveLedgerEntry
+	for _, carry := range carryOvers {
+		if carry.LeaveType != policy.LeaveType || carry.ExpiresAt.After(now) {
+			continue
+		}
+
+		reference := "expiry:" + carry.ID
+		exists, err := s.balances.balanceRepo.HasReference(ctx, emp.ID, policy.LeaveType, reference)
+		if err != nil {
+			return nil, errors.New("failed to check leave ledger")
+		}
+		if exists {
+			continue
+		}
+
+		ledger, err := s.balances.balanceRepo.FindEntriesByType(ctx, emp.ID, policy.LeaveType, year)
+		if err != nil {
+			return nil, errors.New("failed to fetch leave ledger")
+		}
+		used := 0.0
+		for _, e := range ledger {
+			if (e.EntryType == model.LeaveLedgerTaken || e.EntryType == model.LeaveLedgerReversal) && !e.CreatedAt.After(*carry.ExpiresAt) {
+				used -= e.Days
+			}
+		}
+
+		expired := carry.Days - math.Max(used, 0)
+		if expired <= 0 {
+			continue
+		}
+		entries = append(entries, model.LeaveLedgerEntry{
+			EmployeeID:  emp.ID,
+			LeaveType:   policy.LeaveType,
+			Year:        year,
+			EntryType:   model.LeaveLedgerExpiry,
+			Days:        -expired,
+			Reference:   reference,
+			Description: fmt.Sprintf("Carried-over days expired on %s", carry.ExpiresAt.Format("2006-01-02")),
+		})
+	}
+	return entries, nil
+}
+
+// leaveBalanceCalculator computes leave balances from the ledger. It is shared
+// by the balance service and the leave service, which checks balances when
+// leave is requested and debits them when leave is approved.
+type leaveBalanceCalculator struct {
+	balanceRepo repository.LeaveBalanceRepository
+	policyRepo  repository.LeavePolicyRepository
+	leaveRepo   repository.LeaveRepository
+}
+
+// entitlementDays returns the employee's override for the year if set,
+// otherwise the policy's AnnualDays
+func (c leaveBalanceCalculator) entitlementDays(ctx context.Context, employeeID string, policy *model.LeavePolicy, year int) (float64, error) {
+	entitlement, err := c.balanceRepo.FindEntitlement(ctx, employeeID, policy.LeaveType, year)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return policy.AnnualDays, nil
+	}
+	if err != nil {
+		return 0, errors.New("failed to fetch leave entitlement")
+	}
+	return entitlement.Days, nil
+}
+
+// balance summarises the ledger of one leave type and year. Pending and in
+// review requests other than excludeLeaveID are subtracted from Available.
+func (c leaveBalanceCalculator) balance(ctx context.Context, emp *model.Employee, policy *model.LeavePolicy, year int, excludeLeaveID string) (*dto.LeaveBalanceResponse, error) {
+	entitlement, err := c.entitlementDays(ctx, emp.ID, policy, year)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := c.balanceRepo.FindEntriesByType(ctx, emp.ID, policy.LeaveType, year)
+	if err != nil {
+		return nil, errors.New("failed to fetch leave ledger")
+	}
+
+	pending, err := c.leaveRepo.SumDaysByStatus(ctx, emp.ID, policy.LeaveType, year, []model.LeaveStatus{model.LeaveStatusPending, model.LeaveStatusInReview}, excludeLeaveID)
+	if err != nil {
+		return nil, errors.New("failed to fetch pending leaves")
+	}
+
+	balance := &dto.LeaveBalanceResponse{
+		EmployeeID:      emp.ID,
+		LeaveType:       policy.LeaveType,
+		Year:            year,
+		Entitlement:     entitlement,
+		Pending:         pending,
+		RequiresBalance: policy.RequiresBalance,
+	}
+	for _, e := range entries {
+		switch e.EntryType {
+		case model.LeaveLedgerAccrual:
+			balance.Accrued += e.Days
+		case model.LeaveLedgerCarryOver:
+			balance.CarriedOver += e.Days
+		case model.LeaveLedgerAdjustment:
+			balance.Adjusted += e.Days
+		case model.LeaveLedgerTaken, model.LeaveLedgerReversal:
+			balance.Taken -= e.Days
+		case model.LeaveLedgerExpiry:
+			balance.Expired -= e.Days
+		}
+	}
+	balance.Balance = sumLedgerDays(entries)
+	balance.Available = balance.Balance - pending
+
+	return balance, nil
+}
+
+// checkAvailable rejects a request of days that exceeds what is available.
+// Leave types without an active policy, or whose policy does not require a
+// balance, are always allowed.
+func (c leaveBalanceCalculator) checkAvailable(ctx context.Context, emp *model.Employee, leaveType model.LeaveType, year int, days float64, excludeLeaveID string) error {
+	policy, err := c.activePolicy(ctx, emp.CompanyID, leaveType)
+	if err != nil {
+		return err
+	}
+	if policy == nil || !policy.RequiresBalance {
+		return nil
+	}
+
+	balance, err := c.balance(ctx, emp, policy, year, excludeLeaveID)
+	if err != nil {
+		return err
+	}
+	if days > balance.Available {
+		return fmt.Errorf("insufficient leave balance: %.1f days available", math.Max(balance.Available, 0))
+	}
+	return nil
+}
+
+// takenEntry returns the ledger entry debiting an approved leave, or nil when
+// the leave type is not balance-tracked
+func (c leaveBalanceCalculator) takenEntry(ctx context.Context, leave *model.Leave, approverID string) (*model.LeaveLedgerEntry, error) {
+	policy, err := c.activePolicy(ctx, leave.Employee.CompanyID, leave.LeaveType)
+	if err != nil || policy == nil {
+		return nil, err
+	}
+
+	leaveID := leave.ID
+	return &model.LeaveLedgerEntry{
+		EmployeeID:  leave.EmployeeID,
+		LeaveType:   leave.LeaveType,
+		Year:        leave.StartDate.Year(),
+		EntryType:   model.LeaveLedgerTaken,
+		Days:        -leave.TotalDays,
+		LeaveID:     &leaveID,
+		Description: fmt.Sprintf("Leave %s to %s", leave.StartDate.Format("2006-01-02"), leave.EndDate.Format("2006-01-02")),
+		CreatedBy:   &approverID,
+	}, nil
+}
+
+// reversalEntry returns the ledger entry crediting back what a leave took, or
+// nil when nothing was debited for it
+func (c leaveBalanceCalculator) reversalEntry(ctx context.Context, leave *model.Leave, userID string) (*model.LeaveLedgerEntry, error) {
+	entries, err := c.balanceRepo.FindEntriesByLeaveID(ctx, leave.ID)
+	if err != nil {
+		return nil, errors.New("failed to fetch leave ledger")
+	}
+
+	taken := sumLedgerDays(entries)
+	if taken == 0 {
+		return nil, nil
+	}
+
+	leaveID := leave.ID
+	return &model.LeaveLedgerEntry{
+		EmployeeID:  leave.EmployeeID,
+		LeaveType:   leave.LeaveType,
+		Year:        leave.StartDate.Year(),
+		EntryType:   model.LeaveLedgerReversal,
+		Days:        -taken,
+		LeaveID:     &leaveID,
+		Description: fmt.Sprintf("Cancelled leave %s to %s", leave.StartDate.Format("2006-01-02"), leave.EndDate.Format("2006-01-02")),
+		CreatedBy:   &userID,
+	}, nil
+}
+
+// activePolicy returns the active policy of a leave type in a company, or nil
+// when it has none
+func (c leaveBalanceCalculator) activePolicy(ctx context.Context, companyID string, leaveType model.LeaveType) (*model.LeavePolicy, error) {
+	policy, err := c.policyRepo.FindByCompanyAndType(ctx, companyID, leaveType)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.New("failed to fetch leave policy")
+	}
+	return policy, nil
+}
+
+func sumLedgerDays(entries []model.LeaveLedgerEntry) float64 {
+	total := 0.0
+	for _, e := range entries {
+		total += e.Days
+	}
+	return math.Round(total*100) / 100
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"hris-backend/internal/dto"
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+)
+
+type LeaveDelegationService interface {
+	GetAll(ctx context.Context) ([]dto.LeaveDelegationResponse, error)
+	GetByUserID(ctx context.Context, userID string) ([]dto.LeaveDelegationResponse, error)
+	Create(ctx context.Context, req dto.CreateLeaveDelegationRequest, createdBy string) (*dto.LeaveDelegationResponse, error)
+	Delete(ctx context.Context, id, userID string, canManage bool) error
+}
+
+type leaveDelegationService struct {
+	delegationRepo repository.LeaveDelegationRepository
+	userRepo       repository.UserRepository
+}
+
+func NewLeaveDelegationService(delegationRepo repository.LeaveDelegationRepository, userRepo repository.UserRepository) LeaveDelegationService {
+	return &leaveDelegationService{
+		delegationRepo: delegationRepo,
+		userRepo:       userRepo,
+	}
+}
+
+func (s *leaveDelegationService) GetAll(ctx context.Context) ([]dto.LeaveDelegationResponse, error) {
+	delegations, err := s.delegationRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToLeaveDelegationResponses(delegations), nil
+}
+
+func (s *leaveDelegationService) GetByUserID(ctx context.Context, userID string) ([]dto.LeaveDelegationResponse, error) {
+	delegations, err := s.delegationRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToLeaveDelegationResponses(delegations), nil
+}
+
+// Create delegates req.DelegatorID's approval steps to req.DelegateID for the
+// given period. DelegatorID defaults to createdBy.
+func (s *leaveDelegationService) Create(ctx context.Context, req dto.CreateLeaveDelegationRequest, createdBy string) (*dto.LeaveDelegationResponse, error) {
+	delegatorID := req.DelegatorID
+	if delegatorID == "" {
+		delegatorID = createdBy
+	}
+
+	if delegatorID == req.DelegateID {
+		return nil, errors.New("cannot delegate approvals to yourself")
+	}
+
+	if _, err := s.userRepo.FindByID(ctx, delegatorID); err != nil {
+		return nil, errors.New("delegator not found")
+	}
+	if _, err := s.userRepo.FindByID(ctx, req.DelegateID); err != nil {
+		return nil, errors.New("delegate not found")
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, errors.New("invalid start date format, use YYYY-MM-DD")
+	}
+
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, errors.New("invalid end date format, use YYYY-MM-DD")
+	}
+
+	if endDate.Before(startDate) {
+		return nil, errors.New("end date must be after start date")
+	}
+
+	delegation := &model.LeaveDelegation{
+		DelegatorID: delegatorID,
+		DelegateID:  req.DelegateID,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Reason:      req.Reason,
+		CreatedBy:   createdBy,
+	}
+
+	if err := s.delegationRepo.Create(ctx, delegation); err != nil {
+		return nil, errors.New("failed to create leave delegation")
+	}
+
+	created, err := s.delegationRepo.FindByID(ctx, delegation.ID)
+	if err != nil {
+		return nil, errors.New("failed to load leave delegation")
+	}
+
+	response := dto.ToLeaveDelegationResponse(created)
+	return &response, nil
+}
+
+// Delete removes a delegation. Only its delegator, or a user who can manage
+// delegations, may remove it.
+func (s *leaveDelegationService) Delete(ctx context.Context, id, userID string, canManage bool) error {
+	delegation, err := s.delegationRepo.FindByID(ctx, id)
+	if err != nil {
+		return errors.New("leave delegation not found")
+	}
+
+	if !canManage && delegation.DelegatorID != userID {
+		return errors.New("you can only remove your own delegations")
+	}
+
+	return s.delegationRepo.Delete(ctx, id)
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"hris-backend/internal/dto"
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+)
+
+type LeavePolicyService interface {
+	GetAll(ctx context.Context) ([]dto.LeavePolicyResponse, error)
+	GetByCompanyID(ctx context.Context, companyID string) ([]dto.LeavePolicyResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.LeavePolicyResponse, error)
+	Create(ctx context.Context, req dto.CreateLeavePolicyRequest) (*dto.LeavePolicyResponse, error)
+	Update(ctx context.Context, id string, req dto.UpdateLeavePolicyRequest) (*dto.LeavePolicyResponse, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type leavePolicyService struct {
+	policyRepo  repository.LeavePolicyRepository
+	companyRepo repository.CompanyRepository
+}
+
+func NewLeavePolicyService(policyRepo repository.LeavePolicyRepository, companyRepo repository.CompanyRepository) LeavePolicyService {
+	return &leavePolicyService{
+		policyRepo:  policyRepo,
+		companyRepo: companyRepo,
+	}
+}
+
+func (s *leavePolicyService) GetAll(ctx context.Context) ([]dto.LeavePolicyResponse, error) {
+	policies, err := s.policyRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToLeavePolicyResponses(policies), nil
+}
+
+func (s *leavePolicyService) GetByCompanyID(ctx context.Context, companyID string) ([]dto.LeavePolicyResponse, error) {
+	policies, err := s.policyRepo.FindByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToLeavePolicyResponses(policies), nil
+}
+
+func (s *leavePolicyService) GetByID(ctx context.Context, id string) (*dto.LeavePolicyResponse, error) {
+	policy, err := s.policyRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("leave policy not found")
+	}
+	response := dto.ToLeavePolicyResponse(policy)
+	return &response, nil
+}
+
+func (s *leavePolicyService) Create(ctx context.Context, req dto.CreateLeavePolicyRequest) (*dto.LeavePolicyResponse, error) {
+	if _, err := s.companyRepo.FindByID(ctx, req.CompanyID); err != nil {
+		return nil, errors.New("company not found")
+	}
+
+	if existing, _ := s.policyRepo.FindByCompanyAndType(ctx, req.CompanyID, req.LeaveType); existing != nil {
+		return nil, errors.New("leave policy already exists for this leave type")
+	}
+
+	policy := &model.LeavePolicy{
+		CompanyID:             req.CompanyID,
+		LeaveType:             req.LeaveType,
+		AnnualDays:            req.AnnualDays,
+		AccrualMethod:         req.AccrualMethod,
+		MaxCarryOverDays:      req.MaxCarryOverDays,
+		CarryOverExpiryMonths: req.CarryOverExpiryMonths,
+		RequiresBalance:       true,
+		IsActive:              true,
+	}
+
+	if policy.AccrualMethod == "" {
+		policy.AccrualMethod = model.LeaveAccrualAnnual
+	}
+	if req.RequiresBalance != nil {
+		policy.RequiresBalance = *req.RequiresBalance
+	}
+
+	if err := validateLeavePolicy(policy); err != nil {
+		return nil, err
+	}
+
+	if err := s.policyRepo.Create(ctx, policy); err != nil {
+		return nil, errors.New("failed to create leave policy")
+	}
+
+	response := dto.ToLeavePolicyResponse(policy)
+	return &response, nil
+}
+
+func (s *leavePolicyService) Update(ctx context.Context, id string, req dto.UpdateLeavePolicyRequest) (*dto.LeavePolicyResponse, error) {
+	policy, err := s.policyRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("leave policy not found")
+	}
+
+	if req.AnnualDays != nil {
+		policy.AnnualDays = *req.AnnualDays
+	}
+	if req.AccrualMethod != "" {
+		policy.AccrualMethod = req.AccrualMethod
+	}
+	if req.MaxCarryOverDays != nil {
+		policy.MaxCarryOverDays = *req.MaxCarryOverDays
+	}
+	if req.CarryOverExpiryMonths != nil {
+		policy.CarryOverExpiryMonths = *req.CarryOverExpiryMonths
+	}
+	if req.RequiresBalance != nil {
+		policy.RequiresBalance = *req.RequiresBalance
+	}
+	if req.IsActive != nil {
+		policy.IsActive = *req.IsActive
+	}
+
+	if err := validateLeavePolicy(policy); err != nil {
+		return nil, err
+	}
+
+	if err := s.policyRepo.Update(ctx, policy); err != nil {
+		return nil, errors.New("failed to update leave policy")
+	}
+
+	response := dto.ToLeavePolicyResponse(policy)
+	return &response, nil
+}
+
+func (s *leavePolicyService) Delete(ctx context.Context, id string) error {
+	_, err := s.policyRepo.FindByID(ctx, id)
+	if err != nil {
+		return errors.New("leave policy not found")
+	}
+	return s.policyRepo.Delete(ctx, id)
+}
+
+func validateLeavePolicy(policy *model.LeavePolicy) error {
+	if policy.AccrualMethod != model.LeaveAccrualAnnual && policy.AccrualMethod != model.LeaveAccrualMonthly {
+		return errors.New("accrual method must be annual or monthly")
+	}
+	if policy.AnnualDays < 0 || policy.MaxCarryOverDays < 0 || policy.CarryOverExpiryMonths < 0 {
+		return errors.New("leave policy days and months cannot be negative")
+	}
+	return nil
+}
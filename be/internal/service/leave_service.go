@@ -1,47 +1,81 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"hris-backend/internal/dto"
 	"hris-backend/internal/model"
 	"hris-backend/internal/repository"
+	"hris-backend/pkg/kafka"
+
+	"github.com/google/uuid"
 )
 
 type LeaveService interface {
-	GetAll() ([]dto.LeaveResponse, error)
-	GetByID(id string) (*dto.LeaveResponse, error)
-	GetByEmployeeID(employeeID string) ([]dto.LeaveResponse, error)
-	GetPending() ([]dto.LeaveResponse, error)
-	Create(req dto.CreateLeaveRequest) (*dto.LeaveResponse, error)
-	Update(id string, req dto.UpdateLeaveRequest) (*dto.LeaveResponse, error)
-	Approve(id string, approverID string, req dto.ApproveLeaveRequest) (*dto.LeaveResponse, error)
-	Delete(id string) error
+	GetAll(ctx context.Context) ([]dto.LeaveResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.LeaveResponse, error)
+	GetByEmployeeID(ctx context.Context, employeeID string) ([]dto.LeaveResponse, error)
+	GetPending(ctx context.Context) ([]dto.LeaveResponse, error)
+	GetAwaitingApproval(ctx context.Context, userID, role string) ([]dto.LeaveResponse, error)
+	Create(ctx context.Context, req dto.CreateLeaveRequest) (*dto.LeaveResponse, error)
+	Update(ctx context.Context, id string, req dto.UpdateLeaveRequest) (*dto.LeaveResponse, error)
+	Approve(ctx context.Context, id string, approverID, approverRole string, req dto.ApproveLeaveRequest) (*dto.LeaveResponse, error)
+	Cancel(ctx context.Context, id, userID string, canManage bool, req dto.CancelLeaveRequest) (*dto.LeaveResponse, error)
+	Delete(ctx context.Context, id string) error
 }
 
 type leaveService struct {
-	leaveRepo repository.LeaveRepository
-	empRepo   repository.EmployeeRepository
+	leaveRepo   repository.LeaveRepository
+	empRepo     repository.EmployeeRepository
+	holidayRepo repository.HolidayRepository
+	attRepo     repository.AttendanceRepository
+	balances    leaveBalanceCalculator
+	approvals   leaveApprovalChain
 }
 
-func NewLeaveService(leaveRepo repository.LeaveRepository, empRepo repository.EmployeeRepository) LeaveService {
+func NewLeaveService(
+	leaveRepo repository.LeaveRepository,
+	empRepo repository.EmployeeRepository,
+	deptRepo repository.DepartmentRepository,
+	holidayRepo repository.HolidayRepository,
+	attRepo repository.AttendanceRepository,
+	policyRepo repository.LeavePolicyRepository,
+	balanceRepo repository.LeaveBalanceRepository,
+	workflowRepo repository.LeaveWorkflowRepository,
+	delegationRepo repository.LeaveDelegationRepository,
+) LeaveService {
 	return &leaveService{
-		leaveRepo: leaveRepo,
-		empRepo:   empRepo,
+		leaveRepo:   leaveRepo,
+		empRepo:     empRepo,
+		holidayRepo: holidayRepo,
+		attRepo:     attRepo,
+		balances: leaveBalanceCalculator{
+			balanceRepo: balanceRepo,
+			policyRepo:  policyRepo,
+			leaveRepo:   leaveRepo,
+		},
+		approvals: leaveApprovalChain{
+			workflowRepo:   workflowRepo,
+			delegationRepo: delegationRepo,
+			empRepo:        empRepo,
+			deptRepo:       deptRepo,
+		},
 	}
 }
 
-func (s *leaveService) GetAll() ([]dto.LeaveResponse, error) {
-	leaves, err := s.leaveRepo.FindAll()
+func (s *leaveService) GetAll(ctx context.Context) ([]dto.LeaveResponse, error) {
+	leaves, err := s.leaveRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToLeaveResponses(leaves), nil
 }
 
-func (s *leaveService) GetByID(id string) (*dto.LeaveResponse, error) {
-	leave, err := s.leaveRepo.FindByID(id)
+func (s *leaveService) GetByID(ctx context.Context, id string) (*dto.LeaveResponse, error) {
+	leave, err := s.leaveRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("leave not found")
 	}
@@ -49,24 +83,46 @@ func (s *leaveService) GetByID(id string) (*dto.LeaveResponse, error) {
 	return &response, nil
 }
 
-func (s *leaveService) GetByEmployeeID(employeeID string) ([]dto.LeaveResponse, error) {
-	leaves, err := s.leaveRepo.FindByEmployeeID(employeeID)
+func (s *leaveService) GetByEmployeeID(ctx context.Context, employeeID string) ([]dto.LeaveResponse, error) {
+	leaves, err := s.leaveRepo.FindByEmployeeID(ctx, employeeID)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToLeaveResponses(leaves), nil
 }
 
-func (s *leaveService) GetPending() ([]dto.LeaveResponse, error) {
-	leaves, err := s.leaveRepo.FindByStatus(model.LeaveStatusPending)
+func (s *leaveService) GetPending(ctx context.Context) ([]dto.LeaveResponse, error) {
+	leaves, err := s.leaveRepo.FindByStatus(ctx, model.LeaveStatusPending)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToLeaveResponses(leaves), nil
 }
 
-func (s *leaveService) Create(req dto.CreateLeaveRequest) (*dto.LeaveResponse, error) {
-	_, err := s.empRepo.FindByID(req.EmployeeID)
+// GetAwaitingApproval returns the leaves whose current approval step the
+// user may decide, directly or as a delegate
+func (s *leaveService) GetAwaitingApproval(ctx context.Context, userID, role string) ([]dto.LeaveResponse, error) {
+	leaves, err := s.leaveRepo.FindAwaitingApproval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	delegators := s.approvals.delegators(ctx, userID, time.Now())
+	var awaiting []model.Leave
+	for _, leave := range leaves {
+		current := currentApproval(leave.Approvals)
+		if current == nil {
+			continue
+		}
+		if _, ok := canDecide(&leave, current, userID, role, delegators); ok {
+			awaiting = append(awaiting, leave)
+		}
+	}
+	return dto.ToLeaveResponses(awaiting), nil
+}
+
+func (s *leaveService) Create(ctx context.Context, req dto.CreateLeaveRequest) (*dto.LeaveResponse, error) {
+	emp, err := s.empRepo.FindByID(ctx, req.EmployeeID)
 	if err != nil {
 		return nil, errors.New("employee not found")
 	}
@@ -85,22 +141,46 @@ func (s *leaveService) Create(req dto.CreateLeaveRequest) (*dto.LeaveResponse, e
 		return nil, errors.New("end date must be after start date")
 	}
 
+	totalDays, err := s.chargeableDays(ctx, emp, startDate, endDate, req.StartHalfDay, req.EndHalfDay, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.balances.checkAvailable(ctx, emp, req.LeaveType, startDate.Year(), totalDays, ""); err != nil {
+		return nil, err
+	}
+
 	leave := &model.Leave{
-		EmployeeID: req.EmployeeID,
-		LeaveType:  req.LeaveType,
-		StartDate:  startDate,
-		EndDate:    endDate,
-		TotalDays:  req.TotalDays,
-		Reason:     req.Reason,
-		Attachment: req.Attachment,
-		Status:     model.LeaveStatusPending,
+		EmployeeID:   req.EmployeeID,
+		LeaveType:    req.LeaveType,
+		StartDate:    startDate,
+		EndDate:      endDate,
+		StartHalfDay: req.StartHalfDay,
+		EndHalfDay:   req.EndHalfDay,
+		TotalDays:    totalDays,
+		Reason:       req.Reason,
+		Attachment:   req.Attachment,
+		Status:       model.LeaveStatusPending,
 	}
 
-	if err := s.leaveRepo.Create(leave); err != nil {
+	approvals := s.approvals.build(ctx, emp)
+
+	// The ID is set up front for the event announcing the leave to the
+	// approvers of its first step
+	leave.ID = uuid.New().String()
+	event, err := leaveAwaitingEvent(ctx, leave, emp, currentApproval(approvals))
+	if err != nil {
+		return nil, errors.New("failed to create leave request")
+	}
+
+	if err := s.leaveRepo.CreateWithApprovals(ctx, leave, approvals, []model.OutboxEvent{event}); err != nil {
+		if errors.Is(err, repository.ErrLeaveOverlaps) {
+			return nil, err
+		}
 		return nil, errors.New("failed to create leave request")
 	}
 
-	created, err := s.leaveRepo.FindByID(leave.ID)
+	created, err := s.leaveRepo.FindByID(ctx, leave.ID)
 	if err != nil {
 		return nil, errors.New("failed to load leave request")
 	}
@@ -109,8 +189,8 @@ func (s *leaveService) Create(req dto.CreateLeaveRequest) (*dto.LeaveResponse, e
 	return &response, nil
 }
 
-func (s *leaveService) Update(id string, req dto.UpdateLeaveRequest) (*dto.LeaveResponse, error) {
-	leave, err := s.leaveRepo.FindByID(id)
+func (s *leaveService) Update(ctx context.Context, id string, req dto.UpdateLeaveRequest) (*dto.LeaveResponse, error) {
+	leave, err := s.leaveRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("leave not found")
 	}
@@ -136,8 +216,11 @@ func (s *leaveService) Update(id string, req dto.UpdateLeaveRequest) (*dto.Leave
 		}
 		leave.EndDate = ed
 	}
-	if req.TotalDays != nil {
-		leave.TotalDays = *req.TotalDays
+	if req.StartHalfDay != nil {
+		leave.StartHalfDay = *req.StartHalfDay
+	}
+	if req.EndHalfDay != nil {
+		leave.EndHalfDay = *req.EndHalfDay
 	}
 	if req.Reason != "" {
 		leave.Reason = req.Reason
@@ -150,7 +233,25 @@ func (s *leaveService) Update(id string, req dto.UpdateLeaveRequest) (*dto.Leave
 		return nil, errors.New("end date must be after start date")
 	}
 
-	if err := s.leaveRepo.Update(leave); err != nil {
+	emp, err := s.empRepo.FindByID(ctx, leave.EmployeeID)
+	if err != nil {
+		return nil, errors.New("employee not found")
+	}
+
+	totalDays, err := s.chargeableDays(ctx, emp, leave.StartDate, leave.EndDate, leave.StartHalfDay, leave.EndHalfDay, leave.ID)
+	if err != nil {
+		return nil, err
+	}
+	leave.TotalDays = totalDays
+
+	if err := s.balances.checkAvailable(ctx, emp, leave.LeaveType, leave.StartDate.Year(), leave.TotalDays, leave.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.leaveRepo.Update(ctx, leave); err != nil {
+		if errors.Is(err, repository.ErrLeaveOverlaps) {
+			return nil, err
+		}
 		return nil, errors.New("failed to update leave request")
 	}
 
@@ -158,38 +259,116 @@ func (s *leaveService) Update(id string, req dto.UpdateLeaveRequest) (*dto.Leave
 	return &response, nil
 }
 
-func (s *leaveService) Approve(id string, approverID string, req dto.ApproveLeaveRequest) (*dto.LeaveResponse, error) {
-	leave, err := s.leaveRepo.FindByID(id)
+// Approve records the decision of the current approval step. A rejection ends
+// the chain; an approval moves the leave to the next step, or approves it when
+// it was the last step, debiting the balance ledger and writing the leave's
+// attendance rows.
+func (s *leaveService) Approve(ctx context.Context, id string, approverID, approverRole string, req dto.ApproveLeaveRequest) (*dto.LeaveResponse, error) {
+	leave, err := s.leaveRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("leave not found")
 	}
 
-	if leave.Status != model.LeaveStatusPending {
-		return nil, errors.New("can only approve/reject pending leave requests")
+	if leave.Status != model.LeaveStatusPending && leave.Status != model.LeaveStatusInReview {
+		return nil, errors.New("can only approve/reject leave requests awaiting approval")
 	}
 
 	if req.Status != model.LeaveStatusApproved && req.Status != model.LeaveStatusRejected {
 		return nil, errors.New("status must be approved or rejected")
 	}
 
+	if req.Status == model.LeaveStatusRejected && req.RejectionReason == "" {
+		return nil, errors.New("rejection reason is required")
+	}
+
+	current := currentApproval(leave.Approvals)
+	if current == nil {
+		return nil, errors.New("leave request has no pending approval step")
+	}
+
+	if leave.Employee.UserID == approverID {
+		return nil, errors.New("you cannot decide your own leave request")
+	}
+
 	now := time.Now()
-	leave.Status = req.Status
-	leave.ApprovedBy = &approverID
-	leave.ApprovedAt = &now
+	delegatedFrom, ok := canDecide(leave, current, approverID, approverRole, s.approvals.delegators(ctx, approverID, now))
+	if !ok {
+		return nil, errors.New("you are not an approver of the current step")
+	}
 
-	if req.Status == model.LeaveStatusRejected {
-		if req.RejectionReason == "" {
-			return nil, errors.New("rejection reason is required")
-		}
+	from := leave.Status
+	current.Status = model.LeaveApprovalStatus(req.Status)
+	current.DecidedBy = &approverID
+	current.DelegatedFrom = delegatedFrom
+	current.DecidedAt = &now
+	current.Comment = req.Comment
+	if current.Comment == "" {
+		current.Comment = req.RejectionReason
+	}
+	decided := []model.LeaveApproval{*current}
+
+	// Approval of the final step debits the balance ledger and writes the
+	// attendance rows in the same transaction
+	var entries []model.LeaveLedgerEntry
+	var attendances []model.Attendance
+	switch {
+	case req.Status == model.LeaveStatusRejected:
+		leave.Status = model.LeaveStatusRejected
 		leave.RejectionReason = req.RejectionReason
+		leave.ApprovedBy = &approverID
+		leave.ApprovedAt = &now
+		for i := range leave.Approvals {
+			if leave.Approvals[i].Status == model.LeaveApprovalPending {
+				leave.Approvals[i].Status = model.LeaveApprovalSkipped
+				decided = append(decided, leave.Approvals[i])
+			}
+		}
+	case currentApproval(leave.Approvals) != nil:
+		leave.Status = model.LeaveStatusInReview
+	default:
+		if err := s.balances.checkAvailable(ctx, &leave.Employee, leave.LeaveType, leave.StartDate.Year(), leave.TotalDays, leave.ID); err != nil {
+			return nil, err
+		}
+		entry, err := s.balances.takenEntry(ctx, leave, approverID)
+		if err != nil {
+			return nil, err
+		}
+		if entry != nil {
+			entries = append(entries, *entry)
+		}
+		attendances, err = s.attendanceRows(ctx, leave)
+		if err != nil {
+			return nil, err
+		}
+		leave.Status = model.LeaveStatusApproved
+		leave.ApprovedBy = &approverID
+		leave.ApprovedAt = &now
 	}
 
-	if err := s.leaveRepo.Update(leave); err != nil {
+	event, err := leaveStatusEvent(ctx, leave)
+	if err != nil {
+		return nil, errors.New("failed to update leave status")
+	}
+	events := []model.OutboxEvent{event}
+
+	// The approvers of the next step learn that the leave awaits them
+	if leave.Status == model.LeaveStatusInReview {
+		next, err := leaveAwaitingEvent(ctx, leave, &leave.Employee, currentApproval(leave.Approvals))
+		if err != nil {
+			return nil, errors.New("failed to update leave status")
+		}
+		events = append(events, next)
+	}
+
+	if err := s.leaveRepo.UpdateWithDecision(ctx, leave, from, decided, entries, attendances, events); err != nil {
+		if errors.Is(err, repository.ErrLeaveChanged) {
+			return nil, err
+		}
 		return nil, errors.New("failed to update leave status")
 	}
 
 	// Reload with relations
-	updated, err := s.leaveRepo.FindByID(leave.ID)
+	updated, err := s.leaveRepo.FindByID(ctx, leave.ID)
 	if err != nil {
 		return nil, errors.New("failed to load leave request")
 	}
@@ -198,8 +377,118 @@ func (s *leaveService) Approve(id string, approverID string, req dto.ApproveLeav
 	return &response, nil
 }
 
-func (s *leaveService) Delete(id string) error {
-	leave, err := s.leaveRepo.FindByID(id)
+// Cancel withdraws a leave request that is awaiting approval, or cancels an
+// approved one, crediting back its balance and reverting its attendance rows.
+// Employees may cancel their own leave before it starts; users who can manage
+// leave may cancel any leave.
+func (s *leaveService) Cancel(ctx context.Context, id, userID string, canManage bool, req dto.CancelLeaveRequest) (*dto.LeaveResponse, error) {
+	leave, err := s.leaveRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("leave not found")
+	}
+
+	if !canManage {
+		if leave.Employee.UserID != userID {
+			return nil, errors.New("you can only cancel your own leave requests")
+		}
+		if leave.Status == model.LeaveStatusApproved && !time.Now().Before(leave.StartDate) {
+			return nil, errors.New("leave has already started, ask HR to cancel it")
+		}
+	}
+
+	switch leave.Status {
+	case model.LeaveStatusPending, model.LeaveStatusInReview, model.LeaveStatusApproved:
+	default:
+		return nil, errors.New("can only cancel pending, in review or approved leave requests")
+	}
+
+	var entries []model.LeaveLedgerEntry
+	if leave.Status == model.LeaveStatusApproved {
+		entry, err := s.balances.reversalEntry(ctx, leave, userID)
+		if err != nil {
+			return nil, err
+		}
+		if entry != nil {
+			entries = append(entries, *entry)
+		}
+	}
+
+	var skipped []model.LeaveApproval
+	for i := range leave.Approvals {
+		if leave.Approvals[i].Status == model.LeaveApprovalPending {
+			leave.Approvals[i].Status = model.LeaveApprovalSkipped
+			skipped = append(skipped, leave.Approvals[i])
+		}
+	}
+
+	from := leave.Status
+	now := time.Now()
+	leave.Status = model.LeaveStatusCancelled
+	leave.CancelledBy = &userID
+	leave.CancelledAt = &now
+	leave.CancelReason = req.Reason
+
+	// Employees withdrawing their own leave need no notification
+	var events []model.OutboxEvent
+	if leave.Employee.UserID != userID {
+		event, err := leaveStatusEvent(ctx, leave)
+		if err != nil {
+			return nil, errors.New("failed to cancel leave request")
+		}
+		events = append(events, event)
+	}
+
+	if err := s.leaveRepo.Cancel(ctx, leave, from, skipped, entries, events); err != nil {
+		if errors.Is(err, repository.ErrLeaveChanged) {
+			return nil, err
+		}
+		return nil, errors.New("failed to cancel leave request")
+	}
+
+	updated, err := s.leaveRepo.FindByID(ctx, leave.ID)
+	if err != nil {
+		return nil, errors.New("failed to load leave request")
+	}
+
+	response := dto.ToLeaveResponse(updated)
+	return &response, nil
+}
+
+// leaveAwaitingEvent tells the approvers of step that the leave of emp awaits
+// their decision. It is keyed by employee, like the leave's other events.
+func leaveAwaitingEvent(ctx context.Context, leave *model.Leave, emp *model.Employee, step *model.LeaveApproval) (model.OutboxEvent, error) {
+	payload := kafka.LeaveSubmittedPayload{
+		LeaveID:      leave.ID,
+		CompanyID:    emp.CompanyID,
+		EmployeeName: emp.User.Name,
+		LeaveType:    string(leave.LeaveType),
+		TotalDays:    leave.TotalDays,
+	}
+	if step != nil {
+		if step.ApproverUserID != nil {
+			payload.ApproverUserID = *step.ApproverUserID
+		} else {
+			payload.ApproverRole = string(step.ApproverRole)
+		}
+	}
+	return kafka.NewOutboxEvent(ctx, kafka.EventLeaveSubmitted, emp.ID, emp.CompanyID, payload)
+}
+
+// leaveStatusEvent tells the employee their leave moved to a new status. It
+// is keyed by employee, like the submission, so a leave's events stay in
+// order.
+func leaveStatusEvent(ctx context.Context, leave *model.Leave) (model.OutboxEvent, error) {
+	return kafka.NewOutboxEvent(ctx, kafka.EventLeaveStatusChanged, leave.EmployeeID, leave.Employee.CompanyID, kafka.LeaveStatusChangedPayload{
+		LeaveID:         leave.ID,
+		EmployeeUserID:  leave.Employee.UserID,
+		EmployeeName:    leave.Employee.User.Name,
+		NewStatus:       string(leave.Status),
+		RejectionReason: leave.RejectionReason,
+	})
+}
+
+func (s *leaveService) Delete(ctx context.Context, id string) error {
+	leave, err := s.leaveRepo.FindByID(ctx, id)
 	if err != nil {
 		return errors.New("leave not found")
 	}
@@ -208,5 +497,107 @@ func (s *leaveService) Delete(id string) error {
 		return errors.New("can only delete pending leave requests")
 	}
 
-	return s.leaveRepo.Delete(id)
+	return s.leaveRepo.Delete(ctx, id)
+}
+
+// chargeableDays computes the working days a leave charges from the
+// employee's shift work days and the company holiday calendar. It rejects
+// periods without working days and periods that overlap the employee's other
+// pending or approved leaves.
+func (s *leaveService) chargeableDays(ctx context.Context, emp *model.Employee, start, end time.Time, startHalfDay, endHalfDay bool, excludeLeaveID string) (float64, error) {
+	overlapping, err := s.leaveRepo.FindOverlapping(ctx, emp.ID, start, end, excludeLeaveID)
+	if err != nil {
+		return 0, errors.New("failed to check overlapping leaves")
+	}
+	if len(overlapping) > 0 {
+		other := overlapping[0]
+		return 0, fmt.Errorf("leave overlaps with an existing %s leave from %s to %s",
+			other.Status, other.StartDate.Format("2006-01-02"), other.EndDate.Format("2006-01-02"))
+	}
+
+	calendar, err := s.workCalendar(ctx, emp, start, end)
+	if err != nil {
+		return 0, err
+	}
+
+	days := calendar.chargeableDays(start, end, startHalfDay, endHalfDay)
+	if days <= 0 {
+		return 0, errors.New("leave period contains no working days")
+	}
+	return days, nil
+}
+
+// workCalendar returns the employee's working calendar for a period
+func (s *leaveService) workCalendar(ctx context.Context, emp *model.Employee, start, end time.Time) (workCalendar, error) {
+	holidays, err := s.holidayRepo.FindByCompanyIDAndDateRange(ctx, emp.CompanyID, start, end)
+	if err != nil {
+		return workCalendar{}, errors.New("failed to fetch holidays")
+	}
+	return newWorkCalendar(&emp.Shift, holidays), nil
+}
+
+// attendanceRows returns the attendance rows an approved leave writes: one per
+// full working day, replacing any existing row for that day. Half days are
+// left alone since the employee still clocks in for the other half.
+func (s *leaveService) attendanceRows(ctx context.Context, leave *model.Leave) ([]model.Attendance, error) {
+	emp, err := s.empRepo.FindByID(ctx, leave.EmployeeID)
+	if err != nil {
+		return nil, errors.New("employee not found")
+	}
+
+	calendar, err := s.workCalendar(ctx, emp, leave.StartDate, leave.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.attRepo.FindByEmployeeIDAndDateRange(ctx, leave.EmployeeID, leave.StartDate, leave.EndDate)
+	if err != nil {
+		return nil, errors.New("failed to fetch attendance data")
+	}
+	byDate := make(map[string]model.Attendance, len(existing))
+	for _, att := range existing {
+		byDate[att.Date.Format("2006-01-02")] = att
+	}
+
+	status := leaveAttendanceStatus(leave.LeaveType)
+	leaveID := leave.ID
+	var rows []model.Attendance
+	for _, date := range calendar.workingDates(leave.StartDate, leave.EndDate) {
+		if (leave.StartHalfDay && date.Equal(leave.StartDate)) || (leave.EndHalfDay && date.Equal(leave.EndDate)) {
+			continue
+		}
+
+		att, ok := byDate[date.Format("2006-01-02")]
+		if ok {
+			att.StatusBeforeLeave = att.Status
+		} else {
+			att = model.Attendance{
+				EmployeeID: leave.EmployeeID,
+				ShiftID:    emp.ShiftID,
+				Date:       date,
+			}
+		}
+		att.Status = status
+		att.LeaveID = &leaveID
+		if att.Notes == "" {
+			att.Notes = fmt.Sprintf("Approved leave (%s)", leave.LeaveType)
+		}
+		rows = append(rows, att)
+	}
+	return rows, nil
+}
+
+// leaveAttendanceStatus maps a leave type to the attendance status recorded
+// for its days. Business trips count as present.
+func leaveAttendanceStatus(leaveType model.LeaveType) model.AttendanceStatus {
+	switch leaveType {
+	case model.LeaveCutiSakit:
+		return model.AttendanceSakit
+	case model.LeaveIzin:
+		return model.AttendanceIzin
+	case model.LeaveDinasLuar:
+		return model.AttendanceHadir
+	default:
+		return model.AttendanceCuti
+	}
 }
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"hris-backend/internal/dto"
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+)
+
+type LeaveWorkflowService interface {
+	GetAll(ctx context.Context) ([]dto.LeaveWorkflowResponse, error)
+	GetByCompanyID(ctx context.Context, companyID string) ([]dto.LeaveWorkflowResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.LeaveWorkflowResponse, error)
+	Create(ctx context.Context, req dto.CreateLeaveWorkflowRequest) (*dto.LeaveWorkflowResponse, error)
+	Update(ctx context.Context, id string, req dto.UpdateLeaveWorkflowRequest) (*dto.LeaveWorkflowResponse, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type leaveWorkflowService struct {
+	workflowRepo repository.LeaveWorkflowRepository
+	companyRepo  repository.CompanyRepository
+	deptRepo     repository.DepartmentRepository
+	userRepo     repository.UserRepository
+}
+
+func NewLeaveWorkflowService(
+	workflowRepo repository.LeaveWorkflowRepository,
+	companyRepo repository.CompanyRepository,
+	deptRepo repository.DepartmentRepository,
+	userRepo repository.UserRepository,
+) LeaveWorkflowService {
+	return &leaveWorkflowService{
+		workflowRepo: workflowRepo,
+		companyRepo:  companyRepo,
+		deptRepo:     deptRepo,
+		userRepo:     userRepo,
+	}
+}
+
+func (s *leaveWorkflowService) GetAll(ctx context.Context) ([]dto.LeaveWorkflowResponse, error) {
+	workflows, err := s.workflowRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToLeaveWorkflowResponses(workflows), nil
+}
+
+func (s *leaveWorkflowService) GetByCompanyID(ctx context.Context, companyID string) ([]dto.LeaveWorkflowResponse, error) {
+	workflows, err := s.workflowRepo.FindByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToLeaveWorkflowResponses(workflows), nil
+}
+
+func (s *leaveWorkflowService) GetByID(ctx context.Context, id string) (*dto.LeaveWorkflowResponse, error) {
+	workflow, err := s.workflowRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("leave workflow not found")
+	}
+	response := dto.ToLeaveWorkflowResponse(workflow)
+	return &response, nil
+}
+
+func (s *leaveWorkflowService) Create(ctx context.Context, req dto.CreateLeaveWorkflowRequest) (*dto.LeaveWorkflowResponse, error) {
+	if _, err := s.companyRepo.FindByID(ctx, req.CompanyID); err != nil {
+		return nil, errors.New("company not found")
+	}
+
+	var departmentID *string
+	if req.DepartmentID != "" {
+		dept, err := s.deptRepo.FindByID(ctx, req.DepartmentID)
+		if err != nil {
+			return nil, errors.New("department not found")
+		}
+		if dept.CompanyID != req.CompanyID {
+			return nil, errors.New("department does not belong to the company")
+		}
+		departmentID = &req.DepartmentID
+	}
+
+	if existing, _ := s.workflowRepo.FindByScope(ctx, req.CompanyID, departmentID); existing != nil {
+		if departmentID == nil {
+			return nil, errors.New("company already has a default leave workflow")
+		}
+		return nil, errors.New("department already has a leave workflow")
+	}
+
+	steps, err := s.buildSteps(ctx, req.Steps)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow := &model.LeaveWorkflow{
+		CompanyID:    req.CompanyID,
+		DepartmentID: departmentID,
+		Name:         req.Name,
+		IsActive:     true,
+		Steps:        steps,
+	}
+
+	if err := s.workflowRepo.Create(ctx, workflow); err != nil {
+		return nil, errors.New("failed to create leave workflow")
+	}
+
+	created, err := s.workflowRepo.FindByID(ctx, workflow.ID)
+	if err != nil {
+		return nil, errors.New("failed to load leave workflow")
+	}
+
+	response := dto.ToLeaveWorkflowResponse(created)
+	return &response, nil
+}
+
+func (s *leaveWorkflowService) Update(ctx context.Context, id string, req dto.UpdateLeaveWorkflowRequest) (*dto.LeaveWorkflowResponse, error) {
+	workflow, err := s.workflowRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("leave workflow not found")
+	}
+
+	if req.Name != "" {
+		workflow.Name = req.Name
+	}
+	if req.IsActive != nil {
+		workflow.IsActive = *req.IsActive
+	}
+
+	var steps []model.LeaveWorkflowStep
+	if req.Steps != nil {
+		steps, err = s.buildSteps(ctx, req.Steps)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.workflowRepo.UpdateWithSteps(ctx, workflow, steps); err != nil {
+		return nil, errors.New("failed to update leave workflow")
+	}
+
+	updated, err := s.workflowRepo.FindByID(ctx, workflow.ID)
+	if err != nil {
+		return nil, errors.New("failed to load leave workflow")
+	}
+
+	response := dto.ToLeaveWorkflowResponse(updated)
+	return &response, nil
+}
+
+func (s *leaveWorkflowService) Delete(ctx context.Context, id string) error {
+	_, err := s.workflowRepo.FindByID(ctx, id)
+	if err != nil {
+		return errors.New("leave workflow not found")
+	}
+	return s.workflowRepo.Delete(ctx, id)
+}
+
+// buildSteps validates the requested steps and numbers them in order
+func (s *leaveWorkflowService) buildSteps(ctx context.Context, reqs []dto.LeaveWorkflowStepRequest) ([]model.LeaveWorkflowStep, error) {
+	if len(reqs) == 0 {
+		return nil, errors.New("leave workflow needs at least one step")
+	}
+
+	steps := make([]model.LeaveWorkflowStep, len(reqs))
+	for i, req := range reqs {
+		step := model.LeaveWorkflowStep{
+			StepOrder:    i + 1,
+			ApproverType: req.ApproverType,
+		}
+
+		switch req.ApproverType {
+		case model.LeaveApproverSupervisor, model.LeaveApproverDepartmentHead:
+		case model.LeaveApproverRole:
+			if req.ApproverRole != model.RoleHR && req.ApproverRole != model.RoleAdmin {
+				return nil, fmt.Errorf("step %d: approver role must be hr or admin", i+1)
+			}
+			step.ApproverRole = req.ApproverRole
+		case model.LeaveApproverUser:
+			if req.ApproverUserID == "" {
+				return nil, fmt.Errorf("step %d: approver user is required", i+1)
+			}
+			if _, err := s.userRepo.FindByID(ctx, req.ApproverUserID); err != nil {
+				return nil, fmt.Errorf("step %d: approver user not found", i+1)
+			}
+			userID := req.ApproverUserID
+			step.ApproverUserID = &userID
+		default:
+			return nil, fmt.Errorf("step %d: approver type must be supervisor, department_head, role or user", i+1)
+		}
+
+		steps[i] = step
+	}
+	return steps, nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+	"hris-backend/pkg/kafka"
+)
+
+const (
+	lifecycleScanInterval = 5 * time.Minute
+
+	// clockInMissingAfter is how long after the start of their shift an
+	// employee without attendance is reminded to clock in
+	clockInMissingAfter = 30 * time.Minute
+)
+
+// contractNoticeDays are the notice periods before the end of a contract,
+// shortest first. An employee is reminded once in each of them, however
+// often the scan runs.
+var contractNoticeDays = []int{1, 7, 30}
+
+// LifecycleScheduler publishes the lifecycle events that follow from the
+// passing of time rather than from a request: contracts about to end and
+// employees who have not clocked in. The events are identified by their
+// occurrence, so repeated scans, restarts and several instances scanning at
+// once publish each of them once.
+type LifecycleScheduler struct {
+	companyRepo repository.CompanyRepository
+	empRepo     repository.EmployeeRepository
+	holidayRepo repository.HolidayRepository
+	attRepo     repository.AttendanceRepository
+	outboxRepo  repository.OutboxRepository
+}
+
+func NewLifecycleScheduler(
+	companyRepo repository.CompanyRepository,
+	empRepo repository.EmployeeRepository,
+	holidayRepo repository.HolidayRepository,
+	attRepo repository.AttendanceRepository,
+	outboxRepo repository.OutboxRepository,
+) *LifecycleScheduler {
+	return &LifecycleScheduler{
+		companyRepo: companyRepo,
+		empRepo:     empRepo,
+		holidayRepo: holidayRepo,
+		attRepo:     attRepo,
+		outboxRepo:  outboxRepo,
+	}
+}
+
+// Start begins scanning in a background loop.
+func (s *LifecycleScheduler) Start() {
+	go func() {
+		for {
+			// The scans cover every company; they are not tenant scoped
+			ctx := context.Background()
+			now := time.Now()
+
+			if err := s.scanContractEndings(ctx, now); err != nil {
+				log.Printf("[lifecycle] scan contract endings: %v", err)
+			}
+			if err := s.scanMissingClockIns(ctx, now); err != nil {
+				log.Printf("[lifecycle] scan missing clock-ins: %v", err)
+			}
+
+			time.Sleep(lifecycleScanInterval)
+		}
+	}()
+}
+
+// scanContractEndings publishes a reminder for each contract ending within
+// the longest notice period, one per employee, end date and notice period
+func (s *LifecycleScheduler) scanContractEndings(ctx context.Context, now time.Time) error {
+	today := now.Truncate(24 * time.Hour)
+	longest := contractNoticeDays[len(contractNoticeDays)-1]
+
+	employees, err := s.empRepo.FindContractsEndingBetween(ctx, today, today.AddDate(0, 0, longest))
+	if err != nil {
+		return err
+	}
+
+	var events []model.OutboxEvent
+	for _, emp := range employees {
+		endDate := emp.ContractEndDate.Format("2006-01-02")
+		daysLeft := int(math.Round(emp.ContractEndDate.Sub(today).Hours() / 24))
+		notice := contractNotice(daysLeft)
+
+		occurrence := emp.ID + "/" + endDate + "/" + strconv.Itoa(notice) + "d"
+		event, err := kafka.NewOccurrenceOutboxEvent(ctx, kafka.EventContractEnding, occurrence, emp.ID, emp.CompanyID, kafka.ContractEndingPayload{
+			EmployeeID:      emp.ID,
+			EmployeeUserID:  emp.UserID,
+			EmployeeName:    emp.User.Name,
+			EmployeeNumber:  emp.EmployeeNumber,
+			CompanyID:       emp.CompanyID,
+			ContractEndDate: endDate,
+			DaysLeft:        daysLeft,
+		})
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	}
+	return s.outboxRepo.CreateIfAbsent(ctx, events)
+}
+
+// contractNotice returns the shortest notice period daysLeft falls into
+func contractNotice(daysLeft int) int {
+	for _, days := range contractNoticeDays {
+		if daysLeft <= days {
+			return days
+		}
+	}
+	return contractNoticeDays[len(contractNoticeDays)-1]
+}
+
+// scanMissingClockIns reminds the employees working today who have no
+// attendance record clockInMissingAfter into their shift, once per employee
+// and day. Employees on leave have the record their leave wrote.
+func (s *LifecycleScheduler) scanMissingClockIns(ctx context.Context, now time.Time) error {
+	today := now.Truncate(24 * time.Hour)
+
+	recorded, err := s.attRepo.FindEmployeeIDsByDate(ctx, today)
+	if err != nil {
+		return err
+	}
+	present := make(map[string]bool, len(recorded))
+	for _, id := range recorded {
+		present[id] = true
+	}
+
+	companies, err := s.companyRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	var events []model.OutboxEvent
+	for _, company := range companies {
+		employees, err := s.empRepo.FindActiveByCompanyID(ctx, company.ID, today, today)
+		if err != nil {
+			return err
+		}
+		holidays, err := s.holidayRepo.FindByCompanyIDAndDateRange(ctx, company.ID, today, today)
+		if err != nil {
+			return err
+		}
+
+		for _, emp := range employees {
+			if present[emp.ID] || !emp.Shift.IsActive {
+				continue
+			}
+			if !newWorkCalendar(&emp.Shift, holidays).isWorkingDay(today) {
+				continue
+			}
+			start, err := time.Parse("15:04", emp.Shift.StartTime)
+			if err != nil {
+				continue
+			}
+			startsAt := time.Date(now.Year(), now.Month(), now.Day(), start.Hour(), start.Minute(), 0, 0, now.Location())
+			if now.Before(startsAt.Add(clockInMissingAfter)) || !now.Before(shiftEndsAt(&emp.Shift, startsAt)) {
+				continue
+			}
+
+			date := today.Format("2006-01-02")
+			event, err := kafka.NewOccurrenceOutboxEvent(ctx, kafka.EventClockInMissing, emp.ID+"/"+date, emp.ID, emp.CompanyID, kafka.ClockInMissingPayload{
+				EmployeeID:     emp.ID,
+				EmployeeUserID: emp.UserID,
+				EmployeeName:   emp.User.Name,
+				Date:           date,
+				ShiftName:      emp.Shift.Name,
+				ShiftStart:     emp.Shift.StartTime,
+			})
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+	}
+	return s.outboxRepo.CreateIfAbsent(ctx, events)
+}
+
+// shiftEndsAt returns when a shift starting at startsAt ends. A shift ending
+// at or before its start time ends the next day; one with an unreadable end
+// time is taken to last a day.
+func shiftEndsAt(shift *model.Shift, startsAt time.Time) time.Time {
+	end, err := time.Parse("15:04", shift.EndTime)
+	if err != nil {
+		return startsAt.Add(24 * time.Hour)
+	}
+	endsAt := time.Date(startsAt.Year(), startsAt.Month(), startsAt.Day(), end.Hour(), end.Minute(), 0, 0, startsAt.Location())
+	if !endsAt.After(startsAt) {
+		endsAt = endsAt.AddDate(0, 0, 1)
+	}
+	return endsAt
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"hris-backend/internal/dto"
+	"hris-backend/internal/repository"
+)
+
+type LoginAuditService interface {
+	GetAllPaginated(ctx context.Context, page, limit int, userID, email, ipAddress, result, startDate, endDate string) (*dto.PaginatedLoginAttemptResponse, error)
+	Unlock(ctx context.Context, userID string) error
+}
+
+type loginAuditService struct {
+	attemptRepo repository.LoginAttemptRepository
+	userRepo    repository.UserRepository
+}
+
+func NewLoginAuditService(attemptRepo repository.LoginAttemptRepository, userRepo repository.UserRepository) LoginAuditService {
+	return &loginAuditService{
+		attemptRepo: attemptRepo,
+		userRepo:    userRepo,
+	}
+}
+
+func (s *loginAuditService) GetAllPaginated(ctx context.Context, page, limit int, userID, email, ipAddress, result, startDate, endDate string) (*dto.PaginatedLoginAttemptResponse, error) {
+	attempts, total, err := s.attemptRepo.FindAllPaginated(ctx, page, limit, userID, email, ipAddress, result, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	return &dto.PaginatedLoginAttemptResponse{
+		Data:       dto.ToLoginAttemptResponses(attempts),
+		Page:       page,
+		Limit:      limit,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Unlock lifts a lockout and clears the failed login count of a user
+func (s *loginAuditService) Unlock(ctx context.Context, userID string) error {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return errors.New("user not found")
+	}
+
+	if err := s.userRepo.ResetLoginFailures(ctx, userID); err != nil {
+		return errors.New("failed to unlock user")
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"hris-backend/internal/dto"
@@ -9,10 +10,10 @@ import (
 )
 
 type MenuAccessService interface {
-	GetUserMenuKeys(userID string) (*dto.UserMenuKeysResponse, error)
-	GetAll() ([]dto.MenuAccessResponse, error)
-	SetUserMenuAccess(req dto.SetMenuAccessRequest) error
-	DeleteUserMenuAccess(userID string) error
+	GetUserMenuKeys(ctx context.Context, userID string) (*dto.UserMenuKeysResponse, error)
+	GetAll(ctx context.Context) ([]dto.MenuAccessResponse, error)
+	SetUserMenuAccess(ctx context.Context, req dto.SetMenuAccessRequest) error
+	DeleteUserMenuAccess(ctx context.Context, userID string) error
 }
 
 // roleDefaultMenuKeys defines the default menu access per role,
@@ -53,8 +54,8 @@ func NewMenuAccessService(menuRepo repository.MenuAccessRepository, userRepo rep
 	}
 }
 
-func (s *menuAccessService) GetUserMenuKeys(userID string) (*dto.UserMenuKeysResponse, error) {
-	accesses, err := s.menuRepo.FindByUserID(userID)
+func (s *menuAccessService) GetUserMenuKeys(ctx context.Context, userID string) (*dto.UserMenuKeysResponse, error) {
+	accesses, err := s.menuRepo.FindByUserID(ctx, userID)
 	if err != nil {
 		return nil, errors.New("failed to fetch menu access")
 	}
@@ -68,7 +69,7 @@ func (s *menuAccessService) GetUserMenuKeys(userID string) (*dto.UserMenuKeysRes
 	}
 
 	// No custom config — return role-based defaults
-	user, err := s.userRepo.FindByID(userID)
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return nil, errors.New("failed to fetch user")
 	}
@@ -80,8 +81,8 @@ func (s *menuAccessService) GetUserMenuKeys(userID string) (*dto.UserMenuKeysRes
 	return &dto.UserMenuKeysResponse{MenuKeys: defaults}, nil
 }
 
-func (s *menuAccessService) GetAll() ([]dto.MenuAccessResponse, error) {
-	accesses, err := s.menuRepo.FindAllWithUsers()
+func (s *menuAccessService) GetAll(ctx context.Context) ([]dto.MenuAccessResponse, error) {
+	accesses, err := s.menuRepo.FindAllWithUsers(ctx)
 	if err != nil {
 		return nil, errors.New("failed to fetch menu access")
 	}
@@ -107,9 +108,9 @@ func (s *menuAccessService) GetAll() ([]dto.MenuAccessResponse, error) {
 	return result, nil
 }
 
-func (s *menuAccessService) SetUserMenuAccess(req dto.SetMenuAccessRequest) error {
+func (s *menuAccessService) SetUserMenuAccess(ctx context.Context, req dto.SetMenuAccessRequest) error {
 	// Validate user exists
-	_, err := s.userRepo.FindByID(req.UserID)
+	_, err := s.userRepo.FindByID(ctx, req.UserID)
 	if err != nil {
 		return errors.New("user not found")
 	}
@@ -121,9 +122,9 @@ func (s *menuAccessService) SetUserMenuAccess(req dto.SetMenuAccessRequest) erro
 		}
 	}
 
-	return s.menuRepo.ReplaceForUser(req.UserID, req.MenuKeys)
+	return s.menuRepo.ReplaceForUser(ctx, req.UserID, req.MenuKeys)
 }
 
-func (s *menuAccessService) DeleteUserMenuAccess(userID string) error {
-	return s.menuRepo.DeleteByUserID(userID)
+func (s *menuAccessService) DeleteUserMenuAccess(ctx context.Context, userID string) error {
+	return s.menuRepo.DeleteByUserID(ctx, userID)
 }
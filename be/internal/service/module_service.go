@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"time"
@@ -9,22 +10,23 @@ import (
 	"hris-backend/internal/model"
 	"hris-backend/internal/modules"
 	"hris-backend/internal/repository"
+	"hris-backend/pkg/kafka"
 )
 
 type ModuleService interface {
 	// Catalog
-	ListAllModules() ([]dto.ModuleResponse, error)
+	ListAllModules(ctx context.Context) ([]dto.ModuleResponse, error)
 
 	// Per-company config
-	ListForCompany(companyID string) ([]dto.CompanyModuleResponse, error)
-	SetForCompany(companyID, moduleKey, actorUserID string, req dto.SetCompanyModuleRequest) (*dto.CompanyModuleResponse, error)
+	ListForCompany(ctx context.Context, companyID string) ([]dto.CompanyModuleResponse, error)
+	SetForCompany(ctx context.Context, companyID, moduleKey, actorUserID string, req dto.SetCompanyModuleRequest) (*dto.CompanyModuleResponse, error)
 
 	// Runtime checks
-	EnabledKeysForCompany(companyID string) ([]string, error)
-	IsEnabled(companyID, moduleKey string) (bool, error)
+	EnabledKeysForCompany(ctx context.Context, companyID string) ([]string, error)
+	IsEnabled(ctx context.Context, companyID, moduleKey string) (bool, error)
 
 	// Seeding
-	SyncRegistry() error
+	SyncRegistry(ctx context.Context) error
 }
 
 type moduleService struct {
@@ -37,23 +39,23 @@ func NewModuleService(modRepo repository.ModuleRepository, compModRepo repositor
 	return &moduleService{modRepo, compModRepo, companyRepo}
 }
 
-func (s *moduleService) ListAllModules() ([]dto.ModuleResponse, error) {
-	ms, err := s.modRepo.FindAll()
+func (s *moduleService) ListAllModules(ctx context.Context) ([]dto.ModuleResponse, error) {
+	ms, err := s.modRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToModuleResponses(ms), nil
 }
 
-func (s *moduleService) ListForCompany(companyID string) ([]dto.CompanyModuleResponse, error) {
+func (s *moduleService) ListForCompany(ctx context.Context, companyID string) ([]dto.CompanyModuleResponse, error) {
 	// Start with every module in the catalog so the UI can render the full list
 	// even for companies that have never toggled anything.
-	all, err := s.modRepo.FindAll()
+	all, err := s.modRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	existing, err := s.compModRepo.FindByCompanyID(companyID)
+	existing, err := s.compModRepo.FindByCompanyID(ctx, companyID)
 	if err != nil {
 		return nil, err
 	}
@@ -86,14 +88,14 @@ func (s *moduleService) ListForCompany(companyID string) ([]dto.CompanyModuleRes
 	return out, nil
 }
 
-func (s *moduleService) SetForCompany(companyID, moduleKey, actorUserID string, req dto.SetCompanyModuleRequest) (*dto.CompanyModuleResponse, error) {
+func (s *moduleService) SetForCompany(ctx context.Context, companyID, moduleKey, actorUserID string, req dto.SetCompanyModuleRequest) (*dto.CompanyModuleResponse, error) {
 	// Validate company exists
-	if _, err := s.companyRepo.FindByID(companyID); err != nil {
+	if _, err := s.companyRepo.FindByID(ctx, companyID); err != nil {
 		return nil, errors.New("company not found")
 	}
 
 	// Validate module exists in registry / db
-	m, err := s.modRepo.FindByKey(moduleKey)
+	m, err := s.modRepo.FindByKey(ctx, moduleKey)
 	if err != nil {
 		return nil, errors.New("module not found")
 	}
@@ -103,7 +105,7 @@ func (s *moduleService) SetForCompany(companyID, moduleKey, actorUserID string,
 
 	// Enforce dependencies when enabling
 	if req.Enabled && m.DependsOn != "" {
-		enabled, err := s.compModRepo.EnabledKeysForCompany(companyID)
+		enabled, err := s.compModRepo.EnabledKeysForCompany(ctx, companyID)
 		if err != nil {
 			return nil, err
 		}
@@ -126,18 +128,44 @@ func (s *moduleService) SetForCompany(companyID, moduleKey, actorUserID string,
 		}
 	}
 
+	existing, ferr := s.compModRepo.FindByCompanyAndKey(ctx, companyID, moduleKey)
+	if ferr != nil {
+		existing = nil
+	}
+
 	// Config is a jsonb column; an empty string is not valid JSON and Postgres
 	// will reject it with SQLSTATE 22P02. When the caller omits config, keep
 	// whatever is already stored (or default to an empty object on insert).
 	cfg := strings.TrimSpace(req.Config)
 	if cfg == "" {
-		if existing, ferr := s.compModRepo.FindByCompanyAndKey(companyID, moduleKey); ferr == nil && existing != nil && strings.TrimSpace(existing.Config) != "" {
+		if existing != nil && strings.TrimSpace(existing.Config) != "" {
 			cfg = existing.Config
 		} else {
 			cfg = "{}"
 		}
 	}
 
+	// Announce the change when the module is switched on or off; core
+	// modules are always on
+	var events []model.OutboxEvent
+	wasEnabled := m.IsCore || (existing != nil && existing.Enabled)
+	if req.Enabled != wasEnabled {
+		eventType := kafka.EventModuleDisabled
+		if req.Enabled {
+			eventType = kafka.EventModuleEnabled
+		}
+		event, err := kafka.NewOutboxEvent(ctx, eventType, companyID, companyID, kafka.ModuleToggledPayload{
+			CompanyID:   companyID,
+			ModuleKey:   m.Key,
+			ModuleName:  m.Name,
+			ActorUserID: actorUserID,
+		})
+		if err != nil {
+			return nil, errors.New("failed to update module")
+		}
+		events = append(events, event)
+	}
+
 	now := time.Now()
 	cm := &model.CompanyModule{
 		CompanyID: companyID,
@@ -149,12 +177,12 @@ func (s *moduleService) SetForCompany(companyID, moduleKey, actorUserID string,
 	if req.Enabled {
 		cm.EnabledAt = &now
 	}
-	if err := s.compModRepo.Upsert(cm); err != nil {
+	if err := s.compModRepo.Upsert(ctx, cm, events); err != nil {
 		return nil, err
 	}
 
 	// Reload with module preloaded
-	saved, err := s.compModRepo.FindByCompanyAndKey(companyID, moduleKey)
+	saved, err := s.compModRepo.FindByCompanyAndKey(ctx, companyID, moduleKey)
 	if err != nil {
 		return nil, err
 	}
@@ -162,8 +190,8 @@ func (s *moduleService) SetForCompany(companyID, moduleKey, actorUserID string,
 	return &resp, nil
 }
 
-func (s *moduleService) EnabledKeysForCompany(companyID string) ([]string, error) {
-	keys, err := s.compModRepo.EnabledKeysForCompany(companyID)
+func (s *moduleService) EnabledKeysForCompany(ctx context.Context, companyID string) ([]string, error) {
+	keys, err := s.compModRepo.EnabledKeysForCompany(ctx, companyID)
 	if err != nil {
 		return nil, err
 	}
@@ -181,11 +209,11 @@ func (s *moduleService) EnabledKeysForCompany(companyID string) ([]string, error
 	return keys, nil
 }
 
-func (s *moduleService) IsEnabled(companyID, moduleKey string) (bool, error) {
+func (s *moduleService) IsEnabled(ctx context.Context, companyID, moduleKey string) (bool, error) {
 	if modules.IsCore(moduleKey) {
 		return true, nil
 	}
-	row, err := s.compModRepo.FindByCompanyAndKey(companyID, moduleKey)
+	row, err := s.compModRepo.FindByCompanyAndKey(ctx, companyID, moduleKey)
 	if err != nil {
 		// No row = disabled (not an error)
 		return false, nil
@@ -195,7 +223,7 @@ func (s *moduleService) IsEnabled(companyID, moduleKey string) (bool, error) {
 
 // SyncRegistry pushes the code-defined registry into the DB so every registered
 // module has a row. Safe to call on every startup.
-func (s *moduleService) SyncRegistry() error {
+func (s *moduleService) SyncRegistry(ctx context.Context) error {
 	rows := make([]model.Module, 0, len(modules.Registry))
 	for _, m := range modules.Registry {
 		rows = append(rows, model.Module{
@@ -207,5 +235,5 @@ func (s *moduleService) SyncRegistry() error {
 			IsCore:      m.IsCore,
 		})
 	}
-	return s.modRepo.UpsertMany(rows)
+	return s.modRepo.UpsertMany(ctx, rows)
 }
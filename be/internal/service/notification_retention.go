@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"hris-backend/internal/repository"
+)
+
+const (
+	retentionScanInterval = time.Hour
+	retentionBatchSize    = 1000
+)
+
+// NotificationRetention purges the read notifications older than the
+// retention period, and the deleted ones once it has passed since their
+// deletion. Unread notifications are kept however old they are.
+type NotificationRetention struct {
+	notifRepo repository.NotificationRepository
+	retention time.Duration
+}
+
+func NewNotificationRetention(notifRepo repository.NotificationRepository, retentionDays int) *NotificationRetention {
+	return &NotificationRetention{
+		notifRepo: notifRepo,
+		retention: time.Duration(retentionDays) * 24 * time.Hour,
+	}
+}
+
+// Start begins purging in a background loop. A retention below one day
+// would purge every read notification, so it disables purging instead.
+func (r *NotificationRetention) Start() {
+	if r.retention < 24*time.Hour {
+		log.Printf("[retention] notification retention below one day, not purging notifications")
+		return
+	}
+	go func() {
+		for {
+			// Notifications of every company are purged; this is not tenant scoped
+			if err := r.purge(context.Background(), time.Now().Add(-r.retention)); err != nil {
+				log.Printf("[retention] purge notifications: %v", err)
+			}
+			time.Sleep(retentionScanInterval)
+		}
+	}()
+}
+
+// purge removes the notifications due before the given time in batches, so
+// a large backlog does not hold one long transaction
+func (r *NotificationRetention) purge(ctx context.Context, before time.Time) error {
+	var total int64
+	for {
+		n, err := r.notifRepo.PurgeBefore(ctx, before, retentionBatchSize)
+		if err != nil {
+			return err
+		}
+		total += n
+		if n < retentionBatchSize {
+			break
+		}
+	}
+	if total > 0 {
+		log.Printf("[retention] purged %d notifications", total)
+	}
+	return nil
+}
@@ -1,41 +1,236 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"hris-backend/internal/dto"
+	"hris-backend/internal/model"
 	"hris-backend/internal/repository"
+	"hris-backend/pkg/kafka"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxMissedNotifications bounds how many notifications a resumed stream
+	// replays; the client lists older ones
+	maxMissedNotifications = 100
+
+	// maxNotificationPage bounds the notifications listed at once
+	maxNotificationPage = 100
+
+	// maxNotificationBulk bounds the notifications a bulk action applies to
+	maxNotificationBulk = 100
 )
 
 type NotificationService interface {
-	GetByUserID(userID string, limit int) ([]dto.NotificationResponse, error)
-	GetUnreadCount(userID string) (int64, error)
-	MarkAsRead(id string, userID string) error
-	MarkAllAsRead(userID string) error
+	GetPage(ctx context.Context, userID, cursor string, limit int, notifType, refType, isRead, archived string) (*dto.NotificationPageResponse, error)
+	GetUnreadCount(ctx context.Context, userID string) (int64, error)
+	GetMissed(ctx context.Context, userID, lastSeenID string) ([]dto.NotificationResponse, error)
+	MarkAsRead(ctx context.Context, id string, userID string) error
+	MarkAllAsRead(ctx context.Context, userID string) error
+	SetArchived(ctx context.Context, userID string, req dto.NotificationIDsRequest, archived bool) (*dto.NotificationBulkResponse, error)
+	Delete(ctx context.Context, userID string, req dto.NotificationIDsRequest) (*dto.NotificationBulkResponse, error)
+	GetPreferences(ctx context.Context, userID string) ([]dto.NotificationPreferenceResponse, error)
+	UpdatePreferences(ctx context.Context, userID string, req dto.UpdateNotificationPreferencesRequest) ([]dto.NotificationPreferenceResponse, error)
 }
 
 type notificationService struct {
-	repo repository.NotificationRepository
+	repo     repository.NotificationRepository
+	prefRepo repository.NotificationPreferenceRepository
 }
 
-func NewNotificationService(repo repository.NotificationRepository) NotificationService {
-	return &notificationService{repo: repo}
+func NewNotificationService(repo repository.NotificationRepository, prefRepo repository.NotificationPreferenceRepository) NotificationService {
+	return &notificationService{repo: repo, prefRepo: prefRepo}
 }
 
-func (s *notificationService) GetByUserID(userID string, limit int) ([]dto.NotificationResponse, error) {
-	notifications, err := s.repo.FindByUserID(userID, limit)
+// GetPage lists the notifications of a user newest first, from the inbox or,
+// when archived is "true", from the archive. A page ends at limit; the next
+// one starts from its cursor.
+func (s *notificationService) GetPage(ctx context.Context, userID, cursor string, limit int, notifType, refType, isRead, archived string) (*dto.NotificationPageResponse, error) {
+	if cursor != "" {
+		if _, err := uuid.Parse(cursor); err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+	}
+	if limit < 1 || limit > maxNotificationPage {
+		limit = maxNotificationPage
+	}
+	if notifType != "" && !validNotificationType(model.NotificationType(notifType)) {
+		return nil, errors.New("type must be info, success, warning or error")
+	}
+	readFilter, err := parseOptionalBool(isRead, "is_read")
+	if err != nil {
+		return nil, err
+	}
+	archivedFilter, err := parseOptionalBool(archived, "archived")
 	if err != nil {
 		return nil, err
 	}
+
+	// One more than the page tells whether there is a next one
+	notifications, err := s.repo.FindPage(ctx, userID, cursor, limit+1, notifType, refType, readFilter, archivedFilter != nil && *archivedFilter)
+	if err != nil {
+		return nil, errors.New("failed to fetch notifications")
+	}
+
+	page := &dto.NotificationPageResponse{}
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+		page.NextCursor = notifications[limit-1].ID
+	}
+	page.Items = dto.ToNotificationResponses(notifications)
+	return page, nil
+}
+
+func (s *notificationService) GetUnreadCount(ctx context.Context, userID string) (int64, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+// GetMissed returns the notifications created after the one a client saw
+// last, so a reconnecting stream resumes where it left off
+func (s *notificationService) GetMissed(ctx context.Context, userID, lastSeenID string) ([]dto.NotificationResponse, error) {
+	if lastSeenID == "" {
+		return nil, nil
+	}
+	if _, err := uuid.Parse(lastSeenID); err != nil {
+		return nil, errors.New("invalid last event ID")
+	}
+
+	notifications, err := s.repo.FindAfter(ctx, userID, lastSeenID, maxMissedNotifications)
+	if err != nil {
+		return nil, errors.New("failed to fetch missed notifications")
+	}
 	return dto.ToNotificationResponses(notifications), nil
 }
 
-func (s *notificationService) GetUnreadCount(userID string) (int64, error) {
-	return s.repo.CountUnread(userID)
+func (s *notificationService) MarkAsRead(ctx context.Context, id string, userID string) error {
+	return s.repo.MarkAsRead(ctx, id, userID)
+}
+
+func (s *notificationService) MarkAllAsRead(ctx context.Context, userID string) error {
+	return s.repo.MarkAllAsRead(ctx, userID)
+}
+
+// SetArchived moves notifications of the user to the archive, or back to
+// the inbox when archived is false
+func (s *notificationService) SetArchived(ctx context.Context, userID string, req dto.NotificationIDsRequest, archived bool) (*dto.NotificationBulkResponse, error) {
+	if err := validateNotificationIDs(req.IDs); err != nil {
+		return nil, err
+	}
+	count, err := s.repo.SetArchived(ctx, userID, req.IDs, archived)
+	if err != nil {
+		return nil, errors.New("failed to archive notifications")
+	}
+	return &dto.NotificationBulkResponse{Count: count}, nil
+}
+
+func (s *notificationService) Delete(ctx context.Context, userID string, req dto.NotificationIDsRequest) (*dto.NotificationBulkResponse, error) {
+	if err := validateNotificationIDs(req.IDs); err != nil {
+		return nil, err
+	}
+	count, err := s.repo.Delete(ctx, userID, req.IDs)
+	if err != nil {
+		return nil, errors.New("failed to delete notifications")
+	}
+	return &dto.NotificationBulkResponse{Count: count}, nil
 }
 
-func (s *notificationService) MarkAsRead(id string, userID string) error {
-	return s.repo.MarkAsRead(id, userID)
+// GetPreferences returns the mode of every event type users choose one for,
+// the default for those the user did not choose
+func (s *notificationService) GetPreferences(ctx context.Context, userID string) ([]dto.NotificationPreferenceResponse, error) {
+	prefs, err := s.prefRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to fetch notification preferences")
+	}
+	chosen := make(map[string]model.NotificationMode, len(prefs))
+	for _, p := range prefs {
+		chosen[p.EventType] = p.Mode
+	}
+
+	responses := make([]dto.NotificationPreferenceResponse, len(kafka.PreferenceEventTypes))
+	for i, eventType := range kafka.PreferenceEventTypes {
+		mode, ok := chosen[string(eventType)]
+		if !ok {
+			mode = model.NotificationModeEmail
+		}
+		responses[i] = dto.NotificationPreferenceResponse{EventType: string(eventType), Mode: mode}
+	}
+	return responses, nil
 }
 
-func (s *notificationService) MarkAllAsRead(userID string) error {
-	return s.repo.MarkAllAsRead(userID)
+func (s *notificationService) UpdatePreferences(ctx context.Context, userID string, req dto.UpdateNotificationPreferencesRequest) ([]dto.NotificationPreferenceResponse, error) {
+	prefs := make([]model.NotificationPreference, 0, len(req.Preferences))
+	seen := make(map[string]bool)
+	for _, p := range req.Preferences {
+		if !kafka.HasPreference(kafka.EventType(p.EventType)) {
+			return nil, errors.New("unknown event type: " + p.EventType)
+		}
+		if !validNotificationMode(p.Mode) {
+			return nil, errors.New("mode must be in_app, email, digest or off")
+		}
+		if seen[p.EventType] {
+			return nil, errors.New("duplicate event type: " + p.EventType)
+		}
+		seen[p.EventType] = true
+		prefs = append(prefs, model.NotificationPreference{
+			UserID:    userID,
+			EventType: p.EventType,
+			Mode:      p.Mode,
+		})
+	}
+
+	if err := s.prefRepo.Upsert(ctx, prefs); err != nil {
+		return nil, errors.New("failed to update notification preferences")
+	}
+	return s.GetPreferences(ctx, userID)
+}
+
+func validNotificationMode(mode model.NotificationMode) bool {
+	switch mode {
+	case model.NotificationModeInApp, model.NotificationModeEmail, model.NotificationModeDigest, model.NotificationModeOff:
+		return true
+	}
+	return false
+}
+
+func validNotificationType(t model.NotificationType) bool {
+	switch t {
+	case model.NotificationTypeInfo, model.NotificationTypeSuccess, model.NotificationTypeWarning, model.NotificationTypeError:
+		return true
+	}
+	return false
+}
+
+func validateNotificationIDs(ids []string) error {
+	if len(ids) == 0 {
+		return errors.New("ids is required")
+	}
+	if len(ids) > maxNotificationBulk {
+		return fmt.Errorf("at most %d notifications at a time", maxNotificationBulk)
+	}
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return errors.New("invalid notification ID: " + id)
+		}
+	}
+	return nil
+}
+
+// parseOptionalBool parses the value of a true/false query parameter,
+// returning nil when it is not set
+func parseOptionalBool(value, name string) (*bool, error) {
+	switch value {
+	case "":
+		return nil, nil
+	case "true":
+		b := true
+		return &b, nil
+	case "false":
+		b := false
+		return &b, nil
+	}
+	return nil, errors.New(name + " must be true or false")
 }
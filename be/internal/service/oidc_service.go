@@ -0,0 +1,539 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"hris-backend/config"
+	"hris-backend/internal/dto"
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+	"hris-backend/pkg/hash"
+	"hris-backend/pkg/oidc"
+
+	"gorm.io/gorm"
+)
+
+// oidcStateExpiry bounds how long a user may take at the identity provider
+const oidcStateExpiry = 10 * time.Minute
+
+const defaultOIDCScopes = "openid email profile"
+
+var oidcSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+var errNoSSOAccount = errors.New("no account for this email in the provider's company")
+
+type OIDCService interface {
+	GetAll(ctx context.Context) ([]dto.OIDCProviderResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.OIDCProviderResponse, error)
+	Create(ctx context.Context, req dto.CreateOIDCProviderRequest) (*dto.OIDCProviderResponse, error)
+	Update(ctx context.Context, id string, req dto.UpdateOIDCProviderRequest) (*dto.OIDCProviderResponse, error)
+	Delete(ctx context.Context, id string) error
+	GetLoginOptions(ctx context.Context) ([]dto.OIDCLoginOptionResponse, error)
+	StartLogin(ctx context.Context, slug, redirectPath string) (string, error)
+	StartLink(ctx context.Context, userID, slug, redirectPath string) (string, error)
+	CompleteLogin(ctx context.Context, slug, code, state string) (*dto.OIDCLogin, error)
+}
+
+type oidcService struct {
+	providerRepo    repository.OIDCProviderRepository
+	userRepo        repository.UserRepository
+	empRepo         repository.EmployeeRepository
+	userCompanyRepo repository.UserCompanyRepository
+	companyRepo     repository.CompanyRepository
+	client          *oidc.Client
+	cfg             *config.Config
+}
+
+func NewOIDCService(
+	providerRepo repository.OIDCProviderRepository,
+	userRepo repository.UserRepository,
+	empRepo repository.EmployeeRepository,
+	userCompanyRepo repository.UserCompanyRepository,
+	companyRepo repository.CompanyRepository,
+	client *oidc.Client,
+	cfg *config.Config,
+) OIDCService {
+	return &oidcService{
+		providerRepo:    providerRepo,
+		userRepo:        userRepo,
+		empRepo:         empRepo,
+		userCompanyRepo: userCompanyRepo,
+		companyRepo:     companyRepo,
+		client:          client,
+		cfg:             cfg,
+	}
+}
+
+func (s *oidcService) GetAll(ctx context.Context) ([]dto.OIDCProviderResponse, error) {
+	providers, err := s.providerRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToOIDCProviderResponses(providers), nil
+}
+
+func (s *oidcService) GetByID(ctx context.Context, id string) (*dto.OIDCProviderResponse, error) {
+	provider, err := s.providerRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("sso provider not found")
+	}
+	response := dto.ToOIDCProviderResponse(provider)
+	return &response, nil
+}
+
+func (s *oidcService) Create(ctx context.Context, req dto.CreateOIDCProviderRequest) (*dto.OIDCProviderResponse, error) {
+	if _, err := s.companyRepo.FindByID(ctx, req.CompanyID); err != nil {
+		return nil, errors.New("company not found")
+	}
+
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !oidcSlugPattern.MatchString(slug) || len(slug) > 50 {
+		return nil, errors.New("slug must be lowercase letters, digits and dashes")
+	}
+	if existing, _ := s.providerRepo.FindBySlug(ctx, slug); existing != nil {
+		return nil, errors.New("slug is already in use")
+	}
+
+	if err := s.validateIssuerURL(req.IssuerURL); err != nil {
+		return nil, err
+	}
+
+	role, err := provisionRole(req.DefaultRole)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := req.Scopes
+	if scopes == "" {
+		scopes = defaultOIDCScopes
+	}
+
+	provider := &model.OIDCProvider{
+		CompanyID:      req.CompanyID,
+		Slug:           slug,
+		Name:           req.Name,
+		IssuerURL:      req.IssuerURL,
+		ClientID:       req.ClientID,
+		ClientSecret:   req.ClientSecret,
+		Scopes:         scopes,
+		AllowedDomains: normalizeDomains(req.AllowedDomains),
+		AutoProvision:  req.AutoProvision,
+		DefaultRole:    role,
+		IsActive:       true,
+	}
+
+	if err := s.providerRepo.Create(ctx, provider); err != nil {
+		return nil, errors.New("failed to create sso provider")
+	}
+
+	return s.GetByID(ctx, provider.ID)
+}
+
+func (s *oidcService) Update(ctx context.Context, id string, req dto.UpdateOIDCProviderRequest) (*dto.OIDCProviderResponse, error) {
+	provider, err := s.providerRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("sso provider not found")
+	}
+
+	if req.Name != "" {
+		provider.Name = req.Name
+	}
+	if req.IssuerURL != "" {
+		if err := s.validateIssuerURL(req.IssuerURL); err != nil {
+			return nil, err
+		}
+		provider.IssuerURL = req.IssuerURL
+	}
+	if req.ClientID != "" {
+		provider.ClientID = req.ClientID
+	}
+	if req.ClientSecret != nil {
+		provider.ClientSecret = *req.ClientSecret
+	}
+	if req.Scopes != "" {
+		provider.Scopes = req.Scopes
+	}
+	if req.AllowedDomains != nil {
+		provider.AllowedDomains = normalizeDomains(*req.AllowedDomains)
+	}
+	if req.AutoProvision != nil {
+		provider.AutoProvision = *req.AutoProvision
+	}
+	if req.DefaultRole != "" {
+		role, err := provisionRole(req.DefaultRole)
+		if err != nil {
+			return nil, err
+		}
+		provider.DefaultRole = role
+	}
+	if req.IsActive != nil {
+		provider.IsActive = *req.IsActive
+	}
+
+	if err := s.providerRepo.Update(ctx, provider); err != nil {
+		return nil, errors.New("failed to update sso provider")
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *oidcService) Delete(ctx context.Context, id string) error {
+	if _, err := s.providerRepo.FindByID(ctx, id); err != nil {
+		return errors.New("sso provider not found")
+	}
+	return s.providerRepo.Delete(ctx, id)
+}
+
+func (s *oidcService) GetLoginOptions(ctx context.Context) ([]dto.OIDCLoginOptionResponse, error) {
+	providers, err := s.providerRepo.FindActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToOIDCLoginOptionResponses(providers), nil
+}
+
+// StartLogin opens a login at the provider and returns the URL to send the
+// browser to. The state, nonce and PKCE verifier stay on the server until the
+// callback; redirectPath is the frontend page to return to.
+func (s *oidcService) StartLogin(ctx context.Context, slug, redirectPath string) (string, error) {
+	return s.start(ctx, slug, redirectPath, nil)
+}
+
+// StartLink opens a login at the provider that links the identity it
+// confirms to the signed-in user, for users whose account cannot be linked
+// by email on their first SSO login
+func (s *oidcService) StartLink(ctx context.Context, userID, slug, redirectPath string) (string, error) {
+	return s.start(ctx, slug, redirectPath, &userID)
+}
+
+func (s *oidcService) start(ctx context.Context, slug, redirectPath string, linkUserID *string) (string, error) {
+	provider, err := s.providerRepo.FindBySlug(ctx, slug)
+	if err != nil || !provider.IsActive {
+		return "", errors.New("sso provider not found")
+	}
+
+	discovered, err := s.client.Discover(ctx, provider.IssuerURL)
+	if err != nil {
+		return "", errors.New("identity provider is unavailable")
+	}
+
+	state, err := hash.GenerateToken(32)
+	if err != nil {
+		return "", errors.New("failed to start sso login")
+	}
+	nonce, err := hash.GenerateToken(32)
+	if err != nil {
+		return "", errors.New("failed to start sso login")
+	}
+	verifier, challenge, err := oidc.NewPKCE()
+	if err != nil {
+		return "", errors.New("failed to start sso login")
+	}
+
+	// Logins abandoned at the provider are never called back
+	s.providerRepo.DeleteExpiredStates(ctx)
+
+	loginState := &model.OIDCLoginState{
+		StateHash:    hash.HashToken(state),
+		ProviderID:   provider.ID,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		RedirectPath: safeRedirectPath(redirectPath),
+		LinkUserID:   linkUserID,
+		ExpiresAt:    time.Now().Add(oidcStateExpiry),
+	}
+	if err := s.providerRepo.CreateState(ctx, loginState); err != nil {
+		return "", errors.New("failed to start sso login")
+	}
+
+	return discovered.AuthCodeURL(s.clientConfig(provider), state, nonce, challenge), nil
+}
+
+// CompleteLogin handles the provider's callback: it consumes the login state,
+// trades the code for tokens, validates the ID token and resolves the account
+// linked to the identity, or links one; see resolveUser. A link started with
+// StartLink links the identity to the user who started it instead.
+func (s *oidcService) CompleteLogin(ctx context.Context, slug, code, state string) (*dto.OIDCLogin, error) {
+	loginState, err := s.providerRepo.ConsumeState(ctx, hash.HashToken(state))
+	if err != nil || time.Now().After(loginState.ExpiresAt) {
+		return nil, errors.New("invalid or expired sso login")
+	}
+
+	provider, err := s.providerRepo.FindByID(ctx, loginState.ProviderID)
+	if err != nil || provider.Slug != slug || !provider.IsActive {
+		return nil, errors.New("invalid or expired sso login")
+	}
+
+	discovered, err := s.client.Discover(ctx, provider.IssuerURL)
+	if err != nil {
+		return nil, errors.New("identity provider is unavailable")
+	}
+
+	token, err := s.client.Exchange(ctx, discovered, s.clientConfig(provider), code, loginState.CodeVerifier)
+	if err != nil {
+		return nil, errors.New("identity provider rejected the login")
+	}
+
+	claims, err := s.client.VerifyIDToken(ctx, discovered, provider.ClientID, token.IDToken, loginState.Nonce)
+	if err != nil {
+		return nil, errors.New("invalid identity token")
+	}
+	if (claims.Email == "" || claims.EmailVerified == nil) && token.AccessToken != "" {
+		if info, err := s.client.UserInfo(ctx, discovered, token.AccessToken); err == nil && info.Subject == claims.Subject {
+			claims.Email, claims.EmailVerified = info.Email, info.EmailVerified
+			if claims.Name == "" {
+				claims.Name = info.Name
+			}
+		}
+	}
+
+	email := strings.TrimSpace(claims.Email)
+	if email == "" {
+		return nil, errors.New("identity provider did not share an email address")
+	}
+	if claims.EmailVerified == nil || !*claims.EmailVerified {
+		return nil, errors.New("email address is not verified at the identity provider")
+	}
+	if !domainAllowed(provider.AllowedDomains, email) {
+		return nil, errors.New("email domain is not allowed for this sso provider")
+	}
+
+	if loginState.LinkUserID != nil {
+		if err := s.linkUser(ctx, provider, *loginState.LinkUserID, claims.Subject); err != nil {
+			return nil, err
+		}
+		return &dto.OIDCLogin{UserID: *loginState.LinkUserID, RedirectPath: loginState.RedirectPath, Linked: true}, nil
+	}
+
+	user, err := s.resolveUser(ctx, provider, claims.Subject, email, claims.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.OIDCLogin{UserID: user.ID, RedirectPath: loginState.RedirectPath}, nil
+}
+
+// resolveUser finds the account linked to the subject at the provider. On
+// the first login an account is linked by email, but only one that belongs
+// to the provider's company alone: a company's provider must not speak for
+// users of other companies, who link it themselves with StartLink. Without
+// an account, auto-provisioning providers create one.
+func (s *oidcService) resolveUser(ctx context.Context, provider *model.OIDCProvider, subject, email, name string) (*model.User, error) {
+	identity, err := s.providerRepo.FindIdentity(ctx, provider.ID, subject)
+	if err == nil {
+		user, err := s.userRepo.FindByID(ctx, identity.UserID)
+		if err != nil || !s.mayUseProvider(ctx, user, provider) {
+			return nil, errNoSSOAccount
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("failed to look up sso account")
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err == nil {
+		if !s.mayUseProvider(ctx, user, provider) {
+			return nil, errNoSSOAccount
+		}
+		if !s.onlyInCompany(ctx, user.ID, provider.CompanyID) {
+			return nil, errors.New("this account belongs to several companies: sign in with your password and link the sso provider first")
+		}
+		if err := s.createIdentity(ctx, provider, user.ID, subject); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	if !provider.AutoProvision {
+		return nil, errNoSSOAccount
+	}
+
+	// The account signs in through the provider until someone resets its
+	// password
+	hashedPassword, err := unusablePassword()
+	if err != nil {
+		return nil, errors.New("failed to create user")
+	}
+
+	if name == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+	user = &model.User{
+		Name:     name,
+		Email:    email,
+		Password: hashedPassword,
+		Role:     provider.DefaultRole,
+		IsActive: true,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, errors.New("failed to create user")
+	}
+	if err := s.userCompanyRepo.ReplaceForUser(ctx, user.ID, []string{provider.CompanyID}); err != nil {
+		return nil, errors.New("failed to bind user to company")
+	}
+	if err := s.createIdentity(ctx, provider, user.ID, subject); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// linkUser links the subject at the provider to a user who started a link
+// while signed in
+func (s *oidcService) linkUser(ctx context.Context, provider *model.OIDCProvider, userID, subject string) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil || !s.mayUseProvider(ctx, user, provider) {
+		return errors.New("this sso provider is not for your company")
+	}
+	if identity, err := s.providerRepo.FindIdentity(ctx, provider.ID, subject); err == nil {
+		if identity.UserID == userID {
+			return nil
+		}
+		return errors.New("this identity is already linked to another account")
+	}
+	return s.createIdentity(ctx, provider, userID, subject)
+}
+
+func (s *oidcService) createIdentity(ctx context.Context, provider *model.OIDCProvider, userID, subject string) error {
+	identity := &model.UserIdentity{UserID: userID, ProviderID: provider.ID, Subject: subject}
+	if err := s.providerRepo.CreateIdentity(ctx, identity); err != nil {
+		return errors.New("failed to link account to the sso provider, it may already be linked to another identity")
+	}
+	return nil
+}
+
+// mayUseProvider reports whether the user may sign in through the provider.
+// A provider only speaks for its own company, and superadmins and service
+// accounts never sign in through a company's provider.
+func (s *oidcService) mayUseProvider(ctx context.Context, user *model.User, provider *model.OIDCProvider) bool {
+	return user.Role != model.RoleSuperAdmin && !user.IsServiceAccount && s.belongsToCompany(ctx, user.ID, provider.CompanyID)
+}
+
+// onlyInCompany reports whether the company is the only one the user belongs
+// to, through their employee record and company bindings
+func (s *oidcService) onlyInCompany(ctx context.Context, userID, companyID string) bool {
+	emp, err := s.empRepo.FindByUserID(ctx, userID)
+	if err == nil && emp.CompanyID != companyID {
+		return false
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false
+	}
+	bindings, err := s.userCompanyRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return false
+	}
+	for _, b := range bindings {
+		if b.CompanyID != companyID {
+			return false
+		}
+	}
+	return true
+}
+
+// belongsToCompany reports whether the company is the one of the user's
+// employee record or one of their company bindings
+func (s *oidcService) belongsToCompany(ctx context.Context, userID, companyID string) bool {
+	if emp, err := s.empRepo.FindByUserID(ctx, userID); err == nil && emp.CompanyID == companyID {
+		return true
+	}
+	bindings, err := s.userCompanyRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return false
+	}
+	for _, b := range bindings {
+		if b.CompanyID == companyID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *oidcService) clientConfig(provider *model.OIDCProvider) oidc.Config {
+	return oidc.Config{
+		ClientID:     provider.ClientID,
+		ClientSecret: provider.ClientSecret,
+		RedirectURL:  s.cfg.AppBaseURL + "/api/auth/oidc/" + provider.Slug + "/callback",
+		Scopes:       strings.Fields(provider.Scopes),
+	}
+}
+
+// safeRedirectPath returns path when it is a path on the frontend, and "/"
+// otherwise, so a login link cannot send users to another site
+func safeRedirectPath(path string) string {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") ||
+		strings.Contains(path, `\`) || len(path) > 500 {
+		return "/"
+	}
+	return path
+}
+
+// validateIssuerURL requires an https issuer. In development a plain http
+// issuer on the loopback interface is accepted too, for a local mock server.
+func (s *oidcService) validateIssuerURL(issuer string) error {
+	u, err := url.Parse(issuer)
+	if err != nil || u.Host == "" {
+		return errors.New("issuer URL must be an https URL")
+	}
+	if u.Scheme == "https" {
+		return nil
+	}
+	if u.Scheme == "http" && s.cfg.IsDevelopment() && isLoopback(u.Hostname()) {
+		return nil
+	}
+	return errors.New("issuer URL must be an https URL")
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// provisionRole parses the role given to auto-provisioned users. Providers
+// cannot create superadmins.
+func provisionRole(role string) (model.Role, error) {
+	switch model.Role(role) {
+	case "":
+		return model.RoleEmployee, nil
+	case model.RoleAdmin, model.RoleHR, model.RoleEmployee:
+		return model.Role(role), nil
+	}
+	return "", errors.New("default role must be admin, hr or employee")
+}
+
+func normalizeDomains(domains string) string {
+	var list []string
+	for _, d := range strings.Split(domains, ",") {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d != "" {
+			list = append(list, d)
+		}
+	}
+	return strings.Join(list, ",")
+}
+
+func domainAllowed(allowed, email string) bool {
+	if allowed == "" {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, d := range strings.Split(allowed, ",") {
+		if d == domain {
+			return true
+		}
+	}
+	return false
+}
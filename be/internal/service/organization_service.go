@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"hris-backend/internal/dto"
@@ -8,7 +9,7 @@ import (
 )
 
 type OrganizationService interface {
-	GetStructure(companyID string) (*dto.OrgStructureResponse, error)
+	GetStructure(ctx context.Context, companyID string) (*dto.OrgStructureResponse, error)
 }
 
 type organizationService struct {
@@ -19,12 +20,12 @@ func NewOrganizationService(companyRepo repository.CompanyRepository) Organizati
 	return &organizationService{companyRepo: companyRepo}
 }
 
-func (s *organizationService) GetStructure(companyID string) (*dto.OrgStructureResponse, error) {
+func (s *organizationService) GetStructure(ctx context.Context, companyID string) (*dto.OrgStructureResponse, error) {
 	if companyID == "" {
 		return nil, errors.New("company_id is required")
 	}
 
-	company, err := s.companyRepo.FindWithStructure(companyID)
+	company, err := s.companyRepo.FindWithStructure(ctx, companyID)
 	if err != nil {
 		return nil, errors.New("company not found")
 	}
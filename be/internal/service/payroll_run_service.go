@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"hris-backend/internal/dto"
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	payrollRunOutcomeCreated = "created"
+	payrollRunOutcomeSkipped = "skipped"
+	payrollRunOutcomeFailed  = "failed"
+)
+
+type PayrollRunService interface {
+	GetAll(ctx context.Context, companyID string) ([]dto.PayrollRunResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.PayrollRunResponse, error)
+	Generate(ctx context.Context, req dto.GeneratePayrollRunRequest, createdBy string) (*dto.PayrollRunGenerateResponse, error)
+	UpdateStatus(ctx context.Context, id string, req dto.PayrollStatusRequest) (*dto.PayrollRunResponse, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type payrollRunService struct {
+	runRepo     repository.PayrollRunRepository
+	payrollRepo repository.PayrollRepository
+	companyRepo repository.CompanyRepository
+	empRepo     repository.EmployeeRepository
+	calc        payrollCalculator
+}
+
+func NewPayrollRunService(
+	runRepo repository.PayrollRunRepository,
+	payrollRepo repository.PayrollRepository,
+	companyRepo repository.CompanyRepository,
+	empRepo repository.EmployeeRepository,
+	salaryRepo repository.EmployeeSalaryRepository,
+	attRepo repository.AttendanceRepository,
+) PayrollRunService {
+	return &payrollRunService{
+		runRepo:     runRepo,
+		payrollRepo: payrollRepo,
+		companyRepo: companyRepo,
+		empRepo:     empRepo,
+		calc: payrollCalculator{
+			payrollRepo: payrollRepo,
+			salaryRepo:  salaryRepo,
+			attRepo:     attRepo,
+		},
+	}
+}
+
+func (s *payrollRunService) GetAll(ctx context.Context, companyID string) ([]dto.PayrollRunResponse, error) {
+	var runs []model.PayrollRun
+	var err error
+	if companyID != "" {
+		runs, err = s.runRepo.FindByCompanyID(ctx, companyID)
+	} else {
+		runs, err = s.runRepo.FindAll(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToPayrollRunResponses(runs), nil
+}
+
+func (s *payrollRunService) GetByID(ctx context.Context, id string) (*dto.PayrollRunResponse, error) {
+	run, err := s.loadRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	response := dto.ToPayrollRunResponse(run)
+	return &response, nil
+}
+
+// Generate builds draft payrolls for every employee of the company who was
+// active during the period. Employees that already have a payroll for the
+// period are skipped and employees whose payroll cannot be calculated are
+// reported as failed; neither stops the rest of the run. The run and all
+// created payrolls are stored in one transaction. When no payroll could be
+// created, no run is stored and the per-employee results are returned along
+// with an error.
+func (s *payrollRunService) Generate(ctx context.Context, req dto.GeneratePayrollRunRequest, createdBy string) (*dto.PayrollRunGenerateResponse, error) {
+	if _, err := s.companyRepo.FindByID(ctx, req.CompanyID); err != nil {
+		return nil, errors.New("company not found")
+	}
+
+	periodStart := time.Date(req.Year, time.Month(req.Month), 1, 0, 0, 0, 0, time.Local)
+	periodEnd := periodStart.AddDate(0, 1, -1)
+
+	employees, err := s.empRepo.FindActiveByCompanyID(ctx, req.CompanyID, periodStart, periodEnd)
+	if err != nil {
+		return nil, errors.New("failed to fetch employees")
+	}
+	if len(employees) == 0 {
+		return nil, errors.New("no active employees found for this company and period")
+	}
+
+	run := &model.PayrollRun{
+		CompanyID:      req.CompanyID,
+		PeriodMonth:    req.Month,
+		PeriodYear:     req.Year,
+		Status:         model.PayrollDraft,
+		TotalEmployees: len(employees),
+		CreatedBy:      createdBy,
+		Notes:          req.Notes,
+	}
+
+	var payrolls []model.Payroll
+	results := make([]dto.PayrollRunEmployeeResult, len(employees))
+	// payrollIndex maps a result to its entry in payrolls so the payroll ID
+	// can be filled in once the run has been stored.
+	payrollIndex := make(map[int]int)
+
+	for i := range employees {
+		emp := &employees[i]
+		results[i] = dto.PayrollRunEmployeeResult{
+			EmployeeID:     emp.ID,
+			EmployeeNumber: emp.EmployeeNumber,
+			EmployeeName:   emp.User.Name,
+		}
+
+		existing, err := s.payrollRepo.FindByEmployeeIDAndPeriod(ctx, emp.ID, req.Month, req.Year)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("failed to check existing payrolls")
+		}
+		if existing != nil {
+			results[i].Outcome = payrollRunOutcomeSkipped
+			results[i].Reason = "payroll already exists for this period"
+			results[i].PayrollID = existing.ID
+			run.SkippedCount++
+			continue
+		}
+
+		payroll, err := s.calc.build(ctx, emp, req.Month, req.Year)
+		if err != nil {
+			results[i].Outcome = payrollRunOutcomeFailed
+			results[i].Reason = err.Error()
+			run.FailedCount++
+			continue
+		}
+
+		results[i].Outcome = payrollRunOutcomeCreated
+		payrollIndex[i] = len(payrolls)
+		payrolls = append(payrolls, *payroll)
+		run.CreatedCount++
+		run.TotalGross += payroll.GrossSalary
+		run.TotalNet += payroll.NetSalary
+	}
+
+	if len(payrolls) == 0 {
+		return &dto.PayrollRunGenerateResponse{Results: results}, errors.New("no payrolls were generated for this period")
+	}
+
+	run.TotalGross = math.Round(run.TotalGross)
+	run.TotalNet = math.Round(run.TotalNet)
+
+	if err := s.runRepo.CreateWithPayrolls(ctx, run, payrolls); err != nil {
+		if errors.Is(err, repository.ErrPayrollExists) {
+			return nil, errors.New("payrolls for this period were generated meanwhile, generate the run again")
+		}
+		return nil, errors.New("failed to generate payroll run")
+	}
+
+	for i, j := range payrollIndex {
+		results[i].PayrollID = payrolls[j].ID
+	}
+
+	created, err := s.loadRun(ctx, run.ID)
+	if err != nil {
+		return nil, errors.New("failed to load payroll run")
+	}
+
+	runResp := dto.ToPayrollRunResponse(created)
+	return &dto.PayrollRunGenerateResponse{Run: &runResp, Results: results}, nil
+}
+
+// UpdateStatus moves the run and every payroll in it to the next status.
+// Payrolls that were already moved individually to that status or past it
+// are left as they are.
+func (s *payrollRunService) UpdateStatus(ctx context.Context, id string, req dto.PayrollStatusRequest) (*dto.PayrollRunResponse, error) {
+	run, err := s.loadRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validatePayrollTransition(run.Status, req.Status); err != nil {
+		return nil, errors.New("payroll run: " + err.Error())
+	}
+
+	now := time.Now()
+	run.Status = req.Status
+	switch req.Status {
+	case model.PayrollProcessed:
+		run.ProcessedAt = &now
+	case model.PayrollPaid:
+		run.PaidAt = &now
+	}
+
+	var events []model.OutboxEvent
+	for i := range run.Payrolls {
+		payroll := &run.Payrolls[i]
+		if payrollStatusRank[payroll.Status] >= payrollStatusRank[req.Status] {
+			continue
+		}
+		if err := validatePayrollTransition(payroll.Status, req.Status); err != nil {
+			return nil, errors.New("payroll for employee " + payroll.Employee.EmployeeNumber + ": " + err.Error())
+		}
+		payroll.Status = req.Status
+		if req.Status == model.PayrollPaid {
+			payroll.PaidAt = &now
+		}
+
+		event, err := payrollStatusEvent(ctx, payroll)
+		if err != nil {
+			return nil, errors.New("failed to update payroll run status")
+		}
+		events = append(events, event)
+	}
+
+	if err := s.runRepo.SaveWithPayrolls(ctx, run, run.Payrolls, events); err != nil {
+		return nil, errors.New("failed to update payroll run status")
+	}
+
+	updated, err := s.loadRun(ctx, run.ID)
+	if err != nil {
+		return nil, errors.New("failed to load payroll run")
+	}
+
+	response := dto.ToPayrollRunResponse(updated)
+	return &response, nil
+}
+
+func (s *payrollRunService) Delete(ctx context.Context, id string) error {
+	run, err := s.loadRun(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if run.Status != model.PayrollDraft {
+		return errors.New("can only delete draft payroll run")
+	}
+	for _, payroll := range run.Payrolls {
+		if payroll.Status != model.PayrollDraft {
+			return errors.New("payroll run contains payrolls that are no longer draft")
+		}
+	}
+
+	return s.runRepo.DeleteWithPayrolls(ctx, id)
+}
+
+func (s *payrollRunService) loadRun(ctx context.Context, id string) (*model.PayrollRun, error) {
+	run, err := s.runRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("payroll run not found")
+	}
+
+	payrolls, err := s.payrollRepo.FindByPayrollRunID(ctx, run.ID)
+	if err != nil {
+		return nil, errors.New("failed to fetch payroll run payrolls")
+	}
+	run.Payrolls = payrolls
+
+	return run, nil
+}
@@ -1,7 +1,9 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"math"
 	"time"
 
@@ -9,25 +11,27 @@ import (
 	"hris-backend/internal/model"
 	"hris-backend/internal/repository"
 	"hris-backend/pkg/calculator"
+	"hris-backend/pkg/kafka"
+
+	"gorm.io/gorm"
 )
 
 type PayrollService interface {
-	GetAll() ([]dto.PayrollResponse, error)
-	GetByID(id string) (*dto.PayrollResponse, error)
-	GetByEmployeeID(employeeID string) ([]dto.PayrollResponse, error)
-	GetByPeriod(month, year int) ([]dto.PayrollResponse, error)
-	GetPaidByEmployeeID(employeeID string) ([]dto.PayrollResponse, error)
-	Generate(req dto.GeneratePayrollRequest) (*dto.PayrollResponse, error)
-	Update(id string, req dto.UpdatePayrollRequest) (*dto.PayrollResponse, error)
-	UpdateStatus(id string, req dto.PayrollStatusRequest) (*dto.PayrollResponse, error)
-	Delete(id string) error
+	GetAll(ctx context.Context) ([]dto.PayrollResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.PayrollResponse, error)
+	GetByEmployeeID(ctx context.Context, employeeID string) ([]dto.PayrollResponse, error)
+	GetByPeriod(ctx context.Context, month, year int) ([]dto.PayrollResponse, error)
+	GetPaidByEmployeeID(ctx context.Context, employeeID string) ([]dto.PayrollResponse, error)
+	Generate(ctx context.Context, req dto.GeneratePayrollRequest) (*dto.PayrollResponse, error)
+	Update(ctx context.Context, id string, req dto.UpdatePayrollRequest) (*dto.PayrollResponse, error)
+	UpdateStatus(ctx context.Context, id string, req dto.PayrollStatusRequest) (*dto.PayrollResponse, error)
+	Delete(ctx context.Context, id string) error
 }
 
 type payrollService struct {
 	payrollRepo repository.PayrollRepository
 	empRepo     repository.EmployeeRepository
-	salaryRepo  repository.EmployeeSalaryRepository
-	attRepo     repository.AttendanceRepository
+	calc        payrollCalculator
 }
 
 func NewPayrollService(
@@ -39,21 +43,24 @@ func NewPayrollService(
 	return &payrollService{
 		payrollRepo: payrollRepo,
 		empRepo:     empRepo,
-		salaryRepo:  salaryRepo,
-		attRepo:     attRepo,
+		calc: payrollCalculator{
+			payrollRepo: payrollRepo,
+			salaryRepo:  salaryRepo,
+			attRepo:     attRepo,
+		},
 	}
 }
 
-func (s *payrollService) GetAll() ([]dto.PayrollResponse, error) {
-	payrolls, err := s.payrollRepo.FindAll()
+func (s *payrollService) GetAll(ctx context.Context) ([]dto.PayrollResponse, error) {
+	payrolls, err := s.payrollRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToPayrollResponses(payrolls), nil
 }
 
-func (s *payrollService) GetByID(id string) (*dto.PayrollResponse, error) {
-	payroll, err := s.payrollRepo.FindByID(id)
+func (s *payrollService) GetByID(ctx context.Context, id string) (*dto.PayrollResponse, error) {
+	payroll, err := s.payrollRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("payroll not found")
 	}
@@ -61,119 +68,59 @@ func (s *payrollService) GetByID(id string) (*dto.PayrollResponse, error) {
 	return &response, nil
 }
 
-func (s *payrollService) GetByEmployeeID(employeeID string) ([]dto.PayrollResponse, error) {
-	payrolls, err := s.payrollRepo.FindByEmployeeID(employeeID)
+func (s *payrollService) GetByEmployeeID(ctx context.Context, employeeID string) ([]dto.PayrollResponse, error) {
+	payrolls, err := s.payrollRepo.FindByEmployeeID(ctx, employeeID)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToPayrollResponses(payrolls), nil
 }
 
-func (s *payrollService) GetByPeriod(month, year int) ([]dto.PayrollResponse, error) {
-	payrolls, err := s.payrollRepo.FindByPeriod(month, year)
+func (s *payrollService) GetByPeriod(ctx context.Context, month, year int) ([]dto.PayrollResponse, error) {
+	payrolls, err := s.payrollRepo.FindByPeriod(ctx, month, year)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToPayrollResponses(payrolls), nil
 }
 
-func (s *payrollService) GetPaidByEmployeeID(employeeID string) ([]dto.PayrollResponse, error) {
-	payrolls, err := s.payrollRepo.FindPaidByEmployeeID(employeeID)
+func (s *payrollService) GetPaidByEmployeeID(ctx context.Context, employeeID string) ([]dto.PayrollResponse, error) {
+	payrolls, err := s.payrollRepo.FindPaidByEmployeeID(ctx, employeeID)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToPayrollResponses(payrolls), nil
 }
 
-func (s *payrollService) Generate(req dto.GeneratePayrollRequest) (*dto.PayrollResponse, error) {
+func (s *payrollService) Generate(ctx context.Context, req dto.GeneratePayrollRequest) (*dto.PayrollResponse, error) {
 	// Validate employee
-	emp, err := s.empRepo.FindByID(req.EmployeeID)
+	emp, err := s.empRepo.FindByID(ctx, req.EmployeeID)
 	if err != nil {
 		return nil, errors.New("employee not found")
 	}
 
 	// Check for duplicate payroll
-	existing, _ := s.payrollRepo.FindByEmployeeIDAndPeriod(req.EmployeeID, req.Month, req.Year)
+	existing, err := s.payrollRepo.FindByEmployeeIDAndPeriod(ctx, req.EmployeeID, req.Month, req.Year)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("failed to check existing payrolls")
+	}
 	if existing != nil {
 		return nil, errors.New("payroll already exists for this period")
 	}
 
-	// Get latest salary
-	salary, err := s.salaryRepo.FindLatestByEmployeeID(req.EmployeeID)
+	payroll, err := s.calc.build(ctx, emp, req.Month, req.Year)
 	if err != nil {
-		return nil, errors.New("employee salary not found, please set salary first")
-	}
-
-	// Get attendance for the month
-	attendances, err := s.attRepo.FindByEmployeeIDAndMonth(req.EmployeeID, req.Month, req.Year)
-	if err != nil {
-		return nil, errors.New("failed to fetch attendance data")
+		return nil, err
 	}
 
-	// Calculate working days and present days
-	workingDays := calculateWorkingDays(req.Month, req.Year)
-	presentDays := 0
-	totalOvertimeHours := 0.0
-	for _, att := range attendances {
-		if att.Status == model.AttendanceHadir || att.Status == model.AttendanceTerlambat {
-			presentDays++
+	if err := s.payrollRepo.Create(ctx, payroll); err != nil {
+		if errors.Is(err, repository.ErrPayrollExists) {
+			return nil, err
 		}
-		totalOvertimeHours += att.OvertimeHours
-	}
-
-	// Calculate salary components
-	basicSalary := salary.BasicSalary
-	totalAllowances := salary.TransportAllowance + salary.MealAllowance + salary.HousingAllowance + salary.PositionAllowance
-
-	// Calculate overtime pay using calculator
-	overtimePay := calculator.CalculateOvertime(basicSalary, totalOvertimeHours, false)
-
-	// Gross salary
-	grossSalary := basicSalary + totalAllowances + overtimePay
-
-	// BPJS deductions (employee portion)
-	bpjsKesEmployee := salary.BPJSKesEmployee
-	bpjsTKEmployee := salary.BPJSTKJHTEmployee + salary.BPJSTKJPEmployee
-
-	// Calculate PPh21
-	annualGross := grossSalary * 12
-	ptkp := calculator.GetPTKP(emp.MaritalStatus, 0)
-	pph21 := calculator.CalculatePPh21Monthly(annualGross, ptkp)
-
-	// Total deductions
-	totalDeductions := bpjsKesEmployee + bpjsTKEmployee + pph21
-
-	// Net salary
-	netSalary := grossSalary - totalDeductions
-
-	payroll := &model.Payroll{
-		EmployeeID:       req.EmployeeID,
-		PeriodMonth:      req.Month,
-		PeriodYear:       req.Year,
-		WorkingDays:      workingDays,
-		PresentDays:      presentDays,
-		BasicSalary:      basicSalary,
-		TotalAllowances:  totalAllowances,
-		OvertimePay:      overtimePay,
-		GrossSalary:      grossSalary,
-		BPJSKesDeduction: bpjsKesEmployee,
-		BPJSTKDeduction:  bpjsTKEmployee,
-		PPH21:            pph21,
-		TotalDeductions:  totalDeductions,
-		NetSalary:        netSalary,
-		Status:           model.PayrollDraft,
-	}
-
-	// Round all monetary values
-	payroll.GrossSalary = math.Round(payroll.GrossSalary)
-	payroll.TotalDeductions = math.Round(payroll.TotalDeductions)
-	payroll.NetSalary = math.Round(payroll.NetSalary)
-
-	if err := s.payrollRepo.Create(payroll); err != nil {
 		return nil, errors.New("failed to generate payroll")
 	}
 
-	created, err := s.payrollRepo.FindByID(payroll.ID)
+	created, err := s.payrollRepo.FindByID(ctx, payroll.ID)
 	if err != nil {
 		return nil, errors.New("failed to load payroll")
 	}
@@ -182,8 +129,8 @@ func (s *payrollService) Generate(req dto.GeneratePayrollRequest) (*dto.PayrollR
 	return &response, nil
 }
 
-func (s *payrollService) Update(id string, req dto.UpdatePayrollRequest) (*dto.PayrollResponse, error) {
-	payroll, err := s.payrollRepo.FindByID(id)
+func (s *payrollService) Update(ctx context.Context, id string, req dto.UpdatePayrollRequest) (*dto.PayrollResponse, error) {
+	payroll, err := s.payrollRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("payroll not found")
 	}
@@ -205,8 +152,14 @@ func (s *payrollService) Update(id string, req dto.UpdatePayrollRequest) (*dto.P
 		payroll.Notes = req.Notes
 	}
 
-	// Recalculate gross and net
+	// Recalculate gross, PPh21 and net. Company-paid premiums in the taxable
+	// income are unchanged, so only the difference in gross carries over.
+	previousGross := payroll.GrossSalary
 	payroll.GrossSalary = payroll.BasicSalary + payroll.TotalAllowances + payroll.OvertimePay + payroll.THR
+	payroll.TaxableIncome += payroll.GrossSalary - previousGross
+	if err := s.calc.applyPPh21(ctx, &payroll.Employee, payroll); err != nil {
+		return nil, err
+	}
 	payroll.TotalDeductions = payroll.BPJSKesDeduction + payroll.BPJSTKDeduction + payroll.PPH21 + payroll.OtherDeductions
 	payroll.NetSalary = payroll.GrossSalary - payroll.TotalDeductions
 
@@ -214,7 +167,7 @@ func (s *payrollService) Update(id string, req dto.UpdatePayrollRequest) (*dto.P
 	payroll.TotalDeductions = math.Round(payroll.TotalDeductions)
 	payroll.NetSalary = math.Round(payroll.NetSalary)
 
-	if err := s.payrollRepo.Update(payroll); err != nil {
+	if err := s.payrollRepo.Update(ctx, payroll); err != nil {
 		return nil, errors.New("failed to update payroll")
 	}
 
@@ -222,24 +175,14 @@ func (s *payrollService) Update(id string, req dto.UpdatePayrollRequest) (*dto.P
 	return &response, nil
 }
 
-func (s *payrollService) UpdateStatus(id string, req dto.PayrollStatusRequest) (*dto.PayrollResponse, error) {
-	payroll, err := s.payrollRepo.FindByID(id)
+func (s *payrollService) UpdateStatus(ctx context.Context, id string, req dto.PayrollStatusRequest) (*dto.PayrollResponse, error) {
+	payroll, err := s.payrollRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("payroll not found")
 	}
 
-	// Validate status transitions: draft -> processed -> paid
-	switch payroll.Status {
-	case model.PayrollDraft:
-		if req.Status != model.PayrollProcessed {
-			return nil, errors.New("draft payroll can only be moved to processed")
-		}
-	case model.PayrollProcessed:
-		if req.Status != model.PayrollPaid {
-			return nil, errors.New("processed payroll can only be moved to paid")
-		}
-	case model.PayrollPaid:
-		return nil, errors.New("paid payroll status cannot be changed")
+	if err := validatePayrollTransition(payroll.Status, req.Status); err != nil {
+		return nil, err
 	}
 
 	payroll.Status = req.Status
@@ -248,12 +191,16 @@ func (s *payrollService) UpdateStatus(id string, req dto.PayrollStatusRequest) (
 		payroll.PaidAt = &now
 	}
 
-	if err := s.payrollRepo.Update(payroll); err != nil {
+	event, err := payrollStatusEvent(ctx, payroll)
+	if err != nil {
+		return nil, errors.New("failed to update payroll status")
+	}
+	if err := s.payrollRepo.UpdateStatus(ctx, payroll, []model.OutboxEvent{event}); err != nil {
 		return nil, errors.New("failed to update payroll status")
 	}
 
 	// Reload
-	updated, err := s.payrollRepo.FindByID(payroll.ID)
+	updated, err := s.payrollRepo.FindByID(ctx, payroll.ID)
 	if err != nil {
 		return nil, errors.New("failed to load payroll")
 	}
@@ -262,8 +209,8 @@ func (s *payrollService) UpdateStatus(id string, req dto.PayrollStatusRequest) (
 	return &response, nil
 }
 
-func (s *payrollService) Delete(id string) error {
-	payroll, err := s.payrollRepo.FindByID(id)
+func (s *payrollService) Delete(ctx context.Context, id string) error {
+	payroll, err := s.payrollRepo.FindByID(ctx, id)
 	if err != nil {
 		return errors.New("payroll not found")
 	}
@@ -272,7 +219,193 @@ func (s *payrollService) Delete(id string) error {
 		return errors.New("can only delete draft payroll")
 	}
 
-	return s.payrollRepo.Delete(id)
+	return s.payrollRepo.Delete(ctx, id)
+}
+
+// payrollStatusEvent tells the employee their payroll was processed or paid,
+// the statuses a payroll moves to after draft. It is keyed by employee so
+// both events of a payroll stay in order.
+func payrollStatusEvent(ctx context.Context, payroll *model.Payroll) (model.OutboxEvent, error) {
+	eventType := kafka.EventPayrollProcessed
+	if payroll.Status == model.PayrollPaid {
+		eventType = kafka.EventPayrollPaid
+	}
+	return kafka.NewOutboxEvent(ctx, eventType, payroll.EmployeeID, payroll.Employee.CompanyID, kafka.PayrollProcessedPayload{
+		PayrollID:      payroll.ID,
+		EmployeeUserID: payroll.Employee.UserID,
+		EmployeeName:   payroll.Employee.User.Name,
+		Period:         fmt.Sprintf("%s %d", time.Month(payroll.PeriodMonth), payroll.PeriodYear),
+		NetSalary:      payroll.NetSalary,
+		Status:         string(payroll.Status),
+	})
+}
+
+// payrollCalculator holds the repositories needed to calculate a payroll.
+// Single and batch generation share it so the figures always match.
+type payrollCalculator struct {
+	payrollRepo repository.PayrollRepository
+	salaryRepo  repository.EmployeeSalaryRepository
+	attRepo     repository.AttendanceRepository
+}
+
+// build calculates a draft payroll for an employee and period from the
+// employee's latest salary and attendance. It does not persist anything.
+func (c payrollCalculator) build(ctx context.Context, emp *model.Employee, month, year int) (*model.Payroll, error) {
+	// Get latest salary
+	salary, err := c.salaryRepo.FindLatestByEmployeeID(ctx, emp.ID)
+	if err != nil {
+		return nil, errors.New("employee salary not found, please set salary first")
+	}
+
+	// Get attendance for the month
+	attendances, err := c.attRepo.FindByEmployeeIDAndMonth(ctx, emp.ID, month, year)
+	if err != nil {
+		return nil, errors.New("failed to fetch attendance data")
+	}
+
+	// Calculate working days and present days
+	workingDays := calculateWorkingDays(month, year)
+	presentDays := 0
+	totalOvertimeHours := 0.0
+	for _, att := range attendances {
+		// Days of approved leave are paid like days present
+		if att.Status == model.AttendanceHadir || att.Status == model.AttendanceTerlambat || att.LeaveID != nil {
+			presentDays++
+		}
+		totalOvertimeHours += att.OvertimeHours
+	}
+
+	// Calculate salary components
+	basicSalary := salary.BasicSalary
+	totalAllowances := salary.TransportAllowance + salary.MealAllowance + salary.HousingAllowance + salary.PositionAllowance
+
+	// Calculate overtime pay using calculator
+	overtimePay := calculator.CalculateOvertime(basicSalary, totalOvertimeHours, false)
+
+	// Gross salary
+	grossSalary := basicSalary + totalAllowances + overtimePay
+
+	// BPJS deductions (employee portion)
+	bpjsKesEmployee := salary.BPJSKesEmployee
+	bpjsTKEmployee := salary.BPJSTKJHTEmployee + salary.BPJSTKJPEmployee
+
+	// Taxable gross also includes the JKK, JKM and BPJS Kesehatan premiums
+	// paid by the company
+	taxableIncome := grossSalary + salary.BPJSTKJKK + salary.BPJSTKJKM + salary.BPJSKesCompany
+
+	payroll := &model.Payroll{
+		EmployeeID:       emp.ID,
+		PeriodMonth:      month,
+		PeriodYear:       year,
+		WorkingDays:      workingDays,
+		PresentDays:      presentDays,
+		BasicSalary:      basicSalary,
+		TotalAllowances:  totalAllowances,
+		OvertimePay:      overtimePay,
+		GrossSalary:      grossSalary,
+		BPJSKesDeduction: bpjsKesEmployee,
+		BPJSTKDeduction:  bpjsTKEmployee,
+		TaxableIncome:    taxableIncome,
+		Status:           model.PayrollDraft,
+	}
+
+	// Calculate PPh21
+	if err := c.applyPPh21(ctx, emp, payroll); err != nil {
+		return nil, err
+	}
+
+	// Total deductions
+	payroll.TotalDeductions = bpjsKesEmployee + bpjsTKEmployee + payroll.PPH21
+
+	// Net salary
+	payroll.NetSalary = grossSalary - payroll.TotalDeductions
+
+	// Round all monetary values
+	payroll.GrossSalary = math.Round(payroll.GrossSalary)
+	payroll.TotalDeductions = math.Round(payroll.TotalDeductions)
+	payroll.NetSalary = math.Round(payroll.NetSalary)
+
+	return payroll, nil
+}
+
+// applyPPh21 sets the PTKP status, TER category and PPh 21 of a payroll from
+// its TaxableIncome (PP 58/2023). Every month except the employee's last month
+// of the tax year withholds TaxableIncome x TER rate. The last month (December,
+// or the month the employee resigns) is a true-up: the annual tax owed under
+// Pasal 17 minus the tax withheld by the employee's earlier payrolls that year.
+func (c payrollCalculator) applyPPh21(ctx context.Context, emp *model.Employee, payroll *model.Payroll) error {
+	category := calculator.GetTERCategory(emp.MaritalStatus, emp.Dependents)
+	payroll.PTKPStatus = calculator.GetPTKPStatus(emp.MaritalStatus, emp.Dependents)
+	payroll.TERCategory = string(category)
+
+	if !isFinalTaxMonth(emp, payroll.PeriodMonth, payroll.PeriodYear) {
+		payroll.PPH21 = calculator.CalculatePPh21TER(payroll.TaxableIncome, category)
+		return nil
+	}
+
+	earlier, err := c.payrollRepo.FindByEmployeeIDAndYear(ctx, emp.ID, payroll.PeriodYear)
+	if err != nil {
+		return errors.New("failed to fetch payrolls for tax year")
+	}
+
+	annualGross := payroll.TaxableIncome
+	annualPension := payroll.BPJSTKDeduction
+	withheld := 0.0
+	monthsWorked := 1
+	for _, p := range earlier {
+		if p.ID == payroll.ID || p.PeriodMonth >= payroll.PeriodMonth {
+			continue
+		}
+		// Payrolls generated before TaxableIncome was recorded only have gross
+		taxable := p.TaxableIncome
+		if taxable == 0 {
+			taxable = p.GrossSalary
+		}
+		annualGross += taxable
+		annualPension += p.BPJSTKDeduction
+		withheld += p.PPH21
+		monthsWorked++
+	}
+
+	ptkp := calculator.GetPTKP(emp.MaritalStatus, emp.Dependents)
+	payroll.PPH21 = calculator.CalculatePPh21December(annualGross, annualPension, ptkp, monthsWorked, withheld)
+	return nil
+}
+
+// isFinalTaxMonth reports whether a period is the employee's last month of
+// the tax year: December, or the month they resign
+func isFinalTaxMonth(emp *model.Employee, month, year int) bool {
+	if month == 12 {
+		return true
+	}
+	if emp.ResignDate != nil {
+		return emp.ResignDate.Year() == year && int(emp.ResignDate.Month()) == month
+	}
+	return false
+}
+
+// payrollStatusRank orders the payroll statuses along their lifecycle
+var payrollStatusRank = map[model.PayrollStatus]int{
+	model.PayrollDraft:     0,
+	model.PayrollProcessed: 1,
+	model.PayrollPaid:      2,
+}
+
+// validatePayrollTransition enforces the draft -> processed -> paid lifecycle
+func validatePayrollTransition(from, to model.PayrollStatus) error {
+	switch from {
+	case model.PayrollDraft:
+		if to != model.PayrollProcessed {
+			return errors.New("draft payroll can only be moved to processed")
+		}
+	case model.PayrollProcessed:
+		if to != model.PayrollPaid {
+			return errors.New("processed payroll can only be moved to paid")
+		}
+	case model.PayrollPaid:
+		return errors.New("paid payroll status cannot be changed")
+	}
+	return nil
 }
 
 // calculateWorkingDays returns approximate working days (weekdays) in a month
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"hris-backend/internal/dto"
+	"hris-backend/internal/model"
+	"hris-backend/internal/permissions"
+	"hris-backend/internal/repository"
+)
+
+type PermissionService interface {
+	Resolve(ctx context.Context, userID, role string) (permissions.Set, error)
+	GetCatalog(ctx context.Context) ([]dto.PermissionResponse, error)
+	SyncCatalog(ctx context.Context) error
+}
+
+type permissionService struct {
+	permRepo       repository.PermissionRepository
+	customRoleRepo repository.CustomRoleRepository
+	userRepo       repository.UserRepository
+}
+
+func NewPermissionService(permRepo repository.PermissionRepository, customRoleRepo repository.CustomRoleRepository, userRepo repository.UserRepository) PermissionService {
+	return &permissionService{
+		permRepo:       permRepo,
+		customRoleRepo: customRoleRepo,
+		userRepo:       userRepo,
+	}
+}
+
+// Resolve returns the effective permissions of a user: every permission for
+// superadmins, the custom role's permissions when one is assigned, and the
+// built-in role's permissions otherwise.
+func (s *permissionService) Resolve(ctx context.Context, userID, role string) (permissions.Set, error) {
+	if role == string(model.RoleSuperAdmin) {
+		return permissions.NewSet(permissions.Names()), nil
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.CustomRoleID != nil {
+		customRole, err := s.customRoleRepo.FindByID(ctx, *user.CustomRoleID)
+		if err != nil {
+			// The role was deleted or is outside the user's companies
+			return permissions.Set{}, nil
+		}
+		names := make([]string, len(customRole.Permissions))
+		for i, p := range customRole.Permissions {
+			names[i] = p.Name
+		}
+		return permissions.NewSet(names), nil
+	}
+
+	names, err := s.permRepo.FindNamesByRole(ctx, user.Role)
+	if err != nil {
+		return nil, errors.New("failed to resolve permissions")
+	}
+	return permissions.NewSet(names), nil
+}
+
+func (s *permissionService) GetCatalog(ctx context.Context) ([]dto.PermissionResponse, error) {
+	perms, err := s.permRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToPermissionResponses(perms), nil
+}
+
+// SyncCatalog pushes the code-defined permission catalog into the DB and
+// grants the built-in roles their default permissions. Safe to call on every
+// startup.
+func (s *permissionService) SyncCatalog(ctx context.Context) error {
+	rows := make([]model.Permission, len(permissions.Catalog))
+	for i, p := range permissions.Catalog {
+		rows[i] = model.Permission{
+			Name:        p.Name,
+			Description: p.Description,
+			Module:      p.Module(),
+			Action:      p.Action(),
+		}
+	}
+	if err := s.permRepo.UpsertMany(ctx, rows); err != nil {
+		return err
+	}
+
+	for role, names := range permissions.RoleDefaults {
+		if err := s.permRepo.GrantToRole(ctx, model.Role(role), names); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Actor is the caller of a request that grants roles or permissions. It keeps
+// callers from granting more than they hold.
+type Actor struct {
+	Role        string
+	Permissions permissions.Set
+}
+
+// roleRank orders the built-in roles by privilege
+var roleRank = map[model.Role]int{
+	model.RoleEmployee:   0,
+	model.RoleHR:         1,
+	model.RoleAdmin:      2,
+	model.RoleSuperAdmin: 3,
+}
+
+// checkRole returns an error unless the actor may give a user the built-in
+// role, or change a user who has it: only superadmins grant superadmin, and
+// nobody reaches above their own role
+func (a Actor) checkRole(role model.Role) error {
+	if role == model.RoleSuperAdmin && a.Role != string(model.RoleSuperAdmin) {
+		return errors.New("only a superadmin can grant the superadmin role")
+	}
+	if roleRank[role] > roleRank[model.Role(a.Role)] {
+		return errors.New("cannot grant a role above your own")
+	}
+	return nil
+}
+
+// checkPermissions returns an error unless the actor holds every permission
+// they grant
+func (a Actor) checkPermissions(names []string) error {
+	for _, name := range names {
+		if !a.Permissions.Has(name) {
+			return errors.New("cannot grant a permission you do not have: " + name)
+		}
+	}
+	return nil
+}
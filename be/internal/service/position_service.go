@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"hris-backend/internal/dto"
@@ -9,12 +10,12 @@ import (
 )
 
 type PositionService interface {
-	GetAll() ([]dto.PositionResponse, error)
-	GetByID(id string) (*dto.PositionResponse, error)
-	GetByCompanyID(companyID string) ([]dto.PositionResponse, error)
-	Create(req dto.CreatePositionRequest) (*dto.PositionResponse, error)
-	Update(id string, req dto.UpdatePositionRequest) (*dto.PositionResponse, error)
-	Delete(id string) error
+	GetAll(ctx context.Context) ([]dto.PositionResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.PositionResponse, error)
+	GetByCompanyID(ctx context.Context, companyID string) ([]dto.PositionResponse, error)
+	Create(ctx context.Context, req dto.CreatePositionRequest) (*dto.PositionResponse, error)
+	Update(ctx context.Context, id string, req dto.UpdatePositionRequest) (*dto.PositionResponse, error)
+	Delete(ctx context.Context, id string) error
 }
 
 type positionService struct {
@@ -29,16 +30,16 @@ func NewPositionService(posRepo repository.PositionRepository, companyRepo repos
 	}
 }
 
-func (s *positionService) GetAll() ([]dto.PositionResponse, error) {
-	positions, err := s.posRepo.FindAll()
+func (s *positionService) GetAll(ctx context.Context) ([]dto.PositionResponse, error) {
+	positions, err := s.posRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToPositionResponses(positions), nil
 }
 
-func (s *positionService) GetByID(id string) (*dto.PositionResponse, error) {
-	pos, err := s.posRepo.FindByID(id)
+func (s *positionService) GetByID(ctx context.Context, id string) (*dto.PositionResponse, error) {
+	pos, err := s.posRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("position not found")
 	}
@@ -46,17 +47,17 @@ func (s *positionService) GetByID(id string) (*dto.PositionResponse, error) {
 	return &response, nil
 }
 
-func (s *positionService) GetByCompanyID(companyID string) ([]dto.PositionResponse, error) {
-	positions, err := s.posRepo.FindByCompanyID(companyID)
+func (s *positionService) GetByCompanyID(ctx context.Context, companyID string) ([]dto.PositionResponse, error) {
+	positions, err := s.posRepo.FindByCompanyID(ctx, companyID)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToPositionResponses(positions), nil
 }
 
-func (s *positionService) Create(req dto.CreatePositionRequest) (*dto.PositionResponse, error) {
+func (s *positionService) Create(ctx context.Context, req dto.CreatePositionRequest) (*dto.PositionResponse, error) {
 	// Validate company exists
-	_, err := s.companyRepo.FindByID(req.CompanyID)
+	_, err := s.companyRepo.FindByID(ctx, req.CompanyID)
 	if err != nil {
 		return nil, errors.New("company not found")
 	}
@@ -69,12 +70,12 @@ func (s *positionService) Create(req dto.CreatePositionRequest) (*dto.PositionRe
 		IsActive:     true,
 	}
 
-	if err := s.posRepo.Create(pos); err != nil {
+	if err := s.posRepo.Create(ctx, pos); err != nil {
 		return nil, errors.New("failed to create position")
 	}
 
 	// Reload with company preloaded
-	created, err := s.posRepo.FindByID(pos.ID)
+	created, err := s.posRepo.FindByID(ctx, pos.ID)
 	if err != nil {
 		return nil, errors.New("failed to load position")
 	}
@@ -83,8 +84,8 @@ func (s *positionService) Create(req dto.CreatePositionRequest) (*dto.PositionRe
 	return &response, nil
 }
 
-func (s *positionService) Update(id string, req dto.UpdatePositionRequest) (*dto.PositionResponse, error) {
-	pos, err := s.posRepo.FindByID(id)
+func (s *positionService) Update(ctx context.Context, id string, req dto.UpdatePositionRequest) (*dto.PositionResponse, error) {
+	pos, err := s.posRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("position not found")
 	}
@@ -102,7 +103,7 @@ func (s *positionService) Update(id string, req dto.UpdatePositionRequest) (*dto
 		pos.IsActive = *req.IsActive
 	}
 
-	if err := s.posRepo.Update(pos); err != nil {
+	if err := s.posRepo.Update(ctx, pos); err != nil {
 		return nil, errors.New("failed to update position")
 	}
 
@@ -110,10 +111,10 @@ func (s *positionService) Update(id string, req dto.UpdatePositionRequest) (*dto
 	return &response, nil
 }
 
-func (s *positionService) Delete(id string) error {
-	_, err := s.posRepo.FindByID(id)
+func (s *positionService) Delete(ctx context.Context, id string) error {
+	_, err := s.posRepo.FindByID(ctx, id)
 	if err != nil {
 		return errors.New("position not found")
 	}
-	return s.posRepo.Delete(id)
+	return s.posRepo.Delete(ctx, id)
 }
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"hris-backend/internal/dto"
+	"hris-backend/internal/repository"
+)
+
+type SessionService interface {
+	GetByUserID(ctx context.Context, userID, currentSessionID string) ([]dto.SessionResponse, error)
+	Revoke(ctx context.Context, userID, sessionID, reason string) error
+	RevokeAll(ctx context.Context, userID, exceptSessionID, reason string) error
+}
+
+type sessionService struct {
+	sessionRepo repository.SessionRepository
+	userRepo    repository.UserRepository
+}
+
+func NewSessionService(sessionRepo repository.SessionRepository, userRepo repository.UserRepository) SessionService {
+	return &sessionService{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// GetByUserID lists the user's active sessions, flagging currentSessionID.
+// The user lookup goes through the request's company scope, so admins only
+// see sessions of users in their companies.
+func (s *sessionService) GetByUserID(ctx context.Context, userID, currentSessionID string) ([]dto.SessionResponse, error) {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	sessions, err := s.sessionRepo.FindActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to fetch sessions")
+	}
+	return dto.ToSessionResponses(sessions, currentSessionID), nil
+}
+
+func (s *sessionService) Revoke(ctx context.Context, userID, sessionID, reason string) error {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return errors.New("user not found")
+	}
+
+	session, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil || session.UserID != userID {
+		return errors.New("session not found")
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+
+	if err := s.sessionRepo.Revoke(ctx, sessionID, reason); err != nil {
+		return errors.New("failed to revoke session")
+	}
+	return nil
+}
+
+// RevokeAll revokes every session of the user except exceptSessionID, which
+// may be empty
+func (s *sessionService) RevokeAll(ctx context.Context, userID, exceptSessionID, reason string) error {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return errors.New("user not found")
+	}
+
+	if err := s.sessionRepo.RevokeAllForUser(ctx, userID, exceptSessionID, reason); err != nil {
+		return errors.New("failed to revoke sessions")
+	}
+	return nil
+}
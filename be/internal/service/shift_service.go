@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -10,12 +11,12 @@ import (
 )
 
 type ShiftService interface {
-	GetAll() ([]dto.ShiftResponse, error)
-	GetByID(id string) (*dto.ShiftResponse, error)
-	GetByCompanyID(companyID string) ([]dto.ShiftResponse, error)
-	Create(req dto.CreateShiftRequest) (*dto.ShiftResponse, error)
-	Update(id string, req dto.UpdateShiftRequest) (*dto.ShiftResponse, error)
-	Delete(id string) error
+	GetAll(ctx context.Context) ([]dto.ShiftResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.ShiftResponse, error)
+	GetByCompanyID(ctx context.Context, companyID string) ([]dto.ShiftResponse, error)
+	Create(ctx context.Context, req dto.CreateShiftRequest) (*dto.ShiftResponse, error)
+	Update(ctx context.Context, id string, req dto.UpdateShiftRequest) (*dto.ShiftResponse, error)
+	Delete(ctx context.Context, id string) error
 }
 
 type shiftService struct {
@@ -30,16 +31,16 @@ func NewShiftService(shiftRepo repository.ShiftRepository, companyRepo repositor
 	}
 }
 
-func (s *shiftService) GetAll() ([]dto.ShiftResponse, error) {
-	shifts, err := s.shiftRepo.FindAll()
+func (s *shiftService) GetAll(ctx context.Context) ([]dto.ShiftResponse, error) {
+	shifts, err := s.shiftRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToShiftResponses(shifts), nil
 }
 
-func (s *shiftService) GetByID(id string) (*dto.ShiftResponse, error) {
-	shift, err := s.shiftRepo.FindByID(id)
+func (s *shiftService) GetByID(ctx context.Context, id string) (*dto.ShiftResponse, error) {
+	shift, err := s.shiftRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("shift not found")
 	}
@@ -47,16 +48,16 @@ func (s *shiftService) GetByID(id string) (*dto.ShiftResponse, error) {
 	return &response, nil
 }
 
-func (s *shiftService) GetByCompanyID(companyID string) ([]dto.ShiftResponse, error) {
-	shifts, err := s.shiftRepo.FindByCompanyID(companyID)
+func (s *shiftService) GetByCompanyID(ctx context.Context, companyID string) ([]dto.ShiftResponse, error) {
+	shifts, err := s.shiftRepo.FindByCompanyID(ctx, companyID)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToShiftResponses(shifts), nil
 }
 
-func (s *shiftService) Create(req dto.CreateShiftRequest) (*dto.ShiftResponse, error) {
-	_, err := s.companyRepo.FindByID(req.CompanyID)
+func (s *shiftService) Create(ctx context.Context, req dto.CreateShiftRequest) (*dto.ShiftResponse, error) {
+	_, err := s.companyRepo.FindByID(ctx, req.CompanyID)
 	if err != nil {
 		return nil, errors.New("company not found")
 	}
@@ -71,19 +72,29 @@ func (s *shiftService) Create(req dto.CreateShiftRequest) (*dto.ShiftResponse, e
 		return nil, errors.New("invalid end_time format, use HH:mm")
 	}
 
+	workDays := defaultWorkDays
+	if req.WorkDays != "" {
+		normalized, err := normalizeWorkDays(req.WorkDays)
+		if err != nil {
+			return nil, err
+		}
+		workDays = normalized
+	}
+
 	shift := &model.Shift{
 		CompanyID: req.CompanyID,
 		Name:      req.Name,
 		StartTime: req.StartTime,
 		EndTime:   req.EndTime,
+		WorkDays:  workDays,
 		IsActive:  true,
 	}
 
-	if err := s.shiftRepo.Create(shift); err != nil {
+	if err := s.shiftRepo.Create(ctx, shift); err != nil {
 		return nil, errors.New("failed to create shift")
 	}
 
-	created, err := s.shiftRepo.FindByID(shift.ID)
+	created, err := s.shiftRepo.FindByID(ctx, shift.ID)
 	if err != nil {
 		return nil, errors.New("failed to load shift")
 	}
@@ -92,8 +103,8 @@ func (s *shiftService) Create(req dto.CreateShiftRequest) (*dto.ShiftResponse, e
 	return &response, nil
 }
 
-func (s *shiftService) Update(id string, req dto.UpdateShiftRequest) (*dto.ShiftResponse, error) {
-	shift, err := s.shiftRepo.FindByID(id)
+func (s *shiftService) Update(ctx context.Context, id string, req dto.UpdateShiftRequest) (*dto.ShiftResponse, error) {
+	shift, err := s.shiftRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("shift not found")
 	}
@@ -118,11 +129,19 @@ func (s *shiftService) Update(id string, req dto.UpdateShiftRequest) (*dto.Shift
 		shift.EndTime = req.EndTime
 	}
 
+	if req.WorkDays != "" {
+		workDays, err := normalizeWorkDays(req.WorkDays)
+		if err != nil {
+			return nil, err
+		}
+		shift.WorkDays = workDays
+	}
+
 	if req.IsActive != nil {
 		shift.IsActive = *req.IsActive
 	}
 
-	if err := s.shiftRepo.Update(shift); err != nil {
+	if err := s.shiftRepo.Update(ctx, shift); err != nil {
 		return nil, errors.New("failed to update shift")
 	}
 
@@ -130,10 +149,10 @@ func (s *shiftService) Update(id string, req dto.UpdateShiftRequest) (*dto.Shift
 	return &response, nil
 }
 
-func (s *shiftService) Delete(id string) error {
-	_, err := s.shiftRepo.FindByID(id)
+func (s *shiftService) Delete(ctx context.Context, id string) error {
+	_, err := s.shiftRepo.FindByID(ctx, id)
 	if err != nil {
 		return errors.New("shift not found")
 	}
-	return s.shiftRepo.Delete(id)
+	return s.shiftRepo.Delete(ctx, id)
 }
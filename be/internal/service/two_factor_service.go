@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"hris-backend/config"
+	"hris-backend/internal/dto"
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+	"hris-backend/pkg/hash"
+	"hris-backend/pkg/totp"
+)
+
+const recoveryCodeCount = 10
+
+type TwoFactorService interface {
+	GetStatus(ctx context.Context, userID, role string) (*dto.TwoFactorStatusResponse, error)
+	Setup(ctx context.Context, userID string) (*dto.TwoFactorSetupResponse, error)
+	Enable(ctx context.Context, userID string, req dto.TwoFactorCodeRequest) (*dto.RecoveryCodesResponse, error)
+	Disable(ctx context.Context, userID, role string, req dto.DisableTwoFactorRequest) error
+	RegenerateRecoveryCodes(ctx context.Context, userID string, req dto.TwoFactorCodeRequest) (*dto.RecoveryCodesResponse, error)
+	Reset(ctx context.Context, actorRole, userID string) error
+}
+
+type twoFactorService struct {
+	twoFactorRepo repository.TwoFactorRepository
+	userRepo      repository.UserRepository
+	cfg           *config.Config
+}
+
+func NewTwoFactorService(twoFactorRepo repository.TwoFactorRepository, userRepo repository.UserRepository, cfg *config.Config) TwoFactorService {
+	return &twoFactorService{
+		twoFactorRepo: twoFactorRepo,
+		userRepo:      userRepo,
+		cfg:           cfg,
+	}
+}
+
+func (s *twoFactorService) GetStatus(ctx context.Context, userID, role string) (*dto.TwoFactorStatusResponse, error) {
+	status := &dto.TwoFactorStatusResponse{Required: twoFactorRequired(s.cfg, role)}
+
+	tf, err := s.twoFactorRepo.FindByUserID(ctx, userID)
+	if err != nil || !tf.Enabled {
+		return status, nil
+	}
+
+	status.Enabled = true
+	status.RecoveryCodesLeft, err = s.twoFactorRepo.CountRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to count recovery codes")
+	}
+	return status, nil
+}
+
+// Setup generates a new pending secret for the user. It replaces any earlier
+// pending secret and takes effect once confirmed with Enable.
+func (s *twoFactorService) Setup(ctx context.Context, userID string) (*dto.TwoFactorSetupResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	tf, err := s.twoFactorRepo.FindByUserID(ctx, userID)
+	if err == nil && tf.Enabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+	if err != nil {
+		tf = &model.UserTwoFactor{UserID: userID}
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.New("failed to generate secret")
+	}
+	tf.Secret = secret
+	tf.LastUsedStep = 0
+
+	if err := s.twoFactorRepo.Save(ctx, tf); err != nil {
+		return nil, errors.New("failed to save two-factor secret")
+	}
+
+	return &dto.TwoFactorSetupResponse{
+		Secret: secret,
+		URI:    totp.URI(s.cfg.TwoFactorIssuer, user.Email, secret),
+	}, nil
+}
+
+// Enable confirms the pending secret with a code from the authenticator and
+// returns the recovery codes, which are shown only this once
+func (s *twoFactorService) Enable(ctx context.Context, userID string, req dto.TwoFactorCodeRequest) (*dto.RecoveryCodesResponse, error) {
+	tf, err := s.twoFactorRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("two-factor setup has not been started")
+	}
+	if tf.Enabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	if !s.checkTOTP(ctx, tf, req.Code) {
+		return nil, errors.New("invalid code")
+	}
+
+	now := time.Now()
+	tf.Enabled = true
+	tf.EnabledAt = &now
+	if err := s.twoFactorRepo.Save(ctx, tf); err != nil {
+		return nil, errors.New("failed to enable two-factor authentication")
+	}
+
+	return s.issueRecoveryCodes(ctx, userID)
+}
+
+// Disable turns 2FA off after checking the password and a current code.
+// Users whose role requires 2FA cannot turn it off.
+func (s *twoFactorService) Disable(ctx context.Context, userID, role string, req dto.DisableTwoFactorRequest) error {
+	if twoFactorRequired(s.cfg, role) {
+		return errors.New("two-factor authentication is required for your role")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if !hash.CheckPassword(req.Password, user.Password) {
+		return errors.New("password is incorrect")
+	}
+
+	tf, err := s.twoFactorRepo.FindByUserID(ctx, userID)
+	if err != nil || !tf.Enabled {
+		return errors.New("two-factor authentication is not enabled")
+	}
+	if !checkSecondFactor(ctx, s.twoFactorRepo, tf, req.Code) {
+		return errors.New("invalid code")
+	}
+
+	if err := s.twoFactorRepo.Delete(ctx, userID); err != nil {
+		return errors.New("failed to disable two-factor authentication")
+	}
+	return nil
+}
+
+func (s *twoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID string, req dto.TwoFactorCodeRequest) (*dto.RecoveryCodesResponse, error) {
+	tf, err := s.twoFactorRepo.FindByUserID(ctx, userID)
+	if err != nil || !tf.Enabled {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+	if !s.checkTOTP(ctx, tf, req.Code) {
+		return nil, errors.New("invalid code")
+	}
+
+	return s.issueRecoveryCodes(ctx, userID)
+}
+
+// Reset removes another user's 2FA so they can enroll again, e.g. after
+// losing both their authenticator and recovery codes. Only superadmins may
+// reset a superadmin.
+func (s *twoFactorService) Reset(ctx context.Context, actorRole, userID string) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if user.Role == model.RoleSuperAdmin && actorRole != string(model.RoleSuperAdmin) {
+		return errors.New("only a superadmin can reset a superadmin's two-factor authentication")
+	}
+
+	if err := s.twoFactorRepo.Delete(ctx, userID); err != nil {
+		return errors.New("failed to reset two-factor authentication")
+	}
+	return nil
+}
+
+func (s *twoFactorService) checkTOTP(ctx context.Context, tf *model.UserTwoFactor, code string) bool {
+	step, ok := totp.Validate(tf.Secret, code, time.Now())
+	if !ok {
+		return false
+	}
+	used, err := s.twoFactorRepo.MarkStepUsed(ctx, tf.UserID, step)
+	return err == nil && used
+}
+
+func (s *twoFactorService) issueRecoveryCodes(ctx context.Context, userID string) (*dto.RecoveryCodesResponse, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, errors.New("failed to generate recovery codes")
+		}
+		codes[i] = code
+		hashes[i] = hash.HashToken(normalizeRecoveryCode(code))
+	}
+
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, errors.New("failed to save recovery codes")
+	}
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// checkSecondFactor accepts a TOTP code of the user's enabled secret, or one
+// of their unused recovery codes, consuming it
+func checkSecondFactor(ctx context.Context, repo repository.TwoFactorRepository, tf *model.UserTwoFactor, code string) bool {
+	if step, ok := totp.Validate(tf.Secret, code, time.Now()); ok {
+		used, err := repo.MarkStepUsed(ctx, tf.UserID, step)
+		return err == nil && used
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return false
+	}
+	used, err := repo.UseRecoveryCode(ctx, tf.UserID, hash.HashToken(normalized))
+	return err == nil && used
+}
+
+// twoFactorRequired reports whether the policy makes 2FA mandatory for role
+func twoFactorRequired(cfg *config.Config, role string) bool {
+	for _, r := range cfg.TwoFactorRequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateRecoveryCode returns a code like "k3fq-2mzd"
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(recoveryEncoding.EncodeToString(b))
+	return code[:4] + "-" + code[4:], nil
+}
+
+// normalizeRecoveryCode drops separators and case so codes can be typed
+// loosely
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
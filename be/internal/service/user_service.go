@@ -1,40 +1,47 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"hris-backend/internal/dto"
 	"hris-backend/internal/model"
 	"hris-backend/internal/repository"
+	"hris-backend/internal/tenant"
 	"hris-backend/pkg/hash"
 )
 
 type UserService interface {
-	GetAll() ([]dto.UserResponse, error)
-	GetByID(id string) (*dto.UserResponse, error)
-	Create(req dto.CreateUserRequest) (*dto.UserResponse, error)
-	Update(id string, req dto.UpdateUserRequest) (*dto.UserResponse, error)
-	Delete(id string) error
+	GetAll(ctx context.Context) ([]dto.UserResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.UserResponse, error)
+	Create(ctx context.Context, actor Actor, req dto.CreateUserRequest) (*dto.UserResponse, error)
+	Update(ctx context.Context, actor Actor, id string, req dto.UpdateUserRequest) (*dto.UserResponse, error)
+	Delete(ctx context.Context, actor Actor, id string) error
+	CheckManageable(ctx context.Context, id string) error
 }
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo       repository.UserRepository
+	customRoleRepo repository.CustomRoleRepository
 }
 
-func NewUserService(userRepo repository.UserRepository) UserService {
-	return &userService{userRepo: userRepo}
+func NewUserService(userRepo repository.UserRepository, customRoleRepo repository.CustomRoleRepository) UserService {
+	return &userService{
+		userRepo:       userRepo,
+		customRoleRepo: customRoleRepo,
+	}
 }
 
-func (s *userService) GetAll() ([]dto.UserResponse, error) {
-	users, err := s.userRepo.FindAll()
+func (s *userService) GetAll(ctx context.Context) ([]dto.UserResponse, error) {
+	users, err := s.userRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToUserResponses(users), nil
 }
 
-func (s *userService) GetByID(id string) (*dto.UserResponse, error) {
-	user, err := s.userRepo.FindByID(id)
+func (s *userService) GetByID(ctx context.Context, id string) (*dto.UserResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("user not found")
 	}
@@ -42,28 +49,50 @@ func (s *userService) GetByID(id string) (*dto.UserResponse, error) {
 	return &response, nil
 }
 
-func (s *userService) Create(req dto.CreateUserRequest) (*dto.UserResponse, error) {
-	existing, _ := s.userRepo.FindByEmail(req.Email)
+// Create adds a user. The actor cannot grant a role above their own or a
+// custom role with permissions they do not have.
+func (s *userService) Create(ctx context.Context, actor Actor, req dto.CreateUserRequest) (*dto.UserResponse, error) {
+	if err := actor.checkRole(req.Role); err != nil {
+		return nil, err
+	}
+
+	existing, _ := s.userRepo.FindByEmail(ctx, req.Email)
 	if existing != nil {
 		return nil, errors.New("email already exists")
 	}
 
+	if req.Language == "" {
+		req.Language = model.LanguageIndonesian
+	}
+	if !validLanguage(req.Language) {
+		return nil, errors.New("language must be id or en")
+	}
+
 	hashedPassword, err := hash.HashPassword(req.Password)
 	if err != nil {
 		return nil, errors.New("failed to hash password")
 	}
 
 	user := &model.User{
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: hashedPassword,
-		Role:     req.Role,
-		Phone:    req.Phone,
-		Address:  req.Address,
-		IsActive: true,
+		Name:               req.Name,
+		Email:              req.Email,
+		Password:           hashedPassword,
+		Role:               req.Role,
+		Phone:              req.Phone,
+		Address:            req.Address,
+		Language:           req.Language,
+		IsActive:           true,
+		MustChangePassword: req.MustChangePassword,
+	}
+
+	if req.CustomRoleID != "" {
+		if err := s.checkCustomRole(ctx, actor, req.CustomRoleID); err != nil {
+			return nil, err
+		}
+		user.CustomRoleID = &req.CustomRoleID
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, errors.New("failed to create user")
 	}
 
@@ -71,17 +100,23 @@ func (s *userService) Create(req dto.CreateUserRequest) (*dto.UserResponse, erro
 	return &response, nil
 }
 
-func (s *userService) Update(id string, req dto.UpdateUserRequest) (*dto.UserResponse, error) {
-	user, err := s.userRepo.FindByID(id)
+// Update changes a user. The actor cannot change a user whose role is above
+// their own, nor grant a role or custom role they could not create a user
+// with.
+func (s *userService) Update(ctx context.Context, actor Actor, id string, req dto.UpdateUserRequest) (*dto.UserResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("user not found")
 	}
+	if err := actor.checkRole(user.Role); err != nil {
+		return nil, errors.New("cannot change a user whose role is above your own")
+	}
 
 	if req.Name != "" {
 		user.Name = req.Name
 	}
 	if req.Email != "" {
-		existing, _ := s.userRepo.FindByEmail(req.Email)
+		existing, _ := s.userRepo.FindByEmail(ctx, req.Email)
 		if existing != nil && existing.ID != id {
 			return nil, errors.New("email already exists")
 		}
@@ -95,6 +130,9 @@ func (s *userService) Update(id string, req dto.UpdateUserRequest) (*dto.UserRes
 		user.Password = hashedPassword
 	}
 	if req.Role != "" {
+		if err := actor.checkRole(req.Role); err != nil {
+			return nil, err
+		}
 		user.Role = req.Role
 	}
 	if req.Phone != "" {
@@ -103,11 +141,30 @@ func (s *userService) Update(id string, req dto.UpdateUserRequest) (*dto.UserRes
 	if req.Address != "" {
 		user.Address = req.Address
 	}
+	if req.Language != "" {
+		if !validLanguage(req.Language) {
+			return nil, errors.New("language must be id or en")
+		}
+		user.Language = req.Language
+	}
 	if req.IsActive != nil {
 		user.IsActive = *req.IsActive
 	}
+	if req.MustChangePassword != nil {
+		user.MustChangePassword = *req.MustChangePassword
+	}
+	if req.CustomRoleID != nil {
+		// An empty ID removes the custom role
+		user.CustomRoleID = nil
+		if *req.CustomRoleID != "" {
+			if err := s.checkCustomRole(ctx, actor, *req.CustomRoleID); err != nil {
+				return nil, err
+			}
+			user.CustomRoleID = req.CustomRoleID
+		}
+	}
 
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, errors.New("failed to update user")
 	}
 
@@ -115,10 +172,61 @@ func (s *userService) Update(id string, req dto.UpdateUserRequest) (*dto.UserRes
 	return &response, nil
 }
 
-func (s *userService) Delete(id string) error {
-	_, err := s.userRepo.FindByID(id)
+// Delete removes a user. The actor cannot delete a user whose role is above
+// their own.
+func (s *userService) Delete(ctx context.Context, actor Actor, id string) error {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if err := actor.checkRole(user.Role); err != nil {
+		return errors.New("cannot delete a user whose role is above your own")
+	}
+	return s.userRepo.Delete(ctx, id)
+}
+
+// checkCustomRole returns an error unless the custom role exists and the
+// actor holds every permission it grants
+func (s *userService) checkCustomRole(ctx context.Context, actor Actor, id string) error {
+	role, err := s.customRoleRepo.FindByID(ctx, id)
+	if err != nil {
+		return errors.New("custom role not found")
+	}
+	names := make([]string, len(role.Permissions))
+	for i, p := range role.Permissions {
+		names[i] = p.Name
+	}
+	return actor.checkPermissions(names)
+}
+
+// CheckManageable returns an error unless the request may change the user,
+// their sessions, 2FA, lockout or API tokens. Superadmins, whose requests are
+// not scoped, may change anyone; others only users that belong to one of
+// their companies, and no superadmin.
+func (s *userService) CheckManageable(ctx context.Context, id string) error {
+	if tenant.FromContext(ctx) == nil {
+		return nil
+	}
+
+	user, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
 		return errors.New("user not found")
 	}
-	return s.userRepo.Delete(id)
+	if user.Role == model.RoleSuperAdmin {
+		return errors.New("only a superadmin can manage a superadmin")
+	}
+
+	inScope, err := s.userRepo.IsInScope(ctx, id)
+	if err != nil {
+		return errors.New("failed to check user companies")
+	}
+	if !inScope {
+		return errors.New("user does not belong to any of your companies")
+	}
+	return nil
+}
+
+// validLanguage reports whether email can be sent in lang
+func validLanguage(lang string) bool {
+	return lang == model.LanguageIndonesian || lang == model.LanguageEnglish
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -16,18 +17,18 @@ import (
 const MinVisitsPerDayDefault = 5
 
 type VisitPlanService interface {
-	Create(userID string, req dto.CreateVisitPlanRequest) (*dto.VisitPlanResponse, error)
-	Update(id string, req dto.UpdateVisitPlanRequest) (*dto.VisitPlanResponse, error)
-	GetByID(id string) (*dto.VisitPlanResponse, error)
-	GetByEmployeeAndDate(employeeID string, date time.Time) (*dto.VisitPlanResponse, error)
-	ListByEmployee(employeeID string, from, to *time.Time) ([]dto.VisitPlanResponse, error)
-	Delete(id string) error
+	Create(ctx context.Context, userID string, req dto.CreateVisitPlanRequest) (*dto.VisitPlanResponse, error)
+	Update(ctx context.Context, id string, req dto.UpdateVisitPlanRequest) (*dto.VisitPlanResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.VisitPlanResponse, error)
+	GetByEmployeeAndDate(ctx context.Context, employeeID string, date time.Time) (*dto.VisitPlanResponse, error)
+	ListByEmployee(ctx context.Context, employeeID string, from, to *time.Time) ([]dto.VisitPlanResponse, error)
+	Delete(ctx context.Context, id string) error
 
-	AddItem(planID string, req dto.AddVisitPlanItemRequest) (*dto.VisitPlanItemResponse, error)
-	UpdateItem(id string, req dto.UpdateVisitPlanItemRequest) (*dto.VisitPlanItemResponse, error)
-	DeleteItem(id string) error
+	AddItem(ctx context.Context, planID string, req dto.AddVisitPlanItemRequest) (*dto.VisitPlanItemResponse, error)
+	UpdateItem(ctx context.Context, id string, req dto.UpdateVisitPlanItemRequest) (*dto.VisitPlanItemResponse, error)
+	DeleteItem(ctx context.Context, id string) error
 
-	AdherenceReport(companyID string, date time.Time, minimum int) (*dto.VisitAdherenceReport, error)
+	AdherenceReport(ctx context.Context, companyID string, date time.Time, minimum int) (*dto.VisitAdherenceReport, error)
 }
 
 type visitPlanService struct {
@@ -44,19 +45,19 @@ func NewVisitPlanService(
 	return &visitPlanService{repo, empRepo, visitRepo}
 }
 
-func (s *visitPlanService) Create(userID string, req dto.CreateVisitPlanRequest) (*dto.VisitPlanResponse, error) {
+func (s *visitPlanService) Create(ctx context.Context, userID string, req dto.CreateVisitPlanRequest) (*dto.VisitPlanResponse, error) {
 	planDate, err := time.Parse("2006-01-02", req.PlanDate)
 	if err != nil {
 		return nil, errors.New("invalid plan_date (YYYY-MM-DD)")
 	}
 
-	emp, err := s.empRepo.FindByID(req.EmployeeID)
+	emp, err := s.empRepo.FindByID(ctx, req.EmployeeID)
 	if err != nil {
 		return nil, errors.New("employee not found")
 	}
 
 	// Enforce one plan per (employee, date).
-	if existing, _ := s.repo.FindByEmployeeAndDate(req.EmployeeID, planDate); existing != nil {
+	if existing, _ := s.repo.FindByEmployeeAndDate(ctx, req.EmployeeID, planDate); existing != nil {
 		return nil, errors.New("a plan already exists for this employee on this date")
 	}
 
@@ -73,7 +74,7 @@ func (s *visitPlanService) Create(userID string, req dto.CreateVisitPlanRequest)
 		Notes:      req.Notes,
 		CreatedBy:  userID,
 	}
-	if err := s.repo.Create(p); err != nil {
+	if err := s.repo.Create(ctx, p); err != nil {
 		return nil, errors.New("failed to create plan")
 	}
 
@@ -87,7 +88,7 @@ func (s *visitPlanService) Create(userID string, req dto.CreateVisitPlanRequest)
 			SequenceOrder: it.SequenceOrder,
 			Status:        "pending",
 		}
-		if err := s.repo.CreateItem(item); err != nil {
+		if err := s.repo.CreateItem(ctx, item); err != nil {
 			return nil, errors.New("failed to create plan item")
 		}
 		p.Items = append(p.Items, *item)
@@ -97,8 +98,8 @@ func (s *visitPlanService) Create(userID string, req dto.CreateVisitPlanRequest)
 	return &resp, nil
 }
 
-func (s *visitPlanService) Update(id string, req dto.UpdateVisitPlanRequest) (*dto.VisitPlanResponse, error) {
-	p, err := s.repo.FindByID(id)
+func (s *visitPlanService) Update(ctx context.Context, id string, req dto.UpdateVisitPlanRequest) (*dto.VisitPlanResponse, error) {
+	p, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("plan not found")
 	}
@@ -108,15 +109,15 @@ func (s *visitPlanService) Update(id string, req dto.UpdateVisitPlanRequest) (*d
 	if req.Status != nil {
 		p.Status = *req.Status
 	}
-	if err := s.repo.Update(p); err != nil {
+	if err := s.repo.Update(ctx, p); err != nil {
 		return nil, errors.New("failed to update plan")
 	}
 	resp := dto.ToVisitPlanResponse(p)
 	return &resp, nil
 }
 
-func (s *visitPlanService) GetByID(id string) (*dto.VisitPlanResponse, error) {
-	p, err := s.repo.FindByID(id)
+func (s *visitPlanService) GetByID(ctx context.Context, id string) (*dto.VisitPlanResponse, error) {
+	p, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("plan not found")
 	}
@@ -124,8 +125,8 @@ func (s *visitPlanService) GetByID(id string) (*dto.VisitPlanResponse, error) {
 	return &resp, nil
 }
 
-func (s *visitPlanService) GetByEmployeeAndDate(employeeID string, date time.Time) (*dto.VisitPlanResponse, error) {
-	p, err := s.repo.FindByEmployeeAndDate(employeeID, date)
+func (s *visitPlanService) GetByEmployeeAndDate(ctx context.Context, employeeID string, date time.Time) (*dto.VisitPlanResponse, error) {
+	p, err := s.repo.FindByEmployeeAndDate(ctx, employeeID, date)
 	if err != nil {
 		return nil, errors.New("plan not found")
 	}
@@ -133,23 +134,23 @@ func (s *visitPlanService) GetByEmployeeAndDate(employeeID string, date time.Tim
 	return &resp, nil
 }
 
-func (s *visitPlanService) ListByEmployee(employeeID string, from, to *time.Time) ([]dto.VisitPlanResponse, error) {
-	ps, err := s.repo.ListByEmployee(employeeID, from, to)
+func (s *visitPlanService) ListByEmployee(ctx context.Context, employeeID string, from, to *time.Time) ([]dto.VisitPlanResponse, error) {
+	ps, err := s.repo.ListByEmployee(ctx, employeeID, from, to)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToVisitPlanResponses(ps), nil
 }
 
-func (s *visitPlanService) Delete(id string) error {
-	if _, err := s.repo.FindByID(id); err != nil {
+func (s *visitPlanService) Delete(ctx context.Context, id string) error {
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
 		return errors.New("plan not found")
 	}
-	return s.repo.Delete(id)
+	return s.repo.Delete(ctx, id)
 }
 
-func (s *visitPlanService) AddItem(planID string, req dto.AddVisitPlanItemRequest) (*dto.VisitPlanItemResponse, error) {
-	if _, err := s.repo.FindByID(planID); err != nil {
+func (s *visitPlanService) AddItem(ctx context.Context, planID string, req dto.AddVisitPlanItemRequest) (*dto.VisitPlanItemResponse, error) {
+	if _, err := s.repo.FindByID(ctx, planID); err != nil {
 		return nil, errors.New("plan not found")
 	}
 	item := &model.VisitPlanItem{
@@ -161,15 +162,15 @@ func (s *visitPlanService) AddItem(planID string, req dto.AddVisitPlanItemReques
 		SequenceOrder: req.SequenceOrder,
 		Status:        "pending",
 	}
-	if err := s.repo.CreateItem(item); err != nil {
+	if err := s.repo.CreateItem(ctx, item); err != nil {
 		return nil, errors.New("failed to add item")
 	}
 	resp := dto.ToVisitPlanItemResponse(item)
 	return &resp, nil
 }
 
-func (s *visitPlanService) UpdateItem(id string, req dto.UpdateVisitPlanItemRequest) (*dto.VisitPlanItemResponse, error) {
-	item, err := s.repo.FindItemByID(id)
+func (s *visitPlanService) UpdateItem(ctx context.Context, id string, req dto.UpdateVisitPlanItemRequest) (*dto.VisitPlanItemResponse, error) {
+	item, err := s.repo.FindItemByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("item not found")
 	}
@@ -191,18 +192,18 @@ func (s *visitPlanService) UpdateItem(id string, req dto.UpdateVisitPlanItemRequ
 	if req.Status != nil {
 		item.Status = *req.Status
 	}
-	if err := s.repo.UpdateItem(item); err != nil {
+	if err := s.repo.UpdateItem(ctx, item); err != nil {
 		return nil, errors.New("failed to update item")
 	}
 	resp := dto.ToVisitPlanItemResponse(item)
 	return &resp, nil
 }
 
-func (s *visitPlanService) DeleteItem(id string) error {
-	if _, err := s.repo.FindItemByID(id); err != nil {
+func (s *visitPlanService) DeleteItem(ctx context.Context, id string) error {
+	if _, err := s.repo.FindItemByID(ctx, id); err != nil {
 		return errors.New("item not found")
 	}
-	return s.repo.DeleteItem(id)
+	return s.repo.DeleteItem(ctx, id)
 }
 
 // AdherenceReport returns planned-vs-actual visit counts per employee for one
@@ -212,13 +213,13 @@ func (s *visitPlanService) DeleteItem(id string) error {
 //   - Matched: actual visits whose VisitPlanItemID links back into the plan
 //
 // under_minimum flags employees whose actual_count is below `minimum`.
-func (s *visitPlanService) AdherenceReport(companyID string, date time.Time, minimum int) (*dto.VisitAdherenceReport, error) {
+func (s *visitPlanService) AdherenceReport(ctx context.Context, companyID string, date time.Time, minimum int) (*dto.VisitAdherenceReport, error) {
 	if minimum <= 0 {
 		minimum = MinVisitsPerDayDefault
 	}
 
 	// 1. Plans for the day, keyed by employee.
-	plans, err := s.repo.FindByCompanyAndDate(companyID, date)
+	plans, err := s.repo.FindByCompanyAndDate(ctx, companyID, date)
 	if err != nil {
 		return nil, err
 	}
@@ -230,7 +231,7 @@ func (s *visitPlanService) AdherenceReport(companyID string, date time.Time, min
 	// 2. Actual visits for the day via the visits repo.
 	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	to := from.Add(24*time.Hour - time.Second)
-	visits, _, err := s.visitRepo.FindPaginated(1, 10000, "", companyID, &from, &to)
+	visits, _, err := s.visitRepo.FindPaginated(ctx, 1, 10000, "", companyID, &from, &to)
 	if err != nil {
 		return nil, err
 	}
@@ -274,7 +275,7 @@ func (s *visitPlanService) AdherenceReport(companyID string, date time.Time, min
 			matched = a.matched
 		}
 		name := ""
-		if emp, err := s.empRepo.FindByID(id); err == nil && emp != nil {
+		if emp, err := s.empRepo.FindByID(ctx, id); err == nil && emp != nil {
 			name = emp.User.Name
 		}
 		rows = append(rows, dto.VisitAdherenceRow{
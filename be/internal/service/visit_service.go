@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"time"
@@ -11,12 +12,12 @@ import (
 )
 
 type VisitService interface {
-	Start(employeeID string, req dto.StartVisitRequest) (*dto.VisitResponse, error)
-	End(id string, actorEmployeeID string, req dto.EndVisitRequest) (*dto.VisitResponse, error)
-	GetByID(id string) (*dto.VisitResponse, error)
-	GetByAttendanceID(attendanceID string) ([]dto.VisitResponse, error)
-	List(page, limit int, employeeID, companyID string, from, to *time.Time) (*dto.PaginatedVisitResponse, error)
-	Delete(id string) error
+	Start(ctx context.Context, employeeID string, req dto.StartVisitRequest) (*dto.VisitResponse, error)
+	End(ctx context.Context, id string, actorEmployeeID string, req dto.EndVisitRequest) (*dto.VisitResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.VisitResponse, error)
+	GetByAttendanceID(ctx context.Context, attendanceID string) ([]dto.VisitResponse, error)
+	List(ctx context.Context, page, limit int, employeeID, companyID string, from, to *time.Time) (*dto.PaginatedVisitResponse, error)
+	Delete(ctx context.Context, id string) error
 }
 
 type visitService struct {
@@ -29,9 +30,9 @@ func NewVisitService(repo repository.VisitRepository, attRepo repository.Attenda
 	return &visitService{repo, attRepo, empRepo}
 }
 
-func (s *visitService) Start(employeeID string, req dto.StartVisitRequest) (*dto.VisitResponse, error) {
+func (s *visitService) Start(ctx context.Context, employeeID string, req dto.StartVisitRequest) (*dto.VisitResponse, error) {
 	// Validate attendance exists and belongs to the acting employee
-	att, err := s.attRepo.FindByID(req.AttendanceID)
+	att, err := s.attRepo.FindByID(ctx, req.AttendanceID)
 	if err != nil {
 		return nil, errors.New("attendance not found")
 	}
@@ -46,7 +47,7 @@ func (s *visitService) Start(employeeID string, req dto.StartVisitRequest) (*dto
 	}
 
 	// Derive company_id from the employee
-	emp, err := s.empRepo.FindByID(employeeID)
+	emp, err := s.empRepo.FindByID(ctx, employeeID)
 	if err != nil {
 		return nil, errors.New("employee not found")
 	}
@@ -64,15 +65,15 @@ func (s *visitService) Start(employeeID string, req dto.StartVisitRequest) (*dto
 		Photos:          strings.Join(req.Photos, ","),
 		VisitPlanItemID: req.VisitPlanItemID,
 	}
-	if err := s.repo.Create(v); err != nil {
+	if err := s.repo.Create(ctx, v); err != nil {
 		return nil, errors.New("failed to create visit")
 	}
 	resp := dto.ToVisitResponse(v)
 	return &resp, nil
 }
 
-func (s *visitService) End(id string, actorEmployeeID string, req dto.EndVisitRequest) (*dto.VisitResponse, error) {
-	v, err := s.repo.FindByID(id)
+func (s *visitService) End(ctx context.Context, id string, actorEmployeeID string, req dto.EndVisitRequest) (*dto.VisitResponse, error) {
+	v, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("visit not found")
 	}
@@ -96,15 +97,15 @@ func (s *visitService) End(id string, actorEmployeeID string, req dto.EndVisitRe
 		}
 	}
 
-	if err := s.repo.Update(v); err != nil {
+	if err := s.repo.Update(ctx, v); err != nil {
 		return nil, errors.New("failed to end visit")
 	}
 	resp := dto.ToVisitResponse(v)
 	return &resp, nil
 }
 
-func (s *visitService) GetByID(id string) (*dto.VisitResponse, error) {
-	v, err := s.repo.FindByID(id)
+func (s *visitService) GetByID(ctx context.Context, id string) (*dto.VisitResponse, error) {
+	v, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("visit not found")
 	}
@@ -112,16 +113,16 @@ func (s *visitService) GetByID(id string) (*dto.VisitResponse, error) {
 	return &resp, nil
 }
 
-func (s *visitService) GetByAttendanceID(attendanceID string) ([]dto.VisitResponse, error) {
-	vs, err := s.repo.FindByAttendanceID(attendanceID)
+func (s *visitService) GetByAttendanceID(ctx context.Context, attendanceID string) ([]dto.VisitResponse, error) {
+	vs, err := s.repo.FindByAttendanceID(ctx, attendanceID)
 	if err != nil {
 		return nil, err
 	}
 	return dto.ToVisitResponses(vs), nil
 }
 
-func (s *visitService) List(page, limit int, employeeID, companyID string, from, to *time.Time) (*dto.PaginatedVisitResponse, error) {
-	rows, total, err := s.repo.FindPaginated(page, limit, employeeID, companyID, from, to)
+func (s *visitService) List(ctx context.Context, page, limit int, employeeID, companyID string, from, to *time.Time) (*dto.PaginatedVisitResponse, error) {
+	rows, total, err := s.repo.FindPaginated(ctx, page, limit, employeeID, companyID, from, to)
 	if err != nil {
 		return nil, err
 	}
@@ -138,10 +139,10 @@ func (s *visitService) List(page, limit int, employeeID, companyID string, from,
 	}, nil
 }
 
-func (s *visitService) Delete(id string) error {
-	_, err := s.repo.FindByID(id)
+func (s *visitService) Delete(ctx context.Context, id string) error {
+	_, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return errors.New("visit not found")
 	}
-	return s.repo.Delete(id)
+	return s.repo.Delete(ctx, id)
 }
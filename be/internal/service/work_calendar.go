@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"hris-backend/internal/model"
+)
+
+// defaultWorkDays is used when a shift has no work days configured
+const defaultWorkDays = "1,2,3,4,5"
+
+// workCalendar answers whether a date is a working day for one employee,
+// combining their shift's work days with the company holiday calendar.
+type workCalendar struct {
+	workDays map[time.Weekday]bool
+	holidays map[string]bool
+}
+
+func newWorkCalendar(shift *model.Shift, holidays []model.Holiday) workCalendar {
+	workDays := shift.WorkDays
+	if workDays == "" {
+		workDays = defaultWorkDays
+	}
+	days, err := parseWorkDays(workDays)
+	if err != nil {
+		days, _ = parseWorkDays(defaultWorkDays)
+	}
+
+	cal := workCalendar{
+		workDays: make(map[time.Weekday]bool, len(days)),
+		holidays: make(map[string]bool, len(holidays)),
+	}
+	for _, d := range days {
+		cal.workDays[d] = true
+	}
+	for _, h := range holidays {
+		cal.holidays[h.Date.Format("2006-01-02")] = true
+	}
+	return cal
+}
+
+func (c workCalendar) isWorkingDay(date time.Time) bool {
+	return c.workDays[date.Weekday()] && !c.holidays[date.Format("2006-01-02")]
+}
+
+// workingDates returns the working days between start and end inclusive
+func (c workCalendar) workingDates(start, end time.Time) []time.Time {
+	var dates []time.Time
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if c.isWorkingDay(d) {
+			dates = append(dates, d)
+		}
+	}
+	return dates
+}
+
+// chargeableDays counts the working days between start and end inclusive.
+// A half day at the start or end counts as 0.5 when that day is a working
+// day. A single-day leave with either flag set counts as 0.5.
+func (c workCalendar) chargeableDays(start, end time.Time, startHalfDay, endHalfDay bool) float64 {
+	total := float64(len(c.workingDates(start, end)))
+
+	if start.Equal(end) {
+		if (startHalfDay || endHalfDay) && c.isWorkingDay(start) {
+			return 0.5
+		}
+		return total
+	}
+
+	if startHalfDay && c.isWorkingDay(start) {
+		total -= 0.5
+	}
+	if endHalfDay && c.isWorkingDay(end) {
+		total -= 0.5
+	}
+	return total
+}
+
+// parseWorkDays parses a comma-separated list of ISO weekdays
+// (1 = Monday ... 7 = Sunday)
+func parseWorkDays(value string) ([]time.Weekday, error) {
+	seen := make(map[time.Weekday]bool)
+	var days []time.Weekday
+	for _, part := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 1 || n > 7 {
+			return nil, errors.New("work days must be a comma-separated list of weekdays 1 (Monday) to 7 (Sunday)")
+		}
+		day := time.Weekday(n % 7)
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
+
+// normalizeWorkDays validates a work days value and returns it sorted and
+// de-duplicated, e.g. "5,1,2" -> "1,2,5"
+func normalizeWorkDays(value string) (string, error) {
+	days, err := parseWorkDays(value)
+	if err != nil {
+		return "", err
+	}
+
+	iso := make([]int, len(days))
+	for i, d := range days {
+		iso[i] = int(d)
+		if d == time.Sunday {
+			iso[i] = 7
+		}
+	}
+	sort.Ints(iso)
+
+	parts := make([]string, len(iso))
+	for i, n := range iso {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ","), nil
+}
@@ -0,0 +1,193 @@
+package tenant
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// RegisterCallbacks installs the GORM callbacks that enforce request scopes.
+// Reads, updates and deletes of company-owned rows are filtered to the
+// scope's companies; creates and updates that would assign a row to a company
+// outside the scope fail with ErrOutOfScope.
+//
+// A row is company-owned when its table is companies, or it has a company_id
+// or an employee_id column. Users are visible when they belong to a company
+// in scope, through their employee record or a user_companies binding, when
+// they are the scope's own user, or when they belong to no company yet and
+// are neither admins nor superadmins. Updates and deletes of users only reach
+// the scope's own user and the ones that belong to a company in scope. Other
+// tables (approval steps, delegations, notifications) are reached through a
+// scoped parent or by user and are not filtered.
+func RegisterCallbacks(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:scope", scopeStatement); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:scope", scopeStatement); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:scope", scopeWrite); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:check", checkAssignment); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:scope", scopeWrite); err != nil {
+		return err
+	}
+	return callbacks.Create().Before("gorm:create").Register("tenant:check", checkAssignment)
+}
+
+// scopeStatement adds the scope filter to the WHERE clause of a statement
+func scopeStatement(db *gorm.DB) {
+	scope := FromContext(db.Statement.Context)
+	if scope == nil || db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	if condition := scopeCondition(db.Statement.Schema, scope, false); condition != nil {
+		db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{condition}})
+	}
+}
+
+// scopeWrite adds the scope filter to the WHERE clause of an update or delete
+func scopeWrite(db *gorm.DB) {
+	scope := FromContext(db.Statement.Context)
+	if scope == nil || db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	if condition := scopeCondition(db.Statement.Schema, scope, true); condition != nil {
+		db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{condition}})
+	}
+}
+
+// scopeCondition returns the filter confining a table to the scope. Writes
+// only reach users that belong to a company in scope, and the scope's own
+// user.
+func scopeCondition(s *schema.Schema, scope *Scope, write bool) clause.Expression {
+	switch {
+	case s.Table == "companies":
+		return clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: "id"}, Values: companyValues(scope)}
+	case s.Table == "users":
+		id := clause.Column{Table: clause.CurrentTable, Name: "id"}
+		member := "? IN (SELECT user_id FROM employees WHERE company_id IN ? AND deleted_at IS NULL)" +
+			" OR ? IN (SELECT user_id FROM user_companies WHERE company_id IN ?)"
+		if write {
+			return clause.Expr{
+				SQL:  "(" + member + " OR ? = ?)",
+				Vars: []interface{}{id, companyValues(scope), id, companyValues(scope), id, scope.UserID},
+			}
+		}
+		return clause.Expr{
+			SQL: "(" + member + " OR ? = ?" +
+				" OR (NOT EXISTS (SELECT 1 FROM employees WHERE employees.user_id = ? AND employees.deleted_at IS NULL)" +
+				" AND NOT EXISTS (SELECT 1 FROM user_companies WHERE user_companies.user_id = ?)" +
+				" AND ? NOT IN ?))",
+			Vars: []interface{}{id, companyValues(scope), id, companyValues(scope), id, scope.UserID, id, id,
+				clause.Column{Table: clause.CurrentTable, Name: "role"}, []interface{}{"superadmin", "admin"}},
+		}
+	case s.LookUpField("company_id") != nil:
+		return clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: "company_id"}, Values: companyValues(scope)}
+	case s.LookUpField("employee_id") != nil:
+		return clause.Expr{
+			SQL:  "? IN (SELECT id FROM employees WHERE company_id IN ?)",
+			Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: "employee_id"}, companyValues(scope)},
+		}
+	}
+	return nil
+}
+
+// companyValues returns the scope's companies as IN values. An empty scope
+// matches nothing.
+func companyValues(scope *Scope) []interface{} {
+	if len(scope.CompanyIDs) == 0 {
+		return []interface{}{nil}
+	}
+	values := make([]interface{}, len(scope.CompanyIDs))
+	for i, id := range scope.CompanyIDs {
+		values[i] = id
+	}
+	return values
+}
+
+// checkAssignment rejects creates and updates whose rows carry a company or
+// employee outside the scope
+func checkAssignment(db *gorm.DB) {
+	scope := FromContext(db.Statement.Context)
+	if scope == nil || db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	if field := db.Statement.Schema.LookUpField("company_id"); field != nil {
+		for _, companyID := range fieldValues(db.Statement, field) {
+			if !scope.Allows(companyID) {
+				db.AddError(ErrOutOfScope)
+				return
+			}
+		}
+		return
+	}
+
+	if field := db.Statement.Schema.LookUpField("employee_id"); field != nil {
+		employeeIDs := fieldValues(db.Statement, field)
+		if len(employeeIDs) == 0 {
+			return
+		}
+
+		var count int64
+		err := db.Session(&gorm.Session{NewDB: true}).
+			Table("employees").
+			Where("id IN ? AND company_id IN ?", employeeIDs, companyValues(scope)).
+			Count(&count).Error
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+		if count != int64(len(employeeIDs)) {
+			db.AddError(ErrOutOfScope)
+		}
+	}
+}
+
+// fieldValues returns the distinct non-empty values of a string field across
+// the statement's rows
+func fieldValues(stmt *gorm.Statement, field *schema.Field) []string {
+	seen := make(map[string]bool)
+	var values []string
+	collect := func(rv reflect.Value) {
+		value, zero := field.ValueOf(stmt.Context, rv)
+		if zero {
+			return
+		}
+		var id string
+		switch v := value.(type) {
+		case string:
+			id = v
+		case *string:
+			if v != nil {
+				id = *v
+			}
+		}
+		if id != "" && !seen[id] {
+			seen[id] = true
+			values = append(values, id)
+		}
+	}
+
+	rv := reflect.Indirect(stmt.ReflectValue)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if elem := reflect.Indirect(rv.Index(i)); elem.Kind() == reflect.Struct {
+				collect(elem)
+			}
+		}
+	case reflect.Struct:
+		collect(rv)
+	}
+	return values
+}
@@ -0,0 +1,51 @@
+// Package tenant confines database access to the companies a user belongs
+// to. The scope of a request travels in its context.Context; GORM callbacks
+// registered with RegisterCallbacks read it back and filter every statement,
+// so repositories and services do not have to filter by company themselves.
+package tenant
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrOutOfScope is returned when a statement would write a row that belongs
+// to a company outside the request's scope.
+var ErrOutOfScope = errors.New("record belongs to a company outside your scope")
+
+// Scope is the set of companies a request may read and write, and the user
+// it acts for. A context without a scope is unrestricted; that is the case
+// for superadmins, unauthenticated routes and background work.
+type Scope struct {
+	UserID     string
+	CompanyIDs []string
+}
+
+type scopeKey struct{}
+
+// WithScope returns a copy of ctx carrying scope
+func WithScope(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// FromContext returns the scope carried by ctx, or nil when ctx is unrestricted
+func FromContext(ctx context.Context) *Scope {
+	if ctx == nil {
+		return nil
+	}
+	scope, _ := ctx.Value(scopeKey{}).(*Scope)
+	return scope
+}
+
+// Allows reports whether companyID is within the scope
+func (s *Scope) Allows(companyID string) bool {
+	if s == nil {
+		return true
+	}
+	for _, id := range s.CompanyIDs {
+		if id == companyID {
+			return true
+		}
+	}
+	return false
+}
@@ -28,14 +28,16 @@ func CalculateBPJS(basicSalary float64) (float64, float64, float64, float64, flo
 	return bpjsKesEmployee, bpjsKesCompany, jhtEmployee, jhtCompany, jkk, jkm, jpEmployee, jpCompany
 }
 
-// CalculatePPh21Monthly calculates monthly PPh 21 income tax
-// Uses progressive tax brackets (UU HPP 2022):
+// CalculatePPh21Monthly calculates monthly PPh 21 income tax using the
+// pre-2024 method: annualise one month's gross, apply the progressive
+// brackets (UU HPP 2022) and divide by 12.
 // 0 - 60,000,000 → 5%
 // 60,000,001 - 250,000,000 → 15%
 // 250,000,001 - 500,000,000 → 25%
 // 500,000,001 - 5,000,000,000 → 30%
 // > 5,000,000,000 → 35%
 // PTKP (non-taxable income) for single: 54,000,000/year
+// Payroll uses CalculatePPh21TER and CalculatePPh21December since PP 58/2023.
 func CalculatePPh21Monthly(annualGrossIncome float64, ptkp float64) float64 {
 	if ptkp == 0 {
 		ptkp = 54000000 // TK/0 (single, no dependents)
@@ -46,35 +48,8 @@ func CalculatePPh21Monthly(annualGrossIncome float64, ptkp float64) float64 {
 		return 0
 	}
 
-	var annualTax float64
-
-	brackets := []struct {
-		limit float64
-		rate  float64
-	}{
-		{60000000, 0.05},
-		{250000000, 0.15},
-		{500000000, 0.25},
-		{5000000000, 0.30},
-		{math.MaxFloat64, 0.35},
-	}
-
-	remaining := taxableIncome
-	prevLimit := 0.0
-
-	for _, bracket := range brackets {
-		bracketSize := bracket.limit - prevLimit
-		if remaining <= 0 {
-			break
-		}
-		taxable := math.Min(remaining, bracketSize)
-		annualTax += taxable * bracket.rate
-		remaining -= taxable
-		prevLimit = bracket.limit
-	}
-
 	// Return monthly tax
-	return math.Round(annualTax / 12)
+	return math.Round(calculatePasal17Tax(taxableIncome) / 12)
 }
 
 // GetPTKP returns PTKP (Penghasilan Tidak Kena Pajak) based on marital status
@@ -89,11 +64,7 @@ func GetPTKP(maritalStatus string, dependents int) float64 {
 		base = 58500000.0
 	}
 	// Each dependent adds 4,500,000 (max 3 dependents)
-	deps := dependents
-	if deps > 3 {
-		deps = 3
-	}
-	return base + float64(deps)*4500000.0
+	return base + float64(clampDependents(dependents))*4500000.0
 }
 
 // CalculateTHR calculates Tunjangan Hari Raya
@@ -0,0 +1,199 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+)
+
+// TERCategory is the effective-rate (Tarif Efektif Rata-rata) table an
+// employee falls into under PP 58/2023, determined by their PTKP status.
+type TERCategory string
+
+const (
+	TERCategoryA TERCategory = "A" // TK/0, TK/1, K/0
+	TERCategoryB TERCategory = "B" // TK/2, TK/3, K/1, K/2
+	TERCategoryC TERCategory = "C" // K/3
+)
+
+// terBracket is one row of a monthly TER table: gross income up to and
+// including limit is taxed at rate.
+type terBracket struct {
+	limit float64
+	rate  float64
+}
+
+// Monthly TER tables from the appendix of PP 58/2023. The last row of each
+// table has no upper limit.
+var terTables = map[TERCategory][]terBracket{
+	TERCategoryA: {
+		{5400000, 0}, {5650000, 0.0025}, {5950000, 0.005}, {6300000, 0.0075},
+		{6750000, 0.01}, {7500000, 0.0125}, {8550000, 0.015}, {9650000, 0.0175},
+		{10050000, 0.02}, {10350000, 0.0225}, {10700000, 0.025}, {11050000, 0.03},
+		{11600000, 0.035}, {12500000, 0.04}, {13750000, 0.05}, {15100000, 0.06},
+		{16950000, 0.07}, {19750000, 0.08}, {24150000, 0.09}, {26450000, 0.10},
+		{28000000, 0.11}, {30050000, 0.12}, {32400000, 0.13}, {35400000, 0.14},
+		{39100000, 0.15}, {43850000, 0.16}, {47800000, 0.17}, {51400000, 0.18},
+		{56300000, 0.19}, {62200000, 0.20}, {68600000, 0.21}, {77500000, 0.22},
+		{89000000, 0.23}, {103000000, 0.24}, {125000000, 0.25}, {157000000, 0.26},
+		{206000000, 0.27}, {337000000, 0.28}, {454000000, 0.29}, {550000000, 0.30},
+		{695000000, 0.31}, {910000000, 0.32}, {1400000000, 0.33}, {math.MaxFloat64, 0.34},
+	},
+	TERCategoryB: {
+		{6200000, 0}, {6500000, 0.0025}, {6850000, 0.005}, {7300000, 0.0075},
+		{9200000, 0.01}, {10750000, 0.015}, {11250000, 0.02}, {11600000, 0.025},
+		{12600000, 0.03}, {13600000, 0.04}, {14950000, 0.05}, {16400000, 0.06},
+		{18450000, 0.07}, {21850000, 0.08}, {26000000, 0.09}, {27700000, 0.10},
+		{29350000, 0.11}, {31450000, 0.12}, {33950000, 0.13}, {37100000, 0.14},
+		{41100000, 0.15}, {45800000, 0.16}, {49500000, 0.17}, {53800000, 0.18},
+		{58500000, 0.19}, {64000000, 0.20}, {71000000, 0.21}, {80000000, 0.22},
+		{93000000, 0.23}, {109000000, 0.24}, {129000000, 0.25}, {163000000, 0.26},
+		{211000000, 0.27}, {374000000, 0.28}, {459000000, 0.29}, {555000000, 0.30},
+		{704000000, 0.31}, {957000000, 0.32}, {1405000000, 0.33}, {math.MaxFloat64, 0.34},
+	},
+	TERCategoryC: {
+		{6600000, 0}, {6950000, 0.0025}, {7350000, 0.005}, {7800000, 0.0075},
+		{8850000, 0.01}, {9800000, 0.0125}, {10950000, 0.015}, {11200000, 0.0175},
+		{12050000, 0.02}, {12950000, 0.03}, {14150000, 0.04}, {15550000, 0.05},
+		{17050000, 0.06}, {19500000, 0.07}, {22700000, 0.08}, {26600000, 0.09},
+		{28100000, 0.10}, {30100000, 0.11}, {32600000, 0.12}, {35400000, 0.13},
+		{38900000, 0.14}, {43000000, 0.15}, {47400000, 0.16}, {51200000, 0.17},
+		{55800000, 0.18}, {60400000, 0.19}, {66700000, 0.20}, {74500000, 0.21},
+		{83200000, 0.22}, {95600000, 0.23}, {110000000, 0.24}, {134000000, 0.25},
+		{169000000, 0.26}, {221000000, 0.27}, {390000000, 0.28}, {463000000, 0.29},
+		{561000000, 0.30}, {709000000, 0.31}, {965000000, 0.32}, {1419000000, 0.33},
+		{math.MaxFloat64, 0.34},
+	},
+}
+
+// GetPTKPStatus returns the PTKP status code (TK/0 .. K/3) for a marital
+// status and number of dependents. At most 3 dependents are counted.
+func GetPTKPStatus(maritalStatus string, dependents int) string {
+	prefix := "TK"
+	if maritalStatus == "kawin" {
+		prefix = "K"
+	}
+	return fmt.Sprintf("%s/%d", prefix, clampDependents(dependents))
+}
+
+// GetTERCategory returns the TER table for a marital status and number of
+// dependents:
+// A = TK/0, TK/1, K/0
+// B = TK/2, TK/3, K/1, K/2
+// C = K/3
+func GetTERCategory(maritalStatus string, dependents int) TERCategory {
+	deps := clampDependents(dependents)
+	if maritalStatus == "kawin" {
+		switch deps {
+		case 0:
+			return TERCategoryA
+		case 3:
+			return TERCategoryC
+		default:
+			return TERCategoryB
+		}
+	}
+	if deps <= 1 {
+		return TERCategoryA
+	}
+	return TERCategoryB
+}
+
+// GetTERRate returns the monthly effective rate for a gross monthly income
+func GetTERRate(category TERCategory, monthlyGross float64) float64 {
+	table, ok := terTables[category]
+	if !ok {
+		table = terTables[TERCategoryA]
+	}
+	for _, bracket := range table {
+		if monthlyGross <= bracket.limit {
+			return bracket.rate
+		}
+	}
+	return table[len(table)-1].rate
+}
+
+// CalculatePPh21TER calculates PPh 21 withheld for January to November (and
+// any month that is not the employee's last month of the tax year):
+// gross monthly income x TER rate. Gross income includes the employer-paid
+// JKK, JKM and BPJS Kesehatan premiums.
+func CalculatePPh21TER(monthlyGross float64, category TERCategory) float64 {
+	if monthlyGross <= 0 {
+		return 0
+	}
+	return math.Floor(monthlyGross * GetTERRate(category, monthlyGross))
+}
+
+// CalculatePPh21Annual calculates the annual PPh 21 owed using Pasal 17
+// progressive brackets:
+// Gross income
+// - biaya jabatan (5%, max 500,000 per month worked)
+// - employee pension contributions (JHT + JP)
+// = net income
+// - PTKP
+// = PKP, rounded down to the nearest thousand
+func CalculatePPh21Annual(annualGross, annualPensionContribution, ptkp float64, monthsWorked int) float64 {
+	if monthsWorked < 1 {
+		monthsWorked = 1
+	}
+	if monthsWorked > 12 {
+		monthsWorked = 12
+	}
+
+	biayaJabatan := math.Min(annualGross*0.05, 500000*float64(monthsWorked))
+	netIncome := annualGross - biayaJabatan - annualPensionContribution
+
+	pkp := math.Floor((netIncome-ptkp)/1000) * 1000
+	if pkp <= 0 {
+		return 0
+	}
+	return math.Floor(calculatePasal17Tax(pkp))
+}
+
+// CalculatePPh21December calculates the tax withheld in the last month of the
+// tax year: annual tax owed minus tax already withheld in earlier months. Any
+// excess withheld is not refunded through payroll, so the result is never
+// negative.
+func CalculatePPh21December(annualGross, annualPensionContribution, ptkp float64, monthsWorked int, alreadyWithheld float64) float64 {
+	annualTax := CalculatePPh21Annual(annualGross, annualPensionContribution, ptkp, monthsWorked)
+	return math.Max(annualTax-alreadyWithheld, 0)
+}
+
+// calculatePasal17Tax applies the progressive brackets (UU HPP 2022) to PKP
+func calculatePasal17Tax(pkp float64) float64 {
+	brackets := []struct {
+		limit float64
+		rate  float64
+	}{
+		{60000000, 0.05},
+		{250000000, 0.15},
+		{500000000, 0.25},
+		{5000000000, 0.30},
+		{math.MaxFloat64, 0.35},
+	}
+
+	var tax float64
+	remaining := pkp
+	prevLimit := 0.0
+
+	for _, bracket := range brackets {
+		if remaining <= 0 {
+			break
+		}
+		taxable := math.Min(remaining, bracket.limit-prevLimit)
+		tax += taxable * bracket.rate
+		remaining -= taxable
+		prevLimit = bracket.limit
+	}
+
+	return tax
+}
+
+func clampDependents(dependents int) int {
+	if dependents < 0 {
+		return 0
+	}
+	if dependents > 3 {
+		return 3
+	}
+	return dependents
+}
@@ -0,0 +1,171 @@
+package calculator
+
+import "testing"
+
+func TestGetTERCategory(t *testing.T) {
+	tests := []struct {
+		maritalStatus string
+		dependents    int
+		ptkpStatus    string
+		ptkp          float64
+		category      TERCategory
+	}{
+		{"belum_kawin", 0, "TK/0", 54000000, TERCategoryA},
+		{"belum_kawin", 1, "TK/1", 58500000, TERCategoryA},
+		{"belum_kawin", 2, "TK/2", 63000000, TERCategoryB},
+		{"belum_kawin", 3, "TK/3", 67500000, TERCategoryB},
+		{"kawin", 0, "K/0", 58500000, TERCategoryA},
+		{"kawin", 1, "K/1", 63000000, TERCategoryB},
+		{"kawin", 2, "K/2", 67500000, TERCategoryB},
+		{"kawin", 3, "K/3", 72000000, TERCategoryC},
+		// At most 3 dependents are counted
+		{"kawin", 5, "K/3", 72000000, TERCategoryC},
+		{"belum_kawin", -1, "TK/0", 54000000, TERCategoryA},
+	}
+	for _, tt := range tests {
+		if got := GetPTKPStatus(tt.maritalStatus, tt.dependents); got != tt.ptkpStatus {
+			t.Errorf("GetPTKPStatus(%q, %d) = %s, want %s", tt.maritalStatus, tt.dependents, got, tt.ptkpStatus)
+		}
+		if got := GetPTKP(tt.maritalStatus, tt.dependents); got != tt.ptkp {
+			t.Errorf("GetPTKP(%q, %d) = %.0f, want %.0f", tt.maritalStatus, tt.dependents, got, tt.ptkp)
+		}
+		if got := GetTERCategory(tt.maritalStatus, tt.dependents); got != tt.category {
+			t.Errorf("GetTERCategory(%q, %d) = %s, want %s (%s)", tt.maritalStatus, tt.dependents, got, tt.category, tt.ptkpStatus)
+		}
+	}
+}
+
+func TestGetTERRateBoundaries(t *testing.T) {
+	tests := []struct {
+		category TERCategory
+		gross    float64
+		rate     float64
+	}{
+		{TERCategoryA, 5400000, 0},
+		{TERCategoryA, 5400001, 0.0025},
+		{TERCategoryA, 10050000, 0.02},
+		{TERCategoryA, 10050001, 0.0225},
+		{TERCategoryA, 15100000, 0.06},
+		{TERCategoryA, 15100001, 0.07},
+		{TERCategoryA, 1400000000, 0.33},
+		{TERCategoryA, 1400000001, 0.34},
+
+		{TERCategoryB, 6200000, 0},
+		{TERCategoryB, 6200001, 0.0025},
+		{TERCategoryB, 9200000, 0.01},
+		{TERCategoryB, 9200001, 0.015},
+		{TERCategoryB, 12600000, 0.03},
+		{TERCategoryB, 12600001, 0.04},
+		{TERCategoryB, 1405000000, 0.33},
+		{TERCategoryB, 1405000001, 0.34},
+
+		{TERCategoryC, 6600000, 0},
+		{TERCategoryC, 6600001, 0.0025},
+		{TERCategoryC, 12050000, 0.02},
+		{TERCategoryC, 12050001, 0.03},
+		{TERCategoryC, 22700000, 0.08},
+		{TERCategoryC, 22700001, 0.09},
+		{TERCategoryC, 1419000000, 0.33},
+		{TERCategoryC, 1419000001, 0.34},
+
+		// Unknown categories use table A
+		{"X", 5400001, 0.0025},
+	}
+	for _, tt := range tests {
+		if got := GetTERRate(tt.category, tt.gross); got != tt.rate {
+			t.Errorf("GetTERRate(%s, %.0f) = %g, want %g", tt.category, tt.gross, got, tt.rate)
+		}
+	}
+}
+
+// TestTERTables checks every bracket: its limit is taxed at its rate and one
+// rupiah more at the next, higher one
+func TestTERTables(t *testing.T) {
+	for category, table := range terTables {
+		for i, bracket := range table[:len(table)-1] {
+			next := table[i+1]
+			if next.limit <= bracket.limit || next.rate <= bracket.rate {
+				t.Errorf("category %s: bracket %d (%.0f at %g) does not rise to %.0f at %g", category, i, bracket.limit, bracket.rate, next.limit, next.rate)
+			}
+			if got := GetTERRate(category, bracket.limit); got != bracket.rate {
+				t.Errorf("GetTERRate(%s, %.0f) = %g, want %g", category, bracket.limit, got, bracket.rate)
+			}
+			if got := GetTERRate(category, bracket.limit+1); got != next.rate {
+				t.Errorf("GetTERRate(%s, %.0f) = %g, want %g", category, bracket.limit+1, got, next.rate)
+			}
+		}
+	}
+}
+
+func TestCalculatePPh21TER(t *testing.T) {
+	tests := []struct {
+		gross    float64
+		category TERCategory
+		want     float64
+	}{
+		{0, TERCategoryA, 0},
+		{-1000000, TERCategoryA, 0},
+		{5400000, TERCategoryA, 0},
+		{10000000, TERCategoryA, 200000},
+		{10000000, TERCategoryB, 150000},
+		{10000000, TERCategoryC, 150000},
+		// Rounded down to the rupiah
+		{5400001, TERCategoryA, 13500},
+	}
+	for _, tt := range tests {
+		if got := CalculatePPh21TER(tt.gross, tt.category); got != tt.want {
+			t.Errorf("CalculatePPh21TER(%.0f, %s) = %.0f, want %.0f", tt.gross, tt.category, got, tt.want)
+		}
+	}
+}
+
+// TestPPh21FullYear withholds TER from January to November and the true-up
+// in December, which together come to the annual Pasal 17 tax unless TER
+// withheld more
+func TestPPh21FullYear(t *testing.T) {
+	tests := []struct {
+		name           string
+		maritalStatus  string
+		dependents     int
+		monthlyGross   float64
+		monthlyPension float64
+		terWithheld    float64 // January to November
+		annualTax      float64
+		december       float64
+	}{
+		// PKP 116,400,000: 5% of 60,000,000 + 15% of 56,400,000
+		{"TK/0", "belum_kawin", 0, 15000000, 300000, 9900000, 11460000, 1560000},
+		// PKP 72,120,000: 5% of 60,000,000 + 15% of 12,120,000
+		{"K/1", "kawin", 1, 12000000, 240000, 3960000, 4818000, 858000},
+		// TER withholds 20,000 more than the 17,580,000 owed, which is not refunded
+		{"K/3", "kawin", 3, 20000000, 400000, 17600000, 17580000, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category := GetTERCategory(tt.maritalStatus, tt.dependents)
+			ptkp := GetPTKP(tt.maritalStatus, tt.dependents)
+
+			withheld := 0.0
+			for month := 1; month <= 11; month++ {
+				withheld += CalculatePPh21TER(tt.monthlyGross, category)
+			}
+			if withheld != tt.terWithheld {
+				t.Errorf("TER withheld %.0f, want %.0f", withheld, tt.terWithheld)
+			}
+
+			annualGross, annualPension := tt.monthlyGross*12, tt.monthlyPension*12
+			annualTax := CalculatePPh21Annual(annualGross, annualPension, ptkp, 12)
+			if annualTax != tt.annualTax {
+				t.Errorf("annual tax %.0f, want %.0f", annualTax, tt.annualTax)
+			}
+
+			december := CalculatePPh21December(annualGross, annualPension, ptkp, 12, withheld)
+			if december != tt.december {
+				t.Errorf("December %.0f, want %.0f", december, tt.december)
+			}
+			if tt.december > 0 && withheld+december != annualTax {
+				t.Errorf("withheld %.0f over the year, want the annual %.0f", withheld+december, annualTax)
+			}
+		})
+	}
+}
@@ -7,18 +7,49 @@ import (
 )
 
 type TokenClaims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID    string `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid,omitempty"`
+	// MustChangePassword restricts the token to changing the password
+	MustChangePassword bool `json:"mcp,omitempty"`
+	// TwoFactorSetupRequired restricts the token to enrolling in 2FA
+	TwoFactorSetupRequired bool `json:"tfa,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateAccessToken(userID, email, role, secret string, expiry time.Duration) (string, error) {
-	claims := TokenClaims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+// challengeAudience marks the tokens that stand between the password and the
+// second factor of a login
+const challengeAudience = "2fa-challenge"
+
+// RefreshClaims identifies a refresh token: ID is the token's jti and
+// SessionID the session (token family) it was issued to
+type RefreshClaims struct {
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// GenerateAccessToken signs claims with the active key of keys, setting their
+// subject, issue and expiry times
+func GenerateAccessToken(claims TokenClaims, keys *KeySet, expiry time.Duration) (string, error) {
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Issuer:    claims.Issuer,
+		Subject:   claims.UserID,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	}
+
+	return keys.sign(claims)
+}
+
+// GenerateRefreshToken signs a refresh token with an HMAC secret. Unlike access
+// tokens, refresh tokens are only ever read by this service.
+func GenerateRefreshToken(userID, sessionID, tokenID, secret string, expiry time.Duration) (string, error) {
+	claims := RefreshClaims{
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Subject:   userID,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
@@ -28,26 +59,29 @@ func GenerateAccessToken(userID, email, role, secret string, expiry time.Duratio
 	return token.SignedString([]byte(secret))
 }
 
-func GenerateRefreshToken(userID, secret string, expiry time.Duration) (string, error) {
-	claims := jwt.RegisteredClaims{
-		Subject:   userID,
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+func ValidateAccessToken(tokenString string, keys *KeySet) (*TokenClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &TokenClaims{}, keys.verificationKey, jwt.WithValidMethods(validMethods))
+	if err != nil {
+		return nil, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	claims, ok := token.Claims.(*TokenClaims)
+	if !ok || !token.Valid || claims.UserID == "" {
+		return nil, jwt.ErrSignatureInvalid
+	}
+
+	return claims, nil
 }
 
-func ValidateAccessToken(tokenString, secret string) (*TokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
+func ValidateRefreshToken(tokenString, secret string) (*RefreshClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &RefreshClaims{}, func(token *jwt.Token) (interface{}, error) {
 		return []byte(secret), nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 	if err != nil {
 		return nil, err
 	}
 
-	claims, ok := token.Claims.(*TokenClaims)
+	claims, ok := token.Claims.(*RefreshClaims)
 	if !ok || !token.Valid {
 		return nil, jwt.ErrSignatureInvalid
 	}
@@ -55,18 +89,34 @@ func ValidateAccessToken(tokenString, secret string) (*TokenClaims, error) {
 	return claims, nil
 }
 
-func ValidateRefreshToken(tokenString, secret string) (string, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
+// GenerateChallengeToken issues the token a user trades, together with a
+// second factor code, for a session after passing the password check.
+// challengeID becomes the token's jti.
+func GenerateChallengeToken(userID, challengeID string, keys *KeySet, expiry time.Duration) (string, error) {
+	claims := jwt.RegisteredClaims{
+		ID:        challengeID,
+		Subject:   userID,
+		Audience:  jwt.ClaimStrings{challengeAudience},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	}
+
+	return keys.sign(claims)
+}
+
+// ValidateChallengeToken returns the user ID and challenge ID of a challenge
+// token
+func ValidateChallengeToken(tokenString string, keys *KeySet) (string, string, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, keys.verificationKey,
+		jwt.WithValidMethods(validMethods), jwt.WithAudience(challengeAudience))
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	claims, ok := token.Claims.(*jwt.RegisteredClaims)
-	if !ok || !token.Valid {
-		return "", jwt.ErrSignatureInvalid
+	if !ok || !token.Valid || claims.ID == "" {
+		return "", "", jwt.ErrSignatureInvalid
 	}
 
-	return claims.Subject, nil
+	return claims.Subject, claims.ID, nil
 }
@@ -0,0 +1,128 @@
+package kafka
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/s2"
+)
+
+// Compression is the codec of a record batch, as stored in its attributes
+type Compression int8
+
+const (
+	CompressionNone   Compression = 0
+	CompressionGzip   Compression = 1
+	CompressionSnappy Compression = 2
+)
+
+// compressionMask selects the codec bits of batch and message attributes
+const compressionMask = 0x07
+
+// ParseCompression maps a codec name, as in the KAFKA_COMPRESSION setting,
+// to a Compression
+func ParseCompression(name string) (Compression, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "none":
+		return CompressionNone, nil
+	case "gzip":
+		return CompressionGzip, nil
+	case "snappy":
+		return CompressionSnappy, nil
+	default:
+		return CompressionNone, fmt.Errorf("unsupported kafka compression %q (use none, gzip or snappy)", name)
+	}
+}
+
+func (c Compression) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionGzip:
+		return "gzip"
+	case CompressionSnappy:
+		return "snappy"
+	default:
+		return fmt.Sprintf("codec(%d)", int8(c))
+	}
+}
+
+func compress(codec Compression, data []byte) ([]byte, error) {
+	switch codec {
+	case CompressionNone:
+		return data, nil
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case CompressionSnappy:
+		// A plain snappy block, which every Kafka client and broker reads
+		return s2.EncodeSnappy(nil, data), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression codec %d", codec)
+	}
+}
+
+func decompress(codec Compression, data []byte) ([]byte, error) {
+	switch codec {
+	case CompressionNone:
+		return data, nil
+	case CompressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	case CompressionSnappy:
+		return decodeSnappy(data)
+	default:
+		return nil, fmt.Errorf("unsupported compression codec %d", codec)
+	}
+}
+
+// xerialHeader starts snappy data framed by the Java client (snappy-java):
+// the magic, a version and a compatible version, followed by chunks of
+// length-prefixed snappy blocks
+var xerialHeader = []byte{0x82, 'S', 'N', 'A', 'P', 'P', 'Y', 0}
+
+const xerialHeaderSize = 16
+
+// decodeSnappy decodes a plain snappy block or xerial-framed chunks
+func decodeSnappy(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, xerialHeader) {
+		return s2.Decode(nil, data)
+	}
+	if len(data) < xerialHeaderSize {
+		return nil, errors.New("truncated snappy header")
+	}
+
+	var out []byte
+	for data = data[xerialHeaderSize:]; len(data) > 0; {
+		if len(data) < 4 {
+			return nil, errors.New("truncated snappy chunk")
+		}
+		size := binary.BigEndian.Uint32(data)
+		if int64(size) > int64(len(data)-4) {
+			return nil, errors.New("truncated snappy chunk")
+		}
+		chunk, err := s2.Decode(nil, data[4:4+size])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, chunk...)
+		data = data[4+size:]
+	}
+	return out, nil
+}
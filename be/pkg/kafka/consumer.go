@@ -4,38 +4,84 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"time"
 )
 
 // MessageHandler is called for each message received from Kafka.
-type MessageHandler func(value []byte) error
+type MessageHandler func(msg *Message) error
+
+const (
+	// requestTimeout bounds each request/response exchange with a broker
+	requestTimeout = 5 * time.Second
+
+	// maxResponseSize bounds the size of a response frame
+	maxResponseSize = 10 << 20
+
+	// A fetch asks for up to fetchPartitionMaxBytes per partition and
+	// fetchMaxBytes in all, which leaves room for the response framing
+	// within maxResponseSize
+	fetchPartitionMaxBytes = 1 << 20
+	fetchMaxBytes          = 8 << 20
+
+	// A message the handler fails on is tried handlerAttempts times in all,
+	// waiting handlerBaseBackoff before the first retry and four times
+	// longer before each next one, and then dead-lettered
+	handlerAttempts    = 4
+	handlerBaseBackoff = 500 * time.Millisecond
+)
 
-// Consumer reads messages from a Kafka topic using the Kafka binary protocol v0.
+// Consumer reads a Kafka topic as record batches (Fetch v4), and still reads
+// the legacy message sets of older producers. It joins the consumer group
+// groupID, whose leader spreads the partitions of the topic over the members
+// with the range assignor, and reads only the partitions assigned to it.
+// Its position is committed to Kafka after each fetch, so whoever holds a
+// partition next resumes where the previous member stopped. Delivery is at
+// least once: a crash between handling messages and committing them replays
+// those messages, so handlers must be idempotent.
+//
+// A message the handler keeps failing on is published to the dead-letter
+// topic of the topic through deadLetters, and the consumer moves on. Without
+// deadLetters, or when that publish fails, the consumer stops at the message
+// and delivers it again on its next run, so a message is never dropped.
 type Consumer struct {
-	brokers []string
-	topic   string
-	groupID string
-	offset  int64
-	handler MessageHandler
+	brokers     []string
+	topic       string
+	groupID     string
+	handler     MessageHandler
+	deadLetters *Producer
+
+	// memberID is the consumer's ID in its group, kept across runs so a
+	// rejoin takes over the same membership
+	memberID string
+
+	// offsets is the next offset to fetch of each assigned partition and
+	// committed the last one stored in Kafka. Both start over from the
+	// committed offsets in each generation of the group.
+	offsets   map[int32]int64
+	committed map[int32]int64
 }
 
-// NewConsumer creates a new Kafka consumer.
-func NewConsumer(brokers []string, topic, groupID string, handler MessageHandler) *Consumer {
+// NewConsumer creates a new Kafka consumer. deadLetters publishes the
+// messages the handler gives up on; it may be nil.
+func NewConsumer(brokers []string, topic, groupID string, handler MessageHandler, deadLetters *Producer) *Consumer {
 	return &Consumer{
-		brokers: brokers,
-		topic:   topic,
-		groupID: groupID,
-		offset:  0,
-		handler: handler,
+		brokers:     brokers,
+		topic:       topic,
+		groupID:     groupID,
+		handler:     handler,
+		deadLetters: deadLetters,
+		offsets:     make(map[int32]int64),
+		committed:   make(map[int32]int64),
 	}
 }
 
 // Start begins consuming messages in a background loop.
 // It reconnects automatically on failure.
-// Cancel ctx to stop.
 func (c *Consumer) Start() {
 	go func() {
 		for {
@@ -47,190 +93,387 @@ func (c *Consumer) Start() {
 	}()
 }
 
-// runLoop connects to a broker and polls for messages until an error occurs.
+// runLoop finds the group's coordinator, joins the group and polls the
+// assigned partitions for messages, rejoining whenever the group rebalances,
+// until an error occurs. Errors such as a leader change end the loop, so the
+// next run starts from fresh metadata.
 func (c *Consumer) runLoop() error {
-	broker := c.pickBroker()
-	if broker == "" {
-		return fmt.Errorf("no kafka brokers configured")
+	conns := make(brokerConns)
+	defer conns.close()
+
+	bootstrap, err := c.dialBootstrap(conns)
+	if err != nil {
+		return err
 	}
 
-	conn, err := net.DialTimeout("tcp", broker, 5*time.Second)
+	coordinatorAddr, err := findCoordinator(bootstrap, c.groupID)
 	if err != nil {
-		return fmt.Errorf("connect to %s: %w", broker, err)
+		return err
+	}
+	coordinator, err := conns.get(coordinatorAddr)
+	if err != nil {
+		return err
 	}
-	defer conn.Close()
-	log.Printf("[kafka] consumer connected to %s (topic=%s, offset=%d)", broker, c.topic, c.offset)
 
 	for {
-		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		// Partitions may have been added since the last generation
+		leaders, err := fetchMetadata(bootstrap, c.topic)
+		if err != nil {
+			return fmt.Errorf("metadata: %w", err)
+		}
 
-		messages, err := c.fetchMessages(conn)
+		session, err := c.join(coordinator, coordinatorAddr, leaders)
+		if errors.Is(err, errRebalance) {
+			continue
+		}
 		if err != nil {
-			return fmt.Errorf("fetch: %w", err)
+			return fmt.Errorf("join group: %w", err)
 		}
 
-		for _, msg := range messages {
-			if err := c.handler(msg.Value); err != nil {
-				log.Printf("[kafka] handler error at offset %d: %v", msg.Offset, err)
+		err = c.consume(conns, coordinator, leaders, session)
+		session.close()
+		if !errors.Is(err, errRebalance) {
+			// Hand the partitions to the other members while this one
+			// reconnects
+			if leaveErr := leaveGroup(coordinator, c.groupID, c.memberID); leaveErr == nil {
+				c.memberID = ""
 			}
-			c.offset = msg.Offset + 1
+			return err
 		}
+		log.Printf("[kafka] consumer rejoining group %s: %v", c.groupID, err)
+	}
+}
 
-		if len(messages) == 0 {
-			// No new messages — wait before polling again
-			time.Sleep(500 * time.Millisecond)
+// join joins the group and returns the session of the new generation, with
+// the partitions assigned to the consumer. When the coordinator makes the
+// consumer the group's leader, it assigns the partitions of leaders to every
+// member.
+func (c *Consumer) join(coordinator net.Conn, coordinatorAddr string, leaders topicMetadata) (*groupSession, error) {
+	joined, err := joinGroup(coordinator, c.groupID, c.memberID, c.topic)
+	if errors.Is(err, errRebalance) {
+		// The coordinator forgot the member, e.g. after its session expired
+		c.memberID = ""
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.memberID = joined.MemberID
+
+	var assignments map[string][]int32
+	if joined.LeaderID == joined.MemberID {
+		partitions := make([]int32, 0, len(leaders))
+		for partition := range leaders {
+			partitions = append(partitions, partition)
 		}
+		assignments = assignRange(joined.Members, c.topic, partitions)
 	}
-}
 
-type kafkaMessage struct {
-	Offset int64
-	Value  []byte
+	partitions, err := syncGroup(coordinator, c.groupID, joined.Generation, c.memberID, c.topic, assignments)
+	if err != nil {
+		return nil, err
+	}
+	return c.startHeartbeat(coordinatorAddr, joined.Generation, partitions)
 }
 
-// fetchMessages sends a FetchRequest and returns decoded messages.
-func (c *Consumer) fetchMessages(conn net.Conn) ([]kafkaMessage, error) {
-	req := c.buildFetchRequest()
-	if _, err := conn.Write(req); err != nil {
-		return nil, fmt.Errorf("write request: %w", err)
+// consume polls the partitions assigned to the consumer in session for
+// messages, from the group's committed offsets, until an error occurs. It
+// returns errRebalance when the consumer has to rejoin its group, after
+// committing what it handled.
+func (c *Consumer) consume(conns brokerConns, coordinator net.Conn, leaders topicMetadata, session *groupSession) error {
+	// Another member may have moved on from where this one left a partition
+	c.offsets = make(map[int32]int64)
+	c.committed = make(map[int32]int64)
+
+	byLeader := make(map[string][]int32)
+	for _, partition := range session.partitions {
+		addr, ok := leaders[partition]
+		if !ok {
+			return fmt.Errorf("assigned partition %d of %s has no known leader", partition, c.topic)
+		}
+		byLeader[addr] = append(byLeader[addr], partition)
 	}
 
-	return c.readFetchResponse(conn)
+	if err := c.resume(conns, coordinator, leaders, session.partitions); err != nil {
+		return fmt.Errorf("resume: %w", err)
+	}
+	log.Printf("[kafka] consumer joined (topic=%s, group=%s, generation=%d, offsets=%v)", c.topic, c.groupID, session.generation, c.offsets)
+
+	for {
+		received := 0
+		for addr, partitions := range byLeader {
+			if session.rebalancing() {
+				break
+			}
+			conn, err := conns.get(addr)
+			if err != nil {
+				return err
+			}
+			n, err := c.fetchFrom(conn, partitions, session)
+			if err != nil {
+				return fmt.Errorf("fetch from %s: %w", addr, err)
+			}
+			received += n
+		}
+
+		if err := c.commit(coordinator, session.generation); err != nil {
+			return fmt.Errorf("commit: %w", err)
+		}
+		if session.rebalancing() {
+			return errRebalance
+		}
+
+		if received == 0 {
+			// No new messages — wait before polling again
+			select {
+			case <-session.rebalance:
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+	}
 }
 
-// buildFetchRequest constructs a Kafka FetchRequest (ApiKey=1, ApiVersion=0).
-func (c *Consumer) buildFetchRequest() []byte {
-	var body bytes.Buffer
-	writeInt32(&body, -1)       // replica_id = -1 (consumer)
-	writeInt32(&body, 1000)     // max_wait_ms
-	writeInt32(&body, 1)        // min_bytes
-	writeInt32(&body, 1)        // topic array length
-	writeString(&body, c.topic) // topic name
-	writeInt32(&body, 1)        // partition array length
-	writeInt32(&body, 0)        // partition = 0
-	writeInt64(&body, c.offset) // fetch offset
-	writeInt32(&body, 1048576)  // max_bytes = 1MB
-
-	return buildRequest(1, 0, "hris-consumer", body.Bytes())
+// dialBootstrap connects to the first reachable configured broker
+func (c *Consumer) dialBootstrap(conns brokerConns) (net.Conn, error) {
+	if len(c.brokers) == 0 {
+		return nil, fmt.Errorf("no kafka brokers configured")
+	}
+
+	var lastErr error
+	for _, broker := range c.brokers {
+		conn, err := conns.get(broker)
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
 }
 
-// readFetchResponse parses the FetchResponse and returns messages.
-func (c *Consumer) readFetchResponse(conn net.Conn) ([]kafkaMessage, error) {
-	var size int32
-	if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
-		return nil, fmt.Errorf("read response size: %w", err)
+// resume sets the position of the assigned partitions the consumer has not
+// read yet to the group's committed offset. A partition the group never
+// committed starts at its end: its history predates the group and would only
+// be replayed.
+func (c *Consumer) resume(conns brokerConns, coordinator net.Conn, leaders topicMetadata, assigned []int32) error {
+	var missing []int32
+	for _, partition := range assigned {
+		if _, ok := c.offsets[partition]; !ok {
+			missing = append(missing, partition)
+		}
 	}
-	if size <= 0 || size > 10<<20 {
-		return nil, fmt.Errorf("invalid fetch response size: %d", size)
+	if len(missing) == 0 {
+		return nil
 	}
 
-	buf := make([]byte, size)
-	if _, err := readFull(conn, buf); err != nil {
-		return nil, fmt.Errorf("read response body: %w", err)
+	committed, err := fetchCommittedOffsets(coordinator, c.groupID, c.topic, missing)
+	if err != nil {
+		return err
 	}
 
-	r := bytes.NewReader(buf)
+	for _, partition := range missing {
+		if offset, ok := committed[partition]; ok {
+			c.offsets[partition] = offset
+			c.committed[partition] = offset
+			continue
+		}
 
-	// correlation_id (skip)
-	var corrID int32
-	binary.Read(r, binary.BigEndian, &corrID)
+		conn, err := conns.get(leaders[partition])
+		if err != nil {
+			return err
+		}
+		offset, err := listOffset(conn, c.topic, partition, offsetLatest)
+		if err != nil {
+			return err
+		}
+		c.offsets[partition] = offset
+	}
+	return nil
+}
 
-	var topicCount int32
-	binary.Read(r, binary.BigEndian, &topicCount)
+// fetchFrom fetches the given partitions from their leader and hands their
+// messages to the handler in order. It returns how many messages it handled,
+// and stops at a message it could neither handle nor dead-letter, or once the
+// group rebalances.
+func (c *Consumer) fetchFrom(conn net.Conn, partitions []int32, session *groupSession) (int, error) {
+	r, err := roundTrip(conn, apiFetch, 4, c.buildFetchRequest(partitions))
+	if err != nil {
+		return 0, err
+	}
 
-	var result []kafkaMessage
-	for i := int32(0); i < topicCount; i++ {
-		readStringBuf(r) // topic name
+	results, err := readFetchResponse(r)
+	if err != nil {
+		return 0, err
+	}
 
-		var partCount int32
-		binary.Read(r, binary.BigEndian, &partCount)
-		for j := int32(0); j < partCount; j++ {
-			var partition int32
-			var errCode int16
-			var highWatermark int64
-			var msgSetSize int32
-
-			binary.Read(r, binary.BigEndian, &partition)
-			binary.Read(r, binary.BigEndian, &errCode)
-			binary.Read(r, binary.BigEndian, &highWatermark)
-			binary.Read(r, binary.BigEndian, &msgSetSize)
-
-			if errCode != 0 {
-				// Skip the message set bytes and continue
-				skip := make([]byte, msgSetSize)
-				r.Read(skip)
-				continue
+	handled := 0
+	for _, result := range results {
+		switch result.ErrCode {
+		case errNone:
+		case errOffsetOutOfRange:
+			// The messages at our position were deleted by retention
+			offset, err := listOffset(conn, c.topic, result.Partition, offsetEarliest)
+			if err != nil {
+				return handled, err
 			}
+			log.Printf("[kafka] partition %d: offset %d out of range, skipping to %d", result.Partition, c.offsets[result.Partition], offset)
+			c.offsets[result.Partition] = offset
+			continue
+		default:
+			return handled, fmt.Errorf("partition %d: kafka error code %d", result.Partition, result.ErrCode)
+		}
 
-			if msgSetSize > 0 {
-				msgSetData := make([]byte, msgSetSize)
-				r.Read(msgSetData)
-				msgs := parseMessageSet(msgSetData)
-				result = append(result, msgs...)
+		for i := range result.Messages {
+			msg := &result.Messages[i]
+			// A fetch may start before the requested offset, e.g. within a
+			// record batch
+			if msg.Offset < c.offsets[result.Partition] {
+				continue
+			}
+			if session.rebalancing() {
+				// Leave the rest to the member assigned the partition next
+				return handled, nil
+			}
+			msg.Topic = c.topic
+			msg.Partition = result.Partition
+			if err := c.handle(msg); err != nil {
+				return handled, err
 			}
+			c.offsets[result.Partition] = msg.Offset + 1
+			handled++
+		}
+
+		// Move past records that yield no message, such as transaction
+		// markers, so they are not fetched again
+		if result.NextOffset > c.offsets[result.Partition] {
+			c.offsets[result.Partition] = result.NextOffset
 		}
 	}
-	return result, nil
+	return handled, nil
 }
 
-// parseMessageSet decodes a raw MessageSet byte slice into individual messages.
-// Handles partial trailing messages gracefully.
-func parseMessageSet(data []byte) []kafkaMessage {
-	r := bytes.NewReader(data)
-	var msgs []kafkaMessage
-
-	for r.Len() >= 12 { // minimum: offset(8) + size(4)
-		var offset int64
-		var msgSize int32
-		binary.Read(r, binary.BigEndian, &offset)
-		binary.Read(r, binary.BigEndian, &msgSize)
-
-		if msgSize <= 0 || int(msgSize) > r.Len() {
-			// Partial message at end of batch — normal for Kafka
-			break
+// handle hands msg to the handler, retrying with backoff, and dead-letters
+// it when every attempt fails. It returns an error when the message could
+// not be dead-lettered either.
+func (c *Consumer) handle(msg *Message) error {
+	var err error
+	backoff := handlerBaseBackoff
+	for attempt := 1; attempt <= handlerAttempts; attempt++ {
+		if err = c.handler(msg); err == nil {
+			return nil
 		}
-
-		msgData := make([]byte, msgSize)
-		r.Read(msgData)
-
-		// Parse message: crc(4) + magic(1) + attributes(1) + key(bytes) + value(bytes)
-		if len(msgData) < 6 {
-			continue
+		log.Printf("[kafka] handler error at partition %d offset %d, attempt %d of %d (event=%s, trace=%s): %v",
+			msg.Partition, msg.Offset, attempt, handlerAttempts, msg.Header(HeaderEventID), msg.Header(HeaderTraceParent), err)
+		if attempt < handlerAttempts {
+			time.Sleep(backoff)
+			backoff *= 4
 		}
-		mr := bytes.NewReader(msgData[4:]) // skip CRC
+	}
 
-		var magic, attributes int8
-		binary.Read(mr, binary.BigEndian, &magic)
-		binary.Read(mr, binary.BigEndian, &attributes)
+	if c.deadLetters == nil {
+		return fmt.Errorf("handle message at partition %d offset %d: %w", msg.Partition, msg.Offset, err)
+	}
+	dlqTopic := DeadLetterTopic(c.topic)
+	if sendErr := c.deadLetters.Send([]*Message{deadLetterMessage(msg, dlqTopic, err, handlerAttempts)})[0]; sendErr != nil {
+		return fmt.Errorf("dead-letter message at partition %d offset %d: %w", msg.Partition, msg.Offset, sendErr)
+	}
+	log.Printf("[kafka] dead-lettered message at partition %d offset %d to %s", msg.Partition, msg.Offset, dlqTopic)
+	return nil
+}
 
-		// key
-		var keyLen int32
-		binary.Read(mr, binary.BigEndian, &keyLen)
-		if keyLen > 0 {
-			key := make([]byte, keyLen)
-			mr.Read(key)
+// commit stores the offsets that moved since the last commit, as the member
+// of the given generation
+func (c *Consumer) commit(coordinator net.Conn, generation int32) error {
+	changed := make(map[int32]int64)
+	for partition, offset := range c.offsets {
+		if committed, ok := c.committed[partition]; !ok || committed != offset {
+			changed[partition] = offset
 		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
 
-		// value
-		var valLen int32
-		binary.Read(mr, binary.BigEndian, &valLen)
-		if valLen < 0 {
-			continue // null value
-		}
-		val := make([]byte, valLen)
-		mr.Read(val)
+	if err := commitOffsets(coordinator, c.groupID, generation, c.memberID, c.topic, changed); err != nil {
+		return err
+	}
+	for partition, offset := range changed {
+		c.committed[partition] = offset
+	}
+	return nil
+}
+
+// partitionFetch is the part of a FetchResponse about one partition.
+// NextOffset is the offset following its last complete batch, or -1.
+type partitionFetch struct {
+	Partition  int32
+	ErrCode    int16
+	Messages   []Message
+	NextOffset int64
+}
 
-		msgs = append(msgs, kafkaMessage{Offset: offset, Value: val})
+// buildFetchRequest constructs the body of a Kafka FetchRequest (ApiKey=1,
+// ApiVersion=4) for the given partitions at their current offsets.
+func (c *Consumer) buildFetchRequest(partitions []int32) []byte {
+	var body bytes.Buffer
+	writeInt32(&body, -1)            // replica_id = -1 (consumer)
+	writeInt32(&body, 1000)          // max_wait_ms
+	writeInt32(&body, 1)             // min_bytes
+	writeInt32(&body, fetchMaxBytes) // max_bytes
+	body.WriteByte(0)                // isolation_level = read uncommitted
+	writeInt32(&body, 1)             // topic array length
+	writeString(&body, c.topic)      // topic name
+	writeInt32(&body, int32(len(partitions)))
+	for _, partition := range partitions {
+		writeInt32(&body, partition)
+		writeInt64(&body, c.offsets[partition]) // fetch offset
+		writeInt32(&body, fetchPartitionMaxBytes)
 	}
-	return msgs
+	return body.Bytes()
 }
 
-// pickBroker returns the first configured broker address.
-func (c *Consumer) pickBroker() string {
-	if len(c.brokers) == 0 {
-		return ""
+// readFetchResponse parses the body of a FetchResponse (v4).
+func readFetchResponse(r *bytes.Reader) ([]partitionFetch, error) {
+	r.Seek(4, io.SeekCurrent) // throttle_time_ms
+
+	var topicCount int32
+	binary.Read(r, binary.BigEndian, &topicCount)
+
+	var result []partitionFetch
+	for i := int32(0); i < topicCount; i++ {
+		readStringBuf(r) // topic name
+
+		var partCount int32
+		binary.Read(r, binary.BigEndian, &partCount)
+		for j := int32(0); j < partCount; j++ {
+			fetch := partitionFetch{NextOffset: -1}
+			var abortedCount, recordsSize int32
+
+			binary.Read(r, binary.BigEndian, &fetch.Partition)
+			binary.Read(r, binary.BigEndian, &fetch.ErrCode)
+			r.Seek(8+8, io.SeekCurrent) // high_watermark, last_stable_offset
+			binary.Read(r, binary.BigEndian, &abortedCount)
+			if abortedCount > 0 {
+				r.Seek(int64(abortedCount)*16, io.SeekCurrent) // producer_id, first_offset
+			}
+			if err := binary.Read(r, binary.BigEndian, &recordsSize); err != nil {
+				return nil, fmt.Errorf("truncated fetch response: %w", err)
+			}
+			if recordsSize < 0 {
+				recordsSize = 0 // null records
+			}
+			if int(recordsSize) > r.Len() {
+				return nil, fmt.Errorf("invalid records size: %d", recordsSize)
+			}
+
+			records := make([]byte, recordsSize)
+			r.Read(records)
+			if fetch.ErrCode == errNone {
+				fetch.Messages, fetch.NextOffset = decodeRecords(records)
+			}
+			result = append(result, fetch)
+		}
 	}
-	return c.brokers[0]
+	return result, nil
 }
 
 // ParseEvent decodes a raw Kafka message value into a NotificationEvent.
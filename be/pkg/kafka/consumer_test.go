@@ -0,0 +1,184 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"hris-backend/pkg/kafka/kafkatest"
+)
+
+// waitFor polls cond until it holds or timeout passes
+func waitFor(t *testing.T, timeout time.Duration, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestAssignRange(t *testing.T) {
+	members := []groupMember{
+		{ID: "b", Topics: []string{"events"}},
+		{ID: "a", Topics: []string{"events"}},
+		{ID: "c", Topics: []string{"other"}},
+	}
+	got := assignRange(members, "events", []int32{4, 0, 3, 1, 2})
+	want := map[string][]int32{
+		"a": {0, 1, 2},
+		"b": {3, 4},
+		"c": nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("assignRange = %v, want %v", got, want)
+	}
+}
+
+func TestConsumerGroupSplitsPartitions(t *testing.T) {
+	broker, err := kafkatest.NewBroker()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer broker.Close()
+
+	const topic, groupID, partitions = "events", "test-group", 4
+	broker.CreateTopic(topic, partitions)
+
+	var mu sync.Mutex
+	handledBy := make(map[string]string) // partition/offset → consumer
+	owners := make(map[int32]map[string]bool)
+	handler := func(name string) MessageHandler {
+		return func(msg *Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			key := fmt.Sprintf("%d/%d", msg.Partition, msg.Offset)
+			if prev, ok := handledBy[key]; ok {
+				t.Errorf("message %s handled by %s and %s", key, prev, name)
+			}
+			handledBy[key] = name
+			if owners[msg.Partition] == nil {
+				owners[msg.Partition] = make(map[string]bool)
+			}
+			owners[msg.Partition][name] = true
+			return nil
+		}
+	}
+
+	committedAll := func() bool {
+		for p := int32(0); p < partitions; p++ {
+			if _, ok := broker.Committed(groupID, topic, p); !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	NewConsumer([]string{broker.Addr()}, topic, groupID, handler("first"), nil).Start()
+	waitFor(t, 10*time.Second, "the first consumer to commit every partition", committedAll)
+
+	NewConsumer([]string{broker.Addr()}, topic, groupID, handler("second"), nil).Start()
+	waitFor(t, 20*time.Second, "both consumers to join", func() bool {
+		return len(broker.GroupMembers(groupID)) == 2
+	})
+
+	producer := NewProducer([]string{broker.Addr()}, topic, CompressionNone)
+	defer producer.Close()
+	const count = 40
+	msgs := make([]*Message, count)
+	for i := range msgs {
+		msgs[i] = &Message{Key: []byte(fmt.Sprintf("key-%d", i)), Value: []byte("value")}
+	}
+	for i, err := range producer.Send(msgs) {
+		if err != nil {
+			t.Fatalf("send message %d: %v", i, err)
+		}
+	}
+
+	waitFor(t, 20*time.Second, "every message to be handled", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(handledBy) == count
+	})
+
+	mu.Lock()
+	consumers := make(map[string]bool)
+	for partition, names := range owners {
+		if len(names) != 1 {
+			t.Errorf("partition %d read by %v, want one consumer", partition, names)
+		}
+		for name := range names {
+			consumers[name] = true
+		}
+	}
+	mu.Unlock()
+	if len(consumers) != 2 {
+		t.Errorf("messages handled by %v, want both consumers", consumers)
+	}
+
+	waitFor(t, 10*time.Second, "the offsets to be committed", func() bool {
+		for p := int32(0); p < partitions; p++ {
+			if offset, _ := broker.Committed(groupID, topic, p); offset != broker.HighWatermark(topic, p) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestCommitFromOldGenerationIsRefused(t *testing.T) {
+	broker, err := kafkatest.NewBroker()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer broker.Close()
+	broker.CreateTopic("events", 1)
+
+	conn, err := net.Dial("tcp", broker.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	joined, err := joinGroup(conn, "test-group", "", "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if joined.LeaderID != joined.MemberID || len(joined.Members) != 1 {
+		t.Fatalf("lone member should lead a group of one, got %+v", joined)
+	}
+	assignments := assignRange(joined.Members, "events", []int32{0})
+	partitions, err := syncGroup(conn, "test-group", joined.Generation, joined.MemberID, "events", assignments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(partitions, []int32{0}) {
+		t.Fatalf("assigned %v, want [0]", partitions)
+	}
+
+	offsets := map[int32]int64{0: 5}
+	if err := commitOffsets(conn, "test-group", joined.Generation, joined.MemberID, "events", offsets); err != nil {
+		t.Fatalf("commit of the current generation: %v", err)
+	}
+	err = commitOffsets(conn, "test-group", joined.Generation-1, joined.MemberID, "events", offsets)
+	if !errors.Is(err, errRebalance) {
+		t.Fatalf("commit of an old generation: got %v, want errRebalance", err)
+	}
+	err = commitOffsets(conn, "test-group", -1, "", "events", offsets)
+	if !errors.Is(err, errRebalance) {
+		t.Fatalf("standalone commit into a group with members: got %v, want errRebalance", err)
+	}
+
+	if err := leaveGroup(conn, "test-group", joined.MemberID); err != nil {
+		t.Fatal(err)
+	}
+	if err := heartbeat(conn, "test-group", joined.Generation, joined.MemberID); !errors.Is(err, errRebalance) {
+		t.Fatalf("heartbeat after leaving: got %v, want errRebalance", err)
+	}
+}
@@ -0,0 +1,181 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Headers a dead letter carries besides those of the original message. They
+// locate the original message and say why and when the consumer gave up.
+const (
+	HeaderDLQOriginalTopic     = "dlq.original_topic"
+	HeaderDLQOriginalPartition = "dlq.original_partition"
+	HeaderDLQOriginalOffset    = "dlq.original_offset"
+	HeaderDLQError             = "dlq.error"
+	HeaderDLQAttempts          = "dlq.attempts"
+	HeaderDLQFailedAt          = "dlq.failed_at"
+
+	// HeaderReplayOf is set on a replayed message to the ID of the dead
+	// letter it replays
+	HeaderReplayOf = "replay_of"
+)
+
+// DeadLetterTopic returns the dead-letter topic of topic, e.g.
+// hris.notifications.dlq
+func DeadLetterTopic(topic string) string {
+	return topic + ".dlq"
+}
+
+// deadLetterMessage builds the dead letter of msg: its key, value and
+// headers, plus the headers describing the failure
+func deadLetterMessage(msg *Message, dlqTopic string, handlerErr error, attempts int) *Message {
+	headers := make([]Header, 0, len(msg.Headers)+6)
+	for _, h := range msg.Headers {
+		if !strings.HasPrefix(h.Key, "dlq.") {
+			headers = append(headers, h)
+		}
+	}
+	headers = append(headers,
+		Header{Key: HeaderDLQOriginalTopic, Value: []byte(msg.Topic)},
+		Header{Key: HeaderDLQOriginalPartition, Value: []byte(strconv.Itoa(int(msg.Partition)))},
+		Header{Key: HeaderDLQOriginalOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		Header{Key: HeaderDLQError, Value: []byte(handlerErr.Error())},
+		Header{Key: HeaderDLQAttempts, Value: []byte(strconv.Itoa(attempts))},
+		Header{Key: HeaderDLQFailedAt, Value: []byte(time.Now().UTC().Format(time.RFC3339))},
+	)
+
+	return &Message{
+		Topic:     dlqTopic,
+		Key:       msg.Key,
+		Value:     msg.Value,
+		Headers:   headers,
+		Timestamp: msg.Timestamp,
+	}
+}
+
+// DeadLetterRecorder consumes a dead-letter topic and records its messages
+// in the database, where admins list, inspect and replay them.
+type DeadLetterRecorder struct {
+	deadLetterRepo repository.DeadLetterRepository
+}
+
+// NewDeadLetterRecorder creates a recorder writing to deadLetterRepo.
+func NewDeadLetterRecorder(deadLetterRepo repository.DeadLetterRepository) *DeadLetterRecorder {
+	return &DeadLetterRecorder{deadLetterRepo: deadLetterRepo}
+}
+
+// Handle records a dead letter. A message without the dead-letter headers is
+// recorded too, located at its own position, so nothing on the topic is
+// lost.
+func (r *DeadLetterRecorder) Handle(msg *Message) error {
+	event := model.DeadLetterEvent{
+		SourceTopic:     msg.Topic,
+		SourcePartition: msg.Partition,
+		SourceOffset:    msg.Offset,
+		EventID:         msg.Header(HeaderEventID),
+		EventType:       msg.Header(HeaderEventType),
+		Key:             string(msg.Key),
+		Message:         storableText(msg.Value),
+		Error:           msg.Header(HeaderDLQError),
+		FailedAt:        msg.Timestamp,
+	}
+
+	if topic := msg.Header(HeaderDLQOriginalTopic); topic != "" {
+		partition, errP := strconv.ParseInt(msg.Header(HeaderDLQOriginalPartition), 10, 32)
+		offset, errO := strconv.ParseInt(msg.Header(HeaderDLQOriginalOffset), 10, 64)
+		if errP == nil && errO == nil {
+			event.SourceTopic = topic
+			event.SourcePartition = int32(partition)
+			event.SourceOffset = offset
+		}
+	}
+	if attempts, err := strconv.Atoi(msg.Header(HeaderDLQAttempts)); err == nil {
+		event.Attempts = attempts
+	}
+	if failedAt, err := time.Parse(time.RFC3339, msg.Header(HeaderDLQFailedAt)); err == nil {
+		event.FailedAt = failedAt
+	}
+	if event.FailedAt.IsZero() {
+		event.FailedAt = time.Now()
+	}
+
+	// Messages published before headers existed carry their metadata only
+	// in the envelope
+	companyID := msg.Header(HeaderCompanyID)
+	if envelope, err := ParseEvent(msg.Value); err == nil {
+		if event.EventID == "" {
+			event.EventID = envelope.EventID
+		}
+		if event.EventType == "" {
+			event.EventType = string(envelope.EventType)
+		}
+		if companyID == "" {
+			companyID = envelope.CompanyID
+		}
+	}
+	if _, err := uuid.Parse(companyID); err == nil {
+		event.CompanyID = &companyID
+	}
+
+	headers := make(map[string]string)
+	for _, h := range msg.Headers {
+		if !strings.HasPrefix(h.Key, "dlq.") {
+			headers[h.Key] = string(h.Value)
+		}
+	}
+	headersJSON, err := json.Marshal(headers)
+	if err != nil {
+		return err
+	}
+	event.Headers = string(headersJSON)
+
+	// Dead letters are not tenant data of any one request
+	if err := r.deadLetterRepo.Create(context.Background(), &event); err != nil {
+		return fmt.Errorf("record dead letter of %s/%d/%d: %w", event.SourceTopic, event.SourcePartition, event.SourceOffset, err)
+	}
+	return nil
+}
+
+// storableText converts a message value to text Postgres accepts, so a
+// malformed value is recorded rather than stalling the recorder
+func storableText(value []byte) string {
+	return strings.ReplaceAll(strings.ToValidUTF8(string(value), "\uFFFD"), "\x00", "")
+}
+
+// ReplayOutboxEvent builds the outbox row that publishes a dead letter again
+// to its original topic, with its original key, headers and value. The
+// message keeps its event ID, so consumers still dedupe it, but the row gets
+// a new ID: the row of the first publish may still be in the outbox.
+func ReplayOutboxEvent(deadLetter *model.DeadLetterEvent) (model.OutboxEvent, error) {
+	headers := make(map[string]string)
+	if deadLetter.Headers != "" {
+		if err := json.Unmarshal([]byte(deadLetter.Headers), &headers); err != nil {
+			return model.OutboxEvent{}, err
+		}
+	}
+	headers[HeaderReplayOf] = deadLetter.ID
+	headersJSON, err := json.Marshal(headers)
+	if err != nil {
+		return model.OutboxEvent{}, err
+	}
+
+	return model.OutboxEvent{
+		ID:        uuid.New().String(),
+		Topic:     deadLetter.SourceTopic,
+		EventType: deadLetter.EventType,
+		CompanyID: deadLetter.CompanyID,
+		Key:       deadLetter.Key,
+		Headers:   string(headersJSON),
+		Message:   deadLetter.Message,
+		Status:    model.OutboxPending,
+	}, nil
+}
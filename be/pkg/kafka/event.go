@@ -1,34 +1,103 @@
 package kafka
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // EventType defines the type of notification event
 type EventType string
 
 const (
-	EventLeaveSubmitted    EventType = "leave.submitted"
-	EventLeaveStatusChanged EventType = "leave.status_changed"
-	EventPayrollProcessed  EventType = "payroll.processed"
+	EventLeaveSubmitted         EventType = "leave.submitted"
+	EventLeaveStatusChanged     EventType = "leave.status_changed"
+	EventPayrollProcessed       EventType = "payroll.processed"
+	EventPayrollPaid            EventType = "payroll.paid"
+	EventPasswordResetRequested EventType = "auth.password_reset_requested"
+	EventAccountLocked          EventType = "auth.account_locked"
+	EventContractEnding         EventType = "employee.contract_ending"
+	EventClockInMissing         EventType = "attendance.clock_in_missing"
+	EventModuleEnabled          EventType = "module.enabled"
+	EventModuleDisabled         EventType = "module.disabled"
+	EventNotificationDigest     EventType = "notification.digest"
+	EventAnnouncementPublished  EventType = "announcement.published"
 )
 
+// PreferenceEventTypes are the event types users choose a notification mode
+// for. Password resets are always delivered right away, and digests are
+// their own summary.
+var PreferenceEventTypes = []EventType{
+	EventLeaveSubmitted,
+	EventLeaveStatusChanged,
+	EventPayrollProcessed,
+	EventPayrollPaid,
+	EventAccountLocked,
+	EventContractEnding,
+	EventClockInMissing,
+	EventModuleEnabled,
+	EventModuleDisabled,
+	EventAnnouncementPublished,
+}
+
+// HasPreference reports whether users choose how they are notified of
+// eventType.
+func HasPreference(eventType EventType) bool {
+	for _, t := range PreferenceEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
 // Topic used for all HRIS notification events
 const TopicNotifications = "hris.notifications"
 
-// NotificationEvent is the envelope published to Kafka
+// Headers of every published event. The event metadata repeats the envelope
+// so consumers can route or drop messages without decoding them, and
+// traceparent ties the event to the request that caused it.
+const (
+	HeaderEventID       = "event_id"
+	HeaderEventType     = "event_type"
+	HeaderSchemaVersion = "schema_version"
+	HeaderCompanyID     = "company_id"
+	HeaderTraceParent   = "traceparent"
+)
+
+// SchemaVersion is the version of the event envelope and payloads. Bump it
+// on incompatible changes so consumers can tell old events from new ones.
+const SchemaVersion = 1
+
+// NotificationEvent is the envelope published to Kafka. EventID identifies
+// the event across redeliveries; events published before it existed have
+// none, and no schema version either. CompanyID is the company the event
+// happened in, empty for events outside any company.
 type NotificationEvent struct {
-	EventType EventType       `json:"event_type"`
-	Payload   json.RawMessage `json:"payload"`
+	EventID       string          `json:"event_id,omitempty"`
+	EventType     EventType       `json:"event_type"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	CompanyID     string          `json:"company_id,omitempty"`
+	SchemaVersion int             `json:"schema_version,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
 }
 
-// LeaveSubmittedPayload is sent when an employee submits a leave request
+// LeaveSubmittedPayload is sent when an employee submits a leave request,
+// and again whenever an approval moves it to its next step. ApproverUserID
+// or, for a role step, ApproverRole is the approver of the step it awaits;
+// events from before they existed have neither.
 type LeaveSubmittedPayload struct {
-	LeaveID      string `json:"leave_id"`
-	EmployeeName string `json:"employee_name"`
-	LeaveType    string `json:"leave_type"`
-	TotalDays    int    `json:"total_days"`
+	LeaveID        string  `json:"leave_id"`
+	CompanyID      string  `json:"company_id"`
+	EmployeeName   string  `json:"employee_name"`
+	LeaveType      string  `json:"leave_type"`
+	TotalDays      float64 `json:"total_days"`
+	ApproverUserID string  `json:"approver_user_id,omitempty"`
+	ApproverRole   string  `json:"approver_role,omitempty"`
 }
 
-// LeaveStatusChangedPayload is sent when a leave is approved or rejected
+// LeaveStatusChangedPayload is sent when a leave moves through its approval chain
 type LeaveStatusChangedPayload struct {
 	LeaveID         string `json:"leave_id"`
 	EmployeeUserID  string `json:"employee_user_id"`
@@ -37,7 +106,8 @@ type LeaveStatusChangedPayload struct {
 	RejectionReason string `json:"rejection_reason,omitempty"`
 }
 
-// PayrollProcessedPayload is sent when payroll status changes
+// PayrollProcessedPayload is sent when payroll status changes, with
+// EventPayrollProcessed or EventPayrollPaid
 type PayrollProcessedPayload struct {
 	PayrollID      string  `json:"payroll_id"`
 	EmployeeUserID string  `json:"employee_user_id"`
@@ -47,15 +117,124 @@ type PayrollProcessedPayload struct {
 	Status         string  `json:"status"`
 }
 
-// MarshalEvent encodes an event to JSON bytes for Kafka
-func MarshalEvent(eventType EventType, payload any) ([]byte, error) {
+// PasswordResetRequestedPayload is sent when a user asks to reset a
+// forgotten password. The reset token itself is emailed directly and never
+// travels in the event.
+type PasswordResetRequestedPayload struct {
+	UserID    string `json:"user_id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// AccountLockedPayload is sent when failed logins lock an account
+type AccountLockedPayload struct {
+	UserID      string `json:"user_id"`
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	CompanyID   string `json:"company_id"`
+	IPAddress   string `json:"ip_address"`
+	LockedUntil string `json:"locked_until"`
+}
+
+// ContractEndingPayload is sent ahead of the end of an employee's contract,
+// once for each of the notice periods it falls into
+type ContractEndingPayload struct {
+	EmployeeID      string `json:"employee_id"`
+	EmployeeUserID  string `json:"employee_user_id"`
+	EmployeeName    string `json:"employee_name"`
+	EmployeeNumber  string `json:"employee_number"`
+	CompanyID       string `json:"company_id"`
+	ContractEndDate string `json:"contract_end_date"`
+	DaysLeft        int    `json:"days_left"`
+}
+
+// ClockInMissingPayload is sent when an employee has not clocked in some
+// time after their shift started on a working day
+type ClockInMissingPayload struct {
+	EmployeeID     string `json:"employee_id"`
+	EmployeeUserID string `json:"employee_user_id"`
+	EmployeeName   string `json:"employee_name"`
+	Date           string `json:"date"`
+	ShiftName      string `json:"shift_name"`
+	ShiftStart     string `json:"shift_start"`
+}
+
+// ModuleToggledPayload is sent when a module is enabled or disabled for a
+// company, with EventModuleEnabled or EventModuleDisabled
+type ModuleToggledPayload struct {
+	CompanyID   string `json:"company_id"`
+	ModuleKey   string `json:"module_key"`
+	ModuleName  string `json:"module_name"`
+	ActorUserID string `json:"actor_user_id"`
+}
+
+// AnnouncementPublishedPayload is sent when an announcement is shown for the
+// first time. Without DepartmentIDs and JobLevelIDs it is for the whole
+// company. Summary is the start of its text.
+type AnnouncementPublishedPayload struct {
+	AnnouncementID string   `json:"announcement_id"`
+	CompanyID      string   `json:"company_id"`
+	Title          string   `json:"title"`
+	Summary        string   `json:"summary"`
+	IsPinned       bool     `json:"is_pinned"`
+	DepartmentIDs  []string `json:"department_ids,omitempty"`
+	JobLevelIDs    []string `json:"job_level_ids,omitempty"`
+	AuthorUserID   string   `json:"author_user_id"`
+	PublishAt      string   `json:"publish_at"`
+}
+
+// NotificationDigestPayload is sent once a day to each user with
+// notifications held back for their digest. Items lists the oldest
+// MaxDigestItems of Total.
+type NotificationDigestPayload struct {
+	UserID string       `json:"user_id"`
+	Date   string       `json:"date"`
+	Total  int          `json:"total"`
+	Items  []DigestItem `json:"items"`
+}
+
+// DigestItem is one notification of a digest
+type DigestItem struct {
+	EventType  string `json:"event_type"`
+	Title      string `json:"title"`
+	Message    string `json:"message"`
+	RefID      string `json:"ref_id,omitempty"`
+	RefType    string `json:"ref_type,omitempty"`
+	OccurredAt string `json:"occurred_at"`
+}
+
+// MaxDigestItems bounds the items a digest lists
+const MaxDigestItems = 50
+
+// occurrenceNamespace derives the IDs of events published once per
+// occurrence
+var occurrenceNamespace = uuid.MustParse("6f1c2d0e-4b7a-4c55-9a3e-2f8d6b1e7c90")
+
+// OccurrenceEventID returns the event ID of one occurrence of an event type,
+// such as the end of one employee's contract. Events found by periodic scans
+// use it: every scan finding the same occurrence yields the same ID, so the
+// occurrence is published and notified once.
+func OccurrenceEventID(eventType EventType, occurrence string) string {
+	return uuid.NewSHA1(occurrenceNamespace, []byte(string(eventType)+"/"+occurrence)).String()
+}
+
+// NewEvent wraps a payload in an envelope with a new event ID
+func NewEvent(eventType EventType, companyID string, payload any) (*NotificationEvent, error) {
+	return newEvent(uuid.New().String(), eventType, companyID, payload)
+}
+
+func newEvent(eventID string, eventType EventType, companyID string, payload any) (*NotificationEvent, error) {
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
-	env := NotificationEvent{
-		EventType: eventType,
-		Payload:   json.RawMessage(payloadBytes),
-	}
-	return json.Marshal(env)
+	return &NotificationEvent{
+		EventID:       eventID,
+		EventType:     eventType,
+		OccurredAt:    time.Now().UTC(),
+		CompanyID:     companyID,
+		SchemaVersion: SchemaVersion,
+		Payload:       json.RawMessage(payloadBytes),
+	}, nil
 }
@@ -0,0 +1,375 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sort"
+	"time"
+)
+
+const (
+	// A member that sends no heartbeat for sessionTimeout is dropped from
+	// its group. The consumer heartbeats every heartbeatInterval.
+	sessionTimeout    = 30 * time.Second
+	heartbeatInterval = 3 * time.Second
+
+	// rebalanceTimeout is how long the coordinator waits for every member to
+	// rejoin when the group rebalances. A member finishes the message it is
+	// handling first, so it must exceed the handler's retries.
+	rebalanceTimeout = 60 * time.Second
+
+	// The consumer protocol and the range assignor of the Java client, so
+	// the group can be inspected with the usual tools
+	groupProtocolType = "consumer"
+	groupAssignor     = "range"
+)
+
+// errRebalance is returned by the group requests when the consumer must
+// rejoin its group: the group is rebalancing, moved to a new generation or
+// no longer knows the member
+var errRebalance = errors.New("group is rebalancing")
+
+// rebalanceError maps the error codes that call for a rejoin to errRebalance
+func rebalanceError(errCode int16) error {
+	switch errCode {
+	case errIllegalGeneration, errUnknownMemberID, errRebalanceInProgress:
+		return fmt.Errorf("%w (kafka error code %d)", errRebalance, errCode)
+	}
+	return fmt.Errorf("kafka error code %d", errCode)
+}
+
+// groupMember is a member of the group as the coordinator reports it to the
+// group's leader, with the topics it subscribes to
+type groupMember struct {
+	ID     string
+	Topics []string
+}
+
+// joinResult is the outcome of JoinGroup: the generation the member joined,
+// and for the leader only, every member of that generation
+type joinResult struct {
+	Generation int32
+	MemberID   string
+	LeaderID   string
+	Members    []groupMember
+}
+
+// joinGroup asks the coordinator to add the member to groupID, subscribed to
+// topic (JoinGroup v1). memberID is empty on the first join. The coordinator
+// holds the response until every member has rejoined, up to
+// rebalanceTimeout.
+func joinGroup(conn net.Conn, groupID, memberID, topic string) (*joinResult, error) {
+	var body bytes.Buffer
+	writeString(&body, groupID)
+	writeInt32(&body, int32(sessionTimeout.Milliseconds()))
+	writeInt32(&body, int32(rebalanceTimeout.Milliseconds()))
+	writeString(&body, memberID)
+	writeString(&body, groupProtocolType)
+	writeInt32(&body, 1) // protocol array length
+	writeString(&body, groupAssignor)
+	writeBytes(&body, encodeSubscription(topic))
+
+	r, err := roundTripWithin(conn, apiJoinGroup, 1, body.Bytes(), rebalanceTimeout+requestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var errCode int16
+	result := &joinResult{}
+	binary.Read(r, binary.BigEndian, &errCode)
+	binary.Read(r, binary.BigEndian, &result.Generation)
+	readStringBuf(r) // protocol name
+	result.LeaderID = readStringBuf(r)
+	result.MemberID = readStringBuf(r)
+	if errCode != errNone {
+		return nil, fmt.Errorf("join group %s: %w", groupID, rebalanceError(errCode))
+	}
+
+	var memberCount int32
+	binary.Read(r, binary.BigEndian, &memberCount)
+	for i := int32(0); i < memberCount; i++ {
+		id := readStringBuf(r)
+		topics, err := decodeSubscription(readBytesBuf(r))
+		if err != nil {
+			return nil, fmt.Errorf("join group %s: member %s: %w", groupID, id, err)
+		}
+		result.Members = append(result.Members, groupMember{ID: id, Topics: topics})
+	}
+	return result, nil
+}
+
+// syncGroup ends a rebalance (SyncGroup v0). The leader passes the
+// assignment of every member, the others none; each gets back the partitions
+// of topic assigned to it.
+func syncGroup(conn net.Conn, groupID string, generation int32, memberID, topic string, assignments map[string][]int32) ([]int32, error) {
+	var body bytes.Buffer
+	writeString(&body, groupID)
+	writeInt32(&body, generation)
+	writeString(&body, memberID)
+	writeInt32(&body, int32(len(assignments)))
+	for member, partitions := range assignments {
+		writeString(&body, member)
+		writeBytes(&body, encodeAssignment(topic, partitions))
+	}
+
+	r, err := roundTripWithin(conn, apiSyncGroup, 0, body.Bytes(), rebalanceTimeout+requestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var errCode int16
+	binary.Read(r, binary.BigEndian, &errCode)
+	if errCode != errNone {
+		return nil, fmt.Errorf("sync group %s: %w", groupID, rebalanceError(errCode))
+	}
+	partitions, err := decodeAssignment(readBytesBuf(r), topic)
+	if err != nil {
+		return nil, fmt.Errorf("sync group %s: %w", groupID, err)
+	}
+	return partitions, nil
+}
+
+// heartbeat tells the coordinator the member is alive (Heartbeat v0). It
+// returns errRebalance once the group needs the member to rejoin.
+func heartbeat(conn net.Conn, groupID string, generation int32, memberID string) error {
+	var body bytes.Buffer
+	writeString(&body, groupID)
+	writeInt32(&body, generation)
+	writeString(&body, memberID)
+
+	r, err := roundTrip(conn, apiHeartbeat, 0, body.Bytes())
+	if err != nil {
+		return err
+	}
+
+	var errCode int16
+	binary.Read(r, binary.BigEndian, &errCode)
+	if errCode != errNone {
+		return fmt.Errorf("heartbeat: %w", rebalanceError(errCode))
+	}
+	return nil
+}
+
+// leaveGroup removes the member from groupID (LeaveGroup v0), so the others
+// take over its partitions without waiting for its session to expire
+func leaveGroup(conn net.Conn, groupID, memberID string) error {
+	var body bytes.Buffer
+	writeString(&body, groupID)
+	writeString(&body, memberID)
+
+	r, err := roundTrip(conn, apiLeaveGroup, 0, body.Bytes())
+	if err != nil {
+		return err
+	}
+
+	var errCode int16
+	binary.Read(r, binary.BigEndian, &errCode)
+	if errCode != errNone && errCode != errUnknownMemberID {
+		return fmt.Errorf("leave group %s: kafka error code %d", groupID, errCode)
+	}
+	return nil
+}
+
+// assignRange spreads the partitions of topic over the members subscribed to
+// it as the range assignor does: members in ID order each take a contiguous
+// run of partitions, the first ones one more when they do not divide evenly.
+// Every member gets an entry, if only an empty one.
+func assignRange(members []groupMember, topic string, partitions []int32) map[string][]int32 {
+	assignments := make(map[string][]int32, len(members))
+	var subscribed []string
+	for _, member := range members {
+		assignments[member.ID] = nil
+		for _, t := range member.Topics {
+			if t == topic {
+				subscribed = append(subscribed, member.ID)
+				break
+			}
+		}
+	}
+	if len(subscribed) == 0 {
+		return assignments
+	}
+
+	sort.Strings(subscribed)
+	sorted := append([]int32(nil), partitions...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	per, extra := len(sorted)/len(subscribed), len(sorted)%len(subscribed)
+	start := 0
+	for i, member := range subscribed {
+		n := per
+		if i < extra {
+			n++
+		}
+		assignments[member] = sorted[start : start+n]
+		start += n
+	}
+	return assignments
+}
+
+// encodeSubscription builds the member metadata of the consumer protocol
+// (version 0): the subscribed topics and no user data
+func encodeSubscription(topic string) []byte {
+	var b bytes.Buffer
+	writeInt16(&b, 0) // version
+	writeInt32(&b, 1) // topic array length
+	writeString(&b, topic)
+	writeInt32(&b, -1) // user_data = null
+	return b.Bytes()
+}
+
+func decodeSubscription(data []byte) ([]string, error) {
+	r := bytes.NewReader(data)
+	var version int16
+	var topicCount int32
+	binary.Read(r, binary.BigEndian, &version)
+	if err := binary.Read(r, binary.BigEndian, &topicCount); err != nil {
+		return nil, fmt.Errorf("truncated subscription: %w", err)
+	}
+	if topicCount < 0 || int(topicCount) > r.Len()/2 {
+		return nil, fmt.Errorf("invalid subscription topic count: %d", topicCount)
+	}
+	topics := make([]string, 0, topicCount)
+	for i := int32(0); i < topicCount; i++ {
+		topics = append(topics, readStringBuf(r))
+	}
+	return topics, nil
+}
+
+// encodeAssignment builds a member assignment of the consumer protocol
+// (version 0)
+func encodeAssignment(topic string, partitions []int32) []byte {
+	var b bytes.Buffer
+	writeInt16(&b, 0) // version
+	writeInt32(&b, 1) // topic array length
+	writeString(&b, topic)
+	writeInt32(&b, int32(len(partitions)))
+	for _, partition := range partitions {
+		writeInt32(&b, partition)
+	}
+	writeInt32(&b, -1) // user_data = null
+	return b.Bytes()
+}
+
+// decodeAssignment returns the partitions of topic in a member assignment.
+// An empty assignment assigns nothing.
+func decodeAssignment(data []byte, topic string) ([]int32, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	r := bytes.NewReader(data)
+	var version int16
+	var topicCount int32
+	binary.Read(r, binary.BigEndian, &version)
+	if err := binary.Read(r, binary.BigEndian, &topicCount); err != nil {
+		return nil, fmt.Errorf("truncated assignment: %w", err)
+	}
+
+	var partitions []int32
+	for i := int32(0); i < topicCount; i++ {
+		name := readStringBuf(r)
+		var partCount int32
+		if err := binary.Read(r, binary.BigEndian, &partCount); err != nil {
+			return nil, fmt.Errorf("truncated assignment: %w", err)
+		}
+		if partCount < 0 || int(partCount) > r.Len()/4 {
+			return nil, fmt.Errorf("invalid assignment partition count: %d", partCount)
+		}
+		for j := int32(0); j < partCount; j++ {
+			var partition int32
+			binary.Read(r, binary.BigEndian, &partition)
+			if name == topic {
+				partitions = append(partitions, partition)
+			}
+		}
+	}
+	return partitions, nil
+}
+
+// groupSession is the consumer's membership of its group for one
+// generation. A goroutine heartbeats on a connection of its own and closes
+// rebalance when the consumer has to rejoin.
+type groupSession struct {
+	generation int32
+	partitions []int32
+	rebalance  chan struct{}
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// startHeartbeat begins heartbeating for the generation the consumer joined
+func (c *Consumer) startHeartbeat(coordinatorAddr string, generation int32, partitions []int32) (*groupSession, error) {
+	conn, err := net.DialTimeout("tcp", coordinatorAddr, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s: %w", coordinatorAddr, err)
+	}
+
+	session := &groupSession{
+		generation: generation,
+		partitions: partitions,
+		rebalance:  make(chan struct{}),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	memberID := c.memberID
+	go func() {
+		defer close(session.done)
+		defer conn.Close()
+
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-session.stop:
+				return
+			case <-ticker.C:
+			}
+			if err := heartbeat(conn, c.groupID, generation, memberID); err != nil {
+				if !errors.Is(err, errRebalance) {
+					log.Printf("[kafka] consumer heartbeat failed (group=%s): %v", c.groupID, err)
+				}
+				close(session.rebalance)
+				return
+			}
+		}
+	}()
+	return session, nil
+}
+
+// rebalancing reports whether the consumer has to rejoin its group
+func (s *groupSession) rebalancing() bool {
+	select {
+	case <-s.rebalance:
+		return true
+	default:
+		return false
+	}
+}
+
+// close stops the heartbeat and waits for it to finish
+func (s *groupSession) close() {
+	close(s.stop)
+	<-s.done
+}
+
+func writeBytes(w *bytes.Buffer, b []byte) {
+	writeInt32(w, int32(len(b)))
+	w.Write(b)
+}
+
+// readBytesBuf reads a length-prefixed byte string; null reads as empty
+func readBytesBuf(r *bytes.Reader) []byte {
+	var l int32
+	binary.Read(r, binary.BigEndian, &l)
+	if l <= 0 || int(l) > r.Len() {
+		return nil
+	}
+	b := make([]byte, l)
+	io.ReadFull(r, b)
+	return b
+}
@@ -0,0 +1,726 @@
+// Package kafkatest provides an in-process Kafka broker for tests of code
+// that produces or consumes with pkg/kafka, so they run without a cluster.
+package kafkatest
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// API keys and the versions the broker speaks, which are the ones pkg/kafka
+// uses
+const (
+	apiProduce         int16 = 0
+	apiFetch           int16 = 1
+	apiListOffsets     int16 = 2
+	apiMetadata        int16 = 3
+	apiOffsetCommit    int16 = 8
+	apiOffsetFetch     int16 = 9
+	apiFindCoordinator int16 = 10
+	apiJoinGroup       int16 = 11
+	apiHeartbeat       int16 = 12
+	apiLeaveGroup      int16 = 13
+	apiSyncGroup       int16 = 14
+	apiVersions        int16 = 18
+)
+
+var supportedVersions = map[int16]int16{
+	apiProduce:         3,
+	apiFetch:           4,
+	apiListOffsets:     1,
+	apiMetadata:        1,
+	apiOffsetCommit:    2,
+	apiOffsetFetch:     1,
+	apiFindCoordinator: 0,
+	apiJoinGroup:       1,
+	apiHeartbeat:       0,
+	apiLeaveGroup:      0,
+	apiSyncGroup:       0,
+	apiVersions:        0,
+}
+
+// Kafka error codes the broker returns
+const (
+	errNone                    int16 = 0
+	errOffsetOutOfRange        int16 = 1
+	errCorruptMessage          int16 = 2
+	errUnknownTopicOrPartition int16 = 3
+	errIllegalGeneration       int16 = 22
+	errUnknownMemberID         int16 = 25
+	errRebalanceInProgress     int16 = 27
+)
+
+// errBrokerClosed ends the requests waiting on a group when the broker closes
+var errBrokerClosed = errors.New("broker closed")
+
+// nodeID is the ID of the only broker of the cluster
+const nodeID = 1
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// Broker is a single-node Kafka cluster on a local port. It keeps record
+// batches in memory and assigns their offsets as a real broker does, serves
+// fetches with long polling and stores committed group offsets. Topics are
+// created on first use with DefaultPartitions partitions, or up front with
+// CreateTopic.
+//
+// It coordinates consumer groups as well: members join, the leader's
+// assignments are handed out, heartbeats report rebalances, and a commit by
+// a member of an old generation is refused. It does not replicate, compress
+// or compact; the consumer of pkg/kafka needs none of that.
+type Broker struct {
+	// DefaultPartitions is the partition count of auto-created topics
+	DefaultPartitions int32
+
+	ln net.Listener
+	wg sync.WaitGroup
+
+	mu        sync.Mutex
+	topics    map[string][]*partitionLog
+	committed map[string]map[string]map[int32]int64 // group → topic → partition
+	groups    map[string]*group
+	conns     map[net.Conn]struct{}
+	closed    bool
+
+	// done is closed by Close, to end the requests waiting on a group
+	done chan struct{}
+
+	// produced is closed and replaced whenever records are appended, to
+	// wake up waiting fetches
+	produced chan struct{}
+}
+
+type partitionLog struct {
+	logStart int64
+	next     int64 // high watermark
+	batches  []storedBatch
+}
+
+type storedBatch struct {
+	baseOffset   int64
+	lastOffset   int64
+	maxTimestamp int64
+	data         []byte
+}
+
+// NewBroker starts a broker listening on a random local port.
+func NewBroker() (*Broker, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Broker{
+		DefaultPartitions: 1,
+		ln:                ln,
+		topics:            make(map[string][]*partitionLog),
+		committed:         make(map[string]map[string]map[int32]int64),
+		groups:            make(map[string]*group),
+		conns:             make(map[net.Conn]struct{}),
+		done:              make(chan struct{}),
+		produced:          make(chan struct{}),
+	}
+	b.wg.Add(1)
+	go b.accept()
+	return b, nil
+}
+
+// Addr returns the host:port to use as the bootstrap broker.
+func (b *Broker) Addr() string {
+	return b.ln.Addr().String()
+}
+
+// Close stops the broker and drops its connections.
+func (b *Broker) Close() error {
+	b.mu.Lock()
+	if !b.closed {
+		close(b.done)
+	}
+	b.closed = true
+	for conn := range b.conns {
+		conn.Close()
+	}
+	b.mu.Unlock()
+
+	err := b.ln.Close()
+	b.wg.Wait()
+	return err
+}
+
+// CreateTopic creates topic with the given number of partitions. It does
+// nothing if the topic exists.
+func (b *Broker) CreateTopic(topic string, partitions int32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.topicLocked(topic, partitions)
+}
+
+// HighWatermark returns the offset the next record of a partition gets, or
+// -1 for an unknown partition.
+func (b *Broker) HighWatermark(topic string, partition int32) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if p := b.partitionLocked(topic, partition); p != nil {
+		return p.next
+	}
+	return -1
+}
+
+// Batches returns the record batches of a partition as stored, with their
+// assigned base offsets.
+func (b *Broker) Batches(topic string, partition int32) [][]byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p := b.partitionLocked(topic, partition)
+	if p == nil {
+		return nil
+	}
+	batches := make([][]byte, len(p.batches))
+	for i, batch := range p.batches {
+		batches[i] = append([]byte(nil), batch.data...)
+	}
+	return batches
+}
+
+// Committed returns the offset group committed for a partition.
+func (b *Broker) Committed(group, topic string, partition int32) (int64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	offset, ok := b.committed[group][topic][partition]
+	return offset, ok
+}
+
+// DeleteRecordsBefore drops the batches of a partition that end before
+// offset, as retention would, so fetches below it are out of range.
+func (b *Broker) DeleteRecordsBefore(topic string, partition int32, offset int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p := b.partitionLocked(topic, partition)
+	if p == nil {
+		return
+	}
+	for len(p.batches) > 0 && p.batches[0].lastOffset < offset {
+		p.batches = p.batches[1:]
+	}
+	p.logStart = p.next
+	if len(p.batches) > 0 {
+		p.logStart = p.batches[0].baseOffset
+	}
+}
+
+func (b *Broker) topicLocked(topic string, partitions int32) []*partitionLog {
+	if logs, ok := b.topics[topic]; ok {
+		return logs
+	}
+	logs := make([]*partitionLog, partitions)
+	for i := range logs {
+		logs[i] = &partitionLog{}
+	}
+	b.topics[topic] = logs
+	return logs
+}
+
+func (b *Broker) partitionLocked(topic string, partition int32) *partitionLog {
+	logs := b.topics[topic]
+	if partition < 0 || int(partition) >= len(logs) {
+		return nil
+	}
+	return logs[partition]
+}
+
+func (b *Broker) accept() {
+	defer b.wg.Done()
+	for {
+		conn, err := b.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		b.mu.Lock()
+		if b.closed {
+			b.mu.Unlock()
+			conn.Close()
+			return
+		}
+		b.conns[conn] = struct{}{}
+		b.mu.Unlock()
+
+		b.wg.Add(1)
+		go b.serve(conn)
+	}
+}
+
+// serve answers the requests of one connection in order. Like a real
+// broker, it closes the connection on a request it cannot parse or an API
+// version it does not speak.
+func (b *Broker) serve(conn net.Conn) {
+	defer b.wg.Done()
+	defer func() {
+		b.mu.Lock()
+		delete(b.conns, conn)
+		b.mu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		var size int32
+		if err := binary.Read(conn, binary.BigEndian, &size); err != nil || size < 8 {
+			return
+		}
+		frame := make([]byte, size)
+		if _, err := io.ReadFull(conn, frame); err != nil {
+			return
+		}
+
+		r := &reader{data: frame}
+		apiKey, apiVersion, correlationID := r.int16(), r.int16(), r.int32()
+		r.string() // client_id
+		if max, ok := supportedVersions[apiKey]; !ok || apiVersion != max {
+			return
+		}
+
+		body, err := b.handle(apiKey, r)
+		if err != nil {
+			return
+		}
+
+		var resp writer
+		resp.int32(int32(4 + len(body)))
+		resp.int32(correlationID)
+		resp.Write(body)
+		if _, err := conn.Write(resp.Bytes()); err != nil {
+			return
+		}
+	}
+}
+
+func (b *Broker) handle(apiKey int16, r *reader) ([]byte, error) {
+	var w writer
+	switch apiKey {
+	case apiVersions:
+		b.handleAPIVersions(&w)
+	case apiMetadata:
+		b.handleMetadata(r, &w)
+	case apiProduce:
+		b.handleProduce(r, &w)
+	case apiFetch:
+		b.handleFetch(r, &w)
+	case apiListOffsets:
+		b.handleListOffsets(r, &w)
+	case apiFindCoordinator:
+		r.string() // group
+		w.int16(errNone)
+		b.writeNode(&w)
+	case apiOffsetFetch:
+		b.handleOffsetFetch(r, &w)
+	case apiOffsetCommit:
+		b.handleOffsetCommit(r, &w)
+	case apiJoinGroup:
+		b.handleJoinGroup(r, &w)
+	case apiSyncGroup:
+		b.handleSyncGroup(r, &w)
+	case apiHeartbeat:
+		b.handleHeartbeat(r, &w)
+	case apiLeaveGroup:
+		b.handleLeaveGroup(r, &w)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return w.Bytes(), nil
+}
+
+// writeNode writes the node ID, host and port of the broker
+func (b *Broker) writeNode(w *writer) {
+	host, port, _ := net.SplitHostPort(b.Addr())
+	portNum, _ := strconv.Atoi(port)
+	w.int32(nodeID)
+	w.string(host)
+	w.int32(int32(portNum))
+}
+
+func (b *Broker) handleAPIVersions(w *writer) {
+	w.int16(errNone)
+	w.int32(int32(len(supportedVersions)))
+	for apiKey, version := range supportedVersions {
+		w.int16(apiKey)
+		w.int16(version) // min
+		w.int16(version) // max
+	}
+}
+
+// handleMetadata answers Metadata v1. A null topic list asks for every
+// topic; named topics are created if they do not exist.
+func (b *Broker) handleMetadata(r *reader, w *writer) {
+	var names []string
+	count := r.int32()
+	for i := int32(0); i < count; i++ {
+		names = append(names, r.string())
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if count < 0 {
+		for name := range b.topics {
+			names = append(names, name)
+		}
+	}
+
+	w.int32(1) // brokers
+	b.writeNode(w)
+	w.int16(-1)     // rack = null
+	w.int32(nodeID) // controller_id
+
+	w.int32(int32(len(names)))
+	for _, name := range names {
+		logs := b.topicLocked(name, b.DefaultPartitions)
+		w.int16(errNone)
+		w.string(name)
+		w.int8(0) // is_internal
+		w.int32(int32(len(logs)))
+		for i := range logs {
+			w.int16(errNone)
+			w.int32(int32(i))
+			w.int32(nodeID) // leader
+			w.int32(1)      // replicas
+			w.int32(nodeID)
+			w.int32(1) // isr
+			w.int32(nodeID)
+		}
+	}
+}
+
+// handleProduce answers Produce v3: it checks each record batch and appends
+// it with offsets assigned from the partition's high watermark.
+func (b *Broker) handleProduce(r *reader, w *writer) {
+	r.string() // transactional_id
+	r.int16()  // acks
+	r.int32()  // timeout_ms
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	topicCount := r.int32()
+	w.int32(topicCount)
+	for i := int32(0); i < topicCount; i++ {
+		topic := r.string()
+		w.string(topic)
+		logs := b.topicLocked(topic, b.DefaultPartitions)
+
+		partCount := r.int32()
+		w.int32(partCount)
+		for j := int32(0); j < partCount; j++ {
+			partition := r.int32()
+			records := r.bytes()
+
+			errCode, baseOffset := errNone, int64(-1)
+			if partition < 0 || int(partition) >= len(logs) {
+				errCode = errUnknownTopicOrPartition
+			} else if batches, err := splitBatches(records); err != nil {
+				errCode = errCorruptMessage
+			} else {
+				p := logs[partition]
+				baseOffset = p.next
+				for _, data := range batches {
+					p.append(data)
+				}
+				close(b.produced)
+				b.produced = make(chan struct{})
+			}
+
+			w.int32(partition)
+			w.int16(errCode)
+			w.int64(baseOffset)
+			w.int64(-1) // log_append_time = none
+		}
+	}
+	w.int32(0) // throttle_time_ms
+}
+
+// append stores a checked record batch at the end of the log
+func (p *partitionLog) append(data []byte) {
+	lastOffsetDelta := int64(int32(binary.BigEndian.Uint32(data[23:])))
+	binary.BigEndian.PutUint64(data, uint64(p.next))
+	p.batches = append(p.batches, storedBatch{
+		baseOffset:   p.next,
+		lastOffset:   p.next + lastOffsetDelta,
+		maxTimestamp: int64(binary.BigEndian.Uint64(data[35:])),
+		data:         data,
+	})
+	p.next += lastOffsetDelta + 1
+}
+
+// splitBatches splits produced records into record batches (magic 2) and
+// checks their CRCs
+func splitBatches(records []byte) ([][]byte, error) {
+	var batches [][]byte
+	for len(records) > 0 {
+		if len(records) < 61 {
+			return nil, errors.New("truncated batch")
+		}
+		size := int(int32(binary.BigEndian.Uint32(records[8:])))
+		if size < 49 || size > len(records)-12 {
+			return nil, errors.New("invalid batch length")
+		}
+		data := append([]byte(nil), records[:12+size]...)
+		records = records[12+size:]
+
+		if data[16] != 2 {
+			return nil, errors.New("unsupported message format")
+		}
+		if binary.BigEndian.Uint32(data[17:]) != crc32.Checksum(data[21:], crc32c) {
+			return nil, errors.New("crc mismatch")
+		}
+		batches = append(batches, data)
+	}
+	if len(batches) == 0 {
+		return nil, errors.New("no record batch")
+	}
+	return batches, nil
+}
+
+type fetchPartition struct {
+	partition int32
+	offset    int64
+	maxBytes  int32
+}
+
+type fetchTopic struct {
+	name       string
+	partitions []fetchPartition
+}
+
+// handleFetch answers Fetch v4. When no requested partition has records
+// past its offset, it waits up to max_wait_ms for a produce.
+func (b *Broker) handleFetch(r *reader, w *writer) {
+	r.int32() // replica_id
+	maxWait := time.Duration(r.int32()) * time.Millisecond
+	r.int32() // min_bytes
+	r.int32() // max_bytes
+	r.int8()  // isolation_level
+
+	var topics []fetchTopic
+	topicCount := r.int32()
+	for i := int32(0); i < topicCount && r.err == nil; i++ {
+		t := fetchTopic{name: r.string()}
+		partCount := r.int32()
+		for j := int32(0); j < partCount && r.err == nil; j++ {
+			t.partitions = append(t.partitions, fetchPartition{
+				partition: r.int32(),
+				offset:    r.int64(),
+				maxBytes:  r.int32(),
+			})
+		}
+		topics = append(topics, t)
+	}
+	if r.err != nil {
+		return
+	}
+
+	deadline := time.Now().Add(maxWait)
+	for {
+		b.mu.Lock()
+		body, hasData := b.fetchLocked(topics)
+		produced := b.produced
+		b.mu.Unlock()
+
+		remaining := time.Until(deadline)
+		if hasData || remaining <= 0 {
+			w.Write(body)
+			return
+		}
+		select {
+		case <-produced:
+		case <-time.After(remaining):
+		case <-b.done:
+			w.Write(body)
+			return
+		}
+	}
+}
+
+// fetchLocked builds a FetchResponse body and reports whether it holds
+// records or errors, which end the wait
+func (b *Broker) fetchLocked(topics []fetchTopic) ([]byte, bool) {
+	var w writer
+	hasData := false
+
+	w.int32(0) // throttle_time_ms
+	w.int32(int32(len(topics)))
+	for _, t := range topics {
+		w.string(t.name)
+		w.int32(int32(len(t.partitions)))
+		for _, fp := range t.partitions {
+			var records []byte
+			errCode, highWatermark := errNone, int64(-1)
+
+			p := b.partitionLocked(t.name, fp.partition)
+			switch {
+			case p == nil:
+				errCode = errUnknownTopicOrPartition
+			case fp.offset < p.logStart || fp.offset > p.next:
+				errCode = errOffsetOutOfRange
+				highWatermark = p.next
+			default:
+				highWatermark = p.next
+				for _, batch := range p.batches {
+					if batch.lastOffset < fp.offset {
+						continue
+					}
+					// The first batch is returned whatever its size, so
+					// a large batch cannot stall the consumer
+					if len(records) > 0 && len(records)+len(batch.data) > int(fp.maxBytes) {
+						break
+					}
+					records = append(records, batch.data...)
+				}
+			}
+			if errCode != errNone || len(records) > 0 {
+				hasData = true
+			}
+
+			w.int32(fp.partition)
+			w.int16(errCode)
+			w.int64(highWatermark)
+			w.int64(highWatermark) // last_stable_offset
+			w.int32(-1)            // aborted_transactions = null
+			w.bytes(records)
+		}
+	}
+	return w.Bytes(), hasData
+}
+
+// handleListOffsets answers ListOffsets v1: -1 asks for the latest offset,
+// -2 for the earliest and any other timestamp for the first batch at or
+// after it.
+func (b *Broker) handleListOffsets(r *reader, w *writer) {
+	r.int32() // replica_id
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	topicCount := r.int32()
+	w.int32(topicCount)
+	for i := int32(0); i < topicCount; i++ {
+		topic := r.string()
+		w.string(topic)
+		partCount := r.int32()
+		w.int32(partCount)
+		for j := int32(0); j < partCount; j++ {
+			partition, timestamp := r.int32(), r.int64()
+			w.int32(partition)
+
+			p := b.partitionLocked(topic, partition)
+			if p == nil {
+				w.int16(errUnknownTopicOrPartition)
+				w.int64(-1)
+				w.int64(-1)
+				continue
+			}
+
+			offset := p.next
+			switch timestamp {
+			case -1:
+			case -2:
+				offset = p.logStart
+			default:
+				for _, batch := range p.batches {
+					if batch.maxTimestamp >= timestamp {
+						offset = batch.baseOffset
+						break
+					}
+				}
+			}
+			w.int16(errNone)
+			w.int64(-1) // timestamp
+			w.int64(offset)
+		}
+	}
+}
+
+// handleOffsetFetch answers OffsetFetch v1 with -1 for partitions the group
+// never committed.
+func (b *Broker) handleOffsetFetch(r *reader, w *writer) {
+	group := r.string()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	topicCount := r.int32()
+	w.int32(topicCount)
+	for i := int32(0); i < topicCount; i++ {
+		topic := r.string()
+		w.string(topic)
+		partCount := r.int32()
+		w.int32(partCount)
+		for j := int32(0); j < partCount; j++ {
+			partition := r.int32()
+			offset, ok := b.committed[group][topic][partition]
+			if !ok {
+				offset = -1
+			}
+			w.int32(partition)
+			w.int64(offset)
+			w.string("") // metadata
+			w.int16(errNone)
+		}
+	}
+}
+
+// handleOffsetCommit answers OffsetCommit v2. A group with members only
+// takes commits from a member of its current generation; an empty one also
+// from standalone consumers, with generation -1.
+func (b *Broker) handleOffsetCommit(r *reader, w *writer) {
+	group := r.string()
+	generation := r.int32()
+	memberID := r.string()
+	r.int64() // retention_time_ms
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	errCode := errNone
+	if g, ok := b.groups[group]; ok && (len(g.members) > 0 || generation >= 0) {
+		errCode = g.checkMemberLocked(memberID, generation)
+		if errCode == errNone {
+			g.members[memberID].lastSeen = time.Now()
+		}
+	} else if generation >= 0 {
+		errCode = errUnknownMemberID
+	}
+
+	topicCount := r.int32()
+	w.int32(topicCount)
+	for i := int32(0); i < topicCount; i++ {
+		topic := r.string()
+		w.string(topic)
+		partCount := r.int32()
+		w.int32(partCount)
+		for j := int32(0); j < partCount; j++ {
+			partition, offset := r.int32(), r.int64()
+			r.string() // metadata
+			if r.err != nil {
+				return
+			}
+			w.int32(partition)
+			w.int16(errCode)
+			if errCode != errNone {
+				continue
+			}
+
+			if b.committed[group] == nil {
+				b.committed[group] = make(map[string]map[int32]int64)
+			}
+			if b.committed[group][topic] == nil {
+				b.committed[group][topic] = make(map[int32]int64)
+			}
+			b.committed[group][topic][partition] = offset
+		}
+	}
+}
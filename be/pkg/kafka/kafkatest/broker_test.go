@@ -0,0 +1,208 @@
+package kafkatest_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"hris-backend/pkg/kafka"
+	"hris-backend/pkg/kafka/kafkatest"
+)
+
+const partitions = 3
+
+// startConsumer runs a consumer of topic that collects what it receives,
+// and waits until it has a position on every partition, so it gets every
+// message produced afterwards
+func startConsumer(t *testing.T, broker *kafkatest.Broker, topic string) func() []kafka.Message {
+	t.Helper()
+	var mu sync.Mutex
+	var received []kafka.Message
+	kafka.NewConsumer([]string{broker.Addr()}, topic, "test-group", func(msg *kafka.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, *msg)
+		return nil
+	}, nil).Start()
+
+	waitFor(t, "the consumer to join", func() bool {
+		for p := int32(0); p < partitions; p++ {
+			if _, ok := broker.Committed("test-group", topic, p); !ok {
+				return false
+			}
+		}
+		return true
+	})
+	return func() []kafka.Message {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]kafka.Message(nil), received...)
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestProduceFetchRoundTrip(t *testing.T) {
+	for _, codec := range []kafka.Compression{kafka.CompressionNone, kafka.CompressionGzip, kafka.CompressionSnappy} {
+		t.Run(codec.String(), func(t *testing.T) {
+			broker, err := kafkatest.NewBroker()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer broker.Close()
+			broker.CreateTopic("events", partitions)
+			received := startConsumer(t, broker, "events")
+
+			var sent []*kafka.Message
+			for i := 0; i < 30; i++ {
+				msg := &kafka.Message{
+					Key:   []byte(fmt.Sprintf("employee-%d", i%5)),
+					Value: bytes.Repeat([]byte(fmt.Sprintf("payload %d ", i)), 20),
+					Headers: []kafka.Header{
+						{Key: kafka.HeaderEventID, Value: []byte(fmt.Sprintf("event-%d", i))},
+						{Key: "empty", Value: []byte{}},
+					},
+				}
+				if i%10 == 9 {
+					msg.Key = nil
+				}
+				sent = append(sent, msg)
+			}
+
+			producer := kafka.NewProducer([]string{broker.Addr()}, "events", codec)
+			defer producer.Close()
+			// Two sends, so partitions hold more than one batch
+			for _, batch := range [][]*kafka.Message{sent[:15], sent[15:]} {
+				for i, err := range producer.Send(batch) {
+					if err != nil {
+						t.Fatalf("send message %d: %v", i, err)
+					}
+				}
+			}
+
+			waitFor(t, "every message", func() bool { return len(received()) == len(sent) })
+
+			byEventID := make(map[string]kafka.Message)
+			for _, msg := range received() {
+				byEventID[msg.Header(kafka.HeaderEventID)] = msg
+			}
+			partitionOf := make(map[string]int32)
+			lastOffset := make(map[string]int64)
+			for i, want := range sent {
+				got, ok := byEventID[fmt.Sprintf("event-%d", i)]
+				if !ok {
+					t.Fatalf("message %d not received", i)
+				}
+				if !bytes.Equal(got.Key, want.Key) || !bytes.Equal(got.Value, want.Value) {
+					t.Errorf("message %d: got key %q value %q", i, got.Key, got.Value)
+				}
+				if len(got.Headers) != 2 || got.Headers[1].Key != "empty" || len(got.Headers[1].Value) != 0 {
+					t.Errorf("message %d: got headers %v", i, got.Headers)
+				}
+				if got.Topic != "events" {
+					t.Errorf("message %d: got topic %q", i, got.Topic)
+				}
+				if want.Key == nil {
+					continue
+				}
+
+				// Messages of a key share a partition, in the order sent
+				key := string(want.Key)
+				if p, ok := partitionOf[key]; ok && p != got.Partition {
+					t.Errorf("key %s on partitions %d and %d", key, p, got.Partition)
+				}
+				if last, ok := lastOffset[key]; ok && got.Offset <= last {
+					t.Errorf("key %s: offset %d after %d", key, got.Offset, last)
+				}
+				partitionOf[key], lastOffset[key] = got.Partition, got.Offset
+			}
+
+			total := int64(0)
+			for p := int32(0); p < partitions; p++ {
+				total += broker.HighWatermark("events", p)
+				for _, batch := range broker.Batches("events", p) {
+					attributes := binary.BigEndian.Uint16(batch[21:])
+					if kafka.Compression(attributes&0x07) != codec {
+						t.Errorf("partition %d: batch compressed with codec %d", p, attributes&0x07)
+					}
+				}
+			}
+			if total != int64(len(sent)) {
+				t.Errorf("high watermarks add up to %d, want %d", total, len(sent))
+			}
+		})
+	}
+}
+
+func TestFetchSkipsDeletedRecords(t *testing.T) {
+	broker, err := kafkatest.NewBroker()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer broker.Close()
+	broker.CreateTopic("events", 1)
+
+	var mu sync.Mutex
+	var received []string
+	release := make(chan struct{})
+	kafka.NewConsumer([]string{broker.Addr()}, "events", "test-group", func(msg *kafka.Message) error {
+		mu.Lock()
+		received = append(received, string(msg.Value))
+		first := len(received) == 1
+		mu.Unlock()
+		if first {
+			<-release
+		}
+		return nil
+	}, nil).Start()
+	waitFor(t, "the consumer to join", func() bool {
+		_, ok := broker.Committed("test-group", "events", 0)
+		return ok
+	})
+
+	producer := kafka.NewProducer([]string{broker.Addr()}, "events", kafka.CompressionGzip)
+	defer producer.Close()
+	send := func(value string) {
+		t.Helper()
+		if err := producer.Send([]*kafka.Message{{Key: []byte("employee"), Value: []byte(value)}})[0]; err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), received...)
+	}
+
+	send("first")
+	waitFor(t, "the first message", func() bool { return len(got()) == 1 })
+
+	// Retention deletes the next messages while the consumer is busy; it
+	// skips to the earliest one left
+	send("second")
+	send("third")
+	send("fourth")
+	broker.DeleteRecordsBefore("events", 0, 3)
+	close(release)
+
+	waitFor(t, "the message after the deleted ones", func() bool { return len(got()) == 2 })
+	if messages := got(); messages[1] != "fourth" {
+		t.Fatalf("received %v, want [first fourth]", messages)
+	}
+	waitFor(t, "the commit", func() bool {
+		offset, _ := broker.Committed("test-group", "events", 0)
+		return offset == 4
+	})
+}
@@ -0,0 +1,106 @@
+package kafkatest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+var errTruncated = errors.New("truncated request")
+
+// reader decodes the fields of a request. The first short read sets err and
+// later reads return zero values, so handlers check err once.
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.data) {
+		r.err = errTruncated
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) int8() int8 {
+	if b := r.next(1); b != nil {
+		return int8(b[0])
+	}
+	return 0
+}
+
+func (r *reader) int16() int16 {
+	if b := r.next(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (r *reader) int32() int32 {
+	if b := r.next(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (r *reader) int64() int64 {
+	if b := r.next(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+// string reads a nullable string; null reads as ""
+func (r *reader) string() string {
+	n := r.int16()
+	if n <= 0 {
+		return ""
+	}
+	return string(r.next(int(n)))
+}
+
+// bytes reads nullable bytes; null reads as nil
+func (r *reader) bytes() []byte {
+	n := r.int32()
+	if n < 0 {
+		return nil
+	}
+	return r.next(int(n))
+}
+
+// writer encodes the fields of a response
+type writer struct {
+	bytes.Buffer
+}
+
+func (w *writer) int8(v int8) {
+	w.WriteByte(byte(v))
+}
+
+func (w *writer) int16(v int16) {
+	w.Write(binary.BigEndian.AppendUint16(nil, uint16(v)))
+}
+
+func (w *writer) int32(v int32) {
+	w.Write(binary.BigEndian.AppendUint32(nil, uint32(v)))
+}
+
+func (w *writer) int64(v int64) {
+	w.Write(binary.BigEndian.AppendUint64(nil, uint64(v)))
+}
+
+func (w *writer) string(s string) {
+	w.int16(int16(len(s)))
+	w.WriteString(s)
+}
+
+func (w *writer) bytes(b []byte) {
+	w.int32(int32(len(b)))
+	w.Write(b)
+}
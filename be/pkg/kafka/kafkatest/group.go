@@ -0,0 +1,369 @@
+package kafkatest
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// group is the membership of a consumer group. A rebalance starts when a
+// member joins, leaves or lets its session expire; it ends, and the
+// generation moves on, once every member has rejoined or the rebalance
+// timeout has passed, and the leader then hands out the assignments.
+type group struct {
+	generation int32
+	leader     string
+	protocol   string
+	members    map[string]*member
+	nextMember int
+
+	// joining is the rebalance in progress, if any
+	joining *rebalance
+
+	// assignments are those the leader sent for the current generation, and
+	// synced is closed once it has sent them or the generation is over
+	assignments map[string][]byte
+	synced      chan struct{}
+}
+
+type member struct {
+	sessionTimeout time.Duration
+	lastSeen       time.Time
+}
+
+// rebalance collects the members that rejoined. done is closed when it ends,
+// with the generation and leader it made.
+type rebalance struct {
+	deadline   time.Time
+	joined     map[string][]byte // member → protocol metadata
+	done       chan struct{}
+	generation int32
+	leader     string
+}
+
+func (b *Broker) groupLocked(id string) *group {
+	g, ok := b.groups[id]
+	if !ok {
+		g = &group{members: make(map[string]*member), synced: make(chan struct{})}
+		close(g.synced)
+		b.groups[id] = g
+	}
+	return g
+}
+
+// GroupMembers returns the members of a consumer group's current
+// generation in ID order, and none while the group rebalances.
+func (b *Broker) GroupMembers(groupID string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	g, ok := b.groups[groupID]
+	if !ok || g.joining != nil {
+		return nil
+	}
+	var members []string
+	for id := range g.members {
+		members = append(members, id)
+	}
+	sort.Strings(members)
+	return members
+}
+
+// expireLocked drops the members whose session ran out, which starts a
+// rebalance
+func (g *group) expireLocked(now time.Time) {
+	for id, m := range g.members {
+		if g.joining != nil {
+			if _, ok := g.joining.joined[id]; ok {
+				continue
+			}
+		}
+		if now.Sub(m.lastSeen) > m.sessionTimeout {
+			delete(g.members, id)
+			g.rebalanceLocked(now, m.sessionTimeout)
+		}
+	}
+}
+
+// rebalanceLocked starts a rebalance unless one is in progress and returns
+// it. Members still waiting for the previous generation's assignments are
+// released.
+func (g *group) rebalanceLocked(now time.Time, timeout time.Duration) *rebalance {
+	if g.joining == nil {
+		g.joining = &rebalance{
+			deadline: now.Add(timeout),
+			joined:   make(map[string][]byte),
+			done:     make(chan struct{}),
+		}
+		g.releaseSyncLocked()
+	}
+	return g.joining
+}
+
+// completeLocked ends the rebalance in progress once every member rejoined
+func (g *group) completeLocked() {
+	round := g.joining
+	if round == nil {
+		return
+	}
+	for id := range g.members {
+		if _, ok := round.joined[id]; !ok {
+			return
+		}
+	}
+	g.finishLocked()
+}
+
+// finishLocked ends the rebalance in progress with the members that
+// rejoined, and starts the next generation
+func (g *group) finishLocked() {
+	round := g.joining
+	for id := range g.members {
+		if _, ok := round.joined[id]; !ok {
+			delete(g.members, id)
+		}
+	}
+
+	g.generation++
+	if _, ok := round.joined[g.leader]; !ok {
+		ids := make([]string, 0, len(round.joined))
+		for id := range round.joined {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		g.leader = ""
+		if len(ids) > 0 {
+			g.leader = ids[0]
+		}
+	}
+	round.generation, round.leader = g.generation, g.leader
+
+	g.joining = nil
+	g.assignments = nil
+	g.synced = make(chan struct{})
+	close(round.done)
+}
+
+func (g *group) releaseSyncLocked() {
+	select {
+	case <-g.synced:
+	default:
+		close(g.synced)
+	}
+}
+
+// checkMemberLocked returns the error of a request by a member of a
+// generation
+func (g *group) checkMemberLocked(memberID string, generation int32) int16 {
+	if _, ok := g.members[memberID]; !ok {
+		return errUnknownMemberID
+	}
+	if generation != g.generation {
+		return errIllegalGeneration
+	}
+	return errNone
+}
+
+// wait blocks until ch is closed, timeout passes or the broker closes, and
+// reports whether the broker is still open
+func (b *Broker) wait(ch <-chan struct{}, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ch:
+	case <-timer.C:
+	case <-b.done:
+		return false
+	}
+	return true
+}
+
+// handleJoinGroup answers JoinGroup v1. The response waits for the
+// rebalance to end; the leader gets every member with its metadata.
+func (b *Broker) handleJoinGroup(r *reader, w *writer) {
+	groupID := r.string()
+	sessionTimeout := time.Duration(r.int32()) * time.Millisecond
+	rebalanceTimeout := time.Duration(r.int32()) * time.Millisecond
+	memberID := r.string()
+	r.string() // protocol_type
+	var protocol string
+	var metadata []byte
+	protocolCount := r.int32()
+	for i := int32(0); i < protocolCount; i++ {
+		name, meta := r.string(), r.bytes()
+		if i == 0 {
+			protocol, metadata = name, meta
+		}
+	}
+	if r.err != nil {
+		return
+	}
+
+	now := time.Now()
+	b.mu.Lock()
+	g := b.groupLocked(groupID)
+	g.expireLocked(now)
+	if memberID == "" {
+		g.nextMember++
+		memberID = fmt.Sprintf("member-%d", g.nextMember)
+	} else if _, ok := g.members[memberID]; !ok {
+		b.mu.Unlock()
+		w.int16(errUnknownMemberID)
+		w.int32(-1) // generation_id
+		w.string("")
+		w.string("")
+		w.string(memberID)
+		w.int32(0) // members
+		return
+	}
+
+	g.members[memberID] = &member{sessionTimeout: sessionTimeout, lastSeen: now}
+	g.protocol = protocol
+	round := g.rebalanceLocked(now, rebalanceTimeout)
+	round.joined[memberID] = metadata
+	g.completeLocked()
+	b.mu.Unlock()
+
+	if !b.wait(round.done, time.Until(round.deadline)) {
+		r.err = errBrokerClosed
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if g.joining == round {
+		// The others did not rejoin in time
+		g.finishLocked()
+	}
+	if m, ok := g.members[memberID]; ok {
+		m.lastSeen = time.Now()
+	}
+
+	w.int16(errNone)
+	w.int32(round.generation)
+	w.string(g.protocol)
+	w.string(round.leader)
+	w.string(memberID)
+	if memberID != round.leader {
+		w.int32(0)
+		return
+	}
+	ids := make([]string, 0, len(round.joined))
+	for id := range round.joined {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	w.int32(int32(len(ids)))
+	for _, id := range ids {
+		w.string(id)
+		w.bytes(round.joined[id])
+	}
+}
+
+// handleSyncGroup answers SyncGroup v0. The leader's request stores the
+// assignments of the generation; every member waits for them and gets its
+// own.
+func (b *Broker) handleSyncGroup(r *reader, w *writer) {
+	groupID := r.string()
+	generation := r.int32()
+	memberID := r.string()
+	assignments := make(map[string][]byte)
+	count := r.int32()
+	for i := int32(0); i < count; i++ {
+		assignments[r.string()] = r.bytes()
+	}
+	if r.err != nil {
+		return
+	}
+
+	b.mu.Lock()
+	g := b.groupLocked(groupID)
+	errCode := g.checkMemberLocked(memberID, generation)
+	if errCode == errNone && g.joining != nil {
+		errCode = errRebalanceInProgress
+	}
+	if errCode != errNone {
+		b.mu.Unlock()
+		w.int16(errCode)
+		w.bytes(nil)
+		return
+	}
+	timeout := g.members[memberID].sessionTimeout
+	if memberID == g.leader && g.assignments == nil {
+		g.assignments = assignments
+		close(g.synced)
+	}
+	synced := g.synced
+	b.mu.Unlock()
+
+	if !b.wait(synced, timeout) {
+		r.err = errBrokerClosed
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if g.generation != generation || g.assignments == nil {
+		w.int16(errRebalanceInProgress)
+		w.bytes(nil)
+		return
+	}
+	if m, ok := g.members[memberID]; ok {
+		m.lastSeen = time.Now()
+	}
+	w.int16(errNone)
+	w.bytes(g.assignments[memberID])
+}
+
+// handleHeartbeat answers Heartbeat v0, with REBALANCE_IN_PROGRESS while the
+// group waits for its members to rejoin.
+func (b *Broker) handleHeartbeat(r *reader, w *writer) {
+	groupID := r.string()
+	generation := r.int32()
+	memberID := r.string()
+	if r.err != nil {
+		return
+	}
+
+	now := time.Now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	g := b.groupLocked(groupID)
+	g.expireLocked(now)
+	errCode := g.checkMemberLocked(memberID, generation)
+	if errCode == errNone {
+		g.members[memberID].lastSeen = now
+		if g.joining != nil {
+			errCode = errRebalanceInProgress
+		}
+	}
+	w.int16(errCode)
+}
+
+// handleLeaveGroup answers LeaveGroup v0; the remaining members rebalance.
+func (b *Broker) handleLeaveGroup(r *reader, w *writer) {
+	groupID := r.string()
+	memberID := r.string()
+	if r.err != nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	g := b.groupLocked(groupID)
+	m, ok := g.members[memberID]
+	if !ok {
+		w.int16(errUnknownMemberID)
+		return
+	}
+	delete(g.members, memberID)
+	if g.joining != nil {
+		delete(g.joining.joined, memberID)
+	}
+	if len(g.members) > 0 {
+		g.rebalanceLocked(time.Now(), m.sessionTimeout)
+		g.completeLocked()
+	} else if g.joining != nil {
+		g.finishLocked()
+	}
+	w.int16(errNone)
+}
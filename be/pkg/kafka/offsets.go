@@ -0,0 +1,305 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// Kafka API keys used by the producer and consumer
+const (
+	apiProduce         int16 = 0
+	apiFetch           int16 = 1
+	apiListOffsets     int16 = 2
+	apiMetadata        int16 = 3
+	apiOffsetCommit    int16 = 8
+	apiOffsetFetch     int16 = 9
+	apiFindCoordinator int16 = 10
+	apiJoinGroup       int16 = 11
+	apiHeartbeat       int16 = 12
+	apiLeaveGroup      int16 = 13
+	apiSyncGroup       int16 = 14
+)
+
+// Kafka error codes the consumer handles itself. Any other error ends the
+// consumer run, so the next one starts from fresh metadata; the group errors
+// make it rejoin its group. The producer refreshes its metadata on the
+// errors of a moved or missing partition.
+const (
+	errNone                    int16 = 0
+	errOffsetOutOfRange        int16 = 1
+	errUnknownTopicOrPartition int16 = 3
+	errLeaderNotAvailable      int16 = 5
+	errNotLeaderForPartition   int16 = 6
+	errIllegalGeneration       int16 = 22
+	errUnknownMemberID         int16 = 25
+	errRebalanceInProgress     int16 = 27
+)
+
+// Special timestamps of a ListOffsets request
+const (
+	offsetLatest   int64 = -1
+	offsetEarliest int64 = -2
+)
+
+// topicMetadata maps each partition of a topic to the address of its leader
+type topicMetadata map[int32]string
+
+// fetchMetadata asks a broker for the partitions of topic and their leaders
+// (Metadata v1). Brokers that auto-create topics create it on the first ask.
+func fetchMetadata(conn net.Conn, topic string) (topicMetadata, error) {
+	var body bytes.Buffer
+	writeInt32(&body, 1) // topic array length
+	writeString(&body, topic)
+
+	r, err := roundTrip(conn, apiMetadata, 1, body.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	brokers := make(map[int32]string)
+	var brokerCount int32
+	binary.Read(r, binary.BigEndian, &brokerCount)
+	for i := int32(0); i < brokerCount; i++ {
+		var nodeID, port int32
+		binary.Read(r, binary.BigEndian, &nodeID)
+		host := readStringBuf(r)
+		binary.Read(r, binary.BigEndian, &port)
+		readStringBuf(r) // rack
+		brokers[nodeID] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	r.Seek(4, io.SeekCurrent) // controller_id
+
+	leaders := make(topicMetadata)
+	var topicCount int32
+	binary.Read(r, binary.BigEndian, &topicCount)
+	for i := int32(0); i < topicCount; i++ {
+		var topicErr int16
+		binary.Read(r, binary.BigEndian, &topicErr)
+		name := readStringBuf(r)
+		r.ReadByte() // is_internal
+		if name == topic && topicErr != errNone {
+			return nil, fmt.Errorf("metadata for topic %s: kafka error code %d", topic, topicErr)
+		}
+
+		var partCount int32
+		binary.Read(r, binary.BigEndian, &partCount)
+		for j := int32(0); j < partCount; j++ {
+			var partErr int16
+			var partition, leader int32
+			binary.Read(r, binary.BigEndian, &partErr)
+			binary.Read(r, binary.BigEndian, &partition)
+			binary.Read(r, binary.BigEndian, &leader)
+			skipInt32Array(r) // replicas
+			skipInt32Array(r) // isr
+
+			if name != topic {
+				continue
+			}
+			addr, ok := brokers[leader]
+			if partErr != errNone || !ok {
+				return nil, fmt.Errorf("partition %d of %s has no leader (kafka error code %d)", partition, topic, partErr)
+			}
+			leaders[partition] = addr
+		}
+	}
+
+	if len(leaders) == 0 {
+		return nil, fmt.Errorf("topic %s has no partitions", topic)
+	}
+	return leaders, nil
+}
+
+// findCoordinator returns the address of the broker that stores the offsets
+// of groupID (FindCoordinator v0).
+func findCoordinator(conn net.Conn, groupID string) (string, error) {
+	var body bytes.Buffer
+	writeString(&body, groupID)
+
+	r, err := roundTrip(conn, apiFindCoordinator, 0, body.Bytes())
+	if err != nil {
+		return "", err
+	}
+
+	var errCode int16
+	var nodeID, port int32
+	binary.Read(r, binary.BigEndian, &errCode)
+	binary.Read(r, binary.BigEndian, &nodeID)
+	host := readStringBuf(r)
+	binary.Read(r, binary.BigEndian, &port)
+	if errCode != errNone {
+		return "", fmt.Errorf("find coordinator for %s: kafka error code %d", groupID, errCode)
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(port))), nil
+}
+
+// fetchCommittedOffsets returns the offsets groupID committed for the
+// partitions of topic (OffsetFetch v1, Kafka-stored offsets). Partitions
+// without a committed offset are left out.
+func fetchCommittedOffsets(conn net.Conn, groupID, topic string, partitions []int32) (map[int32]int64, error) {
+	var body bytes.Buffer
+	writeString(&body, groupID)
+	writeInt32(&body, 1) // topic array length
+	writeString(&body, topic)
+	writeInt32(&body, int32(len(partitions)))
+	for _, partition := range partitions {
+		writeInt32(&body, partition)
+	}
+
+	r, err := roundTrip(conn, apiOffsetFetch, 1, body.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := make(map[int32]int64)
+	var topicCount int32
+	binary.Read(r, binary.BigEndian, &topicCount)
+	for i := int32(0); i < topicCount; i++ {
+		readStringBuf(r) // topic name
+		var partCount int32
+		binary.Read(r, binary.BigEndian, &partCount)
+		for j := int32(0); j < partCount; j++ {
+			var partition int32
+			var offset int64
+			var errCode int16
+			binary.Read(r, binary.BigEndian, &partition)
+			binary.Read(r, binary.BigEndian, &offset)
+			readStringBuf(r) // metadata
+			binary.Read(r, binary.BigEndian, &errCode)
+			if errCode != errNone {
+				return nil, fmt.Errorf("fetch committed offset of partition %d: kafka error code %d", partition, errCode)
+			}
+			if offset >= 0 {
+				offsets[partition] = offset
+			}
+		}
+	}
+	return offsets, nil
+}
+
+// commitOffsets stores the next offset to read of each partition for groupID
+// (OffsetCommit v2), as the member of the given generation. The coordinator
+// refuses the commit of a member that lost its partitions in a rebalance,
+// which commitOffsets reports as errRebalance.
+func commitOffsets(conn net.Conn, groupID string, generation int32, memberID, topic string, offsets map[int32]int64) error {
+	var body bytes.Buffer
+	writeString(&body, groupID)
+	writeInt32(&body, generation)
+	writeString(&body, memberID)
+	writeInt64(&body, -1) // retention_time = broker default
+	writeInt32(&body, 1)  // topic array length
+	writeString(&body, topic)
+	writeInt32(&body, int32(len(offsets)))
+	for partition, offset := range offsets {
+		writeInt32(&body, partition)
+		writeInt64(&body, offset)
+		writeString(&body, "") // metadata
+	}
+
+	r, err := roundTrip(conn, apiOffsetCommit, 2, body.Bytes())
+	if err != nil {
+		return err
+	}
+
+	var topicCount int32
+	binary.Read(r, binary.BigEndian, &topicCount)
+	for i := int32(0); i < topicCount; i++ {
+		readStringBuf(r) // topic name
+		var partCount int32
+		binary.Read(r, binary.BigEndian, &partCount)
+		for j := int32(0); j < partCount; j++ {
+			var partition int32
+			var errCode int16
+			binary.Read(r, binary.BigEndian, &partition)
+			binary.Read(r, binary.BigEndian, &errCode)
+			if errCode != errNone {
+				return fmt.Errorf("commit offset of partition %d: %w", partition, rebalanceError(errCode))
+			}
+		}
+	}
+	return nil
+}
+
+// listOffset returns the earliest or latest offset of a partition
+// (ListOffsets v1). It must be sent to the partition's leader.
+func listOffset(conn net.Conn, topic string, partition int32, timestamp int64) (int64, error) {
+	var body bytes.Buffer
+	writeInt32(&body, -1) // replica_id = -1 (consumer)
+	writeInt32(&body, 1)  // topic array length
+	writeString(&body, topic)
+	writeInt32(&body, 1) // partition array length
+	writeInt32(&body, partition)
+	writeInt64(&body, timestamp)
+
+	r, err := roundTrip(conn, apiListOffsets, 1, body.Bytes())
+	if err != nil {
+		return 0, err
+	}
+
+	var topicCount int32
+	binary.Read(r, binary.BigEndian, &topicCount)
+	for i := int32(0); i < topicCount; i++ {
+		readStringBuf(r) // topic name
+		var partCount int32
+		binary.Read(r, binary.BigEndian, &partCount)
+		for j := int32(0); j < partCount; j++ {
+			var p int32
+			var errCode int16
+			var offset int64
+			binary.Read(r, binary.BigEndian, &p)
+			binary.Read(r, binary.BigEndian, &errCode)
+			r.Seek(8, io.SeekCurrent) // timestamp
+			binary.Read(r, binary.BigEndian, &offset)
+			if p != partition {
+				continue
+			}
+			if errCode != errNone {
+				return 0, fmt.Errorf("list offsets of partition %d: kafka error code %d", partition, errCode)
+			}
+			return offset, nil
+		}
+	}
+	return 0, fmt.Errorf("list offsets of partition %d: partition missing from response", partition)
+}
+
+func skipInt32Array(r *bytes.Reader) {
+	var n int32
+	binary.Read(r, binary.BigEndian, &n)
+	if n > 0 {
+		r.Seek(int64(n)*4, io.SeekCurrent)
+	}
+}
+
+// brokerConns holds one connection per broker address, for a consumer run
+// or the lifetime of a producer
+type brokerConns map[string]net.Conn
+
+func (b brokerConns) get(addr string) (net.Conn, error) {
+	if conn, ok := b[addr]; ok {
+		return conn, nil
+	}
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s: %w", addr, err)
+	}
+	b[addr] = conn
+	return conn, nil
+}
+
+// drop closes the connection to addr, after an error left it unusable
+func (b brokerConns) drop(addr string) {
+	if conn, ok := b[addr]; ok {
+		conn.Close()
+		delete(b, addr)
+	}
+}
+
+func (b brokerConns) close() {
+	for _, conn := range b {
+		conn.Close()
+	}
+}
@@ -0,0 +1,221 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+	"hris-backend/pkg/trace"
+
+	"github.com/google/uuid"
+)
+
+const (
+	outboxPollInterval = time.Second
+	outboxBatchSize    = 100
+
+	// outboxLease is how long a claimed event is reserved for the relay
+	// publishing it
+	outboxLease = time.Minute
+
+	// Failed publishes are retried with exponential backoff from
+	// outboxBaseBackoff up to outboxMaxBackoff. After outboxMaxAttempts,
+	// about three hours, the event is marked failed.
+	outboxBaseBackoff = 5 * time.Second
+	outboxMaxBackoff  = 10 * time.Minute
+	outboxMaxAttempts = 25
+
+	// Published events are purged after outboxRetention
+	outboxRetention     = 7 * 24 * time.Hour
+	outboxPurgeInterval = time.Hour
+)
+
+// NewOutboxEvent builds the outbox row of a notification event, for the
+// repository to write in the transaction of the change the event reports.
+// key is the subject whose events must stay in order, such as the employee
+// or user the event is about. The event continues the trace of ctx, or
+// starts one outside a request.
+func NewOutboxEvent(ctx context.Context, eventType EventType, key, companyID string, payload any) (model.OutboxEvent, error) {
+	return newOutboxEvent(ctx, uuid.New().String(), eventType, key, companyID, payload)
+}
+
+// NewOccurrenceOutboxEvent builds the outbox row of one occurrence of an
+// event type, identified by OccurrenceEventID. Write it with
+// OutboxRepository.CreateIfAbsent: finding the occurrence again adds no row
+// while the first is in the outbox, and consumers dedupe it after that.
+func NewOccurrenceOutboxEvent(ctx context.Context, eventType EventType, occurrence, key, companyID string, payload any) (model.OutboxEvent, error) {
+	return newOutboxEvent(ctx, OccurrenceEventID(eventType, occurrence), eventType, key, companyID, payload)
+}
+
+func newOutboxEvent(ctx context.Context, eventID string, eventType EventType, key, companyID string, payload any) (model.OutboxEvent, error) {
+	event, err := newEvent(eventID, eventType, companyID, payload)
+	if err != nil {
+		return model.OutboxEvent{}, err
+	}
+	message, err := json.Marshal(event)
+	if err != nil {
+		return model.OutboxEvent{}, err
+	}
+
+	tc, ok := trace.FromContext(ctx)
+	if ok {
+		tc = tc.Child()
+	} else {
+		tc = trace.New()
+	}
+	headers := map[string]string{
+		HeaderEventID:       event.EventID,
+		HeaderEventType:     string(eventType),
+		HeaderSchemaVersion: strconv.Itoa(event.SchemaVersion),
+		HeaderTraceParent:   tc.String(),
+	}
+	if companyID != "" {
+		headers[HeaderCompanyID] = companyID
+	}
+	headersJSON, err := json.Marshal(headers)
+	if err != nil {
+		return model.OutboxEvent{}, err
+	}
+
+	row := model.OutboxEvent{
+		ID:        event.EventID,
+		Topic:     TopicNotifications,
+		EventType: string(eventType),
+		Key:       key,
+		Headers:   string(headersJSON),
+		Message:   string(message),
+		Status:    model.OutboxPending,
+	}
+	if companyID != "" {
+		row.CompanyID = &companyID
+	}
+	return row, nil
+}
+
+// OutboxRelay publishes the events of the outbox table to Kafka. An event is
+// marked sent only once a broker acknowledged it, so it may be published
+// more than once but is never lost; consumers dedupe by event ID.
+type OutboxRelay struct {
+	outboxRepo repository.OutboxRepository
+	producer   *Producer
+}
+
+// NewOutboxRelay creates a relay publishing through producer.
+func NewOutboxRelay(outboxRepo repository.OutboxRepository, producer *Producer) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepo: outboxRepo,
+		producer:   producer,
+	}
+}
+
+// Start begins relaying events in a background loop.
+func (r *OutboxRelay) Start() {
+	go func() {
+		lastPurge := time.Time{}
+		for {
+			// The outbox is not tenant data; the relay reads all of it
+			ctx := context.Background()
+
+			if time.Since(lastPurge) >= outboxPurgeInterval {
+				if err := r.outboxRepo.DeleteSentBefore(ctx, time.Now().Add(-outboxRetention)); err != nil {
+					log.Printf("[kafka] outbox: purge sent events: %v", err)
+				}
+				lastPurge = time.Now()
+			}
+
+			if r.relayBatch(ctx) < outboxBatchSize {
+				time.Sleep(outboxPollInterval)
+			}
+		}
+	}()
+}
+
+// relayBatch publishes the events that are due and returns how many it
+// claimed
+func (r *OutboxRelay) relayBatch(ctx context.Context) int {
+	events, err := r.outboxRepo.ClaimDue(ctx, outboxBatchSize, outboxLease)
+	if err != nil {
+		log.Printf("[kafka] outbox: claim events: %v", err)
+		return 0
+	}
+
+	msgs := make([]*Message, len(events))
+	for i := range events {
+		msgs[i] = outboxMessage(&events[i])
+	}
+
+	// The whole batch goes out in one request per leader; each event is
+	// marked by the outcome of its own partition
+	errs := r.producer.Send(msgs)
+	for i := range events {
+		if errs[i] != nil {
+			r.failed(ctx, &events[i], errs[i])
+			continue
+		}
+		if err := r.outboxRepo.MarkSent(ctx, events[i].ID); err != nil {
+			log.Printf("[kafka] outbox: mark event %s sent: %v", events[i].ID, err)
+		}
+	}
+	return len(events)
+}
+
+// outboxMessage builds the Kafka message of an outbox row. Rows written
+// before keys and headers existed go out without them.
+func outboxMessage(event *model.OutboxEvent) *Message {
+	msg := &Message{
+		Topic:     event.Topic,
+		Value:     []byte(event.Message),
+		Timestamp: event.CreatedAt,
+	}
+	if event.Key != "" {
+		msg.Key = []byte(event.Key)
+	}
+
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(event.Headers), &headers); err == nil {
+		keys := make([]string, 0, len(headers))
+		for k := range headers {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			msg.Headers = append(msg.Headers, Header{Key: k, Value: []byte(headers[k])})
+		}
+	}
+	return msg
+}
+
+// failed schedules the next attempt of an event, or gives up on it
+func (r *OutboxRelay) failed(ctx context.Context, event *model.OutboxEvent, publishErr error) {
+	attempts := event.Attempts + 1
+	if attempts >= outboxMaxAttempts {
+		log.Printf("[kafka] outbox: giving up on event %s (%s) after %d attempts: %v", event.ID, event.EventType, attempts, publishErr)
+		if err := r.outboxRepo.MarkFailed(ctx, event.ID, publishErr.Error()); err != nil {
+			log.Printf("[kafka] outbox: mark event %s failed: %v", event.ID, err)
+		}
+		return
+	}
+
+	next := time.Now().Add(outboxBackoff(attempts))
+	if err := r.outboxRepo.MarkRetry(ctx, event.ID, publishErr.Error(), next); err != nil {
+		log.Printf("[kafka] outbox: reschedule event %s: %v", event.ID, err)
+	}
+}
+
+// outboxBackoff is the delay before the attempt following the given number of
+// failed attempts
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 1; i < attempts && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > outboxMaxBackoff {
+		backoff = outboxMaxBackoff
+	}
+	return backoff
+}
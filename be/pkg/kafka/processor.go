@@ -1,86 +1,195 @@
 package kafka
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"hris-backend/internal/model"
 	"hris-backend/internal/repository"
 )
 
-// EventProcessor consumes Kafka events and persists notifications to the database.
+// EventHandler processes the events of one type.
+type EventHandler func(ctx context.Context, event *NotificationEvent) error
+
+// Channel delivers notifications outside the application, such as by email.
+// Deliver is called with every notification the processor stores; a
+// channel skips the ones it has nothing to send for.
+type Channel interface {
+	Deliver(ctx context.Context, event *NotificationEvent, n *model.Notification) error
+}
+
+// EventProcessor consumes Kafka events and persists notifications to the
+// database, then hands them to its channels. Each event type has its own
+// handler, registered with Register. Users choose per event type whether
+// they are notified in the application, also by email, in their daily
+// digest or not at all.
 type EventProcessor struct {
-	notifRepo repository.NotificationRepository
-	userRepo  repository.UserRepository
+	notifRepo      repository.NotificationRepository
+	userRepo       repository.UserRepository
+	prefRepo       repository.NotificationPreferenceRepository
+	digestRepo     repository.NotificationDigestRepository
+	delegationRepo repository.LeaveDelegationRepository
+	handlers       map[EventType]EventHandler
+	channels       []Channel
 }
 
 // NewEventProcessor creates a new processor that handles notification events.
 func NewEventProcessor(
 	notifRepo repository.NotificationRepository,
 	userRepo repository.UserRepository,
+	prefRepo repository.NotificationPreferenceRepository,
+	digestRepo repository.NotificationDigestRepository,
+	delegationRepo repository.LeaveDelegationRepository,
 ) *EventProcessor {
-	return &EventProcessor{
-		notifRepo: notifRepo,
-		userRepo:  userRepo,
+	p := &EventProcessor{
+		notifRepo:      notifRepo,
+		userRepo:       userRepo,
+		prefRepo:       prefRepo,
+		digestRepo:     digestRepo,
+		delegationRepo: delegationRepo,
+		handlers:       make(map[EventType]EventHandler),
+	}
+
+	p.Register(EventLeaveSubmitted, p.handleLeaveSubmitted)
+	p.Register(EventLeaveStatusChanged, p.handleLeaveStatusChanged)
+	p.Register(EventPayrollProcessed, p.handlePayrollProcessed)
+	p.Register(EventPayrollPaid, p.handlePayrollPaid)
+	p.Register(EventPasswordResetRequested, p.handlePasswordResetRequested)
+	p.Register(EventAccountLocked, p.handleAccountLocked)
+	p.Register(EventContractEnding, p.handleContractEnding)
+	p.Register(EventClockInMissing, p.handleClockInMissing)
+	p.Register(EventModuleEnabled, p.handleModuleToggled)
+	p.Register(EventModuleDisabled, p.handleModuleToggled)
+	p.Register(EventNotificationDigest, p.handleNotificationDigest)
+	p.Register(EventAnnouncementPublished, p.handleAnnouncementPublished)
+	return p
+}
+
+// Register sets the handler of an event type. It panics if the type has a
+// handler already, so two features cannot silently claim the same events.
+func (p *EventProcessor) Register(eventType EventType, handler EventHandler) {
+	if _, ok := p.handlers[eventType]; ok {
+		panic(fmt.Sprintf("kafka: handler for event type %q registered twice", eventType))
 	}
+	p.handlers[eventType] = handler
 }
 
-// Handle processes a raw Kafka message value.
-func (p *EventProcessor) Handle(value []byte) error {
-	event, err := ParseEvent(value)
+// AddChannel delivers the notifications of all events through ch as well.
+func (p *EventProcessor) AddChannel(ch Channel) {
+	p.channels = append(p.channels, ch)
+}
+
+// Handle processes a Kafka message.
+func (p *EventProcessor) Handle(msg *Message) error {
+	event, err := ParseEvent(msg.Value)
 	if err != nil {
 		return fmt.Errorf("parse event: %w", err)
 	}
 
-	switch event.EventType {
-	case EventLeaveSubmitted:
-		return p.handleLeaveSubmitted(event.Payload)
-	case EventLeaveStatusChanged:
-		return p.handleLeaveStatusChanged(event.Payload)
-	case EventPayrollProcessed:
-		return p.handlePayrollProcessed(event.Payload)
-	default:
+	handler, ok := p.handlers[event.EventType]
+	if !ok {
 		log.Printf("[kafka] processor: unknown event type %q — skipping", event.EventType)
+		return nil
 	}
-	return nil
+
+	// Events are processed outside any request, so they are not tenant scoped
+	return handler(context.Background(), event)
 }
 
-// handleLeaveSubmitted notifies all admin and HR users about a new leave request.
-func (p *EventProcessor) handleLeaveSubmitted(payload json.RawMessage) error {
+// handleLeaveSubmitted notifies the approvers of the step a leave request
+// awaits: the first step of a new request, or the next one once a step is
+// approved.
+func (p *EventProcessor) handleLeaveSubmitted(ctx context.Context, event *NotificationEvent) error {
 	var data LeaveSubmittedPayload
-	if err := json.Unmarshal(payload, &data); err != nil {
+	if err := json.Unmarshal(event.Payload, &data); err != nil {
 		return fmt.Errorf("unmarshal LeaveSubmittedPayload: %w", err)
 	}
 
-	admins, err := p.userRepo.FindByRoles([]string{"admin", "hr"})
+	approvers, err := p.leaveApprovers(ctx, &data)
 	if err != nil {
-		return fmt.Errorf("find admin/hr users: %w", err)
+		return err
 	}
 
 	title := "New Leave Request"
-	message := fmt.Sprintf("%s submitted a %d-day %s request", data.EmployeeName, data.TotalDays, data.LeaveType)
+	message := fmt.Sprintf("%s submitted a %g-day %s request", data.EmployeeName, data.TotalDays, data.LeaveType)
 
-	for _, u := range admins {
+	var errs []error
+	for _, u := range approvers {
 		n := &model.Notification{
 			UserID:  u.ID,
 			Title:   title,
 			Message: message,
 			Type:    model.NotificationTypeWarning,
-			RefID:   data.LeaveID,
+			RefID:   refID(data.LeaveID),
 			RefType: "leave",
 		}
-		if err := p.notifRepo.Create(n); err != nil {
-			log.Printf("[kafka] processor: failed to create notification for user %s: %v", u.ID, err)
+		if err := p.notify(ctx, event, n); err != nil {
+			errs = append(errs, fmt.Errorf("notify user %s: %w", u.ID, err))
 		}
 	}
-	return nil
+	return errors.Join(errs...)
+}
+
+// leaveApprovers returns the users who may decide the awaited step of a
+// leave request: its approver and the approver's current delegates, the
+// holders of its approver role in the company, or the admins of the company
+// when the step has nobody active to decide it. Events without an approver go
+// to the admin and HR users of the company.
+func (p *EventProcessor) leaveApprovers(ctx context.Context, data *LeaveSubmittedPayload) ([]model.User, error) {
+	if data.ApproverUserID != "" {
+		approver, err := p.userRepo.FindByID(ctx, data.ApproverUserID)
+		if err != nil {
+			return nil, fmt.Errorf("find approver %s: %w", data.ApproverUserID, err)
+		}
+		delegations, err := p.delegationRepo.FindActiveForDelegator(ctx, data.ApproverUserID, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("find delegates of %s: %w", data.ApproverUserID, err)
+		}
+
+		var approvers []model.User
+		if approver.IsActive {
+			approvers = append(approvers, *approver)
+		}
+		seen := map[string]bool{approver.ID: true}
+		for _, d := range delegations {
+			if d.Delegate.IsActive && !seen[d.DelegateID] {
+				seen[d.DelegateID] = true
+				approvers = append(approvers, d.Delegate)
+			}
+		}
+		if len(approvers) > 0 {
+			return approvers, nil
+		}
+		return p.userRepo.FindByRolesInCompany(ctx, data.CompanyID, []string{"admin"})
+	}
+
+	if data.ApproverRole != "" {
+		holders, err := p.userRepo.FindByRolesInCompany(ctx, data.CompanyID, []string{data.ApproverRole})
+		if err != nil {
+			return nil, fmt.Errorf("find %s users: %w", data.ApproverRole, err)
+		}
+		if len(holders) > 0 {
+			return holders, nil
+		}
+		return p.userRepo.FindByRolesInCompany(ctx, data.CompanyID, []string{"admin"})
+	}
+
+	users, err := p.userRepo.FindByRolesInCompany(ctx, data.CompanyID, []string{"admin", "hr"})
+	if err != nil {
+		return nil, fmt.Errorf("find admin/hr users: %w", err)
+	}
+	return users, nil
 }
 
 // handleLeaveStatusChanged notifies the employee about their leave decision.
-func (p *EventProcessor) handleLeaveStatusChanged(payload json.RawMessage) error {
+func (p *EventProcessor) handleLeaveStatusChanged(ctx context.Context, event *NotificationEvent) error {
 	var data LeaveStatusChangedPayload
-	if err := json.Unmarshal(payload, &data); err != nil {
+	if err := json.Unmarshal(event.Payload, &data); err != nil {
 		return fmt.Errorf("unmarshal LeaveStatusChangedPayload: %w", err)
 	}
 
@@ -99,6 +208,14 @@ func (p *EventProcessor) handleLeaveStatusChanged(payload json.RawMessage) error
 			message += " Reason: " + data.RejectionReason
 		}
 		notifType = model.NotificationTypeError
+	case "cancelled":
+		title = "Leave Request Cancelled"
+		message = "Your leave request has been cancelled."
+		notifType = model.NotificationTypeWarning
+	case "in_review":
+		title = "Leave Request In Review"
+		message = "Your leave request has been approved by one approver and moved to the next approval step."
+		notifType = model.NotificationTypeInfo
 	default:
 		title = "Leave Request Updated"
 		message = fmt.Sprintf("Your leave request status has changed to: %s", data.NewStatus)
@@ -110,16 +227,16 @@ func (p *EventProcessor) handleLeaveStatusChanged(payload json.RawMessage) error
 		Title:   title,
 		Message: message,
 		Type:    notifType,
-		RefID:   data.LeaveID,
+		RefID:   refID(data.LeaveID),
 		RefType: "leave",
 	}
-	return p.notifRepo.Create(n)
+	return p.notify(ctx, event, n)
 }
 
 // handlePayrollProcessed notifies the employee about their payroll.
-func (p *EventProcessor) handlePayrollProcessed(payload json.RawMessage) error {
+func (p *EventProcessor) handlePayrollProcessed(ctx context.Context, event *NotificationEvent) error {
 	var data PayrollProcessedPayload
-	if err := json.Unmarshal(payload, &data); err != nil {
+	if err := json.Unmarshal(event.Payload, &data); err != nil {
 		return fmt.Errorf("unmarshal PayrollProcessedPayload: %w", err)
 	}
 
@@ -131,8 +248,327 @@ func (p *EventProcessor) handlePayrollProcessed(payload json.RawMessage) error {
 		Title:   title,
 		Message: message,
 		Type:    model.NotificationTypeSuccess,
-		RefID:   data.PayrollID,
+		RefID:   refID(data.PayrollID),
+		RefType: "payroll",
+	}
+	return p.notify(ctx, event, n)
+}
+
+// handlePayrollPaid tells the employee their salary was paid.
+func (p *EventProcessor) handlePayrollPaid(ctx context.Context, event *NotificationEvent) error {
+	var data PayrollProcessedPayload
+	if err := json.Unmarshal(event.Payload, &data); err != nil {
+		return fmt.Errorf("unmarshal PayrollProcessedPayload: %w", err)
+	}
+
+	n := &model.Notification{
+		UserID:  data.EmployeeUserID,
+		Title:   "Salary Paid",
+		Message: fmt.Sprintf("Your salary for %s has been paid. Net salary: %.0f", data.Period, data.NetSalary),
+		Type:    model.NotificationTypeSuccess,
+		RefID:   refID(data.PayrollID),
 		RefType: "payroll",
 	}
-	return p.notifRepo.Create(n)
+	return p.notify(ctx, event, n)
+}
+
+// handlePasswordResetRequested tells the user a password reset was requested
+// for their account. The token itself was emailed to them.
+func (p *EventProcessor) handlePasswordResetRequested(ctx context.Context, event *NotificationEvent) error {
+	var data PasswordResetRequestedPayload
+	if err := json.Unmarshal(event.Payload, &data); err != nil {
+		return fmt.Errorf("unmarshal PasswordResetRequestedPayload: %w", err)
+	}
+
+	n := &model.Notification{
+		UserID:  data.UserID,
+		Title:   "Password Reset Requested",
+		Message: fmt.Sprintf("A password reset token was emailed to you; it expires at %s. If you did not ask for a reset, change your password.", data.ExpiresAt),
+		Type:    model.NotificationTypeWarning,
+		RefType: "password_reset",
+	}
+	return p.notify(ctx, event, n)
+}
+
+// handleAccountLocked alerts the admins of the locked user's company, or the
+// superadmins when the user belongs to no company.
+func (p *EventProcessor) handleAccountLocked(ctx context.Context, event *NotificationEvent) error {
+	var data AccountLockedPayload
+	if err := json.Unmarshal(event.Payload, &data); err != nil {
+		return fmt.Errorf("unmarshal AccountLockedPayload: %w", err)
+	}
+
+	var admins []model.User
+	var err error
+	if data.CompanyID != "" {
+		admins, err = p.userRepo.FindByRolesInCompany(ctx, data.CompanyID, []string{"admin"})
+	} else {
+		admins, err = p.userRepo.FindByRoles(ctx, []string{"superadmin"})
+	}
+	if err != nil {
+		return fmt.Errorf("find admin users: %w", err)
+	}
+
+	title := "Account Locked"
+	message := fmt.Sprintf("%s (%s) was locked until %s after too many failed logins, the last from %s",
+		data.Name, data.Email, data.LockedUntil, data.IPAddress)
+
+	var errs []error
+	for _, u := range admins {
+		n := &model.Notification{
+			UserID:  u.ID,
+			Title:   title,
+			Message: message,
+			Type:    model.NotificationTypeError,
+			RefID:   refID(data.UserID),
+			RefType: "user",
+		}
+		if err := p.notify(ctx, event, n); err != nil {
+			errs = append(errs, fmt.Errorf("notify user %s: %w", u.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// handleContractEnding reminds the employee and the admin and HR users of
+// their company that the employee's contract is about to end.
+func (p *EventProcessor) handleContractEnding(ctx context.Context, event *NotificationEvent) error {
+	var data ContractEndingPayload
+	if err := json.Unmarshal(event.Payload, &data); err != nil {
+		return fmt.Errorf("unmarshal ContractEndingPayload: %w", err)
+	}
+
+	admins, err := p.userRepo.FindByRolesInCompany(ctx, data.CompanyID, []string{"admin", "hr"})
+	if err != nil {
+		return fmt.Errorf("find admin/hr users: %w", err)
+	}
+
+	when := fmt.Sprintf("in %d days", data.DaysLeft)
+	switch data.DaysLeft {
+	case 0:
+		when = "today"
+	case 1:
+		when = "tomorrow"
+	}
+
+	n := &model.Notification{
+		UserID:  data.EmployeeUserID,
+		Title:   "Contract Ending Soon",
+		Message: fmt.Sprintf("Your employment contract ends %s, on %s. Please contact HR about its renewal.", when, data.ContractEndDate),
+		Type:    model.NotificationTypeWarning,
+		RefID:   refID(data.EmployeeID),
+		RefType: "employee",
+	}
+	if err := p.notify(ctx, event, n); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("The contract of %s (%s) ends %s, on %s", data.EmployeeName, data.EmployeeNumber, when, data.ContractEndDate)
+	var errs []error
+	for _, u := range admins {
+		if u.ID == data.EmployeeUserID {
+			continue
+		}
+		n := &model.Notification{
+			UserID:  u.ID,
+			Title:   "Contract Ending Soon",
+			Message: message,
+			Type:    model.NotificationTypeWarning,
+			RefID:   refID(data.EmployeeID),
+			RefType: "employee",
+		}
+		if err := p.notify(ctx, event, n); err != nil {
+			errs = append(errs, fmt.Errorf("notify user %s: %w", u.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// handleClockInMissing reminds the employee to clock in.
+func (p *EventProcessor) handleClockInMissing(ctx context.Context, event *NotificationEvent) error {
+	var data ClockInMissingPayload
+	if err := json.Unmarshal(event.Payload, &data); err != nil {
+		return fmt.Errorf("unmarshal ClockInMissingPayload: %w", err)
+	}
+
+	n := &model.Notification{
+		UserID:  data.EmployeeUserID,
+		Title:   "Missing Clock-In",
+		Message: fmt.Sprintf("You have not clocked in yet for your %s shift on %s, which started at %s.", data.ShiftName, data.Date, data.ShiftStart),
+		Type:    model.NotificationTypeWarning,
+		RefID:   refID(data.EmployeeID),
+		RefType: "attendance",
+	}
+	return p.notify(ctx, event, n)
+}
+
+// handleModuleToggled tells the admins of a company that a module was
+// enabled or disabled for it.
+func (p *EventProcessor) handleModuleToggled(ctx context.Context, event *NotificationEvent) error {
+	var data ModuleToggledPayload
+	if err := json.Unmarshal(event.Payload, &data); err != nil {
+		return fmt.Errorf("unmarshal ModuleToggledPayload: %w", err)
+	}
+
+	admins, err := p.userRepo.FindByRolesInCompany(ctx, data.CompanyID, []string{"admin"})
+	if err != nil {
+		return fmt.Errorf("find admin users: %w", err)
+	}
+
+	title := "Module Enabled"
+	message := fmt.Sprintf("%s was enabled for your company", data.ModuleName)
+	if event.EventType == EventModuleDisabled {
+		title = "Module Disabled"
+		message = fmt.Sprintf("%s was disabled for your company", data.ModuleName)
+	}
+
+	var errs []error
+	for _, u := range admins {
+		n := &model.Notification{
+			UserID:  u.ID,
+			Title:   title,
+			Message: message,
+			Type:    model.NotificationTypeInfo,
+			RefID:   refID(data.CompanyID),
+			RefType: "company",
+		}
+		if err := p.notify(ctx, event, n); err != nil {
+			errs = append(errs, fmt.Errorf("notify user %s: %w", u.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// handleAnnouncementPublished notifies the employees an announcement is for,
+// except its author.
+func (p *EventProcessor) handleAnnouncementPublished(ctx context.Context, event *NotificationEvent) error {
+	var data AnnouncementPublishedPayload
+	if err := json.Unmarshal(event.Payload, &data); err != nil {
+		return fmt.Errorf("unmarshal AnnouncementPublishedPayload: %w", err)
+	}
+
+	audience, err := p.userRepo.FindAnnouncementAudience(ctx, data.CompanyID, data.DepartmentIDs, data.JobLevelIDs)
+	if err != nil {
+		return fmt.Errorf("find announcement audience: %w", err)
+	}
+
+	title := "New Announcement"
+	if data.IsPinned {
+		title = "Important Announcement"
+	}
+
+	var errs []error
+	for _, u := range audience {
+		if u.ID == data.AuthorUserID {
+			continue
+		}
+		n := &model.Notification{
+			UserID:  u.ID,
+			Title:   title,
+			Message: data.Title,
+			Type:    model.NotificationTypeInfo,
+			RefID:   refID(data.AnnouncementID),
+			RefType: "announcement",
+		}
+		if err := p.notify(ctx, event, n); err != nil {
+			errs = append(errs, fmt.Errorf("notify user %s: %w", u.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// handleNotificationDigest sends a user the summary of the notifications
+// held back for their digest.
+func (p *EventProcessor) handleNotificationDigest(ctx context.Context, event *NotificationEvent) error {
+	var data NotificationDigestPayload
+	if err := json.Unmarshal(event.Payload, &data); err != nil {
+		return fmt.Errorf("unmarshal NotificationDigestPayload: %w", err)
+	}
+
+	// Count the items by title, in the order they first occurred
+	var titles []string
+	counts := make(map[string]int)
+	for _, item := range data.Items {
+		if counts[item.Title] == 0 {
+			titles = append(titles, item.Title)
+		}
+		counts[item.Title]++
+	}
+	summary := make([]string, len(titles))
+	for i, title := range titles {
+		summary[i] = fmt.Sprintf("%s (%d)", title, counts[title])
+	}
+	if more := data.Total - len(data.Items); more > 0 {
+		summary = append(summary, fmt.Sprintf("%d more", more))
+	}
+
+	n := &model.Notification{
+		UserID:  data.UserID,
+		Title:   "Daily Summary",
+		Message: fmt.Sprintf("You have %d notifications from %s: %s", data.Total, data.Date, strings.Join(summary, ", ")),
+		Type:    model.NotificationTypeInfo,
+		RefType: "digest",
+	}
+	return p.notify(ctx, event, n)
+}
+
+// refID is the RefID of a notification about the record id, or none
+func refID(id string) *string {
+	if id == "" {
+		return nil
+	}
+	return &id
+}
+
+// notify stores a notification created from event and delivers it through
+// the channels, as the user chose for the event type: it is held back for
+// their digest instead, kept in the application only, or dropped. The event
+// ID makes this idempotent: a redelivered event does not notify anyone
+// twice, and channels and digests dedupe by it too. A channel failing fails
+// the event, so it is retried. Handlers that notify several users try every
+// one and return the errors together, so the retry reaches those that failed.
+func (p *EventProcessor) notify(ctx context.Context, event *NotificationEvent, n *model.Notification) error {
+	if event.EventID != "" {
+		n.EventID = &event.EventID
+	}
+
+	mode := model.NotificationModeEmail
+	if HasPreference(event.EventType) {
+		chosen, err := p.prefRepo.FindMode(ctx, n.UserID, string(event.EventType))
+		if err != nil {
+			return fmt.Errorf("find notification preference: %w", err)
+		}
+		if chosen != "" {
+			mode = chosen
+		}
+	}
+
+	switch mode {
+	case model.NotificationModeOff:
+		return nil
+	case model.NotificationModeDigest:
+		return p.digestRepo.Create(ctx, &model.NotificationDigestItem{
+			UserID:    n.UserID,
+			EventID:   n.EventID,
+			EventType: string(event.EventType),
+			Title:     n.Title,
+			Message:   n.Message,
+			Type:      n.Type,
+			RefID:     n.RefID,
+			RefType:   n.RefType,
+		})
+	}
+
+	if err := p.notifRepo.Create(ctx, n); err != nil {
+		return err
+	}
+	if mode == model.NotificationModeInApp {
+		return nil
+	}
+	for _, ch := range p.channels {
+		if err := ch.Deliver(ctx, event, n); err != nil {
+			return fmt.Errorf("deliver notification: %w", err)
+		}
+	}
+	return nil
 }
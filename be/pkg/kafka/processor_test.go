@@ -0,0 +1,136 @@
+package kafka
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"hris-backend/internal/model"
+	"hris-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB connects to the Postgres database in TEST_DATABASE_URL and
+// migrates the notification tables into it. Tests that need one are skipped
+// when it is not set.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("connect to the test database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.Notification{}, &model.NotificationPreference{}, &model.NotificationDigestItem{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func newTestProcessor(db *gorm.DB) *EventProcessor {
+	return NewEventProcessor(
+		repository.NewNotificationRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewNotificationPreferenceRepository(db),
+		repository.NewNotificationDigestRepository(db),
+		repository.NewLeaveDelegationRepository(db),
+	)
+}
+
+// handleEvent passes an event to the processor as the consumer would
+func handleEvent(t *testing.T, p *EventProcessor, eventType EventType, payload any) {
+	t.Helper()
+	event, err := newEvent(uuid.NewString(), eventType, "", payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Handle(&Message{Value: value}); err != nil {
+		t.Fatalf("handle %s: %v", eventType, err)
+	}
+}
+
+func TestProcessorStoresNotificationsWithoutRef(t *testing.T) {
+	db := openTestDB(t)
+	p := newTestProcessor(db)
+	userID := uuid.NewString()
+	t.Cleanup(func() {
+		db.Unscoped().Where("user_id = ?", userID).Delete(&model.Notification{})
+	})
+
+	handleEvent(t, p, EventNotificationDigest, NotificationDigestPayload{
+		UserID: userID,
+		Date:   "2026-10-16",
+		Total:  2,
+		Items: []DigestItem{
+			{EventType: string(EventLeaveStatusChanged), Title: "Leave Approved", RefID: uuid.NewString(), RefType: "leave"},
+			{EventType: string(EventModuleEnabled), Title: "Module Enabled"},
+		},
+	})
+	handleEvent(t, p, EventPasswordResetRequested, PasswordResetRequestedPayload{
+		UserID:    userID,
+		Name:      "Budi Santoso",
+		Email:     "budi@example.com",
+		ExpiresAt: "2026-10-16 10:00",
+	})
+
+	var stored []model.Notification
+	if err := db.Where("user_id = ?", userID).Order("ref_type").Find(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 2 {
+		t.Fatalf("stored %d notifications, want 2", len(stored))
+	}
+	for i, refType := range []string{"digest", "password_reset"} {
+		if stored[i].RefType != refType || stored[i].RefID != nil {
+			t.Errorf("notification %d: ref %s %v, want %s without a ref ID", i, stored[i].RefType, stored[i].RefID, refType)
+		}
+	}
+	if want := "You have 2 notifications from 2026-10-16: Leave Approved (1), Module Enabled (1)"; stored[0].Message != want {
+		t.Errorf("digest message %q, want %q", stored[0].Message, want)
+	}
+}
+
+func TestProcessorHoldsBackDigestItems(t *testing.T) {
+	db := openTestDB(t)
+	p := newTestProcessor(db)
+	userID := uuid.NewString()
+	t.Cleanup(func() {
+		db.Unscoped().Where("user_id = ?", userID).Delete(&model.NotificationDigestItem{})
+		db.Unscoped().Where("user_id = ?", userID).Delete(&model.NotificationPreference{})
+	})
+	err := repository.NewNotificationPreferenceRepository(db).Upsert(t.Context(), []model.NotificationPreference{
+		{UserID: userID, EventType: string(EventClockInMissing), Mode: model.NotificationModeDigest},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	employeeID := uuid.NewString()
+	handleEvent(t, p, EventClockInMissing, ClockInMissingPayload{
+		EmployeeID:     employeeID,
+		EmployeeUserID: userID,
+		EmployeeName:   "Budi Santoso",
+		Date:           "2026-10-16",
+		ShiftName:      "Morning",
+		ShiftStart:     "08:00",
+	})
+
+	items, err := repository.NewNotificationDigestRepository(db).FindPending(t.Context(), userID, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].RefID == nil || *items[0].RefID != employeeID {
+		t.Fatalf("held back %+v, want one item referring to employee %s", items, employeeID)
+	}
+}
@@ -4,42 +4,68 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
-	"hash/crc32"
+	"io"
 	"log"
 	"net"
+	"sync"
 	"time"
 )
 
-// Producer sends messages to a Kafka topic using the Kafka binary protocol v0.
+const (
+	// metadataMaxAge bounds how long the producer trusts the partition
+	// leaders it knows when no error says they moved
+	metadataMaxAge = 5 * time.Minute
+
+	// produceAttempts is how many times a message is sent, with fresh
+	// metadata in between, before Send reports its error
+	produceAttempts = 2
+
+	// produceAckTimeout is how long the leader waits for the replicas to
+	// acknowledge a batch. It is below requestTimeout so the broker answers
+	// before the producer gives up on the connection.
+	produceAckTimeout = 4 * time.Second
+)
+
+// Producer sends messages to Kafka as record batches (Produce v3). It looks
+// up the partitions of each topic and their leaders in the brokers'
+// metadata. A message with a key goes to the partition the key hashes to,
+// as with the Java client, so messages with the same key stay in order;
+// messages without one are spread over the partitions.
 type Producer struct {
-	brokers []string
-	topic   string
+	brokers     []string
+	topic       string
+	compression Compression
+
+	// mu serializes sends, which share the connections and metadata
+	mu        sync.Mutex
+	conns     brokerConns
+	metadata  map[string]topicMetadata
+	fetchedAt map[string]time.Time
+	next      uint32
 }
 
-// NewProducer creates a new Kafka producer.
-func NewProducer(brokers []string, topic string) *Producer {
+// NewProducer creates a new Kafka producer that sends to topic by default
+// and compresses its batches with compression.
+func NewProducer(brokers []string, topic string, compression Compression) *Producer {
 	return &Producer{
-		brokers: brokers,
-		topic:   topic,
+		brokers:     brokers,
+		topic:       topic,
+		compression: compression,
+		conns:       make(brokerConns),
+		metadata:    make(map[string]topicMetadata),
+		fetchedAt:   make(map[string]time.Time),
 	}
 }
 
-// Publish sends a message to the Kafka topic.
-// It tries each broker until one succeeds.
+// Publish sends a message without key to the Kafka topic.
 func (p *Producer) Publish(value []byte) error {
-	var lastErr error
-	for _, broker := range p.brokers {
-		if err := p.sendToBroker(broker, value); err != nil {
-			log.Printf("[kafka] producer: failed to send to %s: %v", broker, err)
-			lastErr = err
-			continue
-		}
-		return nil
-	}
-	if lastErr != nil {
-		return fmt.Errorf("all kafka brokers failed: %w", lastErr)
-	}
-	return fmt.Errorf("no kafka brokers configured")
+	return p.PublishTo(p.topic, value)
+}
+
+// PublishTo sends a message without key to the given topic instead of the
+// producer's.
+func (p *Producer) PublishTo(topic string, value []byte) error {
+	return p.Send([]*Message{{Topic: topic, Value: value}})[0]
 }
 
 // PublishAsync sends a message asynchronously (fire-and-forget with logged errors).
@@ -51,75 +77,190 @@ func (p *Producer) PublishAsync(value []byte) {
 	}()
 }
 
-// sendToBroker sends a single ProduceRequest (v0) to the given broker.
-func (p *Producer) sendToBroker(broker string, value []byte) error {
-	conn, err := net.DialTimeout("tcp", broker, 5*time.Second)
-	if err != nil {
-		return fmt.Errorf("connect: %w", err)
+// Send publishes msgs, with one batch per partition and one request per
+// leader, and returns the error of each message, nil once the broker
+// acknowledged it. Messages without a topic go to the producer's topic.
+func (p *Producer) Send(msgs []*Message) []error {
+	errs := make([]error, len(msgs))
+	if len(msgs) == 0 {
+		return errs
+	}
+	if len(p.brokers) == 0 {
+		for i := range errs {
+			errs[i] = fmt.Errorf("no kafka brokers configured")
+		}
+		return errs
 	}
-	defer conn.Close()
 
-	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	req := p.buildProduceRequest(value)
-	if _, err := conn.Write(req); err != nil {
-		return fmt.Errorf("write request: %w", err)
+	pending := make([]int, len(msgs))
+	for i := range msgs {
+		pending[i] = i
+	}
+	for attempt := 0; attempt < produceAttempts && len(pending) > 0; attempt++ {
+		pending = p.sendOnce(msgs, pending, errs)
 	}
+	return errs
+}
 
-	return p.readProduceResponse(conn)
+// Close closes the producer's connections.
+func (p *Producer) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.conns.close()
 }
 
-// buildProduceRequest constructs a Kafka ProduceRequest (ApiKey=0, ApiVersion=0).
-func (p *Producer) buildProduceRequest(value []byte) []byte {
-	// Build the message first
-	msg := buildMessage(value)
+// produceRoute groups the indexes of messages by topic and partition, for
+// one leader
+type produceRoute map[string]map[int32][]int
 
-	// MessageSet: offset(8) + message_size(4) + message
-	var msgSet bytes.Buffer
-	writeInt64(&msgSet, 0) // offset
-	writeInt32(&msgSet, int32(len(msg)))
-	msgSet.Write(msg)
+// sendOnce sends the pending messages to their leaders, records their errors
+// and returns the ones worth retrying once the metadata is refreshed
+func (p *Producer) sendOnce(msgs []*Message, pending []int, errs []error) []int {
+	var retry []int
+	routes := make(map[string]produceRoute)
 
-	// Build the request body
-	var body bytes.Buffer
-	writeInt16(&body, 1)         // required_acks = 1 (leader)
-	writeInt32(&body, 10000)     // timeout_ms
-	writeInt32(&body, 1)         // topic array length
-	writeString(&body, p.topic)  // topic name
-	writeInt32(&body, 1)         // partition array length
-	writeInt32(&body, 0)         // partition = 0
-	writeInt32(&body, int32(msgSet.Len()))
-	body.Write(msgSet.Bytes())
+	// Messages without key in this send share a partition, so they make
+	// one batch
+	p.next++
+	for _, i := range pending {
+		topic := p.topicOf(msgs[i])
+		leaders, err := p.leaders(topic)
+		if err != nil {
+			errs[i] = err
+			retry = append(retry, i)
+			continue
+		}
 
-	return buildRequest(0, 0, "hris-producer", body.Bytes())
+		partition := choosePartition(msgs[i].Key, len(leaders), p.next)
+		addr := leaders[partition]
+		if routes[addr] == nil {
+			routes[addr] = make(produceRoute)
+		}
+		if routes[addr][topic] == nil {
+			routes[addr][topic] = make(map[int32][]int)
+		}
+		routes[addr][topic][partition] = append(routes[addr][topic][partition], i)
+	}
+
+	for addr, route := range routes {
+		results, err := p.produce(addr, route, msgs)
+		if err != nil {
+			log.Printf("[kafka] producer: failed to send to %s: %v", addr, err)
+			p.conns.drop(addr)
+			for topic, partitions := range route {
+				delete(p.fetchedAt, topic)
+				for _, indexes := range partitions {
+					for _, i := range indexes {
+						errs[i] = fmt.Errorf("produce to %s: %w", addr, err)
+						retry = append(retry, i)
+					}
+				}
+			}
+			continue
+		}
+
+		for topic, partitions := range route {
+			for partition, indexes := range partitions {
+				errCode, ok := results[topic][partition]
+				var err error
+				switch {
+				case !ok:
+					err = fmt.Errorf("partition %d of %s missing from produce response", partition, topic)
+				case errCode != errNone:
+					err = fmt.Errorf("partition %d of %s: kafka error code %d", partition, topic, errCode)
+				}
+				for _, i := range indexes {
+					errs[i] = err
+				}
+				if ok && isStaleMetadata(errCode) {
+					delete(p.fetchedAt, topic)
+					retry = append(retry, indexes...)
+				}
+			}
+		}
+	}
+	return retry
 }
 
-// readProduceResponse reads and validates the ProduceResponse.
-func (p *Producer) readProduceResponse(conn net.Conn) error {
-	// Read response size
-	var size int32
-	if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
-		return fmt.Errorf("read response size: %w", err)
+func (p *Producer) topicOf(msg *Message) string {
+	if msg.Topic != "" {
+		return msg.Topic
 	}
-	if size <= 0 || size > 1<<20 {
-		return fmt.Errorf("invalid response size: %d", size)
+	return p.topic
+}
+
+// leaders returns the partition leaders of topic, from the cache while it is
+// fresh and from the first broker that answers otherwise
+func (p *Producer) leaders(topic string) (topicMetadata, error) {
+	if leaders, ok := p.metadata[topic]; ok && time.Since(p.fetchedAt[topic]) < metadataMaxAge {
+		return leaders, nil
 	}
 
-	buf := make([]byte, size)
-	if _, err := readFull(conn, buf); err != nil {
-		return fmt.Errorf("read response body: %w", err)
+	var lastErr error
+	for _, broker := range p.brokers {
+		conn, err := p.conns.get(broker)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		leaders, err := fetchMetadata(conn, topic)
+		if err != nil {
+			p.conns.drop(broker)
+			lastErr = err
+			continue
+		}
+		p.metadata[topic] = leaders
+		p.fetchedAt[topic] = time.Now()
+		return leaders, nil
+	}
+	return nil, fmt.Errorf("metadata for %s: %w", topic, lastErr)
+}
+
+// produce sends one ProduceRequest (v3) with a record batch per partition of
+// route and returns the error code of each partition
+func (p *Producer) produce(addr string, route produceRoute, msgs []*Message) (map[string]map[int32]int16, error) {
+	conn, err := p.conns.get(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	writeInt16(&body, -1) // transactional_id = null
+	writeInt16(&body, -1) // required_acks = all in-sync replicas
+	writeInt32(&body, int32(produceAckTimeout.Milliseconds()))
+	writeInt32(&body, int32(len(route)))
+	for topic, partitions := range route {
+		writeString(&body, topic)
+		writeInt32(&body, int32(len(partitions)))
+		for partition, indexes := range partitions {
+			batchMsgs := make([]*Message, len(indexes))
+			for k, i := range indexes {
+				batchMsgs[k] = msgs[i]
+			}
+			batch, err := encodeRecordBatch(batchMsgs, p.compression)
+			if err != nil {
+				return nil, err
+			}
+			writeInt32(&body, partition)
+			writeInt32(&body, int32(len(batch)))
+			body.Write(batch)
+		}
 	}
 
-	r := bytes.NewReader(buf)
-	// correlation_id (skip)
-	var correlationID int32
-	binary.Read(r, binary.BigEndian, &correlationID)
+	r, err := roundTrip(conn, apiProduce, 3, body.Bytes())
+	if err != nil {
+		return nil, err
+	}
 
-	// topic array
+	results := make(map[string]map[int32]int16)
 	var topicCount int32
 	binary.Read(r, binary.BigEndian, &topicCount)
 	for i := int32(0); i < topicCount; i++ {
-		readStringBuf(r) // topic name
+		topic := readStringBuf(r)
+		results[topic] = make(map[int32]int16)
 		var partCount int32
 		binary.Read(r, binary.BigEndian, &partCount)
 		for j := int32(0); j < partCount; j++ {
@@ -127,31 +268,74 @@ func (p *Producer) readProduceResponse(conn net.Conn) error {
 			var errCode int16
 			binary.Read(r, binary.BigEndian, &partition)
 			binary.Read(r, binary.BigEndian, &errCode)
-			if errCode != 0 {
-				return fmt.Errorf("kafka error code %d on partition %d", errCode, partition)
-			}
+			r.Seek(8+8, io.SeekCurrent) // base_offset, log_append_time
+			results[topic][partition] = errCode
 		}
 	}
-	return nil
+	return results, nil
+}
+
+// isStaleMetadata reports whether a produce error means the partition moved
+// or the producer's view of the topic is out of date
+func isStaleMetadata(errCode int16) bool {
+	switch errCode {
+	case errUnknownTopicOrPartition, errLeaderNotAvailable, errNotLeaderForPartition:
+		return true
+	}
+	return false
 }
 
-// buildMessage constructs a Kafka message (magic=0, no compression, no key).
-func buildMessage(value []byte) []byte {
-	var payload bytes.Buffer
-	payload.WriteByte(0) // magic = 0
-	payload.WriteByte(0) // attributes = 0 (no compression)
-	writeInt32(&payload, -1)               // key = null
-	writeInt32(&payload, int32(len(value))) // value length
-	payload.Write(value)
+// choosePartition picks the partition of a message: the murmur2 hash of its
+// key, as the Java client's default partitioner does, or the partition of
+// this send's messages without key
+func choosePartition(key []byte, partitions int, next uint32) int32 {
+	if key == nil {
+		return int32(next % uint32(partitions))
+	}
+	return int32((murmur2(key) & 0x7fffffff) % uint32(partitions))
+}
+
+// murmur2 is the hash of the Java client's default partitioner
+func murmur2(data []byte) uint32 {
+	const (
+		seed uint32 = 0x9747b28c
+		m    uint32 = 0x5bd1e995
+		r           = 24
+	)
 
-	crc := crc32.ChecksumIEEE(payload.Bytes())
+	length := len(data)
+	h := seed ^ uint32(length)
+	for i := 0; i+4 <= length; i += 4 {
+		k := binary.LittleEndian.Uint32(data[i:])
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+
+	tail := data[length&^3:]
+	switch len(tail) {
+	case 3:
+		h ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(tail[0])
+		h *= m
+	}
 
-	var msg bytes.Buffer
-	writeInt32(&msg, int32(crc))
-	msg.Write(payload.Bytes())
-	return msg.Bytes()
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return h
 }
 
+// clientID identifies the backend's requests to the brokers
+const clientID = "hris-backend"
+
 // buildRequest constructs a full Kafka request frame with header.
 func buildRequest(apiKey, apiVersion int16, clientID string, body []byte) []byte {
 	var header bytes.Buffer
@@ -194,6 +378,40 @@ func writeString(w *bytes.Buffer, s string) {
 	w.WriteString(s)
 }
 
+// roundTrip sends a request on conn and returns the body of the response,
+// past its correlation ID
+func roundTrip(conn net.Conn, apiKey, apiVersion int16, body []byte) (*bytes.Reader, error) {
+	return roundTripWithin(conn, apiKey, apiVersion, body, requestTimeout)
+}
+
+// roundTripWithin is roundTrip for requests the broker may hold longer than
+// requestTimeout, such as joining a group
+func roundTripWithin(conn net.Conn, apiKey, apiVersion int16, body []byte, timeout time.Duration) (*bytes.Reader, error) {
+	conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.Write(buildRequest(apiKey, apiVersion, clientID, body)); err != nil {
+		return nil, fmt.Errorf("write request: %w", err)
+	}
+	return readResponse(conn)
+}
+
+// readResponse reads one response frame and returns its body, past the
+// correlation ID
+func readResponse(conn net.Conn) (*bytes.Reader, error) {
+	var size int32
+	if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+		return nil, fmt.Errorf("read response size: %w", err)
+	}
+	if size < 4 || size > maxResponseSize {
+		return nil, fmt.Errorf("invalid response size: %d", size)
+	}
+
+	buf := make([]byte, size)
+	if _, err := readFull(conn, buf); err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	return bytes.NewReader(buf[4:]), nil
+}
+
 func readFull(conn net.Conn, buf []byte) (int, error) {
 	total := 0
 	for total < len(buf) {
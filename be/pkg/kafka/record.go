@@ -0,0 +1,386 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"time"
+)
+
+// Header is a key/value pair attached to a message, e.g. its event ID or
+// trace context
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+// Message is a Kafka record. Producers set Topic, Key, Value and Headers;
+// messages with the same key go to the same partition, in order. Consumers
+// also get the Partition, Offset and Timestamp the broker stored.
+type Message struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   []Header
+	Timestamp time.Time
+}
+
+// Header returns the value of the first header named key, or "" when the
+// message has none
+func (m *Message) Header(key string) string {
+	for _, h := range m.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// RecordBatch v2 (magic 2) layout, up to the records:
+//
+//	baseOffset int64, batchLength int32, partitionLeaderEpoch int32,
+//	magic int8, crc uint32, attributes int16, lastOffsetDelta int32,
+//	baseTimestamp int64, maxTimestamp int64, producerId int64,
+//	producerEpoch int16, baseSequence int32, recordCount int32
+//
+// batchLength counts the bytes after itself and the CRC (Castagnoli) covers
+// the bytes from attributes to the end of the batch.
+const (
+	batchHeaderSize   = 61
+	batchLengthOffset = 8
+	batchMagicOffset  = 16
+	batchCRCOffset    = 17
+	batchAttrOffset   = 21
+
+	batchMagic = 2
+
+	// batchControl marks a batch of control records, such as transaction
+	// markers, in the attributes of a record batch
+	batchControl = 1 << 5
+)
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// encodeRecordBatch builds a record batch of msgs, with offsets relative to
+// 0 for the broker to assign, and no idempotent producer state.
+func encodeRecordBatch(msgs []*Message, codec Compression) ([]byte, error) {
+	now := time.Now()
+	baseTimestamp, maxTimestamp := int64(-1), int64(-1)
+	timestamps := make([]int64, len(msgs))
+	for i, msg := range msgs {
+		ts := msg.Timestamp
+		if ts.IsZero() {
+			ts = now
+		}
+		timestamps[i] = ts.UnixMilli()
+		if baseTimestamp < 0 || timestamps[i] < baseTimestamp {
+			baseTimestamp = timestamps[i]
+		}
+		if timestamps[i] > maxTimestamp {
+			maxTimestamp = timestamps[i]
+		}
+	}
+
+	var records bytes.Buffer
+	for i, msg := range msgs {
+		var rec bytes.Buffer
+		rec.WriteByte(0) // attributes
+		writeVarint(&rec, timestamps[i]-baseTimestamp)
+		writeVarint(&rec, int64(i)) // offset delta
+		writeVarBytes(&rec, msg.Key)
+		writeVarBytes(&rec, msg.Value)
+		writeVarint(&rec, int64(len(msg.Headers)))
+		for _, h := range msg.Headers {
+			writeVarBytes(&rec, []byte(h.Key))
+			writeVarBytes(&rec, h.Value)
+		}
+
+		writeVarint(&records, int64(rec.Len()))
+		records.Write(rec.Bytes())
+	}
+
+	payload, err := compress(codec, records.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("compress records: %w", err)
+	}
+
+	var batch bytes.Buffer
+	writeInt64(&batch, 0)  // base offset
+	writeInt32(&batch, 0)  // batch length, set below
+	writeInt32(&batch, -1) // partition leader epoch
+	batch.WriteByte(batchMagic)
+	writeInt32(&batch, 0) // crc, set below
+	writeInt16(&batch, int16(codec))
+	writeInt32(&batch, int32(len(msgs)-1)) // last offset delta
+	writeInt64(&batch, baseTimestamp)
+	writeInt64(&batch, maxTimestamp)
+	writeInt64(&batch, -1) // producer id
+	writeInt16(&batch, -1) // producer epoch
+	writeInt32(&batch, -1) // base sequence
+	writeInt32(&batch, int32(len(msgs)))
+	batch.Write(payload)
+
+	b := batch.Bytes()
+	binary.BigEndian.PutUint32(b[batchLengthOffset:], uint32(len(b)-batchLengthOffset-4))
+	binary.BigEndian.PutUint32(b[batchCRCOffset:], crc32.Checksum(b[batchAttrOffset:], crc32c))
+	return b, nil
+}
+
+// decodeRecords decodes the records of a fetched partition, which hold record
+// batches and, for data written by old producers, legacy message sets. It
+// also returns the offset following the last complete batch, which can be
+// past the last message when a batch holds only control records. A partial
+// batch at the end is normal: fetches are cut at a size limit.
+func decodeRecords(data []byte) ([]Message, int64) {
+	var msgs []Message
+	next := int64(-1)
+
+	for len(data) >= batchMagicOffset+1 {
+		baseOffset := int64(binary.BigEndian.Uint64(data))
+		size := int32(binary.BigEndian.Uint32(data[batchLengthOffset:]))
+		if size <= 0 || int(size) > len(data)-12 {
+			break
+		}
+		entry := data[:12+size]
+		data = data[12+size:]
+
+		if entry[batchMagicOffset] < batchMagic {
+			legacy := parseLegacyMessage(baseOffset, entry[12:])
+			msgs = append(msgs, legacy...)
+			if n := len(legacy); n > 0 && legacy[n-1].Offset >= next {
+				next = legacy[n-1].Offset + 1
+			}
+			continue
+		}
+
+		batch, last, err := decodeRecordBatch(entry)
+		if err != nil {
+			// A batch that cannot be read never will be; skip it rather
+			// than stall the partition
+			log.Printf("[kafka] skipping record batch at offset %d: %v", baseOffset, err)
+		}
+		msgs = append(msgs, batch...)
+		if last >= next {
+			next = last + 1
+		}
+	}
+	return msgs, next
+}
+
+// decodeRecordBatch decodes one record batch and returns its messages and
+// the offset of its last record. Control batches, e.g. transaction markers,
+// yield no messages.
+func decodeRecordBatch(entry []byte) ([]Message, int64, error) {
+	baseOffset := int64(binary.BigEndian.Uint64(entry))
+	if len(entry) < batchHeaderSize {
+		return nil, baseOffset, errors.New("truncated batch header")
+	}
+
+	r := bytes.NewReader(entry[batchAttrOffset:])
+	var attributes int16
+	var lastOffsetDelta, count int32
+	var baseTimestamp int64
+	binary.Read(r, binary.BigEndian, &attributes)
+	binary.Read(r, binary.BigEndian, &lastOffsetDelta)
+	binary.Read(r, binary.BigEndian, &baseTimestamp)
+	r.Seek(8+8+2+4, io.SeekCurrent) // max timestamp, producer id, producer epoch, base sequence
+	binary.Read(r, binary.BigEndian, &count)
+	lastOffset := baseOffset + int64(lastOffsetDelta)
+
+	if crc := binary.BigEndian.Uint32(entry[batchCRCOffset:]); crc != crc32.Checksum(entry[batchAttrOffset:], crc32c) {
+		return nil, lastOffset, errors.New("crc mismatch")
+	}
+	if attributes&batchControl != 0 {
+		return nil, lastOffset, nil
+	}
+
+	records, err := decompress(Compression(attributes&compressionMask), entry[batchHeaderSize:])
+	if err != nil {
+		return nil, lastOffset, err
+	}
+
+	rr := bytes.NewReader(records)
+	msgs := make([]Message, 0, count)
+	for i := int32(0); i < count; i++ {
+		length, err := binary.ReadVarint(rr)
+		if err != nil || length < 0 || length > int64(rr.Len()) {
+			return msgs, lastOffset, fmt.Errorf("record %d: invalid length", i)
+		}
+		pos := len(records) - rr.Len()
+		rec := bytes.NewReader(records[pos : pos+int(length)])
+		rr.Seek(length, io.SeekCurrent)
+
+		msg, err := decodeRecord(rec, baseOffset, baseTimestamp)
+		if err != nil {
+			return msgs, lastOffset, fmt.Errorf("record %d: %w", i, err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, lastOffset, nil
+}
+
+func decodeRecord(r *bytes.Reader, baseOffset, baseTimestamp int64) (Message, error) {
+	var msg Message
+	if _, err := r.ReadByte(); err != nil { // attributes
+		return msg, err
+	}
+	timestampDelta, err := binary.ReadVarint(r)
+	if err != nil {
+		return msg, err
+	}
+	offsetDelta, err := binary.ReadVarint(r)
+	if err != nil {
+		return msg, err
+	}
+	msg.Offset = baseOffset + offsetDelta
+	msg.Timestamp = time.UnixMilli(baseTimestamp + timestampDelta)
+
+	if msg.Key, err = readVarBytes(r); err != nil {
+		return msg, err
+	}
+	if msg.Value, err = readVarBytes(r); err != nil {
+		return msg, err
+	}
+
+	headerCount, err := binary.ReadVarint(r)
+	if err != nil {
+		return msg, err
+	}
+	for i := int64(0); i < headerCount; i++ {
+		key, err := readVarBytes(r)
+		if err != nil {
+			return msg, err
+		}
+		value, err := readVarBytes(r)
+		if err != nil {
+			return msg, err
+		}
+		msg.Headers = append(msg.Headers, Header{Key: string(key), Value: value})
+	}
+	return msg, nil
+}
+
+// parseLegacyMessage decodes a message of magic 0 or 1:
+// crc(4) + magic(1) + attributes(1) + [timestamp(8), magic 1 only] + key + value.
+// A compressed message wraps a message set, which is decoded in turn.
+func parseLegacyMessage(offset int64, msgData []byte) []Message {
+	if len(msgData) < 6 {
+		return nil
+	}
+	mr := bytes.NewReader(msgData[4:]) // skip CRC
+
+	var magic, attributes int8
+	binary.Read(mr, binary.BigEndian, &magic)
+	binary.Read(mr, binary.BigEndian, &attributes)
+	msg := Message{Offset: offset}
+	if magic == 1 {
+		var timestamp int64
+		binary.Read(mr, binary.BigEndian, &timestamp)
+		msg.Timestamp = time.UnixMilli(timestamp)
+	}
+
+	// key
+	var keyLen int32
+	binary.Read(mr, binary.BigEndian, &keyLen)
+	if keyLen > 0 {
+		if int(keyLen) > mr.Len() {
+			return nil
+		}
+		msg.Key = make([]byte, keyLen)
+		mr.Read(msg.Key)
+	}
+
+	// value
+	var valLen int32
+	binary.Read(mr, binary.BigEndian, &valLen)
+	if valLen < 0 || int(valLen) > mr.Len() {
+		return nil // null value
+	}
+	msg.Value = make([]byte, valLen)
+	mr.Read(msg.Value)
+
+	codec := Compression(attributes & compressionMask)
+	if codec == CompressionNone {
+		return []Message{msg}
+	}
+
+	inner, err := decompress(codec, msg.Value)
+	if err != nil {
+		log.Printf("[kafka] skipping message at offset %d: %v", offset, err)
+		return nil
+	}
+	msgs := parseMessageSet(inner)
+	// Inner offsets of magic 1 are relative, and the wrapper carries the
+	// offset of the last inner message
+	if magic == 1 && len(msgs) > 0 {
+		delta := offset - msgs[len(msgs)-1].Offset
+		for i := range msgs {
+			msgs[i].Offset += delta
+		}
+	}
+	return msgs
+}
+
+// parseMessageSet decodes a raw legacy MessageSet byte slice into individual
+// messages. Handles partial trailing messages gracefully.
+func parseMessageSet(data []byte) []Message {
+	r := bytes.NewReader(data)
+	var msgs []Message
+
+	for r.Len() >= 12 { // minimum: offset(8) + size(4)
+		var offset int64
+		var msgSize int32
+		binary.Read(r, binary.BigEndian, &offset)
+		binary.Read(r, binary.BigEndian, &msgSize)
+
+		if msgSize <= 0 || int(msgSize) > r.Len() {
+			// Partial message at end of batch — normal for Kafka
+			break
+		}
+
+		msgData := make([]byte, msgSize)
+		r.Read(msgData)
+		msgs = append(msgs, parseLegacyMessage(offset, msgData)...)
+	}
+	return msgs
+}
+
+// --- Varint helpers (zigzag encoded, as in protobuf) ---
+
+func writeVarint(w *bytes.Buffer, v int64) {
+	b := make([]byte, binary.MaxVarintLen64)
+	w.Write(b[:binary.PutVarint(b, v)])
+}
+
+// writeVarBytes writes a varint length followed by b; nil is written as
+// length -1
+func writeVarBytes(w *bytes.Buffer, b []byte) {
+	if b == nil {
+		writeVarint(w, -1)
+		return
+	}
+	writeVarint(w, int64(len(b)))
+	w.Write(b)
+}
+
+func readVarBytes(r *bytes.Reader) ([]byte, error) {
+	n, err := binary.ReadVarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, nil
+	}
+	if n > int64(r.Len()) {
+		return nil, errors.New("truncated record")
+	}
+	b := make([]byte, n)
+	r.Read(b)
+	return b, nil
+}
@@ -0,0 +1,280 @@
+package mailer
+
+import (
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// smtpCatcher is an SMTP server that accepts every message, like the
+// catchers used in development, and keeps what it received
+type smtpCatcher struct {
+	ln net.Listener
+
+	// offerAuth advertises AUTH PLAIN and rejectRcpt refuses every recipient
+	offerAuth  bool
+	rejectRcpt bool
+
+	mu       sync.Mutex
+	received []caughtMessage
+}
+
+type caughtMessage struct {
+	auth string // decoded AUTH PLAIN response
+	from string
+	to   []string
+	data string
+}
+
+func newSMTPCatcher(t *testing.T) *smtpCatcher {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpCatcher{ln: ln}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpCatcher) config() Config {
+	_, port, _ := net.SplitHostPort(s.ln.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	return Config{Host: "127.0.0.1", Port: portNum, From: "HRIS <noreply@hris.test>", TLS: TLSNone}
+}
+
+func (s *smtpCatcher) messages() []caughtMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]caughtMessage(nil), s.received...)
+}
+
+func (s *smtpCatcher) serve(conn net.Conn) {
+	tp := textproto.NewConn(conn)
+	defer tp.Close()
+
+	var msg caughtMessage
+	tp.PrintfLine("220 catcher ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			if s.offerAuth {
+				tp.PrintfLine("250-catcher")
+				tp.PrintfLine("250 AUTH PLAIN")
+			} else {
+				tp.PrintfLine("250 catcher")
+			}
+		case "AUTH":
+			_, encoded, _ := strings.Cut(arg, " ")
+			decoded, _ := base64.StdEncoding.DecodeString(encoded)
+			msg.auth = string(decoded)
+			tp.PrintfLine("235 authenticated")
+		case "MAIL":
+			msg.from = addressOf(arg)
+			tp.PrintfLine("250 ok")
+		case "RCPT":
+			if s.rejectRcpt {
+				tp.PrintfLine("550 no such user")
+				continue
+			}
+			msg.to = append(msg.to, addressOf(arg))
+			tp.PrintfLine("250 ok")
+		case "DATA":
+			tp.PrintfLine("354 end with .")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.data = string(data)
+			s.mu.Lock()
+			s.received = append(s.received, msg)
+			s.mu.Unlock()
+			msg = caughtMessage{}
+			tp.PrintfLine("250 queued")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+// addressOf returns the address of a MAIL FROM:<...> or RCPT TO:<...>
+// argument
+func addressOf(arg string) string {
+	start, end := strings.Index(arg, "<"), strings.Index(arg, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return arg[start+1 : end]
+}
+
+func TestSendDeliversMultipartMessage(t *testing.T) {
+	catcher := newSMTPCatcher(t)
+	m, err := New(catcher.config())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = m.Send(&Message{
+		To:      "budi@example.com",
+		ToName:  "Budi Santoso",
+		Subject: "Cuti disetujui — 3 hari\r\nBcc: attacker@example.com",
+		Text:    "Pengajuan cuti Anda disetujui.\nSampai jumpa!",
+		HTML:    `<p style="color: #333">Pengajuan cuti Anda <b>disetujui</b>.</p>`,
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	received := catcher.messages()
+	if len(received) != 1 {
+		t.Fatalf("caught %d messages, want 1", len(received))
+	}
+	caught := received[0]
+	if caught.from != "noreply@hris.test" || len(caught.to) != 1 || caught.to[0] != "budi@example.com" {
+		t.Fatalf("envelope from %q to %v", caught.from, caught.to)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(caught.data))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	if got := parsed.Header.Get("Bcc"); got != "" {
+		t.Errorf("subject injected a Bcc header: %q", got)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "Cuti disetujui — 3 hari  Bcc: attacker@example.com" {
+		t.Errorf("subject %q", subject)
+	}
+	to, err := parsed.Header.AddressList("To")
+	if err != nil || len(to) != 1 || to[0].Name != "Budi Santoso" || to[0].Address != "budi@example.com" {
+		t.Errorf("To %v (%v)", to, err)
+	}
+	if parsed.Header.Get("Message-ID") == "" || parsed.Header.Get("Date") == "" {
+		t.Error("missing Message-ID or Date")
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("content type %q (%v)", mediaType, err)
+	}
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+	want := []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", "Pengajuan cuti Anda disetujui.\nSampai jumpa!"},
+		{"text/html; charset=utf-8", `<p style="color: #333">Pengajuan cuti Anda <b>disetujui</b>.</p>`},
+	}
+	for _, w := range want {
+		part, err := mr.NextRawPart()
+		if err != nil {
+			t.Fatalf("part %s: %v", w.contentType, err)
+		}
+		if got := part.Header.Get("Content-Type"); got != w.contentType {
+			t.Errorf("part content type %q, want %q", got, w.contentType)
+		}
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The message travels with CRLF line endings
+		if got := strings.ReplaceAll(string(body), "\r\n", "\n"); got != w.body {
+			t.Errorf("%s body %q, want %q", w.contentType, got, w.body)
+		}
+	}
+	if _, err := mr.NextRawPart(); err != io.EOF {
+		t.Errorf("expected two parts, got more (%v)", err)
+	}
+}
+
+func TestSendAuthenticates(t *testing.T) {
+	catcher := newSMTPCatcher(t)
+	catcher.offerAuth = true
+	cfg := catcher.config()
+	cfg.Username, cfg.Password = "hris", "secret"
+	m, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Send(&Message{To: "budi@example.com", Subject: "Hi", Text: "Hi", HTML: "<p>Hi</p>"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	received := catcher.messages()
+	if len(received) != 1 || received[0].auth != "\x00hris\x00secret" {
+		t.Fatalf("caught %+v, want one message sent with PLAIN credentials", received)
+	}
+}
+
+func TestSendReportsRejectedRecipient(t *testing.T) {
+	catcher := newSMTPCatcher(t)
+	catcher.rejectRcpt = true
+	m, err := New(catcher.config())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = m.Send(&Message{To: "nobody@example.com", Subject: "Hi", Text: "Hi", HTML: "<p>Hi</p>"})
+	if err == nil || !strings.Contains(err.Error(), "rcpt to") {
+		t.Fatalf("Send = %v, want a rcpt to error", err)
+	}
+	if len(catcher.messages()) != 0 {
+		t.Fatal("a message was caught")
+	}
+}
+
+func TestSendRequiresOfferedStartTLS(t *testing.T) {
+	catcher := newSMTPCatcher(t)
+	cfg := catcher.config()
+	cfg.TLS = TLSStartTLS
+	m, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = m.Send(&Message{To: "budi@example.com", Subject: "Hi", Text: "Hi", HTML: "<p>Hi</p>"})
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Fatalf("Send = %v, want a STARTTLS error", err)
+	}
+	if len(catcher.messages()) != 0 {
+		t.Fatal("a message went out unencrypted")
+	}
+}
+
+func TestNewRejectsInvalidConfig(t *testing.T) {
+	if _, err := New(Config{From: "not an address"}); err == nil {
+		t.Error("New accepted an invalid sender")
+	}
+	if _, err := New(Config{From: "noreply@hris.test", TLS: "ssl"}); err == nil {
+		t.Error("New accepted an unknown TLS mode")
+	}
+	if _, err := New(Config{From: "noreply@hris.test"}); err != nil {
+		t.Errorf("New with the default TLS mode: %v", err)
+	}
+}
@@ -0,0 +1,383 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID     = "hris"
+	testClientSecret = "s3cret"
+	testRedirectURL  = "https://hris.example.com/api/auth/oidc/acme/callback"
+)
+
+// mockProvider is an OpenID provider serving discovery, signing keys, the
+// token endpoint and userinfo over TLS. Authorize stands in for the browser
+// visit to the authorization endpoint.
+type mockProvider struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu     sync.Mutex
+	key    *rsa.PrivateKey
+	kid    string
+	codes  map[string]authorization
+	claims jwt.MapClaims
+	// docIssuer overrides the issuer named in the discovery document
+	docIssuer string
+	// jwksURI overrides the jwks_uri of the discovery document
+	jwksURI string
+}
+
+type authorization struct {
+	challenge string
+	nonce     string
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	t.Helper()
+	m := &mockProvider{t: t, codes: make(map[string]authorization)}
+	m.rotateKey("key-1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", m.discovery)
+	mux.HandleFunc("/jwks", m.jwks)
+	mux.HandleFunc("/token", m.token)
+	mux.HandleFunc("/userinfo", m.userinfo)
+	m.server = httptest.NewTLSServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockProvider) issuer() string {
+	return m.server.URL
+}
+
+func (m *mockProvider) client() *Client {
+	return NewClient(m.server.Client())
+}
+
+func (m *mockProvider) rotateKey(kid string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	m.mu.Lock()
+	m.key, m.kid = key, kid
+	m.mu.Unlock()
+}
+
+// authorize records a login the way the authorization endpoint would and
+// returns the code it redirects back with
+func (m *mockProvider) authorize(authURL string) string {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("client_id") != testClientID || q.Get("redirect_uri") != testRedirectURL || q.Get("code_challenge_method") != "S256" {
+		m.t.Fatalf("unexpected authorization request %s", authURL)
+	}
+	code := "code-" + q.Get("state")
+	m.mu.Lock()
+	m.codes[code] = authorization{challenge: q.Get("code_challenge"), nonce: q.Get("nonce")}
+	m.mu.Unlock()
+	return code
+}
+
+func (m *mockProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	issuer := m.issuer()
+	if m.docIssuer != "" {
+		issuer = m.docIssuer
+	}
+	jwksURI := m.issuer() + "/jwks"
+	if m.jwksURI != "" {
+		jwksURI = m.jwksURI
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                issuer,
+		"authorization_endpoint":                m.issuer() + "/authorize",
+		"token_endpoint":                        m.issuer() + "/token",
+		"userinfo_endpoint":                     m.issuer() + "/userinfo",
+		"jwks_uri":                              jwksURI,
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic"},
+	})
+}
+
+func (m *mockProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pub := m.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kid": m.kid,
+			"kty": "RSA",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func (m *mockProvider) token(w http.ResponseWriter, r *http.Request) {
+	clientID, secret, ok := r.BasicAuth()
+	if !ok || clientID != testClientID || secret != testClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	m.mu.Lock()
+	auth, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+	if !ok || PKCEChallenge(r.PostForm.Get("code_verifier")) != auth.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "code or verifier mismatch"})
+		return
+	}
+
+	idToken := m.sign(m.idTokenClaims(auth.nonce))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": "access-" + auth.nonce,
+		"id_token":     idToken,
+		"token_type":   "Bearer",
+		"expires_in":   300,
+	})
+}
+
+func (m *mockProvider) userinfo(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer access-") {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"sub":            "user-1",
+		"email":          "ani@acme.example",
+		"email_verified": "true",
+		"name":           "Ani",
+	})
+}
+
+func (m *mockProvider) idTokenClaims(nonce string) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"iss":   m.issuer(),
+		"sub":   "user-1",
+		"aud":   testClientID,
+		"exp":   time.Now().Add(5 * time.Minute).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": nonce,
+	}
+	m.mu.Lock()
+	for k, v := range m.claims {
+		claims[k] = v
+	}
+	m.mu.Unlock()
+	return claims
+}
+
+func (m *mockProvider) sign(claims jwt.MapClaims) string {
+	m.mu.Lock()
+	key, kid := m.key, m.kid
+	m.mu.Unlock()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	return signed
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func testConfig() Config {
+	return Config{
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+	}
+}
+
+func TestLoginFlow(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockProvider(t)
+	mock.claims = jwt.MapClaims{"email": "ani@acme.example", "email_verified": true, "name": "Ani"}
+	client := mock.client()
+
+	provider, err := client.Discover(ctx, mock.issuer())
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+
+	verifier, challenge, err := NewPKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := mock.authorize(provider.AuthCodeURL(testConfig(), "state-1", "nonce-1", challenge))
+
+	token, err := client.Exchange(ctx, provider, testConfig(), code, verifier)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	claims, err := client.VerifyIDToken(ctx, provider, testClientID, token.IDToken, "nonce-1")
+	if err != nil {
+		t.Fatalf("VerifyIDToken: %v", err)
+	}
+	if claims.Subject != "user-1" || claims.Email != "ani@acme.example" || claims.Name != "Ani" {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+	if claims.EmailVerified == nil || !*claims.EmailVerified {
+		t.Errorf("email_verified = %v, want true", claims.EmailVerified)
+	}
+
+	if _, err := client.Exchange(ctx, provider, testConfig(), code, verifier); err == nil {
+		t.Error("Exchange accepted a code twice")
+	}
+}
+
+func TestExchangeRejectsWrongVerifier(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockProvider(t)
+	client := mock.client()
+
+	provider, err := client.Discover(ctx, mock.issuer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, challenge, _ := NewPKCE()
+	code := mock.authorize(provider.AuthCodeURL(testConfig(), "state-1", "nonce-1", challenge))
+
+	otherVerifier, _, _ := NewPKCE()
+	_, err = client.Exchange(ctx, provider, testConfig(), code, otherVerifier)
+	if err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("Exchange with another verifier: err = %v, want invalid_grant", err)
+	}
+}
+
+func TestDiscoverRejectsForeignIssuer(t *testing.T) {
+	mock := newMockProvider(t)
+	mock.docIssuer = "https://other.example.com"
+
+	if _, err := mock.client().Discover(context.Background(), mock.issuer()); err == nil {
+		t.Error("Discover accepted a document naming another issuer")
+	}
+}
+
+func TestDiscoverRejectsPlainHTTPEndpoints(t *testing.T) {
+	mock := newMockProvider(t)
+	mock.jwksURI = strings.Replace(mock.issuer(), "https://", "http://", 1) + "/jwks"
+
+	if _, err := mock.client().Discover(context.Background(), mock.issuer()); err == nil {
+		t.Error("Discover accepted an http endpoint of an https issuer")
+	}
+}
+
+func TestVerifyIDTokenRejectsInvalidTokens(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockProvider(t)
+	client := mock.client()
+	provider, err := client.Discover(ctx, mock.issuer())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forged, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forgedToken := jwt.NewWithClaims(jwt.SigningMethodRS256, mock.idTokenClaims("nonce-1"))
+	forgedToken.Header["kid"] = "key-1"
+	forgedSigned, _ := forgedToken.SignedString(forged)
+
+	hmacSigned, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, mock.idTokenClaims("nonce-1")).SignedString([]byte("secret"))
+
+	with := func(key string, value interface{}) string {
+		claims := mock.idTokenClaims("nonce-1")
+		claims[key] = value
+		return mock.sign(claims)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"wrong nonce", mock.sign(mock.idTokenClaims("nonce-2"))},
+		{"wrong audience", with("aud", "someone-else")},
+		{"wrong issuer", with("iss", "https://other.example.com")},
+		{"expired", with("exp", time.Now().Add(-time.Hour).Unix())},
+		{"no subject", with("sub", "")},
+		{"forged signature", forgedSigned},
+		{"symmetric algorithm", hmacSigned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.VerifyIDToken(ctx, provider, testClientID, tt.token, "nonce-1"); err == nil {
+				t.Error("VerifyIDToken accepted the token")
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenFollowsKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockProvider(t)
+	client := mock.client()
+	provider, err := client.Discover(ctx, mock.issuer())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.VerifyIDToken(ctx, provider, testClientID, mock.sign(mock.idTokenClaims("n")), "n"); err != nil {
+		t.Fatalf("VerifyIDToken before rotation: %v", err)
+	}
+
+	mock.rotateKey("key-2")
+	if _, err := client.VerifyIDToken(ctx, provider, testClientID, mock.sign(mock.idTokenClaims("n")), "n"); err != nil {
+		t.Errorf("VerifyIDToken after rotation: %v", err)
+	}
+}
+
+func TestUserInfo(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockProvider(t)
+	client := mock.client()
+	provider, err := client.Discover(ctx, mock.issuer())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := client.UserInfo(ctx, provider, "access-n")
+	if err != nil {
+		t.Fatalf("UserInfo: %v", err)
+	}
+	if info.Subject != "user-1" || info.Email != "ani@acme.example" {
+		t.Errorf("unexpected userinfo %+v", info)
+	}
+	if info.EmailVerified == nil || !*info.EmailVerified {
+		t.Errorf("email_verified sent as a string = %v, want true", info.EmailVerified)
+	}
+}
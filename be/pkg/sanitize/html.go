@@ -0,0 +1,118 @@
+// Package sanitize cleans user-written rich text before it is stored and
+// shown to other users.
+package sanitize
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowedTags are the formatting elements rich text may use. Other elements
+// are dropped but their text is kept.
+var allowedTags = map[atom.Atom]bool{
+	atom.P: true, atom.Br: true, atom.Hr: true, atom.Div: true, atom.Span: true,
+	atom.B: true, atom.Strong: true, atom.I: true, atom.Em: true, atom.U: true, atom.S: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true,
+	atom.Ul: true, atom.Ol: true, atom.Li: true,
+	atom.Blockquote: true, atom.Code: true, atom.Pre: true, atom.A: true,
+}
+
+// droppedTags are dropped with everything inside them
+var droppedTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Iframe: true, atom.Object: true,
+	atom.Embed: true, atom.Template: true, atom.Noscript: true, atom.Title: true,
+}
+
+// allowedSchemes are the schemes links may point to
+var allowedSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+// HTML returns s with only the allowed formatting elements and no attributes
+// but the href of links to allowed schemes. Links open in a new tab without
+// access to the page that opened them. Text is escaped, so the result is
+// safe to render as HTML.
+func HTML(s string) string {
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(s))
+	dropDepth := 0
+	var dropping atom.Atom
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			// io.EOF, or input the tokenizer gives up on
+			return b.String()
+		}
+		tok := z.Token()
+
+		if dropDepth > 0 {
+			switch {
+			case tt == html.StartTagToken && tok.DataAtom == dropping:
+				dropDepth++
+			case tt == html.EndTagToken && tok.DataAtom == dropping:
+				dropDepth--
+			}
+			continue
+		}
+
+		switch tt {
+		case html.TextToken:
+			b.WriteString(html.EscapeString(tok.Data))
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if droppedTags[tok.DataAtom] {
+				if tt == html.StartTagToken {
+					dropping = tok.DataAtom
+					dropDepth = 1
+				}
+				continue
+			}
+			if !allowedTags[tok.DataAtom] {
+				continue
+			}
+			b.WriteString("<" + tok.DataAtom.String())
+			if tok.DataAtom == atom.A {
+				if href, ok := safeHref(tok.Attr); ok {
+					b.WriteString(` href="` + html.EscapeString(href) + `" target="_blank" rel="noopener noreferrer"`)
+				}
+			}
+			b.WriteString(">")
+		case html.EndTagToken:
+			if allowedTags[tok.DataAtom] && tok.DataAtom != atom.Br && tok.DataAtom != atom.Hr {
+				b.WriteString("</" + tok.DataAtom.String() + ">")
+			}
+		}
+	}
+}
+
+// Text returns the text of rich text s, with its whitespace collapsed
+func Text(s string) string {
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(b.String()), " ")
+		case html.TextToken:
+			b.Write(z.Text())
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			// Block elements separate words
+			b.WriteByte(' ')
+		}
+	}
+}
+
+func safeHref(attrs []html.Attribute) (string, bool) {
+	for _, attr := range attrs {
+		if attr.Key != "href" {
+			continue
+		}
+		u, err := url.Parse(strings.TrimSpace(attr.Val))
+		if err != nil || !allowedSchemes[strings.ToLower(u.Scheme)] {
+			return "", false
+		}
+		return u.String(), true
+	}
+	return "", false
+}
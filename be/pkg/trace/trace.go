@@ -0,0 +1,80 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// Context is a W3C trace context, carried in the traceparent header of HTTP
+// requests and Kafka messages so one trace follows a change from the request
+// that made it to the events it caused.
+type Context struct {
+	TraceID string
+	SpanID  string
+	Sampled bool
+}
+
+// New starts a trace
+func New() Context {
+	return Context{TraceID: randomHex(16), SpanID: randomHex(8), Sampled: true}
+}
+
+// Parse reads a traceparent header of version 00. It returns false for
+// missing or malformed headers, which start a new trace.
+func Parse(header string) (Context, bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) != 4 || parts[0] != "00" ||
+		!isHex(parts[1], 32) || !isHex(parts[2], 16) || !isHex(parts[3], 2) ||
+		parts[1] == strings.Repeat("0", 32) || parts[2] == strings.Repeat("0", 16) {
+		return Context{}, false
+	}
+	flags, _ := hex.DecodeString(parts[3])
+	return Context{TraceID: parts[1], SpanID: parts[2], Sampled: flags[0]&1 == 1}, true
+}
+
+// Child returns a new span of the same trace
+func (c Context) Child() Context {
+	return Context{TraceID: c.TraceID, SpanID: randomHex(8), Sampled: c.Sampled}
+}
+
+// String formats the context as a traceparent header
+func (c Context) String() string {
+	flags := "00"
+	if c.Sampled {
+		flags = "01"
+	}
+	return "00-" + c.TraceID + "-" + c.SpanID + "-" + flags
+}
+
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying tc
+func WithContext(ctx context.Context, tc Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, tc)
+}
+
+// FromContext returns the trace context ctx carries, if any
+func FromContext(ctx context.Context) (Context, bool) {
+	tc, ok := ctx.Value(contextKey{}).(Context)
+	return tc, ok
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -2,7 +2,7 @@
 
 import { useState, useEffect, FormEvent } from "react";
 import { useRouter } from "next/navigation";
-import { Employee, LeaveType } from "@/lib/types";
+import { Employee, Leave, LeaveType } from "@/lib/types";
 import * as leaveService from "@/services/leave-service";
 import * as employeeService from "@/services/employee-service";
 import { getErrorMessage } from "@/lib/api";
@@ -13,12 +13,14 @@ export default function CreateLeavePage() {
   const [isSubmitting, setIsSubmitting] = useState(false);
   const [isLoading, setIsLoading] = useState(true);
   const [myEmployee, setMyEmployee] = useState<Employee | null>(null);
+  const [created, setCreated] = useState<Leave | null>(null);
 
   const [form, setForm] = useState({
     leave_type: "cuti_tahunan" as LeaveType,
     start_date: "",
     end_date: "",
-    total_days: 1,
+    start_half_day: false,
+    end_half_day: false,
     reason: "",
   });
 
@@ -39,9 +41,15 @@ export default function CreateLeavePage() {
 
   const handleChange = (e: React.ChangeEvent<HTMLInputElement | HTMLSelectElement | HTMLTextAreaElement>) => {
     const { name, value } = e.target;
-    setForm({ ...form, [name]: name === "total_days" ? Number(value) : value });
+    setForm({ ...form, [name]: value });
   };
 
+  const handleHalfDayChange = (e: React.ChangeEvent<HTMLInputElement>) => {
+    setForm({ ...form, [e.target.name]: e.target.checked });
+  };
+
+  const singleDay = form.start_date !== "" && form.start_date === form.end_date;
+
   const handleSubmit = async (e: FormEvent) => {
     e.preventDefault();
     if (!myEmployee) return;
@@ -51,8 +59,9 @@ export default function CreateLeavePage() {
       const res = await leaveService.createLeave({
         employee_id: myEmployee.id,
         ...form,
+        end_half_day: singleDay ? false : form.end_half_day,
       });
-      if (res.success) router.push("/dashboard/leaves");
+      if (res.success && res.data) setCreated(res.data);
       else setError(res.message);
     } catch (err) {
       setError(getErrorMessage(err, "Failed to create leave request"));
@@ -63,6 +72,25 @@ export default function CreateLeavePage() {
 
   if (isLoading) return <div className="flex items-center justify-center py-12"><div className="text-gray-500">Loading...</div></div>;
 
+  if (created) {
+    return (
+      <div className="space-y-6">
+        <div>
+          <h2 className="text-2xl font-bold text-gray-900 dark:text-white">Request Leave</h2>
+          <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">Your leave request was submitted</p>
+        </div>
+        <div className="space-y-5 rounded-xl border border-gray-200 dark:border-gray-700 bg-white dark:bg-gray-800 p-6">
+          <div className="rounded-md bg-green-50 dark:bg-green-900/30 p-4 text-sm text-green-700 dark:text-green-400">
+            {created.start_date.slice(0, 10)} to {created.end_date.slice(0, 10)} counts as {created.total_days} working {created.total_days === 1 ? "day" : "days"}, excluding weekends and holidays.
+          </div>
+          <div className="flex justify-end">
+            <button type="button" onClick={() => router.push("/dashboard/leaves")} className="rounded-lg bg-orange-500 px-4 py-2 text-sm font-semibold text-white shadow-sm hover:bg-orange-600">Back to Leaves</button>
+          </div>
+        </div>
+      </div>
+    );
+  }
+
   return (
     <div className="space-y-6">
       <div>
@@ -83,10 +111,7 @@ export default function CreateLeavePage() {
               <option value="dinas_luar">Dinas Luar</option>
             </select>
           </div>
-          <div>
-            <label className="block text-sm font-semibold text-gray-900 dark:text-white">Total Days *</label>
-            <input name="total_days" type="number" min={1} required value={form.total_days} onChange={handleChange} className="mt-1 block w-full rounded-lg border border-gray-300 dark:border-gray-600 px-3 py-2 text-gray-900 dark:text-white dark:bg-gray-700 focus:border-orange-500 focus:outline-none focus:ring-1 focus:ring-orange-500" />
-          </div>
+          <div className="hidden md:block" />
           <div>
             <label className="block text-sm font-semibold text-gray-900 dark:text-white">Start Date *</label>
             <input name="start_date" type="date" required value={form.start_date} onChange={handleChange} className="mt-1 block w-full rounded-lg border border-gray-300 dark:border-gray-600 px-3 py-2 text-gray-900 dark:text-white dark:bg-gray-700 focus:border-orange-500 focus:outline-none focus:ring-1 focus:ring-orange-500" />
@@ -95,7 +120,18 @@ export default function CreateLeavePage() {
             <label className="block text-sm font-semibold text-gray-900 dark:text-white">End Date *</label>
             <input name="end_date" type="date" required value={form.end_date} onChange={handleChange} className="mt-1 block w-full rounded-lg border border-gray-300 dark:border-gray-600 px-3 py-2 text-gray-900 dark:text-white dark:bg-gray-700 focus:border-orange-500 focus:outline-none focus:ring-1 focus:ring-orange-500" />
           </div>
+          <div className="flex items-center gap-2">
+            <input id="start_half_day" name="start_half_day" type="checkbox" checked={form.start_half_day} onChange={handleHalfDayChange} className="h-4 w-4 rounded border-gray-300 accent-orange-500" />
+            <label htmlFor="start_half_day" className="text-sm text-gray-700 dark:text-gray-300">{singleDay ? "Half day only" : "Half day on start date"}</label>
+          </div>
+          {!singleDay && (
+            <div className="flex items-center gap-2">
+              <input id="end_half_day" name="end_half_day" type="checkbox" checked={form.end_half_day} onChange={handleHalfDayChange} className="h-4 w-4 rounded border-gray-300 accent-orange-500" />
+              <label htmlFor="end_half_day" className="text-sm text-gray-700 dark:text-gray-300">Half day on end date</label>
+            </div>
+          )}
         </div>
+        <p className="text-sm text-gray-500 dark:text-gray-400">The number of days is worked out from your work schedule and company holidays when you submit.</p>
         <div>
           <label className="block text-sm font-semibold text-gray-900 dark:text-white">Reason *</label>
           <textarea name="reason" rows={3} required value={form.reason} onChange={handleChange} className="mt-1 block w-full rounded-lg border border-gray-300 dark:border-gray-600 px-3 py-2 text-gray-900 dark:text-white dark:bg-gray-700 focus:border-orange-500 focus:outline-none focus:ring-1 focus:ring-orange-500" />
@@ -83,7 +83,7 @@ export default function Header({ onMenuClick }: HeaderProps) {
     setNotifLoading(true);
     try {
       const res = await getNotifications(15);
-      if (res.success) setNotifications(res.data ?? []);
+      if (res.success) setNotifications(res.data?.items ?? []);
     } catch {
       // silently ignore
     } finally {
@@ -251,7 +251,17 @@ export default function Header({ onMenuClick }: HeaderProps) {
                         </div>
                         <div className="flex-1 min-w-0">
                           <p className="text-xs font-semibold text-gray-900 dark:text-gray-100">
-                            {n.title}
+                            {n.link ? (
+                              <Link
+                                href={n.link}
+                                onClick={() => setNotifDropdownOpen(false)}
+                                className="hover:underline"
+                              >
+                                {n.title}
+                              </Link>
+                            ) : (
+                              n.title
+                            )}
                           </p>
                           <p className="mt-0.5 text-xs text-gray-500 dark:text-gray-400 line-clamp-2">
                             {n.message}
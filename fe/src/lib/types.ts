@@ -393,6 +393,8 @@ export interface Leave {
   leave_type: LeaveType;
   start_date: string;
   end_date: string;
+  start_half_day: boolean;
+  end_half_day: boolean;
   total_days: number;
   reason: string;
   attachment: string;
@@ -410,7 +412,8 @@ export interface CreateLeaveRequest {
   leave_type: LeaveType;
   start_date: string;
   end_date: string;
-  total_days: number;
+  start_half_day: boolean;
+  end_half_day: boolean;
   reason: string;
   attachment?: string;
 }
@@ -530,9 +533,16 @@ export interface Notification {
   ref_type?: string;
   is_read: boolean;
   read_at?: string;
+  archived_at?: string;
+  link?: string;
   created_at: string;
 }
 
+export interface NotificationPage {
+  items: Notification[];
+  next_cursor?: string;
+}
+
 export interface UnreadCountResponse {
   count: number;
 }
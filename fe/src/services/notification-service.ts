@@ -1,9 +1,11 @@
 import api from "@/lib/api";
-import { Notification, UnreadCountResponse } from "@/lib/types";
+import { NotificationPage, UnreadCountResponse } from "@/lib/types";
 
-export async function getNotifications(limit = 20) {
-  const response = await api.get<{ success: boolean; data: Notification[] }>(
-    `/notifications?limit=${limit}`
+export async function getNotifications(limit = 20, cursor?: string) {
+  const params = new URLSearchParams({ limit: String(limit) });
+  if (cursor) params.set("cursor", cursor);
+  const response = await api.get<{ success: boolean; data: NotificationPage }>(
+    `/notifications?${params}`
   );
   return response.data;
 }
@@ -29,3 +31,19 @@ export async function markAllAsRead() {
   );
   return response.data;
 }
+
+export async function archiveNotifications(ids: string[]) {
+  const response = await api.post<{ success: boolean }>(
+    "/notifications/archive",
+    { ids }
+  );
+  return response.data;
+}
+
+export async function deleteNotifications(ids: string[]) {
+  const response = await api.post<{ success: boolean }>(
+    "/notifications/delete",
+    { ids }
+  );
+  return response.data;
+}